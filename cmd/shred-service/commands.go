@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/catalog"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// runCommand executes a command line sub command instead of serving the API.
+func runCommand(daos *dao.Daos, args []string, out io.Writer) error {
+	switch args[0] {
+	case "import":
+		return runImport(daos, args[1:], out)
+	case "export":
+		return runExport(daos, args[1:], out)
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func newImporter(daos *dao.Daos) *catalog.Importer {
+	return catalog.NewImporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+}
+
+func newExporter(daos *dao.Daos) *catalog.Exporter {
+	return catalog.NewExporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+}
+
+// runImport loads a catalog file of exercises and prints a per-row report.
+func runImport(daos *dao.Daos, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(out)
+	formatName := flags.String("format", "", "input format, csv, ndjson or bundle (default: from the file extension)")
+	mode := flags.String("mode", string(model.ImportModeInsert), "insert, or upsert to update exercises with the same name")
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+	createdBy := flags.String("created-by", "", "uuid of the user recorded as the creator")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: shred-service import [flags] FILE")
+	}
+	path := flags.Arg(0)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format, err := catalog.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	opts := catalog.ImportOptions{Mode: model.ImportMode(*mode), DryRun: *dryRun}
+	if *createdBy != "" {
+		if opts.CreatedBy, err = uuid.Parse(*createdBy); err != nil {
+			return fmt.Errorf("invalid created-by: %w", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := importFile(daos, format, file, opts)
+	if err != nil {
+		return err
+	}
+
+	printImportReport(out, report)
+	if report.Invalid > 0 {
+		return fmt.Errorf("%d of %d rows are invalid, nothing was imported", report.Invalid, report.Total)
+	}
+	return nil
+}
+
+func importFile(daos *dao.Daos, format catalog.Format, file *os.File, opts catalog.ImportOptions) (*model.ImportReport, error) {
+	importer := newImporter(daos)
+	if format == catalog.FormatBundle {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		bundle, err := catalog.ReadBundle(file, info.Size())
+		if err != nil {
+			return nil, err
+		}
+		return importer.ImportBundle(context.Background(), bundle, opts)
+	}
+
+	rows, err := catalog.Parse(format, file)
+	if err != nil {
+		return nil, err
+	}
+	return importer.Import(context.Background(), rows, opts)
+}
+
+// runExport writes the catalog to a file, or to out when no file is given.
+func runExport(daos *dao.Daos, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(out)
+	formatName := flags.String("format", "", "output format, csv, ndjson or bundle (default: from the file extension, else ndjson)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New("usage: shred-service export [flags] [FILE]")
+	}
+	path := flags.Arg(0)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(path), ".")
+		if *formatName == "" {
+			*formatName = string(catalog.FormatNDJSON)
+		}
+	}
+	format, err := catalog.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return newExporter(daos).Export(context.Background(), format, out)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := newExporter(daos).Export(context.Background(), format, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func printImportReport(out io.Writer, report *model.ImportReport) {
+	for _, row := range report.Rows {
+		fmt.Fprintf(out, "row %d: %s %q\n", row.Row, row.Action, row.ExerciseName)
+		for _, problem := range row.Errors {
+			fmt.Fprintf(out, "\t%s\n", problem)
+		}
+	}
+
+	status := "dry run, nothing written"
+	if report.Committed {
+		status = "committed"
+	} else if !report.DryRun {
+		status = "not committed"
+	}
+	fmt.Fprintf(out, "%d rows: %d to create, %d to update, %d invalid (%s)\n",
+		report.Total, report.Created, report.Updated, report.Invalid, status)
+}
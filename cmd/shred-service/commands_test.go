@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommandDb returns the DAOs of a memory store holding the STRENGTH
+// category and the QUAD muscle.
+func newCommandDb(t *testing.T) *dao.Daos {
+	daos := memory.NewDaos(memory.NewStore())
+	_, err := daos.Categories.CreateCategory(context.Background(), &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	_, err = daos.Muscles.CreateMuscle(context.Background(), &model.MuscleRequest{
+		MuscleFields: model.MuscleFields{MuscleCode: "QUAD", MuscleName: "Quadriceps"}})
+	require.NoError(t, err)
+	return daos
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunCommand_Unknown(t *testing.T) {
+	db := newCommandDb(t)
+
+	err := runCommand(db, []string{"serve"}, &bytes.Buffer{})
+	assert.EqualError(t, err, `unknown command "serve"`)
+}
+
+func TestRunImport_Usage(t *testing.T) {
+	db := newCommandDb(t)
+
+	err := runCommand(db, []string{"import"}, &bytes.Buffer{})
+	assert.EqualError(t, err, "usage: shred-service import [flags] FILE")
+
+	err = runCommand(db, []string{"import", "exercises.xlsx"}, &bytes.Buffer{})
+	assert.EqualError(t, err, `unsupported format "xlsx"`)
+
+	err = runCommand(db, []string{"import", "-created-by", "bad", "exercises.csv"}, &bytes.Buffer{})
+	assert.EqualError(t, err, "invalid created-by: invalid UUID length: 3")
+}
+
+func TestRunImport_DryRun(t *testing.T) {
+	db := newCommandDb(t)
+	path := writeTempFile(t, "exercises.csv", "exerciseName,category,muscles\nSquat,STRENGTH,QUAD:primary\n")
+
+	var out bytes.Buffer
+	err := runCommand(db, []string{"import", "-dry-run", path}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "row 2: create \"Squat\"\n1 rows: 1 to create, 0 to update, 0 invalid (dry run, nothing written)\n", out.String())
+	exercises, err := db.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, exercises)
+}
+
+func TestRunImport_Invalid(t *testing.T) {
+	db := newCommandDb(t)
+	path := writeTempFile(t, "exercises.jsonl", `{"exerciseName":"Squat","category":"CARDIO"}`+"\n")
+
+	var out bytes.Buffer
+	err := runCommand(db, []string{"import", "-dry-run", path}, &out)
+	assert.EqualError(t, err, "1 of 1 rows are invalid, nothing was imported")
+	assert.Contains(t, out.String(), "row 1: invalid \"Squat\"\n\tcategory CARDIO not found\n")
+}
+
+func TestRunExport(t *testing.T) {
+	db := newCommandDb(t)
+	path := filepath.Join(t.TempDir(), "catalog.csv")
+	_, err := db.Exercises.Create(context.Background(), &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+
+	err = runCommand(db, []string{"export", path}, &bytes.Buffer{})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "exerciseName,description,instructions,cues,videoUrl,category,licenceShortName,licenceAuthor,muscles,apparatus\n"+
+		"Squat,,,,,STRENGTH,,,,\n", string(data))
+}
+
+func TestRunExport_Usage(t *testing.T) {
+	db := newCommandDb(t)
+
+	err := runCommand(db, []string{"export", "a.csv", "b.csv"}, &bytes.Buffer{})
+	assert.EqualError(t, err, "usage: shred-service export [flags] [FILE]")
+
+	err = runCommand(db, []string{"export", "-format", "xml"}, &bytes.Buffer{})
+	assert.EqualError(t, err, `unsupported format "xml"`)
+}
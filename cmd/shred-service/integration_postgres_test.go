@@ -0,0 +1,16 @@
+//go:build integration
+
+package main
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/dao/pgtest"
+)
+
+// openIntegrationDB opens the Postgres test database with all tables
+// emptied, see pgtest.Open.
+func openIntegrationDB(t *testing.T) *sqlx.DB {
+	return pgtest.Open(t)
+}
@@ -0,0 +1,21 @@
+//go:build !integration
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/stretchr/testify/require"
+)
+
+// openIntegrationDB opens a new SQLite database, removed when the test
+// ends.
+func openIntegrationDB(t *testing.T) *sqlx.DB {
+	db, err := dao.OpenSQLite(filepath.Join(t.TempDir(), "shred.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/pgtest"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	integrationCatalogCSV = "exerciseName,category,muscles\nFront squat,STRENGTH,QUADS:primary\n"
+	integrationStrongCSV  = "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n2025-03-01 08:30:00,Legs,Squat,1,100,5\n"
+	integrationRunGPX     = `<gpx><trk><type>running</type><trkseg>
+<trkpt lat="52.0" lon="21.0"><time>2025-03-01T07:00:00Z</time></trkpt>
+<trkpt lat="52.009" lon="21.0"><time>2025-03-01T07:05:00Z</time></trkpt>
+</trkseg></trk></gpx>`
+)
+
+// routeClient sends requests to the router under an API version prefix and
+// records the routes they hit, so the test can tell which routes were left
+// out.
+type routeClient struct {
+	t       *testing.T
+	router  *Router
+	prefix  string
+	covered map[string]bool
+}
+
+/*
+ * do sends a request to path, which must match route, and asserts the
+ * response status. A string body is sent as JSON unless contentType says
+ * otherwise.
+ */
+func (c *routeClient) do(method, route, path, body, contentType string, want int) []byte {
+	c.t.Helper()
+	route, path = c.prefix+route, c.prefix+path
+	assert.True(c.t, routeExists(c.router.Engine, method, route), "%s %s is not registered", method, route)
+	c.covered[method+" "+route] = true
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+		if contentType == "" {
+			contentType = "application/json"
+		}
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	c.router.Engine.ServeHTTP(w, req)
+	assert.Equal(c.t, want, w.Code, "%s %s: %s", method, path, w.Body.String())
+	return w.Body.Bytes()
+}
+
+// uuidField decodes a response and returns one of its UUID fields.
+func uuidField(t *testing.T, body []byte, field string) uuid.UUID {
+	t.Helper()
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(body, &fields))
+	value, err := uuid.Parse(fmt.Sprint(fields[field]))
+	require.NoError(t, err, "response has no %s: %s", field, body)
+	return value
+}
+
+// seedReferences adds the reference types the routes cannot create.
+func seedReferences(t *testing.T, daos *dao.Daos, userUuid uuid.UUID) {
+	ctx := context.Background()
+	for _, code := range []string{"STRENGTH", "CARDIO"} {
+		_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+			CategoryFields: model.CategoryFields{CategoryCode: code, CategoryName: code}, CreatedBy: userUuid})
+		require.NoError(t, err)
+	}
+	legs := "LEGS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleGroup: "Legs", ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus, CreatedBy: userUuid})
+		require.NoError(t, err)
+	}
+	require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+		ApparatusFields: model.ApparatusFields{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}, CreatedBy: userUuid}))
+}
+
+// TestIntegration_Routes walks every version of the API against a fresh
+// database each.
+func TestIntegration_Routes(t *testing.T) {
+	covered := map[string]bool{}
+	var router *Router
+	for _, prefix := range []string{"/v1", "/v2"} {
+		t.Run(prefix, func(t *testing.T) {
+			router = walkRoutes(t, prefix, covered)
+		})
+	}
+
+	if router == nil {
+		t.Skip("no version of the API was walked")
+	}
+	for _, route := range router.Engine.Routes() {
+		assert.True(t, covered[route.Method+" "+route.Path], "%s %s is not covered", route.Method, route.Path)
+	}
+}
+
+func walkRoutes(t *testing.T, prefix string, covered map[string]bool) *Router {
+	db := openIntegrationDB(t)
+	daos := dao.NewDaos(db)
+	userUuid := pgtest.CreateUser(t, db, "Athlete")
+	clientUuid := pgtest.CreateUser(t, db, "Client")
+	seedReferences(t, daos, userUuid)
+
+	router := setupRouter(daos, logging.Discard(), testAPIConfig)
+	root := &routeClient{t: t, router: router, covered: covered}
+	c := &routeClient{t: t, router: router, prefix: prefix, covered: covered}
+	user := "/users/" + userUuid.String()
+
+	root.do("GET", "/metrics", "/metrics", "", "", http.StatusOK)
+	root.do("GET", "/openapi.json", "/openapi.json", "", "", http.StatusOK)
+	root.do("GET", "/docs", "/docs", "", "", http.StatusOK)
+
+	// Reference types and the exercise catalog.
+	c.do("GET", "/categories", "/categories", "", "", http.StatusOK)
+	c.do("GET", "/apparatus", "/apparatus", "", "", http.StatusOK)
+	c.do("GET", "/muscles", "/muscles", "", "", http.StatusOK)
+	c.do("GET", "/muscles/tree", "/muscles/tree", "", "", http.StatusOK)
+
+	squat := uuidField(t, c.do("POST", "/exercises", "/exercises", fmt.Sprintf(
+		`{"exerciseName":"Squat","category":"STRENGTH","createdBy":%q,"muscles":[{"muscleCode":"QUADS","muscleRole":"primary"}]}`,
+		userUuid), "", http.StatusCreated), "exerciseUuid")
+	lunge := uuidField(t, c.do("POST", "/exercises", "/exercises", fmt.Sprintf(
+		`{"exerciseName":"Lunge","category":"STRENGTH","createdBy":%q,"muscles":[{"muscleCode":"QUADS","muscleRole":"primary"}],"apparatus":["BARBELL"]}`,
+		userUuid), "", http.StatusCreated), "exerciseUuid")
+	run := uuidField(t, c.do("POST", "/exercises", "/exercises", fmt.Sprintf(
+		`{"exerciseName":"Running","category":"CARDIO","createdBy":%q}`, userUuid), "", http.StatusCreated), "exerciseUuid")
+	exercise := "/exercises/" + squat.String()
+
+	c.do("PUT", "/exercises/:uuid", exercise, fmt.Sprintf(
+		`{"exerciseUuid":%q,"exerciseName":"Squat","category":"STRENGTH","cues":"Chest up","createdBy":%q}`, squat, userUuid),
+		"", http.StatusOK)
+	c.do("GET", "/exercises/:uuid", exercise, "", "", http.StatusOK)
+	c.do("GET", "/muscles/:code/exercises", "/muscles/LEGS/exercises", "", "", http.StatusOK)
+	c.do("POST", "/exercises/import", "/exercises/import?format=csv&createdBy="+userUuid.String(),
+		integrationCatalogCSV, "text/csv", http.StatusCreated)
+	c.do("GET", "/exercises/export", "/exercises/export?format=csv", "", "", http.StatusOK)
+
+	c.do("POST", "/exercises/:uuid/relations", "/exercises/"+lunge.String()+"/relations", fmt.Sprintf(
+		`{"relatedUuid":%q,"relation":"progression_of","createdBy":%q}`, squat, userUuid), "", http.StatusCreated)
+	c.do("GET", "/exercises/:uuid/relations", exercise+"/relations", "", "", http.StatusOK)
+	c.do("GET", "/exercises/:uuid/easier", "/exercises/"+lunge.String()+"/easier", "", "", http.StatusOK)
+	c.do("GET", "/exercises/:uuid/harder", exercise+"/harder", "", "", http.StatusOK)
+	c.do("DELETE", "/exercises/:uuid/relations/:relatedUuid",
+		"/exercises/"+lunge.String()+"/relations/"+squat.String()+"?relation=progression_of", "", "", http.StatusNoContent)
+
+	c.do("PUT", "/exercises/:uuid/translations/:locale", exercise+"/translations/de",
+		`{"exerciseName":"Kniebeuge"}`, "", http.StatusOK)
+	c.do("GET", "/exercises/:uuid/translations", exercise+"/translations", "", "", http.StatusOK)
+	c.do("POST", "/exercises/:uuid/aliases", exercise+"/aliases", `{"alias":"Back squat"}`, "", http.StatusCreated)
+	c.do("GET", "/exercises/:uuid/aliases", exercise+"/aliases", "", "", http.StatusOK)
+	c.do("PUT", "/translations/:kind/:code/:locale", "/translations/muscle/QUADS/de", `{"name":"Quadrizeps"}`, "", http.StatusOK)
+	c.do("GET", "/translations/locales", "/translations/locales", "", "", http.StatusOK)
+	c.do("GET", "/exercises/search", "/exercises/search?q=squat", "", "", http.StatusOK)
+	c.do("DELETE", "/exercises/:uuid/aliases", exercise+"/aliases?alias=Back%20squat", "", "", http.StatusNoContent)
+	c.do("DELETE", "/exercises/:uuid/translations/:locale", exercise+"/translations/de", "", "", http.StatusNoContent)
+
+	// A user's preferences, training history and equipment.
+	c.do("PUT", "/users/:uuid/preferences", user+"/preferences", `{"unitSystem":"metric"}`, "", http.StatusOK)
+	c.do("GET", "/users/:uuid/preferences", user+"/preferences", "", "", http.StatusOK)
+	session := uuidField(t, c.do("POST", "/users/:uuid/workouts", user+"/workouts", fmt.Sprintf(
+		`{"sessionName":"Legs","startedAt":"2025-03-03T08:00:00Z","sets":[{"exerciseUuid":%q,"reps":5,"weightKg":100}]}`, squat),
+		"", http.StatusCreated), "sessionUuid")
+	c.do("GET", "/users/:uuid/workouts", user+"/workouts?from=2025-03-01T00:00:00Z&to=2025-03-31T00:00:00Z", "", "", http.StatusOK)
+
+	c.do("PUT", "/history/mappings/:source", "/history/mappings/strong", fmt.Sprintf(
+		`{"externalName":"Squat","exerciseUuid":%q,"createdBy":%q}`, squat, userUuid), "", http.StatusOK)
+	c.do("GET", "/history/mappings/:source", "/history/mappings/strong", "", "", http.StatusOK)
+	c.do("POST", "/users/:uuid/history/import", user+"/history/import?source=strong", integrationStrongCSV, "text/csv",
+		http.StatusCreated)
+	c.do("DELETE", "/history/mappings/:source", "/history/mappings/strong?externalName=Squat", "", "", http.StatusNoContent)
+
+	activity := uuidField(t, c.do("POST", "/users/:uuid/cardio/:format",
+		fmt.Sprintf("%s/cardio/gpx?exerciseUuid=%s&sessionName=Tempo", user, run), integrationRunGPX, "application/gpx+xml",
+		http.StatusCreated), "sessionUuid")
+	c.do("GET", "/users/:uuid/cardio", user+"/cardio?from=2025-03-01T00:00:00Z&to=2025-03-02T00:00:00Z", "", "", http.StatusOK)
+	c.do("GET", "/cardio/:uuid", "/cardio/"+activity.String(), "", "", http.StatusOK)
+
+	home := uuidField(t, c.do("POST", "/users/:uuid/equipment-profiles", user+"/equipment-profiles", fmt.Sprintf(
+		`{"profileName":"Home","isDefault":true,"apparatus":["BARBELL"],"createdBy":%q}`, userUuid), "", http.StatusCreated),
+		"profileUuid")
+	c.do("GET", "/users/:uuid/equipment-profiles", user+"/equipment-profiles", "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/available-exercises", user+"/available-exercises", "", "", http.StatusOK)
+	gym := uuidField(t, c.do("POST", "/equipment-profiles", "/equipment-profiles", fmt.Sprintf(
+		`{"profileName":"Downtown","apparatus":["BARBELL"],"createdBy":%q}`, userUuid), "", http.StatusCreated), "profileUuid")
+	profile := "/equipment-profiles/" + gym.String()
+	c.do("GET", "/equipment-profiles", "/equipment-profiles", "", "", http.StatusOK)
+	c.do("PUT", "/equipment-profiles/:uuid", profile, fmt.Sprintf(
+		`{"profileName":"Uptown","apparatus":[],"createdBy":%q}`, userUuid), "", http.StatusOK)
+	c.do("GET", "/equipment-profiles/:uuid", profile, "", "", http.StatusOK)
+	c.do("GET", "/equipment-profiles/:uuid/exercises", "/equipment-profiles/"+home.String()+"/exercises", "", "", http.StatusOK)
+	c.do("DELETE", "/equipment-profiles/:uuid", profile, "", "", http.StatusNoContent)
+
+	c.do("POST", "/users/:uuid/session-plans", user+"/session-plans",
+		`{"targetMuscles":["LEGS"],"category":"STRENGTH","timeBudgetMinutes":30,"experience":"beginner","seed":1}`,
+		"", http.StatusOK)
+	c.do("GET", "/users/:uuid/recovery", user+"/recovery", "", "", http.StatusOK)
+
+	measurement := uuidField(t, c.do("POST", "/users/:uuid/measurements", user+"/measurements",
+		`{"kind":"bodyweight","value":80.5,"unit":"kg","measuredAt":"2025-03-01T07:00:00Z"}`, "", http.StatusCreated),
+		"measurementUuid")
+	c.do("PUT", "/measurements/:uuid", "/measurements/"+measurement.String(),
+		`{"kind":"bodyweight","value":80,"unit":"kg","measuredAt":"2025-03-01T07:00:00Z"}`, "", http.StatusOK)
+	c.do("GET", "/measurements/:uuid", "/measurements/"+measurement.String(), "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/measurements", user+"/measurements?kind=bodyweight", "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/analytics/timeseries",
+		user+"/analytics/timeseries?from=2025-02-01T00:00:00Z&to=2025-03-31T00:00:00Z", "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/analytics/muscle-volume",
+		user+"/analytics/muscle-volume?from=2025-02-01T00:00:00Z&to=2025-03-31T00:00:00Z&level=region", "", "", http.StatusOK)
+	c.do("DELETE", "/measurements/:uuid", "/measurements/"+measurement.String(), "", "", http.StatusNoContent)
+
+	c.do("POST", "/calculators/plates", "/calculators/plates", `{"target":100,"unit":"kg"}`, "", http.StatusOK)
+	c.do("POST", "/calculators/warmup", "/calculators/warmup", `{"workingLoad":100,"reps":5,"unit":"kg"}`, "", http.StatusOK)
+
+	// Scheduling, calendar feeds and coaching.
+	scheduled := uuidField(t, c.do("POST", "/users/:uuid/scheduled-workouts", user+"/scheduled-workouts",
+		`{"sessionName":"Legs","scheduledAt":"2025-03-03T08:00:00Z"}`, "", http.StatusCreated), "scheduleUuid")
+	schedule := "/scheduled-workouts/" + scheduled.String()
+	c.do("PUT", "/scheduled-workouts/:uuid", schedule, fmt.Sprintf(
+		`{"sessionName":"Legs","scheduledAt":"2025-03-03T08:00:00Z","sessionUuid":%q}`, session), "", http.StatusOK)
+	c.do("GET", "/scheduled-workouts/:uuid", schedule, "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/scheduled-workouts",
+		user+"/scheduled-workouts?from=2025-03-01T00:00:00Z&to=2025-03-31T00:00:00Z", "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/calendar", user+"/calendar?view=month&date=2025-03-01", "", "", http.StatusOK)
+	c.do("GET", "/users/:uuid/adherence", user+"/adherence?weeks=4", "", "", http.StatusOK)
+
+	var feed model.CalendarFeed
+	require.NoError(t, json.Unmarshal(c.do("POST", "/users/:uuid/calendar-feed", user+"/calendar-feed", "", "",
+		http.StatusCreated), &feed))
+	c.do("GET", "/users/:uuid/calendar-feed", user+"/calendar-feed", "", "", http.StatusOK)
+	root.do("GET", "/calendar/:feed", "/calendar/"+feed.Token+".ics", "", "", http.StatusOK)
+	c.do("DELETE", "/users/:uuid/calendar-feed", user+"/calendar-feed", "", "", http.StatusNoContent)
+	root.do("GET", "/calendar/:feed", "/calendar/"+feed.Token+".ics", "", "", http.StatusNotFound)
+	c.do("DELETE", "/scheduled-workouts/:uuid", schedule, "", "", http.StatusNoContent)
+
+	coach := "/coaches/" + userUuid.String()
+	c.do("PUT", "/coaches/:uuid/clients/:clientUuid", coach+"/clients/"+clientUuid.String(), "", "", http.StatusNoContent)
+	c.do("GET", "/coaches/:uuid/clients", coach+"/clients", "", "", http.StatusOK)
+	c.do("GET", "/coaches/:uuid/adherence", coach+"/adherence", "", "", http.StatusOK)
+	c.do("DELETE", "/coaches/:uuid/clients/:clientUuid", coach+"/clients/"+clientUuid.String(), "", "", http.StatusNoContent)
+
+	// The squat is still referred to by its set; the lunge goes with its
+	// muscles and apparatus.
+	c.do("DELETE", "/exercises/:uuid", exercise, "", "", http.StatusConflict)
+	c.do("DELETE", "/exercises/:uuid", "/exercises/"+lunge.String(), "", "", http.StatusNoContent)
+	c.do("GET", "/exercises/:uuid", "/exercises/"+lunge.String(), "", "", http.StatusNotFound)
+	return router
+}
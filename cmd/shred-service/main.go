@@ -1,44 +1,137 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/adherence"
+	"github.com/pwydra/shred/internal/analytics"
+	"github.com/pwydra/shred/internal/calendar"
+	"github.com/pwydra/shred/internal/cardio"
 	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
 	"github.com/pwydra/shred/internal/handlers"
+	"github.com/pwydra/shred/internal/history"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/middleware"
+	"github.com/pwydra/shred/internal/openapi"
+	"github.com/pwydra/shred/internal/planner"
+	"github.com/pwydra/shred/internal/recovery"
+	"github.com/pwydra/shred/internal/service"
+	"github.com/pwydra/shred/internal/tracing"
 )
 
 type Router struct {
 	Engine *gin.Engine
 }
 
-func NewRouter() *Router {
+// NewRouter creates a router that tags requests with an ID, traces them,
+// logs them to logger and records them in the metrics.
+func NewRouter(logger *slog.Logger) *Router {
 	r := Router{
-		Engine: gin.Default(),
+		Engine: gin.New(),
 	}
+	r.Engine.Use(middleware.RequestID(), middleware.Tracing(), middleware.Logger(logger),
+		middleware.Metrics(), middleware.Recovery(logger))
 
 	return &r
 }
 
 func main() {
-	log.Println("Starting Shred API")
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger := logging.New(os.Stdout, logCfg)
+	slog.SetDefault(logger)
+
+	traceCfg, err := tracing.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	shutdown, err := tracing.Setup(context.Background(), traceCfg, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := shutdown(context.Background()); err != nil {
+			logger.Error("flushing spans failed", "error", err)
+		}
+	}()
 
-	db := sqlx.MustConnect("postgres", getConnectionString())
-	defer db.Close()
+	daoCfg, err := dao.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dao.Configure(daoCfg)
 
-	r := setupRouter(db)
+	apiCfg, err := apiConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	daos, closeDaos, err := openDaos(daoCfg)
+	if err != nil {
+		logger.Error("opening store failed", "driver", daoCfg.Driver, "error", err)
+		os.Exit(1)
+	}
+	defer closeDaos()
+
+	if len(os.Args) > 1 {
+		if err := runCommand(daos, os.Args[1:], os.Stdout); err != nil {
+			logger.Error("command failed", "command", os.Args[1], "error", err)
+			closeDaos()
+			os.Exit(1)
+		}
+		return
+	}
+
+	logger.Info("starting Shred API", "addr", ":8088", "driver", daoCfg.Driver)
+	r := setupRouter(daos, logger, apiCfg)
 
 	if err := r.Engine.Run(":8088"); err != nil {
 		panic(err)
 	}
 }
 
+/*
+ * openDaos opens the store cfg.Driver selects and creates the DAOs on it.
+ * The returned function closes the store. Only Postgres needs the POSTGRES_*
+ * variables, so the service runs without any infrastructure on the memory
+ * and sqlite drivers.
+ */
+func openDaos(cfg dao.Config) (*dao.Daos, func() error, error) {
+	switch cfg.Driver {
+	case dao.DriverMemory:
+		return memory.NewDaos(memory.NewStore()), func() error { return nil }, nil
+	case dao.DriverSQLite:
+		db, err := dao.OpenSQLite(cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dao.NewDaos(db), db.Close, nil
+	}
+
+	dsn := getConnectionString()
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to %s: %w", logging.Redact(dsn), err)
+	}
+	return dao.NewDaos(db), db.Close, nil
+}
+
 func getConnectionString() string {
 	dbHost := os.Getenv("POSTGRES_HOST")
 	dbPort := os.Getenv("POSTGRES_PORT")
@@ -57,18 +150,229 @@ func getConnectionString() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", dbHost, port, dbUser, dbPassword, dbName)
 }
 
-func setupRouter(db *sqlx.DB) *Router {
-	exerciseDao := dao.NewExerciseDao(db)
-	handler := handlers.NewHandler(exerciseDao)
+// v1 has been deprecated since v2 spelt the license fields of exercises
+// right, and goes away at its sunset. apiConfigFromEnv may move both dates.
+const (
+	defaultV1DeprecatedAt = "2026-10-19"
+	defaultV1Sunset       = "2027-04-19"
+)
+
+// apiConfig holds when the old API versions were deprecated and go away.
+type apiConfig struct {
+	V1DeprecatedAt time.Time
+	V1Sunset       time.Time
+}
+
+/*
+ * apiConfigFromEnv reads API_V1_DEPRECATED_AT and API_V1_SUNSET, dates like
+ * 2027-04-19 or RFC 3339 times, which default to defaultV1DeprecatedAt and
+ * defaultV1Sunset. Clients plan against the dates in the Deprecation and
+ * Sunset headers, so the sunset must not come before the deprecation.
+ */
+func apiConfigFromEnv() (apiConfig, error) {
+	var cfg apiConfig
+	for _, v := range []struct {
+		name, fallback string
+		dest           *time.Time
+	}{
+		{"API_V1_DEPRECATED_AT", defaultV1DeprecatedAt, &cfg.V1DeprecatedAt},
+		{"API_V1_SUNSET", defaultV1Sunset, &cfg.V1Sunset},
+	} {
+		value := os.Getenv(v.name)
+		if value == "" {
+			value = v.fallback
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			return apiConfig{}, fmt.Errorf("%s must be a date like 2027-04-19, not %q", v.name, value)
+		}
+		*v.dest = t
+	}
+	if cfg.V1Sunset.Before(cfg.V1DeprecatedAt) {
+		return apiConfig{}, fmt.Errorf("API_V1_SUNSET %s is before API_V1_DEPRECATED_AT %s",
+			cfg.V1Sunset.Format(time.DateOnly), cfg.V1DeprecatedAt.Format(time.DateOnly))
+	}
+	return cfg, nil
+}
+
+// parseDate reads a date, taken as midnight UTC, or an RFC 3339 time.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t.UTC(), err
+}
+
+// exerciseRoutes are the exercise handlers, whose bodies differ between
+// API versions.
+type exerciseRoutes struct {
+	create, get, update gin.HandlerFunc
+}
 
-	r := NewRouter()
+func setupRouter(daos *dao.Daos, logger *slog.Logger, apiCfg apiConfig) *Router {
+	handler := handlers.NewHandler(service.NewExerciseService(daos.Exercises, daos.Translations, daos.UnitOfWork))
+	translationHandler := handlers.NewTranslationHandler(daos.Translations)
+	referenceHandler := handlers.NewReferenceHandler(service.NewReferenceService(daos.Muscles, daos.Categories,
+		daos.Apparatus, daos.Translations))
+	catalogHandler := handlers.NewCatalogHandler(newImporter(daos), newExporter(daos))
+	relationHandler := handlers.NewRelationHandler(daos.Relations)
+	userHandler := handlers.NewUserHandler(daos.Users)
+	workoutHandler := handlers.NewWorkoutHandler(service.NewWorkoutService(daos.Workouts, daos.Users))
+	historyHandler := handlers.NewHistoryHandler(
+		history.NewImporter(daos.Workouts, daos.Catalog, daos.Mappings), daos.Mappings)
+	cardioHandler := handlers.NewCardioHandler(cardio.NewImporter(daos.Cardio), daos.Cardio, daos.Users)
+	equipmentHandler := handlers.NewEquipmentHandler(daos.Equipment)
+	tracker := recovery.NewTracker(daos.Recovery, daos.Muscles)
+	plannerHandler := handlers.NewPlannerHandler(planner.NewPlanner(daos.Planner, daos.Equipment, tracker))
+	recoveryHandler := handlers.NewRecoveryHandler(tracker)
+	measurementHandler := handlers.NewMeasurementHandler(daos.Measurements, daos.Users)
+	plateHandler := handlers.NewPlateHandler()
+	analyticsHandler := handlers.NewAnalyticsHandler(analytics.NewAnalyzer(daos.Analytics, daos.Measurements), daos.Users)
+	calendarHandler := handlers.NewCalendarHandler(daos.Calendar, calendar.NewScheduler(daos.Calendar))
+	coachHandler := handlers.NewCoachHandler(daos.Coach)
+	adherenceHandler := handlers.NewAdherenceHandler(adherence.NewReporter(daos.Calendar, daos.Coach))
 
-	//	r.Engine.GET("/exercises/query", handler.queryExercise)
-	//	r.Engine.GET("/exercises", handler.GetExercises)
-	r.Engine.GET("/exercises/:uuid", handler.GetExercise)
-	r.Engine.POST("/exercises", handler.CreateExercise)
-	r.Engine.PUT("/exercises/:uuid", handler.UpdateExercise)
-	r.Engine.DELETE("/exercises/:uuid", handler.DeleteExercise)
+	r := NewRouter(logger)
+
+	r.Engine.GET("/metrics", gin.WrapH(metrics.Handler(metrics.NewRegistry(daos.DB))))
+	r.Engine.GET("/openapi.json", openapi.Handler)
+	r.Engine.GET("/docs", openapi.DocsHandler("/openapi.json"))
+	// Calendar apps subscribe to the feed URL, so it stays out of the
+	// versions.
+	r.Engine.GET("/calendar/:feed", calendarHandler.ServeFeed)
+
+	routes := func(g *gin.RouterGroup, exercises exerciseRoutes) {
+		//	g.GET("/exercises/query", handler.queryExercise)
+		//	g.GET("/exercises", handler.GetExercises)
+		g.GET("/exercises/export", catalogHandler.ExportExercises)
+		g.GET("/exercises/search", translationHandler.SearchExercises)
+		g.GET("/exercises/:uuid", exercises.get)
+		g.POST("/exercises", exercises.create)
+		g.POST("/exercises/import", catalogHandler.ImportExercises)
+		g.PUT("/exercises/:uuid", exercises.update)
+		g.DELETE("/exercises/:uuid", handler.DeleteExercise)
+		g.GET("/exercises/:uuid/relations", relationHandler.GetRelations)
+		g.POST("/exercises/:uuid/relations", relationHandler.CreateRelation)
+		g.DELETE("/exercises/:uuid/relations/:relatedUuid", relationHandler.DeleteRelation)
+		g.GET("/exercises/:uuid/easier", relationHandler.GetEasier)
+		g.GET("/exercises/:uuid/harder", relationHandler.GetHarder)
+		g.GET("/exercises/:uuid/translations", translationHandler.GetTranslations)
+		g.PUT("/exercises/:uuid/translations/:locale", translationHandler.SaveTranslation)
+		g.DELETE("/exercises/:uuid/translations/:locale", translationHandler.DeleteTranslation)
+		g.GET("/exercises/:uuid/aliases", translationHandler.GetAliases)
+		g.POST("/exercises/:uuid/aliases", translationHandler.AddAlias)
+		g.DELETE("/exercises/:uuid/aliases", translationHandler.DeleteAlias)
+
+		g.GET("/muscles", referenceHandler.GetMuscles)
+		g.GET("/muscles/tree", referenceHandler.GetMuscleTree)
+		g.GET("/muscles/:code/exercises", referenceHandler.GetMuscleExercises)
+		g.GET("/categories", referenceHandler.GetCategories)
+		g.GET("/apparatus", referenceHandler.GetApparatus)
+		g.GET("/translations/locales", translationHandler.GetLocales)
+		g.PUT("/translations/:kind/:code/:locale", translationHandler.SaveReferenceTranslation)
+
+		g.GET("/users/:uuid/preferences", userHandler.GetPreferences)
+		g.PUT("/users/:uuid/preferences", userHandler.UpdatePreferences)
+		g.GET("/users/:uuid/workouts", workoutHandler.GetWorkouts)
+		g.POST("/users/:uuid/workouts", workoutHandler.CreateWorkout)
+		g.POST("/users/:uuid/history/import", historyHandler.ImportHistory)
+		g.GET("/history/mappings/:source", historyHandler.GetMappings)
+		g.PUT("/history/mappings/:source", historyHandler.SaveMapping)
+		g.DELETE("/history/mappings/:source", historyHandler.DeleteMapping)
+		g.GET("/users/:uuid/cardio", cardioHandler.GetActivities)
+		g.POST("/users/:uuid/cardio/:format", cardioHandler.ImportActivity)
+		g.GET("/cardio/:uuid", cardioHandler.GetActivity)
+		g.GET("/users/:uuid/equipment-profiles", equipmentHandler.GetUserProfiles)
+		g.POST("/users/:uuid/equipment-profiles", equipmentHandler.CreateUserProfile)
+		g.GET("/users/:uuid/available-exercises", equipmentHandler.GetAvailableExercises)
+		g.GET("/equipment-profiles", equipmentHandler.GetGymProfiles)
+		g.POST("/equipment-profiles", equipmentHandler.CreateGymProfile)
+		g.GET("/equipment-profiles/:uuid", equipmentHandler.GetProfile)
+		g.PUT("/equipment-profiles/:uuid", equipmentHandler.UpdateProfile)
+		g.DELETE("/equipment-profiles/:uuid", equipmentHandler.DeleteProfile)
+		g.GET("/equipment-profiles/:uuid/exercises", equipmentHandler.GetProfileExercises)
+		g.POST("/users/:uuid/session-plans", plannerHandler.CreateSessionPlan)
+		g.GET("/users/:uuid/recovery", recoveryHandler.GetRecovery)
+		g.GET("/users/:uuid/measurements", measurementHandler.GetMeasurements)
+		g.POST("/users/:uuid/measurements", measurementHandler.CreateMeasurement)
+		g.GET("/measurements/:uuid", measurementHandler.GetMeasurement)
+		g.PUT("/measurements/:uuid", measurementHandler.UpdateMeasurement)
+		g.DELETE("/measurements/:uuid", measurementHandler.DeleteMeasurement)
+		g.GET("/users/:uuid/analytics/timeseries", analyticsHandler.GetTimeSeries)
+		g.GET("/users/:uuid/analytics/muscle-volume", analyticsHandler.GetMuscleVolume)
+		g.POST("/calculators/plates", plateHandler.CalculatePlates)
+		g.POST("/calculators/warmup", plateHandler.CalculateWarmup)
+		g.GET("/users/:uuid/scheduled-workouts", calendarHandler.GetScheduledWorkouts)
+		g.POST("/users/:uuid/scheduled-workouts", calendarHandler.CreateScheduledWorkout)
+		g.GET("/scheduled-workouts/:uuid", calendarHandler.GetScheduledWorkout)
+		g.PUT("/scheduled-workouts/:uuid", calendarHandler.UpdateScheduledWorkout)
+		g.DELETE("/scheduled-workouts/:uuid", calendarHandler.DeleteScheduledWorkout)
+		g.GET("/users/:uuid/calendar", calendarHandler.GetCalendar)
+		g.GET("/users/:uuid/calendar-feed", calendarHandler.GetFeed)
+		g.POST("/users/:uuid/calendar-feed", calendarHandler.CreateFeed)
+		g.DELETE("/users/:uuid/calendar-feed", calendarHandler.DeleteFeed)
+		g.GET("/users/:uuid/adherence", adherenceHandler.GetAdherence)
+		g.GET("/coaches/:uuid/clients", coachHandler.GetClients)
+		g.PUT("/coaches/:uuid/clients/:clientUuid", coachHandler.AddClient)
+		g.DELETE("/coaches/:uuid/clients/:clientUuid", coachHandler.RemoveClient)
+		g.GET("/coaches/:uuid/adherence", adherenceHandler.GetRosterAdherence)
+	}
+	routes(r.Engine.Group("/v1", middleware.Deprecated("/v1", "/v2", apiCfg.V1DeprecatedAt, apiCfg.V1Sunset)),
+		exerciseRoutes{create: handler.CreateExercise, get: handler.GetExercise, update: handler.UpdateExercise})
+	routes(r.Engine.Group("/v2"),
+		exerciseRoutes{create: handler.CreateExerciseV2, get: handler.GetExerciseV2, update: handler.UpdateExerciseV2})
+	r.Engine.NoRoute(unversionedRedirect(r.Engine.Routes(),
+		middleware.Deprecated("", "/v2", apiCfg.V1DeprecatedAt, apiCfg.V1Sunset)))
 
 	return r
 }
+
+/*
+ * unversionedRedirect answers requests to the paths routes had before the
+ * API was versioned with a permanent redirect to the same path under /v1,
+ * running the handlers next first. Other unmatched requests get a 404.
+ */
+func unversionedRedirect(routes gin.RoutesInfo, next ...gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		target := "/v1" + ctx.Request.URL.Path
+		if !matchesRoute(routes, ctx.Request.Method, target) {
+			ctx.Abort()
+			return
+		}
+		for _, handler := range next {
+			handler(ctx)
+		}
+		if ctx.Request.URL.RawQuery != "" {
+			target += "?" + ctx.Request.URL.RawQuery
+		}
+		ctx.Redirect(http.StatusPermanentRedirect, target)
+		ctx.Abort()
+	}
+}
+
+// matchesRoute tells whether a request to path would be served by one of
+// routes, whose parameters match any non-empty segment.
+func matchesRoute(routes gin.RoutesInfo, method, path string) bool {
+	segments := strings.Split(path, "/")
+	for _, route := range routes {
+		pattern := strings.Split(route.Path, "/")
+		if route.Method != method || len(pattern) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if strings.HasPrefix(segment, ":") && segments[i] != "" {
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,32 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
-	"github.com/jmoiron/sqlx"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/openapi"
 	"github.com/stretchr/testify/assert"
 )
 
+// testAPIConfig holds the default dates of the old API versions.
+var testAPIConfig = apiConfig{
+	V1DeprecatedAt: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	V1Sunset:       time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC),
+}
+
 func TestGetConnectionString(t *testing.T) {
 	os.Setenv("POSTGRES_HOST", "localhost")
 	os.Setenv("POSTGRES_PORT", "5432")
@@ -43,21 +60,14 @@ func TestGetConnectionString_portNotInt(t *testing.T) {
 }
 
 func TestNewRouter(t *testing.T) {
-	router := NewRouter()
+	router := NewRouter(logging.Discard())
 
 	assert.NotNil(t, router, "Router should not be nil")
 	assert.IsType(t, &gin.Engine{}, router.Engine, "Router.Engine should be of type *gin.Engine")
 }
 
 func TestSetupRouter(t *testing.T) {
-	dbm, _, err := sqlmock.New()
-	assert.NoError(t, err)
-	db := sqlx.NewDb(dbm, "postgres")
-
-	assert.NoError(t, err, "Failed to open database connection")
-	defer db.Close()
-
-	router := setupRouter(db)
+	router := setupRouter(memory.NewDaos(memory.NewStore()), logging.Discard(), testAPIConfig)
 
 	assert.NotNil(t, router, "Router should not be nil")
 	assert.IsType(t, &Router{}, router, "setupRouter should return a *Router")
@@ -67,18 +77,261 @@ func TestSetupRouter(t *testing.T) {
 		method string
 		path   string
 	}{
+		{"GET", "/exercises/export"},
+		{"GET", "/exercises/search"},
 		{"GET", "/exercises/:uuid"},
 		{"POST", "/exercises"},
+		{"POST", "/exercises/import"},
 		{"PUT", "/exercises/:uuid"},
 		{"DELETE", "/exercises/:uuid"},
+		{"GET", "/exercises/:uuid/relations"},
+		{"POST", "/exercises/:uuid/relations"},
+		{"DELETE", "/exercises/:uuid/relations/:relatedUuid"},
+		{"GET", "/exercises/:uuid/easier"},
+		{"GET", "/exercises/:uuid/harder"},
+		{"GET", "/exercises/:uuid/translations"},
+		{"PUT", "/exercises/:uuid/translations/:locale"},
+		{"DELETE", "/exercises/:uuid/translations/:locale"},
+		{"GET", "/exercises/:uuid/aliases"},
+		{"POST", "/exercises/:uuid/aliases"},
+		{"DELETE", "/exercises/:uuid/aliases"},
+		{"GET", "/muscles"},
+		{"GET", "/muscles/tree"},
+		{"GET", "/muscles/:code/exercises"},
+		{"GET", "/categories"},
+		{"GET", "/apparatus"},
+		{"GET", "/translations/locales"},
+		{"PUT", "/translations/:kind/:code/:locale"},
+		{"GET", "/users/:uuid/preferences"},
+		{"PUT", "/users/:uuid/preferences"},
+		{"GET", "/users/:uuid/workouts"},
+		{"POST", "/users/:uuid/workouts"},
+		{"POST", "/users/:uuid/history/import"},
+		{"GET", "/history/mappings/:source"},
+		{"PUT", "/history/mappings/:source"},
+		{"DELETE", "/history/mappings/:source"},
+		{"GET", "/users/:uuid/cardio"},
+		{"POST", "/users/:uuid/cardio/:format"},
+		{"GET", "/cardio/:uuid"},
+		{"GET", "/users/:uuid/equipment-profiles"},
+		{"POST", "/users/:uuid/equipment-profiles"},
+		{"GET", "/users/:uuid/available-exercises"},
+		{"GET", "/equipment-profiles"},
+		{"POST", "/equipment-profiles"},
+		{"GET", "/equipment-profiles/:uuid"},
+		{"PUT", "/equipment-profiles/:uuid"},
+		{"DELETE", "/equipment-profiles/:uuid"},
+		{"GET", "/equipment-profiles/:uuid/exercises"},
+		{"POST", "/users/:uuid/session-plans"},
+		{"GET", "/users/:uuid/recovery"},
+		{"GET", "/users/:uuid/measurements"},
+		{"POST", "/users/:uuid/measurements"},
+		{"GET", "/measurements/:uuid"},
+		{"PUT", "/measurements/:uuid"},
+		{"DELETE", "/measurements/:uuid"},
+		{"GET", "/users/:uuid/analytics/timeseries"},
+		{"POST", "/calculators/plates"},
+		{"POST", "/calculators/warmup"},
+		{"GET", "/users/:uuid/scheduled-workouts"},
+		{"POST", "/users/:uuid/scheduled-workouts"},
+		{"GET", "/scheduled-workouts/:uuid"},
+		{"PUT", "/scheduled-workouts/:uuid"},
+		{"DELETE", "/scheduled-workouts/:uuid"},
+		{"GET", "/users/:uuid/calendar"},
+		{"GET", "/users/:uuid/calendar-feed"},
+		{"POST", "/users/:uuid/calendar-feed"},
+		{"DELETE", "/users/:uuid/calendar-feed"},
+		{"GET", "/users/:uuid/adherence"},
+		{"GET", "/coaches/:uuid/clients"},
+		{"PUT", "/coaches/:uuid/clients/:clientUuid"},
+		{"DELETE", "/coaches/:uuid/clients/:clientUuid"},
+		{"GET", "/coaches/:uuid/adherence"},
+	}
+
+	for _, prefix := range []string{"/v1", "/v2"} {
+		for _, route := range routes {
+			assert.True(t, routeExists(router.Engine, route.method, prefix+route.path),
+				"Route %s %s should exist", route.method, prefix+route.path)
+		}
+	}
+	for _, path := range []string{"/metrics", "/openapi.json", "/docs", "/calendar/:feed"} {
+		assert.True(t, routeExists(router.Engine, "GET", path), "Route GET %s should exist", path)
+	}
+}
+
+// TestOpenAPIMatchesRoutes fails when a route is added without describing
+// it in the OpenAPI document, or the document lists a route that is gone.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	router := setupRouter(memory.NewDaos(memory.NewStore()), logging.Discard(), testAPIConfig)
+
+	registered := map[string]bool{}
+	for _, route := range router.Engine.Routes() {
+		registered[route.Method+" "+openapi.Path(route.Path)] = true
+	}
+	documented := map[string]bool{}
+	for path, item := range openapi.Spec().Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		assert.True(t, documented[route], "%s is not in the OpenAPI document", route)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "%s is documented but not registered", route)
+	}
+}
+
+func TestSetupRouter_OpenAPI(t *testing.T) {
+	router := setupRouter(memory.NewDaos(memory.NewStore()), logging.Discard(), testAPIConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	router.Engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/docs", nil)
+	router.Engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+}
+
+func TestOpenDaos(t *testing.T) {
+	daos, closeDaos, err := openDaos(dao.Config{Driver: dao.DriverMemory})
+	assert.NoError(t, err)
+	assert.Nil(t, daos.DB)
+	assert.NoError(t, closeDaos())
+
+	path := filepath.Join(t.TempDir(), "shred.db")
+	daos, closeDaos, err = openDaos(dao.Config{Driver: dao.DriverSQLite, SQLitePath: path})
+	assert.NoError(t, err)
+	assert.NotNil(t, daos.DB)
+	assert.NoError(t, closeDaos())
+	assert.FileExists(t, path)
+}
+
+func TestSetupRouter_MemoryStore(t *testing.T) {
+	daos := memory.NewDaos(memory.NewStore())
+	_, err := daos.Categories.CreateCategory(context.Background(), &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	assert.NoError(t, err)
+	assert.NoError(t, daos.Licenses.CreateLicense(context.Background(), &model.LicenseRequest{
+		LicenseFields: model.LicenseFields{LicenseShortName: "CC-BY-SA 4", LicenseFullName: "Creative Commons BY-SA 4.0"}}))
+	router := setupRouter(daos, logging.Discard(), testAPIConfig)
+
+	w := httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v2/exercises",
+		strings.NewReader(`{"exerciseName": "Squat", "category": "STRENGTH", "licenseShortName": "CC-BY-SA 4"}`)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created model.ExerciseV2
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/exercises/"+created.ExerciseUuid.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"exerciseName":"Squat"`)
+	assert.Contains(t, w.Body.String(), `"licenseShortName":"CC-BY-SA 4"`)
+
+	w = httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/exercises/"+created.ExerciseUuid.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"licenceShortName":"CC-BY-SA 4"`)
+
+	w = httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v2/exercises",
+		strings.NewReader(`{"exerciseName": "Run", "category": "CARDIO"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetupRouter_Versions(t *testing.T) {
+	router := setupRouter(memory.NewDaos(memory.NewStore()), logging.Discard(), testAPIConfig)
+
+	w := httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/muscles?lang=de", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/muscles>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/muscles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+
+	// Paths from before the API was versioned move to v1.
+	w = httptest.NewRecorder()
+	router.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exercises/"+uuid.NewString()+"/aliases?lang=de", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Regexp(t, `^/v1/exercises/[0-9a-f-]{36}/aliases\?lang=de$`, w.Header().Get("Location"))
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/nowhere", nil),
+		httptest.NewRequest(http.MethodPatch, "/exercises", nil),
+		httptest.NewRequest(http.MethodGet, "/v3/exercises", nil),
+	} {
+		w = httptest.NewRecorder()
+		router.Engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, "%s %s", req.Method, req.URL)
+		assert.Empty(t, w.Header().Get("Deprecation"))
+	}
+}
+
+func TestAPIConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name, deprecatedAt, sunset string
+		expected                   apiConfig
+		fails                      bool
+	}{
+		{"defaults", "", "", testAPIConfig, false},
+		{"dates", "2026-11-01", "2027-06-30", apiConfig{V1DeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			V1Sunset: time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)}, false},
+		{"times", "", "2027-06-30T12:00:00+02:00", apiConfig{V1DeprecatedAt: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			V1Sunset: time.Date(2027, 6, 30, 10, 0, 0, 0, time.UTC)}, false},
+		{"invalid", "19.10.2026", "", apiConfig{}, true},
+		{"sunset before deprecation", "", "2026-01-01", apiConfig{}, true},
 	}
 
-	for _, route := range routes {
-		assert.True(t, routeExists(router.Engine, route.method, route.path),
-			"Route %s %s should exist", route.method, route.path)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("API_V1_DEPRECATED_AT", tt.deprecatedAt)
+			t.Setenv("API_V1_SUNSET", tt.sunset)
+
+			cfg, err := apiConfigFromEnv()
+			if tt.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
 	}
 }
 
+func TestSetupRouter_APIConfig(t *testing.T) {
+	daos := memory.NewDaos(memory.NewStore())
+	moved := setupRouter(daos, logging.Discard(), apiConfig{V1DeprecatedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		V1Sunset: time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)})
+	standard := setupRouter(daos, logging.Discard(), testAPIConfig)
+
+	w := httptest.NewRecorder()
+	moved.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/muscles", nil))
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = httptest.NewRecorder()
+	standard.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/muscles", nil))
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"), "routers do not share their dates")
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+}
+
 func routeExists(engine *gin.Engine, method, path string) bool {
 	for _, route := range engine.Routes() {
 		if route.Method == method && route.Path == path {
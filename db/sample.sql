@@ -2,11 +2,33 @@ insert into shred_user (
     user_uuid, first_name, last_name, email, created_by
 ) values ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'John', 'Doe', 'jdoe@gmail.com', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
 
+insert into shred_user (
+    user_uuid, first_name, last_name, email, created_by
+) values ('a1c0ac40-0b1d-4b7b-8b3d-3b1b1b1b1b2a', 'Jane', 'Roe', 'jroe@gmail.com', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
+insert into muscle_type (
+    muscle_code, muscle_name, muscle_description, 
+    muscle_group, muscle_level, created_by
+) values ('LEGS', 'Legs', 'The muscles of the hips, thighs and lower legs.', 
+    'Legs', 'region', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
 insert into muscle_type (
     muscle_code, muscle_name, muscle_description, 
-    muscle_group, created_by
+    muscle_group, muscle_level, parent_code, movement_pattern, created_by
 ) values ('QUAD', 'Quadriceps', 'The quadriceps are a group of muscles located at the front of the thigh.', 
-    'Legs', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+    'Legs', 'group', 'LEGS', 'push', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
+insert into muscle_type (
+    muscle_code, muscle_name, muscle_description, 
+    muscle_group, muscle_level, parent_code, created_by
+) values ('RECTUS_FEMORIS', 'Rectus femoris', 'The only quadriceps muscle that crosses the hip as well as the knee.', 
+    'Legs', 'muscle', 'QUAD', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
+insert into muscle_type (
+    muscle_code, muscle_name, muscle_description, 
+    muscle_group, muscle_level, parent_code, created_by
+) values ('VASTUS_LATERALIS', 'Vastus lateralis', 'The largest quadriceps muscle, on the outside of the thigh.', 
+    'Legs', 'muscle', 'QUAD', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
 
 insert into category_type (
     category_code, category_name, category_description, 
@@ -14,11 +36,17 @@ insert into category_type (
 ) values ('STRENGTH', 'Strength Training', 'Exercises that improve strength and endurance.', 
     'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
 
+insert into category_type (
+    category_code, category_name, category_description, 
+    created_by
+) values ('CARDIO', 'Cardio', 'Endurance activities such as running, cycling and rowing.', 
+    'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
 insert into apparatus_type (
     apparatus_code, apparatus_name, apparatus_description, 
-    created_by
+    apparatus_group, created_by
 ) values ('BARBELL', 'Barbell', 'A long bar with weights on either end used for strength training.', 
-    'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+    'Free weights', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
 
 insert into license (
     license_short_name, license_full_name, url, 
@@ -38,4 +66,42 @@ insert into exercise (
     'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f'
 );
 
-commit;
\ No newline at end of file
+insert into exercise (
+    exercise_name, exercise_description, category_code, created_by
+) values (
+    'Running', 'Running outdoors or on a treadmill.', 'CARDIO',
+    'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f'
+);
+
+insert into exercise_muscle (exercise_uuid, muscle_code, muscle_role)
+select exercise_uuid, 'RECTUS_FEMORIS', 'primary' from exercise where exercise_name = 'Squat'
+union all
+select exercise_uuid, 'VASTUS_LATERALIS', 'primary' from exercise where exercise_name = 'Squat';
+
+insert into exercise_apparatus (exercise_uuid, apparatus_code)
+select exercise_uuid, 'BARBELL' from exercise where exercise_name = 'Squat';
+
+insert into equipment_profile (
+    user_uuid, profile_name, is_default, created_by
+) values ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'Home', true, 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
+insert into equipment_profile_apparatus (profile_uuid, apparatus_code)
+select profile_uuid, 'BARBELL' from equipment_profile where profile_name = 'Home';
+
+insert into body_measurement (
+    user_uuid, measurement_kind, site, measured_value, measured_at
+) values
+    ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'bodyweight', null, 82.4, '2025-03-01 07:30:00'),
+    ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'bodyweight', null, 82.1, '2025-03-03 07:30:00'),
+    ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'circumference', 'WAIST', 86.0, '2025-03-03 07:35:00');
+
+insert into scheduled_workout (
+    user_uuid, session_name, scheduled_at, duration_minutes
+) values
+    ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'Legs', '2025-03-03 17:00:00', 60),
+    ('f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f', 'Push', '2025-03-05 17:00:00', 60);
+
+insert into coach_client (coach_uuid, client_uuid)
+values ('a1c0ac40-0b1d-4b7b-8b3d-3b1b1b1b1b2a', 'f47f3b9e-0b1d-4b7b-8b3d-3b1b1b1b1b1f');
+
+commit;
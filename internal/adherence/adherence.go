@@ -0,0 +1,90 @@
+// Package adherence measures how well users keep to the workouts they
+// schedule: planned against completed workouts, weekly streaks, missed days
+// and consistency over rolling windows, per user and per coach's roster.
+package adherence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/calendar"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// DefaultWeeks and MaxWeeks bound the weeks a report covers.
+const (
+	DefaultWeeks = 12
+	MaxWeeks     = 52
+)
+
+// Windows are the days of the rolling windows consistency is scored over.
+var Windows = []int{7, 28, 90}
+
+type ReporterInterface interface {
+	Report(ctx context.Context, userUuid uuid.UUID, weeks int, now time.Time) (*model.AdherenceReport, error)
+	Roster(ctx context.Context, coachUuid uuid.UUID, weeks int, now time.Time) (*model.RosterAdherence, error)
+}
+
+// Reporter computes adherence from the scheduled workouts and the logged
+// sessions of users.
+type Reporter struct {
+	schedules dao.CalendarDaoInterface
+	coaches   dao.CoachDaoInterface
+}
+
+// Ensure Reporter implements ReporterInterface
+var _ ReporterInterface = (*Reporter)(nil)
+
+// NewReporter creates a new instance of Reporter.
+func NewReporter(schedules dao.CalendarDaoInterface, coaches dao.CoachDaoInterface) *Reporter {
+	return &Reporter{schedules: schedules, coaches: coaches}
+}
+
+// Report computes the adherence of a user over the weeks up to now, in the
+// time zone of now.
+func (r *Reporter) Report(ctx context.Context, userUuid uuid.UUID, weeks int, now time.Time) (*model.AdherenceReport, error) {
+	current, end := calendar.Span(model.CalendarWeek, now)
+	from := current.AddDate(0, 0, -7*(weeks-1))
+	if since := now.AddDate(0, 0, -Windows[len(Windows)-1]); since.Before(from) {
+		from = since
+	}
+
+	workouts, err := r.schedules.ListScheduledWorkouts(ctx, userUuid, from, end)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := r.schedules.ListCalendarSessions(ctx, userUuid,
+		from.Add(-calendar.MatchWindow), end.Add(calendar.MatchWindow))
+	if err != nil {
+		return nil, err
+	}
+	calendar.Resolve(workouts, sessions, now)
+	return Compute(userUuid, workouts, sessions, weeks, now), nil
+}
+
+// Roster computes the adherence of every client of a coach and pools their
+// windows.
+func (r *Reporter) Roster(ctx context.Context, coachUuid uuid.UUID, weeks int, now time.Time) (*model.RosterAdherence, error) {
+	clients, err := r.coaches.ListClients(ctx, coachUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := &model.RosterAdherence{CoachUuid: coachUuid, ComputedAt: now, Clients: []model.ClientAdherence{}}
+	reports := make([]*model.AdherenceReport, 0, len(clients))
+	for _, client := range clients {
+		report, err := r.Report(ctx, client.UserUuid, weeks, now)
+		if err != nil {
+			return nil, err
+		}
+		if report.CurrentStreak > 0 {
+			roster.OnStreak++
+		}
+		reports = append(reports, report)
+		roster.Clients = append(roster.Clients, model.ClientAdherence{RosterClient: client, Adherence: report})
+	}
+	roster.Windows = Pool(reports)
+	return roster, nil
+}
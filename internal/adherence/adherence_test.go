@@ -0,0 +1,72 @@
+package adherence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestReporter returns a reporter on a memory store, with the DAOs to add
+// clients, scheduled workouts and sessions to it.
+func newTestReporter(t *testing.T) (*Reporter, *dao.Daos) {
+	daos := memory.NewDaos(memory.NewStore())
+	return NewReporter(daos.Calendar, daos.Coach), daos
+}
+
+// train schedules a workout for the user at each time and logs a session at
+// the times in done.
+func train(t *testing.T, daos *dao.Daos, userUuid uuid.UUID, scheduled []time.Time, done ...time.Time) {
+	ctx := context.Background()
+	minutes := 60
+	for _, at := range scheduled {
+		_, err := daos.Calendar.CreateScheduledWorkout(ctx, userUuid,
+			&model.ScheduledWorkoutFields{SessionName: "Workout", ScheduledAt: at, DurationMinutes: &minutes})
+		require.NoError(t, err)
+	}
+	for _, at := range done {
+		_, err := daos.Workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{{
+			WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Workout", StartedAt: at}}})
+		require.NoError(t, err)
+	}
+}
+
+func TestReport(t *testing.T) {
+	reporter, daos := newTestReporter(t)
+
+	userUuid := uuid.New()
+	// the 90 day window reaches back further than 12 weeks
+	train(t, daos, userUuid, []time.Time{now.AddDate(0, 0, -89), day(10), day(14)}, day(10))
+
+	report, err := reporter.Report(context.Background(), userUuid, DefaultWeeks, now)
+	assert.NoError(t, err)
+	assert.Len(t, report.Weeks, DefaultWeeks)
+	assert.Equal(t, 1, report.Completed)
+	assert.Equal(t, 1, report.Upcoming)
+	last := report.Windows[len(report.Windows)-1]
+	assert.Equal(t, Windows[len(Windows)-1], last.Days)
+	assert.Equal(t, 2, last.Due)
+	assert.Equal(t, 1, report.CurrentStreak)
+}
+
+func TestRoster(t *testing.T) {
+	reporter, daos := newTestReporter(t)
+
+	coachUuid, clientUuid := uuid.New(), uuid.New()
+	require.NoError(t, daos.Coach.AddClient(context.Background(), coachUuid, clientUuid))
+	train(t, daos, clientUuid, []time.Time{day(10), day(11)}, day(10))
+
+	roster, err := reporter.Roster(context.Background(), coachUuid, 4, now)
+	assert.NoError(t, err)
+	assert.Len(t, roster.Clients, 1)
+	assert.Equal(t, clientUuid, roster.Clients[0].UserUuid)
+	assert.Equal(t, 0, roster.OnStreak, "the second workout was missed this week")
+	assert.Equal(t, 2, roster.Windows[0].Due)
+	assert.Equal(t, 50.0, *roster.Windows[0].Score)
+}
@@ -0,0 +1,160 @@
+package adherence
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/calendar"
+	"github.com/pwydra/shred/internal/model"
+)
+
+/*
+ * Compute works out the adherence at now over the weeks up to and including
+ * the week of now, in the time zone of now, from workouts resolved with
+ * calendar.Resolve and the sessions they were resolved against. The week of
+ * now is still going, so it only breaks the current streak once a workout
+ * in it was missed.
+ */
+func Compute(userUuid uuid.UUID, workouts []model.ScheduledWorkout, sessions []model.CalendarSession, weeks int, now time.Time) *model.AdherenceReport {
+	loc := now.Location()
+	current, _ := calendar.Span(model.CalendarWeek, now)
+	first := current.AddDate(0, 0, -7*(weeks-1))
+	report := &model.AdherenceReport{UserUuid: userUuid, ComputedAt: now, TimeZone: loc.String(),
+		MissedDays: []string{}, Weeks: make([]model.AdherenceWeek, weeks)}
+	for i := range report.Weeks {
+		report.Weeks[i].Week = first.AddDate(0, 0, 7*i).Format(time.DateOnly)
+	}
+	week := func(t time.Time) *model.AdherenceWeek {
+		start, _ := calendar.Span(model.CalendarWeek, t.In(loc))
+		// whole days, also across a change of clocks
+		i := int(math.Round(start.Sub(first).Hours()/24)) / 7
+		if start.Before(first) || i >= weeks {
+			return nil
+		}
+		return &report.Weeks[i]
+	}
+
+	for _, w := range workouts {
+		wk := week(w.ScheduledAt)
+		if wk == nil {
+			continue
+		}
+		wk.Scheduled++
+		switch w.Status {
+		case model.ScheduleCompleted:
+			wk.Completed++
+		case model.ScheduleMissed:
+			wk.Missed++
+			day := w.ScheduledAt.In(loc).Format(time.DateOnly)
+			if !slices.Contains(report.MissedDays, day) {
+				report.MissedDays = append(report.MissedDays, day)
+			}
+		default:
+			report.Upcoming++
+		}
+	}
+	unplanned := unplannedSessions(workouts, sessions)
+	for _, s := range unplanned {
+		if wk := week(s.StartedAt); wk != nil {
+			wk.Unplanned++
+		}
+	}
+	slices.Sort(report.MissedDays)
+
+	run := 0
+	for i := range report.Weeks {
+		wk := &report.Weeks[i]
+		wk.Kept = wk.Missed == 0 && wk.Completed+wk.Unplanned > 0
+		report.Scheduled += wk.Scheduled
+		report.Completed += wk.Completed
+		report.Missed += wk.Missed
+		report.Unplanned += wk.Unplanned
+		if wk.Kept {
+			run++
+			report.LongestStreak = max(report.LongestStreak, run)
+		} else if i < weeks-1 || wk.Missed > 0 {
+			run = 0
+		}
+	}
+	report.CurrentStreak = run
+
+	for _, days := range Windows {
+		report.Windows = append(report.Windows, window(days, workouts, unplanned, now))
+	}
+	return report
+}
+
+// Pool adds up the windows of reports into windows over all of them.
+func Pool(reports []*model.AdherenceReport) []model.AdherenceWindow {
+	pooled := make([]model.AdherenceWindow, len(Windows))
+	for i, days := range Windows {
+		pooled[i].Days = days
+		for _, r := range reports {
+			w := r.Windows[i]
+			pooled[i].Due += w.Due
+			pooled[i].Completed += w.Completed
+			pooled[i].Missed += w.Missed
+			pooled[i].Unplanned += w.Unplanned
+		}
+		pooled[i].Score = score(pooled[i].Completed, pooled[i].Due)
+	}
+	return pooled
+}
+
+// window sums up the workouts scheduled and the unplanned sessions started
+// within days before now.
+func window(days int, workouts []model.ScheduledWorkout, unplanned []model.CalendarSession, now time.Time) model.AdherenceWindow {
+	since := now.AddDate(0, 0, -days)
+	within := func(t time.Time) bool { return t.After(since) && !t.After(now) }
+
+	w := model.AdherenceWindow{Days: days}
+	for _, workout := range workouts {
+		if !within(workout.ScheduledAt) {
+			continue
+		}
+		switch workout.Status {
+		case model.ScheduleCompleted:
+			w.Due++
+			w.Completed++
+		case model.ScheduleMissed:
+			w.Due++
+			w.Missed++
+		}
+	}
+	for _, s := range unplanned {
+		if within(s.StartedAt) {
+			w.Unplanned++
+		}
+	}
+	w.Score = score(w.Completed, w.Due)
+	return w
+}
+
+// unplannedSessions returns the sessions that completed no workout.
+func unplannedSessions(workouts []model.ScheduledWorkout, sessions []model.CalendarSession) []model.CalendarSession {
+	planned := map[uuid.UUID]bool{}
+	for _, w := range workouts {
+		if w.SessionUuid != nil {
+			planned[*w.SessionUuid] = true
+		}
+	}
+	unplanned := []model.CalendarSession{}
+	for _, s := range sessions {
+		if !planned[s.SessionUuid] {
+			unplanned = append(unplanned, s)
+		}
+	}
+	return unplanned
+}
+
+// score is the percentage of due workouts that were completed, to one
+// decimal.
+func score(completed, due int) *float64 {
+	if due == 0 {
+		return nil
+	}
+	s := math.Round(float64(completed)/float64(due)*1000) / 10
+	return &s
+}
@@ -0,0 +1,146 @@
+package adherence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/calendar"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// now is Wednesday evening; its week started on Monday 2025-03-10.
+var now = time.Date(2025, 3, 12, 20, 0, 0, 0, time.UTC)
+
+func scheduled(at time.Time) model.ScheduledWorkout {
+	return model.ScheduledWorkout{ScheduleUuid: uuid.New(),
+		ScheduledWorkoutFields: model.ScheduledWorkoutFields{SessionName: "Workout", ScheduledAt: at}}
+}
+
+func session(at time.Time) model.CalendarSession {
+	return model.CalendarSession{SessionUuid: uuid.New(), SessionName: "Workout", StartedAt: at}
+}
+
+// day returns 18:00 on the given day of March 2025.
+func day(d int) time.Time {
+	return time.Date(2025, 3, d, 18, 0, 0, 0, time.UTC)
+}
+
+func TestCompute(t *testing.T) {
+	workouts := []model.ScheduledWorkout{
+		scheduled(day(17).AddDate(0, 0, -35)), // 2025-02-10, missed
+		scheduled(day(17).AddDate(0, 0, -21)), // 2025-02-24
+		scheduled(day(3)), scheduled(day(5)),
+		scheduled(day(10)), scheduled(day(14)), // upcoming on Friday
+	}
+	sessions := []model.CalendarSession{
+		session(day(17).AddDate(0, 0, -21).Add(time.Hour)),
+		session(day(17).AddDate(0, 0, -14)), // unplanned in the week of 2025-03-03
+		session(day(3)), session(day(5)),
+		session(day(10).Add(-2 * time.Hour)),
+	}
+	calendar.Resolve(workouts, sessions, now)
+
+	report := Compute(uuid.Nil, workouts, sessions, 6, now)
+
+	assert.Equal(t, []string{"2025-02-03", "2025-02-10", "2025-02-17", "2025-02-24", "2025-03-03", "2025-03-10"},
+		weekStarts(report.Weeks))
+	assert.Equal(t, []bool{false, false, false, true, true, true}, keptWeeks(report.Weeks))
+	assert.Equal(t, 1, report.Weeks[4].Unplanned)
+	assert.Equal(t, 2, report.Weeks[4].Completed)
+	assert.Equal(t, 6, report.Scheduled)
+	assert.Equal(t, 4, report.Completed)
+	assert.Equal(t, 1, report.Missed)
+	assert.Equal(t, 1, report.Upcoming)
+	assert.Equal(t, 1, report.Unplanned)
+	assert.Equal(t, []string{"2025-02-10"}, report.MissedDays)
+	assert.Equal(t, 3, report.CurrentStreak)
+	assert.Equal(t, 3, report.LongestStreak)
+
+	assert.Len(t, report.Windows, len(Windows))
+	week, month := report.Windows[0], report.Windows[1]
+	assert.Equal(t, 7, week.Days)
+	assert.Equal(t, 1, week.Due, "the workout of 10 March")
+	assert.Equal(t, 100.0, *week.Score)
+	assert.Equal(t, 28, month.Days)
+	assert.Equal(t, 4, month.Due)
+	assert.Equal(t, 1, month.Unplanned)
+	assert.Equal(t, 80.0, *report.Windows[2].Score, "4 of the 5 workouts due in 90 days")
+}
+
+func TestCompute_Streaks(t *testing.T) {
+	tests := []struct {
+		name             string
+		workouts         []model.ScheduledWorkout
+		sessions         []model.CalendarSession
+		current, longest int
+	}{
+		{"nothing logged", nil, nil, 0, 0},
+		{"week still going", nil, []model.CalendarSession{session(day(3))}, 1, 1},
+		{"kept this week", nil, []model.CalendarSession{session(day(3)), session(day(11))}, 2, 2},
+		{"missed this week", []model.ScheduledWorkout{scheduled(day(10))},
+			[]model.CalendarSession{session(day(3))}, 0, 1},
+		{"gap", nil, []model.CalendarSession{session(day(17).AddDate(0, 0, -35)), session(day(17).AddDate(0, 0, -28)),
+			session(day(3))}, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar.Resolve(tt.workouts, tt.sessions, now)
+			report := Compute(uuid.Nil, tt.workouts, tt.sessions, 5, now)
+			assert.Equal(t, tt.current, report.CurrentStreak)
+			assert.Equal(t, tt.longest, report.LongestStreak)
+		})
+	}
+}
+
+func TestCompute_TimeZone(t *testing.T) {
+	auckland := time.FixedZone("NZDT", 13*3600)
+	// Sunday evening in UTC is Monday morning in Auckland
+	workouts := []model.ScheduledWorkout{scheduled(time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC))}
+	calendar.Resolve(workouts, nil, now)
+
+	report := Compute(uuid.Nil, workouts, nil, 2, now.In(auckland))
+	assert.Equal(t, "2025-03-10", report.Weeks[1].Week)
+	assert.Equal(t, 1, report.Weeks[1].Missed)
+	assert.Equal(t, []string{"2025-03-10"}, report.MissedDays)
+}
+
+func TestPool(t *testing.T) {
+	a := Compute(uuid.Nil, missed(day(10)), nil, 1, now)
+	b := Compute(uuid.Nil, nil, nil, 1, now)
+	workouts, sessions := []model.ScheduledWorkout{scheduled(day(6)), scheduled(day(7))},
+		[]model.CalendarSession{session(day(6)), session(day(7))}
+	calendar.Resolve(workouts, sessions, now)
+	c := Compute(uuid.Nil, workouts, sessions, 1, now)
+
+	pooled := Pool([]*model.AdherenceReport{a, b, c})
+	assert.Len(t, pooled, len(Windows))
+	assert.Equal(t, 3, pooled[0].Due)
+	assert.Equal(t, 2, pooled[0].Completed)
+	assert.Equal(t, 66.7, *pooled[0].Score)
+	assert.Nil(t, Pool(nil)[0].Score)
+}
+
+func missed(at time.Time) []model.ScheduledWorkout {
+	workouts := []model.ScheduledWorkout{scheduled(at)}
+	calendar.Resolve(workouts, nil, now)
+	return workouts
+}
+
+func weekStarts(weeks []model.AdherenceWeek) []string {
+	starts := []string{}
+	for _, w := range weeks {
+		starts = append(starts, w.Week)
+	}
+	return starts
+}
+
+func keptWeeks(weeks []model.AdherenceWeek) []bool {
+	kept := []bool{}
+	for _, w := range weeks {
+		kept = append(kept, w.Kept)
+	}
+	return kept
+}
@@ -0,0 +1,149 @@
+package analytics
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ErrUnknownMetric is returned for a metric not in Metrics.
+var ErrUnknownMetric = errors.New("unknown metric")
+
+// ErrUnknownLevel is returned for a muscle level that is not one of the
+// model.MuscleLevel constants.
+var ErrUnknownLevel = errors.New("unknown muscle level")
+
+const (
+	// DefaultTrendWindowDays is the moving average window without a request.
+	DefaultTrendWindowDays = 7
+	// MaxTrendWindowDays bounds the window a request may ask for.
+	MaxTrendWindowDays = 90
+)
+
+// Metrics lists the metrics a time series can be asked for.
+var Metrics = []string{model.MetricVolume, string(model.MeasurementBodyweight),
+	string(model.MeasurementBodyFat), string(model.MeasurementCircumference)}
+
+type AnalyzerInterface interface {
+	TimeSeries(ctx context.Context, userUuid uuid.UUID, opts Options) (*model.TimeSeries, error)
+	MuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) (*model.MuscleVolumes, error)
+}
+
+type Options struct {
+	From, To        time.Time
+	TrendWindowDays int
+	// Metrics limits the series to these metrics, all when empty.
+	Metrics []string
+}
+
+// Analyzer turns the training and measurements of users into time series.
+type Analyzer struct {
+	analytics    dao.AnalyticsDaoInterface
+	measurements dao.MeasurementDaoInterface
+}
+
+// Ensure Analyzer implements AnalyzerInterface
+var _ AnalyzerInterface = (*Analyzer)(nil)
+
+// NewAnalyzer creates a new instance of Analyzer.
+func NewAnalyzer(analytics dao.AnalyticsDaoInterface, measurements dao.MeasurementDaoInterface) *Analyzer {
+	return &Analyzer{analytics: analytics, measurements: measurements}
+}
+
+/*
+ * TimeSeries returns the daily training volume and every measured kind, and
+ * site for circumferences, as series between From and To with their trend.
+ * Points from one trend window before From are read so the trend starts
+ * smoothed, but only points from From on are returned.
+ */
+func (a *Analyzer) TimeSeries(ctx context.Context, userUuid uuid.UUID, opts Options) (*model.TimeSeries, error) {
+	for _, metric := range opts.Metrics {
+		if !slices.Contains(Metrics, metric) {
+			return nil, fmt.Errorf("%w %q", ErrUnknownMetric, metric)
+		}
+	}
+	if opts.TrendWindowDays == 0 {
+		opts.TrendWindowDays = DefaultTrendWindowDays
+	}
+	window := time.Duration(opts.TrendWindowDays) * 24 * time.Hour
+	since := opts.From.Add(-window)
+	wanted := func(metric string) bool { return len(opts.Metrics) == 0 || slices.Contains(opts.Metrics, metric) }
+
+	ts := &model.TimeSeries{
+		UserUuid:        userUuid,
+		From:            opts.From,
+		To:              opts.To,
+		TrendWindowDays: opts.TrendWindowDays,
+		Series:          []model.Series{},
+	}
+	if wanted(model.MetricVolume) {
+		points, err := a.analytics.GetDailyVolume(ctx, userUuid, since, opts.To)
+		if err != nil {
+			return nil, err
+		}
+		if series, ok := newSeries(model.MetricVolume, "", "kg", points, window, opts.From); ok {
+			ts.Series = append(ts.Series, series)
+		}
+	}
+
+	measurements, err := a.measurements.ListMeasurements(ctx, userUuid, "", "", since, opts.To)
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		kind model.MeasurementKind
+		site string
+	}
+	grouped := map[key][]model.SeriesPoint{}
+	for _, m := range measurements {
+		if wanted(string(m.Kind)) {
+			k := key{m.Kind, m.Site}
+			grouped[k] = append(grouped[k], model.SeriesPoint{At: m.MeasuredAt, Value: m.Value})
+		}
+	}
+	keys := make([]key, 0, len(grouped))
+	for k := range grouped {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		return cmp.Or(cmp.Compare(slices.Index(Metrics, string(a.kind)), slices.Index(Metrics, string(b.kind))),
+			cmp.Compare(a.site, b.site))
+	})
+	for _, k := range keys {
+		if series, ok := newSeries(string(k.kind), k.site, k.kind.Unit(), grouped[k], window, opts.From); ok {
+			ts.Series = append(ts.Series, series)
+		}
+	}
+	return ts, nil
+}
+
+// MuscleVolume rolls the training volume of a user between from and to up
+// to the muscles of level, e.g. to the regions of the body, in kg.
+func (a *Analyzer) MuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) (*model.MuscleVolumes, error) {
+	if !level.Valid() {
+		return nil, fmt.Errorf("%w %q", ErrUnknownLevel, level)
+	}
+	muscles, err := a.analytics.GetMuscleVolume(ctx, userUuid, level, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &model.MuscleVolumes{UserUuid: userUuid, From: from, To: to, Level: level, Unit: "kg", Muscles: muscles}, nil
+}
+
+// newSeries smooths points and keeps those from from on, reporting whether
+// any are left.
+func newSeries(metric, site, unit string, points []model.SeriesPoint, window time.Duration, from time.Time) (model.Series, bool) {
+	trend := MovingAverage(points, window)
+	first := slices.IndexFunc(points, func(p model.SeriesPoint) bool { return !p.At.Before(from) })
+	if first < 0 {
+		return model.Series{}, false
+	}
+	return model.Series{Metric: metric, Site: site, Unit: unit, Points: points[first:], Trend: trend[first:]}, true
+}
@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newTestAnalyzer returns an analyzer on a memory store holding a squat for
+ * the quadriceps, in the legs region, with the DAOs to log training and
+ * measurements with and the uuid of the squat.
+ */
+func newTestAnalyzer(t *testing.T) (*Analyzer, *dao.Daos, uuid.UUID) {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	legs := "LEGS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		require.NoError(t, err)
+	}
+	squat, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Exercises.AddMuscles(ctx, squat.ExerciseUuid,
+		[]model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}}))
+	return NewAnalyzer(daos.Analytics, daos.Measurements), daos, squat.ExerciseUuid
+}
+
+// lift logs a session of the user at a time with a set of five reps of an
+// exercise adding up to volume kg.
+func lift(t *testing.T, daos *dao.Daos, exUuid, userUuid uuid.UUID, at time.Time, volume float64) {
+	reps, weight := 5, volume/5
+	_, err := daos.Workouts.CreateSessions(context.Background(), userUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: at},
+		Sets:                 []model.WorkoutSet{{ExerciseUuid: exUuid, Reps: &reps, WeightKg: &weight}},
+	}})
+	require.NoError(t, err)
+}
+
+// measure records a measurement of the user a number of days from day.
+func measure(t *testing.T, daos *dao.Daos, userUuid uuid.UUID, kind model.MeasurementKind, site string, days int, value float64) {
+	_, err := daos.Measurements.CreateMeasurement(context.Background(), userUuid, &model.MeasurementRequest{
+		MeasurementFields: model.MeasurementFields{Kind: kind, Site: site, Value: value, MeasuredAt: day.AddDate(0, 0, days)}})
+	require.NoError(t, err)
+}
+
+func TestTimeSeries(t *testing.T) {
+	analyzer, daos, squat := newTestAnalyzer(t)
+
+	userUuid := uuid.New()
+	from, to := day, day.AddDate(0, 0, 14)
+	lift(t, daos, squat, userUuid, day.AddDate(0, 0, -2), 3000)
+	lift(t, daos, squat, userUuid, day.AddDate(0, 0, 1), 4000)
+	// too long ago to smooth the trend
+	lift(t, daos, squat, userUuid, day.AddDate(0, 0, -DefaultTrendWindowDays-1), 9000)
+	measure(t, daos, userUuid, model.MeasurementBodyFat, "", -5, 20)
+	measure(t, daos, userUuid, model.MeasurementBodyweight, "", -3, 83)
+	measure(t, daos, userUuid, model.MeasurementCircumference, "WAIST", 0, 86)
+	measure(t, daos, userUuid, model.MeasurementCircumference, "HIPS", 0, 98)
+	measure(t, daos, userUuid, model.MeasurementBodyweight, "", 2, 82)
+
+	ts, err := analyzer.TimeSeries(context.Background(), userUuid, Options{From: from, To: to})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTrendWindowDays, ts.TrendWindowDays)
+
+	type summary struct {
+		metric, site, unit string
+		points, trend      []float64
+	}
+	summaries := []summary{}
+	for _, s := range ts.Series {
+		sum := summary{metric: s.Metric, site: s.Site, unit: s.Unit}
+		for i := range s.Points {
+			sum.points = append(sum.points, s.Points[i].Value)
+			sum.trend = append(sum.trend, s.Trend[i].Value)
+		}
+		summaries = append(summaries, sum)
+	}
+	// body fat was only measured before from, so it has no series
+	assert.Equal(t, []summary{
+		{"volume", "", "kg", []float64{4000}, []float64{3500}},
+		{"bodyweight", "", "kg", []float64{82}, []float64{82.5}},
+		{"circumference", "HIPS", "cm", []float64{98}, []float64{98}},
+		{"circumference", "WAIST", "cm", []float64{86}, []float64{86}},
+	}, summaries)
+}
+
+func TestTimeSeries_Metrics(t *testing.T) {
+	analyzer, daos, squat := newTestAnalyzer(t)
+
+	userUuid := uuid.New()
+	from, to := day, day.AddDate(0, 0, 14)
+	lift(t, daos, squat, userUuid, day, 3000)
+	measure(t, daos, userUuid, model.MeasurementBodyweight, "", 0, 82)
+	measure(t, daos, userUuid, model.MeasurementBodyFat, "", 0, 19.5)
+
+	ts, err := analyzer.TimeSeries(context.Background(), userUuid,
+		Options{From: from, To: to, TrendWindowDays: 30, Metrics: []string{"body_fat"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 30, ts.TrendWindowDays)
+	assert.Len(t, ts.Series, 1)
+	assert.Equal(t, "body_fat", ts.Series[0].Metric)
+	assert.Equal(t, "%", ts.Series[0].Unit)
+
+	_, err = analyzer.TimeSeries(context.Background(), userUuid, Options{From: from, To: to, Metrics: []string{"steps"}})
+	assert.True(t, errors.Is(err, ErrUnknownMetric))
+}
+
+func TestTimeSeries_Empty(t *testing.T) {
+	analyzer, _, _ := newTestAnalyzer(t)
+
+	ts, err := analyzer.TimeSeries(context.Background(), uuid.New(),
+		Options{From: day, To: day.Add(24 * time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Series{}, ts.Series)
+}
+
+func TestMuscleVolume(t *testing.T) {
+	analyzer, daos, squat := newTestAnalyzer(t)
+	ctx := context.Background()
+	userUuid := uuid.New()
+	lift(t, daos, squat, userUuid, day, 500)
+
+	volumes, err := analyzer.MuscleVolume(ctx, userUuid, model.MuscleLevelRegion, day, day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, &model.MuscleVolumes{UserUuid: userUuid, From: day, To: day.AddDate(0, 0, 1),
+		Level: model.MuscleLevelRegion, Unit: "kg", Muscles: []model.MuscleVolume{
+			{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion, Sets: 1, Volume: 500}}}, volumes)
+
+	_, err = analyzer.MuscleVolume(ctx, userUuid, "limb", day, day.AddDate(0, 0, 1))
+	assert.ErrorIs(t, err, ErrUnknownLevel)
+}
@@ -0,0 +1,32 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+/*
+ * MovingAverage smooths points, sorted by time, with a trailing average over
+ * window: each point becomes the mean of the points of the window ending at
+ * it, or the point alone when it is the only one. Averaging over time
+ * rather than over a number of points copes with irregular weigh-ins.
+ */
+func MovingAverage(points []model.SeriesPoint, window time.Duration) []model.SeriesPoint {
+	trend := make([]model.SeriesPoint, len(points))
+	start, sum := 0, 0.0
+	for i, p := range points {
+		sum += p.Value
+		for start < i && !points[start].At.After(p.At.Add(-window)) {
+			sum -= points[start].Value
+			start++
+		}
+		trend[i] = model.SeriesPoint{At: p.At, Value: round(sum / float64(i-start+1))}
+	}
+	return trend
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,50 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var day = time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+
+func points(values map[int]float64, days ...int) []model.SeriesPoint {
+	p := make([]model.SeriesPoint, len(days))
+	for i, d := range days {
+		p[i] = model.SeriesPoint{At: day.AddDate(0, 0, d), Value: values[d]}
+	}
+	return p
+}
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		days   []int
+		values map[int]float64
+		window time.Duration
+		want   []float64
+	}{
+		{"empty", nil, nil, 7 * 24 * time.Hour, []float64{}},
+		{"single point", []int{0}, map[int]float64{0: 82.4}, 7 * 24 * time.Hour, []float64{82.4}},
+		{"daily", []int{0, 1, 2, 3}, map[int]float64{0: 80, 1: 82, 2: 81, 3: 83}, 2 * 24 * time.Hour,
+			[]float64{80, 81, 81.5, 82}},
+		{"irregular weigh-ins", []int{0, 1, 8, 9}, map[int]float64{0: 84, 1: 83, 8: 81, 9: 80}, 7 * 24 * time.Hour,
+			[]float64{84, 83.5, 81, 80.5}},
+		{"rounded", []int{0, 1, 2}, map[int]float64{0: 80, 1: 80, 2: 81}, 7 * 24 * time.Hour,
+			[]float64{80, 80, 80.33}},
+		{"zero window", []int{0, 1}, map[int]float64{0: 80, 1: 82}, 0, []float64{80, 82}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := MovingAverage(points(tt.values, tt.days...), tt.window)
+			values := []float64{}
+			for i, p := range trend {
+				assert.Equal(t, day.AddDate(0, 0, tt.days[i]), p.At)
+				values = append(values, p.Value)
+			}
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
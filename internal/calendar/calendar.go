@@ -0,0 +1,125 @@
+// Package calendar lays scheduled workouts and logged sessions out on a
+// calendar, tells which scheduled workouts were done and serves them as an
+// iCalendar feed.
+package calendar
+
+import (
+	"context"
+	"io"
+	"time"
+	// time zones of calendar views, also in images without zoneinfo
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Feeds list the sessions of FeedPast before and the workouts scheduled up
+// to FeedAhead after they are fetched.
+const (
+	FeedPast  = 90 * 24 * time.Hour
+	FeedAhead = 365 * 24 * time.Hour
+)
+
+type SchedulerInterface interface {
+	Schedule(ctx context.Context, userUuid uuid.UUID, from, to, now time.Time) ([]model.ScheduledWorkout, error)
+	Resolve(ctx context.Context, workout *model.ScheduledWorkout, now time.Time) error
+	Calendar(ctx context.Context, userUuid uuid.UUID, view model.CalendarView, date, now time.Time) (*model.Calendar, error)
+	CreateFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error)
+	WriteFeed(ctx context.Context, token string, now time.Time, w io.Writer) error
+}
+
+// Scheduler tells which scheduled workouts users did and lays them out on
+// calendars and feeds.
+type Scheduler struct {
+	dao dao.CalendarDaoInterface
+}
+
+// Ensure Scheduler implements SchedulerInterface
+var _ SchedulerInterface = (*Scheduler)(nil)
+
+// NewScheduler creates a new instance of Scheduler.
+func NewScheduler(dao dao.CalendarDaoInterface) *Scheduler {
+	return &Scheduler{dao: dao}
+}
+
+// Schedule lists the workouts a user scheduled between from and to with
+// their status at now.
+func (s *Scheduler) Schedule(ctx context.Context, userUuid uuid.UUID, from, to, now time.Time) ([]model.ScheduledWorkout, error) {
+	workouts, _, err := s.schedule(ctx, userUuid, from, to, now)
+	return workouts, err
+}
+
+// Resolve sets the status of a single workout, matched against the
+// sessions and the other workouts around it.
+func (s *Scheduler) Resolve(ctx context.Context, workout *model.ScheduledWorkout, now time.Time) error {
+	from, to := workout.ScheduledAt.Add(-2*MatchWindow), workout.ScheduledAt.Add(2*MatchWindow)
+	workouts, _, err := s.schedule(ctx, workout.UserUuid, from, to, now)
+	if err != nil {
+		return err
+	}
+	for _, w := range workouts {
+		if w.ScheduleUuid == workout.ScheduleUuid {
+			workout.Status, workout.SessionUuid = w.Status, w.SessionUuid
+			return nil
+		}
+	}
+	Resolve([]model.ScheduledWorkout{*workout}, nil, now)
+	return nil
+}
+
+// Calendar lays out the month or week around date in the time zone of date.
+func (s *Scheduler) Calendar(ctx context.Context, userUuid uuid.UUID, view model.CalendarView, date, now time.Time) (*model.Calendar, error) {
+	from, to := Span(view, date)
+	workouts, sessions, err := s.schedule(ctx, userUuid, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+	return Layout(userUuid, view, from, to, workouts, sessions), nil
+}
+
+// CreateFeed gives the user a feed under a new secret token. A feed created
+// before stops working.
+func (s *Scheduler) CreateFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error) {
+	token, err := NewToken()
+	if err != nil {
+		return nil, err
+	}
+	feed, err := s.dao.SaveFeed(ctx, userUuid, token)
+	if err != nil {
+		return nil, err
+	}
+	feed.Path = FeedPath(feed.Token)
+	return feed, nil
+}
+
+// WriteFeed writes the iCalendar feed with token, the sessions of the user
+// since FeedPast and the workouts they scheduled up to FeedAhead.
+func (s *Scheduler) WriteFeed(ctx context.Context, token string, now time.Time, w io.Writer) error {
+	userUuid, err := s.dao.GetFeedUser(ctx, token)
+	if err != nil {
+		return err
+	}
+	workouts, sessions, err := s.schedule(ctx, userUuid, now.Add(-FeedPast), now.Add(FeedAhead), now)
+	if err != nil {
+		return err
+	}
+	return WriteICS(w, FeedName, Events(workouts, sessions), now)
+}
+
+// schedule lists the workouts scheduled between from and to, resolved
+// against the sessions logged up to MatchWindow around them, which it also
+// returns.
+func (s *Scheduler) schedule(ctx context.Context, userUuid uuid.UUID, from, to, now time.Time) ([]model.ScheduledWorkout, []model.CalendarSession, error) {
+	workouts, err := s.dao.ListScheduledWorkouts(ctx, userUuid, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	sessions, err := s.dao.ListCalendarSessions(ctx, userUuid, from.Add(-MatchWindow), to.Add(MatchWindow))
+	if err != nil {
+		return nil, nil, err
+	}
+	Resolve(workouts, sessions, now)
+	return workouts, sessions, nil
+}
@@ -0,0 +1,202 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var monday = time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC)
+
+// newTestScheduler returns a scheduler on a memory store, with the DAOs to
+// add scheduled workouts and sessions to it.
+func newTestScheduler(t *testing.T) (*Scheduler, *dao.Daos) {
+	daos := memory.NewDaos(memory.NewStore())
+	return NewScheduler(daos.Calendar), daos
+}
+
+func scheduled(name string, at time.Time) model.ScheduledWorkout {
+	return model.ScheduledWorkout{ScheduleUuid: uuid.New(),
+		ScheduledWorkoutFields: model.ScheduledWorkoutFields{SessionName: name, ScheduledAt: at}}
+}
+
+func session(name string, at time.Time) model.CalendarSession {
+	return model.CalendarSession{SessionUuid: uuid.New(), SessionName: name, StartedAt: at, Source: model.SourceShred}
+}
+
+func TestResolve(t *testing.T) {
+	legs, push, pull, rest := scheduled("Legs", monday), scheduled("Push", monday.AddDate(0, 0, 2)),
+		scheduled("Pull", monday.AddDate(0, 0, 4)), scheduled("Legs", monday.AddDate(0, 0, 7))
+	early, late := session("Legs", monday.Add(-time.Hour)), session("Legs", monday.Add(2*time.Hour))
+	linked := session("Pull", monday.AddDate(0, 0, -10))
+	pull.SessionUuid = &linked.SessionUuid
+
+	workouts := []model.ScheduledWorkout{rest, pull, push, legs}
+	Resolve(workouts, []model.CalendarSession{late, early, linked}, monday.AddDate(0, 0, 5))
+
+	assert.Equal(t, model.SchedulePlanned, workouts[0].Status)
+	assert.Nil(t, workouts[0].SessionUuid)
+	assert.Equal(t, model.ScheduleCompleted, workouts[1].Status)
+	assert.Equal(t, linked.SessionUuid, *workouts[1].SessionUuid)
+	assert.Equal(t, model.ScheduleMissed, workouts[2].Status)
+	assert.Equal(t, model.ScheduleCompleted, workouts[3].Status)
+	assert.Equal(t, early.SessionUuid, *workouts[3].SessionUuid, "the closest session completes the workout")
+}
+
+func TestResolve_SessionCompletesOneWorkout(t *testing.T) {
+	am, pm := scheduled("Run", monday.Add(-8*time.Hour)), scheduled("Lift", monday)
+	workouts := []model.ScheduledWorkout{pm, am}
+	Resolve(workouts, []model.CalendarSession{session("Lift", monday.Add(-4*time.Hour))}, monday.AddDate(0, 0, 1))
+
+	assert.Equal(t, model.ScheduleMissed, workouts[0].Status)
+	assert.Equal(t, model.ScheduleCompleted, workouts[1].Status, "the earlier workout takes the session")
+}
+
+func TestSpan(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		view     model.CalendarView
+		date     time.Time
+		from, to time.Time
+	}{
+		{"month", model.CalendarMonth, time.Date(2025, 2, 14, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"week from sunday", model.CalendarWeek, time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"week from monday", model.CalendarWeek, monday,
+			time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"week over clock change", model.CalendarWeek, time.Date(2025, 3, 27, 12, 0, 0, 0, berlin),
+			time.Date(2025, 3, 24, 0, 0, 0, 0, berlin), time.Date(2025, 3, 31, 0, 0, 0, 0, berlin)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := Span(tt.view, tt.date)
+			assert.True(t, tt.from.Equal(from), "from %s", from)
+			assert.True(t, tt.to.Equal(to), "to %s", to)
+		})
+	}
+}
+
+func TestLayout(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	from, to := Span(model.CalendarWeek, monday.In(tokyo))
+	legs := scheduled("Legs", monday)
+	// late on Sunday in UTC is Monday in Tokyo
+	sunday := session("Run", time.Date(2025, 3, 2, 20, 0, 0, 0, time.UTC))
+
+	cal := Layout(uuid.Nil, model.CalendarWeek, from, to, []model.ScheduledWorkout{legs},
+		[]model.CalendarSession{sunday, session("Before", from.Add(-time.Hour))})
+
+	assert.Len(t, cal.Days, 7)
+	assert.Equal(t, "2025-03-03", cal.Days[0].Date)
+	assert.Equal(t, "2025-03-09", cal.Days[6].Date)
+	assert.Len(t, cal.Days[0].Sessions, 1)
+	assert.Equal(t, "Run", cal.Days[0].Sessions[0].SessionName)
+	assert.Len(t, cal.Days[1].Scheduled, 1, "17:00 UTC on Monday is 02:00 on Tuesday in Tokyo")
+	assert.Equal(t, 2, cal.Days[1].Scheduled[0].ScheduledAt.Hour())
+	assert.Empty(t, cal.Days[2].Scheduled)
+}
+
+func TestEvents(t *testing.T) {
+	legs, push := scheduled("Legs", monday), scheduled("Push", monday.AddDate(0, 0, 2))
+	minutes := 45
+	push.DurationMinutes = &minutes
+	done, extra := session("Legs", monday.Add(30*time.Minute)), session("Run", monday.AddDate(0, 0, 1))
+	seconds := 5400
+	done.DurationSeconds = &seconds
+	workouts := []model.ScheduledWorkout{push, legs}
+	Resolve(workouts, []model.CalendarSession{done, extra}, monday.AddDate(0, 0, 1))
+
+	events := Events(workouts, []model.CalendarSession{done, extra})
+	assert.Len(t, events, 3)
+	assert.Equal(t, "schedule-"+legs.ScheduleUuid.String()+"@shred", events[0].UID)
+	assert.Equal(t, done.StartedAt, events[0].Start, "a completed workout moves to its session")
+	assert.Equal(t, done.StartedAt.Add(90*time.Minute), events[0].End)
+	assert.Equal(t, []string{"completed"}, events[0].Categories)
+	assert.Equal(t, "session-"+extra.SessionUuid.String()+"@shred", events[1].UID)
+	assert.Equal(t, "Push", events[2].Summary)
+	assert.Equal(t, push.ScheduledAt.Add(45*time.Minute), events[2].End)
+	assert.Equal(t, "Planned", events[2].Description)
+}
+
+func TestCalendar(t *testing.T) {
+	scheduler, daos := newTestScheduler(t)
+	ctx := context.Background()
+
+	userUuid := uuid.New()
+	minutes, seconds := 60, 3600
+	for _, fields := range []model.ScheduledWorkoutFields{
+		{SessionName: "Legs", ScheduledAt: monday, DurationMinutes: &minutes},
+		{SessionName: "Push", ScheduledAt: monday.AddDate(0, 0, 2)},
+	} {
+		_, err := daos.Calendar.CreateScheduledWorkout(ctx, userUuid, &fields)
+		require.NoError(t, err)
+	}
+	sessions, err := daos.Workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: monday.Add(time.Hour), DurationSeconds: &seconds}}})
+	require.NoError(t, err)
+
+	cal, err := scheduler.Calendar(ctx, userUuid, model.CalendarWeek, monday, monday.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "UTC", cal.TimeZone)
+	assert.Len(t, cal.Days, 7)
+	assert.Equal(t, model.ScheduleCompleted, cal.Days[0].Scheduled[0].Status)
+	assert.Equal(t, sessions[0].SessionUuid, *cal.Days[0].Scheduled[0].SessionUuid)
+	assert.Len(t, cal.Days[0].Sessions, 1)
+	assert.Equal(t, model.SchedulePlanned, cal.Days[2].Scheduled[0].Status)
+}
+
+func TestWriteFeed(t *testing.T) {
+	scheduler, daos := newTestScheduler(t)
+	ctx := context.Background()
+
+	userUuid := uuid.New()
+	now := monday.Add(-24 * time.Hour)
+	minutes := 60
+	_, err := daos.Calendar.CreateScheduledWorkout(ctx, userUuid, &model.ScheduledWorkoutFields{
+		SessionName: "Legs", ScheduledAt: monday, DurationMinutes: &minutes, Notes: "back squat"})
+	require.NoError(t, err)
+	_, err = daos.Calendar.SaveFeed(ctx, userUuid, "secret")
+	require.NoError(t, err)
+
+	var b strings.Builder
+	assert.NoError(t, scheduler.WriteFeed(ctx, "secret", now, &b))
+	assert.Contains(t, b.String(), "SUMMARY:Legs\r\n")
+	assert.Contains(t, b.String(), "DTSTART:20250303T170000Z\r\n")
+	assert.Contains(t, b.String(), `DESCRIPTION:Planned\nback squat`)
+
+	err = scheduler.WriteFeed(ctx, "unknown", now, &b)
+	assert.True(t, errors.Is(err, dao.ErrNotFound))
+}
+
+func TestCreateFeed(t *testing.T) {
+	scheduler, daos := newTestScheduler(t)
+	ctx := context.Background()
+
+	userUuid := uuid.New()
+	feed, err := scheduler.CreateFeed(ctx, userUuid)
+	assert.NoError(t, err)
+	token, ok := strings.CutSuffix(strings.TrimPrefix(feed.Path, "/calendar/"), ".ics")
+	assert.True(t, ok, feed.Path)
+	assert.Len(t, token, 2*tokenBytes)
+	feedUser, err := daos.Calendar.GetFeedUser(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, feedUser)
+
+	token, err = NewToken()
+	assert.NoError(t, err)
+	assert.Len(t, token, 2*tokenBytes)
+}
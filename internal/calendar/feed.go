@@ -0,0 +1,101 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// FeedName is the name calendar applications show for the feed.
+const FeedName = "shred workouts"
+
+// defaultEventLength is how long a workout or session without a duration
+// shows on a calendar.
+const defaultEventLength = time.Hour
+
+// tokenBytes is the length of a feed token before it is hex encoded.
+const tokenBytes = 24
+
+// NewToken returns a random secret for the URL of a feed.
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// FeedPath is the path the feed with token is served at.
+func FeedPath(token string) string {
+	return "/calendar/" + token + ".ics"
+}
+
+/*
+ * Events turns resolved workouts and the sessions around them into calendar
+ * events. A completed workout moves to the time of its session and keeps
+ * its UID, so calendars update the planned event in place; sessions that
+ * were not scheduled get events of their own.
+ */
+func Events(workouts []model.ScheduledWorkout, sessions []model.CalendarSession) []Event {
+	bySession := make(map[uuid.UUID]model.CalendarSession, len(sessions))
+	for _, s := range sessions {
+		bySession[s.SessionUuid] = s
+	}
+
+	events := []Event{}
+	for _, w := range workouts {
+		length := defaultEventLength
+		if w.DurationMinutes != nil {
+			length = time.Duration(*w.DurationMinutes) * time.Minute
+		}
+		e := Event{UID: "schedule-" + w.ScheduleUuid.String() + "@shred", Start: w.ScheduledAt,
+			Summary: w.SessionName, Categories: []string{string(w.Status)}}
+		if w.SessionUuid != nil {
+			if s, ok := bySession[*w.SessionUuid]; ok {
+				e.Start, length = s.StartedAt, sessionLength(s)
+				delete(bySession, s.SessionUuid)
+			}
+		}
+		e.End = e.Start.Add(length)
+		e.Description = describe(w.Status, w.Notes)
+		events = append(events, e)
+	}
+	for _, s := range sessions {
+		if _, ok := bySession[s.SessionUuid]; !ok {
+			continue
+		}
+		events = append(events, Event{UID: "session-" + s.SessionUuid.String() + "@shred",
+			Start: s.StartedAt, End: s.StartedAt.Add(sessionLength(s)), Summary: s.SessionName,
+			Description: describe(model.ScheduleCompleted, ""),
+			Categories:  []string{string(model.ScheduleCompleted)}})
+	}
+
+	slices.SortFunc(events, func(a, b Event) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+	return events
+}
+
+func sessionLength(s model.CalendarSession) time.Duration {
+	if s.DurationSeconds == nil || *s.DurationSeconds <= 0 {
+		return defaultEventLength
+	}
+	return time.Duration(*s.DurationSeconds) * time.Second
+}
+
+// describe gives the status of an event, followed by its notes.
+func describe(status model.ScheduleStatus, notes string) string {
+	description := strings.ToUpper(string(status[:1])) + string(status[1:])
+	if notes != "" {
+		description += "\n" + notes
+	}
+	return description
+}
@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event is an iCalendar event.
+type Event struct {
+	UID         string
+	Start, End  time.Time
+	Summary     string
+	Description string
+	Categories  []string
+}
+
+// maxLineOctets is the longest line iCalendar allows before it is folded.
+const maxLineOctets = 75
+
+const icsTime = "20060102T150405Z"
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+/*
+ * WriteICS writes events as an iCalendar (RFC 5545) calendar called name.
+ * Times are written in UTC, text is escaped and lines longer than 75 octets
+ * are folded without splitting a character; stamp is when the calendar was
+ * generated.
+ */
+func WriteICS(w io.Writer, name string, events []Event, stamp time.Time) error {
+	var b strings.Builder
+	line := func(property, value string) {
+		b.WriteString(fold(property + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//shred//calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escape(name))
+	for _, e := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp.UTC().Format(icsTime))
+		line("DTSTART", e.Start.UTC().Format(icsTime))
+		line("DTEND", e.End.UTC().Format(icsTime))
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				categories[i] = escape(c)
+			}
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escape(text string) string {
+	return icsEscaper.Replace(text)
+}
+
+// fold breaks a content line into lines of at most maxLineOctets, each
+// continued line starting with a space.
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+	var b strings.Builder
+	octets := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if octets+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			octets = 1
+		}
+		b.WriteRune(r)
+		octets += size
+	}
+	return b.String()
+}
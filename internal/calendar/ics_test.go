@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2025, 3, 3, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+	stamp := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []Event{{UID: "schedule-1@shred", Start: start, End: start.Add(time.Hour), Summary: "Legs; heavy, deep",
+		Description: "Planned\nback squat", Categories: []string{"planned"}}}
+
+	var b strings.Builder
+	assert.NoError(t, WriteICS(&b, FeedName, events, stamp))
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//shred//calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:shred workouts",
+		"BEGIN:VEVENT",
+		"UID:schedule-1@shred",
+		"DTSTAMP:20250301T120000Z",
+		"DTSTART:20250303T170000Z",
+		"DTEND:20250303T180000Z",
+		`SUMMARY:Legs\; heavy\, deep`,
+		`DESCRIPTION:Planned\nback squat`,
+		"CATEGORIES:planned",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+	assert.Equal(t, expected, b.String())
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines []string
+	}{
+		{"short", "SUMMARY:Legs", []string{"SUMMARY:Legs"}},
+		{"exactly 75", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"long", strings.Repeat("a", 160), []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " " + strings.Repeat("a", 11)}},
+		// the two byte ü would straddle the 75th octet
+		{"multibyte", strings.Repeat("a", 74) + "übung", []string{strings.Repeat("a", 74), " übung"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := fold(tt.line)
+			assert.Equal(t, tt.lines, strings.Split(folded, "\r\n"))
+			for _, line := range strings.Split(folded, "\r\n") {
+				assert.LessOrEqual(t, len(line), maxLineOctets)
+			}
+		})
+	}
+}
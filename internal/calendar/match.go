@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// MatchWindow is how far from its scheduled time a logged session completes
+// a scheduled workout. A workout not done by then was missed.
+const MatchWindow = 12 * time.Hour
+
+/*
+ * Resolve sets the status of workouts at now. Workouts linked to a session
+ * are completed. Each other workout, earliest first, takes the closest
+ * session within MatchWindow that no other workout took; a workout without
+ * one is missed once its window has passed and planned before that.
+ */
+func Resolve(workouts []model.ScheduledWorkout, sessions []model.CalendarSession, now time.Time) {
+	taken := map[uuid.UUID]bool{}
+	for _, w := range workouts {
+		if w.SessionUuid != nil {
+			taken[*w.SessionUuid] = true
+		}
+	}
+
+	order := make([]int, len(workouts))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return workouts[a].ScheduledAt.Compare(workouts[b].ScheduledAt)
+	})
+
+	for _, i := range order {
+		w := &workouts[i]
+		if w.SessionUuid != nil {
+			w.Status = model.ScheduleCompleted
+			continue
+		}
+		if s := closest(w.ScheduledAt, sessions, taken); s != nil {
+			taken[s.SessionUuid] = true
+			sessionUuid := s.SessionUuid
+			w.SessionUuid, w.Status = &sessionUuid, model.ScheduleCompleted
+			continue
+		}
+		if now.After(w.ScheduledAt.Add(MatchWindow)) {
+			w.Status = model.ScheduleMissed
+		} else {
+			w.Status = model.SchedulePlanned
+		}
+	}
+}
+
+// closest returns the session not taken yet that started closest to at
+// within MatchWindow, or nil.
+func closest(at time.Time, sessions []model.CalendarSession, taken map[uuid.UUID]bool) *model.CalendarSession {
+	var best *model.CalendarSession
+	var bestGap time.Duration
+	for i := range sessions {
+		s := &sessions[i]
+		gap := s.StartedAt.Sub(at).Abs()
+		if taken[s.SessionUuid] || gap > MatchWindow {
+			continue
+		}
+		if best == nil || gap < bestGap {
+			best, bestGap = s, gap
+		}
+	}
+	return best
+}
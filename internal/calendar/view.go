@@ -0,0 +1,60 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Span returns the start of the month or of the week, starting on Monday,
+// that date falls in and the start of the next one, in the time zone of
+// date.
+func Span(view model.CalendarView, date time.Time) (time.Time, time.Time) {
+	y, m, d := date.Date()
+	if view == model.CalendarWeek {
+		day := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+		from := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return from, from.AddDate(0, 0, 7)
+	}
+	from := time.Date(y, m, 1, 0, 0, 0, 0, date.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+/*
+ * Layout puts the workouts and sessions between from and to on the days of
+ * the time zone of from, with their times in that zone. Every day is
+ * listed, also days without workouts.
+ */
+func Layout(userUuid uuid.UUID, view model.CalendarView, from, to time.Time,
+	workouts []model.ScheduledWorkout, sessions []model.CalendarSession) *model.Calendar {
+	loc := from.Location()
+	cal := &model.Calendar{UserUuid: userUuid, View: view, TimeZone: loc.String(), From: from, To: to,
+		Days: []model.CalendarDay{}}
+
+	days := map[string]*model.CalendarDay{}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		cal.Days = append(cal.Days, model.CalendarDay{Date: d.Format(time.DateOnly),
+			Scheduled: []model.ScheduledWorkout{}, Sessions: []model.CalendarSession{}})
+	}
+	for i := range cal.Days {
+		days[cal.Days[i].Date] = &cal.Days[i]
+	}
+
+	for _, w := range workouts {
+		w.ScheduledAt = w.ScheduledAt.In(loc)
+		if day, ok := days[w.ScheduledAt.Format(time.DateOnly)]; ok {
+			day.Scheduled = append(day.Scheduled, w)
+		}
+	}
+	for _, s := range sessions {
+		if s.StartedAt.Before(from) || !s.StartedAt.Before(to) {
+			continue
+		}
+		s.StartedAt = s.StartedAt.In(loc)
+		if day, ok := days[s.StartedAt.Format(time.DateOnly)]; ok {
+			day.Sessions = append(day.Sessions, s)
+		}
+	}
+	return cal
+}
@@ -0,0 +1,227 @@
+package cardio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Format is a file format activities are recorded in.
+type Format string
+
+const (
+	FormatFIT Format = "fit"
+	FormatTCX Format = "tcx"
+	FormatGPX Format = "gpx"
+)
+
+// ParseFormat converts a format name or file extension into a Format.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), ".")); format {
+	case FormatFIT, FormatTCX, FormatGPX:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected fit, tcx or gpx", name)
+}
+
+// elevationThreshold is the climb or descent in metres that has to build up
+// before it counts, so that barometer and GPS noise does not add up to
+// phantom elevation gain.
+const elevationThreshold = 2.0
+
+const earthRadiusM = 6371008.8
+
+// Point is a single trackpoint. Values the device did not record are nil.
+type Point struct {
+	Time      time.Time
+	Latitude  *float64
+	Longitude *float64
+	AltitudeM *float64
+	DistanceM *float64 // cumulative distance reported by the device
+	HeartRate *int
+}
+
+/*
+ * Activity is an activity read from a device file. Totals are taken from the
+ * file when it has them and otherwise derived from the trackpoints.
+ */
+type Activity struct {
+	Format         Format
+	Sport          string
+	Name           string
+	StartedAt      time.Time
+	TotalSeconds   *float64
+	TotalDistanceM *float64
+	Points         []Point
+}
+
+// Parse reads an activity file.
+func Parse(format Format, r io.Reader) (*Activity, error) {
+	var (
+		activity *Activity
+		err      error
+	)
+	switch format {
+	case FormatFIT:
+		activity, err = parseFIT(r)
+	case FormatTCX:
+		activity, err = parseTCX(r)
+	case FormatGPX:
+		activity, err = parseGPX(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	activity.Format = format
+	sort.SliceStable(activity.Points, func(i, j int) bool {
+		return activity.Points[i].Time.Before(activity.Points[j].Time)
+	})
+	if activity.StartedAt.IsZero() && len(activity.Points) > 0 {
+		activity.StartedAt = activity.Points[0].Time
+	}
+	if activity.StartedAt.IsZero() {
+		return nil, errors.New("activity has no start time")
+	}
+	if activity.Sport == "" {
+		activity.Sport = "generic"
+	}
+	return activity, nil
+}
+
+// Summary derives the duration, distance, pace, elevation and heart rate
+// figures of the activity.
+func (a *Activity) Summary() model.CardioSummary {
+	summary := model.CardioSummary{Sport: a.Sport}
+
+	if a.TotalSeconds != nil {
+		summary.DurationSeconds = int(math.Round(*a.TotalSeconds))
+	} else if len(a.Points) > 0 {
+		summary.DurationSeconds = int(a.Points[len(a.Points)-1].Time.Sub(a.StartedAt).Seconds())
+	}
+
+	if a.TotalDistanceM != nil {
+		summary.DistanceM = *a.TotalDistanceM
+	} else {
+		summary.DistanceM = a.trackDistance()
+	}
+	summary.DistanceM = math.Round(summary.DistanceM*100) / 100
+
+	if summary.DistanceM > 0 && summary.DurationSeconds > 0 {
+		pace := math.Round(float64(summary.DurationSeconds)/(summary.DistanceM/1000)*10) / 10
+		summary.PaceSecondsPerKm = &pace
+	}
+
+	summary.ElevationGainM, summary.ElevationLossM = a.elevation()
+
+	total, count, peak := 0, 0, 0
+	for _, p := range a.Points {
+		if p.HeartRate == nil || *p.HeartRate <= 0 {
+			continue
+		}
+		total += *p.HeartRate
+		count++
+		if *p.HeartRate > peak {
+			peak = *p.HeartRate
+		}
+	}
+	if count > 0 {
+		avg := int(math.Round(float64(total) / float64(count)))
+		summary.AvgHeartRate, summary.MaxHeartRate = &avg, &peak
+	}
+
+	return summary
+}
+
+// HeartRate returns one heart rate sample per second of the activity at
+// most, the first reading of each second winning.
+func (a *Activity) HeartRate() []model.HeartRateSample {
+	samples := []model.HeartRateSample{}
+	last := -1
+	for _, p := range a.Points {
+		if p.HeartRate == nil || *p.HeartRate <= 0 {
+			continue
+		}
+		offset := int(p.Time.Sub(a.StartedAt).Seconds())
+		if offset < 0 || offset == last {
+			continue
+		}
+		samples = append(samples, model.HeartRateSample{OffsetSeconds: offset, HeartRate: *p.HeartRate})
+		last = offset
+	}
+	return samples
+}
+
+// trackDistance uses the cumulative distance of the last point when the
+// device recorded one and otherwise sums the great-circle distances between
+// positions.
+func (a *Activity) trackDistance() float64 {
+	for i := len(a.Points) - 1; i >= 0; i-- {
+		if a.Points[i].DistanceM != nil {
+			return *a.Points[i].DistanceM
+		}
+	}
+
+	total := 0.0
+	var prev *Point
+	for i := range a.Points {
+		p := &a.Points[i]
+		if p.Latitude == nil || p.Longitude == nil {
+			continue
+		}
+		if prev != nil {
+			total += haversine(*prev.Latitude, *prev.Longitude, *p.Latitude, *p.Longitude)
+		}
+		prev = p
+	}
+	return total
+}
+
+// elevation sums climbs and descents with hysteresis: a change has to
+// exceed elevationThreshold to start counting, after which the climb or
+// descent counts in full while it continues in the same direction.
+func (a *Activity) elevation() (*float64, *float64) {
+	var gain, loss, ref float64
+	seen, direction := false, 0
+	for _, p := range a.Points {
+		if p.AltitudeM == nil {
+			continue
+		}
+		alt := *p.AltitudeM
+		if !seen {
+			ref, seen = alt, true
+			continue
+		}
+		switch {
+		case alt >= ref+elevationThreshold || (direction > 0 && alt > ref):
+			gain += alt - ref
+			ref, direction = alt, 1
+		case alt <= ref-elevationThreshold || (direction < 0 && alt < ref):
+			loss += ref - alt
+			ref, direction = alt, -1
+		}
+	}
+	if !seen {
+		return nil, nil
+	}
+	gain, loss = math.Round(gain*10)/10, math.Round(loss*10)/10
+	return &gain, &loss
+}
+
+// haversine is the great-circle distance in metres between two positions.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,77 @@
+package cardio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat(".FIT")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatFIT, format)
+
+	_, err = ParseFormat("kml")
+	assert.EqualError(t, err, `unsupported format "kml", expected fit, tcx or gpx`)
+}
+
+func TestSummary_FromTrack(t *testing.T) {
+	activity, err := Parse(FormatGPX, strings.NewReader(testGPX))
+	assert.NoError(t, err)
+
+	summary := activity.Summary()
+	assert.Equal(t, "running", summary.Sport)
+	assert.Equal(t, 300, summary.DurationSeconds)
+	// 0.009 degrees of latitude is about a kilometre
+	assert.InDelta(t, 1000.75, summary.DistanceM, 0.01)
+	assert.InDelta(t, 299.8, *summary.PaceSecondsPerKm, 0.1)
+	assert.Equal(t, 4.0, *summary.ElevationGainM)
+	assert.Equal(t, 0.0, *summary.ElevationLossM)
+	assert.Equal(t, 130, *summary.AvgHeartRate)
+	assert.Equal(t, 140, *summary.MaxHeartRate)
+}
+
+func TestSummary_FromTotals(t *testing.T) {
+	activity, err := Parse(FormatTCX, strings.NewReader(testTCX))
+	assert.NoError(t, err)
+
+	summary := activity.Summary()
+	assert.Equal(t, 900, summary.DurationSeconds)
+	assert.Equal(t, 7500.0, summary.DistanceM)
+	assert.Equal(t, 120.0, *summary.PaceSecondsPerKm)
+}
+
+func TestSummary_ElevationIgnoresNoise(t *testing.T) {
+	start := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	var points []Point
+	for i, alt := range []float64{100, 101, 100, 101.5, 103, 104, 102.5, 101} {
+		alt := alt
+		points = append(points, Point{Time: start.Add(time.Duration(i) * time.Second), AltitudeM: &alt})
+	}
+	activity := &Activity{StartedAt: start, Points: points}
+
+	summary := activity.Summary()
+	assert.Equal(t, 4.0, *summary.ElevationGainM)
+	assert.Equal(t, 3.0, *summary.ElevationLossM)
+	assert.Nil(t, summary.PaceSecondsPerKm)
+	assert.Nil(t, summary.AvgHeartRate)
+}
+
+func TestHeartRate(t *testing.T) {
+	start := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	hr := func(n int) *int { return &n }
+	activity := &Activity{StartedAt: start, Points: []Point{
+		{Time: start, HeartRate: hr(100)},
+		{Time: start.Add(500 * time.Millisecond), HeartRate: hr(101)},
+		{Time: start.Add(time.Second)},
+		{Time: start.Add(2 * time.Second), HeartRate: hr(105)},
+	}}
+
+	assert.Equal(t, []model.HeartRateSample{
+		{OffsetSeconds: 0, HeartRate: 100},
+		{OffsetSeconds: 2, HeartRate: 105},
+	}, activity.HeartRate())
+}
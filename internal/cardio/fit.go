@@ -0,0 +1,332 @@
+package cardio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FIT is Garmin's binary activity format. Only the record and session
+// messages are decoded; everything else is skipped using its definition.
+// See the FIT protocol description in the FIT SDK for the layout.
+
+const (
+	fitMesgSession = 18
+	fitMesgRecord  = 20
+
+	fitFieldTimestamp = 253
+
+	// fields of the record message
+	fitRecordLatitude         = 0
+	fitRecordLongitude        = 1
+	fitRecordAltitude         = 2
+	fitRecordHeartRate        = 3
+	fitRecordDistance         = 5
+	fitRecordEnhancedAltitude = 78
+
+	// fields of the session message
+	fitSessionStartTime        = 2
+	fitSessionSport            = 5
+	fitSessionTotalElapsedTime = 7
+	fitSessionTotalTimerTime   = 8
+	fitSessionTotalDistance    = 9
+)
+
+// fitEpoch is the zero of FIT timestamps, 1989-12-31T00:00:00Z.
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// semicircleDegrees converts FIT semicircles into degrees.
+const semicircleDegrees = 180.0 / (1 << 31)
+
+var fitSports = map[uint64]string{
+	0: "generic", 1: "running", 2: "cycling", 4: "fitness_equipment", 5: "swimming",
+	10: "training", 11: "walking", 15: "rowing", 17: "hiking",
+}
+
+type fitField struct {
+	num, size, baseType byte
+}
+
+type fitDefinition struct {
+	global    uint16
+	order     binary.ByteOrder
+	fields    []fitField
+	devFields int // total size of developer fields, which are skipped
+}
+
+// fitMessage maps field numbers to raw values. Invalid values are left out.
+type fitMessage map[byte]uint64
+
+func parseFIT(r io.Reader) (*Activity, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, errors.New("fit file is too short")
+	}
+
+	headerSize := int(data[0])
+	if (headerSize != 12 && headerSize != 14) || !bytes.Equal(data[8:12], []byte(".FIT")) {
+		return nil, errors.New("not a fit file")
+	}
+	dataSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	end := headerSize + dataSize
+	if len(data) < end+2 {
+		return nil, errors.New("fit file is truncated")
+	}
+	if fitCRC(data[:end]) != binary.LittleEndian.Uint16(data[end:end+2]) {
+		return nil, errors.New("fit file checksum does not match")
+	}
+
+	activity := &Activity{}
+	err = decodeFIT(data[headerSize:end], func(global uint16, msg fitMessage) {
+		switch global {
+		case fitMesgRecord:
+			if _, ok := msg[fitFieldTimestamp]; ok {
+				activity.Points = append(activity.Points, fitPoint(msg))
+			}
+		case fitMesgSession:
+			// only the first session of multisport files is used
+			if activity.StartedAt.IsZero() {
+				fitSession(activity, msg)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// decodeFIT walks the data records and calls handle for each data message.
+func decodeFIT(data []byte, handle func(global uint16, msg fitMessage)) error {
+	definitions := map[byte]*fitDefinition{}
+	var lastTimestamp uint32
+	pos := 0
+	need := func(n int) error {
+		if pos+n > len(data) {
+			return errors.New("fit record is truncated")
+		}
+		return nil
+	}
+
+	for pos < len(data) {
+		header := data[pos]
+		pos++
+
+		if header&0x80 == 0 && header&0x40 != 0 {
+			def, n, err := readFITDefinition(data[pos:], header&0x20 != 0)
+			if err != nil {
+				return err
+			}
+			definitions[header&0x0F] = def
+			pos += n
+			continue
+		}
+
+		local := header & 0x0F
+		var timeOffset *uint32
+		if header&0x80 != 0 {
+			// compressed timestamp header
+			local = (header >> 5) & 0x03
+			offset := uint32(header & 0x1F)
+			timeOffset = &offset
+		}
+		def, ok := definitions[local]
+		if !ok {
+			return fmt.Errorf("fit data message for undefined local message %d", local)
+		}
+
+		msg := fitMessage{}
+		for _, field := range def.fields {
+			if err := need(int(field.size)); err != nil {
+				return err
+			}
+			if value, ok := fitValue(data[pos:pos+int(field.size)], field.baseType, def.order); ok {
+				msg[field.num] = value
+			}
+			pos += int(field.size)
+		}
+		if err := need(def.devFields); err != nil {
+			return err
+		}
+		pos += def.devFields
+
+		if ts, ok := msg[fitFieldTimestamp]; ok {
+			lastTimestamp = uint32(ts)
+		} else if timeOffset != nil {
+			ts := lastTimestamp&^0x1F + *timeOffset
+			if *timeOffset < lastTimestamp&0x1F {
+				ts += 0x20
+			}
+			lastTimestamp = ts
+			msg[fitFieldTimestamp] = uint64(ts)
+		}
+
+		handle(def.global, msg)
+	}
+	return nil
+}
+
+// readFITDefinition reads a definition message following its record header
+// and returns it with the number of bytes it took.
+func readFITDefinition(data []byte, developer bool) (*fitDefinition, int, error) {
+	if len(data) < 5 {
+		return nil, 0, errors.New("fit definition is truncated")
+	}
+	def := &fitDefinition{order: binary.LittleEndian}
+	if data[1] == 1 {
+		def.order = binary.BigEndian
+	}
+	def.global = def.order.Uint16(data[2:4])
+
+	count := int(data[4])
+	pos := 5
+	if len(data) < pos+3*count {
+		return nil, 0, errors.New("fit definition is truncated")
+	}
+	for i := 0; i < count; i++ {
+		def.fields = append(def.fields, fitField{num: data[pos], size: data[pos+1], baseType: data[pos+2]})
+		pos += 3
+	}
+
+	if developer {
+		if len(data) < pos+1 {
+			return nil, 0, errors.New("fit definition is truncated")
+		}
+		devCount := int(data[pos])
+		pos++
+		if len(data) < pos+3*devCount {
+			return nil, 0, errors.New("fit definition is truncated")
+		}
+		for i := 0; i < devCount; i++ {
+			def.devFields += int(data[pos+1])
+			pos += 3
+		}
+	}
+	return def, pos, nil
+}
+
+// fitValue decodes a single integer value of a field. Arrays, strings and
+// floating point fields are not needed and reported as invalid, as are the
+// values the protocol uses to mark a field as not set.
+func fitValue(raw []byte, baseType byte, order binary.ByteOrder) (uint64, bool) {
+	var value, invalid uint64
+	switch baseType & 0x1F {
+	case 0x00, 0x01, 0x02, 0x0A, 0x0D: // enum, sint8, uint8, uint8z, byte
+		if len(raw) != 1 {
+			return 0, false
+		}
+		value = uint64(raw[0])
+	case 0x03, 0x04, 0x0B: // sint16, uint16, uint16z
+		if len(raw) != 2 {
+			return 0, false
+		}
+		value = uint64(order.Uint16(raw))
+	case 0x05, 0x06, 0x0C: // sint32, uint32, uint32z
+		if len(raw) != 4 {
+			return 0, false
+		}
+		value = uint64(order.Uint32(raw))
+	default:
+		return 0, false
+	}
+
+	switch baseType & 0x1F {
+	case 0x00, 0x02, 0x0D:
+		invalid = 0xFF
+	case 0x01:
+		invalid = 0x7F
+	case 0x03:
+		invalid = 0x7FFF
+	case 0x04:
+		invalid = 0xFFFF
+	case 0x05:
+		invalid = 0x7FFFFFFF
+	case 0x06:
+		invalid = 0xFFFFFFFF
+	case 0x0A, 0x0B, 0x0C:
+		invalid = 0
+	}
+	return value, value != invalid
+}
+
+func fitTime(value uint64) time.Time {
+	return fitEpoch.Add(time.Duration(value) * time.Second)
+}
+
+func fitPoint(msg fitMessage) Point {
+	p := Point{Time: fitTime(msg[fitFieldTimestamp])}
+	if lat, ok := msg[fitRecordLatitude]; ok {
+		if lon, ok := msg[fitRecordLongitude]; ok {
+			latDeg := float64(int32(uint32(lat))) * semicircleDegrees
+			lonDeg := float64(int32(uint32(lon))) * semicircleDegrees
+			p.Latitude, p.Longitude = &latDeg, &lonDeg
+		}
+	}
+	if alt, ok := msg[fitRecordEnhancedAltitude]; ok {
+		m := float64(alt)/5 - 500
+		p.AltitudeM = &m
+	} else if alt, ok := msg[fitRecordAltitude]; ok {
+		m := float64(alt)/5 - 500
+		p.AltitudeM = &m
+	}
+	if dist, ok := msg[fitRecordDistance]; ok {
+		m := float64(dist) / 100
+		p.DistanceM = &m
+	}
+	if hr, ok := msg[fitRecordHeartRate]; ok {
+		bpm := int(hr)
+		p.HeartRate = &bpm
+	}
+	return p
+}
+
+// fitSession takes the totals of the activity from a session message. The
+// timer time, which excludes pauses, is preferred over the elapsed time.
+func fitSession(activity *Activity, msg fitMessage) {
+	if start, ok := msg[fitSessionStartTime]; ok {
+		activity.StartedAt = fitTime(start)
+	}
+	if sport, ok := msg[fitSessionSport]; ok {
+		if name, known := fitSports[sport]; known {
+			activity.Sport = name
+		}
+	}
+	for _, field := range []byte{fitSessionTotalTimerTime, fitSessionTotalElapsedTime} {
+		if ms, ok := msg[field]; ok {
+			seconds := float64(ms) / 1000
+			activity.TotalSeconds = &seconds
+			break
+		}
+	}
+	if cm, ok := msg[fitSessionTotalDistance]; ok {
+		m := float64(cm) / 100
+		activity.TotalDistanceM = &m
+	}
+}
+
+var fitCRCTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCRC is the CRC-16 the FIT protocol appends to files.
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[b&0xF]
+
+		tmp = fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[(b>>4)&0xF]
+	}
+	return crc
+}
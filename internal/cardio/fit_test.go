@@ -0,0 +1,128 @@
+package cardio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fitBuilder writes minimal FIT files for the tests.
+type fitBuilder struct {
+	records bytes.Buffer
+}
+
+func (b *fitBuilder) define(local byte, global uint16, fields ...fitField) {
+	b.records.WriteByte(0x40 | local)
+	b.records.Write([]byte{0, 0})
+	binary.Write(&b.records, binary.LittleEndian, global)
+	b.records.WriteByte(byte(len(fields)))
+	for _, f := range fields {
+		b.records.Write([]byte{f.num, f.size, f.baseType})
+	}
+}
+
+func (b *fitBuilder) data(header byte, values ...any) {
+	b.records.WriteByte(header)
+	for _, v := range values {
+		binary.Write(&b.records, binary.LittleEndian, v)
+	}
+}
+
+func (b *fitBuilder) bytes() []byte {
+	var file bytes.Buffer
+	header := []byte{14, 0x20, 0, 0, 0, 0, 0, 0, '.', 'F', 'I', 'T', 0, 0}
+	binary.LittleEndian.PutUint32(header[4:8], uint32(b.records.Len()))
+	file.Write(header)
+	file.Write(b.records.Bytes())
+	binary.Write(&file, binary.LittleEndian, fitCRC(file.Bytes()))
+	return file.Bytes()
+}
+
+func fitTimestamp(t time.Time) uint32 {
+	return uint32(t.Sub(fitEpoch).Seconds())
+}
+
+func buildFIT(start time.Time) []byte {
+	b := &fitBuilder{}
+	b.define(0, fitMesgRecord,
+		fitField{fitFieldTimestamp, 4, 0x86},
+		fitField{fitRecordLatitude, 4, 0x85},
+		fitField{fitRecordLongitude, 4, 0x85},
+		fitField{fitRecordAltitude, 2, 0x84},
+		fitField{fitRecordHeartRate, 1, 0x02},
+		fitField{fitRecordDistance, 4, 0x86},
+	)
+	// 52.2297N 21.0122E in semicircles, altitude 100 m
+	latDeg, lonDeg := 52.2297, 21.0122
+	lat, lon := int32(latDeg/semicircleDegrees), int32(lonDeg/semicircleDegrees)
+	b.data(0x00, fitTimestamp(start), lat, lon, uint16(3000), uint8(120), uint32(0))
+	b.data(0x00, fitTimestamp(start.Add(30*time.Second)), lat, lon, uint16(3015), uint8(0xFF), uint32(10000))
+
+	// a record with a compressed timestamp header, 5 seconds later
+	b.define(1, fitMesgRecord, fitField{fitRecordHeartRate, 1, 0x02}, fitField{fitRecordDistance, 4, 0x86})
+	offset := byte((fitTimestamp(start.Add(35 * time.Second))) & 0x1F)
+	b.data(0x80|1<<5|offset, uint8(150), uint32(11000))
+
+	b.define(2, fitMesgSession,
+		fitField{fitFieldTimestamp, 4, 0x86},
+		fitField{fitSessionStartTime, 4, 0x86},
+		fitField{fitSessionSport, 1, 0x00},
+		fitField{fitSessionTotalElapsedTime, 4, 0x86},
+		fitField{fitSessionTotalTimerTime, 4, 0x86},
+		fitField{fitSessionTotalDistance, 4, 0x86},
+	)
+	b.data(0x02, fitTimestamp(start.Add(35*time.Second)), fitTimestamp(start), uint8(1), uint32(40000), uint32(35000), uint32(11000))
+	return b.bytes()
+}
+
+func TestParseFIT(t *testing.T) {
+	start := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+
+	activity, err := Parse(FormatFIT, bytes.NewReader(buildFIT(start)))
+	assert.NoError(t, err)
+	assert.Equal(t, FormatFIT, activity.Format)
+	assert.Equal(t, "running", activity.Sport)
+	assert.Equal(t, start, activity.StartedAt)
+	assert.Equal(t, 35.0, *activity.TotalSeconds)
+	assert.Equal(t, 110.0, *activity.TotalDistanceM)
+	assert.Len(t, activity.Points, 3)
+
+	first := activity.Points[0]
+	assert.InDelta(t, 52.2297, *first.Latitude, 1e-6)
+	assert.InDelta(t, 21.0122, *first.Longitude, 1e-6)
+	assert.Equal(t, 100.0, *first.AltitudeM)
+	assert.Equal(t, 120, *first.HeartRate)
+	assert.Nil(t, activity.Points[1].HeartRate, "0xFF marks an invalid heart rate")
+	assert.Equal(t, start.Add(35*time.Second), activity.Points[2].Time)
+	assert.Equal(t, 150, *activity.Points[2].HeartRate)
+}
+
+func TestParseFIT_Invalid(t *testing.T) {
+	data := buildFIT(time.Now())
+
+	_, err := Parse(FormatFIT, strings.NewReader("<gpx/>"))
+	assert.EqualError(t, err, "fit file is too short")
+
+	corrupt := append([]byte{}, data...)
+	corrupt[20] ^= 0xFF
+	_, err = Parse(FormatFIT, bytes.NewReader(corrupt))
+	assert.EqualError(t, err, "fit file checksum does not match")
+
+	_, err = Parse(FormatFIT, bytes.NewReader(data[:len(data)-4]))
+	assert.EqualError(t, err, "fit file is truncated")
+
+	notFIT := append([]byte{}, data...)
+	copy(notFIT[8:12], "XXXX")
+	_, err = Parse(FormatFIT, bytes.NewReader(notFIT))
+	assert.EqualError(t, err, "not a fit file")
+}
+
+func TestFitCRC(t *testing.T) {
+	// the CRC of a file including its CRC is zero
+	data := buildFIT(time.Now())
+	assert.Equal(t, uint16(0), fitCRC(data))
+}
@@ -0,0 +1,79 @@
+package cardio
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// gpxFile is the part of a GPX 1.1 track that is read. Heart rate comes
+// from the Garmin TrackPointExtension most devices write.
+type gpxFile struct {
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Latitude  float64    `xml:"lat,attr"`
+				Longitude float64    `xml:"lon,attr"`
+				Elevation *float64   `xml:"ele"`
+				Time      *time.Time `xml:"time"`
+				HeartRate *int       `xml:"extensions>TrackPointExtension>hr"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// parseGPX reads the first track of a GPX file. GPX has no totals, so the
+// distance is measured along the track.
+func parseGPX(r io.Reader) (*Activity, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	if len(file.Tracks) == 0 {
+		return nil, errors.New("gpx file has no track")
+	}
+
+	trk := file.Tracks[0]
+	activity := &Activity{Name: strings.TrimSpace(trk.Name), Sport: normalizeSport(trk.Type)}
+	for _, seg := range trk.Segments {
+		for _, pt := range seg.Points {
+			if pt.Time == nil {
+				continue
+			}
+			lat, lon := pt.Latitude, pt.Longitude
+			activity.Points = append(activity.Points, Point{
+				Time:      *pt.Time,
+				Latitude:  &lat,
+				Longitude: &lon,
+				AltitudeM: pt.Elevation,
+				HeartRate: pt.HeartRate,
+			})
+		}
+	}
+	if len(activity.Points) == 0 {
+		return nil, errors.New("gpx track has no timed points")
+	}
+
+	return activity, nil
+}
+
+// normalizeSport maps the sport names used by the formats onto one set.
+func normalizeSport(sport string) string {
+	switch sport = strings.ToLower(strings.TrimSpace(sport)); sport {
+	case "biking", "cycling", "ride":
+		return "cycling"
+	case "running", "run":
+		return "running"
+	case "walking", "walk":
+		return "walking"
+	case "swimming", "swim":
+		return "swimming"
+	case "other":
+		return "generic"
+	}
+	return sport
+}
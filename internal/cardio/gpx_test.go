@@ -0,0 +1,50 @@
+package cardio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1"
+     xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <metadata><time>2025-03-01T06:59:00Z</time></metadata>
+  <trk>
+    <name>Morning Run</name>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="52.0000" lon="21.0000">
+        <ele>100</ele><time>2025-03-01T07:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="52.0090" lon="21.0000">
+        <ele>104</ele><time>2025-03-01T07:05:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="52.0100" lon="21.0000"><ele>104</ele></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestParseGPX(t *testing.T) {
+	activity, err := Parse(FormatGPX, strings.NewReader(testGPX))
+	assert.NoError(t, err)
+	assert.Equal(t, "Morning Run", activity.Name)
+	assert.Equal(t, "running", activity.Sport)
+	assert.Equal(t, time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC), activity.StartedAt)
+	assert.Nil(t, activity.TotalSeconds)
+	assert.Len(t, activity.Points, 2, "points without a time are skipped")
+	assert.Equal(t, 140, *activity.Points[1].HeartRate)
+	assert.Equal(t, 104.0, *activity.Points[1].AltitudeM)
+}
+
+func TestParseGPX_Invalid(t *testing.T) {
+	_, err := Parse(FormatGPX, strings.NewReader(`<gpx></gpx>`))
+	assert.EqualError(t, err, "gpx file has no track")
+
+	_, err = Parse(FormatGPX, strings.NewReader(`<gpx><trk><trkseg><trkpt lat="1" lon="2"/></trkseg></trk></gpx>`))
+	assert.EqualError(t, err, "gpx track has no timed points")
+}
@@ -0,0 +1,108 @@
+package cardio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+var (
+	// ErrInvalidExercise is returned when the exercise to log the activity
+	// against does not exist or is not in the cardio category.
+	ErrInvalidExercise = errors.New("invalid exercise")
+	// ErrDuplicate is returned when the user already has an activity that
+	// started at the same time.
+	ErrDuplicate = errors.New("activity already imported")
+	// ErrEmpty is returned for recordings without duration and distance.
+	ErrEmpty = errors.New("activity has no duration or distance")
+)
+
+type ImportOptions struct {
+	UserUuid     uuid.UUID
+	ExerciseUuid uuid.UUID
+	// SessionName overrides the name taken from the file or the sport.
+	SessionName string
+}
+
+type ImporterInterface interface {
+	Import(ctx context.Context, activity *Activity, opts ImportOptions) (*model.CardioActivity, error)
+}
+
+// Importer stores parsed activities as cardio workout sessions.
+type Importer struct {
+	dao dao.CardioDaoInterface
+}
+
+// Ensure Importer implements ImporterInterface
+var _ ImporterInterface = (*Importer)(nil)
+
+// NewImporter creates a new instance of Importer.
+func NewImporter(dao dao.CardioDaoInterface) *Importer {
+	return &Importer{dao: dao}
+}
+
+// Import checks that the exercise is a cardio exercise and that the activity
+// was not imported before, then stores it with its summary and heart rate.
+func (imp *Importer) Import(ctx context.Context, activity *Activity, opts ImportOptions) (*model.CardioActivity, error) {
+	if opts.UserUuid == uuid.Nil {
+		return nil, errors.New("user is required")
+	}
+
+	category, err := imp.dao.GetExerciseCategory(ctx, opts.ExerciseUuid)
+	if err != nil {
+		if errors.Is(err, dao.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExercise, err)
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(category, model.CategoryCardio) {
+		return nil, fmt.Errorf("%w: exercise %s is in category %s, not %s", ErrInvalidExercise, opts.ExerciseUuid, category, model.CategoryCardio)
+	}
+
+	summary := activity.Summary()
+	if summary.DurationSeconds <= 0 && summary.DistanceM <= 0 {
+		return nil, ErrEmpty
+	}
+
+	existing, err := imp.dao.FindActivityByStart(ctx, opts.UserUuid, activity.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w as session %s", ErrDuplicate, existing)
+	}
+
+	cardio := &model.CardioActivity{
+		UserUuid:      opts.UserUuid,
+		ExerciseUuid:  opts.ExerciseUuid,
+		SessionName:   sessionName(opts.SessionName, activity),
+		StartedAt:     activity.StartedAt.UTC(),
+		Source:        string(activity.Format),
+		CardioSummary: summary,
+		HeartRate:     activity.HeartRate(),
+	}
+	if err := imp.dao.CreateActivity(ctx, cardio); err != nil {
+		return nil, err
+	}
+	return cardio, nil
+}
+
+// sessionName prefers the given name, then the name in the file and falls
+// back to the sport, e.g. "Running".
+func sessionName(name string, activity *Activity) string {
+	for _, candidate := range []string{name, activity.Name} {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			return candidate
+		}
+	}
+	sport := strings.ReplaceAll(activity.Sport, "_", " ")
+	if sport == "" {
+		return "Workout"
+	}
+	return strings.ToUpper(sport[:1]) + sport[1:]
+}
@@ -0,0 +1,95 @@
+package cardio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newTestImporter returns an importer on a memory store holding a CARDIO
+ * run and a STRENGTH squat, with the DAOs and the two exercises' uuids.
+ */
+func newTestImporter(t *testing.T) (imp *Importer, daos *dao.Daos, run, squat uuid.UUID) {
+	daos = memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	exercises := map[string]uuid.UUID{}
+	for _, category := range []string{"CARDIO", "STRENGTH"} {
+		_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+			CategoryFields: model.CategoryFields{CategoryCode: category, CategoryName: category}})
+		require.NoError(t, err)
+		ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+			ExerciseFields: model.ExerciseFields{ExerciseName: category, CategoryCode: category}})
+		require.NoError(t, err)
+		exercises[category] = ex.ExerciseUuid
+	}
+
+	return NewImporter(daos.Cardio), daos, exercises["CARDIO"], exercises["STRENGTH"]
+}
+
+func parseTestGPX(t *testing.T) *Activity {
+	activity, err := Parse(FormatGPX, strings.NewReader(testGPX))
+	assert.NoError(t, err)
+	return activity
+}
+
+func TestImport(t *testing.T) {
+	importer, daos, run, _ := newTestImporter(t)
+	userUuid := uuid.New()
+	startedAt := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+
+	created, err := importer.Import(context.Background(), parseTestGPX(t), ImportOptions{UserUuid: userUuid, ExerciseUuid: run})
+	assert.NoError(t, err)
+	assert.Equal(t, "gpx", created.Source)
+	assert.Len(t, created.HeartRate, 2)
+
+	stored, err := daos.Cardio.GetActivity(context.Background(), created.SessionUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, stored.UserUuid)
+	assert.Equal(t, run, stored.ExerciseUuid)
+	assert.True(t, startedAt.Equal(stored.StartedAt))
+	assert.Equal(t, "Morning Run", stored.SessionName)
+	assert.Len(t, stored.HeartRate, 2)
+}
+
+func TestImport_NotCardio(t *testing.T) {
+	importer, _, _, squat := newTestImporter(t)
+
+	_, err := importer.Import(context.Background(), parseTestGPX(t), ImportOptions{UserUuid: uuid.New(), ExerciseUuid: squat})
+	assert.ErrorIs(t, err, ErrInvalidExercise)
+	assert.EqualError(t, err, "invalid exercise: exercise "+squat.String()+" is in category STRENGTH, not CARDIO")
+}
+
+func TestImport_UnknownExercise(t *testing.T) {
+	importer, _, _, _ := newTestImporter(t)
+	exUuid := uuid.New()
+
+	_, err := importer.Import(context.Background(), parseTestGPX(t), ImportOptions{UserUuid: uuid.New(), ExerciseUuid: exUuid})
+	assert.ErrorIs(t, err, ErrInvalidExercise)
+	assert.EqualError(t, err, "invalid exercise: exercise with uuid "+exUuid.String()+" not found")
+}
+
+func TestImport_Duplicate(t *testing.T) {
+	importer, _, run, _ := newTestImporter(t)
+	opts := ImportOptions{UserUuid: uuid.New(), ExerciseUuid: run}
+
+	_, err := importer.Import(context.Background(), parseTestGPX(t), opts)
+	require.NoError(t, err)
+
+	_, err = importer.Import(context.Background(), parseTestGPX(t), opts)
+	assert.ErrorIs(t, err, ErrDuplicate)
+}
+
+func TestSessionName(t *testing.T) {
+	assert.Equal(t, "Commute", sessionName(" Commute ", &Activity{Name: "Morning Ride"}))
+	assert.Equal(t, "Morning Ride", sessionName("", &Activity{Name: "Morning Ride"}))
+	assert.Equal(t, "Fitness equipment", sessionName("", &Activity{Sport: "fitness_equipment"}))
+}
@@ -0,0 +1,77 @@
+package cardio
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+// tcxFile is the part of a Garmin Training Center database that is read.
+type tcxFile struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Id    string `xml:"Id"`
+		Laps  []struct {
+			TotalTimeSeconds *float64 `xml:"TotalTimeSeconds"`
+			DistanceMeters   *float64 `xml:"DistanceMeters"`
+			Trackpoints      []struct {
+				Time           time.Time `xml:"Time"`
+				LatitudeDeg    *float64  `xml:"Position>LatitudeDegrees"`
+				LongitudeDeg   *float64  `xml:"Position>LongitudeDegrees"`
+				AltitudeMeters *float64  `xml:"AltitudeMeters"`
+				DistanceMeters *float64  `xml:"DistanceMeters"`
+				HeartRate      *int      `xml:"HeartRateBpm>Value"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+// parseTCX reads the first activity of a TCX file. Lap totals are summed
+// into the activity totals.
+func parseTCX(r io.Reader) (*Activity, error) {
+	var file tcxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	if len(file.Activities) == 0 {
+		return nil, errors.New("tcx file has no activity")
+	}
+
+	tcx := file.Activities[0]
+	activity := &Activity{Sport: normalizeSport(tcx.Sport)}
+	if id, err := time.Parse(time.RFC3339, tcx.Id); err == nil {
+		activity.StartedAt = id
+	}
+
+	var seconds, distance float64
+	hasSeconds, hasDistance := false, false
+	for _, lap := range tcx.Laps {
+		if lap.TotalTimeSeconds != nil {
+			seconds += *lap.TotalTimeSeconds
+			hasSeconds = true
+		}
+		if lap.DistanceMeters != nil {
+			distance += *lap.DistanceMeters
+			hasDistance = true
+		}
+		for _, tp := range lap.Trackpoints {
+			activity.Points = append(activity.Points, Point{
+				Time:      tp.Time,
+				Latitude:  tp.LatitudeDeg,
+				Longitude: tp.LongitudeDeg,
+				AltitudeM: tp.AltitudeMeters,
+				DistanceM: tp.DistanceMeters,
+				HeartRate: tp.HeartRate,
+			})
+		}
+	}
+	if hasSeconds {
+		activity.TotalSeconds = &seconds
+	}
+	if hasDistance {
+		activity.TotalDistanceM = &distance
+	}
+
+	return activity, nil
+}
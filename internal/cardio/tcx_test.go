@@ -0,0 +1,62 @@
+package cardio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+  <Activities>
+    <Activity Sport="Biking">
+      <Id>2025-03-01T07:00:00Z</Id>
+      <Lap StartTime="2025-03-01T07:00:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>5000</DistanceMeters>
+        <Track>
+          <Trackpoint>
+            <Time>2025-03-01T07:00:00Z</Time>
+            <Position><LatitudeDegrees>52.0</LatitudeDegrees><LongitudeDegrees>21.0</LongitudeDegrees></Position>
+            <AltitudeMeters>100</AltitudeMeters>
+            <DistanceMeters>0</DistanceMeters>
+            <HeartRateBpm><Value>110</Value></HeartRateBpm>
+          </Trackpoint>
+          <Trackpoint>
+            <Time>2025-03-01T07:10:00Z</Time>
+            <AltitudeMeters>120</AltitudeMeters>
+            <DistanceMeters>5000</DistanceMeters>
+            <HeartRateBpm><Value>150</Value></HeartRateBpm>
+          </Trackpoint>
+        </Track>
+      </Lap>
+      <Lap StartTime="2025-03-01T07:10:00Z">
+        <TotalTimeSeconds>300</TotalTimeSeconds>
+        <DistanceMeters>2500</DistanceMeters>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+func TestParseTCX(t *testing.T) {
+	activity, err := Parse(FormatTCX, strings.NewReader(testTCX))
+	assert.NoError(t, err)
+	assert.Equal(t, "cycling", activity.Sport)
+	assert.Equal(t, time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC), activity.StartedAt)
+	assert.Equal(t, 900.0, *activity.TotalSeconds)
+	assert.Equal(t, 7500.0, *activity.TotalDistanceM)
+	assert.Len(t, activity.Points, 2)
+	assert.Equal(t, 52.0, *activity.Points[0].Latitude)
+	assert.Nil(t, activity.Points[1].Latitude)
+	assert.Equal(t, 150, *activity.Points[1].HeartRate)
+}
+
+func TestParseTCX_Invalid(t *testing.T) {
+	_, err := Parse(FormatTCX, strings.NewReader(`<TrainingCenterDatabase><Activities/></TrainingCenterDatabase>`))
+	assert.EqualError(t, err, "tcx file has no activity")
+
+	_, err = Parse(FormatTCX, strings.NewReader(`not xml`))
+	assert.Error(t, err)
+}
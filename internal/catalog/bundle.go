@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// BundleVersion is the bundle format written by this version of shred.
+// Readers accept any version up to and including it.
+const BundleVersion = 1
+
+// A bundle is a zip archive with a manifest and one NDJSON file per type.
+const (
+	manifestFile   = "manifest.json"
+	categoriesFile = "categories.ndjson"
+	licensesFile   = "licenses.ndjson"
+	musclesFile    = "muscles.ndjson"
+	apparatusFile  = "apparatus.ndjson"
+	exercisesFile  = "exercises.ndjson"
+)
+
+// maxBundleFileSize bounds each uncompressed file read from a bundle.
+const maxBundleFileSize = 64 * 1024 * 1024
+
+// Bundle is a catalog read from a bundle archive. Exercises are kept as rows
+// so that problems can be reported against their line in exercises.ndjson.
+type Bundle struct {
+	Manifest   model.CatalogManifest
+	References model.CatalogReferences
+	Rows       []Row
+}
+
+// WriteBundle writes the reference types and exercises as a bundle archive.
+func WriteBundle(w io.Writer, refs model.CatalogReferences, exercises []model.CatalogExercise, exportedAt time.Time) error {
+	archive := zip.NewWriter(w)
+
+	manifest := model.CatalogManifest{
+		FormatVersion: BundleVersion,
+		ExportedAt:    exportedAt.UTC(),
+		Categories:    len(refs.Categories),
+		Licenses:      len(refs.Licenses),
+		Muscles:       len(refs.Muscles),
+		Apparatus:     len(refs.Apparatus),
+		Exercises:     len(exercises),
+	}
+	file, err := archive.Create(manifestFile)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+
+	if err := addNDJSONFile(archive, categoriesFile, refs.Categories); err != nil {
+		return err
+	}
+	if err := addNDJSONFile(archive, licensesFile, refs.Licenses); err != nil {
+		return err
+	}
+	if err := addNDJSONFile(archive, musclesFile, refs.Muscles); err != nil {
+		return err
+	}
+	if err := addNDJSONFile(archive, apparatusFile, refs.Apparatus); err != nil {
+		return err
+	}
+	if err := addNDJSONFile(archive, exercisesFile, exercises); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func addNDJSONFile[T any](archive *zip.Writer, name string, items []T) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	return writeNDJSON(file, items)
+}
+
+// ReadBundle reads a bundle archive. The manifest and exercises are required,
+// the reference type files are optional.
+func ReadBundle(r io.ReaderAt, size int64) (*Bundle, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	bundle := &Bundle{}
+	data, err := readBundleFile(files, manifestFile, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &bundle.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFile, err)
+	}
+	if bundle.Manifest.FormatVersion < 1 || bundle.Manifest.FormatVersion > BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Manifest.FormatVersion)
+	}
+
+	if err := readReferences(files, categoriesFile, &bundle.References.Categories, func(c model.CategoryFields) error {
+		return requireFields("categoryCode", c.CategoryCode, "categoryName", c.CategoryName)
+	}); err != nil {
+		return nil, err
+	}
+	if err := readReferences(files, licensesFile, &bundle.References.Licenses, func(l model.LicenseFields) error {
+		return requireFields("licenseShortName", l.LicenseShortName, "licenseFullName", l.LicenseFullName)
+	}); err != nil {
+		return nil, err
+	}
+	if err := readReferences(files, musclesFile, &bundle.References.Muscles, func(m model.MuscleFields) error {
+		return requireFields("muscleCode", m.MuscleCode, "muscleName", m.MuscleName)
+	}); err != nil {
+		return nil, err
+	}
+	if err := readReferences(files, apparatusFile, &bundle.References.Apparatus, func(a model.ApparatusFields) error {
+		return requireFields("apparatusCode", a.ApparatusCode, "apparatusName", a.ApparatusName)
+	}); err != nil {
+		return nil, err
+	}
+
+	data, err = readBundleFile(files, exercisesFile, true)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Rows, err = parseNDJSON(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func readBundleFile(files map[string]*zip.File, name string, required bool) ([]byte, error) {
+	file, ok := files[name]
+	if !ok {
+		if required {
+			return nil, fmt.Errorf("bundle is missing %s", name)
+		}
+		return nil, nil
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxBundleFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBundleFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxBundleFileSize)
+	}
+	return data, nil
+}
+
+// readReferences decodes one reference type file. Unlike exercises,
+// reference types are few and curated, so the first bad line fails the read.
+func readReferences[T any](files map[string]*zip.File, name string, items *[]T, check func(T) error) error {
+	data, err := readBundleFile(files, name, false)
+	if err != nil {
+		return err
+	}
+
+	line := 0
+	for _, raw := range bytes.Split(data, []byte("\n")) {
+		line++
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		var item T
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		if err := check(item); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		*items = append(*items, item)
+	}
+	return nil
+}
+
+// requireFields takes pairs of field names and values and fails on the first
+// empty value.
+func requireFields(pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			return errors.New(pairs[i] + " is required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBundleReferences() model.CatalogReferences {
+	return model.CatalogReferences{
+		Categories: []model.CategoryFields{{CategoryCode: "STRENGTH", CategoryName: "Strength"}},
+		Licenses:   []model.LicenseFields{{LicenseShortName: "CC_BY", LicenseFullName: "Attribution", LicenseUrl: "https://cc.org"}},
+		Muscles:    []model.MuscleFields{{MuscleCode: "QUAD", MuscleName: "Quadriceps", MuscleGroup: "Legs"}},
+		Apparatus:  []model.ApparatusFields{{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}},
+	}
+}
+
+// zipFiles builds an archive from file names and contents.
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := archive.Create(name)
+		assert.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	refs := newBundleReferences()
+	exercises := newExportedExercises()
+	exportedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteBundle(&buf, refs, exercises, exportedAt))
+
+	bundle, err := ReadBundle(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, BundleVersion, bundle.Manifest.FormatVersion)
+	assert.Equal(t, exportedAt, bundle.Manifest.ExportedAt)
+	assert.Equal(t, 2, bundle.Manifest.Exercises)
+	assert.Equal(t, refs, bundle.References)
+	assert.Len(t, bundle.Rows, 2)
+	for i, row := range bundle.Rows {
+		assert.Equal(t, i+1, row.Line)
+		assert.Equal(t, exercises[i], row.Exercise)
+	}
+}
+
+func TestReadBundle_Errors(t *testing.T) {
+	manifest := `{"formatVersion":1}`
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"no manifest", map[string]string{exercisesFile: ""}, "bundle is missing manifest.json"},
+		{"bad manifest", map[string]string{manifestFile: "{", exercisesFile: ""}, "invalid manifest.json: unexpected end of JSON input"},
+		{"future version", map[string]string{manifestFile: `{"formatVersion":99}`, exercisesFile: ""}, "unsupported bundle version 99"},
+		{"no exercises", map[string]string{manifestFile: manifest}, "bundle is missing exercises.ndjson"},
+		{"bad reference", map[string]string{
+			manifestFile:   manifest,
+			exercisesFile:  "",
+			categoriesFile: `{"categoryCode":"STRENGTH","categoryName":"Strength"}` + "\n" + `{"categoryCode":"CARDIO"}`,
+		}, "categories.ndjson line 2: categoryName is required"},
+		{"unknown field", map[string]string{
+			manifestFile:  manifest,
+			exercisesFile: "",
+			musclesFile:   `{"muscleCode":"QUAD","colour":"red"}`,
+		}, `muscles.ndjson line 1: json: unknown field "colour"`},
+	}
+
+	for _, tt := range tests {
+		data := zipFiles(t, tt.files)
+		_, err := ReadBundle(bytes.NewReader(data), int64(len(data)))
+		assert.EqualError(t, err, tt.want, tt.name)
+	}
+
+	_, err := ReadBundle(bytes.NewReader([]byte("not a zip")), 9)
+	assert.EqualError(t, err, "invalid bundle: zip: not a valid zip file")
+}
@@ -0,0 +1,134 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// csvHeader lists the CSV columns, named after the JSON fields of the model.
+// Muscles are written as CODE:role pairs and apparatus as codes, both
+// separated by listSeparator.
+var csvHeader = []string{
+	"exerciseName", "description", "instructions", "cues", "videoUrl",
+	"category", "licenceShortName", "licenceAuthor", "muscles", "apparatus",
+}
+
+const listSeparator = ";"
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv input is empty")
+		}
+		return nil, err
+	}
+	columns, err := csvColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, Row{Line: parseErr.StartLine, Errors: []string{parseErr.Err.Error()}})
+				continue
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, csvRow(line, columns, record))
+	}
+
+	return rows, nil
+}
+
+// csvColumns maps each known column name to its index in the header.
+func csvColumns(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(csvHeader))
+	for _, name := range csvHeader {
+		known[strings.ToLower(name)] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if !known[key] {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		columns[key] = i
+	}
+	if _, ok := columns["exercisename"]; !ok {
+		return nil, errors.New("csv header is missing the exerciseName column")
+	}
+	return columns, nil
+}
+
+func csvRow(line int, columns map[string]int, record []string) Row {
+	get := func(name string) string {
+		idx, ok := columns[strings.ToLower(name)]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	row := Row{Line: line}
+	if len(record) > len(columns) {
+		row.Errors = append(row.Errors, fmt.Sprintf("expected %d fields, got %d", len(columns), len(record)))
+	}
+	row.Exercise = model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{
+			ExerciseName:     get("exerciseName"),
+			Description:      get("description"),
+			Instructions:     get("instructions"),
+			Cues:             get("cues"),
+			VideoUrl:         get("videoUrl"),
+			CategoryCode:     get("category"),
+			LicenseShortName: get("licenceShortName"),
+			LicenseAuthor:    get("licenceAuthor"),
+		},
+		Muscles:   parseMuscles(get("muscles")),
+		Apparatus: splitList(get("apparatus")),
+	}
+	return row
+}
+
+// parseMuscles reads a list of CODE:role pairs. A missing role is left empty
+// so validation can report it against the row.
+func parseMuscles(cell string) []model.ExerciseMuscle {
+	var muscles []model.ExerciseMuscle
+	for _, item := range splitList(cell) {
+		code, role, _ := strings.Cut(item, ":")
+		muscles = append(muscles, model.ExerciseMuscle{
+			MuscleCode: strings.TrimSpace(code),
+			MuscleRole: model.MuscleRole(strings.ToLower(strings.TrimSpace(role))),
+		})
+	}
+	return muscles
+}
+
+func splitList(cell string) []string {
+	var items []string
+	for _, item := range strings.Split(cell, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
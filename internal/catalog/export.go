@@ -0,0 +1,147 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type ExporterInterface interface {
+	Export(ctx context.Context, format Format, w io.Writer) error
+}
+
+// Exporter writes the exercise catalog in any of the supported formats.
+type Exporter struct {
+	catalog    dao.CatalogDaoInterface
+	categories dao.CategoryDaoInterface
+	licenses   dao.LicenseDaoInterface
+	muscles    dao.MuscleDaoInterface
+	apparatus  dao.ApparatusDaoInterface
+	now        func() time.Time
+}
+
+// Ensure Exporter implements ExporterInterface
+var _ ExporterInterface = (*Exporter)(nil)
+
+// NewExporter creates a new instance of Exporter.
+func NewExporter(catalog dao.CatalogDaoInterface, categories dao.CategoryDaoInterface,
+	licenses dao.LicenseDaoInterface, muscles dao.MuscleDaoInterface,
+	apparatus dao.ApparatusDaoInterface) *Exporter {
+	return &Exporter{
+		catalog:    catalog,
+		categories: categories,
+		licenses:   licenses,
+		muscles:    muscles,
+		apparatus:  apparatus,
+		now:        time.Now,
+	}
+}
+
+// Export writes every exercise to w. Bundles additionally contain all
+// reference types so they can be imported into an empty instance.
+func (exp *Exporter) Export(ctx context.Context, format Format, w io.Writer) error {
+	exercises, err := exp.catalog.ListExercises(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, exercises)
+	case FormatNDJSON:
+		return writeNDJSON(w, exercises)
+	case FormatBundle:
+		refs, err := exp.references(ctx)
+		if err != nil {
+			return err
+		}
+		return WriteBundle(w, *refs, exercises, exp.now())
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+func (exp *Exporter) references(ctx context.Context) (*model.CatalogReferences, error) {
+	refs := &model.CatalogReferences{
+		Categories: []model.CategoryFields{},
+		Licenses:   []model.LicenseFields{},
+		Muscles:    []model.MuscleFields{},
+		Apparatus:  []model.ApparatusFields{},
+	}
+
+	cats, err := exp.categories.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cat := range cats {
+		refs.Categories = append(refs.Categories, cat.CategoryFields)
+	}
+
+	lics, err := exp.licenses.GetAllLicenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, lic := range lics {
+		refs.Licenses = append(refs.Licenses, lic.LicenseFields)
+	}
+
+	muss, err := exp.muscles.GetAllMuscles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mus := range muss {
+		refs.Muscles = append(refs.Muscles, mus.MuscleFields)
+	}
+
+	apps, err := exp.apparatus.GetAllApparatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		refs.Apparatus = append(refs.Apparatus, app.ApparatusFields)
+	}
+
+	return refs, nil
+}
+
+// WriteCSV writes exercises with the same columns parseCSV reads.
+func WriteCSV(w io.Writer, exercises []model.CatalogExercise) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, ex := range exercises {
+		muscles := make([]string, len(ex.Muscles))
+		for i, mus := range ex.Muscles {
+			muscles[i] = mus.MuscleCode + ":" + string(mus.MuscleRole)
+		}
+		record := []string{
+			ex.ExerciseName, ex.Description, ex.Instructions, ex.Cues, ex.VideoUrl,
+			ex.CategoryCode, ex.LicenseShortName, ex.LicenseAuthor,
+			strings.Join(muscles, listSeparator), strings.Join(ex.Apparatus, listSeparator),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeNDJSON[T any](w io.Writer, items []T) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
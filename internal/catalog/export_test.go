@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportedExercises() []model.CatalogExercise {
+	return []model.CatalogExercise{
+		{
+			ExerciseFields: model.ExerciseFields{
+				ExerciseName:     "Squat",
+				Description:      "Lower body, \"classic\"",
+				Instructions:     "Stand with feet\nshoulder-width apart",
+				CategoryCode:     "STRENGTH",
+				LicenseShortName: "CC_BY",
+				LicenseAuthor:    "John Doe",
+			},
+			Muscles: []model.ExerciseMuscle{
+				{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary},
+				{MuscleCode: "GLUTE", MuscleRole: model.MuscleRoleSecondary},
+			},
+			Apparatus: []string{"BARBELL", "RACK"},
+		},
+		{
+			ExerciseFields: model.ExerciseFields{ExerciseName: "Plank", CategoryCode: "CORE"},
+		},
+	}
+}
+
+func TestWriteCSV_RoundTrip(t *testing.T) {
+	exercises := newExportedExercises()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, exercises))
+
+	rows, err := Parse(FormatCSV, &buf)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	for i, row := range rows {
+		assert.Empty(t, row.Errors)
+		assert.Equal(t, exercises[i], row.Exercise)
+	}
+}
+
+func TestWriteNDJSON_RoundTrip(t *testing.T) {
+	exercises := newExportedExercises()
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeNDJSON(&buf, exercises))
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+
+	rows, err := Parse(FormatNDJSON, &buf)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	for i, row := range rows {
+		assert.Empty(t, row.Errors)
+		assert.Equal(t, exercises[i], row.Exercise)
+	}
+}
+
+func TestExport(t *testing.T) {
+	daos := newTestDaos(t)
+	squat := createExercise(t, daos, "Squat")
+	assert.NoError(t, daos.Exercises.AddMuscles(context.Background(), squat,
+		[]model.ExerciseMuscle{{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary}}))
+
+	exporter := NewExporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+	exportedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	exporter.now = func() time.Time { return exportedAt }
+
+	var buf bytes.Buffer
+	err := exporter.Export(context.Background(), FormatBundle, &buf)
+	assert.NoError(t, err)
+
+	bundle, err := ReadBundle(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, model.CatalogManifest{
+		FormatVersion: BundleVersion,
+		ExportedAt:    exportedAt,
+		Categories:    1,
+		Licenses:      1,
+		Muscles:       2,
+		Apparatus:     1,
+		Exercises:     1,
+	}, bundle.Manifest)
+	assert.Equal(t, []model.CategoryFields{{CategoryCode: "STRENGTH", CategoryName: "Strength"}}, bundle.References.Categories)
+	if assert.Len(t, bundle.Rows, 1) {
+		assert.Equal(t, "Squat", bundle.Rows[0].Exercise.ExerciseName)
+		assert.Equal(t, []model.ExerciseMuscle{{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary}},
+			bundle.Rows[0].Exercise.Muscles)
+	}
+}
+
+func TestExport_Error(t *testing.T) {
+	daos := newTestDaos(t)
+	exporter := NewExporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	err := exporter.Export(ctx, FormatCSV, &buf)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, buf.Len())
+}
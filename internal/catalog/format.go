@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Format identifies a serialisation of the exercise catalog.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	// FormatBundle is a versioned zip archive holding the reference types as
+	// well as the exercises, see bundle.go.
+	FormatBundle Format = "bundle"
+)
+
+// ParseFormat converts a user supplied format name into a Format.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	case FormatBundle, "zip":
+		return FormatBundle, nil
+	}
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// ContentType returns the media type used when serving the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatBundle:
+		return "application/zip"
+	}
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension, without the dot, for the format.
+func (f Format) Extension() string {
+	if f == FormatBundle {
+		return "zip"
+	}
+	return string(f)
+}
+
+// Row is a parsed import record along with the line it came from and any
+// problems found while parsing it.
+type Row struct {
+	Line     int
+	Exercise model.CatalogExercise
+	Errors   []string
+}
+
+// Parse reads every exercise from r in the given row format. Bundles also
+// carry reference types and are read with ReadBundle instead. Problems with a
+// single record are attached to its Row so the rest of the file can still be
+// reported on; an error is only returned when the input cannot be read at all.
+func Parse(format Format, r io.Reader) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatNDJSON:
+		return parseNDJSON(r)
+	case FormatBundle:
+		return nil, errors.New("bundles must be read with ReadBundle")
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
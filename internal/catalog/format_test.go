@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"csv", FormatCSV, false},
+		{"CSV", FormatCSV, false},
+		{"ndjson", FormatNDJSON, false},
+		{"jsonl", FormatNDJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.Equal(t, tt.wantErr, err != nil, tt.name)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "exerciseName,description,category,licenceShortName,muscles,apparatus\n" +
+		"Squat,\"Lower body,\nmulti line\",strength,cc_by,QUAD:primary; GLUTE:Secondary,BARBELL;RACK\n" +
+		"Push-up,,strength,,CHEST,\n"
+
+	rows, err := Parse(FormatCSV, strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Empty(t, rows[0].Errors)
+	assert.Equal(t, "Squat", rows[0].Exercise.ExerciseName)
+	assert.Equal(t, "Lower body,\nmulti line", rows[0].Exercise.Description)
+	assert.Equal(t, "strength", rows[0].Exercise.CategoryCode)
+	assert.Equal(t, "cc_by", rows[0].Exercise.LicenseShortName)
+	assert.Equal(t, []model.ExerciseMuscle{
+		{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary},
+		{MuscleCode: "GLUTE", MuscleRole: model.MuscleRoleSecondary},
+	}, rows[0].Exercise.Muscles)
+	assert.Equal(t, []string{"BARBELL", "RACK"}, rows[0].Exercise.Apparatus)
+
+	assert.Equal(t, 4, rows[1].Line)
+	assert.Equal(t, []model.ExerciseMuscle{{MuscleCode: "CHEST"}}, rows[1].Exercise.Muscles)
+	assert.Nil(t, rows[1].Exercise.Apparatus)
+}
+
+func TestParseCSV_BadHeader(t *testing.T) {
+	_, err := Parse(FormatCSV, strings.NewReader("exerciseName,colour\nSquat,red\n"))
+	assert.EqualError(t, err, `unknown csv column "colour"`)
+
+	_, err = Parse(FormatCSV, strings.NewReader("description\nLower body\n"))
+	assert.EqualError(t, err, "csv header is missing the exerciseName column")
+
+	_, err = Parse(FormatCSV, strings.NewReader(""))
+	assert.EqualError(t, err, "csv input is empty")
+}
+
+func TestParseCSV_RowErrors(t *testing.T) {
+	input := "exerciseName,category\n" +
+		"Squat,STRENGTH,extra\n" +
+		"Bad \"quote,STRENGTH\n"
+
+	rows, err := Parse(FormatCSV, strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{"expected 2 fields, got 3"}, rows[0].Errors)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.NotEmpty(t, rows[1].Errors)
+}
+
+func TestParseNDJSON(t *testing.T) {
+	input := `{"exerciseName":"Squat","category":"STRENGTH","muscles":[{"muscleCode":"QUAD","muscleRole":"primary"}],"apparatus":["BARBELL"]}
+
+{"exerciseName":"Bench",
+{"exerciseName":"Row","colour":"red"}
+`
+
+	rows, err := Parse(FormatNDJSON, strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Empty(t, rows[0].Errors)
+	assert.Equal(t, "Squat", rows[0].Exercise.ExerciseName)
+	assert.Equal(t, []string{"BARBELL"}, rows[0].Exercise.Apparatus)
+
+	assert.Equal(t, 3, rows[1].Line)
+	assert.NotEmpty(t, rows[1].Errors)
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Equal(t, []string{`json: unknown field "colour"`}, rows[2].Errors)
+}
@@ -0,0 +1,162 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type ImportOptions struct {
+	Mode      model.ImportMode
+	DryRun    bool
+	CreatedBy uuid.UUID
+}
+
+type ImporterInterface interface {
+	Import(ctx context.Context, rows []Row, opts ImportOptions) (*model.ImportReport, error)
+	ImportBundle(ctx context.Context, bundle *Bundle, opts ImportOptions) (*model.ImportReport, error)
+}
+
+// Importer validates parsed catalog rows against the reference data and
+// writes them to the database.
+type Importer struct {
+	catalog    dao.CatalogDaoInterface
+	categories dao.CategoryDaoInterface
+	licenses   dao.LicenseDaoInterface
+	muscles    dao.MuscleDaoInterface
+	apparatus  dao.ApparatusDaoInterface
+}
+
+// Ensure Importer implements ImporterInterface
+var _ ImporterInterface = (*Importer)(nil)
+
+// NewImporter creates a new instance of Importer.
+func NewImporter(catalog dao.CatalogDaoInterface, categories dao.CategoryDaoInterface,
+	licenses dao.LicenseDaoInterface, muscles dao.MuscleDaoInterface,
+	apparatus dao.ApparatusDaoInterface) *Importer {
+	return &Importer{
+		catalog:    catalog,
+		categories: categories,
+		licenses:   licenses,
+		muscles:    muscles,
+		apparatus:  apparatus,
+	}
+}
+
+// Import validates every row and, unless this is a dry run or a row is
+// invalid, writes all of them in one transaction. The returned report
+// describes the outcome of each row; an error is only returned when the
+// database could not be reached or the write failed.
+func (imp *Importer) Import(ctx context.Context, rows []Row, opts ImportOptions) (*model.ImportReport, error) {
+	return imp.importRows(ctx, nil, rows, opts)
+}
+
+// ImportBundle imports the reference types of a bundle along with its
+// exercises, which may refer to them. Reference types that already exist are
+// only changed in upsert mode.
+func (imp *Importer) ImportBundle(ctx context.Context, bundle *Bundle, opts ImportOptions) (*model.ImportReport, error) {
+	return imp.importRows(ctx, &bundle.References, bundle.Rows, opts)
+}
+
+func (imp *Importer) importRows(ctx context.Context, bundleRefs *model.CatalogReferences, rows []Row, opts ImportOptions) (*model.ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = model.ImportModeInsert
+	}
+	if opts.Mode != model.ImportModeInsert && opts.Mode != model.ImportModeUpsert {
+		return nil, errors.New("import mode must be insert or upsert")
+	}
+	if !opts.DryRun && opts.CreatedBy == uuid.Nil {
+		return nil, errors.New("createdBy is required")
+	}
+
+	refs, err := LoadReferences(ctx, imp.categories, imp.licenses, imp.muscles, imp.apparatus)
+	if err != nil {
+		return nil, err
+	}
+	if bundleRefs != nil {
+		refs.Add(bundleRefs)
+	}
+
+	report := &model.ImportReport{
+		DryRun: opts.DryRun,
+		Mode:   opts.Mode,
+		Total:  len(rows),
+		Rows:   make([]model.ImportRowResult, len(rows)),
+	}
+
+	names := make([]string, 0, len(rows))
+	firstLine := map[string]int{}
+	for i := range rows {
+		row := &rows[i]
+		problems := append([]string{}, row.Errors...)
+		if len(row.Errors) == 0 {
+			problems = append(problems, refs.Validate(&row.Exercise)...)
+		}
+
+		key := strings.ToLower(row.Exercise.ExerciseName)
+		if key != "" {
+			if line, ok := firstLine[key]; ok {
+				problems = append(problems, fmt.Sprintf("duplicate of the exercise on row %d", line))
+			} else {
+				firstLine[key] = row.Line
+				names = append(names, row.Exercise.ExerciseName)
+			}
+		}
+
+		report.Rows[i] = model.ImportRowResult{
+			Row:          row.Line,
+			ExerciseName: row.Exercise.ExerciseName,
+			Errors:       problems,
+		}
+	}
+
+	existing, err := imp.catalog.FindExercisesByName(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Rows {
+		result := &report.Rows[i]
+		exUuid, exists := existing[strings.ToLower(result.ExerciseName)]
+		if exists && opts.Mode == model.ImportModeInsert {
+			result.Errors = append(result.Errors, "exercise already exists")
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Action = model.ImportActionInvalid
+			report.Invalid++
+		case exists:
+			result.Action = model.ImportActionUpdate
+			result.ExerciseUuid = &exUuid
+			report.Updated++
+		default:
+			result.Action = model.ImportActionCreate
+			report.Created++
+		}
+	}
+
+	if opts.DryRun || report.Invalid > 0 || (len(rows) == 0 && bundleRefs == nil) {
+		return report, nil
+	}
+
+	entries := make([]model.CatalogExercise, len(rows))
+	for i := range rows {
+		entries[i] = rows[i].Exercise
+	}
+	uuids, err := imp.catalog.ImportCatalog(ctx, bundleRefs, entries, opts.Mode == model.ImportModeUpsert, opts.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	for i := range uuids {
+		report.Rows[i].ExerciseUuid = &uuids[i]
+	}
+	report.Committed = true
+
+	return report, nil
+}
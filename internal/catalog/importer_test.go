@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestImporter returns an importer on the memory store of newTestDaos,
+// with its DAOs.
+func newTestImporter(t *testing.T) (*Importer, *dao.Daos) {
+	daos := newTestDaos(t)
+	return NewImporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus), daos
+}
+
+// createExercise adds an exercise to the store and returns its uuid.
+func createExercise(t *testing.T, daos *dao.Daos, name string) uuid.UUID {
+	ex, err := daos.Exercises.Create(context.Background(), &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: name, CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	return ex.ExerciseUuid
+}
+
+func newRow(line int, name string) Row {
+	return Row{
+		Line: line,
+		Exercise: model.CatalogExercise{
+			ExerciseFields: model.ExerciseFields{ExerciseName: name, CategoryCode: "STRENGTH"},
+			Muscles:        []model.ExerciseMuscle{{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary}},
+			Apparatus:      []string{"BARBELL"},
+		},
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	importer, daos := newTestImporter(t)
+
+	existing := createExercise(t, daos, "Front Squat")
+
+	rows := []Row{newRow(2, "Squat"), newRow(3, "Front Squat")}
+	report, err := importer.Import(context.Background(), rows, ImportOptions{Mode: model.ImportModeUpsert, DryRun: true})
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, model.ImportActionCreate, report.Rows[0].Action)
+	assert.Equal(t, model.ImportActionUpdate, report.Rows[1].Action)
+	assert.Equal(t, existing, *report.Rows[1].ExerciseUuid)
+
+	found, err := daos.Catalog.FindExercisesByName(context.Background(), []string{"squat"})
+	assert.NoError(t, err)
+	assert.Empty(t, found, "a dry run creates nothing")
+}
+
+func TestImport_InvalidRowsAreNotCommitted(t *testing.T) {
+	importer, daos := newTestImporter(t)
+
+	createExercise(t, daos, "Deadlift")
+
+	badCategory := newRow(4, "Lunge")
+	badCategory.Exercise.CategoryCode = "CARDIO"
+	rows := []Row{
+		newRow(2, "Squat"),
+		newRow(3, "squat"),
+		badCategory,
+		newRow(5, "Deadlift"),
+		{Line: 6, Errors: []string{"bare \" in non-quoted-field"}},
+	}
+
+	report, err := importer.Import(context.Background(), rows, ImportOptions{CreatedBy: uuid.New()})
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, model.ImportModeInsert, report.Mode)
+	assert.Equal(t, 4, report.Invalid)
+	assert.Equal(t, model.ImportActionCreate, report.Rows[0].Action)
+	assert.Equal(t, []string{"duplicate of the exercise on row 2"}, report.Rows[1].Errors)
+	assert.Equal(t, []string{"category CARDIO not found"}, report.Rows[2].Errors)
+	assert.Equal(t, []string{"exercise already exists"}, report.Rows[3].Errors)
+	assert.Equal(t, []string{"bare \" in non-quoted-field"}, report.Rows[4].Errors)
+
+	found, err := daos.Catalog.FindExercisesByName(context.Background(), []string{"squat"})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestImport_Commit(t *testing.T) {
+	importer, daos := newTestImporter(t)
+
+	report, err := importer.Import(context.Background(), []Row{newRow(2, "Squat")}, ImportOptions{CreatedBy: uuid.New()})
+	assert.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, 1, report.Created)
+
+	exercises, err := daos.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 1) {
+		assert.Equal(t, "Squat", exercises[0].ExerciseName)
+		assert.Equal(t, newRow(2, "Squat").Exercise.Muscles, exercises[0].Muscles)
+		assert.Equal(t, []string{"BARBELL"}, exercises[0].Apparatus)
+	}
+}
+
+func TestImport_BadOptions(t *testing.T) {
+	importer, _ := newTestImporter(t)
+
+	_, err := importer.Import(context.Background(), nil, ImportOptions{Mode: "merge"})
+	assert.EqualError(t, err, "import mode must be insert or upsert")
+
+	_, err = importer.Import(context.Background(), nil, ImportOptions{})
+	assert.EqualError(t, err, "createdBy is required")
+}
+
+func TestImportBundle(t *testing.T) {
+	daos := memory.NewDaos(memory.NewStore())
+	importer := NewImporter(daos.Catalog, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+
+	createdBy := uuid.New()
+	bundle := &Bundle{
+		Manifest:   model.CatalogManifest{FormatVersion: BundleVersion},
+		References: newBundleReferences(),
+		Rows:       []Row{newRow(1, "Squat")},
+	}
+	report, err := importer.ImportBundle(context.Background(), bundle, ImportOptions{CreatedBy: createdBy})
+	assert.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Zero(t, report.Invalid)
+
+	category, err := daos.Categories.GetCategoryByCode(context.Background(), "STRENGTH")
+	assert.NoError(t, err)
+	assert.Equal(t, createdBy, category.CreatedBy)
+	found, err := daos.Catalog.FindExercisesByName(context.Background(), []string{"squat"})
+	assert.NoError(t, err)
+	assert.Equal(t, *report.Rows[0].ExerciseUuid, found["squat"])
+}
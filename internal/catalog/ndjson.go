@@ -0,0 +1,43 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// maxLineSize bounds a single NDJSON record; long markdown instructions fit
+// comfortably.
+const maxLineSize = 1024 * 1024
+
+func parseNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var rows []Row
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := Row{Line: line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.Exercise); err != nil {
+			row.Exercise = model.CatalogExercise{}
+			row.Errors = append(row.Errors, err.Error())
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
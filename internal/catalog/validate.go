@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Column limits from db/schema.sql, in characters as varchar counts them.
+const (
+	maxNameLength   = 100
+	maxTextLength   = 2500
+	maxUrlLength    = 256
+	maxAuthorLength = 100
+)
+
+// References holds the codes of the reference types an exercise may point
+// at, keyed by their upper-cased code.
+type References struct {
+	Categories map[string]bool
+	Licenses   map[string]bool
+	Muscles    map[string]bool
+	Apparatus  map[string]bool
+}
+
+// LoadReferences reads all reference types from the database.
+func LoadReferences(ctx context.Context,
+	categories dao.CategoryDaoInterface, licenses dao.LicenseDaoInterface,
+	muscles dao.MuscleDaoInterface, apparatus dao.ApparatusDaoInterface) (*References, error) {
+	refs := &References{
+		Categories: map[string]bool{},
+		Licenses:   map[string]bool{},
+		Muscles:    map[string]bool{},
+		Apparatus:  map[string]bool{},
+	}
+
+	cats, err := categories.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cat := range cats {
+		refs.Categories[strings.ToUpper(cat.CategoryCode)] = true
+	}
+
+	lics, err := licenses.GetAllLicenses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, lic := range lics {
+		refs.Licenses[strings.ToUpper(lic.LicenseShortName)] = true
+	}
+
+	muss, err := muscles.GetAllMuscles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mus := range muss {
+		refs.Muscles[strings.ToUpper(mus.MuscleCode)] = true
+	}
+
+	apps, err := apparatus.GetAllApparatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		refs.Apparatus[strings.ToUpper(app.ApparatusCode)] = true
+	}
+
+	return refs, nil
+}
+
+// Add makes the reference types of a bundle available to its exercises.
+func (refs *References) Add(bundle *model.CatalogReferences) {
+	for _, cat := range bundle.Categories {
+		refs.Categories[strings.ToUpper(cat.CategoryCode)] = true
+	}
+	for _, lic := range bundle.Licenses {
+		refs.Licenses[strings.ToUpper(lic.LicenseShortName)] = true
+	}
+	for _, mus := range bundle.Muscles {
+		refs.Muscles[strings.ToUpper(mus.MuscleCode)] = true
+	}
+	for _, app := range bundle.Apparatus {
+		refs.Apparatus[strings.ToUpper(app.ApparatusCode)] = true
+	}
+}
+
+// Validate upper-cases the codes of ex in place and returns every problem
+// found with it. An empty result means the exercise can be written.
+func (refs *References) Validate(ex *model.CatalogExercise) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	ex.ExerciseName = strings.TrimSpace(ex.ExerciseName)
+	if ex.ExerciseName == "" {
+		fail("exerciseName is required")
+	}
+	checkLength := func(field, value string, max int) {
+		if utf8.RuneCountInString(value) > max {
+			fail("%s is longer than %d characters", field, max)
+		}
+	}
+	checkLength("exerciseName", ex.ExerciseName, maxNameLength)
+	checkLength("description", ex.Description, maxTextLength)
+	checkLength("instructions", ex.Instructions, maxTextLength)
+	checkLength("cues", ex.Cues, maxTextLength)
+	checkLength("videoUrl", ex.VideoUrl, maxUrlLength)
+	checkLength("licenceAuthor", ex.LicenseAuthor, maxAuthorLength)
+
+	ex.CategoryCode = strings.ToUpper(strings.TrimSpace(ex.CategoryCode))
+	switch {
+	case ex.CategoryCode == "":
+		fail("category is required")
+	case !refs.Categories[ex.CategoryCode]:
+		fail("category %s not found", ex.CategoryCode)
+	}
+
+	ex.LicenseShortName = strings.ToUpper(strings.TrimSpace(ex.LicenseShortName))
+	if ex.LicenseShortName != "" && !refs.Licenses[ex.LicenseShortName] {
+		fail("license %s not found", ex.LicenseShortName)
+	}
+
+	seenMuscles := map[string]bool{}
+	for i := range ex.Muscles {
+		mus := &ex.Muscles[i]
+		mus.MuscleCode = strings.ToUpper(strings.TrimSpace(mus.MuscleCode))
+		switch {
+		case mus.MuscleCode == "":
+			fail("muscle code is required")
+			continue
+		case !refs.Muscles[mus.MuscleCode]:
+			fail("muscle %s not found", mus.MuscleCode)
+		case seenMuscles[mus.MuscleCode]:
+			fail("muscle %s is listed more than once", mus.MuscleCode)
+		}
+		seenMuscles[mus.MuscleCode] = true
+		if !mus.MuscleRole.Valid() {
+			fail("muscle %s has invalid role %q", mus.MuscleCode, mus.MuscleRole)
+		}
+	}
+
+	seenApparatus := map[string]bool{}
+	for i := range ex.Apparatus {
+		code := strings.ToUpper(strings.TrimSpace(ex.Apparatus[i]))
+		ex.Apparatus[i] = code
+		switch {
+		case code == "":
+			fail("apparatus code is required")
+		case !refs.Apparatus[code]:
+			fail("apparatus %s not found", code)
+		case seenApparatus[code]:
+			fail("apparatus %s is listed more than once", code)
+		}
+		seenApparatus[code] = true
+	}
+
+	return problems
+}
@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReferences() *References {
+	return &References{
+		Categories: map[string]bool{"STRENGTH": true},
+		Licenses:   map[string]bool{"CC_BY": true},
+		Muscles:    map[string]bool{"QUAD": true, "GLUTE": true},
+		Apparatus:  map[string]bool{"BARBELL": true},
+	}
+}
+
+/*
+ * newTestDaos returns the DAOs of a memory store holding the references of
+ * newReferences: the STRENGTH category, the CC_BY license, the QUAD and
+ * GLUTE muscles and the BARBELL.
+ */
+func newTestDaos(t *testing.T) *dao.Daos {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Licenses.CreateLicense(ctx, &model.LicenseRequest{
+		LicenseFields: model.LicenseFields{LicenseShortName: "CC_BY", LicenseFullName: "Attribution", LicenseUrl: "https://cc.org"}}))
+	for _, code := range []string{"QUAD", "GLUTE"} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{
+			MuscleFields: model.MuscleFields{MuscleCode: code, MuscleName: code, MuscleGroup: "Legs"}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+		ApparatusFields: model.ApparatusFields{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}}))
+	return daos
+}
+
+func TestLoadReferences(t *testing.T) {
+	daos := newTestDaos(t)
+
+	refs, err := LoadReferences(context.Background(), daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+	assert.NoError(t, err)
+	assert.Equal(t, newReferences(), refs)
+}
+
+func TestLoadReferences_Error(t *testing.T) {
+	daos := newTestDaos(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	refs, err := LoadReferences(ctx, daos.Categories, daos.Licenses, daos.Muscles, daos.Apparatus)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, refs)
+}
+
+func TestValidate(t *testing.T) {
+	refs := newReferences()
+
+	ex := model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{
+			ExerciseName:     " Squat ",
+			CategoryCode:     "strength",
+			LicenseShortName: "cc_by",
+		},
+		Muscles:   []model.ExerciseMuscle{{MuscleCode: "quad", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus: []string{"barbell"},
+	}
+
+	assert.Empty(t, refs.Validate(&ex))
+	assert.Equal(t, "Squat", ex.ExerciseName)
+	assert.Equal(t, "STRENGTH", ex.CategoryCode)
+	assert.Equal(t, "CC_BY", ex.LicenseShortName)
+	assert.Equal(t, "QUAD", ex.Muscles[0].MuscleCode)
+	assert.Equal(t, []string{"BARBELL"}, ex.Apparatus)
+}
+
+func TestValidate_LengthInCharacters(t *testing.T) {
+	refs := newReferences()
+
+	ex := model.CatalogExercise{ExerciseFields: model.ExerciseFields{
+		ExerciseName: strings.Repeat("ü", maxNameLength),
+		CategoryCode: "STRENGTH",
+	}}
+	assert.Empty(t, refs.Validate(&ex))
+
+	ex.ExerciseName += "ß"
+	assert.Equal(t, []string{"exerciseName is longer than 100 characters"}, refs.Validate(&ex))
+}
+
+func TestValidate_Problems(t *testing.T) {
+	refs := newReferences()
+
+	ex := model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{
+			Description:      strings.Repeat("x", maxTextLength+1),
+			LicenseShortName: "GPL",
+		},
+		Muscles: []model.ExerciseMuscle{
+			{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary},
+			{MuscleCode: "QUAD", MuscleRole: "major"},
+			{MuscleCode: "NECK", MuscleRole: model.MuscleRoleSecondary},
+		},
+		Apparatus: []string{"BARBELL", "BARBELL", "SLED"},
+	}
+
+	assert.Equal(t, []string{
+		"exerciseName is required",
+		"description is longer than 2500 characters",
+		"category is required",
+		"license GPL not found",
+		"muscle QUAD is listed more than once",
+		`muscle QUAD has invalid role "major"`,
+		"muscle NECK not found",
+		"apparatus BARBELL is listed more than once",
+		"apparatus SLED not found",
+	}, refs.Validate(&ex))
+}
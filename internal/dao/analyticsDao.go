@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// AnalyticsDao aggregates the training of users into time series.
+type AnalyticsDao struct {
+	db *sqlx.DB
+}
+
+type AnalyticsDaoInterface interface {
+	GetDailyVolume(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.SeriesPoint, error)
+	GetMuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) ([]model.MuscleVolume, error)
+}
+
+// Ensure AnalyticsDao implements AnalyticsDaoInterface
+var _ AnalyticsDaoInterface = (*AnalyticsDao)(nil)
+
+// NewAnalyticsDao creates a new instance of AnalyticsDao.
+func NewAnalyticsDao(db *sqlx.DB) *AnalyticsDao {
+	return &AnalyticsDao{db: db}
+}
+
+// getDailyVolumeDQL sums reps times load per UTC day of the sessions of user
+// $1 between $2 and $3. Sets without reps or load do not count.
+const getDailyVolumeDQL string = `
+	SELECT date_trunc('day', s.started_at) AS at, SUM(ws.reps * ws.weight_kg) AS value
+	FROM   workout_session s
+	JOIN   workout_set ws ON ws.session_uuid = s.session_uuid
+	WHERE  s.user_uuid = $1 AND s.started_at >= $2 AND s.started_at < $3
+	AND    ws.reps IS NOT NULL AND ws.weight_kg IS NOT NULL
+	GROUP BY 1
+	ORDER BY 1`
+
+func (dao *AnalyticsDao) GetDailyVolume(ctx context.Context, userUuid uuid.UUID, from, to time.Time) (_ []model.SeriesPoint, err error) {
+	defer observe(ctx, "AnalyticsDao", "GetDailyVolume", time.Now(), &err)
+	var rows []struct {
+		At    dbTime  `db:"at"`
+		Value float64 `db:"value"`
+	}
+	if err := selectContext(ctx, dao.db, "getDailyVolumeDQL", &rows, getDailyVolumeDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	points := make([]model.SeriesPoint, len(rows))
+	for i, row := range rows {
+		points[i] = model.SeriesPoint{At: time.Time(row.At), Value: row.Value}
+	}
+	return points, nil
+}
+
+/*
+ * getMuscleVolumeDQL sums reps times load of the sets of user $1 between $2
+ * and $3 per muscle of level $4 that the exercise of the set works, itself or
+ * through a muscle below it, in any role. A set counts once per muscle even
+ * when its exercise works several muscles below it. The casts let SQLite run
+ * it as well.
+ */
+const getMuscleVolumeDQL string = `
+	SELECT m.muscle_code, m.muscle_name, m.muscle_level,
+	       COUNT(*) AS sets, SUM(w.reps * w.weight_kg) AS volume
+	FROM (
+		SELECT DISTINCT ws.session_uuid, ws.set_number, ws.reps, ws.weight_kg, a.ancestor_code
+		FROM   workout_session s
+		JOIN   workout_set ws     ON ws.session_uuid = s.session_uuid
+		JOIN   exercise_muscle em ON em.exercise_uuid = ws.exercise_uuid
+		JOIN   muscle_ancestry a  ON a.muscle_code = em.muscle_code
+		WHERE  s.user_uuid = $1 AND s.started_at >= $2 AND s.started_at < $3
+		AND    ws.reps IS NOT NULL AND ws.weight_kg IS NOT NULL
+	) w
+	JOIN   muscle_type m ON m.muscle_code = w.ancestor_code
+	WHERE  CAST(m.muscle_level AS TEXT) = $4
+	GROUP BY m.muscle_code, m.muscle_name, m.muscle_level
+	ORDER BY volume DESC, m.muscle_code`
+
+func (dao *AnalyticsDao) GetMuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) (_ []model.MuscleVolume, err error) {
+	defer observe(ctx, "AnalyticsDao", "GetMuscleVolume", time.Now(), &err)
+	volumes := []model.MuscleVolume{}
+	if err := selectContext(ctx, dao.db, "getMuscleVolumeDQL", &volumes, getMuscleVolumeDQL,
+		userUuid, from.UTC(), to.UTC(), string(level)); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDailyVolume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewAnalyticsDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("SELECT date_trunc\\('day', s.started_at\\) AS at, SUM\\(ws.reps \\* ws.weight_kg\\) AS value").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "value"}).
+			AddRow(from, 4200.0).
+			AddRow(from.AddDate(0, 0, 2), 3850.5))
+
+	points, err := dao.GetDailyVolume(context.Background(), userUuid, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SeriesPoint{
+		{At: from, Value: 4200},
+		{At: from.AddDate(0, 0, 2), Value: 3850.5},
+	}, points)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMuscleVolume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewAnalyticsDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("FROM workout_session s JOIN workout_set ws .* JOIN muscle_ancestry a .* "+
+		"JOIN muscle_type m ON m.muscle_code = w.ancestor_code WHERE CAST\\(m.muscle_level AS TEXT\\) = \\$4").
+		WithArgs(userUuid, from, to, "region").
+		WillReturnRows(sqlmock.NewRows([]string{"muscle_code", "muscle_name", "muscle_level", "sets", "volume"}).
+			AddRow("LEGS", "Legs", "region", 12, 9600.0).
+			AddRow("BACK", "Back", "region", 8, 4800.5))
+
+	volumes, err := dao.GetMuscleVolume(context.Background(), userUuid, model.MuscleLevelRegion, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleVolume{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion, Sets: 12, Volume: 9600},
+		{MuscleCode: "BACK", MuscleName: "Back", MuscleLevel: model.MuscleLevelRegion, Sets: 8, Volume: 4800.5},
+	}, volumes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,11 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pwydra/shred/internal/model"
 )
 
+type ApparatusDaoInterface interface {
+	CreateApparatus(ctx context.Context, appReq *model.ApparatusRequest) error
+	GetApparatusByCode(ctx context.Context, code string) (*model.Apparatus, error)
+	GetAllApparatuses(ctx context.Context) ([]model.Apparatus, error)
+	UpdateApparatus(ctx context.Context, appReq *model.ApparatusRequest) error
+	DeleteApparatus(ctx context.Context, code string) error
+}
+
+// Ensure ApparatusDAO implements ApparatusDaoInterface
+var _ ApparatusDaoInterface = (*ApparatusDAO)(nil)
+
 // ApparatusDAO provides access to the apparatuses in the database.
 type ApparatusDAO struct {
 	db *sqlx.DB
@@ -23,9 +35,9 @@ func NewApparatusDAO(db *sqlx.DB) *ApparatusDAO {
 
 const createAppDML string = `
 	INSERT INTO apparatus_type (
-		apparatus_code, apparatus_name, apparatus_description
+		apparatus_code, apparatus_name, apparatus_description, apparatus_group, created_by
 	) VALUES (
-		$1, $2, $3
+		$1, $2, $3, $4, $5
 	)`
 
 // GetApparatusByCode retrieves a apparatus by its Code.
@@ -34,9 +46,10 @@ const getAppByCodeDQL string = `
 	FROM apparatus_type
 	WHERE apparatus_code = $1`
 
-func (dao *ApparatusDAO) GetApparatusByCode(appCode string) (*model.Apparatus, error) {
+func (dao *ApparatusDAO) GetApparatusByCode(ctx context.Context, appCode string) (_ *model.Apparatus, err error) {
+	defer observe(ctx, "ApparatusDAO", "GetApparatusByCode", time.Now(), &err)
 	var apparatus model.Apparatus
-	if err := dao.db.QueryRowx(getAppByCodeDQL, strings.ToUpper(appCode)).StructScan(&apparatus); err != nil {
+	if err := getContext(ctx, dao.db, "getAppByCodeDQL", &apparatus, getAppByCodeDQL, strings.ToUpper(appCode)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("apparatus with code %s not found", strings.ToUpper(appCode))
 		}
@@ -51,9 +64,10 @@ const getAllAppsDQL string = `
 	SELECT *
 	FROM apparatus_type`
 
-func (dao *ApparatusDAO) GetAllApparatuses(ctx context.Context) ([]model.Apparatus, error) {
+func (dao *ApparatusDAO) GetAllApparatuses(ctx context.Context) (_ []model.Apparatus, err error) {
+	defer observe(ctx, "ApparatusDAO", "GetAllApparatuses", time.Now(), &err)
 	var apparatuses []model.Apparatus
-	if err := dao.db.SelectContext(ctx, &apparatuses, getAllAppsDQL); err != nil {
+	if err := selectContext(ctx, dao.db, "getAllAppsDQL", &apparatuses, getAllAppsDQL); err != nil {
 		return nil, err
 	}
 
@@ -63,9 +77,11 @@ func (dao *ApparatusDAO) GetAllApparatuses(ctx context.Context) ([]model.Apparat
 // CreateApparatus inserts a new apparatus into the database.
 // Returns an error if the insertion fails.
 // Does not return the PK as type tables have PK specified by the request.
-func (dao *ApparatusDAO) CreateApparatus(appReq *model.ApparatusRequest) error {
-	_, err := dao.db.Exec(createAppDML,
-		strings.ToUpper(appReq.ApparatusCode), appReq.ApparatusName, appReq.ApparatusDesc)
+func (dao *ApparatusDAO) CreateApparatus(ctx context.Context, appReq *model.ApparatusRequest) (err error) {
+	defer observe(ctx, "ApparatusDAO", "CreateApparatus", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "createAppDML", createAppDML,
+		strings.ToUpper(appReq.ApparatusCode), appReq.ApparatusName, appReq.ApparatusDesc,
+		ApparatusGroup(appReq.ApparatusFields), appReq.CreatedBy)
 	if err != nil {
 		return err
 	}
@@ -73,17 +89,21 @@ func (dao *ApparatusDAO) CreateApparatus(appReq *model.ApparatusRequest) error {
 	return nil
 }
 
-// UpdateApparatus updates an existing apparatus in the database.
+// UpdateApparatus updates an existing apparatus in the database, keeping
+// its group when the request has none.
 const updateAppDML string = `
 	UPDATE apparatus_type
 	SET
 		apparatus_name = $1,
-		apparatus_description = $2
-	WHERE apparatus_code = $3`
-
-func (dao *ApparatusDAO) UpdateApparatus(appReq *model.ApparatusRequest) error {
-	result, err := dao.db.Exec(updateAppDML,
-		appReq.ApparatusName, appReq.ApparatusDesc, strings.ToUpper(appReq.ApparatusCode))
+		apparatus_description = $2,
+		apparatus_group = COALESCE(NULLIF($3, ''), apparatus_group)
+	WHERE apparatus_code = $4`
+
+func (dao *ApparatusDAO) UpdateApparatus(ctx context.Context, appReq *model.ApparatusRequest) (err error) {
+	defer observe(ctx, "ApparatusDAO", "UpdateApparatus", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "updateAppDML", updateAppDML,
+		appReq.ApparatusName, appReq.ApparatusDesc, strings.TrimSpace(appReq.ApparatusGroup),
+		strings.ToUpper(appReq.ApparatusCode))
 	if err != nil {
 		return err
 	}
@@ -105,8 +125,9 @@ const deleteAppDML string = `
 	DELETE FROM apparatus_type
 	WHERE apparatus_code = $1`
 
-func (dao *ApparatusDAO) DeleteApparatus(code string) error {
-	result, err := dao.db.Exec(deleteAppDML, strings.ToUpper(code))
+func (dao *ApparatusDAO) DeleteApparatus(ctx context.Context, code string) (err error) {
+	defer observe(ctx, "ApparatusDAO", "DeleteApparatus", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteAppDML", deleteAppDML, strings.ToUpper(code))
 	if err != nil {
 		return err
 	}
@@ -122,3 +143,11 @@ func (dao *ApparatusDAO) DeleteApparatus(code string) error {
 
 	return nil
 }
+
+// ApparatusGroup puts apparatus without a group into the Other group.
+func ApparatusGroup(app model.ApparatusFields) string {
+	if group := strings.TrimSpace(app.ApparatusGroup); group != "" {
+		return group
+	}
+	return model.ApparatusGroupOther
+}
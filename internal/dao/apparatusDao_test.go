@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/pwydra/shred/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +26,7 @@ func TestGetApparatusByCode(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"apparatus_code", "apparatus_name", "apparatus_description"}).
 			AddRow(appCode, "Bench", "Bench you can lie on and that is optionally adjustable"))
 
-	apparatus, err := dao.GetApparatusByCode(appCode)
+	apparatus, err := dao.GetApparatusByCode(context.Background(), appCode)
 	assert.NoError(t, err)
 	assert.NotNil(t, apparatus)
 	assert.Equal(t, "Bench", apparatus.ApparatusName)
@@ -43,7 +44,7 @@ func TestGetApparatusByCode_NotFound(t *testing.T) {
 		WithArgs(appCode).
 		WillReturnError(sql.ErrNoRows)
 
-	apparatus, err := dao.GetApparatusByCode(appCode)
+	apparatus, err := dao.GetApparatusByCode(context.Background(), appCode)
 	assert.Error(t, err)
 	assert.Nil(t, apparatus)
 	assert.Equal(t, "apparatus with code INVALID not found", err.Error())
@@ -78,17 +79,19 @@ func TestCreateApparatus(t *testing.T) {
 
 	appReq := &model.ApparatusRequest{
 		ApparatusFields: model.ApparatusFields{
-			ApparatusCode: "BENCH",
-			ApparatusName: "Bench",
-			ApparatusDesc: "Bench you can lie on and that is optionally adjustable",
+			ApparatusCode:  "BENCH",
+			ApparatusName:  "Bench",
+			ApparatusDesc:  "Bench you can lie on and that is optionally adjustable",
+			ApparatusGroup: "Benches and racks",
 		},
+		CreatedBy: uuid.New(),
 	}
 
-	mock.ExpectExec("INSERT INTO apparatus_type \\( apparatus_code, apparatus_name, apparatus_description \\) VALUES \\( \\$1, \\$2, \\$3 \\)").
-		WithArgs(appReq.ApparatusCode, appReq.ApparatusName, appReq.ApparatusDesc).
+	mock.ExpectExec("INSERT INTO apparatus_type \\( apparatus_code, apparatus_name, apparatus_description, apparatus_group, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5 \\)").
+		WithArgs(appReq.ApparatusCode, appReq.ApparatusName, appReq.ApparatusDesc, appReq.ApparatusGroup, appReq.CreatedBy).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.CreateApparatus(appReq)
+	err = dao.CreateApparatus(context.Background(), appReq)
 	assert.NoError(t, err)
 }
 
@@ -101,17 +104,19 @@ func TestCreateApparatus_Error(t *testing.T) {
 
 	appReq := &model.ApparatusRequest{
 		ApparatusFields: model.ApparatusFields{
-			ApparatusCode: "BENCH",
-			ApparatusName: "Bench",
-			ApparatusDesc: "Bench you can lie on and that is optionally adjustable",
+			ApparatusCode:  "BENCH",
+			ApparatusName:  "Bench",
+			ApparatusDesc:  "Bench you can lie on and that is optionally adjustable",
+			ApparatusGroup: "Benches and racks",
 		},
+		CreatedBy: uuid.New(),
 	}
 
-	mock.ExpectExec("INSERT INTO apparatus_type \\( apparatus_code, apparatus_name, apparatus_description \\) VALUES \\( \\$1, \\$2, \\$3 \\)").
-		WithArgs(appReq.ApparatusCode, appReq.ApparatusName, appReq.ApparatusDesc).
+	mock.ExpectExec("INSERT INTO apparatus_type \\( apparatus_code, apparatus_name, apparatus_description, apparatus_group, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5 \\)").
+		WithArgs(appReq.ApparatusCode, appReq.ApparatusName, appReq.ApparatusDesc, appReq.ApparatusGroup, appReq.CreatedBy).
 		WillReturnError(errors.New("insertion error"))
 
-	err = dao.CreateApparatus(appReq)
+	err = dao.CreateApparatus(context.Background(), appReq)
 	assert.Error(t, err)
 	assert.Equal(t, "insertion error", err.Error())
 }
@@ -131,11 +136,11 @@ func TestUpdateApparatus(t *testing.T) {
 		},
 	}
 
-	mock.ExpectExec("UPDATE apparatus_type SET apparatus_name = \\$1, apparatus_description = \\$2 WHERE apparatus_code = \\$3").
-		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, appReq.ApparatusCode).
+	mock.ExpectExec("UPDATE apparatus_type SET apparatus_name = \\$1, apparatus_description = \\$2, apparatus_group = COALESCE\\(NULLIF\\(\\$3, ''\\), apparatus_group\\) WHERE apparatus_code = \\$4").
+		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, "", appReq.ApparatusCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.UpdateApparatus(appReq)
+	err = dao.UpdateApparatus(context.Background(), appReq)
 	assert.NoError(t, err)
 }
 
@@ -155,10 +160,10 @@ func TestUpdateApparatus_Error(t *testing.T) {
 	}
 
 	mock.ExpectExec("UPDATE").
-		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, appReq.ApparatusCode).
+		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, "", appReq.ApparatusCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.UpdateApparatus(appReq)
+	err = dao.UpdateApparatus(context.Background(), appReq)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -178,11 +183,11 @@ func TestUpdateApparatus_NotFound(t *testing.T) {
 		},
 	}
 
-	mock.ExpectExec("UPDATE apparatus_type SET apparatus_name = \\$1, apparatus_description = \\$2 WHERE apparatus_code = \\$3").
-		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, appReq.ApparatusCode).
+	mock.ExpectExec("UPDATE apparatus_type SET apparatus_name = \\$1, apparatus_description = \\$2, apparatus_group = COALESCE\\(NULLIF\\(\\$3, ''\\), apparatus_group\\) WHERE apparatus_code = \\$4").
+		WithArgs(appReq.ApparatusName, appReq.ApparatusDesc, "", appReq.ApparatusCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.UpdateApparatus(appReq)
+	err = dao.UpdateApparatus(context.Background(), appReq)
 	assert.Error(t, err)
 	assert.Equal(t, "apparatus with Code INVALID not found", err.Error())
 }
@@ -199,7 +204,7 @@ func TestDeleteApparatus(t *testing.T) {
 		WithArgs(appCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.DeleteApparatus(appCode)
+	err = dao.DeleteApparatus(context.Background(), appCode)
 	assert.NoError(t, err)
 }
 
@@ -215,7 +220,7 @@ func TestDeleteApparatus_Error(t *testing.T) {
 		WithArgs(appCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.DeleteApparatus(appCode)
+	err = dao.DeleteApparatus(context.Background(), appCode)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -232,7 +237,7 @@ func TestDeleteApparatus_NotFound(t *testing.T) {
 		WithArgs(appCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.DeleteApparatus(appCode)
+	err = dao.DeleteApparatus(context.Background(), appCode)
 	assert.Error(t, err)
 	assert.Equal(t, "apparatus with code INVALID not found", err.Error())
 }
@@ -0,0 +1,245 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CalendarDao provides access to scheduled workouts and calendar feeds.
+type CalendarDao struct {
+	db *sqlx.DB
+}
+
+type CalendarDaoInterface interface {
+	CreateScheduledWorkout(ctx context.Context, userUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error)
+	ListScheduledWorkouts(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.ScheduledWorkout, error)
+	GetScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) (*model.ScheduledWorkout, error)
+	UpdateScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error)
+	DeleteScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) error
+	ListCalendarSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CalendarSession, error)
+	GetFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error)
+	SaveFeed(ctx context.Context, userUuid uuid.UUID, token string) (*model.CalendarFeed, error)
+	DeleteFeed(ctx context.Context, userUuid uuid.UUID) error
+	GetFeedUser(ctx context.Context, token string) (uuid.UUID, error)
+}
+
+// Ensure CalendarDao implements CalendarDaoInterface
+var _ CalendarDaoInterface = (*CalendarDao)(nil)
+
+// NewCalendarDao creates a new instance of CalendarDao.
+func NewCalendarDao(db *sqlx.DB) *CalendarDao {
+	return &CalendarDao{db: db}
+}
+
+const scheduledWorkoutColumns string = `
+	schedule_uuid, user_uuid, session_name, scheduled_at, duration_minutes,
+	COALESCE(notes, '') AS notes, session_uuid, created_at, updated_at`
+
+const (
+	createScheduledWorkoutDML string = `
+	INSERT INTO scheduled_workout (
+		user_uuid, session_name, scheduled_at, duration_minutes, notes, session_uuid
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	) RETURNING` + scheduledWorkoutColumns
+
+	listScheduledWorkoutsDQL string = `
+	SELECT` + scheduledWorkoutColumns + `
+	FROM   scheduled_workout
+	WHERE  user_uuid = $1 AND scheduled_at >= $2 AND scheduled_at < $3
+	ORDER BY scheduled_at, schedule_uuid`
+
+	getScheduledWorkoutDQL string = `
+	SELECT` + scheduledWorkoutColumns + `
+	FROM   scheduled_workout
+	WHERE  schedule_uuid = $1`
+
+	updateScheduledWorkoutDML string = `
+	UPDATE scheduled_workout
+	SET    session_name = $1, scheduled_at = $2, duration_minutes = $3, notes = $4, session_uuid = $5,
+	       updated_at = CURRENT_TIMESTAMP
+	WHERE  schedule_uuid = $6
+	RETURNING` + scheduledWorkoutColumns
+
+	deleteScheduledWorkoutDML string = `
+	DELETE FROM scheduled_workout WHERE schedule_uuid = $1`
+
+	listCalendarSessionsDQL string = `
+	SELECT session_uuid, session_name, started_at, duration_seconds, source
+	FROM   workout_session
+	WHERE  user_uuid = $1 AND started_at >= $2 AND started_at < $3
+	ORDER BY started_at, session_uuid`
+)
+
+const (
+	getFeedDQL string = `
+	SELECT user_uuid, feed_token, created_at FROM calendar_feed WHERE user_uuid = $1`
+
+	// saveFeedDML replaces the token of a user, so the old feed URL stops
+	// working.
+	saveFeedDML string = `
+	INSERT INTO calendar_feed (user_uuid, feed_token) VALUES ($1, $2)
+	ON CONFLICT (user_uuid) DO UPDATE SET feed_token = EXCLUDED.feed_token, created_at = CURRENT_TIMESTAMP
+	RETURNING user_uuid, feed_token, created_at`
+
+	deleteFeedDML string = `
+	DELETE FROM calendar_feed WHERE user_uuid = $1`
+
+	getFeedUserDQL string = `
+	SELECT user_uuid FROM calendar_feed WHERE feed_token = $1`
+)
+
+// CreateScheduledWorkout stores a workout normalized with
+// ScheduledWorkoutFields.Normalize.
+func (dao *CalendarDao) CreateScheduledWorkout(ctx context.Context, userUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (_ *model.ScheduledWorkout, err error) {
+	defer observe(ctx, "CalendarDao", "CreateScheduledWorkout", time.Now(), &err)
+	var w model.ScheduledWorkout
+	err = getContext(ctx, dao.db, "createScheduledWorkoutDML", &w, createScheduledWorkoutDML, userUuid, fields.SessionName,
+		fields.ScheduledAt.UTC(), fields.DurationMinutes, nullIfEmpty(fields.Notes), fields.SessionUuid)
+	if isForeignKeyViolation(err) {
+		return nil, missingScheduleReference(userUuid, fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics.WorkoutsScheduled.Inc()
+	return &w, nil
+}
+
+func (dao *CalendarDao) ListScheduledWorkouts(ctx context.Context, userUuid uuid.UUID, from, to time.Time) (_ []model.ScheduledWorkout, err error) {
+	defer observe(ctx, "CalendarDao", "ListScheduledWorkouts", time.Now(), &err)
+	workouts := []model.ScheduledWorkout{}
+	if err := selectContext(ctx, dao.db, "listScheduledWorkoutsDQL", &workouts, listScheduledWorkoutsDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+func (dao *CalendarDao) GetScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) (_ *model.ScheduledWorkout, err error) {
+	defer observe(ctx, "CalendarDao", "GetScheduledWorkout", time.Now(), &err)
+	var w model.ScheduledWorkout
+	if err := getContext(ctx, dao.db, "getScheduledWorkoutDQL", &w, getScheduledWorkoutDQL, scheduleUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("scheduled workout %s %w", scheduleUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (dao *CalendarDao) UpdateScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (_ *model.ScheduledWorkout, err error) {
+	defer observe(ctx, "CalendarDao", "UpdateScheduledWorkout", time.Now(), &err)
+	var w model.ScheduledWorkout
+	err = getContext(ctx, dao.db, "updateScheduledWorkoutDML", &w, updateScheduledWorkoutDML,
+		fields.SessionName, fields.ScheduledAt.UTC(),
+		fields.DurationMinutes, nullIfEmpty(fields.Notes), fields.SessionUuid, scheduleUuid)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("scheduled workout %s %w", scheduleUuid, ErrNotFound)
+	case isForeignKeyViolation(err):
+		return nil, fmt.Errorf("session %s %w", fields.SessionUuid, ErrNotFound)
+	case err != nil:
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (dao *CalendarDao) DeleteScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) (err error) {
+	defer observe(ctx, "CalendarDao", "DeleteScheduledWorkout", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteScheduledWorkoutDML", deleteScheduledWorkoutDML, scheduleUuid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("scheduled workout %s %w", scheduleUuid, ErrNotFound)
+	}
+	return nil
+}
+
+// ListCalendarSessions lists the sessions a user started between from and
+// to, without their sets.
+func (dao *CalendarDao) ListCalendarSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) (_ []model.CalendarSession, err error) {
+	defer observe(ctx, "CalendarDao", "ListCalendarSessions", time.Now(), &err)
+	sessions := []model.CalendarSession{}
+	if err := selectContext(ctx, dao.db, "listCalendarSessionsDQL", &sessions, listCalendarSessionsDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (dao *CalendarDao) GetFeed(ctx context.Context, userUuid uuid.UUID) (_ *model.CalendarFeed, err error) {
+	defer observe(ctx, "CalendarDao", "GetFeed", time.Now(), &err)
+	var feed model.CalendarFeed
+	if err := getContext(ctx, dao.db, "getFeedDQL", &feed, getFeedDQL, userUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("calendar feed of user %s %w", userUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// SaveFeed gives the user a feed with token, replacing the token of an
+// existing feed.
+func (dao *CalendarDao) SaveFeed(ctx context.Context, userUuid uuid.UUID, token string) (_ *model.CalendarFeed, err error) {
+	defer observe(ctx, "CalendarDao", "SaveFeed", time.Now(), &err)
+	var feed model.CalendarFeed
+	err = getContext(ctx, dao.db, "saveFeedDML", &feed, saveFeedDML, userUuid, token)
+	if isForeignKeyViolation(err) {
+		return nil, fmt.Errorf("user %s %w", userUuid, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (dao *CalendarDao) DeleteFeed(ctx context.Context, userUuid uuid.UUID) (err error) {
+	defer observe(ctx, "CalendarDao", "DeleteFeed", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteFeedDML", deleteFeedDML, userUuid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("calendar feed of user %s %w", userUuid, ErrNotFound)
+	}
+	return nil
+}
+
+// GetFeedUser returns the user whose feed has token.
+func (dao *CalendarDao) GetFeedUser(ctx context.Context, token string) (_ uuid.UUID, err error) {
+	defer observe(ctx, "CalendarDao", "GetFeedUser", time.Now(), &err)
+	var userUuid uuid.UUID
+	if err := getContext(ctx, dao.db, "getFeedUserDQL", &userUuid, getFeedUserDQL, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, fmt.Errorf("calendar feed %w", ErrNotFound)
+		}
+		return uuid.Nil, err
+	}
+	return userUuid, nil
+}
+
+// missingScheduleReference names the user, or the user and the session, a
+// scheduled workout refers to when one of them does not exist.
+func missingScheduleReference(userUuid uuid.UUID, fields *model.ScheduledWorkoutFields) error {
+	if fields.SessionUuid != nil {
+		return fmt.Errorf("user %s or session %s %w", userUuid, fields.SessionUuid, ErrNotFound)
+	}
+	return fmt.Errorf("user %s %w", userUuid, ErrNotFound)
+}
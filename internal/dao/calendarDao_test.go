@@ -0,0 +1,187 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var scheduledWorkoutRowColumns = []string{"schedule_uuid", "user_uuid", "session_name", "scheduled_at",
+	"duration_minutes", "notes", "session_uuid", "created_at", "updated_at"}
+
+func TestCreateScheduledWorkout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, scheduleUuid := uuid.New(), uuid.New()
+	at := time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC)
+	minutes := 60
+	fields := &model.ScheduledWorkoutFields{SessionName: "Legs", ScheduledAt: at, DurationMinutes: &minutes}
+	mock.ExpectQuery("INSERT INTO scheduled_workout").
+		WithArgs(userUuid, "Legs", at, &minutes, nil, nil).
+		WillReturnRows(sqlmock.NewRows(scheduledWorkoutRowColumns).
+			AddRow(scheduleUuid, userUuid, "Legs", at, 60, "", nil, at, at))
+
+	w, err := dao.CreateScheduledWorkout(context.Background(), userUuid, fields)
+	assert.NoError(t, err)
+	assert.Equal(t, scheduleUuid, w.ScheduleUuid)
+	assert.Equal(t, 60, *w.DurationMinutes)
+	assert.Nil(t, w.SessionUuid)
+
+	sessionUuid := uuid.New()
+	fields.SessionUuid = &sessionUuid
+	mock.ExpectQuery("INSERT INTO scheduled_workout").
+		WillReturnError(&pq.Error{Code: "23503"})
+	_, err = dao.CreateScheduledWorkout(context.Background(), userUuid, fields)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), sessionUuid.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListScheduledWorkouts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, sessionUuid := uuid.New(), uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("FROM scheduled_workout WHERE user_uuid = \\$1 AND scheduled_at >= \\$2 AND scheduled_at < \\$3").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows(scheduledWorkoutRowColumns).
+			AddRow(uuid.New(), userUuid, "Legs", from, nil, "", sessionUuid, from, from).
+			AddRow(uuid.New(), userUuid, "Push", from.AddDate(0, 0, 2), 45, "bench focus", nil, from, from))
+
+	workouts, err := dao.ListScheduledWorkouts(context.Background(), userUuid, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, workouts, 2)
+	assert.Equal(t, sessionUuid, *workouts[0].SessionUuid)
+	assert.Nil(t, workouts[0].DurationMinutes)
+	assert.Equal(t, "bench focus", workouts[1].Notes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateScheduledWorkout_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	scheduleUuid := uuid.New()
+	at := time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("UPDATE scheduled_workout").
+		WithArgs("Legs", at, nil, nil, nil, scheduleUuid).
+		WillReturnRows(sqlmock.NewRows(scheduledWorkoutRowColumns))
+
+	_, err = dao.UpdateScheduledWorkout(context.Background(), scheduleUuid,
+		&model.ScheduledWorkoutFields{SessionName: "Legs", ScheduledAt: at})
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteScheduledWorkout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	scheduleUuid := uuid.New()
+	mock.ExpectExec("DELETE FROM scheduled_workout").
+		WithArgs(scheduleUuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.DeleteScheduledWorkout(context.Background(), scheduleUuid))
+
+	mock.ExpectExec("DELETE FROM scheduled_workout").
+		WithArgs(scheduleUuid).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = dao.DeleteScheduledWorkout(context.Background(), scheduleUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListCalendarSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	mock.ExpectQuery("SELECT session_uuid, session_name, started_at, duration_seconds, source FROM workout_session").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "session_name", "started_at", "duration_seconds", "source"}).
+			AddRow(uuid.New(), "Legs", from, 3600, "shred").
+			AddRow(uuid.New(), "Morning", from.AddDate(0, 0, 1), nil, "strong"))
+
+	sessions, err := dao.ListCalendarSessions(context.Background(), userUuid, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, 3600, *sessions[0].DurationSeconds)
+	assert.Equal(t, "strong", sessions[1].Source)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveFeed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("INSERT INTO calendar_feed \\(user_uuid, feed_token\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(user_uuid\\) DO UPDATE").
+		WithArgs(userUuid, "secret").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "feed_token", "created_at"}).AddRow(userUuid, "secret", now))
+
+	feed, err := dao.SaveFeed(context.Background(), userUuid, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", feed.Token)
+
+	mock.ExpectQuery("INSERT INTO calendar_feed").
+		WillReturnError(&pq.Error{Code: "23503"})
+	_, err = dao.SaveFeed(context.Background(), userUuid, "secret")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFeedUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCalendarDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	mock.ExpectQuery("SELECT user_uuid FROM calendar_feed WHERE feed_token = \\$1").
+		WithArgs("secret").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid"}).AddRow(userUuid))
+	got, err := dao.GetFeedUser(context.Background(), "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, got)
+
+	mock.ExpectQuery("SELECT user_uuid FROM calendar_feed").
+		WithArgs("revoked").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid"}))
+	_, err = dao.GetFeedUser(context.Background(), "revoked")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NotContains(t, err.Error(), "revoked", "tokens are secret")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,187 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CardioDao provides access to activities recorded on devices.
+type CardioDao struct {
+	db *sqlx.DB
+}
+
+type CardioDaoInterface interface {
+	GetExerciseCategory(ctx context.Context, exUuid uuid.UUID) (string, error)
+	FindActivityByStart(ctx context.Context, userUuid uuid.UUID, startedAt time.Time) (*uuid.UUID, error)
+	CreateActivity(ctx context.Context, activity *model.CardioActivity) error
+	ListActivities(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CardioActivity, error)
+	GetActivity(ctx context.Context, sessionUuid uuid.UUID) (*model.CardioActivity, error)
+}
+
+// Ensure CardioDao implements CardioDaoInterface
+var _ CardioDaoInterface = (*CardioDao)(nil)
+
+// NewCardioDao creates a new instance of CardioDao.
+func NewCardioDao(db *sqlx.DB) *CardioDao {
+	return &CardioDao{db: db}
+}
+
+// GetExerciseCategory returns the category code of an exercise.
+const getExerciseCategoryDQL string = `
+	SELECT category_code
+	FROM   exercise
+	WHERE  exercise_uuid = $1`
+
+func (dao *CardioDao) GetExerciseCategory(ctx context.Context, exUuid uuid.UUID) (_ string, err error) {
+	defer observe(ctx, "CardioDao", "GetExerciseCategory", time.Now(), &err)
+	var category string
+	if err := scanContext(ctx, dao.db, "getExerciseCategoryDQL", getExerciseCategoryDQL,
+		[]any{exUuid}, &category); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("exercise with uuid %s %w", exUuid, ErrNotFound)
+		}
+		return "", err
+	}
+	return category, nil
+}
+
+// FindActivityByStart returns the activity of a user that started at
+// startedAt, if any, so the same recording is not imported twice.
+const findActivityByStartDQL string = `
+	SELECT s.session_uuid
+	FROM   workout_session s
+	JOIN   cardio_activity c ON c.session_uuid = s.session_uuid
+	WHERE  s.user_uuid = $1 AND s.started_at = $2`
+
+func (dao *CardioDao) FindActivityByStart(ctx context.Context, userUuid uuid.UUID, startedAt time.Time) (_ *uuid.UUID, err error) {
+	defer observe(ctx, "CardioDao", "FindActivityByStart", time.Now(), &err)
+	var sessionUuid uuid.UUID
+	if err := scanContext(ctx, dao.db, "findActivityByStartDQL", findActivityByStartDQL,
+		[]any{userUuid, startedAt.UTC()}, &sessionUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sessionUuid, nil
+}
+
+const createCardioDML string = `
+	INSERT INTO cardio_activity (
+		session_uuid, sport, distance_m, duration_seconds, pace_seconds_per_km,
+		elevation_gain_m, elevation_loss_m, avg_heart_rate, max_heart_rate
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+const createHeartRateDML string = `
+	INSERT INTO cardio_heart_rate (session_uuid, offset_seconds, heart_rate)
+	SELECT $1, unnest($2::integer[]), unnest($3::integer[])`
+
+// CreateActivity stores the activity as a session with one set of its
+// exercise, together with its summary and heart rate samples, in one
+// transaction or in the unit of work it is called in.
+func (dao *CardioDao) CreateActivity(ctx context.Context, activity *model.CardioActivity) (err error) {
+	defer observe(ctx, "CardioDao", "CreateActivity", time.Now(), &err)
+	var session *model.WorkoutSession
+	err = transaction(ctx, dao.db, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		distance, duration := activity.DistanceM, activity.DurationSeconds
+		var err error
+		session, err = insertSession(ctx, tx, activity.UserUuid, model.WorkoutSessionRequest{
+			WorkoutSessionFields: model.WorkoutSessionFields{
+				SessionName:     activity.SessionName,
+				StartedAt:       activity.StartedAt,
+				DurationSeconds: &duration,
+				Source:          activity.Source,
+			},
+			Sets: []model.WorkoutSet{{ExerciseUuid: activity.ExerciseUuid, DistanceM: &distance, DurationSeconds: &duration}},
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := execContext(ctx, tx, "createCardioDML", createCardioDML,
+			session.SessionUuid, activity.Sport, activity.DistanceM, activity.DurationSeconds,
+			activity.PaceSecondsPerKm, activity.ElevationGainM, activity.ElevationLossM,
+			activity.AvgHeartRate, activity.MaxHeartRate); err != nil {
+			return err
+		}
+
+		if len(activity.HeartRate) > 0 {
+			offsets := make([]int64, len(activity.HeartRate))
+			rates := make([]int64, len(activity.HeartRate))
+			for i, sample := range activity.HeartRate {
+				offsets[i], rates[i] = int64(sample.OffsetSeconds), int64(sample.HeartRate)
+			}
+			if _, err := execContext(ctx, tx, "createHeartRateDML", createHeartRateDML, session.SessionUuid, pq.Array(offsets), pq.Array(rates)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	countLogged(session)
+	activity.SessionUuid = session.SessionUuid
+	activity.StartedAt = session.StartedAt
+	activity.CreatedAt = session.CreatedAt
+	return nil
+}
+
+const selectCardioDQL string = `
+	SELECT s.session_uuid, s.user_uuid, ws.exercise_uuid, s.session_name, s.started_at,
+	       s.source, c.sport, c.distance_m, c.duration_seconds, c.pace_seconds_per_km,
+	       c.elevation_gain_m, c.elevation_loss_m, c.avg_heart_rate, c.max_heart_rate,
+	       s.created_at
+	FROM   cardio_activity c
+	JOIN   workout_session s ON s.session_uuid = c.session_uuid
+	JOIN   workout_set ws ON ws.session_uuid = c.session_uuid AND ws.set_number = 1`
+
+// ListActivities returns the activities of a user started in [from, to),
+// oldest first, without their heart rate samples.
+const listCardioDQL string = selectCardioDQL + `
+	WHERE  s.user_uuid = $1 AND s.started_at >= $2 AND s.started_at < $3
+	ORDER BY s.started_at, s.session_uuid`
+
+func (dao *CardioDao) ListActivities(ctx context.Context, userUuid uuid.UUID, from, to time.Time) (_ []model.CardioActivity, err error) {
+	defer observe(ctx, "CardioDao", "ListActivities", time.Now(), &err)
+	activities := []model.CardioActivity{}
+	if err := selectContext(ctx, dao.db, "listCardioDQL", &activities, listCardioDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// GetActivity returns a single activity with its heart rate samples.
+const getCardioDQL string = selectCardioDQL + `
+	WHERE  s.session_uuid = $1`
+
+const listHeartRateDQL string = `
+	SELECT offset_seconds, heart_rate
+	FROM   cardio_heart_rate
+	WHERE  session_uuid = $1
+	ORDER BY offset_seconds`
+
+func (dao *CardioDao) GetActivity(ctx context.Context, sessionUuid uuid.UUID) (_ *model.CardioActivity, err error) {
+	defer observe(ctx, "CardioDao", "GetActivity", time.Now(), &err)
+	var activity model.CardioActivity
+	if err := getContext(ctx, dao.db, "getCardioDQL", &activity, getCardioDQL, sessionUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("activity with uuid %s %w", sessionUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+
+	activity.HeartRate = []model.HeartRateSample{}
+	if err := selectContext(ctx, dao.db, "listHeartRateDQL", &activity.HeartRate, listHeartRateDQL, sessionUuid); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
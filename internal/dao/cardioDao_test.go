@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var cardioColumns = []string{
+	"session_uuid", "user_uuid", "exercise_uuid", "session_name", "started_at", "source", "sport",
+	"distance_m", "duration_seconds", "pace_seconds_per_km", "elevation_gain_m", "elevation_loss_m",
+	"avg_heart_rate", "max_heart_rate", "created_at",
+}
+
+func TestGetExerciseCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCardioDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT category_code FROM exercise WHERE exercise_uuid = \\$1").WithArgs(exUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"category_code"}).AddRow("CARDIO"))
+	mock.ExpectQuery("SELECT category_code").WithArgs(exUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"category_code"}))
+
+	category, err := dao.GetExerciseCategory(context.Background(), exUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "CARDIO", category)
+
+	_, err = dao.GetExerciseCategory(context.Background(), exUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "exercise with uuid "+exUuid.String()+" not found")
+}
+
+func TestCreateActivity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCardioDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, exUuid, sessionUuid := uuid.New(), uuid.New(), uuid.New()
+	startedAt := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	pace, hr := 300.0, 140
+	activity := &model.CardioActivity{
+		UserUuid:     userUuid,
+		ExerciseUuid: exUuid,
+		SessionName:  "Morning Run",
+		StartedAt:    startedAt,
+		Source:       "fit",
+		CardioSummary: model.CardioSummary{
+			Sport: "running", DistanceM: 5000, DurationSeconds: 1500,
+			PaceSecondsPerKm: &pace, AvgHeartRate: &hr, MaxHeartRate: &hr,
+		},
+		HeartRate: []model.HeartRateSample{{OffsetSeconds: 0, HeartRate: 120}, {OffsetSeconds: 5, HeartRate: 125}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WithArgs(userUuid, "Morning Run", startedAt, 1500, "", "fit").
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(sessionUuid, startedAt, startedAt))
+	mock.ExpectExec("INSERT INTO workout_set").
+		WithArgs(sessionUuid, 1, exUuid, nil, nil, 5000.0, 1500, nil, "", nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO cardio_activity").
+		WithArgs(sessionUuid, "running", 5000.0, 1500, 300.0, nil, nil, 140, 140).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO cardio_heart_rate .* unnest").
+		WithArgs(sessionUuid, pq.Array([]int64{0, 5}), pq.Array([]int64{120, 125})).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err = dao.CreateActivity(context.Background(), activity)
+	assert.NoError(t, err)
+	assert.Equal(t, sessionUuid, activity.SessionUuid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateActivity_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCardioDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(uuid.New(), time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO workout_set").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO cardio_activity").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	err = dao.CreateActivity(context.Background(), &model.CardioActivity{UserUuid: uuid.New()})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListActivities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCardioDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("FROM cardio_activity c .* WHERE s.user_uuid = \\$1 AND s.started_at >= \\$2 AND s.started_at < \\$3").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows(cardioColumns).
+			AddRow(uuid.New(), userUuid, uuid.New(), "Morning Run", from, "gpx", "running", "5000.00", 1500, "300.0", "12.0", "10.0", 140, 171, from))
+
+	activities, err := dao.ListActivities(context.Background(), userUuid, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+	assert.Equal(t, 5000.0, activities[0].DistanceM)
+	assert.Equal(t, 171, *activities[0].MaxHeartRate)
+	assert.Nil(t, activities[0].HeartRate)
+}
+
+func TestGetActivity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCardioDao(sqlx.NewDb(db, "postgres"))
+
+	sessionUuid := uuid.New()
+	now := time.Now()
+	mock.ExpectQuery("FROM cardio_activity c .* WHERE s.session_uuid = \\$1").WithArgs(sessionUuid).
+		WillReturnRows(sqlmock.NewRows(cardioColumns).
+			AddRow(sessionUuid, uuid.New(), uuid.New(), "Ride", now, "fit", "cycling", "20000.00", 3600, "180.0", nil, nil, nil, nil, now))
+	mock.ExpectQuery("SELECT offset_seconds, heart_rate FROM cardio_heart_rate").WithArgs(sessionUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"offset_seconds", "heart_rate"}))
+
+	activity, err := dao.GetActivity(context.Background(), sessionUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "cycling", activity.Sport)
+	assert.Nil(t, activity.AvgHeartRate)
+	assert.Equal(t, []model.HeartRateSample{}, activity.HeartRate)
+
+	mock.ExpectQuery("FROM cardio_activity c").WithArgs(sessionUuid).WillReturnRows(sqlmock.NewRows(cardioColumns))
+	_, err = dao.GetActivity(context.Background(), sessionUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,397 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CatalogDao provides bulk access to exercises together with their muscle and
+// apparatus links.
+type CatalogDao struct {
+	db *sqlx.DB
+}
+
+type CatalogDaoInterface interface {
+	FindExercisesByName(ctx context.Context, names []string) (map[string]uuid.UUID, error)
+	ListExercises(ctx context.Context) ([]model.CatalogExercise, error)
+	ListExerciseNames(ctx context.Context) ([]model.ExerciseName, error)
+	ImportCatalog(ctx context.Context, refs *model.CatalogReferences, entries []model.CatalogExercise, upsert bool, createdBy uuid.UUID) ([]uuid.UUID, error)
+}
+
+// Ensure CatalogDao implements CatalogDaoInterface
+var _ CatalogDaoInterface = (*CatalogDao)(nil)
+
+// NewCatalogDao creates a new instance of CatalogDao.
+func NewCatalogDao(db *sqlx.DB) *CatalogDao {
+	return &CatalogDao{db: db}
+}
+
+// FindExercisesByName looks up exercises by case-insensitive name.
+const findExByNameDQL string = `
+	SELECT exercise_uuid, lower(exercise_name) AS exercise_name
+	FROM   exercise
+	WHERE  lower(exercise_name) = ANY($1)`
+
+// FindExercisesByName returns the uuids of existing exercises keyed by their
+// lower-cased name. Names that do not exist are absent from the map.
+func (dao *CatalogDao) FindExercisesByName(ctx context.Context, names []string) (_ map[string]uuid.UUID, err error) {
+	defer observe(ctx, "CatalogDao", "FindExercisesByName", time.Now(), &err)
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var rows []struct {
+		ExerciseUuid uuid.UUID `db:"exercise_uuid"`
+		ExerciseName string    `db:"exercise_name"`
+	}
+	if err := selectContext(ctx, dao.db, "findExByNameDQL", &rows, findExByNameDQL, pq.Array(lowered)); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]uuid.UUID, len(rows))
+	for _, row := range rows {
+		found[row.ExerciseName] = row.ExerciseUuid
+	}
+	return found, nil
+}
+
+const findExForUpdateDQL string = `
+	SELECT exercise_uuid
+	FROM   exercise
+	WHERE  lower(exercise_name) = lower($1)
+	LIMIT  1
+	FOR UPDATE`
+
+const deleteExMusclesDML string = "DELETE FROM exercise_muscle WHERE exercise_uuid = $1"
+
+const deleteExApparatusDML string = "DELETE FROM exercise_apparatus WHERE exercise_uuid = $1"
+
+const createExMuscleDML string = `
+	INSERT INTO exercise_muscle (
+		exercise_uuid, muscle_code, muscle_role
+	) VALUES ($1, $2, $3)`
+
+const createExApparatusDML string = `
+	INSERT INTO exercise_apparatus (
+		exercise_uuid, apparatus_code
+	) VALUES ($1, $2)`
+
+// ListExercises returns every exercise ordered by name. Nullable text
+// columns are read as empty strings.
+const listCatalogExDQL string = `
+	SELECT exercise_uuid, exercise_name, exercise_description,
+	       COALESCE(instructions, '') AS instructions,
+	       COALESCE(cues, '') AS cues,
+	       COALESCE(video_url, '') AS video_url,
+	       category_code,
+	       COALESCE(license_short_name, '') AS license_short_name,
+	       COALESCE(license_author, '') AS license_author
+	FROM   exercise
+	ORDER BY exercise_name, exercise_uuid`
+
+const listCatalogMusclesDQL string = `
+	SELECT exercise_uuid, muscle_code, muscle_role
+	FROM   exercise_muscle
+	ORDER BY exercise_uuid, muscle_role, muscle_code`
+
+const listCatalogApparatusDQL string = `
+	SELECT exercise_uuid, apparatus_code
+	FROM   exercise_apparatus
+	ORDER BY exercise_uuid, apparatus_code`
+
+// ListExercises returns all exercises together with their muscles and
+// apparatus.
+func (dao *CatalogDao) ListExercises(ctx context.Context) (_ []model.CatalogExercise, err error) {
+	defer observe(ctx, "CatalogDao", "ListExercises", time.Now(), &err)
+	var exercises []struct {
+		ExerciseUuid uuid.UUID `db:"exercise_uuid"`
+		model.ExerciseFields
+	}
+	if err := selectContext(ctx, dao.db, "listCatalogExDQL", &exercises, listCatalogExDQL); err != nil {
+		return nil, err
+	}
+
+	var muscles []struct {
+		ExerciseUuid uuid.UUID `db:"exercise_uuid"`
+		model.ExerciseMuscle
+	}
+	if err := selectContext(ctx, dao.db, "listCatalogMusclesDQL", &muscles, listCatalogMusclesDQL); err != nil {
+		return nil, err
+	}
+
+	var apparatus []struct {
+		ExerciseUuid  uuid.UUID `db:"exercise_uuid"`
+		ApparatusCode string    `db:"apparatus_code"`
+	}
+	if err := selectContext(ctx, dao.db, "listCatalogApparatusDQL", &apparatus, listCatalogApparatusDQL); err != nil {
+		return nil, err
+	}
+
+	catalog := make([]model.CatalogExercise, len(exercises))
+	byUuid := make(map[uuid.UUID]*model.CatalogExercise, len(exercises))
+	for i, ex := range exercises {
+		catalog[i] = model.CatalogExercise{
+			ExerciseFields: ex.ExerciseFields,
+			Muscles:        []model.ExerciseMuscle{},
+			Apparatus:      []string{},
+		}
+		byUuid[ex.ExerciseUuid] = &catalog[i]
+	}
+	for _, mus := range muscles {
+		if ex, ok := byUuid[mus.ExerciseUuid]; ok {
+			ex.Muscles = append(ex.Muscles, mus.ExerciseMuscle)
+		}
+	}
+	for _, app := range apparatus {
+		if ex, ok := byUuid[app.ExerciseUuid]; ok {
+			ex.Apparatus = append(ex.Apparatus, app.ApparatusCode)
+		}
+	}
+
+	return catalog, nil
+}
+
+// ListExerciseNames returns the uuid and name of every exercise.
+const listExNamesDQL string = `
+	SELECT exercise_uuid, exercise_name
+	FROM   exercise
+	ORDER BY exercise_name, exercise_uuid`
+
+func (dao *CatalogDao) ListExerciseNames(ctx context.Context) (_ []model.ExerciseName, err error) {
+	defer observe(ctx, "CatalogDao", "ListExerciseNames", time.Now(), &err)
+	var names []model.ExerciseName
+	if err := selectContext(ctx, dao.db, "listExNamesDQL", &names, listExNamesDQL); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Reference types are created if missing. On upsert the names and
+// descriptions of existing rows are replaced, otherwise they are kept.
+const (
+	importCatDML string = `
+	INSERT INTO category_type (
+		category_code, category_name, category_description, created_by
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (category_code) DO NOTHING`
+
+	upsertCatDML string = `
+	INSERT INTO category_type (
+		category_code, category_name, category_description, created_by
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (category_code) DO UPDATE SET
+		category_name = EXCLUDED.category_name,
+		category_description = EXCLUDED.category_description,
+		updated_at = CURRENT_TIMESTAMP`
+
+	importLicenseDML string = `
+	INSERT INTO license (
+		license_short_name, license_full_name, url, created_by
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (license_short_name) DO NOTHING`
+
+	upsertLicenseDML string = `
+	INSERT INTO license (
+		license_short_name, license_full_name, url, created_by
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (license_short_name) DO UPDATE SET
+		license_full_name = EXCLUDED.license_full_name,
+		url = EXCLUDED.url,
+		updated_at = CURRENT_TIMESTAMP`
+
+	importMusDML string = `
+	INSERT INTO muscle_type (
+		muscle_code, muscle_name, muscle_description, muscle_group, muscle_level, parent_code,
+		movement_pattern, created_by
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (muscle_code) DO NOTHING`
+
+	upsertMusDML string = `
+	INSERT INTO muscle_type (
+		muscle_code, muscle_name, muscle_description, muscle_group, muscle_level, parent_code,
+		movement_pattern, created_by
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (muscle_code) DO UPDATE SET
+		muscle_name = EXCLUDED.muscle_name,
+		muscle_description = EXCLUDED.muscle_description,
+		muscle_group = EXCLUDED.muscle_group,
+		muscle_level = EXCLUDED.muscle_level,
+		parent_code = EXCLUDED.parent_code,
+		movement_pattern = EXCLUDED.movement_pattern,
+		updated_at = CURRENT_TIMESTAMP`
+
+	importAppDML string = `
+	INSERT INTO apparatus_type (
+		apparatus_code, apparatus_name, apparatus_description, apparatus_group, created_by
+	) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (apparatus_code) DO NOTHING`
+
+	upsertAppDML string = `
+	INSERT INTO apparatus_type (
+		apparatus_code, apparatus_name, apparatus_description, apparatus_group, created_by
+	) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (apparatus_code) DO UPDATE SET
+		apparatus_name = EXCLUDED.apparatus_name,
+		apparatus_description = EXCLUDED.apparatus_description,
+		apparatus_group = EXCLUDED.apparatus_group,
+		updated_at = CURRENT_TIMESTAMP`
+)
+
+// ImportCatalog writes the reference types in refs, which may be nil, and
+// then all entries in a single transaction, rolling back on the first
+// failure. When upsert is true an exercise with the same name is updated in
+// place and its muscle and apparatus links are replaced, otherwise a new
+// exercise is always inserted.
+// Returns the uuid of each entry in input order.
+func (dao *CatalogDao) ImportCatalog(ctx context.Context, refs *model.CatalogReferences, entries []model.CatalogExercise, upsert bool, createdBy uuid.UUID) (_ []uuid.UUID, err error) {
+	defer observe(ctx, "CatalogDao", "ImportCatalog", time.Now(), &err)
+	uuids := make([]uuid.UUID, 0, len(entries))
+	err = transaction(ctx, dao.db, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		if refs != nil {
+			if err := importReferences(ctx, tx, refs, upsert, createdBy); err != nil {
+				return err
+			}
+		}
+
+		for i := range entries {
+			exUuid, err := importExercise(ctx, tx, &entries[i], upsert, createdBy)
+			if err != nil {
+				return err
+			}
+			uuids = append(uuids, exUuid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uuids, nil
+}
+
+func importReferences(ctx context.Context, tx *sqlx.Tx, refs *model.CatalogReferences, upsert bool, createdBy uuid.UUID) error {
+	catDML, licDML, musDML, appDML := importCatDML, importLicenseDML, importMusDML, importAppDML
+	if upsert {
+		catDML, licDML, musDML, appDML = upsertCatDML, upsertLicenseDML, upsertMusDML, upsertAppDML
+	}
+
+	for _, cat := range refs.Categories {
+		if _, err := execContext(ctx, tx, "catDML", catDML,
+			strings.ToUpper(cat.CategoryCode), cat.CategoryName, cat.CategoryDesc, createdBy); err != nil {
+			return err
+		}
+	}
+	for _, lic := range refs.Licenses {
+		if _, err := execContext(ctx, tx, "licDML", licDML,
+			strings.ToUpper(lic.LicenseShortName), lic.LicenseFullName, lic.LicenseUrl, createdBy); err != nil {
+			return err
+		}
+	}
+	// Parents are always higher up the hierarchy, so inserting region by
+	// region down to the heads creates them before their children.
+	muscles := slices.Clone(refs.Muscles)
+	slices.SortStableFunc(muscles, func(a, b model.MuscleFields) int {
+		return muscleDepth(a) - muscleDepth(b)
+	})
+	for _, mus := range muscles {
+		var parent *string
+		if mus.ParentCode != nil {
+			code := strings.ToUpper(*mus.ParentCode)
+			parent = &code
+		}
+		if _, err := execContext(ctx, tx, "musDML", musDML,
+			strings.ToUpper(mus.MuscleCode), mus.MuscleName, mus.MuscleDesc, mus.MuscleGroup,
+			muscleLevel(mus), parent, mus.MovementPattern, createdBy); err != nil {
+			return err
+		}
+	}
+	for _, app := range refs.Apparatus {
+		if _, err := execContext(ctx, tx, "appDML", appDML,
+			strings.ToUpper(app.ApparatusCode), app.ApparatusName, app.ApparatusDesc,
+			ApparatusGroup(app), createdBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importExercise(ctx context.Context, tx *sqlx.Tx, entry *model.CatalogExercise, upsert bool, createdBy uuid.UUID) (uuid.UUID, error) {
+	var exUuid uuid.UUID
+	found := false
+	if upsert {
+		err := scanContext(ctx, tx, "findExForUpdateDQL", findExForUpdateDQL, []any{entry.ExerciseName}, &exUuid)
+		switch {
+		case err == nil:
+			found = true
+		case !errors.Is(err, sql.ErrNoRows):
+			return uuid.Nil, err
+		}
+	}
+
+	if found {
+		if _, err := execContext(ctx, tx, "updateDML", updateDML,
+			entry.ExerciseName, entry.Description, entry.Instructions, entry.Cues,
+			entry.VideoUrl, entry.CategoryCode, nullIfEmpty(entry.LicenseShortName),
+			entry.LicenseAuthor, exUuid); err != nil {
+			return uuid.Nil, err
+		}
+		if _, err := execContext(ctx, tx, "deleteExMusclesDML", deleteExMusclesDML, exUuid); err != nil {
+			return uuid.Nil, err
+		}
+		if _, err := execContext(ctx, tx, "deleteExApparatusDML", deleteExApparatusDML, exUuid); err != nil {
+			return uuid.Nil, err
+		}
+	} else {
+		var createdAt, updatedAt sql.NullTime
+		if err := scanContext(ctx, tx, "createDML", createDML, []any{
+			entry.ExerciseName, entry.Description, entry.Instructions, entry.Cues, entry.VideoUrl,
+			entry.CategoryCode, nullIfEmpty(entry.LicenseShortName), entry.LicenseAuthor, createdBy,
+		}, &exUuid, &createdAt, &updatedAt); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	for _, mus := range entry.Muscles {
+		if _, err := execContext(ctx, tx, "createExMuscleDML", createExMuscleDML,
+			exUuid, strings.ToUpper(mus.MuscleCode), mus.MuscleRole); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	for _, app := range entry.Apparatus {
+		if _, err := execContext(ctx, tx, "createExApparatusDML", createExApparatusDML, exUuid, strings.ToUpper(app)); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	return exUuid, nil
+}
+
+// nullIfEmpty maps an empty optional column, such as a foreign key, to NULL.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// muscleLevel defaults the level of muscles exported before the hierarchy
+// existed.
+func muscleLevel(mus model.MuscleFields) model.MuscleLevel {
+	if mus.MuscleLevel == "" {
+		return model.MuscleLevelMuscle
+	}
+	return mus.MuscleLevel
+}
+
+func muscleDepth(mus model.MuscleFields) int {
+	return muscleLevel(mus).Depth()
+}
@@ -0,0 +1,272 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCatalogExercise() model.CatalogExercise {
+	return model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{
+			ExerciseName:     "Squat",
+			Description:      "Lower Body",
+			Instructions:     "Stand with feet shoulder-width apart",
+			Cues:             "Keep chest up and back flat",
+			VideoUrl:         "http://example.com/squat.mp4",
+			CategoryCode:     "STRENGTH",
+			LicenseShortName: "CC_BY",
+			LicenseAuthor:    "John Doe",
+		},
+		Muscles:   []model.ExerciseMuscle{{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus: []string{"BARBELL"},
+	}
+}
+
+func TestFindExercisesByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT exercise_uuid, lower\\(exercise_name\\) AS exercise_name FROM exercise WHERE lower\\(exercise_name\\) = ANY\\(\\$1\\)").
+		WithArgs(`{"squat","bench press"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name"}).AddRow(exUuid, "squat"))
+
+	found, err := dao.FindExercisesByName(context.Background(), []string{"Squat", "Bench Press"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uuid.UUID{"squat": exUuid}, found)
+}
+
+func TestFindExercisesByName_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT exercise_uuid").WillReturnError(sqlmock.ErrCancelled)
+
+	found, err := dao.FindExercisesByName(context.Background(), []string{"Squat"})
+	assert.Error(t, err)
+	assert.Nil(t, found)
+}
+
+func TestImportCatalog_Insert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	ex := newCatalogExercise()
+	createdBy := uuid.New()
+	exUuid := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO exercise").
+		WithArgs(ex.ExerciseName, ex.Description, ex.Instructions, ex.Cues,
+			ex.VideoUrl, ex.CategoryCode, ex.LicenseShortName, ex.LicenseAuthor, createdBy).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "created_at", "updated_at"}).AddRow(exUuid, time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO exercise_muscle").
+		WithArgs(exUuid, "QUAD", model.MuscleRolePrimary).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO exercise_apparatus").
+		WithArgs(exUuid, "BARBELL").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	uuids, err := dao.ImportCatalog(context.Background(), nil, []model.CatalogExercise{ex}, false, createdBy)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{exUuid}, uuids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportCatalog_UpsertExisting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	ex := newCatalogExercise()
+	exUuid := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT exercise_uuid FROM exercise WHERE lower\\(exercise_name\\) = lower\\(\\$1\\)").
+		WithArgs(ex.ExerciseName).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid"}).AddRow(exUuid))
+	mock.ExpectExec("UPDATE exercise SET").
+		WithArgs(ex.ExerciseName, ex.Description, ex.Instructions, ex.Cues,
+			ex.VideoUrl, ex.CategoryCode, ex.LicenseShortName, ex.LicenseAuthor, exUuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM exercise_muscle").WithArgs(exUuid).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM exercise_apparatus").WithArgs(exUuid).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO exercise_muscle").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO exercise_apparatus").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	uuids, err := dao.ImportCatalog(context.Background(), nil, []model.CatalogExercise{ex}, true, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{exUuid}, uuids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportCatalog_RollbackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	ex := newCatalogExercise()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT exercise_uuid FROM exercise").
+		WithArgs(ex.ExerciseName).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid"}))
+	mock.ExpectQuery("INSERT INTO exercise").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	uuids, err := dao.ImportCatalog(context.Background(), nil, []model.CatalogExercise{ex}, true, uuid.New())
+	assert.Error(t, err)
+	assert.Nil(t, uuids)
+	assert.Equal(t, "canceling query due to user request", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportCatalog_References(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	createdBy := uuid.New()
+	legs, push := "legs", model.MovementPush
+	refs := &model.CatalogReferences{
+		Categories: []model.CategoryFields{{CategoryCode: "strength", CategoryName: "Strength"}},
+		Licenses:   []model.LicenseFields{{LicenseShortName: "cc_by", LicenseFullName: "Attribution", LicenseUrl: "https://cc.org"}},
+		Muscles: []model.MuscleFields{
+			{MuscleCode: "quad", MuscleName: "Quadriceps", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelGroup,
+				ParentCode: &legs, MovementPattern: &push},
+			{MuscleCode: "legs", MuscleName: "Legs", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		},
+		Apparatus: []model.ApparatusFields{{ApparatusCode: "barbell", ApparatusName: "Barbell"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO category_type .* ON CONFLICT \\(category_code\\) DO UPDATE").
+		WithArgs("STRENGTH", "Strength", "", createdBy).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO license .* ON CONFLICT \\(license_short_name\\) DO UPDATE").
+		WithArgs("CC_BY", "Attribution", "https://cc.org", createdBy).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO muscle_type .* ON CONFLICT \\(muscle_code\\) DO UPDATE").
+		WithArgs("LEGS", "Legs", "", "Legs", model.MuscleLevelRegion, nil, nil, createdBy).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO muscle_type .* ON CONFLICT \\(muscle_code\\) DO UPDATE").
+		WithArgs("QUAD", "Quadriceps", "", "Legs", model.MuscleLevelGroup, "LEGS", "push", createdBy).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO apparatus_type .* ON CONFLICT \\(apparatus_code\\) DO UPDATE").
+		WithArgs("BARBELL", "Barbell", "", model.ApparatusGroupOther, createdBy).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	uuids, err := dao.ImportCatalog(context.Background(), refs, nil, true, createdBy)
+	assert.NoError(t, err)
+	assert.Empty(t, uuids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportCatalog_ReferencesKeepExisting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	refs := &model.CatalogReferences{
+		Categories: []model.CategoryFields{{CategoryCode: "STRENGTH", CategoryName: "Strength"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO category_type .* ON CONFLICT \\(category_code\\) DO NOTHING").
+		WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	_, err = dao.ImportCatalog(context.Background(), refs, nil, false, uuid.New())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListExercises(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	squat, pushUp := uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT exercise_uuid, exercise_name, exercise_description, .* FROM exercise ORDER BY exercise_name").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"exercise_uuid", "exercise_name", "exercise_description", "instructions", "cues",
+			"video_url", "category_code", "license_short_name", "license_author"}).
+			AddRow(pushUp, "Push-up", "Upper Body", "", "", "", "STRENGTH", "", "").
+			AddRow(squat, "Squat", "Lower Body", "Stand", "Chest up", "", "STRENGTH", "CC_BY", "John Doe"))
+	mock.ExpectQuery("SELECT exercise_uuid, muscle_code, muscle_role FROM exercise_muscle").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "muscle_code", "muscle_role"}).
+			AddRow(squat, "QUAD", "primary").
+			AddRow(squat, "GLUTE", "secondary"))
+	mock.ExpectQuery("SELECT exercise_uuid, apparatus_code FROM exercise_apparatus").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "apparatus_code"}).
+			AddRow(squat, "BARBELL"))
+
+	exercises, err := dao.ListExercises(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, exercises, 2)
+	assert.Equal(t, "Push-up", exercises[0].ExerciseName)
+	assert.Empty(t, exercises[0].Muscles)
+	assert.Empty(t, exercises[0].Apparatus)
+	assert.Equal(t, "CC_BY", exercises[1].LicenseShortName)
+	assert.Equal(t, []model.ExerciseMuscle{
+		{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary},
+		{MuscleCode: "GLUTE", MuscleRole: model.MuscleRoleSecondary},
+	}, exercises[1].Muscles)
+	assert.Equal(t, []string{"BARBELL"}, exercises[1].Apparatus)
+}
+
+func TestListExercises_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT exercise_uuid, exercise_name").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name"}))
+	mock.ExpectQuery("SELECT exercise_uuid, muscle_code").WillReturnError(sqlmock.ErrCancelled)
+
+	exercises, err := dao.ListExercises(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, exercises)
+}
+
+func TestListExerciseNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCatalogDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT exercise_uuid, exercise_name FROM exercise ORDER BY exercise_name").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name"}).AddRow(exUuid, "Squat"))
+
+	names, err := dao.ListExerciseNames(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseName{{ExerciseUuid: exUuid, ExerciseName: "Squat"}}, names)
+}
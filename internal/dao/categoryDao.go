@@ -6,16 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pwydra/shred/internal/model"
 )
 
 type CategoryDaoInterface interface {
-	CreateCategory(catReq *model.CategoryRequest) (model.Category, error)
-	GetCategoryByCode(code string) (*model.Category, error)
-	UpdateCategory(catReq *model.CategoryRequest) error
-	DeleteCategory(code string) error
+	CreateCategory(ctx context.Context, catReq *model.CategoryRequest) (model.Category, error)
+	GetCategoryByCode(ctx context.Context, code string) (*model.Category, error)
+	GetAllCategories(ctx context.Context) ([]model.Category, error)
+	UpdateCategory(ctx context.Context, catReq *model.CategoryRequest) error
+	DeleteCategory(ctx context.Context, code string) error
 }
 
 // Ensure ExerciseDao implements ExerciseDaoInterface
@@ -44,9 +46,10 @@ const getCatByCodeDQL string = `
 	FROM category_type
 	WHERE category_code = $1`
 
-func (dao *CategoryDAO) GetCategoryByCode(catCode string) (*model.Category, error) {
+func (dao *CategoryDAO) GetCategoryByCode(ctx context.Context, catCode string) (_ *model.Category, err error) {
+	defer observe(ctx, "CategoryDAO", "GetCategoryByCode", time.Now(), &err)
 	var category model.Category
-	if err := dao.db.QueryRowx(getCatByCodeDQL, strings.ToUpper(catCode)).StructScan(&category); err != nil {
+	if err := getContext(ctx, dao.db, "getCatByCodeDQL", &category, getCatByCodeDQL, strings.ToUpper(catCode)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("category with code %s not found", strings.ToUpper(catCode))
 		}
@@ -61,9 +64,10 @@ const getAllCatsDQL string = `
 	SELECT *
 	FROM category_type`
 
-func (dao *CategoryDAO) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+func (dao *CategoryDAO) GetAllCategories(ctx context.Context) (_ []model.Category, err error) {
+	defer observe(ctx, "CategoryDAO", "GetAllCategories", time.Now(), &err)
 	var categories []model.Category
-	if err := dao.db.SelectContext(ctx, &categories, getAllCatsDQL); err != nil {
+	if err := selectContext(ctx, dao.db, "getAllCatsDQL", &categories, getAllCatsDQL); err != nil {
 		return nil, err
 	}
 
@@ -73,14 +77,15 @@ func (dao *CategoryDAO) GetAllCategories(ctx context.Context) ([]model.Category,
 // CreateCategory inserts a new category into the database.
 // Returns an error if the insertion fails.
 // Does not return the PK as type tables have PK specified by the request.
-func (dao *CategoryDAO) CreateCategory(catReq *model.CategoryRequest) (model.Category, error) {
+func (dao *CategoryDAO) CreateCategory(ctx context.Context, catReq *model.CategoryRequest) (_ model.Category, err error) {
+	defer observe(ctx, "CategoryDAO", "CreateCategory", time.Now(), &err)
 	cat := model.Category{
 		CategoryFields: catReq.CategoryFields,
 		AuditRecord:    model.AuditRecord{CreatedBy: catReq.CreatedBy},
 	}
-	err := dao.db.QueryRowx(createCatDML,
-		strings.ToUpper(catReq.CategoryCode), catReq.CategoryName,
-		catReq.CategoryDesc, catReq.CreatedBy).Scan(&cat.CreatedAt, &cat.UpdatedAt)
+	err = scanContext(ctx, dao.db, "createCatDML", createCatDML, []any{
+		strings.ToUpper(catReq.CategoryCode), catReq.CategoryName, catReq.CategoryDesc, catReq.CreatedBy,
+	}, &cat.CreatedAt, &cat.UpdatedAt)
 	if err != nil {
 		return cat, err
 	}
@@ -96,8 +101,9 @@ const updateCatDML string = `
 		category_description = $2
 	WHERE category_code = $3`
 
-func (dao *CategoryDAO) UpdateCategory(catReq *model.CategoryRequest) error {
-	result, err := dao.db.Exec(updateCatDML,
+func (dao *CategoryDAO) UpdateCategory(ctx context.Context, catReq *model.CategoryRequest) (err error) {
+	defer observe(ctx, "CategoryDAO", "UpdateCategory", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "updateCatDML", updateCatDML,
 		catReq.CategoryName, catReq.CategoryDesc, strings.ToUpper(catReq.CategoryCode))
 	if err != nil {
 		return err
@@ -120,8 +126,9 @@ const deleteCatDML string = `
 	DELETE FROM category_type
 	WHERE category_code = $1`
 
-func (dao *CategoryDAO) DeleteCategory(code string) error {
-	result, err := dao.db.Exec(deleteCatDML, strings.ToUpper(code))
+func (dao *CategoryDAO) DeleteCategory(ctx context.Context, code string) (err error) {
+	defer observe(ctx, "CategoryDAO", "DeleteCategory", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteCatDML", deleteCatDML, strings.ToUpper(code))
 	if err != nil {
 		return err
 	}
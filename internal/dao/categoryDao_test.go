@@ -27,7 +27,7 @@ func TestGetCategoryByCode(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"category_code", "category_name", "category_description"}).
 			AddRow(catCode, "Strength", "Strength training exercises"))
 
-	category, err := dao.GetCategoryByCode(catCode)
+	category, err := dao.GetCategoryByCode(context.Background(), catCode)
 	assert.NoError(t, err)
 	assert.NotNil(t, category)
 	assert.Equal(t, "Strength", category.CategoryName)
@@ -45,7 +45,7 @@ func TestGetCategoryByCode_NotFound(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnError(sql.ErrNoRows)
 
-	category, err := dao.GetCategoryByCode(catCode)
+	category, err := dao.GetCategoryByCode(context.Background(), catCode)
 	assert.Error(t, err)
 	assert.Nil(t, category)
 	assert.Equal(t, "category with code INVALID not found", err.Error())
@@ -93,7 +93,7 @@ func TestCreateCategory(t *testing.T) {
 		WithArgs(catReq.CategoryCode, catReq.CategoryName, catReq.CategoryDesc, catReq.CreatedBy).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(timeNow, timeNow))
 
-	cat, err := dao.CreateCategory(catReq)
+	cat, err := dao.CreateCategory(context.Background(), catReq)
 	assert.NoError(t, err)
 	assert.Equal(t, catReq.CategoryCode, cat.CategoryCode)
 	assert.Equal(t, catReq.CategoryName, cat.CategoryName)
@@ -123,7 +123,7 @@ func TestCreateCategory_Error(t *testing.T) {
 		WithArgs(catReq.CategoryCode, catReq.CategoryName, catReq.CategoryDesc, catReq.CreatedBy).
 		WillReturnError(errors.New("insertion error"))
 
-	_, err = dao.CreateCategory(catReq)
+	_, err = dao.CreateCategory(context.Background(), catReq)
 	assert.Error(t, err)
 	assert.Equal(t, "insertion error", err.Error())
 }
@@ -148,7 +148,7 @@ func TestUpdateCategory(t *testing.T) {
 		WithArgs(catReq.CategoryName, catReq.CategoryDesc, catReq.CategoryCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.UpdateCategory(catReq)
+	err = dao.UpdateCategory(context.Background(), catReq)
 	assert.NoError(t, err)
 }
 
@@ -171,7 +171,7 @@ func TestUpdateCategory_Error(t *testing.T) {
 		WithArgs(catReq.CategoryName, catReq.CategoryDesc, catReq.CategoryCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.UpdateCategory(catReq)
+	err = dao.UpdateCategory(context.Background(), catReq)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -195,7 +195,7 @@ func TestUpdateCategory_NotFound(t *testing.T) {
 		WithArgs(catReq.CategoryName, catReq.CategoryDesc, catReq.CategoryCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.UpdateCategory(catReq)
+	err = dao.UpdateCategory(context.Background(), catReq)
 	assert.Error(t, err)
 	assert.Equal(t, "category with Code INVALID not found", err.Error())
 }
@@ -212,7 +212,7 @@ func TestDeleteCategory(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.DeleteCategory(catCode)
+	err = dao.DeleteCategory(context.Background(), catCode)
 	assert.NoError(t, err)
 }
 
@@ -228,7 +228,7 @@ func TestDeleteCategory_Error(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.DeleteCategory(catCode)
+	err = dao.DeleteCategory(context.Background(), catCode)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -245,7 +245,7 @@ func TestDeleteCategory_NotFound(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.DeleteCategory(catCode)
+	err = dao.DeleteCategory(context.Background(), catCode)
 	assert.Error(t, err)
 	assert.Equal(t, "category with code INVALID not found", err.Error())
 }
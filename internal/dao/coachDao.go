@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CoachDao provides access to the rosters of coaches.
+type CoachDao struct {
+	db *sqlx.DB
+}
+
+type CoachDaoInterface interface {
+	AddClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error
+	RemoveClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error
+	ListClients(ctx context.Context, coachUuid uuid.UUID) ([]model.RosterClient, error)
+}
+
+// Ensure CoachDao implements CoachDaoInterface
+var _ CoachDaoInterface = (*CoachDao)(nil)
+
+// NewCoachDao creates a new instance of CoachDao.
+func NewCoachDao(db *sqlx.DB) *CoachDao {
+	return &CoachDao{db: db}
+}
+
+const (
+	// addClientDML keeps a client added before as is.
+	addClientDML string = `
+	INSERT INTO coach_client (coach_uuid, client_uuid) VALUES ($1, $2)
+	ON CONFLICT (coach_uuid, client_uuid) DO NOTHING`
+
+	removeClientDML string = `
+	DELETE FROM coach_client WHERE coach_uuid = $1 AND client_uuid = $2`
+
+	listClientsDQL string = `
+	SELECT u.user_uuid, u.first_name, u.last_name, cc.created_at
+	FROM   coach_client cc
+	JOIN   shred_user u ON u.user_uuid = cc.client_uuid
+	WHERE  cc.coach_uuid = $1
+	ORDER BY u.last_name, u.first_name, u.user_uuid`
+)
+
+func (dao *CoachDao) AddClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) (err error) {
+	defer observe(ctx, "CoachDao", "AddClient", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "addClientDML", addClientDML, coachUuid, clientUuid)
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("coach %s or client %s %w", coachUuid, clientUuid, ErrNotFound)
+	}
+	return err
+}
+
+func (dao *CoachDao) RemoveClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) (err error) {
+	defer observe(ctx, "CoachDao", "RemoveClient", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "removeClientDML", removeClientDML, coachUuid, clientUuid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("client %s of coach %s %w", clientUuid, coachUuid, ErrNotFound)
+	}
+	return nil
+}
+
+func (dao *CoachDao) ListClients(ctx context.Context, coachUuid uuid.UUID) (_ []model.RosterClient, err error) {
+	defer observe(ctx, "CoachDao", "ListClients", time.Now(), &err)
+	clients := []model.RosterClient{}
+	if err := selectContext(ctx, dao.db, "listClientsDQL", &clients, listClientsDQL, coachUuid); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
@@ -0,0 +1,74 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddClient(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCoachDao(sqlx.NewDb(db, "postgres"))
+
+	coachUuid, clientUuid := uuid.New(), uuid.New()
+	mock.ExpectExec("INSERT INTO coach_client \\(coach_uuid, client_uuid\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT").
+		WithArgs(coachUuid, clientUuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.AddClient(context.Background(), coachUuid, clientUuid))
+
+	mock.ExpectExec("INSERT INTO coach_client").
+		WillReturnError(&pq.Error{Code: "23503"})
+	err = dao.AddClient(context.Background(), coachUuid, clientUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveClient_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCoachDao(sqlx.NewDb(db, "postgres"))
+
+	coachUuid, clientUuid := uuid.New(), uuid.New()
+	mock.ExpectExec("DELETE FROM coach_client WHERE coach_uuid = \\$1 AND client_uuid = \\$2").
+		WithArgs(coachUuid, clientUuid).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = dao.RemoveClient(context.Background(), coachUuid, clientUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListClients(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCoachDao(sqlx.NewDb(db, "postgres"))
+
+	coachUuid := uuid.New()
+	added := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM coach_client cc JOIN shred_user u ON u.user_uuid = cc.client_uuid WHERE cc.coach_uuid = \\$1").
+		WithArgs(coachUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "first_name", "last_name", "created_at"}).
+			AddRow(uuid.New(), "John", "Doe", added).
+			AddRow(uuid.New(), "Mary", "Major", added))
+
+	clients, err := dao.ListClients(context.Background(), coachUuid)
+	assert.NoError(t, err)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, "Doe", clients[0].LastName)
+	assert.Equal(t, added, clients[1].AddedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
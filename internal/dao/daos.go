@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/*
+ * Daos holds a DAO of each kind and the unit of work they take part in, all
+ * backed by the same store. The service is wired from it, so it runs on
+ * Postgres, SQLite or the store of package memory alike.
+ */
+type Daos struct {
+	Exercises    ExerciseDaoInterface
+	Catalog      CatalogDaoInterface
+	Muscles      MuscleDaoInterface
+	Categories   CategoryDaoInterface
+	Apparatus    ApparatusDaoInterface
+	Licenses     LicenseDaoInterface
+	Relations    RelationDaoInterface
+	Translations TranslationDaoInterface
+	Mappings     ExerciseMappingDaoInterface
+	Users        UserDaoInterface
+	Workouts     WorkoutDaoInterface
+	Cardio       CardioDaoInterface
+	Equipment    EquipmentDaoInterface
+	Recovery     RecoveryDaoInterface
+	Planner      PlannerDaoInterface
+	Measurements MeasurementDaoInterface
+	Analytics    AnalyticsDaoInterface
+	Calendar     CalendarDaoInterface
+	Coach        CoachDaoInterface
+	UnitOfWork   UnitOfWorkInterface
+	// DB is the connection pool of the SQL DAOs, nil for the memory store.
+	DB *sql.DB
+}
+
+// NewDaos creates the DAOs on a Postgres database or one opened by
+// OpenSQLite.
+func NewDaos(db *sqlx.DB) *Daos {
+	return &Daos{
+		Exercises:    NewExerciseDao(db),
+		Catalog:      NewCatalogDao(db),
+		Muscles:      NewMuscleDAO(db),
+		Categories:   NewCategoryDAO(db),
+		Apparatus:    NewApparatusDAO(db),
+		Licenses:     NewLicenseDAO(db),
+		Relations:    NewRelationDao(db),
+		Translations: NewTranslationDao(db),
+		Mappings:     NewExerciseMappingDao(db),
+		Users:        NewUserDao(db),
+		Workouts:     NewWorkoutDao(db),
+		Cardio:       NewCardioDao(db),
+		Equipment:    NewEquipmentDao(db),
+		Recovery:     NewRecoveryDao(db),
+		Planner:      NewPlannerDao(db),
+		Measurements: NewMeasurementDao(db),
+		Analytics:    NewAnalyticsDao(db),
+		Calendar:     NewCalendarDao(db),
+		Coach:        NewCoachDao(db),
+		UnitOfWork:   NewUnitOfWork(db),
+		DB:           db.DB,
+	}
+}
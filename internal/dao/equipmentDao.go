@@ -0,0 +1,319 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// EquipmentDao provides access to the equipment profiles of users and gym
+// locations and to the exercises that can be done with them.
+type EquipmentDao struct {
+	db *sqlx.DB
+}
+
+type EquipmentDaoInterface interface {
+	CreateProfile(ctx context.Context, userUuid *uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error)
+	ListProfiles(ctx context.Context, userUuid *uuid.UUID) ([]model.EquipmentProfile, error)
+	GetProfile(ctx context.Context, profileUuid uuid.UUID) (*model.EquipmentProfile, error)
+	GetDefaultProfile(ctx context.Context, userUuid uuid.UUID) (*model.EquipmentProfile, error)
+	UpdateProfile(ctx context.Context, profileUuid uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error)
+	DeleteProfile(ctx context.Context, profileUuid uuid.UUID) error
+	FindAvailableExercises(ctx context.Context, profileUuid uuid.UUID, category string) ([]model.AvailableExercise, error)
+}
+
+// Ensure EquipmentDao implements EquipmentDaoInterface
+var _ EquipmentDaoInterface = (*EquipmentDao)(nil)
+
+// NewEquipmentDao creates a new instance of EquipmentDao.
+func NewEquipmentDao(db *sqlx.DB) *EquipmentDao {
+	return &EquipmentDao{db: db}
+}
+
+// equipmentProfileRow scans a profile with its apparatus aggregated into an
+// array.
+type equipmentProfileRow struct {
+	model.EquipmentProfile
+	Apparatus pq.StringArray `db:"apparatus"`
+}
+
+func (row equipmentProfileRow) profile() model.EquipmentProfile {
+	profile := row.EquipmentProfile
+	profile.Apparatus = []string(row.Apparatus)
+	return profile
+}
+
+const selectProfileDQL string = `
+	SELECT p.profile_uuid, p.user_uuid, p.profile_name, p.is_default,
+	       p.created_by, p.created_at, p.updated_at,
+	       COALESCE(array_agg(a.apparatus_code ORDER BY a.apparatus_code)
+	                FILTER (WHERE a.apparatus_code IS NOT NULL), '{}') AS apparatus
+	FROM      equipment_profile p
+	LEFT JOIN equipment_profile_apparatus a ON a.profile_uuid = p.profile_uuid`
+
+const (
+	getProfileDQL string = selectProfileDQL + `
+	WHERE  p.profile_uuid = $1
+	GROUP BY p.profile_uuid`
+
+	getDefaultProfileDQL string = selectProfileDQL + `
+	WHERE  p.user_uuid = $1 AND p.is_default
+	GROUP BY p.profile_uuid`
+
+	// listProfilesDQL lists the profiles of user $1, or the gym locations
+	// when $1 is NULL.
+	listProfilesDQL string = selectProfileDQL + `
+	WHERE  p.user_uuid IS NOT DISTINCT FROM $1
+	GROUP BY p.profile_uuid
+	ORDER BY p.is_default DESC, p.profile_name`
+)
+
+const (
+	clearDefaultProfileDML string = `
+	UPDATE equipment_profile SET is_default = FALSE
+	WHERE  user_uuid = $1 AND is_default AND profile_uuid <> $2`
+
+	createProfileDML string = `
+	INSERT INTO equipment_profile (profile_uuid, user_uuid, profile_name, is_default, created_by)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING created_at, updated_at`
+
+	lockProfileDQL string = `
+	SELECT user_uuid, created_by, created_at FROM equipment_profile WHERE profile_uuid = $1 FOR UPDATE`
+
+	updateProfileDML string = `
+	UPDATE equipment_profile
+	SET    profile_name = $1, is_default = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE  profile_uuid = $3
+	RETURNING updated_at`
+
+	deleteProfileApparatusDML string = `
+	DELETE FROM equipment_profile_apparatus WHERE profile_uuid = $1`
+
+	createProfileApparatusDML string = `
+	INSERT INTO equipment_profile_apparatus (profile_uuid, apparatus_code)
+	SELECT $1, unnest($2::varchar[])`
+
+	deleteProfileDML string = `
+	DELETE FROM equipment_profile WHERE profile_uuid = $1`
+)
+
+// CreateProfile creates a profile for the user, or a gym location when
+// userUuid is nil. A new default profile replaces the previous default.
+func (dao *EquipmentDao) CreateProfile(ctx context.Context, userUuid *uuid.UUID, req *model.EquipmentProfileRequest) (_ *model.EquipmentProfile, err error) {
+	defer observe(ctx, "EquipmentDao", "CreateProfile", time.Now(), &err)
+	if userUuid == nil && req.IsDefault {
+		return nil, fmt.Errorf("a gym location cannot be a default profile: %w", ErrConflict)
+	}
+
+	profile := model.EquipmentProfile{
+		ProfileUuid:            uuid.New(),
+		UserUuid:               userUuid,
+		EquipmentProfileFields: req.EquipmentProfileFields,
+		AuditRecord:            model.AuditRecord{CreatedBy: req.CreatedBy},
+	}
+	profile.Apparatus = ApparatusCodes(req.Apparatus)
+
+	err = transaction(ctx, dao.db, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		if req.IsDefault {
+			if _, err := execContext(ctx, tx, "clearDefaultProfileDML", clearDefaultProfileDML, userUuid, profile.ProfileUuid); err != nil {
+				return err
+			}
+		}
+		err := scanContext(ctx, tx, "createProfileDML", createProfileDML, []any{
+			profile.ProfileUuid, userUuid, profile.ProfileName, profile.IsDefault, req.CreatedBy,
+		}, &profile.CreatedAt, &profile.UpdatedAt)
+		if err != nil {
+			return profileError(err, profile.ProfileName)
+		}
+		return insertProfileApparatus(ctx, tx, profile.ProfileUuid, profile.Apparatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (dao *EquipmentDao) ListProfiles(ctx context.Context, userUuid *uuid.UUID) (_ []model.EquipmentProfile, err error) {
+	defer observe(ctx, "EquipmentDao", "ListProfiles", time.Now(), &err)
+	var rows []equipmentProfileRow
+	if err := selectContext(ctx, dao.db, "listProfilesDQL", &rows, listProfilesDQL, userUuid); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]model.EquipmentProfile, len(rows))
+	for i, row := range rows {
+		profiles[i] = row.profile()
+	}
+	return profiles, nil
+}
+
+func (dao *EquipmentDao) GetProfile(ctx context.Context, profileUuid uuid.UUID) (_ *model.EquipmentProfile, err error) {
+	defer observe(ctx, "EquipmentDao", "GetProfile", time.Now(), &err)
+	var row equipmentProfileRow
+	if err := getContext(ctx, dao.db, "getProfileDQL", &row, getProfileDQL, profileUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("equipment profile %s %w", profileUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	profile := row.profile()
+	return &profile, nil
+}
+
+func (dao *EquipmentDao) GetDefaultProfile(ctx context.Context, userUuid uuid.UUID) (_ *model.EquipmentProfile, err error) {
+	defer observe(ctx, "EquipmentDao", "GetDefaultProfile", time.Now(), &err)
+	var row equipmentProfileRow
+	if err := getContext(ctx, dao.db, "getDefaultProfileDQL", &row, getDefaultProfileDQL, userUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("default equipment profile of user %s %w", userUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	profile := row.profile()
+	return &profile, nil
+}
+
+// UpdateProfile renames a profile and replaces its apparatus.
+func (dao *EquipmentDao) UpdateProfile(ctx context.Context, profileUuid uuid.UUID, req *model.EquipmentProfileRequest) (_ *model.EquipmentProfile, err error) {
+	defer observe(ctx, "EquipmentDao", "UpdateProfile", time.Now(), &err)
+	profile := model.EquipmentProfile{
+		ProfileUuid:            profileUuid,
+		EquipmentProfileFields: req.EquipmentProfileFields,
+	}
+	profile.Apparatus = ApparatusCodes(req.Apparatus)
+
+	err = transaction(ctx, dao.db, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		err := scanContext(ctx, tx, "lockProfileDQL", lockProfileDQL,
+			[]any{profileUuid}, &profile.UserUuid, &profile.CreatedBy, &profile.CreatedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("equipment profile %s %w", profileUuid, ErrNotFound)
+		case err != nil:
+			return err
+		}
+		if req.IsDefault {
+			if profile.UserUuid == nil {
+				return fmt.Errorf("a gym location cannot be a default profile: %w", ErrConflict)
+			}
+			if _, err := execContext(ctx, tx, "clearDefaultProfileDML", clearDefaultProfileDML, profile.UserUuid, profileUuid); err != nil {
+				return err
+			}
+		}
+		if err := scanContext(ctx, tx, "updateProfileDML", updateProfileDML,
+			[]any{profile.ProfileName, profile.IsDefault, profileUuid}, &profile.UpdatedAt); err != nil {
+			return profileError(err, profile.ProfileName)
+		}
+		if _, err := execContext(ctx, tx, "deleteProfileApparatusDML", deleteProfileApparatusDML, profileUuid); err != nil {
+			return err
+		}
+		return insertProfileApparatus(ctx, tx, profileUuid, profile.Apparatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (dao *EquipmentDao) DeleteProfile(ctx context.Context, profileUuid uuid.UUID) (err error) {
+	defer observe(ctx, "EquipmentDao", "DeleteProfile", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteProfileDML", deleteProfileDML, profileUuid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("equipment profile %s %w", profileUuid, ErrNotFound)
+	}
+	return nil
+}
+
+// FindAvailableExercises returns the exercises, optionally of category $2,
+// whose apparatus are all in profile $1. Exercises without apparatus need
+// nothing and are always available.
+const profileExistsDQL string = `
+	SELECT EXISTS (SELECT 1 FROM equipment_profile WHERE profile_uuid = $1)`
+
+const findAvailableExDQL string = `
+	SELECT e.exercise_uuid, e.exercise_name, e.category_code,
+	       COALESCE(array_agg(ea.apparatus_code ORDER BY ea.apparatus_code)
+	                FILTER (WHERE ea.apparatus_code IS NOT NULL), '{}') AS apparatus
+	FROM      exercise e
+	LEFT JOIN exercise_apparatus ea ON ea.exercise_uuid = e.exercise_uuid
+	WHERE     $2 = '' OR e.category_code = $2
+	GROUP BY  e.exercise_uuid
+	HAVING    bool_and(ea.apparatus_code IS NULL OR ea.apparatus_code IN (
+		SELECT apparatus_code FROM equipment_profile_apparatus WHERE profile_uuid = $1))
+	ORDER BY  e.exercise_name`
+
+func (dao *EquipmentDao) FindAvailableExercises(ctx context.Context, profileUuid uuid.UUID, category string) (_ []model.AvailableExercise, err error) {
+	defer observe(ctx, "EquipmentDao", "FindAvailableExercises", time.Now(), &err)
+	var exists bool
+	if err := scanContext(ctx, dao.db, "profileExistsDQL", profileExistsDQL, []any{profileUuid}, &exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("equipment profile %s %w", profileUuid, ErrNotFound)
+	}
+
+	var rows []struct {
+		model.AvailableExercise
+		Apparatus pq.StringArray `db:"apparatus"`
+	}
+	if err := selectContext(ctx, dao.db, "findAvailableExDQL", &rows, findAvailableExDQL, profileUuid, strings.ToUpper(category)); err != nil {
+		return nil, err
+	}
+
+	exercises := make([]model.AvailableExercise, len(rows))
+	for i, row := range rows {
+		exercises[i] = row.AvailableExercise
+		exercises[i].Apparatus = []string(row.Apparatus)
+	}
+	return exercises, nil
+}
+
+func insertProfileApparatus(ctx context.Context, tx *sqlx.Tx, profileUuid uuid.UUID, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	_, err := execContext(ctx, tx, "createProfileApparatusDML", createProfileApparatusDML, profileUuid, pq.Array(codes))
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("apparatus in %s %w", strings.Join(codes, ", "), ErrNotFound)
+	}
+	return err
+}
+
+// profileError maps the violations of creating or renaming a profile.
+func profileError(err error, name string) error {
+	switch {
+	case isUniqueViolation(err):
+		return fmt.Errorf("equipment profile '%s' %w", name, ErrConflict)
+	case isForeignKeyViolation(err):
+		return fmt.Errorf("user %w", ErrNotFound)
+	}
+	return err
+}
+
+// ApparatusCodes upper-cases, sorts and de-duplicates apparatus codes.
+func ApparatusCodes(codes []string) []string {
+	upper := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			upper = append(upper, code)
+		}
+	}
+	slices.Sort(upper)
+	return slices.Compact(upper)
+}
@@ -0,0 +1,223 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var profileColumns = []string{"profile_uuid", "user_uuid", "profile_name", "is_default",
+	"created_by", "created_at", "updated_at", "apparatus"}
+
+func TestCreateProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, createdBy := uuid.New(), uuid.New()
+	req := &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{
+			ProfileName: "Home", IsDefault: true, Apparatus: []string{"kettlebell", "BARBELL", "Barbell"}},
+		CreatedBy: createdBy,
+	}
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE equipment_profile SET is_default = FALSE").
+		WithArgs(&userUuid, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO equipment_profile \\(profile_uuid, user_uuid, profile_name, is_default, created_by\\)").
+		WithArgs(sqlmock.AnyArg(), &userUuid, "Home", true, createdBy).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectExec("INSERT INTO equipment_profile_apparatus .* unnest").
+		WithArgs(sqlmock.AnyArg(), pq.Array([]string{"BARBELL", "KETTLEBELL"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	profile, err := dao.CreateProfile(context.Background(), &userUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BARBELL", "KETTLEBELL"}, profile.Apparatus)
+	assert.Equal(t, &userUuid, profile.UserUuid)
+	assert.Equal(t, now, profile.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateProfile_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	req := &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{ProfileName: "Garage", Apparatus: []string{"SLED"}},
+		CreatedBy:              uuid.New(),
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO equipment_profile").WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO equipment_profile").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO equipment_profile_apparatus").WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	_, err = dao.CreateProfile(context.Background(), nil, req)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	_, err = dao.CreateProfile(context.Background(), nil, req)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	req.IsDefault = true
+	_, err = dao.CreateProfile(context.Background(), nil, req)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListProfiles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	mock.ExpectQuery("FROM equipment_profile p .* WHERE p.user_uuid IS NOT DISTINCT FROM \\$1").
+		WithArgs(&userUuid).
+		WillReturnRows(sqlmock.NewRows(profileColumns).
+			AddRow(uuid.New(), userUuid, "Home", true, uuid.New(), time.Now(), time.Now(), "{BARBELL,KETTLEBELL}").
+			AddRow(uuid.New(), userUuid, "Travel", false, uuid.New(), time.Now(), time.Now(), "{}"))
+
+	profiles, err := dao.ListProfiles(context.Background(), &userUuid)
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.Equal(t, []string{"BARBELL", "KETTLEBELL"}, profiles[0].Apparatus)
+	assert.Empty(t, profiles[1].Apparatus)
+}
+
+func TestGetProfile_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("WHERE p.profile_uuid = \\$1").WillReturnRows(sqlmock.NewRows(profileColumns))
+	mock.ExpectQuery("WHERE p.user_uuid = \\$1 AND p.is_default").WillReturnRows(sqlmock.NewRows(profileColumns))
+
+	_, err = dao.GetProfile(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = dao.GetDefaultProfile(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	profileUuid, userUuid, createdBy := uuid.New(), uuid.New(), uuid.New()
+	req := &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{ProfileName: "Home", IsDefault: true},
+		CreatedBy:              uuid.New(),
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_uuid, created_by, created_at FROM equipment_profile .* FOR UPDATE").
+		WithArgs(profileUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "created_by", "created_at"}).AddRow(userUuid, createdBy, time.Now()))
+	mock.ExpectExec("UPDATE equipment_profile SET is_default = FALSE").
+		WithArgs(&userUuid, profileUuid).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("UPDATE equipment_profile SET profile_name = \\$1, is_default = \\$2").
+		WithArgs("Home", true, profileUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	mock.ExpectExec("DELETE FROM equipment_profile_apparatus").
+		WithArgs(profileUuid).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	profile, err := dao.UpdateProfile(context.Background(), profileUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, createdBy, profile.CreatedBy)
+	assert.Empty(t, profile.Apparatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProfile_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	req := &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{ProfileName: "Downtown", IsDefault: true},
+		CreatedBy:              uuid.New(),
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "created_by", "created_at"}))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "created_by", "created_at"}).AddRow(nil, uuid.New(), time.Now()))
+	mock.ExpectRollback()
+
+	_, err = dao.UpdateProfile(context.Background(), uuid.New(), req)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = dao.UpdateProfile(context.Background(), uuid.New(), req)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec("DELETE FROM equipment_profile WHERE").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM equipment_profile WHERE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, dao.DeleteProfile(context.Background(), uuid.New()))
+	assert.ErrorIs(t, dao.DeleteProfile(context.Background(), uuid.New()), ErrNotFound)
+}
+
+func TestFindAvailableExercises(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewEquipmentDao(sqlx.NewDb(db, "postgres"))
+
+	profileUuid := uuid.New()
+	mock.ExpectQuery("SELECT EXISTS").WithArgs(profileUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("HAVING bool_and\\(ea.apparatus_code IS NULL OR ea.apparatus_code IN").
+		WithArgs(profileUuid, "STRENGTH").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "category_code", "apparatus"}).
+			AddRow(uuid.New(), "Back Squat", "STRENGTH", "{BARBELL}").
+			AddRow(uuid.New(), "Push-up", "STRENGTH", "{}"))
+
+	exercises, err := dao.FindAvailableExercises(context.Background(), profileUuid, "strength")
+	assert.NoError(t, err)
+	assert.Len(t, exercises, 2)
+	assert.Equal(t, []string{"BARBELL"}, exercises[0].Apparatus)
+	assert.Empty(t, exercises[1].Apparatus)
+
+	mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	_, err = dao.FindAvailableExercises(context.Background(), uuid.New(), "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ErrNotFound is wrapped by lookups that found no row, so callers can tell a
+// missing row from a failing database with errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is wrapped when a row to be created already exists, or when
+// rows that refer to a row to be deleted exist.
+var ErrConflict = errors.New("already exists")
+
+// ErrInvalidHierarchy is wrapped when a muscle would end up below a muscle of
+// the same or a lower level, e.g. a region inside a group.
+var ErrInvalidHierarchy = errors.New("invalid muscle hierarchy")
+
+// Postgres error codes of a missing referenced row and of a duplicate key,
+// and of transactions that lost against concurrent ones.
+const (
+	foreignKeyViolation  = "23503"
+	uniqueViolation      = "23505"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == foreignKeyViolation
+	}
+	return sqliteCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == uniqueViolation
+	}
+	code := sqliteCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// sqliteCode returns the extended result code of an SQLite error, or 0.
+func sqliteCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+// isRetryable reports whether err ended a transaction that may succeed when
+// run again.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected)
+}
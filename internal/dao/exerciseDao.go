@@ -1,7 +1,12 @@
 package dao
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -14,10 +19,14 @@ type ExerciseDao struct {
 }
 
 type ExerciseDaoInterface interface {
-	Create(exerciseRequest *model.ExerciseRequest) (*model.Exercise, error)
-	Read(uuid uuid.UUID) (*model.Exercise, error)
-	Update(exercise *model.Exercise) error
-	Delete(uuid uuid.UUID) error
+	Create(ctx context.Context, exerciseRequest *model.ExerciseRequest) (*model.Exercise, error)
+	Read(ctx context.Context, uuid uuid.UUID) (*model.Exercise, error)
+	Update(ctx context.Context, exercise *model.Exercise) error
+	Delete(ctx context.Context, uuid uuid.UUID) error
+	AddMuscles(ctx context.Context, exUuid uuid.UUID, muscles []model.ExerciseMuscle) error
+	AddApparatus(ctx context.Context, exUuid uuid.UUID, codes []string) error
+	RemoveMuscles(ctx context.Context, exUuid uuid.UUID) error
+	RemoveApparatus(ctx context.Context, exUuid uuid.UUID) error
 }
 
 // Ensure ExerciseDao implements ExerciseDaoInterface
@@ -32,7 +41,7 @@ const createDML string = `
 		exercise_name, exercise_description, instructions, cues, 
 		video_url, category_code, license_short_name, license_author, 
 		created_by
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING uuid, created_at, updated_at`
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING exercise_uuid, created_at, updated_at`
 
 const updateDML string = `
 	UPDATE exercise SET 
@@ -48,58 +57,119 @@ const updateDML string = `
 
 const deleteDML string = "DELETE FROM exercise WHERE exercise_uuid = $1"
 
+// request1DQL reads an exercise without a license as one with an empty
+// license short name, which Create and Update store as NULL.
 const request1DQL string = `
-	SELECT * 
-	FROM   exercise 
+	SELECT exercise_uuid, exercise_name, exercise_description, instructions, cues,
+	       video_url, category_code, COALESCE(license_short_name, '') AS license_short_name,
+	       license_author, created_by, created_at, updated_at
+	FROM   exercise
 	WHERE  exercise_uuid = $1`
 
 // TODO: add query for all exercises
 // var requestAllDQL string = "SELECT uuid, exercise_name, description cues, primary_muscles, apparatus, created_at, user_uuid FROM exercises"
 
-func (dao *ExerciseDao) Create(exReq *model.ExerciseRequest) (*model.Exercise, error) {
+func (dao *ExerciseDao) Create(ctx context.Context, exReq *model.ExerciseRequest) (_ *model.Exercise, err error) {
+	defer observe(ctx, "ExerciseDao", "Create", time.Now(), &err)
 	exercise := model.Exercise{
 		ExerciseFields: exReq.ExerciseFields,
 	}
 
-	err := dao.db.QueryRowx(createDML,
-		exReq.ExerciseName, exReq.Description, exReq.Instructions, exReq.Cues,
-		exReq.VideoUrl, exReq.CategoryCode, exReq.LicenseShortName, exReq.LicenseAuthor,
-		exReq.CreatedBy).Scan(&exercise.ExerciseUuid, &exercise.CreatedAt, &exercise.UpdatedAt)
+	err = scanContext(ctx, dao.db, "createDML", createDML, []any{
+		exReq.ExerciseName, exReq.Description, exReq.Instructions, exReq.Cues, exReq.VideoUrl, exReq.CategoryCode,
+		nullIfEmpty(exReq.LicenseShortName), exReq.LicenseAuthor, exReq.CreatedBy,
+	}, &exercise.ExerciseUuid, &exercise.CreatedAt, &exercise.UpdatedAt)
 	if err != nil {
-		log.Println("Error creating exercise:", err)
 		return nil, err
 	}
 	return &exercise, nil
 }
 
-func (dao *ExerciseDao) Read(exUuid uuid.UUID) (*model.Exercise, error) {
+// Read returns the exercise with the given UUID. A missing exercise wraps
+// ErrNotFound.
+func (dao *ExerciseDao) Read(ctx context.Context, exUuid uuid.UUID) (_ *model.Exercise, err error) {
+	defer observe(ctx, "ExerciseDao", "Read", time.Now(), &err)
 	var ex model.Exercise
-	err := dao.db.QueryRowx(request1DQL, exUuid).StructScan(&ex)
+	err = getContext(ctx, dao.db, "request1DQL", &ex, request1DQL, exUuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("exercise with uuid %s %w", exUuid, ErrNotFound)
+	}
 	if err != nil {
-		log.Println("Error reading exercise:", err)
 		return nil, err
 	}
 	return &ex, nil
 }
 
-func (dao *ExerciseDao) Update(exercise *model.Exercise) error {
-	_, err := dao.db.Exec(updateDML,
+func (dao *ExerciseDao) Update(ctx context.Context, exercise *model.Exercise) (err error) {
+	defer observe(ctx, "ExerciseDao", "Update", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "updateDML", updateDML,
 		exercise.ExerciseName, exercise.Description, exercise.Instructions, exercise.Cues,
-		exercise.VideoUrl, exercise.CategoryCode, exercise.LicenseShortName,
+		exercise.VideoUrl, exercise.CategoryCode, nullIfEmpty(exercise.LicenseShortName),
 		exercise.LicenseAuthor, exercise.ExerciseUuid)
 	if err != nil {
-		log.Println("Error updating exercise:", err)
 		return err
 	}
 	// TODO: return updated exercise
 	return nil
 }
 
-func (dao *ExerciseDao) Delete(uuid uuid.UUID) error {
-	_, err := dao.db.Exec(deleteDML, uuid)
+// Delete deletes an exercise. Sets and app mappings that still refer to it
+// wrap ErrConflict.
+func (dao *ExerciseDao) Delete(ctx context.Context, uuid uuid.UUID) (err error) {
+	defer observe(ctx, "ExerciseDao", "Delete", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "deleteDML", deleteDML, uuid)
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("a set or app mapping of exercise %s %w", uuid, ErrConflict)
+	}
 	if err != nil {
-		log.Println("Error deleting exercise:", err)
 		return err
 	}
 	return nil
 }
+
+// AddMuscles links muscles to an exercise. An unknown muscle wraps
+// ErrNotFound.
+func (dao *ExerciseDao) AddMuscles(ctx context.Context, exUuid uuid.UUID, muscles []model.ExerciseMuscle) (err error) {
+	defer observe(ctx, "ExerciseDao", "AddMuscles", time.Now(), &err)
+	for _, mus := range muscles {
+		_, err := execContext(ctx, dao.db, "createExMuscleDML", createExMuscleDML,
+			exUuid, strings.ToUpper(mus.MuscleCode), mus.MuscleRole)
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("exercise %s or muscle %s %w", exUuid, mus.MuscleCode, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddApparatus links apparatus to an exercise. An unknown apparatus wraps
+// ErrNotFound.
+func (dao *ExerciseDao) AddApparatus(ctx context.Context, exUuid uuid.UUID, codes []string) (err error) {
+	defer observe(ctx, "ExerciseDao", "AddApparatus", time.Now(), &err)
+	for _, code := range codes {
+		_, err := execContext(ctx, dao.db, "createExApparatusDML", createExApparatusDML, exUuid, strings.ToUpper(code))
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("exercise %s or apparatus %s %w", exUuid, code, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveMuscles unlinks all muscles from an exercise.
+func (dao *ExerciseDao) RemoveMuscles(ctx context.Context, exUuid uuid.UUID) (err error) {
+	defer observe(ctx, "ExerciseDao", "RemoveMuscles", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "deleteExMusclesDML", deleteExMusclesDML, exUuid)
+	return err
+}
+
+// RemoveApparatus unlinks all apparatus from an exercise.
+func (dao *ExerciseDao) RemoveApparatus(ctx context.Context, exUuid uuid.UUID) (err error) {
+	defer observe(ctx, "ExerciseDao", "RemoveApparatus", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "deleteExApparatusDML", deleteExApparatusDML, exUuid)
+	return err
+}
@@ -1,12 +1,14 @@
 package dao
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/pwydra/shred/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -40,7 +42,7 @@ func TestCreateExercise(t *testing.T) {
 			exReq.CreatedBy).
 		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "created_at", "updated_at"}).AddRow(uuid.New(), time.Now(), time.Now()))
 
-	ex, err := dao.Create(exReq)
+	ex, err := dao.Create(context.Background(), exReq)
 	assert.NoError(t, err)
 	assert.NotNil(t, ex)
 	assert.Equal(t, exReq.ExerciseName, ex.ExerciseName)
@@ -74,7 +76,7 @@ func TestCreateExercise_Error(t *testing.T) {
 			exReq.CreatedBy).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	ex, err := dao.Create(exReq)
+	ex, err := dao.Create(context.Background(), exReq)
 	assert.Error(t, err)
 	assert.Nil(t, ex)
 	assert.Equal(t, "canceling query due to user request", err.Error())
@@ -100,7 +102,7 @@ func TestReadExercise(t *testing.T) {
 				"Keep chest up and back flat", "https://squat.mp4", "Strength", "MIT",
 				"John Doe", uuid.New(), createdAt, createdAt))
 
-	ex, err := dao.Read(exUuid)
+	ex, err := dao.Read(context.Background(), exUuid)
 	assert.NoError(t, err)
 	assert.NotNil(t, ex)
 	assert.Equal(t, "Squat", ex.ExerciseName)
@@ -119,7 +121,7 @@ func TestReadExercise_Error(t *testing.T) {
 		WithArgs(exUuid).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	ex, err := dao.Read(exUuid)
+	ex, err := dao.Read(context.Background(), exUuid)
 	assert.Error(t, err)
 	assert.Nil(t, ex)
 	assert.Equal(t, "canceling query due to user request", err.Error())
@@ -154,7 +156,7 @@ func TestUpdateExercise(t *testing.T) {
 			ex.ExerciseUuid).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.Update(ex)
+	err = dao.Update(context.Background(), ex)
 	assert.NoError(t, err)
 }
 
@@ -187,11 +189,27 @@ func TestUpdateExercise_Error(t *testing.T) {
 			ex.ExerciseUuid).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.Update(ex)
+	err = dao.Update(context.Background(), ex)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
 
+func TestReadExercise_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT.*FROM exercise WHERE exercise_uuid =.*").
+		WithArgs(exUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid"}))
+
+	_, err = dao.Read(context.Background(), exUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestDeleteExercise(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -204,7 +222,7 @@ func TestDeleteExercise(t *testing.T) {
 		WithArgs(exUuid).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.Delete(exUuid)
+	err = dao.Delete(context.Background(), exUuid)
 	assert.NoError(t, err)
 }
 
@@ -220,7 +238,23 @@ func TestDeleteExercise_Error(t *testing.T) {
 		WithArgs(exUuid).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.Delete(exUuid)
+	err = dao.Delete(context.Background(), exUuid)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
+
+func TestDeleteExercise_InUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectExec("DELETE FROM exercise WHERE exercise_uuid =.*").
+		WithArgs(exUuid).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = dao.Delete(context.Background(), exUuid)
+	assert.ErrorIs(t, err, ErrConflict)
+}
@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ExerciseMappingDao provides access to the manual mappings from exercise
+// names used by other tracking applications to the catalog.
+type ExerciseMappingDao struct {
+	db *sqlx.DB
+}
+
+type ExerciseMappingDaoInterface interface {
+	GetMappings(ctx context.Context, source string) ([]model.ExerciseMapping, error)
+	SaveMapping(ctx context.Context, mapping *model.ExerciseMapping) error
+	DeleteMapping(ctx context.Context, source, externalName string) error
+}
+
+// Ensure ExerciseMappingDao implements ExerciseMappingDaoInterface
+var _ ExerciseMappingDaoInterface = (*ExerciseMappingDao)(nil)
+
+// NewExerciseMappingDao creates a new instance of ExerciseMappingDao.
+func NewExerciseMappingDao(db *sqlx.DB) *ExerciseMappingDao {
+	return &ExerciseMappingDao{db: db}
+}
+
+// GetMappings retrieves all mappings for a source application.
+const getMappingsDQL string = `
+	SELECT source, external_name, exercise_uuid, created_by
+	FROM   exercise_name_mapping
+	WHERE  source = $1
+	ORDER BY external_name`
+
+func (dao *ExerciseMappingDao) GetMappings(ctx context.Context, source string) (_ []model.ExerciseMapping, err error) {
+	defer observe(ctx, "ExerciseMappingDao", "GetMappings", time.Now(), &err)
+	mappings := []model.ExerciseMapping{}
+	if err := selectContext(ctx, dao.db, "getMappingsDQL", &mappings, getMappingsDQL, strings.ToLower(source)); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// SaveMapping creates a mapping or points an existing one at another
+// exercise. External names are matched case-insensitively.
+const saveMappingDML string = `
+	INSERT INTO exercise_name_mapping (
+		source, external_name, exercise_uuid, created_by
+	) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (source, external_name) DO UPDATE SET
+		exercise_uuid = EXCLUDED.exercise_uuid,
+		updated_at = CURRENT_TIMESTAMP`
+
+func (dao *ExerciseMappingDao) SaveMapping(ctx context.Context, mapping *model.ExerciseMapping) (err error) {
+	defer observe(ctx, "ExerciseMappingDao", "SaveMapping", time.Now(), &err)
+	mapping.Source = strings.ToLower(mapping.Source)
+	mapping.ExternalName = strings.ToLower(strings.TrimSpace(mapping.ExternalName))
+	_, err = execContext(ctx, dao.db, "saveMappingDML", saveMappingDML,
+		mapping.Source, mapping.ExternalName, mapping.ExerciseUuid, mapping.CreatedBy)
+	return err
+}
+
+// DeleteMapping removes a mapping.
+const deleteMappingDML string = `
+	DELETE FROM exercise_name_mapping
+	WHERE source = $1 AND external_name = $2`
+
+func (dao *ExerciseMappingDao) DeleteMapping(ctx context.Context, source, externalName string) (err error) {
+	defer observe(ctx, "ExerciseMappingDao", "DeleteMapping", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteMappingDML", deleteMappingDML,
+		strings.ToLower(source), strings.ToLower(strings.TrimSpace(externalName)))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("mapping for %s exercise '%s' not found", strings.ToLower(source), externalName)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMappings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseMappingDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid, createdBy := uuid.New(), uuid.New()
+	mock.ExpectQuery("SELECT source, external_name, exercise_uuid, created_by FROM exercise_name_mapping WHERE source = \\$1").
+		WithArgs("strong").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "external_name", "exercise_uuid", "created_by"}).
+			AddRow("strong", "squat (barbell)", exUuid, createdBy))
+
+	mappings, err := dao.GetMappings(context.Background(), "Strong")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseMapping{{Source: "strong", ExternalName: "squat (barbell)", ExerciseUuid: exUuid, CreatedBy: createdBy}}, mappings)
+}
+
+func TestGetMappings_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseMappingDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT source").WillReturnError(sqlmock.ErrCancelled)
+
+	mappings, err := dao.GetMappings(context.Background(), "strong")
+	assert.Error(t, err)
+	assert.Nil(t, mappings)
+}
+
+func TestSaveMapping(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseMappingDao(sqlx.NewDb(db, "postgres"))
+
+	mapping := &model.ExerciseMapping{Source: "Hevy", ExternalName: " Squat (Barbell) ", ExerciseUuid: uuid.New(), CreatedBy: uuid.New()}
+	mock.ExpectExec("INSERT INTO exercise_name_mapping .* ON CONFLICT \\(source, external_name\\) DO UPDATE").
+		WithArgs("hevy", "squat (barbell)", mapping.ExerciseUuid, mapping.CreatedBy).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = dao.SaveMapping(context.Background(), mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "squat (barbell)", mapping.ExternalName)
+}
+
+func TestDeleteMapping(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseMappingDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec("DELETE FROM exercise_name_mapping WHERE source = \\$1 AND external_name = \\$2").
+		WithArgs("strong", "squat (barbell)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = dao.DeleteMapping(context.Background(), "strong", "Squat (Barbell)")
+	assert.NoError(t, err)
+}
+
+func TestDeleteMapping_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseMappingDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec("DELETE FROM exercise_name_mapping").
+		WithArgs("strong", "plank").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = dao.DeleteMapping(context.Background(), "strong", "Plank")
+	assert.EqualError(t, err, "mapping for strong exercise 'Plank' not found")
+}
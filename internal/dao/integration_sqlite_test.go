@@ -0,0 +1,20 @@
+//go:build !integration
+
+package dao
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// openIntegrationDB opens a new SQLite database, removed when the test
+// ends.
+func openIntegrationDB(t *testing.T) *sqlx.DB {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "shred.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
@@ -0,0 +1,573 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/dao/pgtest"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newIntegrationDaos returns the DAOs on an empty test database, a new
+ * SQLite database or, with the integration build tag, the Postgres database
+ * in TEST_POSTGRES_DSN (see openIntegrationDB). The database holds a
+ * user, the STRENGTH and CARDIO categories, a license, the muscles
+ * LEGS > QUADS > RECTUS and LEGS > GLUTES and the BARBELL and BENCH
+ * apparatus.
+ */
+func newIntegrationDaos(t *testing.T) (*Daos, uuid.UUID) {
+	db := openIntegrationDB(t)
+	daos := NewDaos(db)
+	userUuid := pgtest.CreateUser(t, db, "Admin")
+	ctx := context.Background()
+
+	for _, code := range []string{"strength", "cardio"} {
+		_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+			CategoryFields: model.CategoryFields{CategoryCode: code, CategoryName: code}, CreatedBy: userUuid})
+		require.NoError(t, err)
+	}
+	require.NoError(t, daos.Licenses.CreateLicense(ctx, &model.LicenseRequest{
+		LicenseFields: model.LicenseFields{LicenseShortName: "CC-BY", LicenseFullName: "Creative Commons Attribution",
+			LicenseUrl: "https://creativecommons.org/licenses/by/4.0/"}, CreatedBy: userUuid}))
+
+	legs, quads := "LEGS", "QUADS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleGroup: "Legs", ParentCode: &legs},
+		{MuscleCode: "RECTUS", MuscleName: "Rectus femoris", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelHead, ParentCode: &quads},
+		{MuscleCode: "GLUTES", MuscleName: "Glutes", MuscleGroup: "Legs", ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus, CreatedBy: userUuid})
+		require.NoError(t, err)
+	}
+	for _, code := range []string{"BARBELL", "BENCH"} {
+		require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+			ApparatusFields: model.ApparatusFields{ApparatusCode: code, ApparatusName: code}, CreatedBy: userUuid}))
+	}
+	return daos, userUuid
+}
+
+// createIntegrationExercise creates an exercise of a category working
+// muscles as primary muscles.
+func createIntegrationExercise(t *testing.T, daos *Daos, userUuid uuid.UUID, name, category string, muscles ...string) uuid.UUID {
+	ctx := context.Background()
+	ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: name, CategoryCode: category}, CreatedBy: userUuid})
+	require.NoError(t, err)
+	for _, code := range muscles {
+		require.NoError(t, daos.Exercises.AddMuscles(ctx, ex.ExerciseUuid,
+			[]model.ExerciseMuscle{{MuscleCode: code, MuscleRole: model.MuscleRolePrimary}}))
+	}
+	return ex.ExerciseUuid
+}
+
+func TestIntegration_ReferenceTypes(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+
+	category, err := daos.Categories.GetCategoryByCode(ctx, "strength")
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, category.CreatedBy)
+	assert.NoError(t, daos.Categories.UpdateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "CARDIO", CategoryName: "Cardio", CategoryDesc: "Endurance"}}))
+	categories, err := daos.Categories.GetAllCategories(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	_, err = daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}, CreatedBy: userUuid})
+	assert.Error(t, err)
+
+	license, err := daos.Licenses.GetLicenseByShortName(ctx, "cc-by")
+	assert.NoError(t, err)
+	assert.Equal(t, "Creative Commons Attribution", license.LicenseFullName)
+
+	apparatus, err := daos.Apparatus.GetApparatusByCode(ctx, "barbell")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ApparatusGroupOther, apparatus.ApparatusGroup)
+	assert.Equal(t, userUuid, apparatus.CreatedBy)
+	assert.NoError(t, daos.Apparatus.UpdateApparatus(ctx, &model.ApparatusRequest{ApparatusFields: model.ApparatusFields{
+		ApparatusCode: "BARBELL", ApparatusName: "Barbell", ApparatusGroup: "Free weights"}}))
+	apparatus, err = daos.Apparatus.GetApparatusByCode(ctx, "BARBELL")
+	assert.NoError(t, err)
+	assert.Equal(t, "Free weights", apparatus.ApparatusGroup)
+	assert.NoError(t, daos.Apparatus.DeleteApparatus(ctx, "BENCH"))
+	all, err := daos.Apparatus.GetAllApparatuses(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestIntegration_Muscles(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+
+	mus, err := daos.Muscles.GetMuscleByCode(ctx, "quads")
+	assert.NoError(t, err)
+	assert.Equal(t, model.MuscleLevelMuscle, mus.MuscleLevel)
+	assert.Equal(t, "LEGS", *mus.ParentCode)
+
+	rectus, missing := "RECTUS", "ARMS"
+	_, err = daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "VASTUS", MuscleName: "Vastus", MuscleGroup: "Legs", ParentCode: &rectus}, CreatedBy: userUuid})
+	assert.ErrorIs(t, err, ErrInvalidHierarchy)
+	_, err = daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "BICEPS", MuscleName: "Biceps", MuscleGroup: "Arms", ParentCode: &missing}, CreatedBy: userUuid})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	tree, err := daos.Muscles.GetMuscleTree(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, tree, 1) && assert.Len(t, tree[0].Children, 2) {
+		assert.Equal(t, "GLUTES", tree[0].Children[0].MuscleCode)
+		assert.Equal(t, "RECTUS", tree[0].Children[1].Children[0].MuscleCode)
+	}
+
+	squat := createIntegrationExercise(t, daos, userUuid, "Squat", "STRENGTH", "QUADS")
+	extension := createIntegrationExercise(t, daos, userUuid, "Leg extension", "STRENGTH")
+	assert.NoError(t, daos.Exercises.AddMuscles(ctx, extension,
+		[]model.ExerciseMuscle{{MuscleCode: "RECTUS", MuscleRole: model.MuscleRoleSecondary}}))
+	exercises, err := daos.Muscles.FindExercisesByMuscle(ctx, "LEGS", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleExercise{
+		{ExerciseUuid: extension, ExerciseName: "Leg extension", CategoryCode: "STRENGTH", MuscleCode: "RECTUS", MuscleRole: model.MuscleRoleSecondary},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary},
+	}, exercises)
+
+	assert.Error(t, daos.Muscles.DeleteMuscle(ctx, "QUADS"), "a muscle cannot be deleted while it has children")
+	assert.NoError(t, daos.Muscles.UpdateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "GLUTES", MuscleName: "Gluteus maximus", MuscleGroup: "Legs", ParentCode: nil}}))
+}
+
+func TestIntegration_Planner(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+
+	legs, push, pull := "LEGS", model.MovementPush, model.MovementPull
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleGroup: "Legs", ParentCode: &legs, MovementPattern: &push},
+		{MuscleCode: "GLUTES", MuscleName: "Glutes", MuscleGroup: "Legs", ParentCode: &legs, MovementPattern: &pull},
+	} {
+		require.NoError(t, daos.Muscles.UpdateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus}))
+	}
+	mus, err := daos.Muscles.GetMuscleByCode(ctx, "QUADS")
+	assert.NoError(t, err)
+	assert.Equal(t, &push, mus.MovementPattern)
+
+	squat := createIntegrationExercise(t, daos, userUuid, "Squat", "STRENGTH", "RECTUS")
+	thrust := createIntegrationExercise(t, daos, userUuid, "Hip thrust", "STRENGTH", "GLUTES")
+	sled := createIntegrationExercise(t, daos, userUuid, "Sled drag", "STRENGTH", "LEGS")
+	createIntegrationExercise(t, daos, userUuid, "Running", "CARDIO", "QUADS")
+
+	candidates, err := daos.Planner.ListCandidates(ctx, "strength")
+	assert.NoError(t, err)
+	patterns := map[uuid.UUID]model.MovementPattern{}
+	for _, candidate := range candidates {
+		if assert.Len(t, candidate.Muscles, 1) {
+			patterns[candidate.ExerciseUuid] = candidate.Muscles[0].Pattern
+		}
+	}
+	assert.Equal(t, map[uuid.UUID]model.MovementPattern{
+		squat:  model.MovementPush,
+		thrust: model.MovementPull,
+		sled:   model.MovementOther,
+	}, patterns, "muscles take the pattern of the nearest muscle above them that has one")
+}
+
+func TestIntegration_Exercises(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+
+	ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{ExerciseFields: model.ExerciseFields{
+		ExerciseName: "Squat", Description: "Lower body", CategoryCode: "STRENGTH", LicenseShortName: "CC-BY",
+		LicenseAuthor: "Jane Doe"}, CreatedBy: userUuid})
+	require.NoError(t, err)
+	assert.NoError(t, daos.Exercises.AddMuscles(ctx, ex.ExerciseUuid,
+		[]model.ExerciseMuscle{{MuscleCode: "quads", MuscleRole: model.MuscleRolePrimary}}))
+	assert.NoError(t, daos.Exercises.AddApparatus(ctx, ex.ExerciseUuid, []string{"barbell"}))
+	assert.ErrorIs(t, daos.Exercises.AddApparatus(ctx, ex.ExerciseUuid, []string{"KETTLEBELL"}), ErrNotFound)
+
+	ex.Cues = "Chest up"
+	assert.NoError(t, daos.Exercises.Update(ctx, ex))
+	read, err := daos.Exercises.Read(ctx, ex.ExerciseUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, ex.ExerciseFields, read.ExerciseFields)
+
+	lunge := createIntegrationExercise(t, daos, userUuid, "Lunge", "STRENGTH")
+	assert.NoError(t, daos.Exercises.Delete(ctx, lunge))
+	_, err = daos.Exercises.Read(ctx, lunge)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// An exercise goes together with its muscles and apparatus, as the
+	// exercise service deletes it.
+	assert.NoError(t, daos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := daos.Exercises.RemoveMuscles(ctx, ex.ExerciseUuid); err != nil {
+			return err
+		}
+		if err := daos.Exercises.RemoveApparatus(ctx, ex.ExerciseUuid); err != nil {
+			return err
+		}
+		return daos.Exercises.Delete(ctx, ex.ExerciseUuid)
+	}))
+	_, err = daos.Exercises.Read(ctx, ex.ExerciseUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIntegration_UnitOfWork(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+
+	var created uuid.UUID
+	err := daos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+			ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}, CreatedBy: userUuid})
+		if err != nil {
+			return err
+		}
+		created = ex.ExerciseUuid
+		return daos.Exercises.AddMuscles(ctx, ex.ExerciseUuid,
+			[]model.ExerciseMuscle{{MuscleCode: "BICEPS", MuscleRole: model.MuscleRolePrimary}})
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = daos.Exercises.Read(ctx, created)
+	assert.ErrorIs(t, err, ErrNotFound, "the exercise is rolled back with its muscles")
+}
+
+func TestIntegration_Catalog(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	squat := model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"},
+		Muscles:        []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus:      []string{"BARBELL"},
+	}
+	refs := &model.CatalogReferences{
+		Apparatus: []model.ApparatusFields{{ApparatusCode: "KETTLEBELL", ApparatusName: "Kettlebell"}},
+	}
+
+	uuids, err := daos.Catalog.ImportCatalog(ctx, refs, []model.CatalogExercise{squat}, false, userUuid)
+	require.NoError(t, err)
+	squat.ExerciseName, squat.Apparatus = "squat", []string{"KETTLEBELL"}
+	upserted, err := daos.Catalog.ImportCatalog(ctx, nil, []model.CatalogExercise{squat}, true, userUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, uuids, upserted)
+
+	catalog, err := daos.Catalog.ListExercises(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, catalog, 1) {
+		assert.Equal(t, []string{"KETTLEBELL"}, catalog[0].Apparatus)
+		assert.Equal(t, squat.Muscles, catalog[0].Muscles)
+	}
+	found, err := daos.Catalog.FindExercisesByName(ctx, []string{"SQUAT", "Lunge"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uuid.UUID{"squat": uuids[0]}, found)
+	names, err := daos.Catalog.ListExerciseNames(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, names, 1)
+}
+
+func TestIntegration_RelationsAndTranslations(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	split := createIntegrationExercise(t, daos, userUuid, "Split squat", "STRENGTH", "QUADS")
+	squat := createIntegrationExercise(t, daos, userUuid, "Squat", "STRENGTH", "QUADS")
+
+	req := &model.ExerciseRelationRequest{RelatedUuid: squat, Relation: model.RelationProgressionOf, CreatedBy: userUuid}
+	_, err := daos.Relations.CreateRelation(ctx, split, req)
+	assert.NoError(t, err)
+	_, err = daos.Relations.CreateRelation(ctx, split, req)
+	assert.ErrorIs(t, err, ErrConflict)
+	relations, err := daos.Relations.GetRelations(ctx, squat)
+	assert.NoError(t, err)
+	if assert.Len(t, relations, 1) {
+		assert.Equal(t, "Split squat", relations[0].ExerciseName)
+	}
+	easier, err := daos.Relations.FindProgressions(ctx, split, model.DirectionEasier, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RelatedExercise{{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", Steps: 1}}, easier)
+
+	// the progressions of an alternative, but not the alternative itself
+	hack := createIntegrationExercise(t, daos, userUuid, "Hack squat", "STRENGTH", "QUADS")
+	press := createIntegrationExercise(t, daos, userUuid, "Leg press", "STRENGTH", "QUADS")
+	_, err = daos.Relations.CreateRelation(ctx, split, &model.ExerciseRelationRequest{
+		RelatedUuid: hack, Relation: model.RelationAlternativeTo, CreatedBy: userUuid})
+	assert.NoError(t, err)
+	_, err = daos.Relations.CreateRelation(ctx, hack, &model.ExerciseRelationRequest{
+		RelatedUuid: press, Relation: model.RelationProgressionOf, CreatedBy: userUuid})
+	assert.NoError(t, err)
+	easier, err = daos.Relations.FindProgressions(ctx, split, model.DirectionEasier, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RelatedExercise{
+		{ExerciseUuid: press, ExerciseName: "Leg press", CategoryCode: "STRENGTH", Steps: 1},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", Steps: 1},
+	}, easier)
+	easier, err = daos.Relations.FindProgressions(ctx, split, model.DirectionEasier, 2, false)
+	assert.NoError(t, err)
+	assert.Len(t, easier, 1, "alternatives are only followed for the same muscles")
+	assert.NoError(t, daos.Relations.DeleteRelation(ctx, split, squat, model.RelationProgressionOf))
+
+	assert.NoError(t, daos.Translations.AddAlias(ctx, split, &model.ExerciseAlias{Alias: "Bulgarian"}))
+	assert.NoError(t, daos.Translations.SaveExerciseTranslation(ctx, squat,
+		&model.ExerciseTranslation{Locale: "de", ExerciseName: "Kniebeuge"}))
+	assert.NoError(t, daos.Translations.SaveReferenceTranslation(ctx,
+		&model.ReferenceTranslation{Kind: model.ReferenceMuscle, Code: "QUADS", Locale: "de", Name: "Quadrizeps"}))
+
+	results, err := daos.Translations.SearchExercises(ctx, "kniebeuge", "de", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, squat, results[0].ExerciseUuid)
+		assert.Equal(t, model.MatchedByTranslation, results[0].MatchedBy)
+	}
+	aliases, err := daos.Translations.GetAliases(ctx, split)
+	assert.NoError(t, err)
+	assert.Len(t, aliases, 1)
+	translations, err := daos.Translations.GetReferenceTranslations(ctx, model.ReferenceMuscle, "de")
+	assert.NoError(t, err)
+	assert.Equal(t, "Quadrizeps", translations["QUADS"].Name)
+	locales, err := daos.Translations.ListLocales(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de"}, locales)
+	assert.NoError(t, daos.Translations.DeleteExerciseTranslation(ctx, squat, "de"))
+	assert.NoError(t, daos.Translations.DeleteAlias(ctx, split, "Bulgarian"))
+}
+
+func TestIntegration_WorkoutsAndHistory(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	squat := createIntegrationExercise(t, daos, userUuid, "Squat", "STRENGTH", "QUADS")
+	started := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Second)
+	reps, weight, distance := 5, 100.0, 20.0
+
+	created, err := daos.Workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: started, Source: "strong"},
+		Sets: []model.WorkoutSet{{ExerciseUuid: squat, Reps: &reps, WeightKg: &weight},
+			{ExerciseUuid: squat, Reps: &reps, WeightKg: &weight, DistanceM: &distance, EnteredDistanceUnit: units.M}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	sessions, err := daos.Workouts.ListSessions(ctx, userUuid, started.Add(-time.Hour), started.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		assert.Len(t, sessions[0].Sets, 2)
+		assert.Equal(t, units.M, sessions[0].Sets[1].EnteredDistanceUnit)
+		assert.True(t, started.Equal(sessions[0].StartedAt))
+	}
+	starts, err := daos.Workouts.ListSessionStarts(ctx, userUuid, "strong")
+	assert.NoError(t, err)
+	assert.Len(t, starts, 1)
+
+	assert.NoError(t, daos.Mappings.SaveMapping(ctx, &model.ExerciseMapping{
+		Source: "strong", ExternalName: "squat (barbell)", ExerciseUuid: squat, CreatedBy: userUuid}))
+	mappings, err := daos.Mappings.GetMappings(ctx, "strong")
+	assert.NoError(t, err)
+	assert.Len(t, mappings, 1)
+	assert.NoError(t, daos.Mappings.DeleteMapping(ctx, "strong", "squat (barbell)"))
+
+	loads, err := daos.Recovery.ListMuscleLoads(ctx, userUuid, started.Add(-time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, loads, 1) {
+		assert.Equal(t, model.MuscleLoad{StartedAt: loads[0].StartedAt, MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary, Sets: 2}, loads[0])
+	}
+	volume, err := daos.Analytics.GetDailyVolume(ctx, userUuid, started.Add(-48*time.Hour), started.Add(48*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, volume, 1) {
+		assert.InDelta(t, 1000.0, volume[0].Value, 0.01)
+	}
+	candidates, err := daos.Planner.ListCandidates(ctx, "STRENGTH")
+	assert.NoError(t, err)
+	if assert.Len(t, candidates, 1) && assert.Len(t, candidates[0].Muscles, 1) {
+		assert.Equal(t, "QUADS", candidates[0].Muscles[0].MuscleCode)
+	}
+
+	// a lunge set counts once for LEGS though it works RECTUS and GLUTES
+	lunge := createIntegrationExercise(t, daos, userUuid, "Lunge", "STRENGTH", "RECTUS", "GLUTES")
+	lungeReps, lungeWeight := 10, 40.0
+	_, err = daos.Workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Lunges", StartedAt: started.Add(time.Hour), Source: "strong"},
+		Sets:                 []model.WorkoutSet{{ExerciseUuid: lunge, Reps: &lungeReps, WeightKg: &lungeWeight}},
+	}})
+	require.NoError(t, err)
+	regions, err := daos.Analytics.GetMuscleVolume(ctx, userUuid, model.MuscleLevelRegion, started.Add(-time.Hour), started.Add(2*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, regions, 1) {
+		assert.Equal(t, "LEGS", regions[0].MuscleCode)
+		assert.Equal(t, model.MuscleLevelRegion, regions[0].MuscleLevel)
+		assert.Equal(t, 3, regions[0].Sets)
+		assert.InDelta(t, 1400.0, regions[0].Volume, 0.01)
+	}
+	muscles, err := daos.Analytics.GetMuscleVolume(ctx, userUuid, model.MuscleLevelMuscle, started.Add(-time.Hour), started.Add(2*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, muscles, 2) {
+		assert.Equal(t, "QUADS", muscles[0].MuscleCode)
+		assert.Equal(t, 3, muscles[0].Sets)
+		assert.InDelta(t, 1400.0, muscles[0].Volume, 0.01)
+		assert.Equal(t, "GLUTES", muscles[1].MuscleCode)
+		assert.Equal(t, 1, muscles[1].Sets)
+		assert.InDelta(t, 400.0, muscles[1].Volume, 0.01)
+	}
+}
+
+func TestIntegration_Cardio(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	run := createIntegrationExercise(t, daos, userUuid, "Running", "CARDIO")
+	started := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+
+	category, err := daos.Cardio.GetExerciseCategory(ctx, run)
+	assert.NoError(t, err)
+	assert.Equal(t, "CARDIO", category)
+
+	activity := &model.CardioActivity{
+		UserUuid: userUuid, ExerciseUuid: run, SessionName: "Morning run", StartedAt: started, Source: "fit",
+		CardioSummary: model.CardioSummary{Sport: "running", DistanceM: 5000, DurationSeconds: 1500},
+		HeartRate:     []model.HeartRateSample{{OffsetSeconds: 0, HeartRate: 120}, {OffsetSeconds: 60, HeartRate: 150}},
+	}
+	require.NoError(t, daos.Cardio.CreateActivity(ctx, activity))
+
+	found, err := daos.Cardio.FindActivityByStart(ctx, userUuid, started)
+	assert.NoError(t, err)
+	if assert.NotNil(t, found) {
+		assert.Equal(t, activity.SessionUuid, *found)
+	}
+	read, err := daos.Cardio.GetActivity(ctx, activity.SessionUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, activity.HeartRate, read.HeartRate)
+	activities, err := daos.Cardio.ListActivities(ctx, userUuid, started.Add(-time.Hour), started.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+}
+
+func TestIntegration_EquipmentProfiles(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	squat := createIntegrationExercise(t, daos, userUuid, "Squat", "STRENGTH")
+	assert.NoError(t, daos.Exercises.AddApparatus(ctx, squat, []string{"BARBELL"}))
+	bench := createIntegrationExercise(t, daos, userUuid, "Bench press", "STRENGTH")
+	assert.NoError(t, daos.Exercises.AddApparatus(ctx, bench, []string{"BARBELL", "BENCH"}))
+
+	home, err := daos.Equipment.CreateProfile(ctx, &userUuid, &model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Home", IsDefault: true, Apparatus: []string{"barbell"}}, CreatedBy: userUuid})
+	require.NoError(t, err)
+	_, err = daos.Equipment.CreateProfile(ctx, &userUuid, &model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "home"}, CreatedBy: userUuid})
+	assert.ErrorIs(t, err, ErrConflict)
+	gym, err := daos.Equipment.CreateProfile(ctx, nil, &model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Downtown", Apparatus: []string{"BARBELL", "BENCH"}}, CreatedBy: userUuid})
+	require.NoError(t, err)
+
+	profile, err := daos.Equipment.GetDefaultProfile(ctx, userUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, home.ProfileUuid, profile.ProfileUuid)
+	profiles, err := daos.Equipment.ListProfiles(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 1)
+
+	exercises, err := daos.Equipment.FindAvailableExercises(ctx, home.ProfileUuid, "")
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 1) {
+		assert.Equal(t, squat, exercises[0].ExerciseUuid)
+	}
+	_, err = daos.Equipment.UpdateProfile(ctx, gym.ProfileUuid, &model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Downtown", Apparatus: []string{"KETTLEBELL"}}})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, daos.Equipment.DeleteProfile(ctx, gym.ProfileUuid))
+	_, err = daos.Equipment.GetProfile(ctx, gym.ProfileUuid)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIntegration_UsersAndMeasurements(t *testing.T) {
+	daos, userUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	increment := 5.0
+
+	prefs, err := daos.Users.UpdatePreferences(ctx, userUuid, &model.UserPreferences{UnitSystem: units.Imperial, LoadIncrement: &increment})
+	assert.NoError(t, err)
+	assert.Equal(t, units.Imperial, prefs.UnitSystem)
+	prefs, err = daos.Users.GetPreferences(ctx, userUuid)
+	assert.NoError(t, err)
+	assert.InDelta(t, increment, *prefs.LoadIncrement, 0.001)
+	_, err = daos.Users.GetPreferences(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	measuredAt := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	measurement, err := daos.Measurements.CreateMeasurement(ctx, userUuid, &model.MeasurementRequest{MeasurementFields: model.MeasurementFields{
+		Kind: model.MeasurementBodyweight, Value: 80.5, MeasuredAt: measuredAt}})
+	require.NoError(t, err)
+	_, err = daos.Measurements.CreateMeasurement(ctx, uuid.New(), &model.MeasurementRequest{MeasurementFields: model.MeasurementFields{
+		Kind: model.MeasurementBodyweight, Value: 80.5, MeasuredAt: measuredAt}})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	measurement, err = daos.Measurements.UpdateMeasurement(ctx, measurement.MeasurementUuid, &model.MeasurementRequest{MeasurementFields: model.MeasurementFields{
+		Kind: model.MeasurementBodyweight, Value: 80, MeasuredAt: measuredAt}})
+	assert.NoError(t, err)
+	measurements, err := daos.Measurements.ListMeasurements(ctx, userUuid, model.MeasurementBodyweight, "", measuredAt.Add(-time.Hour), measuredAt.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, measurements, 1) {
+		assert.InDelta(t, 80.0, measurements[0].Value, 0.001)
+	}
+	weights, err := daos.Measurements.GetMeasurement(ctx, measurement.MeasurementUuid)
+	assert.NoError(t, err)
+	assert.True(t, measuredAt.Equal(weights.MeasuredAt))
+	assert.NoError(t, daos.Measurements.DeleteMeasurement(ctx, measurement.MeasurementUuid))
+	assert.ErrorIs(t, daos.Measurements.DeleteMeasurement(ctx, measurement.MeasurementUuid), ErrNotFound)
+}
+
+func TestIntegration_CalendarAndCoaching(t *testing.T) {
+	daos, coachUuid := newIntegrationDaos(t)
+	ctx := context.Background()
+	clientUuid := pgtest.CreateUser(t, sqlx.NewDb(daos.DB, "postgres"), "Client")
+	squat := createIntegrationExercise(t, daos, coachUuid, "Squat", "STRENGTH")
+	scheduledAt := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	sessions, err := daos.Workouts.CreateSessions(ctx, clientUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: scheduledAt},
+		Sets:                 []model.WorkoutSet{{ExerciseUuid: squat}},
+	}})
+	require.NoError(t, err)
+
+	scheduled, err := daos.Calendar.CreateScheduledWorkout(ctx, clientUuid, &model.ScheduledWorkoutFields{
+		SessionName: "Legs", ScheduledAt: scheduledAt})
+	require.NoError(t, err)
+	scheduled, err = daos.Calendar.UpdateScheduledWorkout(ctx, scheduled.ScheduleUuid, &model.ScheduledWorkoutFields{
+		SessionName: "Legs", ScheduledAt: scheduledAt, SessionUuid: &sessions[0].SessionUuid})
+	assert.NoError(t, err)
+	_, err = daos.Calendar.UpdateScheduledWorkout(ctx, scheduled.ScheduleUuid, &model.ScheduledWorkoutFields{
+		SessionName: "Legs", ScheduledAt: scheduledAt, SessionUuid: &squat})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	read, err := daos.Calendar.GetScheduledWorkout(ctx, scheduled.ScheduleUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions[0].SessionUuid, *read.SessionUuid)
+	workouts, err := daos.Calendar.ListScheduledWorkouts(ctx, clientUuid, scheduledAt.Add(-time.Hour), scheduledAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, workouts, 1)
+	calendarSessions, err := daos.Calendar.ListCalendarSessions(ctx, clientUuid, scheduledAt.Add(-time.Hour), scheduledAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, calendarSessions, 1)
+	assert.NoError(t, daos.Calendar.DeleteScheduledWorkout(ctx, scheduled.ScheduleUuid))
+
+	_, err = daos.Calendar.SaveFeed(ctx, clientUuid, "secret")
+	assert.NoError(t, err)
+	feedUser, err := daos.Calendar.GetFeedUser(ctx, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, clientUuid, feedUser)
+	feed, err := daos.Calendar.GetFeed(ctx, clientUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", feed.Token)
+	assert.NoError(t, daos.Calendar.DeleteFeed(ctx, clientUuid))
+
+	assert.NoError(t, daos.Coach.AddClient(ctx, coachUuid, clientUuid))
+	assert.ErrorIs(t, daos.Coach.AddClient(ctx, coachUuid, uuid.New()), ErrNotFound)
+	clients, err := daos.Coach.ListClients(ctx, coachUuid)
+	assert.NoError(t, err)
+	if assert.Len(t, clients, 1) {
+		assert.Equal(t, "Client", clients[0].FirstName)
+	}
+	assert.NoError(t, daos.Coach.RemoveClient(ctx, coachUuid, clientUuid))
+	assert.ErrorIs(t, daos.Coach.RemoveClient(ctx, coachUuid, clientUuid), ErrNotFound)
+}
@@ -6,11 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pwydra/shred/internal/model"
 )
 
+type LicenseDaoInterface interface {
+	CreateLicense(ctx context.Context, licenseReq *model.LicenseRequest) error
+	GetLicenseByShortName(ctx context.Context, shortName string) (*model.License, error)
+	GetAllLicenses(ctx context.Context) ([]model.License, error)
+	UpdateLicense(ctx context.Context, licenseReq *model.LicenseRequest) error
+	DeleteLicense(ctx context.Context, shortName string) error
+}
+
+// Ensure LicenseDAO implements LicenseDaoInterface
+var _ LicenseDaoInterface = (*LicenseDAO)(nil)
+
 // LicenseDAO provides access to the licenses in the database.
 type LicenseDAO struct {
 	db *sqlx.DB
@@ -23,9 +35,9 @@ func NewLicenseDAO(db *sqlx.DB) *LicenseDAO {
 
 const createLicenseDML string = `
 	INSERT INTO license (
-		license_short_name, license_full_name, url
+		license_short_name, license_full_name, url, created_by
 	) VALUES (
-		$1, $2, $3
+		$1, $2, $3, $4
 	)`
 
 // GetLicenseByShortName retrieves a license by its short name.
@@ -34,9 +46,11 @@ const getLicenseByShortNameDQL string = `
 	FROM license
 	WHERE license_short_name = $1`
 
-func (dao *LicenseDAO) GetLicenseByShortName(licenseShortName string) (*model.License, error) {
+func (dao *LicenseDAO) GetLicenseByShortName(ctx context.Context, licenseShortName string) (_ *model.License, err error) {
+	defer observe(ctx, "LicenseDAO", "GetLicenseByShortName", time.Now(), &err)
 	var license model.License
-	if err := dao.db.QueryRowx(getLicenseByShortNameDQL, strings.ToUpper(licenseShortName)).StructScan(&license); err != nil {
+	if err := getContext(ctx, dao.db, "getLicenseByShortNameDQL", &license, getLicenseByShortNameDQL,
+		strings.ToUpper(licenseShortName)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("license with short name %s not found", strings.ToUpper(licenseShortName))
 		}
@@ -51,9 +65,10 @@ const getAllLicensesDQL string = `
 	SELECT *
 	FROM license`
 
-func (dao *LicenseDAO) GetAllLicenses(ctx context.Context) ([]model.License, error) {
+func (dao *LicenseDAO) GetAllLicenses(ctx context.Context) (_ []model.License, err error) {
+	defer observe(ctx, "LicenseDAO", "GetAllLicenses", time.Now(), &err)
 	var licenses []model.License
-	if err := dao.db.SelectContext(ctx, &licenses, getAllLicensesDQL); err != nil {
+	if err := selectContext(ctx, dao.db, "getAllLicensesDQL", &licenses, getAllLicensesDQL); err != nil {
 		return nil, err
 	}
 
@@ -63,9 +78,11 @@ func (dao *LicenseDAO) GetAllLicenses(ctx context.Context) ([]model.License, err
 // CreateLicense inserts a new license into the database.
 // Returns an error if the insertion fails.
 // Does not return the PK as type tables have PK specified by the request.
-func (dao *LicenseDAO) CreateLicense(licenseReq *model.LicenseRequest) error {
-	_, err := dao.db.Exec(createLicenseDML,
-		strings.ToUpper(licenseReq.LicenseShortName), licenseReq.LicenseFullName, licenseReq.LicenseUrl)
+func (dao *LicenseDAO) CreateLicense(ctx context.Context, licenseReq *model.LicenseRequest) (err error) {
+	defer observe(ctx, "LicenseDAO", "CreateLicense", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "createLicenseDML", createLicenseDML,
+		strings.ToUpper(licenseReq.LicenseShortName), licenseReq.LicenseFullName, licenseReq.LicenseUrl,
+		licenseReq.CreatedBy)
 	if err != nil {
 		return err
 	}
@@ -81,8 +98,9 @@ const updateLicenseDML string = `
 		url = $2
 	WHERE license_short_name = $3`
 
-func (dao *LicenseDAO) UpdateLicense(licenseReq *model.LicenseRequest) error {
-	result, err := dao.db.Exec(updateLicenseDML,
+func (dao *LicenseDAO) UpdateLicense(ctx context.Context, licenseReq *model.LicenseRequest) (err error) {
+	defer observe(ctx, "LicenseDAO", "UpdateLicense", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "updateLicenseDML", updateLicenseDML,
 		licenseReq.LicenseFullName, licenseReq.LicenseUrl, strings.ToUpper(licenseReq.LicenseShortName))
 	if err != nil {
 		return err
@@ -105,8 +123,9 @@ const deleteLicenseDML string = `
 	DELETE FROM license
 	WHERE license_short_name = $1`
 
-func (dao *LicenseDAO) DeleteLicense(shortName string) error {
-	result, err := dao.db.Exec(deleteLicenseDML, strings.ToUpper(shortName))
+func (dao *LicenseDAO) DeleteLicense(ctx context.Context, shortName string) (err error) {
+	defer observe(ctx, "LicenseDAO", "DeleteLicense", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteLicenseDML", deleteLicenseDML, strings.ToUpper(shortName))
 	if err != nil {
 		return err
 	}
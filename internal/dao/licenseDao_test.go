@@ -66,7 +66,7 @@ func TestGetLicenseByShortName(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"license_short_name", "license_full_name", "url"}).
 			AddRow(licenseShortName, expected.LicenseFullName, expected.LicenseUrl))
 
-	license, err := dao.GetLicenseByShortName(licenseShortName)
+	license, err := dao.GetLicenseByShortName(context.Background(), licenseShortName)
 	assert.NoError(t, err)
 	assert.NotNil(t, license)
 	assert.Equal(t, expected.LicenseFullName, license.LicenseFullName)
@@ -84,7 +84,7 @@ func TestGetLicenseByShortName_NotFound(t *testing.T) {
 		WithArgs(licenseShortName).
 		WillReturnError(sql.ErrNoRows)
 
-	license, err := dao.GetLicenseByShortName(licenseShortName)
+	license, err := dao.GetLicenseByShortName(context.Background(), licenseShortName)
 	assert.Error(t, err)
 	assert.Nil(t, license)
 	assert.Equal(t, "license with short name INVALID not found", err.Error())
@@ -119,11 +119,11 @@ func TestCreateLicense(t *testing.T) {
 
 	licenseReq := licenseReq()
 
-	mock.ExpectExec("INSERT INTO license \\( license_short_name, license_full_name, url \\) VALUES \\( \\$1, \\$2, \\$3 \\)").
-		WithArgs(licenseReq.LicenseShortName, licenseReq.LicenseFullName, licenseReq.LicenseUrl).
+	mock.ExpectExec("INSERT INTO license \\( license_short_name, license_full_name, url, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4 \\)").
+		WithArgs(licenseReq.LicenseShortName, licenseReq.LicenseFullName, licenseReq.LicenseUrl, licenseReq.CreatedBy).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.CreateLicense(licenseReq)
+	err = dao.CreateLicense(context.Background(), licenseReq)
 	assert.NoError(t, err)
 }
 
@@ -136,11 +136,11 @@ func TestCreateLicense_Error(t *testing.T) {
 
 	licenseReq := licenseReq()
 
-	mock.ExpectExec("INSERT INTO license \\( license_short_name, license_full_name, url \\) VALUES \\( \\$1, \\$2, \\$3 \\)").
-		WithArgs(licenseReq.LicenseShortName, licenseReq.LicenseFullName, licenseReq.LicenseUrl).
+	mock.ExpectExec("INSERT INTO license \\( license_short_name, license_full_name, url, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4 \\)").
+		WithArgs(licenseReq.LicenseShortName, licenseReq.LicenseFullName, licenseReq.LicenseUrl, licenseReq.CreatedBy).
 		WillReturnError(errors.New("insertion error"))
 
-	err = dao.CreateLicense(licenseReq)
+	err = dao.CreateLicense(context.Background(), licenseReq)
 	assert.Error(t, err)
 	assert.Equal(t, "insertion error", err.Error())
 }
@@ -158,7 +158,7 @@ func TestUpdateLicense(t *testing.T) {
 		WithArgs(licenseReq.LicenseFullName, licenseReq.LicenseUrl, licenseReq.LicenseShortName).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.UpdateLicense(licenseReq)
+	err = dao.UpdateLicense(context.Background(), licenseReq)
 	assert.NoError(t, err)
 }
 
@@ -175,7 +175,7 @@ func TestUpdateLicense_Error(t *testing.T) {
 		WithArgs(licenseReq.LicenseFullName, licenseReq.LicenseUrl, licenseReq.LicenseShortName).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.UpdateLicense(licenseReq)
+	err = dao.UpdateLicense(context.Background(), licenseReq)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -193,7 +193,7 @@ func TestUpdateLicense_NotFound(t *testing.T) {
 		WithArgs(licenseReq.LicenseFullName, licenseReq.LicenseUrl, licenseReq.LicenseShortName).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.UpdateLicense(licenseReq)
+	err = dao.UpdateLicense(context.Background(), licenseReq)
 	assert.Error(t, err)
 	assert.Equal(t, "license with Short Name 'CC-BY-SA 3' not found", err.Error())
 }
@@ -210,7 +210,7 @@ func TestDeleteLicense(t *testing.T) {
 		WithArgs(licenseShortName).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.DeleteLicense(licenseShortName)
+	err = dao.DeleteLicense(context.Background(), licenseShortName)
 	assert.NoError(t, err)
 }
 
@@ -226,7 +226,7 @@ func TestDeleteLicense_Error(t *testing.T) {
 		WithArgs(licenseShortName).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.DeleteLicense(licenseShortName)
+	err = dao.DeleteLicense(context.Background(), licenseShortName)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -243,7 +243,7 @@ func TestDeleteLicense_NotFound(t *testing.T) {
 		WithArgs(licenseShortName).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.DeleteLicense(licenseShortName)
+	err = dao.DeleteLicense(context.Background(), licenseShortName)
 	assert.Error(t, err)
 	assert.Equal(t, "license with Short Name 'INVALID' not found", err.Error())
 }
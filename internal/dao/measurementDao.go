@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// MeasurementDao provides access to the body measurements of users.
+type MeasurementDao struct {
+	db *sqlx.DB
+}
+
+type MeasurementDaoInterface interface {
+	CreateMeasurement(ctx context.Context, userUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error)
+	ListMeasurements(ctx context.Context, userUuid uuid.UUID, kind model.MeasurementKind, site string, from, to time.Time) ([]model.Measurement, error)
+	GetMeasurement(ctx context.Context, measurementUuid uuid.UUID) (*model.Measurement, error)
+	UpdateMeasurement(ctx context.Context, measurementUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error)
+	DeleteMeasurement(ctx context.Context, measurementUuid uuid.UUID) error
+}
+
+// Ensure MeasurementDao implements MeasurementDaoInterface
+var _ MeasurementDaoInterface = (*MeasurementDao)(nil)
+
+// NewMeasurementDao creates a new instance of MeasurementDao.
+func NewMeasurementDao(db *sqlx.DB) *MeasurementDao {
+	return &MeasurementDao{db: db}
+}
+
+const measurementColumns string = `
+	measurement_uuid, user_uuid, measurement_kind, COALESCE(site, '') AS site, measured_value,
+	measured_at, COALESCE(notes, '') AS notes, COALESCE(entered_unit, '') AS entered_unit,
+	created_at, updated_at`
+
+const (
+	createMeasurementDML string = `
+	INSERT INTO body_measurement (
+		user_uuid, measurement_kind, site, measured_value, measured_at, notes, entered_unit
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7
+	) RETURNING` + measurementColumns
+
+	// listMeasurementsDQL lists the measurements of user $1 between $4 and $5,
+	// optionally only of kind $2 and site $3.
+	listMeasurementsDQL string = `
+	SELECT` + measurementColumns + `
+	FROM   body_measurement
+	WHERE  user_uuid = $1
+	AND    ($2 = '' OR measurement_kind::text = $2)
+	AND    ($3 = '' OR site = $3)
+	AND    measured_at >= $4 AND measured_at < $5
+	ORDER BY measured_at, measurement_uuid`
+
+	getMeasurementDQL string = `
+	SELECT` + measurementColumns + `
+	FROM   body_measurement
+	WHERE  measurement_uuid = $1`
+
+	updateMeasurementDML string = `
+	UPDATE body_measurement
+	SET    measurement_kind = $1, site = $2, measured_value = $3, measured_at = $4, notes = $5,
+	       entered_unit = $6, updated_at = CURRENT_TIMESTAMP
+	WHERE  measurement_uuid = $7
+	RETURNING` + measurementColumns
+
+	deleteMeasurementDML string = `
+	DELETE FROM body_measurement WHERE measurement_uuid = $1`
+)
+
+// CreateMeasurement stores a measurement normalized with
+// MeasurementRequest.Normalize.
+func (dao *MeasurementDao) CreateMeasurement(ctx context.Context, userUuid uuid.UUID, req *model.MeasurementRequest) (_ *model.Measurement, err error) {
+	defer observe(ctx, "MeasurementDao", "CreateMeasurement", time.Now(), &err)
+	var m model.Measurement
+	err = getContext(ctx, dao.db, "createMeasurementDML", &m, createMeasurementDML,
+		userUuid, req.Kind, nullIfEmpty(req.Site),
+		req.Value, req.MeasuredAt.UTC(), nullIfEmpty(req.Notes), nullIfEmpty(req.EnteredUnit))
+	if isForeignKeyViolation(err) {
+		return nil, fmt.Errorf("user %s %w", userUuid, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	metrics.MeasurementsRecorded.WithLabelValues(string(m.Kind)).Inc()
+	return &m, nil
+}
+
+func (dao *MeasurementDao) ListMeasurements(ctx context.Context, userUuid uuid.UUID, kind model.MeasurementKind, site string, from, to time.Time) (_ []model.Measurement, err error) {
+	defer observe(ctx, "MeasurementDao", "ListMeasurements", time.Now(), &err)
+	measurements := []model.Measurement{}
+	if err := selectContext(ctx, dao.db, "listMeasurementsDQL", &measurements, listMeasurementsDQL,
+		userUuid, string(kind), site, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	for i := range measurements {
+		measurements[i].Unit = measurements[i].Kind.Unit()
+	}
+	return measurements, nil
+}
+
+func (dao *MeasurementDao) GetMeasurement(ctx context.Context, measurementUuid uuid.UUID) (_ *model.Measurement, err error) {
+	defer observe(ctx, "MeasurementDao", "GetMeasurement", time.Now(), &err)
+	var m model.Measurement
+	if err := getContext(ctx, dao.db, "getMeasurementDQL", &m, getMeasurementDQL, measurementUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("measurement %s %w", measurementUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	return &m, nil
+}
+
+func (dao *MeasurementDao) UpdateMeasurement(ctx context.Context, measurementUuid uuid.UUID, req *model.MeasurementRequest) (_ *model.Measurement, err error) {
+	defer observe(ctx, "MeasurementDao", "UpdateMeasurement", time.Now(), &err)
+	var m model.Measurement
+	err = getContext(ctx, dao.db, "updateMeasurementDML", &m, updateMeasurementDML, req.Kind, nullIfEmpty(req.Site),
+		req.Value, req.MeasuredAt.UTC(), nullIfEmpty(req.Notes), nullIfEmpty(req.EnteredUnit), measurementUuid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("measurement %s %w", measurementUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	return &m, nil
+}
+
+func (dao *MeasurementDao) DeleteMeasurement(ctx context.Context, measurementUuid uuid.UUID) (err error) {
+	defer observe(ctx, "MeasurementDao", "DeleteMeasurement", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteMeasurementDML", deleteMeasurementDML, measurementUuid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("measurement %s %w", measurementUuid, ErrNotFound)
+	}
+	return nil
+}
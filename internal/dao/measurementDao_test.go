@@ -0,0 +1,134 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var measurementRowColumns = []string{"measurement_uuid", "user_uuid", "measurement_kind", "site",
+	"measured_value", "measured_at", "notes", "entered_unit", "created_at", "updated_at"}
+
+func TestCreateMeasurement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMeasurementDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, measurementUuid := uuid.New(), uuid.New()
+	measuredAt := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	req := &model.MeasurementRequest{MeasurementFields: model.MeasurementFields{
+		Kind: model.MeasurementCircumference, Site: "WAIST", Value: 84.46, MeasuredAt: measuredAt, EnteredUnit: "in"}}
+	mock.ExpectQuery("INSERT INTO body_measurement").
+		WithArgs(userUuid, req.Kind, "WAIST", 84.46, measuredAt, nil, "in").
+		WillReturnRows(sqlmock.NewRows(measurementRowColumns).
+			AddRow(measurementUuid, userUuid, "circumference", "WAIST", 84.46, measuredAt, "", "in", measuredAt, measuredAt))
+
+	m, err := dao.CreateMeasurement(context.Background(), userUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, measurementUuid, m.MeasurementUuid)
+	assert.Equal(t, "WAIST", m.Site)
+	assert.Equal(t, "cm", m.Unit)
+	assert.Equal(t, "in", m.EnteredUnit)
+
+	mock.ExpectQuery("INSERT INTO body_measurement").
+		WillReturnError(&pq.Error{Code: "23503"})
+	_, err = dao.CreateMeasurement(context.Background(), userUuid, req)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListMeasurements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMeasurementDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mock.ExpectQuery("FROM body_measurement WHERE user_uuid = \\$1 AND \\(\\$2 = '' OR measurement_kind::text = \\$2\\)").
+		WithArgs(userUuid, "bodyweight", "", from, to).
+		WillReturnRows(sqlmock.NewRows(measurementRowColumns).
+			AddRow(uuid.New(), userUuid, "bodyweight", "", 82.4, from, "", "kg", from, from).
+			AddRow(uuid.New(), userUuid, "bodyweight", "", 81.9, from.AddDate(0, 0, 7), "after travel", "kg", from, from))
+
+	measurements, err := dao.ListMeasurements(context.Background(), userUuid, model.MeasurementBodyweight, "", from, to)
+	assert.NoError(t, err)
+	assert.Len(t, measurements, 2)
+	assert.Equal(t, 81.9, measurements[1].Value)
+	assert.Equal(t, "after travel", measurements[1].Notes)
+	assert.Equal(t, "kg", measurements[0].Unit)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMeasurement_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMeasurementDao(sqlx.NewDb(db, "postgres"))
+
+	measurementUuid := uuid.New()
+	mock.ExpectQuery("FROM body_measurement WHERE measurement_uuid = \\$1").
+		WithArgs(measurementUuid).
+		WillReturnRows(sqlmock.NewRows(measurementRowColumns))
+
+	_, err = dao.GetMeasurement(context.Background(), measurementUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateMeasurement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMeasurementDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, measurementUuid := uuid.New(), uuid.New()
+	measuredAt := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	req := &model.MeasurementRequest{MeasurementFields: model.MeasurementFields{
+		Kind: model.MeasurementBodyFat, Value: 18.2, MeasuredAt: measuredAt, Notes: "calipers"}}
+	mock.ExpectQuery("UPDATE body_measurement SET measurement_kind = \\$1").
+		WithArgs(req.Kind, nil, 18.2, measuredAt, "calipers", nil, measurementUuid).
+		WillReturnRows(sqlmock.NewRows(measurementRowColumns).
+			AddRow(measurementUuid, userUuid, "body_fat", "", 18.2, measuredAt, "calipers", "", measuredAt, measuredAt))
+
+	m, err := dao.UpdateMeasurement(context.Background(), measurementUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "%", m.Unit)
+	assert.Equal(t, "calipers", m.Notes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMeasurement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMeasurementDao(sqlx.NewDb(db, "postgres"))
+
+	measurementUuid := uuid.New()
+	mock.ExpectExec("DELETE FROM body_measurement").
+		WithArgs(measurementUuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.DeleteMeasurement(context.Background(), measurementUuid))
+
+	mock.ExpectExec("DELETE FROM body_measurement").
+		WithArgs(measurementUuid).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = dao.DeleteMeasurement(context.Background(), measurementUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
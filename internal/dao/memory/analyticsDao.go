@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// AnalyticsDao aggregates the workouts in a store into series over time.
+type AnalyticsDao struct {
+	store *Store
+}
+
+// Ensure AnalyticsDao implements dao.AnalyticsDaoInterface
+var _ dao.AnalyticsDaoInterface = (*AnalyticsDao)(nil)
+
+// NewAnalyticsDao creates a new instance of AnalyticsDao.
+func NewAnalyticsDao(store *Store) *AnalyticsDao {
+	return &AnalyticsDao{store: store}
+}
+
+// GetDailyVolume sums reps times load per UTC day of the sessions of a user
+// started in [from, to). Sets without reps or load do not count.
+func (d *AnalyticsDao) GetDailyVolume(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.SeriesPoint, error) {
+	points := []model.SeriesPoint{}
+	err := d.store.read(ctx, func() error {
+		for _, session := range d.store.userSessions(userUuid, from, to) {
+			day := session.StartedAt.UTC().Truncate(24 * time.Hour)
+			for _, set := range session.Sets {
+				if set.Reps == nil || set.WeightKg == nil {
+					continue
+				}
+				i := slices.IndexFunc(points, func(p model.SeriesPoint) bool { return p.At.Equal(day) })
+				if i < 0 {
+					points = append(points, model.SeriesPoint{At: day})
+					i = len(points) - 1
+				}
+				points[i].Value += float64(*set.Reps) * *set.WeightKg
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// GetMuscleVolume sums reps times load of the sets of a user in [from, to)
+// per muscle of level that the exercise of the set works, itself or through
+// a muscle below it, in any role. A set counts once per muscle even when its
+// exercise works several muscles below it.
+func (d *AnalyticsDao) GetMuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) ([]model.MuscleVolume, error) {
+	volumes := []model.MuscleVolume{}
+	err := d.store.read(ctx, func() error {
+		for _, session := range d.store.userSessions(userUuid, from, to) {
+			for _, set := range session.Sets {
+				if set.Reps == nil || set.WeightKg == nil {
+					continue
+				}
+				counted := map[string]bool{}
+				for key := range d.store.exerciseMuscles.rows {
+					if key.Exercise != set.ExerciseUuid {
+						continue
+					}
+					for _, code := range d.store.ancestors(key.Code) {
+						mus, ok := d.store.muscles.get(code)
+						if !ok || mus.MuscleLevel != level || counted[code] {
+							continue
+						}
+						counted[code] = true
+						i := slices.IndexFunc(volumes, func(v model.MuscleVolume) bool { return v.MuscleCode == code })
+						if i < 0 {
+							volumes = append(volumes, model.MuscleVolume{
+								MuscleCode: code, MuscleName: mus.MuscleName, MuscleLevel: mus.MuscleLevel})
+							i = len(volumes) - 1
+						}
+						volumes[i].Sets++
+						volumes[i].Volume += float64(*set.Reps) * *set.WeightKg
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(volumes, func(a, b model.MuscleVolume) int {
+		return cmp.Or(cmp.Compare(b.Volume, a.Volume), cmp.Compare(a.MuscleCode, b.MuscleCode))
+	})
+	return volumes, nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsDao_GetMuscleVolume(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat", "QUADS")
+	lunge := createExercise(t, store, "Lunge", "RECTUS", "GLUTES")
+	userUuid := uuid.New()
+	started := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	reps, weight, light := 5, 100.0, 40.0
+
+	_, err := NewWorkoutDao(store).CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: started},
+		Sets: []model.WorkoutSet{{ExerciseUuid: squat, Reps: &reps, WeightKg: &weight},
+			{ExerciseUuid: lunge, Reps: &reps, WeightKg: &light}, {ExerciseUuid: squat, Reps: &reps}},
+	}})
+	assert.NoError(t, err)
+
+	analytics := NewAnalyticsDao(store)
+	// the lunge set counts once for LEGS though it works RECTUS and GLUTES
+	regions, err := analytics.GetMuscleVolume(ctx, userUuid, model.MuscleLevelRegion, started, started.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleVolume{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion, Sets: 2, Volume: 700},
+	}, regions)
+
+	muscles, err := analytics.GetMuscleVolume(ctx, userUuid, model.MuscleLevelMuscle, started, started.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleVolume{
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelMuscle, Sets: 2, Volume: 700},
+		{MuscleCode: "GLUTES", MuscleName: "Glutes", MuscleLevel: model.MuscleLevelMuscle, Sets: 1, Volume: 200},
+	}, muscles)
+
+	none, err := analytics.GetMuscleVolume(ctx, userUuid, model.MuscleLevelRegion, started.Add(time.Hour), started.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Ensure ApparatusDAO implements dao.ApparatusDaoInterface
+var _ dao.ApparatusDaoInterface = (*ApparatusDAO)(nil)
+
+// ApparatusDAO provides access to the apparatuses in a store.
+type ApparatusDAO struct {
+	store *Store
+}
+
+// NewApparatusDAO creates a new instance of ApparatusDAO.
+func NewApparatusDAO(store *Store) *ApparatusDAO {
+	return &ApparatusDAO{store: store}
+}
+
+func (d *ApparatusDAO) GetApparatusByCode(ctx context.Context, appCode string) (*model.Apparatus, error) {
+	code := strings.ToUpper(appCode)
+	var apparatus model.Apparatus
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if apparatus, ok = d.store.apparatus.get(code); !ok {
+			return fmt.Errorf("apparatus with code %s not found", code)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &apparatus, nil
+}
+
+// GetAllApparatuses returns the apparatus ordered by code.
+func (d *ApparatusDAO) GetAllApparatuses(ctx context.Context) ([]model.Apparatus, error) {
+	var apparatuses []model.Apparatus
+	err := d.store.read(ctx, func() error {
+		apparatuses = d.store.apparatus.list(nil)
+		return nil
+	})
+	slices.SortFunc(apparatuses, func(a, b model.Apparatus) int {
+		return strings.Compare(a.ApparatusCode, b.ApparatusCode)
+	})
+	return apparatuses, err
+}
+
+func (d *ApparatusDAO) CreateApparatus(ctx context.Context, appReq *model.ApparatusRequest) error {
+	app := model.Apparatus{
+		ApparatusFields: appReq.ApparatusFields,
+		AuditRecord:     model.AuditRecord{CreatedBy: appReq.CreatedBy, CreatedAt: now()},
+	}
+	app.ApparatusCode = strings.ToUpper(app.ApparatusCode)
+	app.ApparatusGroup = dao.ApparatusGroup(app.ApparatusFields)
+	app.UpdatedAt = app.CreatedAt
+	return d.store.write(ctx, func(tx *txn) error {
+		if d.store.apparatus.has(app.ApparatusCode) {
+			return fmt.Errorf("apparatus with code %s %w", app.ApparatusCode, dao.ErrConflict)
+		}
+		d.store.apparatus.put(tx, app.ApparatusCode, app)
+		return nil
+	})
+}
+
+// UpdateApparatus updates an apparatus, keeping its group when the request
+// has none.
+func (d *ApparatusDAO) UpdateApparatus(ctx context.Context, appReq *model.ApparatusRequest) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		app, ok := d.store.apparatus.get(strings.ToUpper(appReq.ApparatusCode))
+		if !ok {
+			return fmt.Errorf("apparatus with Code %s not found", appReq.ApparatusCode)
+		}
+		app.ApparatusName, app.ApparatusDesc = appReq.ApparatusName, appReq.ApparatusDesc
+		if group := strings.TrimSpace(appReq.ApparatusGroup); group != "" {
+			app.ApparatusGroup = group
+		}
+		d.store.apparatus.put(tx, app.ApparatusCode, app)
+		return nil
+	})
+}
+
+// DeleteApparatus deletes an apparatus no exercise or equipment profile
+// needs.
+func (d *ApparatusDAO) DeleteApparatus(ctx context.Context, code string) error {
+	code = strings.ToUpper(code)
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.apparatus.has(code) {
+			return fmt.Errorf("apparatus with code %s not found", code)
+		}
+		for key := range d.store.exerciseApparatus.rows {
+			if key.Code == code {
+				return fmt.Errorf("apparatus with code %s is used by exercises", code)
+			}
+		}
+		for _, profile := range d.store.profiles.rows {
+			if slices.Contains(profile.Apparatus, code) {
+				return fmt.Errorf("apparatus with code %s is used by equipment profiles", code)
+			}
+		}
+		d.store.apparatus.delete(tx, code)
+		return nil
+	})
+}
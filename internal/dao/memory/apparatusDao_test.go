@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApparatusDAO_UpdateApparatus_KeepsGroup(t *testing.T) {
+	appDao := NewApparatusDAO(NewStore())
+	ctx := context.Background()
+
+	assert.NoError(t, appDao.CreateApparatus(ctx, &model.ApparatusRequest{ApparatusFields: model.ApparatusFields{
+		ApparatusCode: "barbell", ApparatusName: "Barbell", ApparatusGroup: "Free weights"}}))
+
+	assert.NoError(t, appDao.UpdateApparatus(ctx, &model.ApparatusRequest{ApparatusFields: model.ApparatusFields{
+		ApparatusCode: "BARBELL", ApparatusName: "Olympic barbell"}}))
+	app, err := appDao.GetApparatusByCode(ctx, "BARBELL")
+	assert.NoError(t, err)
+	assert.Equal(t, "Olympic barbell", app.ApparatusName)
+	assert.Equal(t, "Free weights", app.ApparatusGroup)
+
+	assert.NoError(t, appDao.UpdateApparatus(ctx, &model.ApparatusRequest{ApparatusFields: model.ApparatusFields{
+		ApparatusCode: "BARBELL", ApparatusName: "Olympic barbell", ApparatusGroup: " Barbells "}}))
+	app, err = appDao.GetApparatusByCode(ctx, "BARBELL")
+	assert.NoError(t, err)
+	assert.Equal(t, "Barbells", app.ApparatusGroup)
+}
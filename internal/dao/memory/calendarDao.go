@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CalendarDao provides access to the scheduled workouts and calendar feeds
+// in a store.
+type CalendarDao struct {
+	store *Store
+}
+
+// Ensure CalendarDao implements dao.CalendarDaoInterface
+var _ dao.CalendarDaoInterface = (*CalendarDao)(nil)
+
+// NewCalendarDao creates a new instance of CalendarDao.
+func NewCalendarDao(store *Store) *CalendarDao {
+	return &CalendarDao{store: store}
+}
+
+// CreateScheduledWorkout stores a workout normalized with
+// ScheduledWorkoutFields.Normalize.
+func (d *CalendarDao) CreateScheduledWorkout(ctx context.Context, userUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error) {
+	w := model.ScheduledWorkout{
+		ScheduleUuid:           uuid.New(),
+		UserUuid:               userUuid,
+		ScheduledWorkoutFields: cloneScheduleFields(*fields),
+		CreatedAt:              now(),
+	}
+	w.ScheduledAt, w.UpdatedAt = w.ScheduledAt.UTC(), w.CreatedAt
+	err := d.store.write(ctx, func(tx *txn) error {
+		if w.SessionUuid != nil && !d.store.sessions.has(*w.SessionUuid) {
+			return fmt.Errorf("user %s or session %s %w", userUuid, w.SessionUuid, dao.ErrNotFound)
+		}
+		d.store.scheduled.put(tx, w.ScheduleUuid, w)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.WorkoutsScheduled.Inc()
+	w.ScheduledWorkoutFields = cloneScheduleFields(w.ScheduledWorkoutFields)
+	return &w, nil
+}
+
+// ListScheduledWorkouts lists the workouts a user scheduled in [from, to),
+// earliest first.
+func (d *CalendarDao) ListScheduledWorkouts(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.ScheduledWorkout, error) {
+	var workouts []model.ScheduledWorkout
+	err := d.store.read(ctx, func() error {
+		workouts = d.store.scheduled.list(func(_ uuid.UUID, w model.ScheduledWorkout) bool {
+			return w.UserUuid == userUuid && inRange(w.ScheduledAt, from, to)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range workouts {
+		workouts[i].ScheduledWorkoutFields = cloneScheduleFields(workouts[i].ScheduledWorkoutFields)
+	}
+	slices.SortFunc(workouts, func(a, b model.ScheduledWorkout) int {
+		return cmp.Or(a.ScheduledAt.Compare(b.ScheduledAt), cmp.Compare(a.ScheduleUuid.String(), b.ScheduleUuid.String()))
+	})
+	return workouts, nil
+}
+
+func (d *CalendarDao) GetScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) (*model.ScheduledWorkout, error) {
+	var w model.ScheduledWorkout
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if w, ok = d.store.scheduled.get(scheduleUuid); !ok {
+			return fmt.Errorf("scheduled workout %s %w", scheduleUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.ScheduledWorkoutFields = cloneScheduleFields(w.ScheduledWorkoutFields)
+	return &w, nil
+}
+
+func (d *CalendarDao) UpdateScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error) {
+	var w model.ScheduledWorkout
+	err := d.store.write(ctx, func(tx *txn) error {
+		var ok bool
+		if w, ok = d.store.scheduled.get(scheduleUuid); !ok {
+			return fmt.Errorf("scheduled workout %s %w", scheduleUuid, dao.ErrNotFound)
+		}
+		if fields.SessionUuid != nil && !d.store.sessions.has(*fields.SessionUuid) {
+			return fmt.Errorf("session %s %w", fields.SessionUuid, dao.ErrNotFound)
+		}
+		w.ScheduledWorkoutFields = cloneScheduleFields(*fields)
+		w.ScheduledAt, w.UpdatedAt = w.ScheduledAt.UTC(), now()
+		d.store.scheduled.put(tx, scheduleUuid, w)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.ScheduledWorkoutFields = cloneScheduleFields(w.ScheduledWorkoutFields)
+	return &w, nil
+}
+
+func (d *CalendarDao) DeleteScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.scheduled.delete(tx, scheduleUuid) {
+			return fmt.Errorf("scheduled workout %s %w", scheduleUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// ListCalendarSessions lists the sessions a user started between from and
+// to, without their sets.
+func (d *CalendarDao) ListCalendarSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CalendarSession, error) {
+	sessions := []model.CalendarSession{}
+	err := d.store.read(ctx, func() error {
+		for _, session := range d.store.userSessions(userUuid, from, to) {
+			sessions = append(sessions, model.CalendarSession{
+				SessionUuid:     session.SessionUuid,
+				SessionName:     session.SessionName,
+				StartedAt:       session.StartedAt,
+				DurationSeconds: session.DurationSeconds,
+				Source:          session.Source,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (d *CalendarDao) GetFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if feed, ok = d.store.feeds.get(userUuid); !ok {
+			return fmt.Errorf("calendar feed of user %s %w", userUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// SaveFeed gives the user a feed with token, replacing the token of an
+// existing feed.
+func (d *CalendarDao) SaveFeed(ctx context.Context, userUuid uuid.UUID, token string) (*model.CalendarFeed, error) {
+	feed := model.CalendarFeed{UserUuid: userUuid, Token: token, CreatedAt: now()}
+	err := d.store.write(ctx, func(tx *txn) error {
+		d.store.feeds.put(tx, userUuid, feed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (d *CalendarDao) DeleteFeed(ctx context.Context, userUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.feeds.delete(tx, userUuid) {
+			return fmt.Errorf("calendar feed of user %s %w", userUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// GetFeedUser returns the user whose feed has token.
+func (d *CalendarDao) GetFeedUser(ctx context.Context, token string) (uuid.UUID, error) {
+	userUuid := uuid.Nil
+	err := d.store.read(ctx, func() error {
+		for _, feed := range d.store.feeds.rows {
+			if feed.Token == token {
+				userUuid = feed.UserUuid
+				return nil
+			}
+		}
+		return fmt.Errorf("calendar feed %w", dao.ErrNotFound)
+	})
+	return userUuid, err
+}
+
+// cloneScheduleFields copies the optional fields of a scheduled workout so
+// the stored one is not shared with callers.
+func cloneScheduleFields(fields model.ScheduledWorkoutFields) model.ScheduledWorkoutFields {
+	if fields.DurationMinutes != nil {
+		minutes := *fields.DurationMinutes
+		fields.DurationMinutes = &minutes
+	}
+	if fields.SessionUuid != nil {
+		session := *fields.SessionUuid
+		fields.SessionUuid = &session
+	}
+	return fields
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CardioDao provides access to the activities recorded on devices in a
+// store.
+type CardioDao struct {
+	store *Store
+}
+
+// Ensure CardioDao implements dao.CardioDaoInterface
+var _ dao.CardioDaoInterface = (*CardioDao)(nil)
+
+// NewCardioDao creates a new instance of CardioDao.
+func NewCardioDao(store *Store) *CardioDao {
+	return &CardioDao{store: store}
+}
+
+// GetExerciseCategory returns the category code of an exercise.
+func (d *CardioDao) GetExerciseCategory(ctx context.Context, exUuid uuid.UUID) (string, error) {
+	var category string
+	err := d.store.read(ctx, func() error {
+		ex, ok := d.store.exercises.get(exUuid)
+		if !ok {
+			return fmt.Errorf("exercise with uuid %s %w", exUuid, dao.ErrNotFound)
+		}
+		category = ex.CategoryCode
+		return nil
+	})
+	return category, err
+}
+
+// FindActivityByStart returns the activity of a user that started at
+// startedAt, if any, so the same recording is not imported twice.
+func (d *CardioDao) FindActivityByStart(ctx context.Context, userUuid uuid.UUID, startedAt time.Time) (*uuid.UUID, error) {
+	var found *uuid.UUID
+	err := d.store.read(ctx, func() error {
+		for sessionUuid, activity := range d.store.cardio.rows {
+			if activity.UserUuid == userUuid && activity.StartedAt.Equal(startedAt) {
+				found = &sessionUuid
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// CreateActivity stores the activity as a session with one set of its
+// exercise, together with its summary and heart rate samples, at once or in
+// the unit of work it is called in.
+func (d *CardioDao) CreateActivity(ctx context.Context, activity *model.CardioActivity) error {
+	var session *model.WorkoutSession
+	err := d.store.write(ctx, func(tx *txn) error {
+		distance, duration := activity.DistanceM, activity.DurationSeconds
+		var err error
+		session, err = d.store.insertSession(tx, activity.UserUuid, model.WorkoutSessionRequest{
+			WorkoutSessionFields: model.WorkoutSessionFields{
+				SessionName:     activity.SessionName,
+				StartedAt:       activity.StartedAt,
+				DurationSeconds: &duration,
+				Source:          activity.Source,
+			},
+			Sets: []model.WorkoutSet{{ExerciseUuid: activity.ExerciseUuid, DistanceM: &distance, DurationSeconds: &duration}},
+		})
+		if err != nil {
+			return err
+		}
+
+		stored := model.CardioActivity{
+			SessionUuid:   session.SessionUuid,
+			UserUuid:      session.UserUuid,
+			ExerciseUuid:  activity.ExerciseUuid,
+			SessionName:   session.SessionName,
+			StartedAt:     session.StartedAt,
+			Source:        session.Source,
+			CardioSummary: activity.CardioSummary,
+			HeartRate:     slices.Clone(activity.HeartRate),
+			CreatedAt:     session.CreatedAt,
+		}
+		slices.SortFunc(stored.HeartRate, func(a, b model.HeartRateSample) int {
+			return cmp.Compare(a.OffsetSeconds, b.OffsetSeconds)
+		})
+		d.store.cardio.put(tx, stored.SessionUuid, stored)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	countLogged(session)
+	activity.SessionUuid = session.SessionUuid
+	activity.StartedAt = session.StartedAt
+	activity.CreatedAt = session.CreatedAt
+	return nil
+}
+
+// ListActivities returns the activities of a user started in [from, to),
+// oldest first, without their heart rate samples.
+func (d *CardioDao) ListActivities(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CardioActivity, error) {
+	var activities []model.CardioActivity
+	err := d.store.read(ctx, func() error {
+		activities = d.store.cardio.list(func(_ uuid.UUID, activity model.CardioActivity) bool {
+			return activity.UserUuid == userUuid && inRange(activity.StartedAt, from, to)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range activities {
+		activities[i].HeartRate = nil
+	}
+	slices.SortFunc(activities, func(a, b model.CardioActivity) int {
+		return cmp.Or(a.StartedAt.Compare(b.StartedAt), cmp.Compare(a.SessionUuid.String(), b.SessionUuid.String()))
+	})
+	return activities, nil
+}
+
+// GetActivity returns a single activity with its heart rate samples.
+func (d *CardioDao) GetActivity(ctx context.Context, sessionUuid uuid.UUID) (*model.CardioActivity, error) {
+	var activity model.CardioActivity
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if activity, ok = d.store.cardio.get(sessionUuid); !ok {
+			return fmt.Errorf("activity with uuid %s %w", sessionUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	activity.HeartRate = slices.Clone(activity.HeartRate)
+	if activity.HeartRate == nil {
+		activity.HeartRate = []model.HeartRateSample{}
+	}
+	return &activity, nil
+}
@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CatalogDao provides bulk access to the exercises in a store together with
+// their muscle and apparatus links.
+type CatalogDao struct {
+	store *Store
+}
+
+// Ensure CatalogDao implements dao.CatalogDaoInterface
+var _ dao.CatalogDaoInterface = (*CatalogDao)(nil)
+
+// NewCatalogDao creates a new instance of CatalogDao.
+func NewCatalogDao(store *Store) *CatalogDao {
+	return &CatalogDao{store: store}
+}
+
+// FindExercisesByName returns the uuids of existing exercises keyed by their
+// lower-cased name. Names that do not exist are absent from the map.
+func (d *CatalogDao) FindExercisesByName(ctx context.Context, names []string) (map[string]uuid.UUID, error) {
+	lowered := make(map[string]bool, len(names))
+	for _, name := range names {
+		lowered[strings.ToLower(name)] = true
+	}
+
+	found := map[string]uuid.UUID{}
+	err := d.store.read(ctx, func() error {
+		for _, ex := range d.store.exercises.rows {
+			if name := strings.ToLower(ex.ExerciseName); lowered[name] {
+				found[name] = ex.ExerciseUuid
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// ListExercises returns all exercises ordered by name together with their
+// muscles and apparatus.
+func (d *CatalogDao) ListExercises(ctx context.Context) ([]model.CatalogExercise, error) {
+	var catalog []model.CatalogExercise
+	err := d.store.read(ctx, func() error {
+		exercises := d.store.sortedExercises()
+		catalog = make([]model.CatalogExercise, len(exercises))
+		for i, ex := range exercises {
+			catalog[i] = model.CatalogExercise{
+				ExerciseFields: ex.ExerciseFields,
+				Muscles:        d.store.exerciseMusclesOf(ex.ExerciseUuid),
+				Apparatus:      d.store.exerciseApparatusOf(ex.ExerciseUuid),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// ListExerciseNames returns the uuid and name of every exercise.
+func (d *CatalogDao) ListExerciseNames(ctx context.Context) ([]model.ExerciseName, error) {
+	var names []model.ExerciseName
+	err := d.store.read(ctx, func() error {
+		for _, ex := range d.store.sortedExercises() {
+			names = append(names, model.ExerciseName{ExerciseUuid: ex.ExerciseUuid, ExerciseName: ex.ExerciseName})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+/*
+ * ImportCatalog writes the reference types in refs, which may be nil, and
+ * then all entries as one unit of work. Missing reference types are created;
+ * on upsert existing ones are replaced as well. When upsert is true an
+ * exercise with the same name is updated in place and its muscle and
+ * apparatus links are replaced, otherwise a new exercise is always created.
+ * Returns the uuid of each entry in input order.
+ */
+func (d *CatalogDao) ImportCatalog(ctx context.Context, refs *model.CatalogReferences, entries []model.CatalogExercise, upsert bool, createdBy uuid.UUID) ([]uuid.UUID, error) {
+	uuids := make([]uuid.UUID, 0, len(entries))
+	err := d.store.write(ctx, func(tx *txn) error {
+		if refs != nil {
+			d.store.importReferences(tx, refs, upsert, createdBy)
+		}
+		for i := range entries {
+			exUuid, err := d.store.importExercise(tx, &entries[i], upsert, createdBy)
+			if err != nil {
+				return err
+			}
+			uuids = append(uuids, exUuid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uuids, nil
+}
+
+func (s *Store) importReferences(tx *txn, refs *model.CatalogReferences, upsert bool, createdBy uuid.UUID) {
+	audit := model.AuditRecord{CreatedBy: createdBy, CreatedAt: now()}
+	audit.UpdatedAt = audit.CreatedAt
+
+	for _, cat := range refs.Categories {
+		cat.CategoryCode = strings.ToUpper(cat.CategoryCode)
+		if old, ok := s.categories.get(cat.CategoryCode); !ok {
+			s.categories.put(tx, cat.CategoryCode, model.Category{CategoryFields: cat, AuditRecord: audit})
+		} else if upsert {
+			old.CategoryName, old.CategoryDesc, old.UpdatedAt = cat.CategoryName, cat.CategoryDesc, audit.UpdatedAt
+			s.categories.put(tx, cat.CategoryCode, old)
+		}
+	}
+	for _, lic := range refs.Licenses {
+		lic.LicenseShortName = strings.ToUpper(lic.LicenseShortName)
+		if old, ok := s.licenses.get(lic.LicenseShortName); !ok {
+			s.licenses.put(tx, lic.LicenseShortName, model.License{LicenseFields: lic, AuditRecord: audit})
+		} else if upsert {
+			old.LicenseFullName, old.LicenseUrl, old.UpdatedAt = lic.LicenseFullName, lic.LicenseUrl, audit.UpdatedAt
+			s.licenses.put(tx, lic.LicenseShortName, old)
+		}
+	}
+	// Parents are always higher up the hierarchy, so importing region by
+	// region down to the heads creates them before their children.
+	muscles := slices.Clone(refs.Muscles)
+	slices.SortStableFunc(muscles, func(a, b model.MuscleFields) int {
+		return muscleLevel(a).Depth() - muscleLevel(b).Depth()
+	})
+	for _, mus := range muscles {
+		mus.MuscleCode, mus.MuscleLevel = strings.ToUpper(mus.MuscleCode), muscleLevel(mus)
+		if mus.ParentCode != nil {
+			parent := strings.ToUpper(*mus.ParentCode)
+			mus.ParentCode = &parent
+		}
+		if old, ok := s.muscles.get(mus.MuscleCode); !ok {
+			s.muscles.put(tx, mus.MuscleCode, model.Muscle{MuscleFields: mus, AuditRecord: audit})
+		} else if upsert {
+			old.MuscleFields, old.UpdatedAt = mus, audit.UpdatedAt
+			s.muscles.put(tx, mus.MuscleCode, old)
+		}
+	}
+	for _, app := range refs.Apparatus {
+		app.ApparatusCode, app.ApparatusGroup = strings.ToUpper(app.ApparatusCode), dao.ApparatusGroup(app)
+		if old, ok := s.apparatus.get(app.ApparatusCode); !ok {
+			s.apparatus.put(tx, app.ApparatusCode, model.Apparatus{ApparatusFields: app, AuditRecord: audit})
+		} else if upsert {
+			old.ApparatusFields, old.UpdatedAt = app, audit.UpdatedAt
+			s.apparatus.put(tx, app.ApparatusCode, old)
+		}
+	}
+}
+
+func (s *Store) importExercise(tx *txn, entry *model.CatalogExercise, upsert bool, createdBy uuid.UUID) (uuid.UUID, error) {
+	if err := s.checkExerciseReferences(entry.ExerciseFields); err != nil {
+		return uuid.Nil, err
+	}
+
+	var ex model.Exercise
+	found := false
+	if upsert {
+		for _, row := range s.exercises.rows {
+			if strings.EqualFold(row.ExerciseName, entry.ExerciseName) {
+				ex, found = row, true
+				break
+			}
+		}
+	}
+	if found {
+		ex.ExerciseFields = entry.ExerciseFields
+		s.exerciseMuscles.deleteWhere(tx, func(key exerciseCodeKey, _ model.MuscleRole) bool {
+			return key.Exercise == ex.ExerciseUuid
+		})
+		s.exerciseApparatus.deleteWhere(tx, func(key exerciseCodeKey, _ bool) bool {
+			return key.Exercise == ex.ExerciseUuid
+		})
+	} else {
+		ex = model.Exercise{
+			ExerciseUuid:   uuid.New(),
+			ExerciseFields: entry.ExerciseFields,
+			AuditRecord:    model.AuditRecord{CreatedBy: createdBy, CreatedAt: now()},
+		}
+		ex.UpdatedAt = ex.CreatedAt
+	}
+	s.exercises.put(tx, ex.ExerciseUuid, ex)
+
+	for _, mus := range entry.Muscles {
+		if err := s.addExerciseMuscle(tx, ex.ExerciseUuid, mus); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	for _, app := range entry.Apparatus {
+		if err := s.addExerciseApparatus(tx, ex.ExerciseUuid, app); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return ex.ExerciseUuid, nil
+}
+
+// sortedExercises returns all exercises ordered by name.
+func (s *Store) sortedExercises() []model.Exercise {
+	exercises := s.exercises.list(nil)
+	slices.SortFunc(exercises, func(a, b model.Exercise) int {
+		return cmp.Or(strings.Compare(a.ExerciseName, b.ExerciseName),
+			strings.Compare(a.ExerciseUuid.String(), b.ExerciseUuid.String()))
+	})
+	return exercises
+}
+
+// exerciseMusclesOf returns the muscles an exercise works ordered by role
+// and code.
+func (s *Store) exerciseMusclesOf(exUuid uuid.UUID) []model.ExerciseMuscle {
+	muscles := []model.ExerciseMuscle{}
+	for key, role := range s.exerciseMuscles.rows {
+		if key.Exercise == exUuid {
+			muscles = append(muscles, model.ExerciseMuscle{MuscleCode: key.Code, MuscleRole: role})
+		}
+	}
+	slices.SortFunc(muscles, func(a, b model.ExerciseMuscle) int {
+		return cmp.Or(cmp.Compare(roleOrder(a.MuscleRole), roleOrder(b.MuscleRole)), strings.Compare(a.MuscleCode, b.MuscleCode))
+	})
+	return muscles
+}
+
+// exerciseApparatusOf returns the codes of the apparatus an exercise needs
+// in order.
+func (s *Store) exerciseApparatusOf(exUuid uuid.UUID) []string {
+	codes := []string{}
+	for key := range s.exerciseApparatus.rows {
+		if key.Exercise == exUuid {
+			codes = append(codes, key.Code)
+		}
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// muscleLevel defaults the level of muscles exported before the hierarchy
+// existed.
+func muscleLevel(mus model.MuscleFields) model.MuscleLevel {
+	if mus.MuscleLevel == "" {
+		return model.MuscleLevelMuscle
+	}
+	return mus.MuscleLevel
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogDao_ImportCatalog(t *testing.T) {
+	store := NewStore()
+	catDao := NewCatalogDao(store)
+	ctx := context.Background()
+	legs := "legs"
+	refs := &model.CatalogReferences{
+		Categories: []model.CategoryFields{{CategoryCode: "strength", CategoryName: "Strength"}},
+		// children before their parents are imported once the parents exist
+		Muscles: []model.MuscleFields{
+			{MuscleCode: "quads", MuscleName: "Quadriceps", ParentCode: &legs},
+			{MuscleCode: "legs", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		},
+		Apparatus: []model.ApparatusFields{{ApparatusCode: "barbell", ApparatusName: "Barbell"}},
+	}
+	squat := model.CatalogExercise{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"},
+		Muscles:        []model.ExerciseMuscle{{MuscleCode: "quads", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus:      []string{"barbell"},
+	}
+
+	uuids, err := catDao.ImportCatalog(ctx, refs, []model.CatalogExercise{squat}, false, uuid.Nil)
+	assert.NoError(t, err)
+	assert.Len(t, uuids, 1)
+
+	squat.ExerciseName, squat.Cues, squat.Apparatus = "squat", "Brace", nil
+	upserted, err := catDao.ImportCatalog(ctx, nil, []model.CatalogExercise{squat}, true, uuid.Nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uuids, upserted)
+
+	catalog, err := catDao.ListExercises(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CatalogExercise{{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "squat", Cues: "Brace", CategoryCode: "STRENGTH"},
+		Muscles:        []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus:      []string{},
+	}}, catalog)
+
+	found, err := catDao.FindExercisesByName(ctx, []string{"SQUAT", "Lunge"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uuid.UUID{"squat": uuids[0]}, found)
+}
+
+func TestCatalogDao_ImportCatalog_Rollback(t *testing.T) {
+	store := newSeededStore(t)
+	catDao := NewCatalogDao(store)
+
+	_, err := catDao.ImportCatalog(context.Background(), nil, []model.CatalogExercise{
+		{ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}},
+		{ExerciseFields: model.ExerciseFields{ExerciseName: "Curl", CategoryCode: "STRENGTH"},
+			Muscles: []model.ExerciseMuscle{{MuscleCode: "BICEPS", MuscleRole: model.MuscleRolePrimary}}},
+	}, false, uuid.Nil)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+
+	names, err := catDao.ListExerciseNames(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
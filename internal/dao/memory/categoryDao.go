@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Ensure CategoryDAO implements dao.CategoryDaoInterface
+var _ dao.CategoryDaoInterface = (*CategoryDAO)(nil)
+
+// CategoryDAO provides access to the categories in a store.
+type CategoryDAO struct {
+	store *Store
+}
+
+// NewCategoryDAO creates a new instance of CategoryDAO.
+func NewCategoryDAO(store *Store) *CategoryDAO {
+	return &CategoryDAO{store: store}
+}
+
+func (d *CategoryDAO) GetCategoryByCode(ctx context.Context, catCode string) (*model.Category, error) {
+	code := strings.ToUpper(catCode)
+	var category model.Category
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if category, ok = d.store.categories.get(code); !ok {
+			return fmt.Errorf("category with code %s not found", code)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetAllCategories returns the categories ordered by code.
+func (d *CategoryDAO) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	var categories []model.Category
+	err := d.store.read(ctx, func() error {
+		categories = d.store.categories.list(nil)
+		return nil
+	})
+	slices.SortFunc(categories, func(a, b model.Category) int {
+		return strings.Compare(a.CategoryCode, b.CategoryCode)
+	})
+	return categories, err
+}
+
+func (d *CategoryDAO) CreateCategory(ctx context.Context, catReq *model.CategoryRequest) (model.Category, error) {
+	cat := model.Category{
+		CategoryFields: catReq.CategoryFields,
+		AuditRecord:    model.AuditRecord{CreatedBy: catReq.CreatedBy, CreatedAt: now()},
+	}
+	cat.UpdatedAt = cat.CreatedAt
+	err := d.store.write(ctx, func(tx *txn) error {
+		stored := cat
+		stored.CategoryCode = strings.ToUpper(cat.CategoryCode)
+		if d.store.categories.has(stored.CategoryCode) {
+			return fmt.Errorf("category with code %s %w", stored.CategoryCode, dao.ErrConflict)
+		}
+		d.store.categories.put(tx, stored.CategoryCode, stored)
+		return nil
+	})
+	return cat, err
+}
+
+func (d *CategoryDAO) UpdateCategory(ctx context.Context, catReq *model.CategoryRequest) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		cat, ok := d.store.categories.get(strings.ToUpper(catReq.CategoryCode))
+		if !ok {
+			return fmt.Errorf("category with Code %s not found", catReq.CategoryCode)
+		}
+		cat.CategoryName, cat.CategoryDesc = catReq.CategoryName, catReq.CategoryDesc
+		d.store.categories.put(tx, cat.CategoryCode, cat)
+		return nil
+	})
+}
+
+// DeleteCategory deletes a category no exercise is in.
+func (d *CategoryDAO) DeleteCategory(ctx context.Context, code string) error {
+	code = strings.ToUpper(code)
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.categories.has(code) {
+			return fmt.Errorf("category with code %s not found", code)
+		}
+		if d.store.referencedByExercise(func(ex model.Exercise) bool { return ex.CategoryCode == code }) {
+			return fmt.Errorf("category with code %s is used by exercises", code)
+		}
+		d.store.categories.delete(tx, code)
+		return nil
+	})
+}
+
+// referencedByExercise reports whether any exercise matches.
+func (s *Store) referencedByExercise(match func(ex model.Exercise) bool) bool {
+	for _, ex := range s.exercises.rows {
+		if match(ex) {
+			return true
+		}
+	}
+	return false
+}
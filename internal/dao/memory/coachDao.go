@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// CoachDao provides access to the rosters of the coaches in a store.
+type CoachDao struct {
+	store *Store
+}
+
+// Ensure CoachDao implements dao.CoachDaoInterface
+var _ dao.CoachDaoInterface = (*CoachDao)(nil)
+
+// NewCoachDao creates a new instance of CoachDao.
+func NewCoachDao(store *Store) *CoachDao {
+	return &CoachDao{store: store}
+}
+
+// AddClient adds a client to the roster of a coach, keeping a client added
+// before as is.
+func (d *CoachDao) AddClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		key := clientKey{Coach: coachUuid, Client: clientUuid}
+		if !d.store.clients.has(key) {
+			d.store.clients.put(tx, key, now())
+		}
+		return nil
+	})
+}
+
+func (d *CoachDao) RemoveClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.clients.delete(tx, clientKey{Coach: coachUuid, Client: clientUuid}) {
+			return fmt.Errorf("client %s of coach %s %w", clientUuid, coachUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// ListClients returns the roster of a coach. As users are implicit in a
+// store their names are empty.
+func (d *CoachDao) ListClients(ctx context.Context, coachUuid uuid.UUID) ([]model.RosterClient, error) {
+	clients := []model.RosterClient{}
+	err := d.store.read(ctx, func() error {
+		for key, addedAt := range d.store.clients.rows {
+			if key.Coach == coachUuid {
+				clients = append(clients, model.RosterClient{UserUuid: key.Client, AddedAt: addedAt})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(clients, func(a, b model.RosterClient) int {
+		return cmp.Compare(a.UserUuid.String(), b.UserUuid.String())
+	})
+	return clients, nil
+}
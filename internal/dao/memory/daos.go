@@ -0,0 +1,29 @@
+package memory
+
+import "github.com/pwydra/shred/internal/dao"
+
+// NewDaos creates the DAOs on a store.
+func NewDaos(store *Store) *dao.Daos {
+	return &dao.Daos{
+		Exercises:    NewExerciseDao(store),
+		Catalog:      NewCatalogDao(store),
+		Muscles:      NewMuscleDAO(store),
+		Categories:   NewCategoryDAO(store),
+		Apparatus:    NewApparatusDAO(store),
+		Licenses:     NewLicenseDAO(store),
+		Relations:    NewRelationDao(store),
+		Translations: NewTranslationDao(store),
+		Mappings:     NewExerciseMappingDao(store),
+		Users:        NewUserDao(store),
+		Workouts:     NewWorkoutDao(store),
+		Cardio:       NewCardioDao(store),
+		Equipment:    NewEquipmentDao(store),
+		Recovery:     NewRecoveryDao(store),
+		Planner:      NewPlannerDao(store),
+		Measurements: NewMeasurementDao(store),
+		Analytics:    NewAnalyticsDao(store),
+		Calendar:     NewCalendarDao(store),
+		Coach:        NewCoachDao(store),
+		UnitOfWork:   NewUnitOfWork(store),
+	}
+}
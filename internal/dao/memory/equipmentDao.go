@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// EquipmentDao provides access to the equipment profiles of users and gym
+// locations in a store and to the exercises that can be done with them.
+type EquipmentDao struct {
+	store *Store
+}
+
+// Ensure EquipmentDao implements dao.EquipmentDaoInterface
+var _ dao.EquipmentDaoInterface = (*EquipmentDao)(nil)
+
+// NewEquipmentDao creates a new instance of EquipmentDao.
+func NewEquipmentDao(store *Store) *EquipmentDao {
+	return &EquipmentDao{store: store}
+}
+
+// CreateProfile creates a profile for the user, or a gym location when
+// userUuid is nil. A new default profile replaces the previous default.
+func (d *EquipmentDao) CreateProfile(ctx context.Context, userUuid *uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error) {
+	if userUuid == nil && req.IsDefault {
+		return nil, fmt.Errorf("a gym location cannot be a default profile: %w", dao.ErrConflict)
+	}
+	profile := model.EquipmentProfile{
+		ProfileUuid:            uuid.New(),
+		UserUuid:               userUuid,
+		EquipmentProfileFields: req.EquipmentProfileFields,
+		AuditRecord:            model.AuditRecord{CreatedBy: req.CreatedBy, CreatedAt: now()},
+	}
+	profile.Apparatus = dao.ApparatusCodes(req.Apparatus)
+	profile.UpdatedAt = profile.CreatedAt
+	err := d.store.write(ctx, func(tx *txn) error {
+		return d.store.putProfile(tx, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListProfiles lists the profiles of a user, or the gym locations when
+// userUuid is nil, the default first and then by name.
+func (d *EquipmentDao) ListProfiles(ctx context.Context, userUuid *uuid.UUID) ([]model.EquipmentProfile, error) {
+	var profiles []model.EquipmentProfile
+	err := d.store.read(ctx, func() error {
+		profiles = d.store.profiles.list(func(_ uuid.UUID, profile model.EquipmentProfile) bool {
+			return sameUser(profile.UserUuid, userUuid)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		profiles[i] = cloneProfile(profiles[i])
+	}
+	slices.SortFunc(profiles, func(a, b model.EquipmentProfile) int {
+		return cmp.Or(-cmp.Compare(boolOrder(a.IsDefault), boolOrder(b.IsDefault)), strings.Compare(a.ProfileName, b.ProfileName))
+	})
+	return profiles, nil
+}
+
+func (d *EquipmentDao) GetProfile(ctx context.Context, profileUuid uuid.UUID) (*model.EquipmentProfile, error) {
+	var profile model.EquipmentProfile
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if profile, ok = d.store.profiles.get(profileUuid); !ok {
+			return fmt.Errorf("equipment profile %s %w", profileUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	profile = cloneProfile(profile)
+	return &profile, nil
+}
+
+func (d *EquipmentDao) GetDefaultProfile(ctx context.Context, userUuid uuid.UUID) (*model.EquipmentProfile, error) {
+	var profile *model.EquipmentProfile
+	err := d.store.read(ctx, func() error {
+		for _, row := range d.store.profiles.rows {
+			if row.IsDefault && sameUser(row.UserUuid, &userUuid) {
+				found := cloneProfile(row)
+				profile = &found
+				return nil
+			}
+		}
+		return fmt.Errorf("default equipment profile of user %s %w", userUuid, dao.ErrNotFound)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UpdateProfile renames a profile and replaces its apparatus.
+func (d *EquipmentDao) UpdateProfile(ctx context.Context, profileUuid uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error) {
+	var profile model.EquipmentProfile
+	err := d.store.write(ctx, func(tx *txn) error {
+		old, ok := d.store.profiles.get(profileUuid)
+		if !ok {
+			return fmt.Errorf("equipment profile %s %w", profileUuid, dao.ErrNotFound)
+		}
+		if req.IsDefault && old.UserUuid == nil {
+			return fmt.Errorf("a gym location cannot be a default profile: %w", dao.ErrConflict)
+		}
+		profile = old
+		profile.EquipmentProfileFields = req.EquipmentProfileFields
+		profile.Apparatus = dao.ApparatusCodes(req.Apparatus)
+		profile.UpdatedAt = now()
+		return d.store.putProfile(tx, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (d *EquipmentDao) DeleteProfile(ctx context.Context, profileUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.profiles.delete(tx, profileUuid) {
+			return fmt.Errorf("equipment profile %s %w", profileUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// FindAvailableExercises returns the exercises, optionally of a category,
+// whose apparatus are all in a profile, ordered by name. Exercises without
+// apparatus need nothing and are always available.
+func (d *EquipmentDao) FindAvailableExercises(ctx context.Context, profileUuid uuid.UUID, category string) ([]model.AvailableExercise, error) {
+	category = strings.ToUpper(category)
+	exercises := []model.AvailableExercise{}
+	err := d.store.read(ctx, func() error {
+		profile, ok := d.store.profiles.get(profileUuid)
+		if !ok {
+			return fmt.Errorf("equipment profile %s %w", profileUuid, dao.ErrNotFound)
+		}
+		for _, ex := range d.store.sortedExercises() {
+			if category != "" && ex.CategoryCode != category {
+				continue
+			}
+			apparatus := d.store.exerciseApparatusOf(ex.ExerciseUuid)
+			if !slices.ContainsFunc(apparatus, func(code string) bool { return !slices.Contains(profile.Apparatus, code) }) {
+				exercises = append(exercises, model.AvailableExercise{
+					ExerciseUuid: ex.ExerciseUuid,
+					ExerciseName: ex.ExerciseName,
+					CategoryCode: ex.CategoryCode,
+					Apparatus:    apparatus,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
+
+/*
+ * putProfile stores a new or changed profile within tx. Its name must be
+ * unique among the profiles of its user, or among the gym locations, and its
+ * apparatus must exist. A default profile replaces the previous default.
+ */
+func (s *Store) putProfile(tx *txn, profile model.EquipmentProfile) error {
+	for _, other := range s.profiles.rows {
+		if other.ProfileUuid != profile.ProfileUuid && sameUser(other.UserUuid, profile.UserUuid) &&
+			strings.EqualFold(other.ProfileName, profile.ProfileName) {
+			return fmt.Errorf("equipment profile '%s' %w", profile.ProfileName, dao.ErrConflict)
+		}
+	}
+	for _, code := range profile.Apparatus {
+		if !s.apparatus.has(code) {
+			return fmt.Errorf("apparatus in %s %w", strings.Join(profile.Apparatus, ", "), dao.ErrNotFound)
+		}
+	}
+	if profile.IsDefault {
+		for _, other := range s.profiles.rows {
+			if other.IsDefault && other.ProfileUuid != profile.ProfileUuid && sameUser(other.UserUuid, profile.UserUuid) {
+				other.IsDefault = false
+				s.profiles.put(tx, other.ProfileUuid, other)
+			}
+		}
+	}
+	s.profiles.put(tx, profile.ProfileUuid, cloneProfile(profile))
+	return nil
+}
+
+// sameUser reports whether two profiles belong to the same user, or are
+// both gym locations.
+func sameUser(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// cloneProfile copies a profile so callers cannot change the stored one.
+func cloneProfile(profile model.EquipmentProfile) model.EquipmentProfile {
+	profile.Apparatus = slices.Clone(profile.Apparatus)
+	if profile.Apparatus == nil {
+		profile.Apparatus = []string{}
+	}
+	if profile.UserUuid != nil {
+		user := *profile.UserUuid
+		profile.UserUuid = &user
+	}
+	return profile
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquipmentDao_Profiles(t *testing.T) {
+	eqDao := NewEquipmentDao(newSeededStore(t))
+	ctx := context.Background()
+	userUuid := uuid.New()
+	home := model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Home", IsDefault: true, Apparatus: []string{"barbell"}}}
+
+	first, err := eqDao.CreateProfile(ctx, &userUuid, &home)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BARBELL"}, first.Apparatus)
+	_, err = eqDao.CreateProfile(ctx, &userUuid, &home)
+	assert.ErrorIs(t, err, dao.ErrConflict)
+
+	gym := model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Gym", IsDefault: true, Apparatus: []string{"BARBELL", "BENCH"}}}
+	second, err := eqDao.CreateProfile(ctx, &userUuid, &gym)
+	assert.NoError(t, err)
+
+	profile, err := eqDao.GetDefaultProfile(ctx, userUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ProfileUuid, profile.ProfileUuid)
+	profiles, err := eqDao.ListProfiles(ctx, &userUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Gym", "Home"}, []string{profiles[0].ProfileName, profiles[1].ProfileName})
+
+	_, err = eqDao.CreateProfile(ctx, nil, &gym)
+	assert.ErrorIs(t, err, dao.ErrConflict, "gym locations cannot be defaults")
+	gym.Apparatus = []string{"KETTLEBELL"}
+	_, err = eqDao.UpdateProfile(ctx, second.ProfileUuid, &gym)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+
+	assert.NoError(t, eqDao.DeleteProfile(ctx, first.ProfileUuid))
+	assert.ErrorIs(t, eqDao.DeleteProfile(ctx, first.ProfileUuid), dao.ErrNotFound)
+}
+
+func TestEquipmentDao_FindAvailableExercises(t *testing.T) {
+	store := newSeededStore(t)
+	eqDao := NewEquipmentDao(store)
+	exDao := NewExerciseDao(store)
+	ctx := context.Background()
+	pushUp := createExercise(t, store, "Push-up")
+	squat := createExercise(t, store, "Squat")
+	bench := createExercise(t, store, "Bench press")
+	assert.NoError(t, exDao.AddApparatus(ctx, squat, []string{"BARBELL"}))
+	assert.NoError(t, exDao.AddApparatus(ctx, bench, []string{"BARBELL", "BENCH"}))
+
+	profile, err := eqDao.CreateProfile(ctx, nil, &model.EquipmentProfileRequest{EquipmentProfileFields: model.EquipmentProfileFields{
+		ProfileName: "Garage", Apparatus: []string{"BARBELL"}}})
+	assert.NoError(t, err)
+
+	exercises, err := eqDao.FindAvailableExercises(ctx, profile.ProfileUuid, "strength")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AvailableExercise{
+		{ExerciseUuid: pushUp, ExerciseName: "Push-up", CategoryCode: "STRENGTH", Apparatus: []string{}},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", Apparatus: []string{"BARBELL"}},
+	}, exercises)
+
+	_, err = eqDao.FindAvailableExercises(ctx, uuid.New(), "")
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ExerciseDao provides access to the exercises in a store.
+type ExerciseDao struct {
+	store *Store
+}
+
+// Ensure ExerciseDao implements dao.ExerciseDaoInterface
+var _ dao.ExerciseDaoInterface = (*ExerciseDao)(nil)
+
+// NewExerciseDao creates a new instance of ExerciseDao.
+func NewExerciseDao(store *Store) *ExerciseDao {
+	return &ExerciseDao{store: store}
+}
+
+// Create stores a new exercise. An unknown category or license wraps
+// dao.ErrNotFound.
+func (d *ExerciseDao) Create(ctx context.Context, exReq *model.ExerciseRequest) (*model.Exercise, error) {
+	exercise := model.Exercise{
+		ExerciseUuid:   uuid.New(),
+		ExerciseFields: exReq.ExerciseFields,
+		AuditRecord:    model.AuditRecord{CreatedBy: exReq.CreatedBy, CreatedAt: now()},
+	}
+	exercise.UpdatedAt = exercise.CreatedAt
+	err := d.store.write(ctx, func(tx *txn) error {
+		if err := d.store.checkExerciseReferences(exercise.ExerciseFields); err != nil {
+			return err
+		}
+		d.store.exercises.put(tx, exercise.ExerciseUuid, exercise)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &exercise, nil
+}
+
+// Read returns the exercise with the given UUID. A missing exercise wraps
+// dao.ErrNotFound.
+func (d *ExerciseDao) Read(ctx context.Context, exUuid uuid.UUID) (*model.Exercise, error) {
+	var ex model.Exercise
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if ex, ok = d.store.exercises.get(exUuid); !ok {
+			return fmt.Errorf("exercise with uuid %s %w", exUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ex, nil
+}
+
+// Update replaces the fields of an exercise. Updating an exercise that does
+// not exist does nothing.
+func (d *ExerciseDao) Update(ctx context.Context, exercise *model.Exercise) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		ex, ok := d.store.exercises.get(exercise.ExerciseUuid)
+		if !ok {
+			return nil
+		}
+		if err := d.store.checkExerciseReferences(exercise.ExerciseFields); err != nil {
+			return err
+		}
+		ex.ExerciseFields = exercise.ExerciseFields
+		d.store.exercises.put(tx, ex.ExerciseUuid, ex)
+		return nil
+	})
+}
+
+/*
+ * Delete deletes an exercise with its muscles, apparatus, relations, aliases
+ * and translations. Sets and app mappings that still refer to it wrap
+ * dao.ErrConflict. Deleting an exercise that does not exist does nothing.
+ */
+func (d *ExerciseDao) Delete(ctx context.Context, exUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.exercises.has(exUuid) {
+			return nil
+		}
+		if d.store.exerciseInUse(exUuid) {
+			return fmt.Errorf("a set or app mapping of exercise %s %w", exUuid, dao.ErrConflict)
+		}
+		d.store.exerciseMuscles.deleteWhere(tx, func(key exerciseCodeKey, _ model.MuscleRole) bool {
+			return key.Exercise == exUuid
+		})
+		d.store.exerciseApparatus.deleteWhere(tx, func(key exerciseCodeKey, _ bool) bool {
+			return key.Exercise == exUuid
+		})
+		d.store.relations.deleteWhere(tx, func(key relationKey, _ model.ExerciseRelation) bool {
+			return key.Exercise == exUuid || key.Related == exUuid
+		})
+		d.store.aliases.deleteWhere(tx, func(key exerciseCodeKey, _ model.ExerciseAlias) bool {
+			return key.Exercise == exUuid
+		})
+		d.store.exerciseTranslations.deleteWhere(tx, func(key exerciseCodeKey, _ model.ExerciseTranslation) bool {
+			return key.Exercise == exUuid
+		})
+		d.store.exercises.delete(tx, exUuid)
+		return nil
+	})
+}
+
+// AddMuscles links muscles to an exercise. An unknown muscle wraps
+// dao.ErrNotFound.
+func (d *ExerciseDao) AddMuscles(ctx context.Context, exUuid uuid.UUID, muscles []model.ExerciseMuscle) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		for _, mus := range muscles {
+			if err := d.store.addExerciseMuscle(tx, exUuid, mus); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddApparatus links apparatus to an exercise. An unknown apparatus wraps
+// dao.ErrNotFound.
+func (d *ExerciseDao) AddApparatus(ctx context.Context, exUuid uuid.UUID, codes []string) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		for _, code := range codes {
+			if err := d.store.addExerciseApparatus(tx, exUuid, code); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveMuscles unlinks all muscles from an exercise.
+func (d *ExerciseDao) RemoveMuscles(ctx context.Context, exUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		d.store.exerciseMuscles.deleteWhere(tx, func(key exerciseCodeKey, _ model.MuscleRole) bool {
+			return key.Exercise == exUuid
+		})
+		return nil
+	})
+}
+
+// RemoveApparatus unlinks all apparatus from an exercise.
+func (d *ExerciseDao) RemoveApparatus(ctx context.Context, exUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		d.store.exerciseApparatus.deleteWhere(tx, func(key exerciseCodeKey, _ bool) bool {
+			return key.Exercise == exUuid
+		})
+		return nil
+	})
+}
+
+func (s *Store) addExerciseMuscle(tx *txn, exUuid uuid.UUID, mus model.ExerciseMuscle) error {
+	key := exerciseCodeKey{Exercise: exUuid, Code: strings.ToUpper(mus.MuscleCode)}
+	if !s.exercises.has(exUuid) || !s.muscles.has(key.Code) {
+		return fmt.Errorf("exercise %s or muscle %s %w", exUuid, mus.MuscleCode, dao.ErrNotFound)
+	}
+	if s.exerciseMuscles.has(key) {
+		return fmt.Errorf("muscle %s of exercise %s %w", key.Code, exUuid, dao.ErrConflict)
+	}
+	s.exerciseMuscles.put(tx, key, mus.MuscleRole)
+	return nil
+}
+
+func (s *Store) addExerciseApparatus(tx *txn, exUuid uuid.UUID, code string) error {
+	key := exerciseCodeKey{Exercise: exUuid, Code: strings.ToUpper(code)}
+	if !s.exercises.has(exUuid) || !s.apparatus.has(key.Code) {
+		return fmt.Errorf("exercise %s or apparatus %s %w", exUuid, code, dao.ErrNotFound)
+	}
+	if s.exerciseApparatus.has(key) {
+		return fmt.Errorf("apparatus %s of exercise %s %w", key.Code, exUuid, dao.ErrConflict)
+	}
+	s.exerciseApparatus.put(tx, key, true)
+	return nil
+}
+
+// exerciseInUse reports whether rows that are not deleted with an exercise
+// refer to it: the sets it was done in and the names other apps use for it.
+func (s *Store) exerciseInUse(exUuid uuid.UUID) bool {
+	for _, session := range s.sessions.rows {
+		for _, set := range session.Sets {
+			if set.ExerciseUuid == exUuid {
+				return true
+			}
+		}
+	}
+	for _, mapping := range s.mappings.rows {
+		if mapping.ExerciseUuid == exUuid {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExerciseReferences checks that the category and license, if any, of
+// an exercise exist.
+func (s *Store) checkExerciseReferences(fields model.ExerciseFields) error {
+	if !s.categories.has(fields.CategoryCode) {
+		return fmt.Errorf("category with code %s %w", fields.CategoryCode, dao.ErrNotFound)
+	}
+	if fields.LicenseShortName != "" && !s.licenses.has(fields.LicenseShortName) {
+		return fmt.Errorf("license with short name %s %w", fields.LicenseShortName, dao.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExerciseDao_Create_UnknownReferences(t *testing.T) {
+	exDao := NewExerciseDao(newSeededStore(t))
+	ctx := context.Background()
+
+	_, err := exDao.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Run", CategoryCode: "CARDIO"}})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	_, err = exDao.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH", LicenseShortName: "CC-BY"}})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
+
+func TestExerciseDao_ReadUpdate(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	ctx := context.Background()
+	exUuid := createExercise(t, store, "Squat")
+
+	ex, err := exDao.Read(ctx, exUuid)
+	assert.NoError(t, err)
+	ex.Cues = "Chest up"
+	assert.NoError(t, exDao.Update(ctx, ex))
+
+	ex, err = exDao.Read(ctx, exUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "Chest up", ex.Cues)
+
+	_, err = exDao.Read(ctx, uuid.New())
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	assert.NoError(t, exDao.Update(ctx, &model.Exercise{ExerciseUuid: uuid.New()}))
+}
+
+func TestExerciseDao_AddMuscles(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	ctx := context.Background()
+	exUuid := createExercise(t, store, "Squat", "QUADS")
+
+	err := exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "quads", MuscleRole: model.MuscleRoleSecondary}})
+	assert.ErrorIs(t, err, dao.ErrConflict)
+	err = exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "BICEPS", MuscleRole: model.MuscleRolePrimary}})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	err = exDao.AddApparatus(ctx, uuid.New(), []string{"BARBELL"})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
+
+func TestExerciseDao_Delete(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat", "QUADS")
+	lunge := createExercise(t, store, "Lunge")
+	_, err := NewRelationDao(store).CreateRelation(ctx, lunge,
+		&model.ExerciseRelationRequest{RelatedUuid: squat, Relation: model.RelationAlternativeTo})
+	assert.NoError(t, err)
+	assert.NoError(t, NewTranslationDao(store).AddAlias(ctx, lunge, &model.ExerciseAlias{Alias: "Split squat"}))
+
+	assert.NoError(t, exDao.AddApparatus(ctx, squat, []string{"BARBELL"}))
+	assert.NoError(t, NewExerciseMappingDao(store).SaveMapping(ctx,
+		&model.ExerciseMapping{Source: "strong", ExternalName: "Lunge", ExerciseUuid: lunge}))
+
+	assert.NoError(t, exDao.Delete(ctx, squat))
+	assert.Empty(t, store.exerciseMuscles.rows)
+	assert.Empty(t, store.exerciseApparatus.rows)
+	assert.Empty(t, store.relations.rows)
+	assert.ErrorIs(t, exDao.Delete(ctx, lunge), dao.ErrConflict, "a mapping still refers to it")
+
+	assert.NoError(t, NewExerciseMappingDao(store).DeleteMapping(ctx, "strong", "Lunge"))
+	assert.NoError(t, exDao.Delete(ctx, lunge))
+	assert.Empty(t, store.aliases.rows)
+	assert.NoError(t, exDao.Delete(ctx, lunge))
+}
+
+func TestExerciseDao_RemoveLinks(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat", "QUADS", "GLUTES")
+	createExercise(t, store, "Lunge", "QUADS")
+	assert.NoError(t, exDao.AddApparatus(ctx, squat, []string{"BARBELL"}))
+
+	assert.NoError(t, exDao.RemoveMuscles(ctx, squat))
+	assert.NoError(t, exDao.RemoveApparatus(ctx, squat))
+	assert.Len(t, store.exerciseMuscles.rows, 1, "the muscles of the lunge stay")
+	assert.Empty(t, store.exerciseApparatus.rows)
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ExerciseMappingDao provides access to the exercise names of other
+// tracking applications mapped in a store.
+type ExerciseMappingDao struct {
+	store *Store
+}
+
+// Ensure ExerciseMappingDao implements dao.ExerciseMappingDaoInterface
+var _ dao.ExerciseMappingDaoInterface = (*ExerciseMappingDao)(nil)
+
+// NewExerciseMappingDao creates a new instance of ExerciseMappingDao.
+func NewExerciseMappingDao(store *Store) *ExerciseMappingDao {
+	return &ExerciseMappingDao{store: store}
+}
+
+// GetMappings retrieves all mappings for a source application ordered by
+// external name.
+func (d *ExerciseMappingDao) GetMappings(ctx context.Context, source string) ([]model.ExerciseMapping, error) {
+	source = strings.ToLower(source)
+	var mappings []model.ExerciseMapping
+	err := d.store.read(ctx, func() error {
+		mappings = d.store.mappings.list(func(key mappingKey, _ model.ExerciseMapping) bool {
+			return key.Source == source
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(mappings, func(a, b model.ExerciseMapping) int {
+		return strings.Compare(a.ExternalName, b.ExternalName)
+	})
+	return mappings, nil
+}
+
+// SaveMapping creates a mapping or points an existing one at another
+// exercise. External names are matched case-insensitively.
+func (d *ExerciseMappingDao) SaveMapping(ctx context.Context, mapping *model.ExerciseMapping) error {
+	mapping.Source = strings.ToLower(mapping.Source)
+	mapping.ExternalName = strings.ToLower(strings.TrimSpace(mapping.ExternalName))
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.exercises.has(mapping.ExerciseUuid) {
+			return fmt.Errorf("exercise with uuid %s %w", mapping.ExerciseUuid, dao.ErrNotFound)
+		}
+		key := mappingKey{Source: mapping.Source, ExternalName: mapping.ExternalName}
+		saved := *mapping
+		if old, ok := d.store.mappings.get(key); ok {
+			saved.CreatedBy = old.CreatedBy
+		}
+		d.store.mappings.put(tx, key, saved)
+		return nil
+	})
+}
+
+// DeleteMapping removes a mapping.
+func (d *ExerciseMappingDao) DeleteMapping(ctx context.Context, source, externalName string) error {
+	key := mappingKey{Source: strings.ToLower(source), ExternalName: strings.ToLower(strings.TrimSpace(externalName))}
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.mappings.delete(tx, key) {
+			return fmt.Errorf("mapping for %s exercise '%s' not found", key.Source, externalName)
+		}
+		return nil
+	})
+}
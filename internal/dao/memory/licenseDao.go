@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Ensure LicenseDAO implements dao.LicenseDaoInterface
+var _ dao.LicenseDaoInterface = (*LicenseDAO)(nil)
+
+// LicenseDAO provides access to the licenses in a store.
+type LicenseDAO struct {
+	store *Store
+}
+
+// NewLicenseDAO creates a new instance of LicenseDAO.
+func NewLicenseDAO(store *Store) *LicenseDAO {
+	return &LicenseDAO{store: store}
+}
+
+func (d *LicenseDAO) GetLicenseByShortName(ctx context.Context, licenseShortName string) (*model.License, error) {
+	shortName := strings.ToUpper(licenseShortName)
+	var license model.License
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if license, ok = d.store.licenses.get(shortName); !ok {
+			return fmt.Errorf("license with short name %s not found", shortName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+// GetAllLicenses returns the licenses ordered by short name.
+func (d *LicenseDAO) GetAllLicenses(ctx context.Context) ([]model.License, error) {
+	var licenses []model.License
+	err := d.store.read(ctx, func() error {
+		licenses = d.store.licenses.list(nil)
+		return nil
+	})
+	slices.SortFunc(licenses, func(a, b model.License) int {
+		return strings.Compare(a.LicenseShortName, b.LicenseShortName)
+	})
+	return licenses, err
+}
+
+func (d *LicenseDAO) CreateLicense(ctx context.Context, licenseReq *model.LicenseRequest) error {
+	license := model.License{
+		LicenseFields: licenseReq.LicenseFields,
+		AuditRecord:   model.AuditRecord{CreatedBy: licenseReq.CreatedBy, CreatedAt: now()},
+	}
+	license.LicenseShortName = strings.ToUpper(license.LicenseShortName)
+	license.UpdatedAt = license.CreatedAt
+	return d.store.write(ctx, func(tx *txn) error {
+		if d.store.licenses.has(license.LicenseShortName) {
+			return fmt.Errorf("license with short name %s %w", license.LicenseShortName, dao.ErrConflict)
+		}
+		d.store.licenses.put(tx, license.LicenseShortName, license)
+		return nil
+	})
+}
+
+func (d *LicenseDAO) UpdateLicense(ctx context.Context, licenseReq *model.LicenseRequest) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		license, ok := d.store.licenses.get(strings.ToUpper(licenseReq.LicenseShortName))
+		if !ok {
+			return fmt.Errorf("license with Short Name '%s' not found", licenseReq.LicenseShortName)
+		}
+		license.LicenseFullName, license.LicenseUrl = licenseReq.LicenseFullName, licenseReq.LicenseUrl
+		d.store.licenses.put(tx, license.LicenseShortName, license)
+		return nil
+	})
+}
+
+// DeleteLicense deletes a license no exercise is published under.
+func (d *LicenseDAO) DeleteLicense(ctx context.Context, shortName string) error {
+	shortName = strings.ToUpper(shortName)
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.licenses.has(shortName) {
+			return fmt.Errorf("license with Short Name '%s' not found", shortName)
+		}
+		if d.store.referencedByExercise(func(ex model.Exercise) bool { return ex.LicenseShortName == shortName }) {
+			return fmt.Errorf("license with short name %s is used by exercises", shortName)
+		}
+		d.store.licenses.delete(tx, shortName)
+		return nil
+	})
+}
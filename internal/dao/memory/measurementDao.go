@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// MeasurementDao provides access to the body measurements of the users of a
+// store.
+type MeasurementDao struct {
+	store *Store
+}
+
+// Ensure MeasurementDao implements dao.MeasurementDaoInterface
+var _ dao.MeasurementDaoInterface = (*MeasurementDao)(nil)
+
+// NewMeasurementDao creates a new instance of MeasurementDao.
+func NewMeasurementDao(store *Store) *MeasurementDao {
+	return &MeasurementDao{store: store}
+}
+
+// CreateMeasurement stores a measurement normalized with
+// MeasurementRequest.Normalize.
+func (d *MeasurementDao) CreateMeasurement(ctx context.Context, userUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error) {
+	m := model.Measurement{
+		MeasurementUuid:   uuid.New(),
+		UserUuid:          userUuid,
+		MeasurementFields: req.MeasurementFields,
+		CreatedAt:         now(),
+	}
+	m.MeasuredAt, m.UpdatedAt = m.MeasuredAt.UTC(), m.CreatedAt
+	err := d.store.write(ctx, func(tx *txn) error {
+		d.store.measurements.put(tx, m.MeasurementUuid, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	metrics.MeasurementsRecorded.WithLabelValues(string(m.Kind)).Inc()
+	return &m, nil
+}
+
+// ListMeasurements lists the measurements of a user taken in [from, to),
+// optionally only of one kind and site, oldest first.
+func (d *MeasurementDao) ListMeasurements(ctx context.Context, userUuid uuid.UUID, kind model.MeasurementKind, site string, from, to time.Time) ([]model.Measurement, error) {
+	var measurements []model.Measurement
+	err := d.store.read(ctx, func() error {
+		measurements = d.store.measurements.list(func(_ uuid.UUID, m model.Measurement) bool {
+			return m.UserUuid == userUuid && (kind == "" || m.Kind == kind) && (site == "" || m.Site == site) &&
+				inRange(m.MeasuredAt, from, to)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range measurements {
+		measurements[i].Unit = measurements[i].Kind.Unit()
+	}
+	slices.SortFunc(measurements, func(a, b model.Measurement) int {
+		return cmp.Or(a.MeasuredAt.Compare(b.MeasuredAt), cmp.Compare(a.MeasurementUuid.String(), b.MeasurementUuid.String()))
+	})
+	return measurements, nil
+}
+
+func (d *MeasurementDao) GetMeasurement(ctx context.Context, measurementUuid uuid.UUID) (*model.Measurement, error) {
+	var m model.Measurement
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if m, ok = d.store.measurements.get(measurementUuid); !ok {
+			return fmt.Errorf("measurement %s %w", measurementUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	return &m, nil
+}
+
+func (d *MeasurementDao) UpdateMeasurement(ctx context.Context, measurementUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error) {
+	var m model.Measurement
+	err := d.store.write(ctx, func(tx *txn) error {
+		var ok bool
+		if m, ok = d.store.measurements.get(measurementUuid); !ok {
+			return fmt.Errorf("measurement %s %w", measurementUuid, dao.ErrNotFound)
+		}
+		m.MeasurementFields = req.MeasurementFields
+		m.MeasuredAt, m.UpdatedAt = m.MeasuredAt.UTC(), now()
+		d.store.measurements.put(tx, measurementUuid, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.Unit = m.Kind.Unit()
+	return &m, nil
+}
+
+func (d *MeasurementDao) DeleteMeasurement(ctx context.Context, measurementUuid uuid.UUID) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.measurements.delete(tx, measurementUuid) {
+			return fmt.Errorf("measurement %s %w", measurementUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
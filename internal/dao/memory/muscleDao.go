@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// Ensure MuscleDAO implements dao.MuscleDaoInterface
+var _ dao.MuscleDaoInterface = (*MuscleDAO)(nil)
+
+// MuscleDAO provides access to the muscles in a store.
+type MuscleDAO struct {
+	store *Store
+}
+
+// NewMuscleDAO creates a new instance of MuscleDAO.
+func NewMuscleDAO(store *Store) *MuscleDAO {
+	return &MuscleDAO{store: store}
+}
+
+func (d *MuscleDAO) GetMuscleByCode(ctx context.Context, musCode string) (*model.Muscle, error) {
+	code := strings.ToUpper(musCode)
+	var muscle model.Muscle
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if muscle, ok = d.store.muscles.get(code); !ok {
+			return fmt.Errorf("muscle with code %s %w", code, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &muscle, nil
+}
+
+// GetAllMuscles returns the muscles ordered by code.
+func (d *MuscleDAO) GetAllMuscles(ctx context.Context) ([]model.Muscle, error) {
+	var muscles []model.Muscle
+	err := d.store.read(ctx, func() error {
+		muscles = d.store.muscles.list(nil)
+		return nil
+	})
+	slices.SortFunc(muscles, func(a, b model.Muscle) int {
+		return strings.Compare(a.MuscleCode, b.MuscleCode)
+	})
+	return muscles, err
+}
+
+func (d *MuscleDAO) CreateMuscle(ctx context.Context, musReq *model.MuscleRequest) (model.Muscle, error) {
+	musReq.MuscleCode = strings.ToUpper(musReq.MuscleCode)
+	mus := model.Muscle{
+		MuscleFields: musReq.MuscleFields,
+		AuditRecord:  model.AuditRecord{CreatedBy: musReq.CreatedBy, CreatedAt: now()},
+	}
+	err := d.store.write(ctx, func(tx *txn) error {
+		if err := d.store.checkHierarchy(musReq, false); err != nil {
+			return err
+		}
+		if d.store.muscles.has(musReq.MuscleCode) {
+			return fmt.Errorf("muscle with code %s %w", musReq.MuscleCode, dao.ErrConflict)
+		}
+		mus.MuscleFields = musReq.MuscleFields
+		mus.UpdatedAt = mus.CreatedAt
+		d.store.muscles.put(tx, mus.MuscleCode, mus)
+		return nil
+	})
+	return mus, err
+}
+
+func (d *MuscleDAO) UpdateMuscle(ctx context.Context, musReq *model.MuscleRequest) error {
+	musReq.MuscleCode = strings.ToUpper(musReq.MuscleCode)
+	return d.store.write(ctx, func(tx *txn) error {
+		if err := d.store.checkHierarchy(musReq, true); err != nil {
+			return err
+		}
+		mus, ok := d.store.muscles.get(musReq.MuscleCode)
+		if !ok {
+			return fmt.Errorf("muscle with code %s %w", musReq.MuscleCode, dao.ErrNotFound)
+		}
+		mus.MuscleFields = musReq.MuscleFields
+		d.store.muscles.put(tx, mus.MuscleCode, mus)
+		return nil
+	})
+}
+
+// DeleteMuscle deletes a muscle without muscles below it that no exercise
+// works.
+func (d *MuscleDAO) DeleteMuscle(ctx context.Context, code string) error {
+	code = strings.ToUpper(code)
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.muscles.has(code) {
+			return fmt.Errorf("muscle with code %s %w", code, dao.ErrNotFound)
+		}
+		for _, mus := range d.store.muscles.rows {
+			if mus.ParentCode != nil && *mus.ParentCode == code {
+				return fmt.Errorf("muscle with code %s has muscles below it", code)
+			}
+		}
+		for key := range d.store.exerciseMuscles.rows {
+			if key.Code == code {
+				return fmt.Errorf("muscle with code %s is worked by exercises", code)
+			}
+		}
+		d.store.muscles.delete(tx, code)
+		return nil
+	})
+}
+
+/*
+ * checkHierarchy defaults the level to muscle and checks that the parent, if
+ * any, is higher up the hierarchy. When a muscle is updated its children must
+ * stay below it as well.
+ */
+func (s *Store) checkHierarchy(musReq *model.MuscleRequest, update bool) error {
+	if musReq.MuscleLevel == "" {
+		musReq.MuscleLevel = model.MuscleLevelMuscle
+	}
+	if !musReq.MuscleLevel.Valid() {
+		return fmt.Errorf("unknown muscle level %q: %w", musReq.MuscleLevel, dao.ErrInvalidHierarchy)
+	}
+	if musReq.ParentCode != nil {
+		parent := strings.ToUpper(*musReq.ParentCode)
+		musReq.ParentCode = &parent
+		if musReq.MuscleLevel == model.MuscleLevelRegion {
+			return fmt.Errorf("region %s cannot have a parent: %w", musReq.MuscleCode, dao.ErrInvalidHierarchy)
+		}
+
+		parentMus, ok := s.muscles.get(parent)
+		if !ok {
+			return fmt.Errorf("parent muscle with code %s %w", parent, dao.ErrNotFound)
+		}
+		if parentMus.MuscleLevel.Depth() >= musReq.MuscleLevel.Depth() {
+			return fmt.Errorf("%s %s cannot be below %s %s: %w", musReq.MuscleLevel, musReq.MuscleCode,
+				parentMus.MuscleLevel, parent, dao.ErrInvalidHierarchy)
+		}
+	}
+	if !update {
+		return nil
+	}
+	for _, child := range s.muscles.rows {
+		if child.ParentCode != nil && *child.ParentCode == musReq.MuscleCode &&
+			child.MuscleLevel.Depth() <= musReq.MuscleLevel.Depth() {
+			return fmt.Errorf("muscle %s has children at or above level %s: %w", musReq.MuscleCode, musReq.MuscleLevel, dao.ErrInvalidHierarchy)
+		}
+	}
+	return nil
+}
+
+// GetMuscleTree returns the regions with the groups, muscles and heads below
+// them, each level sorted by name. Muscles without a parent that are not
+// regions are returned as roots as well.
+func (d *MuscleDAO) GetMuscleTree(ctx context.Context) ([]*model.MuscleNode, error) {
+	var muscles []model.Muscle
+	err := d.store.read(ctx, func() error {
+		muscles = d.store.muscles.list(nil)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(muscles, func(a, b model.Muscle) int {
+		return cmp.Or(strings.Compare(a.MuscleName, b.MuscleName), strings.Compare(a.MuscleCode, b.MuscleCode))
+	})
+
+	nodes := make(map[string]*model.MuscleNode, len(muscles))
+	for _, mus := range muscles {
+		nodes[mus.MuscleCode] = &model.MuscleNode{MuscleFields: mus.MuscleFields}
+	}
+	roots := []*model.MuscleNode{}
+	for _, mus := range muscles {
+		node := nodes[mus.MuscleCode]
+		if mus.ParentCode != nil {
+			if parent, ok := nodes[*mus.ParentCode]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+// FindExercisesByMuscle returns the exercises that work a muscle or any
+// muscle below it, optionally only in the given role. An exercise that works
+// several of these muscles is returned once, for its most important role
+// and then the muscle nearest to the one asked for.
+func (d *MuscleDAO) FindExercisesByMuscle(ctx context.Context, code string, role model.MuscleRole) ([]model.MuscleExercise, error) {
+	code = strings.ToUpper(code)
+	type found struct {
+		model.MuscleExercise
+		distance int
+	}
+	best := map[uuid.UUID]found{}
+	err := d.store.read(ctx, func() error {
+		if !d.store.muscles.has(code) {
+			return fmt.Errorf("muscle with code %s %w", code, dao.ErrNotFound)
+		}
+		for key, muscleRole := range d.store.exerciseMuscles.rows {
+			if role != "" && muscleRole != role {
+				continue
+			}
+			distance := slices.Index(d.store.ancestors(key.Code), code)
+			if distance < 0 {
+				continue
+			}
+			ex, ok := d.store.exercises.get(key.Exercise)
+			if !ok {
+				continue
+			}
+			candidate := found{
+				MuscleExercise: model.MuscleExercise{
+					ExerciseUuid: ex.ExerciseUuid,
+					ExerciseName: ex.ExerciseName,
+					CategoryCode: ex.CategoryCode,
+					MuscleCode:   key.Code,
+					MuscleRole:   muscleRole,
+				},
+				distance: distance,
+			}
+			if current, ok := best[ex.ExerciseUuid]; ok && cmp.Or(
+				cmp.Compare(roleOrder(current.MuscleRole), roleOrder(muscleRole)),
+				cmp.Compare(current.distance, distance),
+				strings.Compare(current.MuscleCode, key.Code)) <= 0 {
+				continue
+			}
+			best[ex.ExerciseUuid] = candidate
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make([]model.MuscleExercise, 0, len(best))
+	for _, f := range best {
+		exercises = append(exercises, f.MuscleExercise)
+	}
+	slices.SortFunc(exercises, func(a, b model.MuscleExercise) int {
+		return cmp.Or(strings.Compare(a.ExerciseName, b.ExerciseName), strings.Compare(a.ExerciseUuid.String(), b.ExerciseUuid.String()))
+	})
+	return exercises, nil
+}
+
+// ancestors returns the muscle with the given code followed by the muscles
+// above it, nearest first, like the muscle_ancestry view.
+func (s *Store) ancestors(code string) []string {
+	codes := []string{code}
+	for {
+		mus, ok := s.muscles.get(code)
+		if !ok || mus.ParentCode == nil || slices.Contains(codes, *mus.ParentCode) {
+			return codes
+		}
+		code = *mus.ParentCode
+		codes = append(codes, code)
+	}
+}
+
+// roleOrder orders muscle roles like the muscle_role enum, most important
+// first.
+func roleOrder(role model.MuscleRole) int {
+	switch role {
+	case model.MuscleRolePrimary:
+		return 0
+	case model.MuscleRoleSecondary:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMuscleDAO_CreateMuscle_Hierarchy(t *testing.T) {
+	store := newSeededStore(t)
+	musDao := NewMuscleDAO(store)
+	ctx := context.Background()
+
+	rectus := "rectus"
+	_, err := musDao.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "VASTUS", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &rectus}})
+	assert.ErrorIs(t, err, dao.ErrInvalidHierarchy)
+
+	missing := "ARMS"
+	_, err = musDao.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "BICEPS", ParentCode: &missing}})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+
+	_, err = musDao.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{MuscleCode: "quads"}})
+	assert.ErrorIs(t, err, dao.ErrConflict)
+
+	legs := "legs"
+	mus, err := musDao.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "hamstrings", MuscleName: "Hamstrings", ParentCode: &legs}})
+	assert.NoError(t, err)
+	assert.Equal(t, "HAMSTRINGS", mus.MuscleCode)
+	assert.Equal(t, model.MuscleLevelMuscle, mus.MuscleLevel)
+	assert.Equal(t, "LEGS", *mus.ParentCode)
+}
+
+func TestMuscleDAO_DeleteMuscle(t *testing.T) {
+	store := newSeededStore(t)
+	musDao := NewMuscleDAO(store)
+	ctx := context.Background()
+	createExercise(t, store, "Hip thrust", "GLUTES")
+
+	assert.Error(t, musDao.DeleteMuscle(ctx, "QUADS"), "muscles below it")
+	assert.Error(t, musDao.DeleteMuscle(ctx, "GLUTES"), "worked by an exercise")
+	assert.NoError(t, musDao.DeleteMuscle(ctx, "rectus"))
+	assert.NoError(t, musDao.DeleteMuscle(ctx, "QUADS"))
+	assert.ErrorIs(t, musDao.DeleteMuscle(ctx, "QUADS"), dao.ErrNotFound)
+}
+
+func TestMuscleDAO_GetMuscleTree(t *testing.T) {
+	musDao := NewMuscleDAO(newSeededStore(t))
+
+	tree, err := musDao.GetMuscleTree(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, tree, 1) {
+		assert.Equal(t, "LEGS", tree[0].MuscleCode)
+		if assert.Len(t, tree[0].Children, 2) {
+			assert.Equal(t, "GLUTES", tree[0].Children[0].MuscleCode)
+			assert.Equal(t, "QUADS", tree[0].Children[1].MuscleCode)
+			assert.Equal(t, "RECTUS", tree[0].Children[1].Children[0].MuscleCode)
+		}
+	}
+}
+
+func TestMuscleDAO_FindExercisesByMuscle(t *testing.T) {
+	store := newSeededStore(t)
+	musDao := NewMuscleDAO(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat", "QUADS", "RECTUS")
+	createExercise(t, store, "Hip thrust", "GLUTES")
+	extension := createExercise(t, store, "Leg extension")
+	assert.NoError(t, NewExerciseDao(store).AddMuscles(ctx, extension,
+		[]model.ExerciseMuscle{{MuscleCode: "RECTUS", MuscleRole: model.MuscleRoleSecondary}}))
+
+	exercises, err := musDao.FindExercisesByMuscle(ctx, "quads", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleExercise{
+		{ExerciseUuid: extension, ExerciseName: "Leg extension", CategoryCode: "STRENGTH", MuscleCode: "RECTUS", MuscleRole: model.MuscleRoleSecondary},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary},
+	}, exercises)
+
+	exercises, err = musDao.FindExercisesByMuscle(ctx, "LEGS", model.MuscleRolePrimary)
+	assert.NoError(t, err)
+	assert.Len(t, exercises, 2)
+
+	_, err = musDao.FindExercisesByMuscle(ctx, "ARMS", "")
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
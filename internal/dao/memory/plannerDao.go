@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// PlannerDao reads the exercises in a store the workout planner picks from.
+type PlannerDao struct {
+	store *Store
+}
+
+// Ensure PlannerDao implements dao.PlannerDaoInterface
+var _ dao.PlannerDaoInterface = (*PlannerDao)(nil)
+
+// NewPlannerDao creates a new instance of PlannerDao.
+func NewPlannerDao(store *Store) *PlannerDao {
+	return &PlannerDao{store: store}
+}
+
+// ListCandidates lists the exercises, optionally of a category, with their
+// apparatus and their muscles, each with its ancestors nearest first and the
+// movement pattern of the nearest of these that has one.
+func (d *PlannerDao) ListCandidates(ctx context.Context, category string) ([]model.CandidateExercise, error) {
+	category = strings.ToUpper(category)
+	candidates := []model.CandidateExercise{}
+	err := d.store.read(ctx, func() error {
+		for _, ex := range d.store.exercises.rows {
+			if category != "" && ex.CategoryCode != category {
+				continue
+			}
+			candidate := model.CandidateExercise{
+				ExerciseUuid: ex.ExerciseUuid,
+				ExerciseName: ex.ExerciseName,
+				CategoryCode: ex.CategoryCode,
+				Apparatus:    d.store.exerciseApparatusOf(ex.ExerciseUuid),
+			}
+			for _, mus := range d.store.exerciseMusclesOf(ex.ExerciseUuid) {
+				ancestors := d.store.ancestors(mus.MuscleCode)
+				candidate.Muscles = append(candidate.Muscles, model.CandidateMuscle{
+					MuscleCode: mus.MuscleCode,
+					MuscleRole: mus.MuscleRole,
+					Ancestors:  ancestors,
+					Pattern:    d.store.movementPattern(ancestors),
+				})
+			}
+			slices.SortFunc(candidate.Muscles, func(a, b model.CandidateMuscle) int {
+				return strings.Compare(a.MuscleCode, b.MuscleCode)
+			})
+			candidates = append(candidates, candidate)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(candidates, func(a, b model.CandidateExercise) int {
+		return cmp.Compare(a.ExerciseUuid.String(), b.ExerciseUuid.String())
+	})
+	return candidates, nil
+}
+
+// movementPattern returns the pattern of the first of the muscles that has
+// one, model.MovementOther when none does.
+func (s *Store) movementPattern(codes []string) model.MovementPattern {
+	for _, code := range codes {
+		if mus, ok := s.muscles.get(code); ok && mus.MovementPattern != nil {
+			return *mus.MovementPattern
+		}
+	}
+	return model.MovementOther
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// RecoveryDao reads how hard the muscles of users were trained from the
+// workouts in a store.
+type RecoveryDao struct {
+	store *Store
+}
+
+// Ensure RecoveryDao implements dao.RecoveryDaoInterface
+var _ dao.RecoveryDaoInterface = (*RecoveryDao)(nil)
+
+// NewRecoveryDao creates a new instance of RecoveryDao.
+func NewRecoveryDao(store *Store) *RecoveryDao {
+	return &RecoveryDao{store: store}
+}
+
+// ListMuscleLoads counts the sets of each session of a user since a time
+// per muscle and role, oldest session first.
+func (d *RecoveryDao) ListMuscleLoads(ctx context.Context, userUuid uuid.UUID, since time.Time) ([]model.MuscleLoad, error) {
+	var loads []model.MuscleLoad
+	err := d.store.read(ctx, func() error {
+		sessions := d.store.sessions.list(func(_ uuid.UUID, session model.WorkoutSession) bool {
+			return session.UserUuid == userUuid && !session.StartedAt.Before(since)
+		})
+		sortSessions(sessions)
+		for _, session := range sessions {
+			var sessionLoads []model.MuscleLoad
+			for _, set := range session.Sets {
+				for _, mus := range d.store.exerciseMusclesOf(set.ExerciseUuid) {
+					i := slices.IndexFunc(sessionLoads, func(l model.MuscleLoad) bool {
+						return l.MuscleCode == mus.MuscleCode && l.MuscleRole == mus.MuscleRole
+					})
+					if i < 0 {
+						sessionLoads = append(sessionLoads, model.MuscleLoad{
+							StartedAt:  session.StartedAt,
+							MuscleCode: mus.MuscleCode,
+							MuscleRole: mus.MuscleRole,
+						})
+						i = len(sessionLoads) - 1
+					}
+					sessionLoads[i].Sets++
+				}
+			}
+			slices.SortStableFunc(sessionLoads, func(a, b model.MuscleLoad) int {
+				return strings.Compare(a.MuscleCode, b.MuscleCode)
+			})
+			loads = append(loads, sessionLoads...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loads, nil
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// RelationDao provides access to the graph of variations, progressions,
+// regressions and alternatives between the exercises in a store.
+type RelationDao struct {
+	store *Store
+}
+
+// Ensure RelationDao implements dao.RelationDaoInterface
+var _ dao.RelationDaoInterface = (*RelationDao)(nil)
+
+// NewRelationDao creates a new instance of RelationDao.
+func NewRelationDao(store *Store) *RelationDao {
+	return &RelationDao{store: store}
+}
+
+func (d *RelationDao) CreateRelation(ctx context.Context, exUuid uuid.UUID, req *model.ExerciseRelationRequest) (*model.ExerciseRelation, error) {
+	relation := model.ExerciseRelation{
+		ExerciseUuid: exUuid,
+		Relation:     req.Relation,
+		RelatedUuid:  req.RelatedUuid,
+		CreatedBy:    req.CreatedBy,
+		CreatedAt:    now(),
+	}
+	err := d.store.write(ctx, func(tx *txn) error {
+		if exUuid == req.RelatedUuid {
+			return fmt.Errorf("exercise %s cannot be related to itself", exUuid)
+		}
+		key := relationKey{Exercise: exUuid, Related: req.RelatedUuid, Relation: req.Relation}
+		if d.store.relations.has(key) {
+			return fmt.Errorf("relation %s %s %s %w", exUuid, req.Relation, req.RelatedUuid, dao.ErrConflict)
+		}
+		if !d.store.exercises.has(exUuid) || !d.store.exercises.has(req.RelatedUuid) {
+			return fmt.Errorf("exercise %s or %s %w", exUuid, req.RelatedUuid, dao.ErrNotFound)
+		}
+		d.store.relations.put(tx, key, relation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// GetRelations returns the edges from and to an exercise with the names of
+// the exercises at both ends.
+func (d *RelationDao) GetRelations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseRelation, error) {
+	relations := []model.ExerciseRelation{}
+	err := d.store.read(ctx, func() error {
+		for key, relation := range d.store.relations.rows {
+			if key.Exercise != exUuid && key.Related != exUuid {
+				continue
+			}
+			ex, ok := d.store.exercises.get(key.Exercise)
+			related, relatedOk := d.store.exercises.get(key.Related)
+			if !ok || !relatedOk {
+				continue
+			}
+			relation.ExerciseName, relation.RelatedName = ex.ExerciseName, related.ExerciseName
+			relations = append(relations, relation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(relations, func(a, b model.ExerciseRelation) int {
+		return cmp.Or(cmp.Compare(relationOrder(a.Relation), relationOrder(b.Relation)),
+			strings.Compare(a.ExerciseName, b.ExerciseName), strings.Compare(a.RelatedName, b.RelatedName))
+	})
+	return relations, nil
+}
+
+func (d *RelationDao) DeleteRelation(ctx context.Context, exUuid, relatedUuid uuid.UUID, relation model.RelationType) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.relations.delete(tx, relationKey{Exercise: exUuid, Related: relatedUuid, Relation: relation}) {
+			return fmt.Errorf("relation %s %s %s %w", exUuid, relation, relatedUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+/*
+ * FindProgressions walks the progression graph from an exercise, following
+ * progression_of and regression_of edges in the given direction for up to
+ * maxSteps steps. Each exercise is reported once, at its shortest distance.
+ * With samePrimaryMuscles set only exercises that have all primary muscles
+ * of the starting exercise as primary muscles are returned, and the walk
+ * also crosses alternative_to edges at no step, so the progressions of an
+ * alternative are suggested but not the alternatives of the exercise.
+ *
+ * "a progression_of b" makes b easier than a, "a regression_of b" makes a
+ * easier than b, so walking towards easier exercises follows progression_of
+ * forwards and regression_of backwards.
+ */
+func (d *RelationDao) FindProgressions(ctx context.Context, exUuid uuid.UUID, direction model.Direction, maxSteps int, samePrimaryMuscles bool) ([]model.RelatedExercise, error) {
+	forward, backward := model.RelationProgressionOf, model.RelationRegressionOf
+	switch direction {
+	case model.DirectionEasier:
+	case model.DirectionHarder:
+		forward, backward = backward, forward
+	default:
+		return nil, fmt.Errorf("unknown direction %q", direction)
+	}
+
+	related := []model.RelatedExercise{}
+	err := d.store.read(ctx, func() error {
+		edges, alternatives := map[uuid.UUID][]uuid.UUID{}, map[uuid.UUID][]uuid.UUID{}
+		for key := range d.store.relations.rows {
+			switch {
+			case key.Relation == forward:
+				edges[key.Exercise] = append(edges[key.Exercise], key.Related)
+			case key.Relation == backward:
+				edges[key.Related] = append(edges[key.Related], key.Exercise)
+			case key.Relation == model.RelationAlternativeTo && samePrimaryMuscles:
+				alternatives[key.Exercise] = append(alternatives[key.Exercise], key.Related)
+				alternatives[key.Related] = append(alternatives[key.Related], key.Exercise)
+			}
+		}
+
+		steps := map[uuid.UUID]int{exUuid: 0}
+		frontier := []uuid.UUID{exUuid}
+		for step := 0; step <= maxSteps && len(frontier) > 0; step++ {
+			// alternatives are as many steps away as the exercise they
+			// are an alternative to
+			for i := 0; i < len(frontier); i++ {
+				for _, alternative := range alternatives[frontier[i]] {
+					if _, seen := steps[alternative]; !seen {
+						steps[alternative] = step
+						frontier = append(frontier, alternative)
+					}
+				}
+			}
+			var next []uuid.UUID
+			for _, source := range frontier {
+				for _, target := range edges[source] {
+					if _, seen := steps[target]; !seen && step < maxSteps {
+						steps[target] = step + 1
+						next = append(next, target)
+					}
+				}
+			}
+			frontier = next
+		}
+
+		primary := d.store.primaryMuscles(exUuid)
+		for target, step := range steps {
+			ex, ok := d.store.exercises.get(target)
+			if step == 0 || !ok {
+				continue
+			}
+			if samePrimaryMuscles && !isSubset(primary, d.store.primaryMuscles(target)) {
+				continue
+			}
+			related = append(related, model.RelatedExercise{
+				ExerciseUuid: target,
+				ExerciseName: ex.ExerciseName,
+				CategoryCode: ex.CategoryCode,
+				Steps:        step,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(related, func(a, b model.RelatedExercise) int {
+		return cmp.Or(cmp.Compare(a.Steps, b.Steps), strings.Compare(a.ExerciseName, b.ExerciseName))
+	})
+	return related, nil
+}
+
+// primaryMuscles returns the codes of the muscles an exercise works as
+// primary muscles.
+func (s *Store) primaryMuscles(exUuid uuid.UUID) map[string]bool {
+	codes := map[string]bool{}
+	for key, role := range s.exerciseMuscles.rows {
+		if key.Exercise == exUuid && role == model.MuscleRolePrimary {
+			codes[key.Code] = true
+		}
+	}
+	return codes
+}
+
+func isSubset(sub, set map[string]bool) bool {
+	for code := range sub {
+		if !set[code] {
+			return false
+		}
+	}
+	return true
+}
+
+// relationOrder orders relations like the exercise_relation enum.
+func relationOrder(relation model.RelationType) int {
+	return slices.Index([]model.RelationType{model.RelationVariationOf, model.RelationProgressionOf,
+		model.RelationRegressionOf, model.RelationAlternativeTo}, relation)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationDao_CreateRelation(t *testing.T) {
+	store := newSeededStore(t)
+	relDao := NewRelationDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat")
+	lunge := createExercise(t, store, "Lunge")
+	req := &model.ExerciseRelationRequest{RelatedUuid: lunge, Relation: model.RelationAlternativeTo}
+
+	_, err := relDao.CreateRelation(ctx, squat, req)
+	assert.NoError(t, err)
+	_, err = relDao.CreateRelation(ctx, squat, req)
+	assert.ErrorIs(t, err, dao.ErrConflict)
+	_, err = relDao.CreateRelation(ctx, uuid.New(), req)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	_, err = relDao.CreateRelation(ctx, lunge, req)
+	assert.Error(t, err, "an exercise cannot be related to itself")
+
+	relations, err := relDao.GetRelations(ctx, lunge)
+	assert.NoError(t, err)
+	if assert.Len(t, relations, 1) {
+		assert.Equal(t, "Squat", relations[0].ExerciseName)
+		assert.Equal(t, "Lunge", relations[0].RelatedName)
+	}
+	assert.NoError(t, relDao.DeleteRelation(ctx, squat, lunge, model.RelationAlternativeTo))
+	assert.ErrorIs(t, relDao.DeleteRelation(ctx, squat, lunge, model.RelationAlternativeTo), dao.ErrNotFound)
+}
+
+func TestRelationDao_FindProgressions(t *testing.T) {
+	store := newSeededStore(t)
+	relDao := NewRelationDao(store)
+	ctx := context.Background()
+	pistol := createExercise(t, store, "Pistol squat", "QUADS")
+	split := createExercise(t, store, "Split squat", "QUADS")
+	squat := createExercise(t, store, "Squat", "QUADS")
+	bridge := createExercise(t, store, "Glute bridge", "GLUTES")
+	shrimp := createExercise(t, store, "Shrimp squat", "QUADS")
+	hack := createExercise(t, store, "Hack squat", "QUADS")
+	press := createExercise(t, store, "Leg press", "QUADS")
+	// pistol is harder than split, which is harder than squat; squat is
+	// harder than bridge. shrimp can replace pistol and hack split, and hack
+	// is harder than press
+	for _, edge := range []struct {
+		from, to uuid.UUID
+		relation model.RelationType
+	}{
+		{pistol, split, model.RelationProgressionOf},
+		{squat, split, model.RelationRegressionOf},
+		{squat, bridge, model.RelationProgressionOf},
+		{shrimp, pistol, model.RelationAlternativeTo},
+		{split, hack, model.RelationAlternativeTo},
+		{hack, press, model.RelationProgressionOf},
+	} {
+		_, err := relDao.CreateRelation(ctx, edge.from, &model.ExerciseRelationRequest{RelatedUuid: edge.to, Relation: edge.relation})
+		assert.NoError(t, err)
+	}
+
+	easier, err := relDao.FindProgressions(ctx, pistol, model.DirectionEasier, 3, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RelatedExercise{
+		{ExerciseUuid: split, ExerciseName: "Split squat", CategoryCode: "STRENGTH", Steps: 1},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", Steps: 2},
+		{ExerciseUuid: bridge, ExerciseName: "Glute bridge", CategoryCode: "STRENGTH", Steps: 3},
+	}, easier)
+
+	easier, err = relDao.FindProgressions(ctx, pistol, model.DirectionEasier, 3, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RelatedExercise{
+		{ExerciseUuid: hack, ExerciseName: "Hack squat", CategoryCode: "STRENGTH", Steps: 1},
+		{ExerciseUuid: split, ExerciseName: "Split squat", CategoryCode: "STRENGTH", Steps: 1},
+		{ExerciseUuid: press, ExerciseName: "Leg press", CategoryCode: "STRENGTH", Steps: 2},
+		{ExerciseUuid: squat, ExerciseName: "Squat", CategoryCode: "STRENGTH", Steps: 2},
+	}, easier, "alternatives are followed but those of the pistol are not easier")
+
+	easier, err = relDao.FindProgressions(ctx, shrimp, model.DirectionEasier, 1, true)
+	assert.NoError(t, err)
+	assert.Len(t, easier, 2, "the progressions of an alternative")
+
+	harder, err := relDao.FindProgressions(ctx, squat, model.DirectionHarder, 1, false)
+	assert.NoError(t, err)
+	if assert.Len(t, harder, 1) {
+		assert.Equal(t, split, harder[0].ExerciseUuid)
+	}
+
+	_, err = relDao.FindProgressions(ctx, squat, "sideways", 1, false)
+	assert.Error(t, err)
+}
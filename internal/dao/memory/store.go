@@ -0,0 +1,148 @@
+/*
+ * Package memory implements the DAO interfaces of package dao on tables held
+ * in memory, so the service and its tests run without a database. A Store
+ * lives as long as the process.
+ *
+ * The DAOs mirror the Postgres DAOs: codes are upper-cased, lists come in
+ * the same order and missing or duplicate rows wrap dao.ErrNotFound and
+ * dao.ErrConflict with the same messages. Users are implicit, as the service
+ * cannot create them: they are not checked when referenced and exist once
+ * their preferences are saved.
+ */
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+/*
+ * Store holds the tables the DAOs read and write. Reads share the store,
+ * writes lock it, so each write and each unit of work is isolated from all
+ * others.
+ */
+type Store struct {
+	mu sync.RWMutex
+
+	users                 *table[uuid.UUID, model.UserPreferences]
+	exercises             *table[uuid.UUID, model.Exercise]
+	exerciseMuscles       *table[exerciseCodeKey, model.MuscleRole]
+	exerciseApparatus     *table[exerciseCodeKey, bool]
+	muscles               *table[string, model.Muscle]
+	categories            *table[string, model.Category]
+	apparatus             *table[string, model.Apparatus]
+	licenses              *table[string, model.License]
+	relations             *table[relationKey, model.ExerciseRelation]
+	aliases               *table[exerciseCodeKey, model.ExerciseAlias]
+	exerciseTranslations  *table[exerciseCodeKey, model.ExerciseTranslation]
+	referenceTranslations *table[referenceKey, model.ReferenceTranslation]
+	mappings              *table[mappingKey, model.ExerciseMapping]
+	sessions              *table[uuid.UUID, model.WorkoutSession]
+	cardio                *table[uuid.UUID, model.CardioActivity]
+	profiles              *table[uuid.UUID, model.EquipmentProfile]
+	measurements          *table[uuid.UUID, model.Measurement]
+	scheduled             *table[uuid.UUID, model.ScheduledWorkout]
+	feeds                 *table[uuid.UUID, model.CalendarFeed]
+	clients               *table[clientKey, time.Time]
+}
+
+// NewStore creates an empty store that is lost when the process ends.
+func NewStore() *Store {
+	s := &Store{}
+	s.users = newTable[uuid.UUID, model.UserPreferences]()
+	s.exercises = newTable[uuid.UUID, model.Exercise]()
+	s.exerciseMuscles = newTable[exerciseCodeKey, model.MuscleRole]()
+	s.exerciseApparatus = newTable[exerciseCodeKey, bool]()
+	s.muscles = newTable[string, model.Muscle]()
+	s.categories = newTable[string, model.Category]()
+	s.apparatus = newTable[string, model.Apparatus]()
+	s.licenses = newTable[string, model.License]()
+	s.relations = newTable[relationKey, model.ExerciseRelation]()
+	s.aliases = newTable[exerciseCodeKey, model.ExerciseAlias]()
+	s.exerciseTranslations = newTable[exerciseCodeKey, model.ExerciseTranslation]()
+	s.referenceTranslations = newTable[referenceKey, model.ReferenceTranslation]()
+	s.mappings = newTable[mappingKey, model.ExerciseMapping]()
+	s.sessions = newTable[uuid.UUID, model.WorkoutSession]()
+	s.cardio = newTable[uuid.UUID, model.CardioActivity]()
+	s.profiles = newTable[uuid.UUID, model.EquipmentProfile]()
+	s.measurements = newTable[uuid.UUID, model.Measurement]()
+	s.scheduled = newTable[uuid.UUID, model.ScheduledWorkout]()
+	s.feeds = newTable[uuid.UUID, model.CalendarFeed]()
+	s.clients = newTable[clientKey, time.Time]()
+	return s
+}
+
+// txn records how to undo the writes of a unit of work.
+type txn struct {
+	store *Store
+	undo  []func()
+}
+
+func (tx *txn) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+}
+
+type txnKey struct{}
+
+// joined returns the unit of work of the store that ctx is running in, if
+// any. The store is locked for it already.
+func (s *Store) joined(ctx context.Context) *txn {
+	if tx, ok := ctx.Value(txnKey{}).(*txn); ok && tx.store == s {
+		return tx
+	}
+	return nil
+}
+
+// read runs fn with the store locked for reading.
+func (s *Store) read(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.joined(ctx) != nil {
+		return fn()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn()
+}
+
+/*
+ * write runs fn in the unit of work of ctx, or else with the store locked
+ * in a unit of work of its own. The writes of fn are undone when it returns
+ * an error or panics.
+ */
+func (s *Store) write(ctx context.Context, fn func(tx *txn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tx := s.joined(ctx); tx != nil {
+		return fn(tx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &txn{store: s}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	return nil
+}
+
+// now is the time rows are created and updated at, in UTC like the
+// timestamps Postgres stores.
+func now() time.Time {
+	return time.Now().UTC()
+}
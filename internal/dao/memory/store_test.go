@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSeededStore returns a store with a category, a small muscle hierarchy
+// and two apparatus to create exercises against.
+func newSeededStore(t *testing.T) *Store {
+	store := NewStore()
+	ctx := context.Background()
+	_, err := NewCategoryDAO(store).CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "strength", CategoryName: "Strength"}})
+	assert.NoError(t, err)
+
+	muscles := NewMuscleDAO(store)
+	legs, quads := "LEGS", "QUADS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &legs},
+		{MuscleCode: "RECTUS", MuscleName: "Rectus femoris", MuscleLevel: model.MuscleLevelHead, ParentCode: &quads},
+		{MuscleCode: "GLUTES", MuscleName: "Glutes", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &legs},
+	} {
+		_, err := muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		assert.NoError(t, err)
+	}
+
+	apparatus := NewApparatusDAO(store)
+	for _, code := range []string{"BARBELL", "BENCH"} {
+		assert.NoError(t, apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+			ApparatusFields: model.ApparatusFields{ApparatusCode: code, ApparatusName: code}}))
+	}
+	return store
+}
+
+// createExercise creates a strength exercise working muscles as primary
+// muscles.
+func createExercise(t *testing.T, store *Store, name string, muscles ...string) uuid.UUID {
+	exDao := NewExerciseDao(store)
+	ex, err := exDao.Create(context.Background(), &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: name, CategoryCode: "STRENGTH"}})
+	assert.NoError(t, err)
+	for _, code := range muscles {
+		assert.NoError(t, exDao.AddMuscles(context.Background(), ex.ExerciseUuid,
+			[]model.ExerciseMuscle{{MuscleCode: code, MuscleRole: model.MuscleRolePrimary}}))
+	}
+	return ex.ExerciseUuid
+}
+
+func TestStore_CanceledContext(t *testing.T) {
+	store := newSeededStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewCategoryDAO(store).GetAllCategories(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = NewCategoryDAO(store).CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "CARDIO"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStore_WriteRollsBackOnPanic(t *testing.T) {
+	store := newSeededStore(t)
+
+	assert.Panics(t, func() {
+		_ = store.write(context.Background(), func(tx *txn) error {
+			store.categories.delete(tx, "STRENGTH")
+			panic("boom")
+		})
+	})
+	assert.True(t, store.categories.has("STRENGTH"))
+}
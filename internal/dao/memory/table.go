@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// table holds the rows of one kind keyed by their primary key.
+type table[K comparable, V any] struct {
+	rows map[K]V
+}
+
+func newTable[K comparable, V any]() *table[K, V] {
+	return &table[K, V]{rows: map[K]V{}}
+}
+
+func (t *table[K, V]) get(key K) (V, bool) {
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+func (t *table[K, V]) has(key K) bool {
+	_, ok := t.rows[key]
+	return ok
+}
+
+// put inserts or replaces the row with key within tx. Rows are replaced as
+// a whole and never changed in place, so tx can restore them.
+func (t *table[K, V]) put(tx *txn, key K, row V) {
+	t.record(tx, key)
+	t.rows[key] = row
+}
+
+// delete removes the row with key within tx and reports whether it existed.
+func (t *table[K, V]) delete(tx *txn, key K) bool {
+	if !t.has(key) {
+		return false
+	}
+	t.record(tx, key)
+	delete(t.rows, key)
+	return true
+}
+
+// deleteWhere removes the rows that match within tx and returns how many.
+func (t *table[K, V]) deleteWhere(tx *txn, match func(K, V) bool) int {
+	deleted := 0
+	for key, row := range t.rows {
+		if match(key, row) {
+			t.delete(tx, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// list returns the rows that match, all rows when match is nil, in no
+// particular order.
+func (t *table[K, V]) list(match func(K, V) bool) []V {
+	rows := []V{}
+	for key, row := range t.rows {
+		if match == nil || match(key, row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// record lets tx restore the row with key as it is now.
+func (t *table[K, V]) record(tx *txn, key K) {
+	old, existed := t.rows[key]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			t.rows[key] = old
+		} else {
+			delete(t.rows, key)
+		}
+	})
+}
+
+// exerciseCodeKey keys the rows of an exercise by a code, alias or locale.
+type exerciseCodeKey struct {
+	Exercise uuid.UUID
+	Code     string
+}
+
+type relationKey struct {
+	Exercise uuid.UUID
+	Related  uuid.UUID
+	Relation model.RelationType
+}
+
+type referenceKey struct {
+	Kind   model.ReferenceKind
+	Code   string
+	Locale string
+}
+
+type mappingKey struct {
+	Source       string
+	ExternalName string
+}
+
+type clientKey struct {
+	Coach  uuid.UUID
+	Client uuid.UUID
+}
+
+// inRange reports whether t is in [from, to).
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
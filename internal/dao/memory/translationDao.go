@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// TranslationDao provides access to the translations and aliases of the
+// exercises and reference types in a store.
+type TranslationDao struct {
+	store *Store
+}
+
+// Ensure TranslationDao implements dao.TranslationDaoInterface
+var _ dao.TranslationDaoInterface = (*TranslationDao)(nil)
+
+// NewTranslationDao creates a new instance of TranslationDao.
+func NewTranslationDao(store *Store) *TranslationDao {
+	return &TranslationDao{store: store}
+}
+
+// ListLocales returns every locale something has been translated into.
+func (d *TranslationDao) ListLocales(ctx context.Context) ([]string, error) {
+	locales := []string{}
+	err := d.store.read(ctx, func() error {
+		for key := range d.store.exerciseTranslations.rows {
+			locales = append(locales, key.Code)
+		}
+		for key := range d.store.referenceTranslations.rows {
+			locales = append(locales, key.Locale)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(locales)
+	return slices.Compact(locales), nil
+}
+
+func (d *TranslationDao) GetExerciseTranslations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseTranslation, error) {
+	var translations []model.ExerciseTranslation
+	err := d.store.read(ctx, func() error {
+		translations = d.store.exerciseTranslations.list(func(key exerciseCodeKey, _ model.ExerciseTranslation) bool {
+			return key.Exercise == exUuid
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(translations, func(a, b model.ExerciseTranslation) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+	return translations, nil
+}
+
+func (d *TranslationDao) SaveExerciseTranslation(ctx context.Context, exUuid uuid.UUID, tr *model.ExerciseTranslation) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.exercises.has(exUuid) {
+			return fmt.Errorf("exercise with uuid %s %w", exUuid, dao.ErrNotFound)
+		}
+		d.store.exerciseTranslations.put(tx, exerciseCodeKey{Exercise: exUuid, Code: tr.Locale}, *tr)
+		return nil
+	})
+}
+
+func (d *TranslationDao) DeleteExerciseTranslation(ctx context.Context, exUuid uuid.UUID, locale string) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.exerciseTranslations.delete(tx, exerciseCodeKey{Exercise: exUuid, Code: locale}) {
+			return fmt.Errorf("%s translation of exercise %s %w", locale, exUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+func (d *TranslationDao) GetAliases(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseAlias, error) {
+	var aliases []model.ExerciseAlias
+	err := d.store.read(ctx, func() error {
+		aliases = d.store.aliases.list(func(key exerciseCodeKey, _ model.ExerciseAlias) bool {
+			return key.Exercise == exUuid
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(aliases, func(a, b model.ExerciseAlias) int {
+		return strings.Compare(a.Alias, b.Alias)
+	})
+	return aliases, nil
+}
+
+func (d *TranslationDao) AddAlias(ctx context.Context, exUuid uuid.UUID, alias *model.ExerciseAlias) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.exercises.has(exUuid) {
+			return fmt.Errorf("exercise with uuid %s %w", exUuid, dao.ErrNotFound)
+		}
+		key := exerciseCodeKey{Exercise: exUuid, Code: alias.Alias}
+		if d.store.aliases.has(key) {
+			return fmt.Errorf("alias '%s' of exercise %s %w", alias.Alias, exUuid, dao.ErrConflict)
+		}
+		d.store.aliases.put(tx, key, *alias)
+		return nil
+	})
+}
+
+func (d *TranslationDao) DeleteAlias(ctx context.Context, exUuid uuid.UUID, alias string) error {
+	return d.store.write(ctx, func(tx *txn) error {
+		if !d.store.aliases.delete(tx, exerciseCodeKey{Exercise: exUuid, Code: alias}) {
+			return fmt.Errorf("alias '%s' of exercise %s %w", alias, exUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// GetReferenceTranslations returns the translations of one kind of
+// reference into locale, keyed by code.
+func (d *TranslationDao) GetReferenceTranslations(ctx context.Context, kind model.ReferenceKind, locale string) (map[string]model.ReferenceTranslation, error) {
+	translations := map[string]model.ReferenceTranslation{}
+	err := d.store.read(ctx, func() error {
+		for key, tr := range d.store.referenceTranslations.rows {
+			if key.Kind == kind && key.Locale == locale {
+				translations[key.Code] = tr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// SaveReferenceTranslation stores the translation of an existing muscle,
+// category or apparatus.
+func (d *TranslationDao) SaveReferenceTranslation(ctx context.Context, tr *model.ReferenceTranslation) error {
+	tr.Code = strings.ToUpper(tr.Code)
+	return d.store.write(ctx, func(tx *txn) error {
+		var exists bool
+		switch tr.Kind {
+		case model.ReferenceMuscle:
+			exists = d.store.muscles.has(tr.Code)
+		case model.ReferenceCategory:
+			exists = d.store.categories.has(tr.Code)
+		case model.ReferenceApparatus:
+			exists = d.store.apparatus.has(tr.Code)
+		default:
+			return fmt.Errorf("unknown reference kind %q", tr.Kind)
+		}
+		if !exists {
+			return fmt.Errorf("%s with code %s %w", tr.Kind, tr.Code, dao.ErrNotFound)
+		}
+		d.store.referenceTranslations.put(tx, referenceKey{Kind: tr.Kind, Code: tr.Code, Locale: tr.Locale}, *tr)
+		return nil
+	})
+}
+
+/*
+ * SearchExercises finds exercises whose name, alias or translated name
+ * contains query, ignoring case. Exact matches come first, then matches on
+ * the name before aliases and translations. Names are returned in locale
+ * when translated.
+ */
+func (d *TranslationDao) SearchExercises(ctx context.Context, query, locale string, limit int) ([]model.ExerciseSearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	type found struct {
+		model.ExerciseSearchResult
+		exact bool
+		rank  int
+	}
+	// better orders the matches of an exercise, and then the exercises.
+	better := func(a, b found) int {
+		return cmp.Or(-cmp.Compare(boolOrder(a.exact), boolOrder(b.exact)), cmp.Compare(a.rank, b.rank),
+			strings.Compare(a.ExerciseName, b.ExerciseName), strings.Compare(a.Match, b.Match))
+	}
+
+	best := map[uuid.UUID]found{}
+	err := d.store.read(ctx, func() error {
+		consider := func(exUuid uuid.UUID, matchedBy model.MatchedBy, match string, rank int) {
+			if !strings.Contains(strings.ToLower(match), query) {
+				return
+			}
+			ex, ok := d.store.exercises.get(exUuid)
+			if !ok {
+				return
+			}
+			name := ex.ExerciseName
+			if tr, ok := d.store.exerciseTranslations.get(exerciseCodeKey{Exercise: exUuid, Code: locale}); ok {
+				name = tr.ExerciseName
+			}
+			candidate := found{
+				ExerciseSearchResult: model.ExerciseSearchResult{
+					ExerciseUuid: exUuid,
+					ExerciseName: name,
+					CategoryCode: ex.CategoryCode,
+					MatchedBy:    matchedBy,
+					Match:        match,
+				},
+				exact: strings.ToLower(match) == query,
+				rank:  rank,
+			}
+			if current, ok := best[exUuid]; !ok || better(candidate, current) < 0 {
+				best[exUuid] = candidate
+			}
+		}
+
+		for _, ex := range d.store.exercises.rows {
+			consider(ex.ExerciseUuid, model.MatchedByName, ex.ExerciseName, 0)
+		}
+		for key, alias := range d.store.aliases.rows {
+			consider(key.Exercise, model.MatchedByAlias, alias.Alias, 1)
+		}
+		for key, tr := range d.store.exerciseTranslations.rows {
+			consider(key.Exercise, model.MatchedByTranslation, tr.ExerciseName, 2)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]found, 0, len(best))
+	for _, f := range best {
+		matches = append(matches, f)
+	}
+	slices.SortFunc(matches, better)
+	results := []model.ExerciseSearchResult{}
+	for _, f := range matches[:min(limit, len(matches))] {
+		results = append(results, f.ExerciseSearchResult)
+	}
+	return results, nil
+}
+
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslationDao_SearchExercises(t *testing.T) {
+	store := newSeededStore(t)
+	trDao := NewTranslationDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Back squat")
+	frontSquat := createExercise(t, store, "Front squat")
+	lunge := createExercise(t, store, "Lunge")
+	assert.NoError(t, trDao.AddAlias(ctx, lunge, &model.ExerciseAlias{Alias: "Squat"}))
+	assert.NoError(t, trDao.SaveExerciseTranslation(ctx, squat, &model.ExerciseTranslation{Locale: "de", ExerciseName: "Kniebeuge"}))
+
+	results, err := trDao.SearchExercises(ctx, " SQUAT ", "de", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseSearchResult{
+		{ExerciseUuid: lunge, ExerciseName: "Lunge", CategoryCode: "STRENGTH", MatchedBy: model.MatchedByAlias, Match: "Squat"},
+		{ExerciseUuid: frontSquat, ExerciseName: "Front squat", CategoryCode: "STRENGTH", MatchedBy: model.MatchedByName, Match: "Front squat"},
+		{ExerciseUuid: squat, ExerciseName: "Kniebeuge", CategoryCode: "STRENGTH", MatchedBy: model.MatchedByName, Match: "Back squat"},
+	}, results)
+
+	results, err = trDao.SearchExercises(ctx, "squat", "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = trDao.SearchExercises(ctx, "kniebeuge", "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, model.MatchedByTranslation, results[0].MatchedBy)
+		assert.Equal(t, "Back squat", results[0].ExerciseName)
+	}
+}
+
+func TestTranslationDao_Aliases(t *testing.T) {
+	store := newSeededStore(t)
+	trDao := NewTranslationDao(store)
+	ctx := context.Background()
+	exUuid := createExercise(t, store, "Squat")
+
+	assert.NoError(t, trDao.AddAlias(ctx, exUuid, &model.ExerciseAlias{Alias: "Squats"}))
+	assert.ErrorIs(t, trDao.AddAlias(ctx, exUuid, &model.ExerciseAlias{Alias: "Squats"}), dao.ErrConflict)
+	assert.ErrorIs(t, trDao.AddAlias(ctx, uuid.New(), &model.ExerciseAlias{Alias: "Squats"}), dao.ErrNotFound)
+	assert.NoError(t, trDao.DeleteAlias(ctx, exUuid, "Squats"))
+	assert.ErrorIs(t, trDao.DeleteAlias(ctx, exUuid, "Squats"), dao.ErrNotFound)
+}
+
+func TestTranslationDao_ReferenceTranslations(t *testing.T) {
+	trDao := NewTranslationDao(newSeededStore(t))
+	ctx := context.Background()
+
+	assert.NoError(t, trDao.SaveReferenceTranslation(ctx, &model.ReferenceTranslation{
+		Kind: model.ReferenceMuscle, Code: "quads", Locale: "de", Name: "Quadrizeps"}))
+	err := trDao.SaveReferenceTranslation(ctx, &model.ReferenceTranslation{
+		Kind: model.ReferenceApparatus, Code: "KETTLEBELL", Locale: "de", Name: "Kugelhantel"})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+
+	translations, err := trDao.GetReferenceTranslations(ctx, model.ReferenceMuscle, "de")
+	assert.NoError(t, err)
+	assert.Equal(t, "Quadrizeps", translations["QUADS"].Name)
+	locales, err := trDao.ListLocales(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de"}, locales)
+}
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/pwydra/shred/internal/dao"
+)
+
+// UnitOfWork runs several DAO calls on a store all or nothing.
+type UnitOfWork struct {
+	store *Store
+}
+
+// Ensure UnitOfWork implements dao.UnitOfWorkInterface
+var _ dao.UnitOfWorkInterface = (*UnitOfWork)(nil)
+
+// NewUnitOfWork creates a new instance of UnitOfWork.
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{store: store}
+}
+
+/*
+ * Do runs fn with the store locked. DAO methods called with the context
+ * given to fn take part in the unit of work, whose writes are undone when fn
+ * returns an error or panics. As nothing runs concurrently, fn is run once.
+ * Called within a unit of work, Do joins it.
+ */
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return u.store.write(ctx, func(tx *txn) error {
+		return fn(context.WithValue(ctx, txnKey{}, tx))
+	})
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	exUuid := createExercise(t, store, "Squat")
+
+	err := NewUnitOfWork(store).Do(context.Background(), func(ctx context.Context) error {
+		if err := exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "quads", MuscleRole: model.MuscleRolePrimary}}); err != nil {
+			return err
+		}
+		return exDao.AddApparatus(ctx, exUuid, []string{"barbell"})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}}, store.exerciseMusclesOf(exUuid))
+	assert.Equal(t, []string{"BARBELL"}, store.exerciseApparatusOf(exUuid))
+}
+
+func TestUnitOfWork_Rollback(t *testing.T) {
+	store := newSeededStore(t)
+	exDao := NewExerciseDao(store)
+	exUuid := createExercise(t, store, "Squat")
+
+	err := NewUnitOfWork(store).Do(context.Background(), func(ctx context.Context) error {
+		if _, err := exDao.Create(ctx, &model.ExerciseRequest{
+			ExerciseFields: model.ExerciseFields{ExerciseName: "Lunge", CategoryCode: "STRENGTH"}}); err != nil {
+			return err
+		}
+		if err := exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}}); err != nil {
+			return err
+		}
+		return exDao.AddApparatus(ctx, exUuid, []string{"KETTLEBELL"})
+	})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	assert.Empty(t, store.exerciseMusclesOf(exUuid))
+	names, err := NewCatalogDao(store).ListExerciseNames(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, names, 1)
+}
+
+func TestUnitOfWork_Nested(t *testing.T) {
+	store := newSeededStore(t)
+	uow := NewUnitOfWork(store)
+	catDao := NewCategoryDAO(store)
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		return uow.Do(ctx, func(ctx context.Context) error {
+			_, err := catDao.CreateCategory(ctx, &model.CategoryRequest{
+				CategoryFields: model.CategoryFields{CategoryCode: "CARDIO"}})
+			return err
+		})
+	})
+	assert.NoError(t, err)
+	_, err = catDao.GetCategoryByCode(context.Background(), "CARDIO")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// UserDao provides access to the settings of the users of a store.
+type UserDao struct {
+	store *Store
+}
+
+// Ensure UserDao implements dao.UserDaoInterface
+var _ dao.UserDaoInterface = (*UserDao)(nil)
+
+// NewUserDao creates a new instance of UserDao.
+func NewUserDao(store *Store) *UserDao {
+	return &UserDao{store: store}
+}
+
+// GetPreferences returns the preferences of a user. A user who never saved
+// any wraps dao.ErrNotFound.
+func (d *UserDao) GetPreferences(ctx context.Context, userUuid uuid.UUID) (*model.UserPreferences, error) {
+	var prefs model.UserPreferences
+	err := d.store.read(ctx, func() error {
+		var ok bool
+		if prefs, ok = d.store.users.get(userUuid); !ok {
+			return fmt.Errorf("user %s %w", userUuid, dao.ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences saves the preferences of a user, creating the user.
+func (d *UserDao) UpdatePreferences(ctx context.Context, userUuid uuid.UUID, prefs *model.UserPreferences) (*model.UserPreferences, error) {
+	updated := *prefs
+	if prefs.LoadIncrement != nil {
+		increment := *prefs.LoadIncrement
+		updated.LoadIncrement = &increment
+	}
+	err := d.store.write(ctx, func(tx *txn) error {
+		d.store.users.put(tx, userUuid, updated)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
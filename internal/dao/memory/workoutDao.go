@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// WorkoutDao provides access to the workout sessions logged in a store and
+// their sets.
+type WorkoutDao struct {
+	store *Store
+}
+
+// Ensure WorkoutDao implements dao.WorkoutDaoInterface
+var _ dao.WorkoutDaoInterface = (*WorkoutDao)(nil)
+
+// NewWorkoutDao creates a new instance of WorkoutDao.
+func NewWorkoutDao(store *Store) *WorkoutDao {
+	return &WorkoutDao{store: store}
+}
+
+// CreateSessions stores the sessions with all their sets at once, or in the
+// unit of work it is called in. Sets without a set number are numbered in
+// the order given.
+func (d *WorkoutDao) CreateSessions(ctx context.Context, userUuid uuid.UUID, sessions []model.WorkoutSessionRequest) ([]model.WorkoutSession, error) {
+	created := make([]model.WorkoutSession, 0, len(sessions))
+	err := d.store.write(ctx, func(tx *txn) error {
+		for _, req := range sessions {
+			session, err := d.store.insertSession(tx, userUuid, req)
+			if err != nil {
+				return err
+			}
+			created = append(created, *session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range created {
+		countLogged(&created[i])
+	}
+	return created, nil
+}
+
+// countLogged counts a stored session and its sets in the domain metrics.
+func countLogged(session *model.WorkoutSession) {
+	metrics.WorkoutsLogged.WithLabelValues(session.Source).Inc()
+	metrics.SetsLogged.Add(float64(len(session.Sets)))
+}
+
+// insertSession stores a session and its sets within tx and returns it with
+// the sets as given.
+func (s *Store) insertSession(tx *txn, userUuid uuid.UUID, req model.WorkoutSessionRequest) (*model.WorkoutSession, error) {
+	session := &model.WorkoutSession{
+		SessionUuid:          uuid.New(),
+		UserUuid:             userUuid,
+		WorkoutSessionFields: req.WorkoutSessionFields,
+		Sets:                 make([]model.WorkoutSet, len(req.Sets)),
+		CreatedAt:            now(),
+	}
+	if session.Source == "" {
+		session.Source = model.SourceShred
+	}
+	session.StartedAt = session.StartedAt.UTC()
+	session.UpdatedAt = session.CreatedAt
+
+	stored := *session
+	stored.Sets = make([]model.WorkoutSet, len(req.Sets))
+	numbers := map[int]bool{}
+	for i, set := range req.Sets {
+		if set.SetNumber == 0 {
+			set.SetNumber = i + 1
+		}
+		if numbers[set.SetNumber] {
+			return nil, fmt.Errorf("set %d of session %s %w", set.SetNumber, session.SessionUuid, dao.ErrConflict)
+		}
+		numbers[set.SetNumber] = true
+		if !s.exercises.has(set.ExerciseUuid) {
+			return nil, fmt.Errorf("exercise with uuid %s %w", set.ExerciseUuid, dao.ErrNotFound)
+		}
+		session.Sets[i] = set
+		// Only the columns of workout_set are stored.
+		set.Weight, set.WeightUnit, set.Distance, set.DistanceUnit = nil, "", nil, ""
+		stored.Sets[i] = set
+	}
+	slices.SortFunc(stored.Sets, func(a, b model.WorkoutSet) int {
+		return cmp.Compare(a.SetNumber, b.SetNumber)
+	})
+	s.sessions.put(tx, stored.SessionUuid, stored)
+	return session, nil
+}
+
+// ListSessions returns the sessions of a user started in [from, to) with
+// their sets, oldest first.
+func (d *WorkoutDao) ListSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.WorkoutSession, error) {
+	var sessions []model.WorkoutSession
+	err := d.store.read(ctx, func() error {
+		sessions = d.store.userSessions(userUuid, from, to)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Sets = slices.Clone(sessions[i].Sets)
+		if sessions[i].Sets == nil {
+			sessions[i].Sets = []model.WorkoutSet{}
+		}
+	}
+	return sessions, nil
+}
+
+// userSessions returns the sessions of a user started in [from, to), oldest
+// first. Their sets are shared with the store.
+func (s *Store) userSessions(userUuid uuid.UUID, from, to time.Time) []model.WorkoutSession {
+	sessions := s.sessions.list(func(_ uuid.UUID, session model.WorkoutSession) bool {
+		return session.UserUuid == userUuid && inRange(session.StartedAt, from, to)
+	})
+	sortSessions(sessions)
+	return sessions
+}
+
+// sortSessions orders sessions by the time they started.
+func sortSessions(sessions []model.WorkoutSession) {
+	slices.SortFunc(sessions, func(a, b model.WorkoutSession) int {
+		return cmp.Or(a.StartedAt.Compare(b.StartedAt), cmp.Compare(a.SessionUuid.String(), b.SessionUuid.String()))
+	})
+}
+
+// ListSessionStarts returns the start times of the sessions a user imported
+// from source, used to skip sessions that were imported before.
+func (d *WorkoutDao) ListSessionStarts(ctx context.Context, userUuid uuid.UUID, source string) ([]time.Time, error) {
+	var starts []time.Time
+	err := d.store.read(ctx, func() error {
+		for _, session := range d.store.sessions.rows {
+			if session.UserUuid == userUuid && session.Source == source {
+				starts = append(starts, session.StartedAt)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return starts, nil
+}
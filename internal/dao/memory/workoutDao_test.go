@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkoutDao_CreateSessions(t *testing.T) {
+	store := newSeededStore(t)
+	woDao := NewWorkoutDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat")
+	userUuid := uuid.New()
+	reps := 5
+	started := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	created, err := woDao.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{
+		{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: started.Add(24 * time.Hour)},
+			Sets: []model.WorkoutSet{{SetNumber: 2, ExerciseUuid: squat, Reps: &reps}, {SetNumber: 1, ExerciseUuid: squat}}},
+		{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: started, Source: "strong"},
+			Sets: []model.WorkoutSet{{ExerciseUuid: squat}}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+	assert.Equal(t, model.SourceShred, created[0].Source)
+
+	sessions, err := woDao.ListSessions(ctx, userUuid, started, started.Add(48*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, created[1].SessionUuid, sessions[0].SessionUuid)
+		assert.Equal(t, 1, sessions[0].Sets[0].SetNumber)
+		assert.Equal(t, []int{1, 2}, []int{sessions[1].Sets[0].SetNumber, sessions[1].Sets[1].SetNumber})
+	}
+
+	starts, err := woDao.ListSessionStarts(ctx, userUuid, "strong")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{started}, starts)
+}
+
+func TestWorkoutDao_CreateSessions_Invalid(t *testing.T) {
+	store := newSeededStore(t)
+	woDao := NewWorkoutDao(store)
+	ctx := context.Background()
+	squat := createExercise(t, store, "Squat")
+	userUuid := uuid.New()
+
+	_, err := woDao.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{
+		{Sets: []model.WorkoutSet{{ExerciseUuid: squat}}},
+		{Sets: []model.WorkoutSet{{SetNumber: 1, ExerciseUuid: squat}, {SetNumber: 1, ExerciseUuid: squat}}},
+	})
+	assert.ErrorIs(t, err, dao.ErrConflict)
+	_, err = woDao.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{
+		{Sets: []model.WorkoutSet{{ExerciseUuid: uuid.New()}}},
+	})
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+
+	assert.Empty(t, store.sessions.rows)
+}
@@ -0,0 +1,35 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/metrics"
+)
+
+/*
+ * observe records a call of a DAO method in the query metrics and logs its
+ * failure with the logger of ctx, which ties it to the request being
+ * served. DAO methods name their error result and defer it with the start
+ * of the call:
+ *
+ *	defer observe(ctx, "UserDao", "GetPreferences", time.Now(), &err)
+ *
+ * Missing rows and rejected writes are answers rather than failures of the
+ * database, so they are neither counted as errors nor logged.
+ */
+func observe(ctx context.Context, dao, method string, start time.Time, err *error) {
+	failed := *err != nil && !rejected(*err)
+	metrics.ObserveQuery(dao, method, time.Since(start), failed)
+	if failed {
+		logging.FromContext(ctx).ErrorContext(ctx, "query failed", "dao", dao, "method", method, "error", *err)
+	}
+}
+
+func rejected(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) ||
+		errors.Is(err, ErrInvalidHierarchy)
+}
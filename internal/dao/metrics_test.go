@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// queryMetrics returns how often method of dao was called and how often it
+// failed.
+func queryMetrics(t *testing.T, dao, method string) (calls, failures float64) {
+	families, err := metrics.NewRegistry(nil).Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["dao"] != dao || labels["method"] != method {
+				continue
+			}
+			switch family.GetName() {
+			case "shred_dao_query_duration_seconds":
+				calls = float64(m.GetHistogram().GetSampleCount())
+			case "shred_dao_query_errors_total":
+				failures = m.GetCounter().GetValue()
+			}
+		}
+	}
+	return calls, failures
+}
+
+func TestObserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+	calls, failures := queryMetrics(t, "UserDao", "GetPreferences")
+
+	mock.ExpectQuery("SELECT unit_system").WillReturnError(sql.ErrNoRows)
+	_, err = dao.GetPreferences(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectQuery("SELECT unit_system").WillReturnError(sql.ErrConnDone)
+	_, err = dao.GetPreferences(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	newCalls, newFailures := queryMetrics(t, "UserDao", "GetPreferences")
+	assert.Equal(t, calls+2, newCalls)
+	assert.Equal(t, failures+1, newFailures, "a missing user is not a failure")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestObserve_Logs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var logs bytes.Buffer
+	ctx := logging.NewContext(logging.WithRequestID(context.Background(), "req-1"),
+		logging.New(&logs, logging.Config{Format: logging.FormatJSON}))
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT unit_system").WillReturnError(sql.ErrNoRows)
+	_, err = dao.GetPreferences(ctx, uuid.New())
+	assert.Error(t, err)
+	assert.Empty(t, logs.String(), "a missing user is not a failure")
+
+	mock.ExpectQuery("SELECT unit_system").WillReturnError(sql.ErrConnDone)
+	_, err = dao.GetPreferences(ctx, uuid.New())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Contains(t, logs.String(), `"msg":"query failed","dao":"UserDao","method":"GetPreferences"`)
+	assert.Contains(t, logs.String(), `"request_id":"req-1"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pwydra/shred/internal/model"
@@ -17,11 +18,13 @@ type MuscleDAO struct {
 }
 
 type MuscleDaoInterface interface {
-	CreateMuscle(musReq *model.MuscleRequest) (model.Muscle, error)
-	GetMuscleByCode(code string) (*model.Muscle, error)
+	CreateMuscle(ctx context.Context, musReq *model.MuscleRequest) (model.Muscle, error)
+	GetMuscleByCode(ctx context.Context, code string) (*model.Muscle, error)
 	GetAllMuscles(ctx context.Context) ([]model.Muscle, error)
-	UpdateMuscle(musReq *model.MuscleRequest) error
-	DeleteMuscle(code string) error
+	GetMuscleTree(ctx context.Context) ([]*model.MuscleNode, error)
+	FindExercisesByMuscle(ctx context.Context, code string, role model.MuscleRole) ([]model.MuscleExercise, error)
+	UpdateMuscle(ctx context.Context, musReq *model.MuscleRequest) error
+	DeleteMuscle(ctx context.Context, code string) error
 }
 
 // Ensure MuscleDAO implements MuscleDaoInterface
@@ -34,11 +37,11 @@ func NewMuscleDAO(db *sqlx.DB) *MuscleDAO {
 
 const createMusDML string = `
 	INSERT INTO muscle_type (
-		muscle_code, muscle_name, muscle_description, muscle_group, created_by
+		muscle_code, muscle_name, muscle_description, muscle_group, muscle_level, parent_code,
+		movement_pattern, created_by
 	) VALUES (
-		$1, $2, $3, $4, $5
-	) RETURNING created_at, updated_at
-	)`
+		$1, $2, $3, $4, $5, $6, $7, $8
+	) RETURNING created_at, updated_at`
 
 // GetMuscleByCode retrieves a muscle by its Code.
 const getMusByCodeDQL string = `
@@ -46,11 +49,12 @@ const getMusByCodeDQL string = `
 	FROM muscle_type
 	WHERE muscle_code = $1`
 
-func (dao *MuscleDAO) GetMuscleByCode(musCode string) (*model.Muscle, error) {
+func (dao *MuscleDAO) GetMuscleByCode(ctx context.Context, musCode string) (_ *model.Muscle, err error) {
+	defer observe(ctx, "MuscleDAO", "GetMuscleByCode", time.Now(), &err)
 	var muscle model.Muscle
-	if err := dao.db.QueryRowx(getMusByCodeDQL, strings.ToUpper(musCode)).StructScan(&muscle); err != nil {
+	if err := getContext(ctx, dao.db, "getMusByCodeDQL", &muscle, getMusByCodeDQL, strings.ToUpper(musCode)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("muscle with code %s not found", strings.ToUpper(musCode))
+			return nil, fmt.Errorf("muscle with code %s %w", strings.ToUpper(musCode), ErrNotFound)
 		}
 		return nil, err
 	}
@@ -63,9 +67,10 @@ const getAllMusDQL string = `
 	SELECT *
 	FROM muscle_type`
 
-func (dao *MuscleDAO) GetAllMuscles(ctx context.Context) ([]model.Muscle, error) {
+func (dao *MuscleDAO) GetAllMuscles(ctx context.Context) (_ []model.Muscle, err error) {
+	defer observe(ctx, "MuscleDAO", "GetAllMuscles", time.Now(), &err)
 	var muscles []model.Muscle
-	if err := dao.db.SelectContext(ctx, &muscles, getAllMusDQL); err != nil {
+	if err := selectContext(ctx, dao.db, "getAllMusDQL", &muscles, getAllMusDQL); err != nil {
 		return nil, err
 	}
 
@@ -75,15 +80,20 @@ func (dao *MuscleDAO) GetAllMuscles(ctx context.Context) ([]model.Muscle, error)
 // CreateMuscle inserts a new muscle into the database.
 // Returns an error if the insertion fails.
 // Returns created object.
-func (dao *MuscleDAO) CreateMuscle(musReq *model.MuscleRequest) (model.Muscle, error) {
+func (dao *MuscleDAO) CreateMuscle(ctx context.Context, musReq *model.MuscleRequest) (_ model.Muscle, err error) {
+	defer observe(ctx, "MuscleDAO", "CreateMuscle", time.Now(), &err)
 	musReq.MuscleCode = strings.ToUpper(musReq.MuscleCode)
+	if err := dao.checkHierarchy(ctx, musReq, false); err != nil {
+		return model.Muscle{}, err
+	}
 	mus := model.Muscle{
 		MuscleFields: musReq.MuscleFields,
 		AuditRecord:  model.AuditRecord{CreatedBy: musReq.CreatedBy},
 	}
-	err := dao.db.QueryRowx(createMusDML,
-		musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc,
-		musReq.MuscleGroup, musReq.CreatedBy).Scan(&mus.CreatedAt, &mus.UpdatedAt)
+	err = scanContext(ctx, dao.db, "createMusDML", createMusDML, []any{
+		musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.MuscleLevel,
+		musReq.ParentCode, musReq.MovementPattern, musReq.CreatedBy,
+	}, &mus.CreatedAt, &mus.UpdatedAt)
 	if err != nil {
 		return mus, err
 	}
@@ -97,12 +107,21 @@ const updateMusDML string = `
 	SET
 		muscle_name = $1,
 		muscle_description = $2,
-		muscle_group = $3
-	WHERE muscle_code = $4`
+		muscle_group = $3,
+		muscle_level = $4,
+		parent_code = $5,
+		movement_pattern = $6
+	WHERE muscle_code = $7`
 
-func (dao *MuscleDAO) UpdateMuscle(musReq *model.MuscleRequest) error {
-	result, err := dao.db.Exec(updateMusDML,
-		musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, strings.ToUpper(musReq.MuscleCode))
+func (dao *MuscleDAO) UpdateMuscle(ctx context.Context, musReq *model.MuscleRequest) (err error) {
+	defer observe(ctx, "MuscleDAO", "UpdateMuscle", time.Now(), &err)
+	musReq.MuscleCode = strings.ToUpper(musReq.MuscleCode)
+	if err := dao.checkHierarchy(ctx, musReq, true); err != nil {
+		return err
+	}
+	result, err := execContext(ctx, dao.db, "updateMusDML", updateMusDML,
+		musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup,
+		musReq.MuscleLevel, musReq.ParentCode, musReq.MovementPattern, musReq.MuscleCode)
 	if err != nil {
 		return err
 	}
@@ -113,7 +132,7 @@ func (dao *MuscleDAO) UpdateMuscle(musReq *model.MuscleRequest) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("muscle with Code %s not found", musReq.MuscleCode)
+		return fmt.Errorf("muscle with code %s %w", musReq.MuscleCode, ErrNotFound)
 	}
 
 	return nil
@@ -124,8 +143,9 @@ const deleteMusDML string = `
 	DELETE FROM muscle_type
 	WHERE muscle_code = $1`
 
-func (dao *MuscleDAO) DeleteMuscle(code string) error {
-	result, err := dao.db.Exec(deleteMusDML, strings.ToUpper(code))
+func (dao *MuscleDAO) DeleteMuscle(ctx context.Context, code string) (err error) {
+	defer observe(ctx, "MuscleDAO", "DeleteMuscle", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteMusDML", deleteMusDML, strings.ToUpper(code))
 	if err != nil {
 		return err
 	}
@@ -136,8 +156,127 @@ func (dao *MuscleDAO) DeleteMuscle(code string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("muscle with code %s not found", strings.ToUpper(code))
+		return fmt.Errorf("muscle with code %s %w", strings.ToUpper(code), ErrNotFound)
+	}
+
+	return nil
+}
+
+// checkHierarchy defaults the level to muscle and checks that the parent, if
+// any, is higher up the hierarchy. When a muscle is updated its children must
+// stay below it as well.
+const getMusHierarchyDQL string = `
+	SELECT (SELECT muscle_level FROM muscle_type WHERE muscle_code = $1) AS parent_level,
+	       EXISTS (SELECT 1 FROM muscle_type WHERE parent_code = $2 AND muscle_level <= $3) AS child_above`
+
+func (dao *MuscleDAO) checkHierarchy(ctx context.Context, musReq *model.MuscleRequest, update bool) error {
+	if musReq.MuscleLevel == "" {
+		musReq.MuscleLevel = model.MuscleLevelMuscle
+	}
+	if !musReq.MuscleLevel.Valid() {
+		return fmt.Errorf("unknown muscle level %q: %w", musReq.MuscleLevel, ErrInvalidHierarchy)
+	}
+	if musReq.ParentCode != nil {
+		parent := strings.ToUpper(*musReq.ParentCode)
+		musReq.ParentCode = &parent
+		if musReq.MuscleLevel == model.MuscleLevelRegion {
+			return fmt.Errorf("region %s cannot have a parent: %w", musReq.MuscleCode, ErrInvalidHierarchy)
+		}
+	}
+	if musReq.ParentCode == nil && !update {
+		return nil
 	}
 
+	var parentLevel sql.NullString
+	var childAbove bool
+	if err := scanContext(ctx, dao.db, "getMusHierarchyDQL", getMusHierarchyDQL, []any{
+		musReq.ParentCode, musReq.MuscleCode, musReq.MuscleLevel,
+	}, &parentLevel, &childAbove); err != nil {
+		return err
+	}
+	if musReq.ParentCode != nil {
+		if !parentLevel.Valid {
+			return fmt.Errorf("parent muscle with code %s %w", *musReq.ParentCode, ErrNotFound)
+		}
+		if model.MuscleLevel(parentLevel.String).Depth() >= musReq.MuscleLevel.Depth() {
+			return fmt.Errorf("%s %s cannot be below %s %s: %w", musReq.MuscleLevel, musReq.MuscleCode,
+				parentLevel.String, *musReq.ParentCode, ErrInvalidHierarchy)
+		}
+	}
+	if childAbove {
+		return fmt.Errorf("muscle %s has children at or above level %s: %w", musReq.MuscleCode, musReq.MuscleLevel, ErrInvalidHierarchy)
+	}
 	return nil
 }
+
+// GetMuscleTree returns the regions with the groups, muscles and heads below
+// them, each level sorted by name. Muscles without a parent that are not
+// regions are returned as roots as well.
+const getMusTreeDQL string = `
+	SELECT muscle_code, muscle_name, COALESCE(muscle_description, '') AS muscle_description,
+	       muscle_group, muscle_level, parent_code, movement_pattern
+	FROM   muscle_type
+	ORDER BY muscle_name`
+
+func (dao *MuscleDAO) GetMuscleTree(ctx context.Context) (_ []*model.MuscleNode, err error) {
+	defer observe(ctx, "MuscleDAO", "GetMuscleTree", time.Now(), &err)
+	var muscles []model.MuscleFields
+	if err := selectContext(ctx, dao.db, "getMusTreeDQL", &muscles, getMusTreeDQL); err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*model.MuscleNode, len(muscles))
+	for _, mus := range muscles {
+		nodes[mus.MuscleCode] = &model.MuscleNode{MuscleFields: mus}
+	}
+	roots := []*model.MuscleNode{}
+	for _, mus := range muscles {
+		node := nodes[mus.MuscleCode]
+		if mus.ParentCode != nil {
+			if parent, ok := nodes[*mus.ParentCode]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+// FindExercisesByMuscle returns the exercises that work a muscle or any
+// muscle below it, optionally only in the given role. An exercise that works
+// several of these muscles is returned once, for its most important role.
+const musExistsDQL string = `
+	SELECT EXISTS (SELECT 1 FROM muscle_type WHERE muscle_code = $1)`
+
+const findExByMuscleDQL string = `
+	SELECT exercise_uuid, exercise_name, category_code, muscle_code, muscle_role
+	FROM (
+		SELECT DISTINCT ON (e.exercise_uuid)
+		       e.exercise_uuid, e.exercise_name, e.category_code, em.muscle_code, em.muscle_role
+		FROM   muscle_ancestry a
+		JOIN   exercise_muscle em ON em.muscle_code = a.muscle_code
+		JOIN   exercise e ON e.exercise_uuid = em.exercise_uuid
+		WHERE  a.ancestor_code = $1
+		AND    ($2 = '' OR em.muscle_role::text = $2)
+		ORDER BY e.exercise_uuid, em.muscle_role, a.distance
+	) found
+	ORDER BY exercise_name`
+
+func (dao *MuscleDAO) FindExercisesByMuscle(ctx context.Context, code string, role model.MuscleRole) (_ []model.MuscleExercise, err error) {
+	defer observe(ctx, "MuscleDAO", "FindExercisesByMuscle", time.Now(), &err)
+	code = strings.ToUpper(code)
+	var exists bool
+	if err := scanContext(ctx, dao.db, "musExistsDQL", musExistsDQL, []any{code}, &exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("muscle with code %s %w", code, ErrNotFound)
+	}
+
+	exercises := []model.MuscleExercise{}
+	if err := selectContext(ctx, dao.db, "findExByMuscleDQL", &exercises, findExByMuscleDQL, code, string(role)); err != nil {
+		return nil, err
+	}
+	return exercises, nil
+}
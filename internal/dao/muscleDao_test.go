@@ -27,7 +27,7 @@ func TestGetMuscleByCode(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"muscle_code", "muscle_name", "muscle_description", "muscle_group", "created_by", "created_at", "updated_at"}).
 			AddRow(musCode, "Latissimus", "Muscle of the back", "Back", uuid.New(), time.Now(), time.Now()))
 
-	muscle, err := dao.GetMuscleByCode(musCode)
+	muscle, err := dao.GetMuscleByCode(context.Background(), musCode)
 	assert.NoError(t, err)
 	assert.NotNil(t, muscle)
 	assert.Equal(t, "Latissimus", muscle.MuscleName)
@@ -45,9 +45,10 @@ func TestGetMuscleByCode_NotFound(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnError(sql.ErrNoRows)
 
-	muscle, err := dao.GetMuscleByCode(catCode)
+	muscle, err := dao.GetMuscleByCode(context.Background(), catCode)
 	assert.Error(t, err)
 	assert.Nil(t, muscle)
+	assert.ErrorIs(t, err, ErrNotFound)
 	assert.Equal(t, "muscle with code INVALID not found", err.Error())
 }
 
@@ -81,19 +82,19 @@ func TestCreateMuscle(t *testing.T) {
 
 	musReq := &model.MuscleRequest{
 		MuscleFields: model.MuscleFields{
-			MuscleCode: "LAT",
-			MuscleName: "Latissimus",
-			MuscleDesc: "Muscle of the back",
+			MuscleCode:  "LAT",
+			MuscleName:  "Latissimus",
+			MuscleDesc:  "Muscle of the back",
 			MuscleGroup: "Back",
 		},
 		CreatedBy: uuid.New(),
 	}
 
-	mock.ExpectQuery("INSERT INTO muscle_type \\( muscle_code, muscle_name, muscle_description, muscle_group, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5 \\).*").
-		WithArgs(musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.CreatedBy).
+	mock.ExpectQuery("INSERT INTO muscle_type \\( muscle_code, muscle_name, muscle_description, muscle_group, muscle_level, parent_code, movement_pattern, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8 \\).*").
+		WithArgs(musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, model.MuscleLevelMuscle, nil, nil, musReq.CreatedBy).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(timeNow, timeNow))
 
-	mus, err := dao.CreateMuscle(musReq)
+	mus, err := dao.CreateMuscle(context.Background(), musReq)
 	assert.NoError(t, err)
 	assert.Equal(t, mus.MuscleCode, musReq.MuscleCode)
 	assert.Equal(t, mus.MuscleName, musReq.MuscleName)
@@ -117,11 +118,11 @@ func TestCreateMuscle_Error(t *testing.T) {
 		},
 	}
 
-	mock.ExpectQuery("INSERT INTO muscle_type \\( muscle_code, muscle_name, muscle_description, muscle_group, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5 \\).*").
-		WithArgs(musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.CreatedBy).
+	mock.ExpectQuery("INSERT INTO muscle_type \\( muscle_code, muscle_name, muscle_description, muscle_group, muscle_level, parent_code, movement_pattern, created_by \\) VALUES \\( \\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8 \\).*").
+		WithArgs(musReq.MuscleCode, musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, model.MuscleLevelMuscle, nil, nil, musReq.CreatedBy).
 		WillReturnError(errors.New("insertion error"))
 
-	_, err = dao.CreateMuscle(musReq)
+	_, err = dao.CreateMuscle(context.Background(), musReq)
 	assert.Error(t, err)
 	assert.Equal(t, "insertion error", err.Error())
 }
@@ -142,11 +143,12 @@ func TestUpdateMuscle(t *testing.T) {
 		},
 	}
 
-	mock.ExpectExec("UPDATE muscle_type SET muscle_name = \\$1, muscle_description = \\$2, muscle_group = \\$3 WHERE muscle_code = \\$4").
-		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.MuscleCode).
+	expectMuscleHierarchy(mock, musReq.MuscleCode, model.MuscleLevelMuscle, nil, false)
+	mock.ExpectExec("UPDATE muscle_type SET muscle_name = \\$1, muscle_description = \\$2, muscle_group = \\$3, muscle_level = \\$4, parent_code = \\$5, movement_pattern = \\$6 WHERE muscle_code = \\$7").
+		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, model.MuscleLevelMuscle, nil, nil, musReq.MuscleCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.UpdateMuscle(musReq)
+	err = dao.UpdateMuscle(context.Background(), musReq)
 	assert.NoError(t, err)
 }
 
@@ -166,11 +168,12 @@ func TestUpdateMuscle_Error(t *testing.T) {
 		},
 	}
 
+	expectMuscleHierarchy(mock, musReq.MuscleCode, model.MuscleLevelMuscle, nil, false)
 	mock.ExpectExec("UPDATE").
-		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.MuscleCode).
+		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, model.MuscleLevelMuscle, nil, nil, musReq.MuscleCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.UpdateMuscle(musReq)
+	err = dao.UpdateMuscle(context.Background(), musReq)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -191,13 +194,15 @@ func TestUpdateMuscle_NotFound(t *testing.T) {
 		},
 	}
 
-	mock.ExpectExec("UPDATE muscle_type SET muscle_name = \\$1, muscle_description = \\$2, muscle_group = \\$3 WHERE muscle_code = \\$4").
-		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, musReq.MuscleCode).
+	expectMuscleHierarchy(mock, musReq.MuscleCode, model.MuscleLevelMuscle, nil, false)
+	mock.ExpectExec("UPDATE muscle_type SET muscle_name = \\$1, muscle_description = \\$2, muscle_group = \\$3, muscle_level = \\$4, parent_code = \\$5, movement_pattern = \\$6 WHERE muscle_code = \\$7").
+		WithArgs(musReq.MuscleName, musReq.MuscleDesc, musReq.MuscleGroup, model.MuscleLevelMuscle, nil, nil, musReq.MuscleCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.UpdateMuscle(musReq)
+	err = dao.UpdateMuscle(context.Background(), musReq)
 	assert.Error(t, err)
-	assert.Equal(t, "muscle with Code INVALID not found", err.Error())
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "muscle with code INVALID not found", err.Error())
 }
 
 func TestDeleteMuscle(t *testing.T) {
@@ -212,7 +217,7 @@ func TestDeleteMuscle(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = dao.DeleteMuscle(catCode)
+	err = dao.DeleteMuscle(context.Background(), catCode)
 	assert.NoError(t, err)
 }
 
@@ -228,7 +233,7 @@ func TestDeleteMuscle_Error(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnError(sqlmock.ErrCancelled)
 
-	err = dao.DeleteMuscle(catCode)
+	err = dao.DeleteMuscle(context.Background(), catCode)
 	assert.Error(t, err)
 	assert.Equal(t, "canceling query due to user request", err.Error())
 }
@@ -245,7 +250,168 @@ func TestDeleteMuscle_NotFound(t *testing.T) {
 		WithArgs(catCode).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = dao.DeleteMuscle(catCode)
+	err = dao.DeleteMuscle(context.Background(), catCode)
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFound)
 	assert.Equal(t, "muscle with code INVALID not found", err.Error())
 }
+
+// expectMuscleHierarchy expects the lookup of the parent level and of
+// children at or above the level of a created or updated muscle.
+func expectMuscleHierarchy(mock sqlmock.Sqlmock, code string, level model.MuscleLevel, parentLevel any, childAbove bool) {
+	var parent any
+	if parentLevel != nil {
+		parent = sqlmock.AnyArg()
+	}
+	mock.ExpectQuery("SELECT \\(SELECT muscle_level FROM muscle_type WHERE muscle_code = \\$1\\) AS parent_level").
+		WithArgs(parent, code, level).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_level", "child_above"}).AddRow(parentLevel, childAbove))
+}
+
+func TestCreateMuscle_Hierarchy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMuscleDAO(sqlx.NewDb(db, "postgres"))
+
+	quad := "quad"
+	musReq := &model.MuscleRequest{
+		MuscleFields: model.MuscleFields{
+			MuscleCode:  "rectus_femoris",
+			MuscleName:  "Rectus femoris",
+			MuscleGroup: "Legs",
+			MuscleLevel: model.MuscleLevelMuscle,
+			ParentCode:  &quad,
+		},
+		CreatedBy: uuid.New(),
+	}
+
+	expectMuscleHierarchy(mock, "RECTUS_FEMORIS", model.MuscleLevelMuscle, "group", false)
+	mock.ExpectQuery("INSERT INTO muscle_type").
+		WithArgs("RECTUS_FEMORIS", "Rectus femoris", "", "Legs", model.MuscleLevelMuscle, "QUAD", nil, musReq.CreatedBy).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+
+	mus, err := dao.CreateMuscle(context.Background(), musReq)
+	assert.NoError(t, err)
+	assert.Equal(t, "QUAD", *mus.ParentCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateMuscle_InvalidHierarchy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMuscleDAO(sqlx.NewDb(db, "postgres"))
+
+	parent := "RECTUS_FEMORIS"
+	tests := []struct {
+		name        string
+		level       model.MuscleLevel
+		parentLevel any
+		want        error
+	}{
+		{"group below a muscle", model.MuscleLevelGroup, "muscle", ErrInvalidHierarchy},
+		{"muscle below a muscle", model.MuscleLevelMuscle, "muscle", ErrInvalidHierarchy},
+		{"missing parent", model.MuscleLevelHead, nil, ErrNotFound},
+	}
+	for _, tt := range tests {
+		musReq := &model.MuscleRequest{MuscleFields: model.MuscleFields{
+			MuscleCode: "VASTUS", MuscleLevel: tt.level, ParentCode: &parent,
+		}}
+		mock.ExpectQuery("SELECT \\(SELECT muscle_level").
+			WillReturnRows(sqlmock.NewRows([]string{"parent_level", "child_above"}).AddRow(tt.parentLevel, false))
+
+		_, err := dao.CreateMuscle(context.Background(), musReq)
+		assert.ErrorIs(t, err, tt.want, tt.name)
+	}
+
+	// Rejected without asking the database.
+	for _, level := range []model.MuscleLevel{model.MuscleLevelRegion, "organ"} {
+		musReq := &model.MuscleRequest{MuscleFields: model.MuscleFields{
+			MuscleCode: "LEGS", MuscleLevel: level, ParentCode: &parent,
+		}}
+		_, err := dao.CreateMuscle(context.Background(), musReq)
+		assert.ErrorIs(t, err, ErrInvalidHierarchy, string(level))
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateMuscle_ChildAbove(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMuscleDAO(sqlx.NewDb(db, "postgres"))
+
+	musReq := &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "QUAD", MuscleName: "Quadriceps", MuscleGroup: "Legs", MuscleLevel: model.MuscleLevelHead,
+	}}
+	expectMuscleHierarchy(mock, "QUAD", model.MuscleLevelHead, nil, true)
+
+	err = dao.UpdateMuscle(context.Background(), musReq)
+	assert.ErrorIs(t, err, ErrInvalidHierarchy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMuscleTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMuscleDAO(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT muscle_code, muscle_name, .* FROM muscle_type ORDER BY muscle_name").
+		WillReturnRows(sqlmock.NewRows([]string{"muscle_code", "muscle_name", "muscle_description", "muscle_group", "muscle_level", "parent_code"}).
+			AddRow("LAT", "Latissimus", "", "Back", "muscle", nil).
+			AddRow("LEGS", "Legs", "", "Legs", "region", nil).
+			AddRow("QUAD", "Quadriceps", "", "Legs", "group", "LEGS").
+			AddRow("RECTUS_FEMORIS", "Rectus femoris", "", "Legs", "muscle", "QUAD").
+			AddRow("VASTUS_LATERALIS", "Vastus lateralis", "", "Legs", "muscle", "QUAD"))
+
+	tree, err := dao.GetMuscleTree(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "LAT", tree[0].MuscleCode)
+	assert.Empty(t, tree[0].Children)
+	legs := tree[1]
+	assert.Equal(t, model.MuscleLevelRegion, legs.MuscleLevel)
+	assert.Len(t, legs.Children, 1)
+	quad := legs.Children[0]
+	assert.Equal(t, "QUAD", quad.MuscleCode)
+	assert.Len(t, quad.Children, 2)
+	assert.Equal(t, "Rectus femoris", quad.Children[0].MuscleName)
+	assert.Equal(t, "Vastus lateralis", quad.Children[1].MuscleName)
+}
+
+func TestFindExercisesByMuscle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewMuscleDAO(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM muscle_type WHERE muscle_code = \\$1\\)").
+		WithArgs("LEGS").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("FROM muscle_ancestry a .* WHERE a.ancestor_code = \\$1").
+		WithArgs("LEGS", "primary").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "category_code", "muscle_code", "muscle_role"}).
+			AddRow(exUuid, "Squat", "STRENGTH", "RECTUS_FEMORIS", "primary"))
+
+	exercises, err := dao.FindExercisesByMuscle(context.Background(), "legs", model.MuscleRolePrimary)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleExercise{{
+		ExerciseUuid: exUuid, ExerciseName: "Squat", CategoryCode: "STRENGTH",
+		MuscleCode: "RECTUS_FEMORIS", MuscleRole: model.MuscleRolePrimary,
+	}}, exercises)
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("WINGS").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	_, err = dao.FindExercisesByMuscle(context.Background(), "wings", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
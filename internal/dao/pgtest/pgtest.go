@@ -0,0 +1,147 @@
+/*
+ * Package pgtest runs tests against a real Postgres database. The database
+ * is given by the TEST_POSTGRES_DSN variable and is wiped: on first use in a
+ * test binary the public schema is dropped and db/schema.sql is applied, and
+ * every test starts with empty tables. Use a database of its own, never the
+ * one the service runs on.
+ *
+ * Test binaries share the database, so run the packages one at a time:
+ *
+ *	go test -tags integration -p 1 ./...
+ */
+package pgtest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DSNVariable names the environment variable holding the connection string
+// of the test database, e.g.
+// "host=localhost port=5432 user=postgres password=postgres dbname=shred_test sslmode=disable".
+const DSNVariable = "TEST_POSTGRES_DSN"
+
+// dsn is read before any test runs, as some tests clear the environment.
+var dsn = os.Getenv(DSNVariable)
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+/*
+ * Open connects to the test database and empties all its tables. The
+ * schema is created on first use. Tests are skipped when TEST_POSTGRES_DSN is
+ * not set and the connection is closed when the test ends.
+ */
+func Open(t testing.TB) *sqlx.DB {
+	t.Helper()
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNVariable)
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connecting to the test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrateOnce.Do(func() { migrateErr = migrate(db) })
+	if migrateErr != nil {
+		t.Fatalf("creating the schema: %v", migrateErr)
+	}
+	if err := Reset(db); err != nil {
+		t.Fatalf("emptying the tables: %v", err)
+	}
+	return db
+}
+
+const dropSchemaDDL string = `
+	DROP SCHEMA IF EXISTS public CASCADE;
+	CREATE SCHEMA public`
+
+// migrate recreates the public schema from db/schema.sql.
+func migrate(db *sqlx.DB) error {
+	schema, err := readSchema()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(dropSchemaDDL); err != nil {
+		return err
+	}
+	_, err = db.Exec(schema)
+	return err
+}
+
+/*
+ * readSchema returns the statements of db/schema.sql without the lines that
+ * create and connect to the shred_db database, which only psql understands
+ * and which the test database replaces.
+ */
+func readSchema() (string, error) {
+	_, file, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(file), "..", "..", "..", "db", "schema.sql")
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var schema strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, `\`) || strings.HasPrefix(line, "CREATE DATABASE") {
+			continue
+		}
+		schema.WriteString(line)
+		schema.WriteByte('\n')
+	}
+	return schema.String(), scanner.Err()
+}
+
+const listTablesDQL string = `
+	SELECT tablename
+	FROM   pg_tables
+	WHERE  schemaname = 'public'`
+
+// Reset empties all tables of the test database.
+func Reset(db *sqlx.DB) error {
+	var tables []string
+	if err := db.Select(&tables, listTablesDQL); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	for i, table := range tables {
+		tables[i] = pq.QuoteIdentifier(table)
+	}
+	_, err := db.Exec(fmt.Sprintf("TRUNCATE %s RESTART IDENTITY CASCADE", strings.Join(tables, ", ")))
+	return err
+}
+
+const createUserDML string = `
+	INSERT INTO shred_user (user_uuid, first_name, last_name, email, created_by)
+	VALUES ($1, $2, 'Test', $3, $1)`
+
+// CreateUser adds a user who created themselves, as the service cannot
+// create users, and returns their uuid.
+func CreateUser(t testing.TB, db *sqlx.DB, firstName string) uuid.UUID {
+	t.Helper()
+	userUuid := uuid.New()
+	email := strings.ToLower(firstName) + "@example.com"
+	if _, err := db.Exec(createUserDML, userUuid, firstName, email); err != nil {
+		t.Fatalf("creating user %s: %v", firstName, err)
+	}
+	return userUuid
+}
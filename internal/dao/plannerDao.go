@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// PlannerDao reads what the session planner picks exercises from: the
+// catalog with the muscles and apparatus of each exercise.
+type PlannerDao struct {
+	db *sqlx.DB
+}
+
+type PlannerDaoInterface interface {
+	ListCandidates(ctx context.Context, category string) ([]model.CandidateExercise, error)
+}
+
+// Ensure PlannerDao implements PlannerDaoInterface
+var _ PlannerDaoInterface = (*PlannerDao)(nil)
+
+// NewPlannerDao creates a new instance of PlannerDao.
+func NewPlannerDao(db *sqlx.DB) *PlannerDao {
+	return &PlannerDao{db: db}
+}
+
+// listCandidatesDQL lists the exercises, optionally of category $1, with
+// their apparatus.
+const listCandidatesDQL string = `
+	SELECT e.exercise_uuid, e.exercise_name, e.category_code,
+	       COALESCE(array_agg(ea.apparatus_code ORDER BY ea.apparatus_code)
+	                FILTER (WHERE ea.apparatus_code IS NOT NULL), '{}') AS apparatus
+	FROM      exercise e
+	LEFT JOIN exercise_apparatus ea ON ea.exercise_uuid = e.exercise_uuid
+	WHERE     $1 = '' OR e.category_code = $1
+	GROUP BY  e.exercise_uuid
+	ORDER BY  e.exercise_uuid`
+
+// listCandidateMusclesDQL lists the muscles of the exercises of
+// listCandidatesDQL with their ancestors, nearest first, and the movement
+// pattern of the nearest of these that has one.
+const listCandidateMusclesDQL string = `
+	SELECT em.exercise_uuid, em.muscle_code, em.muscle_role,
+	       array_agg(a.ancestor_code ORDER BY a.distance) AS ancestors,
+	       COALESCE((SELECT CAST(pm.movement_pattern AS TEXT)
+	                 FROM   muscle_ancestry pa
+	                 JOIN   muscle_type pm ON pm.muscle_code = pa.ancestor_code
+	                 WHERE  pa.muscle_code = em.muscle_code AND pm.movement_pattern IS NOT NULL
+	                 ORDER BY pa.distance LIMIT 1), 'other') AS pattern
+	FROM   exercise_muscle em
+	JOIN   exercise e        ON e.exercise_uuid = em.exercise_uuid
+	JOIN   muscle_ancestry a ON a.muscle_code = em.muscle_code
+	WHERE  $1 = '' OR e.category_code = $1
+	GROUP BY em.exercise_uuid, em.muscle_code, em.muscle_role
+	ORDER BY em.exercise_uuid, em.muscle_code`
+
+func (dao *PlannerDao) ListCandidates(ctx context.Context, category string) (_ []model.CandidateExercise, err error) {
+	defer observe(ctx, "PlannerDao", "ListCandidates", time.Now(), &err)
+	category = strings.ToUpper(category)
+
+	var exRows []struct {
+		ExerciseUuid uuid.UUID      `db:"exercise_uuid"`
+		ExerciseName string         `db:"exercise_name"`
+		CategoryCode string         `db:"category_code"`
+		Apparatus    pq.StringArray `db:"apparatus"`
+	}
+	if err := selectContext(ctx, dao.db, "listCandidatesDQL", &exRows, listCandidatesDQL, category); err != nil {
+		return nil, err
+	}
+	var musRows []struct {
+		ExerciseUuid uuid.UUID             `db:"exercise_uuid"`
+		MuscleCode   string                `db:"muscle_code"`
+		MuscleRole   model.MuscleRole      `db:"muscle_role"`
+		Ancestors    pq.StringArray        `db:"ancestors"`
+		Pattern      model.MovementPattern `db:"pattern"`
+	}
+	if err := selectContext(ctx, dao.db, "listCandidateMusclesDQL", &musRows, listCandidateMusclesDQL, category); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]model.CandidateExercise, len(exRows))
+	index := make(map[uuid.UUID]int, len(exRows))
+	for i, row := range exRows {
+		candidates[i] = model.CandidateExercise{
+			ExerciseUuid: row.ExerciseUuid,
+			ExerciseName: row.ExerciseName,
+			CategoryCode: row.CategoryCode,
+			Apparatus:    []string(row.Apparatus),
+		}
+		index[row.ExerciseUuid] = i
+	}
+	for _, row := range musRows {
+		i, ok := index[row.ExerciseUuid]
+		if !ok {
+			continue
+		}
+		candidates[i].Muscles = append(candidates[i].Muscles, model.CandidateMuscle{
+			MuscleCode: row.MuscleCode,
+			MuscleRole: row.MuscleRole,
+			Ancestors:  []string(row.Ancestors),
+			Pattern:    row.Pattern,
+		})
+	}
+	return candidates, nil
+}
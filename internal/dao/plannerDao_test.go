@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewPlannerDao(sqlx.NewDb(db, "postgres"))
+
+	squat, run := uuid.New(), uuid.New()
+	mock.ExpectQuery("FROM exercise e LEFT JOIN exercise_apparatus ea .* WHERE \\$1 = '' OR e.category_code = \\$1").
+		WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "category_code", "apparatus"}).
+			AddRow(squat, "Back Squat", "STRENGTH", "{BARBELL}").
+			AddRow(run, "Running", "CARDIO", "{}"))
+	mock.ExpectQuery("FROM exercise_muscle em .* JOIN muscle_ancestry a").
+		WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "muscle_code", "muscle_role", "ancestors", "pattern"}).
+			AddRow(squat, "GLUTES", "secondary", "{GLUTES,LEGS}", "pull").
+			AddRow(squat, "QUAD", "primary", "{QUAD,LEGS}", "push"))
+
+	candidates, err := dao.ListCandidates(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, []string{"BARBELL"}, candidates[0].Apparatus)
+	assert.Equal(t, []model.CandidateMuscle{
+		{MuscleCode: "GLUTES", MuscleRole: model.MuscleRoleSecondary, Ancestors: []string{"GLUTES", "LEGS"}, Pattern: model.MovementPull},
+		{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary, Ancestors: []string{"QUAD", "LEGS"}, Pattern: model.MovementPush},
+	}, candidates[0].Muscles)
+	assert.Empty(t, candidates[1].Muscles)
+}
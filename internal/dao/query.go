@@ -0,0 +1,194 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/pwydra/shred/internal/dao"
+
+// DefaultQueryTimeout bounds statements unless configured otherwise.
+const DefaultQueryTimeout = 5 * time.Second
+
+// queryTimeout bounds each statement. Zero leaves statements to the
+// deadline of their context.
+var queryTimeout = DefaultQueryTimeout
+
+// Drivers name the stores the DAOs can run on.
+const (
+	DriverPostgres = "postgres"
+	// DriverMemory keeps everything in memory until the service stops.
+	DriverMemory = "memory"
+	// DriverSQLite keeps everything in an SQLite database file, see
+	// OpenSQLite.
+	DriverSQLite = "sqlite"
+)
+
+// DefaultSQLitePath is the database file of the sqlite driver unless
+// configured otherwise.
+const DefaultSQLitePath = "shred.db"
+
+type Config struct {
+	QueryTimeout time.Duration
+	Driver       string
+	SQLitePath   string
+}
+
+/*
+ * ConfigFromEnv reads DB_QUERY_TIMEOUT, a duration like 2s or 500ms, which
+ * bounds each statement the DAOs run; 0 turns the timeout off. It is
+ * DefaultQueryTimeout by default. DB_DRIVER picks the store, postgres by
+ * default, memory or sqlite, and SQLITE_PATH the database file of sqlite.
+ */
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{QueryTimeout: DefaultQueryTimeout, Driver: DriverPostgres, SQLitePath: DefaultSQLitePath}
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "":
+	case DriverPostgres, DriverMemory, DriverSQLite:
+		cfg.Driver = driver
+	default:
+		return Config{}, fmt.Errorf("DB_DRIVER must be postgres, memory or sqlite, not %q", driver)
+	}
+	if path := os.Getenv("SQLITE_PATH"); path != "" {
+		cfg.SQLitePath = path
+	}
+	if value := os.Getenv("DB_QUERY_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return Config{}, fmt.Errorf("DB_QUERY_TIMEOUT must be a duration like 2s, not %q", value)
+		}
+		cfg.QueryTimeout = timeout
+	}
+	return cfg, nil
+}
+
+// Configure applies cfg to all DAOs. Call it before they are used.
+func Configure(cfg Config) {
+	queryTimeout = cfg.QueryTimeout
+}
+
+// queryer runs statements, either on the database or in a transaction.
+type queryer interface {
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row
+}
+
+var (
+	_ queryer = (*sqlx.DB)(nil)
+	_ queryer = (*sqlx.Tx)(nil)
+)
+
+/*
+ * The helpers below run a statement, in the transaction of the unit of work
+ * of ctx if there is one, within the query timeout and in a span of its own
+ * named after the constant holding it, e.g. getPreferencesDQL, with the
+ * number of rows it returned or changed and its error, if any. On SQLite
+ * they run its SQLite variant, see inDialect.
+ */
+
+func selectContext(ctx context.Context, q queryer, statement string, dest any, query string, args ...any) error {
+	q = inTransaction(ctx, q)
+	query, args = inDialect(q, statement, query, args)
+	ctx, run := startQuery(ctx, q, statement)
+	err := q.SelectContext(ctx, dest, query, args...)
+	var rows int64
+	if err == nil {
+		rows = int64(reflect.ValueOf(dest).Elem().Len())
+	}
+	return run.end(rows, err)
+}
+
+func getContext(ctx context.Context, q queryer, statement string, dest any, query string, args ...any) error {
+	q = inTransaction(ctx, q)
+	query, args = inDialect(q, statement, query, args)
+	ctx, run := startQuery(ctx, q, statement)
+	err := q.GetContext(ctx, dest, query, args...)
+	return run.end(scanned(err), err)
+}
+
+func execContext(ctx context.Context, q queryer, statement string, query string, args ...any) (sql.Result, error) {
+	q = inTransaction(ctx, q)
+	query, args = inDialect(q, statement, query, args)
+	ctx, run := startQuery(ctx, q, statement)
+	result, err := q.ExecContext(ctx, query, args...)
+	var rows int64
+	if err == nil {
+		rows, _ = result.RowsAffected()
+	}
+	return result, run.end(rows, err)
+}
+
+// scanContext runs a statement returning at most one row and scans its
+// columns into dest, like the Scan method of sql.Row.
+func scanContext(ctx context.Context, q queryer, statement string, query string, args []any, dest ...any) error {
+	q = inTransaction(ctx, q)
+	query, args = inDialect(q, statement, query, args)
+	ctx, run := startQuery(ctx, q, statement)
+	err := q.QueryRowxContext(ctx, query, args...).Scan(dest...)
+	return run.end(scanned(err), err)
+}
+
+// queryRun is a statement being run.
+type queryRun struct {
+	ctx    context.Context
+	span   trace.Span
+	cancel context.CancelFunc
+}
+
+func startQuery(ctx context.Context, q queryer, statement string) (context.Context, *queryRun) {
+	system := "postgresql"
+	if isSQLite(q) {
+		system = "sqlite"
+	}
+	cancel := context.CancelFunc(func() {})
+	if timeout := queryTimeout; timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", system),
+			attribute.String("db.statement.name", statement)))
+	return ctx, &queryRun{ctx: ctx, span: span, cancel: cancel}
+}
+
+/*
+ * end ends the span of the statement and returns its error. Drivers report
+ * statements that ran out of time or were canceled in their own words, so
+ * the error is made to wrap the context error as well. Finding no row is not
+ * an error.
+ */
+func (run *queryRun) end(rows int64, err error) error {
+	if ctxErr := run.ctx.Err(); err != nil && ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	run.cancel()
+	run.span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		run.span.RecordError(err)
+		run.span.SetStatus(codes.Error, err.Error())
+	}
+	run.span.End()
+	return err
+}
+
+// scanned is the number of rows a single row lookup found.
+func scanned(err error) int64 {
+	if err == nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestExporter records the spans of the test in memory.
+func newTestExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracing.NewProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing_QueryRow(t *testing.T) {
+	exporter := newTestExporter(t)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+	mock.ExpectQuery("SELECT unit_system").
+		WillReturnRows(sqlmock.NewRows([]string{"unit_system", "load_increment"}).AddRow("metric", 2.5))
+	mock.ExpectQuery("SELECT unit_system").WillReturnError(sql.ErrNoRows)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	_, err = dao.GetPreferences(ctx, uuid.New())
+	assert.NoError(t, err)
+	_, err = dao.GetPreferences(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	for _, span := range spans[:2] {
+		assert.Equal(t, "getPreferencesDQL", span.Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+		assert.Equal(t, "getPreferencesDQL", spanAttr(span, "db.statement.name").AsString())
+		assert.Equal(t, codes.Unset, span.Status.Code, "finding no row is not an error")
+	}
+	assert.Equal(t, int64(1), spanAttr(spans[0], "db.rows_affected").AsInt64())
+	assert.Equal(t, int64(0), spanAttr(spans[1], "db.rows_affected").AsInt64())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTracing_Scan(t *testing.T) {
+	exporter := newTestExporter(t)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCategoryDAO(sqlx.NewDb(db, "postgres"))
+	req := &model.CategoryRequest{CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}}
+	mock.ExpectQuery("INSERT INTO category_type").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectQuery("INSERT INTO category_type").WillReturnError(sql.ErrConnDone)
+
+	_, err = dao.CreateCategory(context.Background(), req)
+	assert.NoError(t, err)
+	_, err = dao.CreateCategory(context.Background(), req)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2, "the span ends whether the row is scanned or not")
+	assert.Equal(t, "createCatDML", spans[0].Name)
+	assert.Equal(t, int64(1), spanAttr(spans[0], "db.rows_affected").AsInt64())
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTracing_Select(t *testing.T) {
+	exporter := newTestExporter(t)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewCoachDao(sqlx.NewDb(db, "postgres"))
+	mock.ExpectQuery("SELECT u.user_uuid").
+		WillReturnRows(sqlmock.NewRows([]string{"user_uuid", "first_name", "last_name", "created_at"}).
+			AddRow(uuid.New(), "John", "Doe", time.Now()).
+			AddRow(uuid.New(), "Jane", "Roe", time.Now()))
+
+	clients, err := dao.ListClients(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Len(t, clients, 2)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "listClientsDQL", spans[0].Name)
+	assert.Equal(t, int64(2), spanAttr(spans[0], "db.rows_affected").AsInt64())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTracing_ExecInTransaction(t *testing.T) {
+	exporter := newTestExporter(t)
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(uuid.New(), time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO workout_set").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	req := model.WorkoutSessionRequest{Sets: []model.WorkoutSet{{ExerciseUuid: uuid.New()}}}
+	_, err = dao.CreateSessions(context.Background(), uuid.New(), []model.WorkoutSessionRequest{req})
+	assert.Error(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "createSessionDML", spans[0].Name)
+	assert.Equal(t, "createSetDML", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, sqlmock.ErrCancelled.Error(), spans[1].Status.Description)
+	assert.Len(t, spans[1].Events, 1, "the error is recorded")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryTimeout(t *testing.T) {
+	previous := queryTimeout
+	Configure(Config{QueryTimeout: 10 * time.Millisecond})
+	t.Cleanup(func() { queryTimeout = previous })
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+	mock.ExpectQuery("SELECT unit_system").WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"unit_system", "load_increment"}).AddRow("metric", 2.5))
+
+	start := time.Now()
+	_, err = dao.GetPreferences(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestQueryTimeout_Canceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewExerciseDao(sqlx.NewDb(db, "postgres"))
+	mock.ExpectExec("DELETE FROM exercise").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = dao.Delete(ctx, uuid.New())
+	assert.ErrorIs(t, err, context.Canceled, "a client going away cancels its queries")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		fails    bool
+	}{
+		{"", DefaultQueryTimeout, false},
+		{"250ms", 250 * time.Millisecond, false},
+		{"0", 0, false},
+		{"-1s", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DB_QUERY_TIMEOUT", tt.value)
+
+			cfg, err := ConfigFromEnv()
+			if tt.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.QueryTimeout)
+		})
+	}
+}
+
+func TestConfigFromEnv_Driver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "")
+	t.Setenv("SQLITE_PATH", "")
+	cfg, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, DriverPostgres, cfg.Driver)
+	assert.Equal(t, DefaultSQLitePath, cfg.SQLitePath)
+
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("SQLITE_PATH", "/tmp/dev.db")
+	cfg, err = ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, DriverSQLite, cfg.Driver)
+	assert.Equal(t, "/tmp/dev.db", cfg.SQLitePath)
+
+	t.Setenv("DB_DRIVER", "mysql")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
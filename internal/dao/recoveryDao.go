@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// RecoveryDao reads the training load of users' muscles from their logged
+// sets.
+type RecoveryDao struct {
+	db *sqlx.DB
+}
+
+type RecoveryDaoInterface interface {
+	ListMuscleLoads(ctx context.Context, userUuid uuid.UUID, since time.Time) ([]model.MuscleLoad, error)
+}
+
+// Ensure RecoveryDao implements RecoveryDaoInterface
+var _ RecoveryDaoInterface = (*RecoveryDao)(nil)
+
+// NewRecoveryDao creates a new instance of RecoveryDao.
+func NewRecoveryDao(db *sqlx.DB) *RecoveryDao {
+	return &RecoveryDao{db: db}
+}
+
+// listMuscleLoadsDQL counts the sets of each session of user $1 since $2 per
+// muscle and role.
+const listMuscleLoadsDQL string = `
+	SELECT s.started_at, em.muscle_code, em.muscle_role, count(*) AS sets
+	FROM   workout_session s
+	JOIN   workout_set ws     ON ws.session_uuid = s.session_uuid
+	JOIN   exercise_muscle em ON em.exercise_uuid = ws.exercise_uuid
+	WHERE  s.user_uuid = $1 AND s.started_at >= $2
+	GROUP BY s.session_uuid, s.started_at, em.muscle_code, em.muscle_role
+	ORDER BY s.started_at, em.muscle_code`
+
+func (dao *RecoveryDao) ListMuscleLoads(ctx context.Context, userUuid uuid.UUID, since time.Time) (_ []model.MuscleLoad, err error) {
+	defer observe(ctx, "RecoveryDao", "ListMuscleLoads", time.Now(), &err)
+	var loads []model.MuscleLoad
+	if err := selectContext(ctx, dao.db, "listMuscleLoadsDQL", &loads, listMuscleLoadsDQL, userUuid, since.UTC()); err != nil {
+		return nil, err
+	}
+	return loads, nil
+}
@@ -0,0 +1,37 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMuscleLoads(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRecoveryDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	since := time.Date(2025, 2, 26, 18, 0, 0, 0, time.UTC)
+	startedAt := since.Add(72 * time.Hour)
+	mock.ExpectQuery("SELECT s.started_at, em.muscle_code, em.muscle_role, count\\(\\*\\) AS sets .* WHERE s.user_uuid = \\$1 AND s.started_at >= \\$2").
+		WithArgs(userUuid, since).
+		WillReturnRows(sqlmock.NewRows([]string{"started_at", "muscle_code", "muscle_role", "sets"}).
+			AddRow(startedAt, "QUAD", "primary", 5).
+			AddRow(startedAt, "GLUTES", "secondary", 5))
+
+	loads, err := dao.ListMuscleLoads(context.Background(), userUuid, since)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MuscleLoad{
+		{StartedAt: startedAt, MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary, Sets: 5},
+		{StartedAt: startedAt, MuscleCode: "GLUTES", MuscleRole: model.MuscleRoleSecondary, Sets: 5},
+	}, loads)
+}
@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// RelationDao provides access to the graph of variations, progressions,
+// regressions and alternatives between exercises.
+type RelationDao struct {
+	db *sqlx.DB
+}
+
+type RelationDaoInterface interface {
+	CreateRelation(ctx context.Context, exUuid uuid.UUID, req *model.ExerciseRelationRequest) (*model.ExerciseRelation, error)
+	GetRelations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseRelation, error)
+	DeleteRelation(ctx context.Context, exUuid, relatedUuid uuid.UUID, relation model.RelationType) error
+	FindProgressions(ctx context.Context, exUuid uuid.UUID, direction model.Direction, maxSteps int, samePrimaryMuscles bool) ([]model.RelatedExercise, error)
+}
+
+// Ensure RelationDao implements RelationDaoInterface
+var _ RelationDaoInterface = (*RelationDao)(nil)
+
+// NewRelationDao creates a new instance of RelationDao.
+func NewRelationDao(db *sqlx.DB) *RelationDao {
+	return &RelationDao{db: db}
+}
+
+const createRelationDML string = `
+	INSERT INTO exercise_relationship (exercise_uuid, related_uuid, relation, created_by)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (exercise_uuid, related_uuid, relation) DO NOTHING
+	RETURNING created_at`
+
+func (dao *RelationDao) CreateRelation(ctx context.Context, exUuid uuid.UUID, req *model.ExerciseRelationRequest) (_ *model.ExerciseRelation, err error) {
+	defer observe(ctx, "RelationDao", "CreateRelation", time.Now(), &err)
+	relation := model.ExerciseRelation{
+		ExerciseUuid: exUuid,
+		Relation:     req.Relation,
+		RelatedUuid:  req.RelatedUuid,
+		CreatedBy:    req.CreatedBy,
+	}
+	err = scanContext(ctx, dao.db, "createRelationDML", createRelationDML,
+		[]any{exUuid, req.RelatedUuid, req.Relation, req.CreatedBy}, &relation.CreatedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("relation %s %s %s %w", exUuid, req.Relation, req.RelatedUuid, ErrConflict)
+	case isForeignKeyViolation(err):
+		return nil, fmt.Errorf("exercise %s or %s %w", exUuid, req.RelatedUuid, ErrNotFound)
+	case err != nil:
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// GetRelations returns the edges from and to an exercise with the names of
+// the exercises at both ends.
+const getRelationsDQL string = `
+	SELECT r.exercise_uuid, e.exercise_name, r.relation, r.related_uuid,
+	       x.exercise_name AS related_name, r.created_by, r.created_at
+	FROM   exercise_relationship r
+	JOIN   exercise e ON e.exercise_uuid = r.exercise_uuid
+	JOIN   exercise x ON x.exercise_uuid = r.related_uuid
+	WHERE  r.exercise_uuid = $1 OR r.related_uuid = $1
+	ORDER BY r.relation, e.exercise_name, x.exercise_name`
+
+func (dao *RelationDao) GetRelations(ctx context.Context, exUuid uuid.UUID) (_ []model.ExerciseRelation, err error) {
+	defer observe(ctx, "RelationDao", "GetRelations", time.Now(), &err)
+	relations := []model.ExerciseRelation{}
+	if err := selectContext(ctx, dao.db, "getRelationsDQL", &relations, getRelationsDQL, exUuid); err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+const deleteRelationDML string = `
+	DELETE FROM exercise_relationship
+	WHERE  exercise_uuid = $1 AND related_uuid = $2 AND relation = $3`
+
+func (dao *RelationDao) DeleteRelation(ctx context.Context, exUuid, relatedUuid uuid.UUID, relation model.RelationType) (err error) {
+	defer observe(ctx, "RelationDao", "DeleteRelation", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteRelationDML", deleteRelationDML, exUuid, relatedUuid, relation)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("relation %s %s %s %w", exUuid, relation, relatedUuid, ErrNotFound)
+	}
+	return nil
+}
+
+// FindProgressions walks the progression graph from an exercise, following
+// progression_of and regression_of edges in the given direction for up to
+// $4 steps. Each exercise is reported once, at its shortest distance. With
+// $5 set only exercises that have all primary muscles of the starting
+// exercise as primary muscles are returned, and the walk also crosses
+// alternative_to edges, which cost no step: the progressions of an
+// alternative are suggested, the alternatives of the starting exercise are
+// not, as they are neither easier nor harder.
+//
+// edges(source, target, cost) holds one edge per progression, pointing from
+// an exercise to one that is a step in the requested direction: "a
+// progression_of b" makes b easier than a, "a regression_of b" makes a
+// easier than b. $2 and $3 name which relation is followed forwards and
+// which backwards. Alternatives are followed both ways.
+const findProgressionsDQL string = `
+	WITH RECURSIVE edges (source, target, cost) AS (
+		SELECT exercise_uuid, related_uuid, 1 FROM exercise_relationship WHERE relation = $2
+		UNION
+		SELECT related_uuid, exercise_uuid, 1 FROM exercise_relationship WHERE relation = $3
+		UNION
+		SELECT exercise_uuid, related_uuid, 0 FROM exercise_relationship WHERE relation = 'alternative_to' AND $5
+		UNION
+		SELECT related_uuid, exercise_uuid, 0 FROM exercise_relationship WHERE relation = 'alternative_to' AND $5
+	), walk (exercise_uuid, steps) AS (
+		SELECT target, cost FROM edges WHERE source = $1
+		UNION
+		SELECT s.target, w.steps + s.cost
+		FROM   edges s
+		JOIN   walk w ON w.exercise_uuid = s.source
+		WHERE  w.steps + s.cost <= $4
+	)
+	SELECT w.exercise_uuid, e.exercise_name, e.category_code, MIN(w.steps) AS steps
+	FROM   walk w
+	JOIN   exercise e ON e.exercise_uuid = w.exercise_uuid
+	WHERE  w.exercise_uuid <> $1
+	AND    (NOT $5 OR NOT EXISTS (
+		SELECT 1
+		FROM   exercise_muscle m
+		WHERE  m.exercise_uuid = $1 AND m.muscle_role = 'primary'
+		AND    NOT EXISTS (
+			SELECT 1
+			FROM   exercise_muscle o
+			WHERE  o.exercise_uuid = w.exercise_uuid
+			AND    o.muscle_code = m.muscle_code
+			AND    o.muscle_role = 'primary')))
+	GROUP BY w.exercise_uuid, e.exercise_name, e.category_code
+	HAVING MIN(w.steps) > 0
+	ORDER BY steps, e.exercise_name`
+
+func (dao *RelationDao) FindProgressions(ctx context.Context, exUuid uuid.UUID, direction model.Direction, maxSteps int, samePrimaryMuscles bool) (_ []model.RelatedExercise, err error) {
+	defer observe(ctx, "RelationDao", "FindProgressions", time.Now(), &err)
+	forward, backward := model.RelationProgressionOf, model.RelationRegressionOf
+	switch direction {
+	case model.DirectionEasier:
+	case model.DirectionHarder:
+		forward, backward = backward, forward
+	default:
+		return nil, fmt.Errorf("unknown direction %q", direction)
+	}
+
+	related := []model.RelatedExercise{}
+	if err := selectContext(ctx, dao.db, "findProgressionsDQL", &related, findProgressionsDQL,
+		exUuid, forward, backward, maxSteps, samePrimaryMuscles); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
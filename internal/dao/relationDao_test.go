@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRelation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRelationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid, relatedUuid, createdBy := uuid.New(), uuid.New(), uuid.New()
+	req := &model.ExerciseRelationRequest{RelatedUuid: relatedUuid, Relation: model.RelationRegressionOf, CreatedBy: createdBy}
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO exercise_relationship .* ON CONFLICT .* DO NOTHING").
+		WithArgs(exUuid, relatedUuid, model.RelationRegressionOf, createdBy).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+
+	relation, err := dao.CreateRelation(context.Background(), exUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, exUuid, relation.ExerciseUuid)
+	assert.Equal(t, model.RelationRegressionOf, relation.Relation)
+	assert.Equal(t, now, relation.CreatedAt)
+}
+
+func TestCreateRelation_Errors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRelationDao(sqlx.NewDb(db, "postgres"))
+
+	req := &model.ExerciseRelationRequest{RelatedUuid: uuid.New(), Relation: model.RelationAlternativeTo, CreatedBy: uuid.New()}
+	mock.ExpectQuery("INSERT INTO exercise_relationship").WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	mock.ExpectQuery("INSERT INTO exercise_relationship").WillReturnError(&pq.Error{Code: "23503"})
+
+	_, err = dao.CreateRelation(context.Background(), uuid.New(), req)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	_, err = dao.CreateRelation(context.Background(), uuid.New(), req)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetRelations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRelationDao(sqlx.NewDb(db, "postgres"))
+
+	pushUp, kneePushUp := uuid.New(), uuid.New()
+	mock.ExpectQuery("FROM exercise_relationship r .* WHERE r.exercise_uuid = \\$1 OR r.related_uuid = \\$1").
+		WithArgs(pushUp).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "relation", "related_uuid", "related_name", "created_by", "created_at"}).
+			AddRow(kneePushUp, "Knee Push-up", "regression_of", pushUp, "Push-up", uuid.New(), time.Now()))
+
+	relations, err := dao.GetRelations(context.Background(), pushUp)
+	assert.NoError(t, err)
+	assert.Len(t, relations, 1)
+	assert.Equal(t, "Knee Push-up", relations[0].ExerciseName)
+	assert.Equal(t, "Push-up", relations[0].RelatedName)
+}
+
+func TestDeleteRelation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRelationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid, relatedUuid := uuid.New(), uuid.New()
+	mock.ExpectExec("DELETE FROM exercise_relationship").
+		WithArgs(exUuid, relatedUuid, model.RelationVariationOf).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM exercise_relationship").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, dao.DeleteRelation(context.Background(), exUuid, relatedUuid, model.RelationVariationOf))
+	err = dao.DeleteRelation(context.Background(), exUuid, relatedUuid, model.RelationVariationOf)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFindProgressions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewRelationDao(sqlx.NewDb(db, "postgres"))
+
+	pushUp, kneePushUp := uuid.New(), uuid.New()
+	columns := []string{"exercise_uuid", "exercise_name", "category_code", "steps"}
+	mock.ExpectQuery("WITH RECURSIVE edges").
+		WithArgs(pushUp, model.RelationProgressionOf, model.RelationRegressionOf, 3, true).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(kneePushUp, "Knee Push-up", "STRENGTH", 1))
+	mock.ExpectQuery("WITH RECURSIVE edges").
+		WithArgs(kneePushUp, model.RelationRegressionOf, model.RelationProgressionOf, 2, false).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(pushUp, "Push-up", "STRENGTH", 1))
+
+	easier, err := dao.FindProgressions(context.Background(), pushUp, model.DirectionEasier, 3, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RelatedExercise{{ExerciseUuid: kneePushUp, ExerciseName: "Knee Push-up", CategoryCode: "STRENGTH", Steps: 1}}, easier)
+
+	harder, err := dao.FindProgressions(context.Background(), kneePushUp, model.DirectionHarder, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, pushUp, harder[0].ExerciseUuid)
+
+	_, err = dao.FindProgressions(context.Background(), pushUp, "sideways", 2, false)
+	assert.EqualError(t, err, `unknown direction "sideways"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,280 @@
+package dao
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	// registers the pure Go driver "sqlite", which needs no cgo
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaDDL creates the tables of db/schema.sql on SQLite.
+//
+//go:embed sqlite_schema.sql
+var sqliteSchemaDDL string
+
+// sqliteTimeFormat is how times are stored on SQLite: in UTC and without an
+// offset, like CURRENT_TIMESTAMP, so that they sort as they compare.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999"
+
+/*
+ * OpenSQLite opens the SQLite database at path with foreign keys enforced,
+ * creating the file and its schema when they do not exist. The DAOs of
+ * NewDaos run on it as they do on Postgres: statements SQLite does not
+ * understand are replaced with the variants in sqliteStatements. SQLite
+ * allows one writer at a time, so the database is used over a single
+ * connection.
+ */
+func OpenSQLite(path string) (*sqlx.DB, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma":      {"foreign_keys(1)", "busy_timeout(5000)"},
+		"_time_format": {"sqlite"},
+	}.Encode()
+	db, err := sqlx.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchemaDDL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating the schema in %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// isSQLite reports whether q runs its statements on SQLite.
+func isSQLite(q queryer) bool {
+	d, ok := q.(interface{ DriverName() string })
+	return ok && d.DriverName() == DriverSQLite
+}
+
+/*
+ * inDialect returns the statement and arguments to run on q. On SQLite the
+ * variant of the statement in sqliteStatements replaces it, if there is one;
+ * times are passed in sqliteTimeFormat and arrays as JSON arrays, which the
+ * variants read with json_each.
+ */
+func inDialect(q queryer, statement, query string, args []any) (string, []any) {
+	if !isSQLite(q) {
+		return query, args
+	}
+	if variant, ok := sqliteStatements[statement]; ok {
+		query = variant
+	}
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		converted[i] = sqliteArg(arg)
+	}
+	return query, converted
+}
+
+func sqliteArg(arg any) any {
+	switch v := arg.(type) {
+	case time.Time:
+		return v.UTC().Format(sqliteTimeFormat)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(sqliteTimeFormat)
+	case *pq.StringArray, *pq.Int64Array, *pq.Float64Array, *pq.BoolArray:
+		array, err := json.Marshal(v)
+		if err != nil {
+			return arg
+		}
+		return string(array)
+	}
+	return arg
+}
+
+/*
+ * dbTime scans a time computed by a statement. SQLite returns the times of
+ * columns as times, but those of expressions such as date(started_at) as
+ * text.
+ */
+type dbTime time.Time
+
+func (t *dbTime) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*t = dbTime(v)
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into a time", src)
+}
+
+func (t *dbTime) parse(value string) error {
+	for _, layout := range []string{sqliteTimeFormat, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*t = dbTime(parsed)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as a time", value)
+}
+
+/*
+ * sqliteStatements holds the SQLite variants of the statements written in
+ * the Postgres dialect that SQLite does not understand, under the names of
+ * the constants holding them:
+ *
+ *   - arrays are aggregated into the text form of Postgres arrays, which
+ *     pq.StringArray scans, and unnested with json_each
+ *   - DISTINCT ON becomes a ROW_NUMBER() window over the same order
+ *   - ILIKE becomes LIKE, which ignores the case of ASCII letters only
+ *   - casts to text and FOR UPDATE are dropped; SQLite has one writer
+ *   - enums are text, so muscle levels are compared by their position in
+ *     the list of levels rather than by themselves
+ */
+var sqliteStatements = map[string]string{
+	"getDailyVolumeDQL": `
+	SELECT date(s.started_at) AS at, SUM(ws.reps * ws.weight_kg) AS value
+	FROM   workout_session s
+	JOIN   workout_set ws ON ws.session_uuid = s.session_uuid
+	WHERE  s.user_uuid = $1 AND s.started_at >= $2 AND s.started_at < $3
+	AND    ws.reps IS NOT NULL AND ws.weight_kg IS NOT NULL
+	GROUP BY 1
+	ORDER BY 1`,
+
+	"createHeartRateDML": `
+	INSERT INTO cardio_heart_rate (session_uuid, offset_seconds, heart_rate)
+	SELECT $1, o.value, r.value
+	FROM   json_each($2) o
+	JOIN   json_each($3) r ON r.key = o.key`,
+
+	"findExByNameDQL": `
+	SELECT exercise_uuid, lower(exercise_name) AS exercise_name
+	FROM   exercise
+	WHERE  lower(exercise_name) IN (SELECT value FROM json_each($1))`,
+
+	"findExForUpdateDQL": `
+	SELECT exercise_uuid
+	FROM   exercise
+	WHERE  lower(exercise_name) = lower($1)
+	LIMIT  1`,
+
+	"getProfileDQL": sqliteSelectProfileDQL + `
+	WHERE  p.profile_uuid = $1
+	GROUP BY p.profile_uuid`,
+
+	"getDefaultProfileDQL": sqliteSelectProfileDQL + `
+	WHERE  p.user_uuid = $1 AND p.is_default
+	GROUP BY p.profile_uuid`,
+
+	"listProfilesDQL": sqliteSelectProfileDQL + `
+	WHERE  p.user_uuid IS NOT DISTINCT FROM $1
+	GROUP BY p.profile_uuid
+	ORDER BY p.is_default DESC, p.profile_name`,
+
+	"lockProfileDQL": `
+	SELECT user_uuid, created_by, created_at FROM equipment_profile WHERE profile_uuid = $1`,
+
+	"createProfileApparatusDML": `
+	INSERT INTO equipment_profile_apparatus (profile_uuid, apparatus_code)
+	SELECT $1, value FROM json_each($2)`,
+
+	"findAvailableExDQL": `
+	SELECT e.exercise_uuid, e.exercise_name, e.category_code,
+	       '{' || COALESCE(group_concat(ea.apparatus_code, ',' ORDER BY ea.apparatus_code), '') || '}' AS apparatus
+	FROM      exercise e
+	LEFT JOIN exercise_apparatus ea ON ea.exercise_uuid = e.exercise_uuid
+	WHERE     $2 = '' OR e.category_code = $2
+	GROUP BY  e.exercise_uuid
+	HAVING    MIN(ea.apparatus_code IS NULL OR ea.apparatus_code IN (
+		SELECT apparatus_code FROM equipment_profile_apparatus WHERE profile_uuid = $1))
+	ORDER BY  e.exercise_name`,
+
+	"listMeasurementsDQL": `
+	SELECT` + measurementColumns + `
+	FROM   body_measurement
+	WHERE  user_uuid = $1
+	AND    ($2 = '' OR measurement_kind = $2)
+	AND    ($3 = '' OR site = $3)
+	AND    measured_at >= $4 AND measured_at < $5
+	ORDER BY measured_at, measurement_uuid`,
+
+	"getMusHierarchyDQL": `
+	SELECT (SELECT muscle_level FROM muscle_type WHERE muscle_code = $1) AS parent_level,
+	       EXISTS (SELECT 1 FROM muscle_type WHERE parent_code = $2
+	               AND instr('region,group,muscle,head', muscle_level) <= instr('region,group,muscle,head', $3)) AS child_above`,
+
+	"findExByMuscleDQL": `
+	SELECT exercise_uuid, exercise_name, category_code, muscle_code, muscle_role
+	FROM (
+		SELECT e.exercise_uuid, e.exercise_name, e.category_code, em.muscle_code, em.muscle_role,
+		       ROW_NUMBER() OVER (PARTITION BY e.exercise_uuid ORDER BY em.muscle_role, a.distance) AS n
+		FROM   muscle_ancestry a
+		JOIN   exercise_muscle em ON em.muscle_code = a.muscle_code
+		JOIN   exercise e ON e.exercise_uuid = em.exercise_uuid
+		WHERE  a.ancestor_code = $1
+		AND    ($2 = '' OR em.muscle_role = $2)
+	) found
+	WHERE  n = 1
+	ORDER BY exercise_name`,
+
+	"listCandidatesDQL": `
+	SELECT e.exercise_uuid, e.exercise_name, e.category_code,
+	       '{' || COALESCE(group_concat(ea.apparatus_code, ',' ORDER BY ea.apparatus_code), '') || '}' AS apparatus
+	FROM      exercise e
+	LEFT JOIN exercise_apparatus ea ON ea.exercise_uuid = e.exercise_uuid
+	WHERE     $1 = '' OR e.category_code = $1
+	GROUP BY  e.exercise_uuid
+	ORDER BY  e.exercise_uuid`,
+
+	"listCandidateMusclesDQL": `
+	SELECT em.exercise_uuid, em.muscle_code, em.muscle_role,
+	       '{' || group_concat(a.ancestor_code, ',' ORDER BY a.distance) || '}' AS ancestors,
+	       COALESCE((SELECT CAST(pm.movement_pattern AS TEXT)
+	                 FROM   muscle_ancestry pa
+	                 JOIN   muscle_type pm ON pm.muscle_code = pa.ancestor_code
+	                 WHERE  pa.muscle_code = em.muscle_code AND pm.movement_pattern IS NOT NULL
+	                 ORDER BY pa.distance LIMIT 1), 'other') AS pattern
+	FROM   exercise_muscle em
+	JOIN   exercise e        ON e.exercise_uuid = em.exercise_uuid
+	JOIN   muscle_ancestry a ON a.muscle_code = em.muscle_code
+	WHERE  $1 = '' OR e.category_code = $1
+	GROUP BY em.exercise_uuid, em.muscle_code, em.muscle_role
+	ORDER BY em.exercise_uuid, em.muscle_code`,
+
+	"searchExercisesDQL": `
+	SELECT exercise_uuid, exercise_name, category_code, matched_by, match
+	FROM (
+		SELECT e.exercise_uuid,
+		       COALESCE(t.exercise_name, e.exercise_name) AS exercise_name,
+		       e.category_code, m.matched_by, m.match,
+		       lower(m.match) = lower($4) AS exact, m.rank,
+		       ROW_NUMBER() OVER (PARTITION BY e.exercise_uuid
+		                          ORDER BY lower(m.match) = lower($4) DESC, m.rank) AS n
+		FROM (
+			SELECT exercise_uuid, 'name' AS matched_by, exercise_name AS match, 0 AS rank
+			FROM   exercise WHERE exercise_name LIKE $1 ESCAPE '\'
+			UNION ALL
+			SELECT exercise_uuid, 'alias', alias, 1
+			FROM   exercise_alias WHERE alias LIKE $1 ESCAPE '\'
+			UNION ALL
+			SELECT exercise_uuid, 'translation', exercise_name, 2
+			FROM   exercise_translation WHERE exercise_name LIKE $1 ESCAPE '\'
+		) m
+		JOIN      exercise e ON e.exercise_uuid = m.exercise_uuid
+		LEFT JOIN exercise_translation t ON t.exercise_uuid = e.exercise_uuid AND t.locale = $2
+	) found
+	WHERE  n = 1
+	ORDER BY exact DESC, rank, exercise_name
+	LIMIT $3`,
+}
+
+const sqliteSelectProfileDQL string = `
+	SELECT p.profile_uuid, p.user_uuid, p.profile_name, p.is_default,
+	       p.created_by, p.created_at, p.updated_at,
+	       '{' || COALESCE(group_concat(a.apparatus_code, ',' ORDER BY a.apparatus_code), '') || '}' AS apparatus
+	FROM      equipment_profile p
+	LEFT JOIN equipment_profile_apparatus a ON a.profile_uuid = p.profile_uuid`
@@ -0,0 +1,337 @@
+-- The schema of db/schema.sql for SQLite, applied by OpenSQLite; keep the two in step.
+--
+-- SQLite has no UUID, enum or NUMERIC(p, s) types: UUIDs are stored as text, generated as version 4 UUIDs
+-- where Postgres uses uuid_generate_v4(), enums as text checked against their values and decimals as REAL.
+-- Timestamps are stored as text in UTC, "YYYY-MM-DD HH:MM:SS.fffffffff", so they sort as they compare.
+
+-- units a user reads loads, distances and lengths in; values are stored metric
+CREATE TABLE IF NOT EXISTS shred_user (
+  user_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  first_name VARCHAR(45) NOT NULL,
+  last_name VARCHAR(45) NOT NULL,
+  email VARCHAR(100) NOT NULL,
+  unit_system TEXT NOT NULL DEFAULT 'metric' CHECK (unit_system IN ('metric', 'imperial')),
+  load_increment REAL NULL, -- smallest step between loads in the unit system, e.g. 2.5 kg or 5 lb
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (user_uuid),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+
+-- levels of the muscle hierarchy from the top down, e.g. Legs > Quadriceps > Rectus femoris
+CREATE TABLE IF NOT EXISTS muscle_type (
+  muscle_code VARCHAR(45) NOT NULL,
+  muscle_name VARCHAR(45) NOT NULL,
+  muscle_description VARCHAR(2500) NULL, -- description of the muscle as markdown
+  muscle_group VARCHAR(45) NOT NULL,
+  muscle_level TEXT NOT NULL DEFAULT 'muscle' CHECK (muscle_level IN ('region', 'group', 'muscle', 'head')),
+  parent_code VARCHAR(45) NULL, -- the muscle one level up, NULL for regions
+  movement_pattern TEXT NULL CHECK (movement_pattern IN ('push', 'pull')), -- NULL to take the pattern of the muscle above
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (muscle_code),
+  CHECK (parent_code <> muscle_code),
+  FOREIGN KEY (parent_code) REFERENCES muscle_type(muscle_code),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+CREATE INDEX IF NOT EXISTS muscle_type_parent ON muscle_type (parent_code);
+
+-- every muscle paired with itself and each of its ancestors, to roll muscles up to their groups and regions
+CREATE VIEW IF NOT EXISTS muscle_ancestry (muscle_code, ancestor_code, distance) AS
+  WITH RECURSIVE up (muscle_code, ancestor_code, distance) AS (
+    SELECT muscle_code, muscle_code, 0 FROM muscle_type
+    UNION ALL
+    SELECT up.muscle_code, m.parent_code, up.distance + 1
+    FROM   up
+    JOIN   muscle_type m ON m.muscle_code = up.ancestor_code
+    WHERE  m.parent_code IS NOT NULL
+  )
+  SELECT muscle_code, ancestor_code, distance FROM up;
+
+CREATE TABLE IF NOT EXISTS category_type (
+  category_code VARCHAR(45) NOT NULL,
+  category_name VARCHAR(45) NOT NULL,
+  category_description VARCHAR(2500) NULL, -- description of the category as markdown
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (category_code),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+
+CREATE TABLE IF NOT EXISTS apparatus_type (
+  apparatus_code VARCHAR(45) NOT NULL,
+  apparatus_name VARCHAR(45) NOT NULL,
+  apparatus_description VARCHAR(2500) NULL, -- description of the apparatus as markdown
+  apparatus_group VARCHAR(45) NOT NULL DEFAULT 'Other', -- e.g. Free weights, Machines, Cardio equipment
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (apparatus_code),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+
+CREATE TABLE IF NOT EXISTS license (
+  license_short_name VARCHAR(45) NOT NULL,
+  license_full_name VARCHAR(45) NOT NULL,
+  url VARCHAR(250) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (license_short_name),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+
+CREATE TABLE IF NOT EXISTS exercise (
+  exercise_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  exercise_name VARCHAR(100) NOT NULL,
+  exercise_description VARCHAR(2500) NOT NULL,
+  instructions VARCHAR(2500),
+  cues VARCHAR(2500),
+  video_url VARCHAR(256),
+  category_code VARCHAR(45) NOT NULL,
+  license_short_name VARCHAR(45) NULL,
+  license_author VARCHAR(100) NULL,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (exercise_uuid),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid),
+  FOREIGN KEY (category_code) REFERENCES category_type(category_code),
+  FOREIGN KEY (license_short_name) REFERENCES license(license_short_name)
+);
+
+CREATE TABLE IF NOT EXISTS exercise_apparatus (
+  exercise_uuid TEXT NOT NULL,
+  apparatus_code VARCHAR(45) NOT NULL,
+  PRIMARY KEY (exercise_uuid, apparatus_code),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid),
+  FOREIGN KEY (apparatus_code) REFERENCES apparatus_type(apparatus_code)
+);
+
+CREATE TABLE IF NOT EXISTS exercise_muscle (
+  exercise_uuid TEXT NOT NULL,
+  muscle_code VARCHAR(45) NOT NULL,
+  muscle_role TEXT NOT NULL CHECK (muscle_role IN ('primary', 'secondary', 'stabilizer')),
+  PRIMARY KEY (exercise_uuid, muscle_code),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid),
+  FOREIGN KEY (muscle_code) REFERENCES muscle_type(muscle_code)
+);
+
+CREATE TABLE IF NOT EXISTS workout_session (
+  session_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  user_uuid TEXT NOT NULL,
+  session_name VARCHAR(100) NOT NULL,
+  started_at TIMESTAMP NOT NULL, -- UTC
+  duration_seconds INTEGER NULL,
+  notes VARCHAR(2500) NULL,
+  source VARCHAR(45) NOT NULL DEFAULT 'shred', -- application the session was logged in
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (session_uuid),
+  FOREIGN KEY (user_uuid) REFERENCES shred_user(user_uuid)
+);
+CREATE INDEX IF NOT EXISTS workout_session_user_started ON workout_session (user_uuid, started_at);
+
+CREATE TABLE IF NOT EXISTS workout_set (
+  session_uuid TEXT NOT NULL,
+  set_number INTEGER NOT NULL, -- order of the set within the session
+  exercise_uuid TEXT NOT NULL,
+  reps INTEGER NULL,
+  weight_kg REAL NULL,
+  distance_m REAL NULL,
+  duration_seconds INTEGER NULL,
+  rpe REAL NULL,
+  notes VARCHAR(2500) NULL,
+  entered_weight_unit VARCHAR(2) NULL, -- unit the load was logged in, kg or lb
+  entered_distance_unit VARCHAR(2) NULL, -- unit the distance was logged in, km, mi or m
+  PRIMARY KEY (session_uuid, set_number),
+  CHECK (entered_weight_unit IN ('kg', 'lb')),
+  CHECK (entered_distance_unit IN ('km', 'mi', 'm')),
+  FOREIGN KEY (session_uuid) REFERENCES workout_session(session_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid)
+);
+
+-- maps exercise names used by other tracking apps to the catalog
+CREATE TABLE IF NOT EXISTS exercise_name_mapping (
+  source VARCHAR(45) NOT NULL,
+  external_name VARCHAR(100) NOT NULL, -- lower case
+  exercise_uuid TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by TEXT NOT NULL,
+  PRIMARY KEY (source, external_name),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid),
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+
+-- summary of an activity recorded on a device, stored as a session with one cardio set
+CREATE TABLE IF NOT EXISTS cardio_activity (
+  session_uuid TEXT NOT NULL,
+  sport VARCHAR(45) NOT NULL,
+  distance_m REAL NOT NULL,
+  duration_seconds INTEGER NOT NULL,
+  pace_seconds_per_km REAL NULL,
+  elevation_gain_m REAL NULL,
+  elevation_loss_m REAL NULL,
+  avg_heart_rate INTEGER NULL,
+  max_heart_rate INTEGER NULL,
+  PRIMARY KEY (session_uuid),
+  FOREIGN KEY (session_uuid) REFERENCES workout_session(session_uuid) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cardio_heart_rate (
+  session_uuid TEXT NOT NULL,
+  offset_seconds INTEGER NOT NULL, -- seconds since the start of the activity
+  heart_rate INTEGER NOT NULL,
+  PRIMARY KEY (session_uuid, offset_seconds),
+  FOREIGN KEY (session_uuid) REFERENCES cardio_activity(session_uuid) ON DELETE CASCADE
+);
+
+-- typed edges between exercises, read as "exercise <relation> related", e.g. knee push-up regression_of push-up
+CREATE TABLE IF NOT EXISTS exercise_relationship (
+  exercise_uuid TEXT NOT NULL,
+  related_uuid TEXT NOT NULL,
+  relation TEXT NOT NULL CHECK (relation IN ('variation_of', 'progression_of', 'regression_of', 'alternative_to')),
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (exercise_uuid, related_uuid, relation),
+  CHECK (exercise_uuid <> related_uuid),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (related_uuid) REFERENCES exercise(exercise_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+CREATE INDEX IF NOT EXISTS exercise_relationship_related ON exercise_relationship (related_uuid);
+
+-- other names an exercise is known by, e.g. RDL for Romanian Deadlift
+CREATE TABLE IF NOT EXISTS exercise_alias (
+  exercise_uuid TEXT NOT NULL,
+  alias VARCHAR(100) NOT NULL,
+  locale VARCHAR(35) NULL, -- language of the alias, NULL when it is used across languages
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (exercise_uuid, alias),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS exercise_alias_lower ON exercise_alias (lower(alias));
+
+-- exercise texts in languages other than the default language of the catalog (en)
+CREATE TABLE IF NOT EXISTS exercise_translation (
+  exercise_uuid TEXT NOT NULL,
+  locale VARCHAR(35) NOT NULL, -- BCP 47 tag such as de or pt-BR
+  exercise_name VARCHAR(100) NOT NULL,
+  exercise_description VARCHAR(2500) NULL,
+  instructions VARCHAR(2500) NULL,
+  cues VARCHAR(2500) NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (exercise_uuid, locale),
+  FOREIGN KEY (exercise_uuid) REFERENCES exercise(exercise_uuid) ON DELETE CASCADE
+);
+
+-- names and descriptions of muscles, categories and apparatus in other languages
+CREATE TABLE IF NOT EXISTS reference_translation (
+  reference_kind VARCHAR(20) NOT NULL, -- muscle, category or apparatus
+  reference_code VARCHAR(45) NOT NULL,
+  locale VARCHAR(35) NOT NULL,
+  reference_name VARCHAR(100) NOT NULL,
+  reference_description VARCHAR(2500) NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (reference_kind, reference_code, locale)
+);
+
+-- the equipment available to a user, e.g. at home, or at a gym location when user_uuid is NULL
+CREATE TABLE IF NOT EXISTS equipment_profile (
+  profile_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  user_uuid TEXT NULL,
+  profile_name VARCHAR(100) NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT FALSE,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (profile_uuid),
+  CHECK (user_uuid IS NOT NULL OR NOT is_default),
+  FOREIGN KEY (user_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES shred_user(user_uuid)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS equipment_profile_name ON equipment_profile (COALESCE(user_uuid, ''), lower(profile_name));
+CREATE UNIQUE INDEX IF NOT EXISTS equipment_profile_default ON equipment_profile (user_uuid) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS equipment_profile_apparatus (
+  profile_uuid TEXT NOT NULL,
+  apparatus_code VARCHAR(45) NOT NULL,
+  PRIMARY KEY (profile_uuid, apparatus_code),
+  FOREIGN KEY (profile_uuid) REFERENCES equipment_profile(profile_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (apparatus_code) REFERENCES apparatus_type(apparatus_code)
+);
+
+-- body measurements of a user, stored in kg for bodyweight, percent for body fat and cm for circumferences
+CREATE TABLE IF NOT EXISTS body_measurement (
+  measurement_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  user_uuid TEXT NOT NULL,
+  measurement_kind TEXT NOT NULL CHECK (measurement_kind IN ('bodyweight', 'body_fat', 'circumference')),
+  site VARCHAR(45) NULL, -- where a circumference is measured, e.g. WAIST
+  measured_value REAL NOT NULL,
+  measured_at TIMESTAMP NOT NULL, -- UTC
+  notes VARCHAR(2500) NULL,
+  entered_unit VARCHAR(2) NULL, -- unit the value was given in, e.g. lb
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (measurement_uuid),
+  CHECK ((measurement_kind = 'circumference') = (site IS NOT NULL)),
+  CHECK (measured_value > 0),
+  FOREIGN KEY (user_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS body_measurement_user_measured ON body_measurement (user_uuid, measurement_kind, measured_at);
+
+-- workouts a user plans to do; session_uuid is the logged session that completed the workout
+CREATE TABLE IF NOT EXISTS scheduled_workout (
+  schedule_uuid TEXT NOT NULL DEFAULT (lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || substr('89ab', 1 + (random() & 3), 1) ||
+    substr(lower(hex(randomblob(2))), 2) || '-' || lower(hex(randomblob(6)))),
+  user_uuid TEXT NOT NULL,
+  session_name VARCHAR(100) NOT NULL,
+  scheduled_at TIMESTAMP NOT NULL, -- UTC
+  duration_minutes INTEGER NULL,
+  notes VARCHAR(2500) NULL,
+  session_uuid TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (schedule_uuid),
+  CHECK (duration_minutes > 0),
+  FOREIGN KEY (user_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (session_uuid) REFERENCES workout_session(session_uuid) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS scheduled_workout_user_scheduled ON scheduled_workout (user_uuid, scheduled_at);
+
+-- secret token in the URL of the iCalendar feed of a user
+CREATE TABLE IF NOT EXISTS calendar_feed (
+  user_uuid TEXT NOT NULL,
+  feed_token VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_uuid),
+  UNIQUE (feed_token),
+  FOREIGN KEY (user_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE
+);
+
+-- clients on the roster of a coach; both are users
+CREATE TABLE IF NOT EXISTS coach_client (
+  coach_uuid TEXT NOT NULL,
+  client_uuid TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (coach_uuid, client_uuid),
+  CHECK (coach_uuid <> client_uuid),
+  FOREIGN KEY (coach_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE,
+  FOREIGN KEY (client_uuid) REFERENCES shred_user(user_uuid) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS coach_client_client ON coach_client (client_uuid);
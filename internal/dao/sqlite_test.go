@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/dao/pgtest"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSQLite_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shred.db")
+	db, err := OpenSQLite(path)
+	require.NoError(t, err)
+	userUuid := pgtest.CreateUser(t, db, "Admin")
+	_, err = NewDaos(db).Categories.CreateCategory(context.Background(), &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "strength", CategoryName: "Strength"}, CreatedBy: userUuid})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = OpenSQLite(path)
+	require.NoError(t, err, "the schema is created only where it is missing")
+	defer db.Close()
+	category, err := NewDaos(db).Categories.GetCategoryByCode(context.Background(), "STRENGTH")
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, category.CreatedBy)
+}
+
+func TestOpenSQLite_ForeignKeys(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "shred.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewDaos(db).Categories.CreateCategory(context.Background(), &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "strength", CategoryName: "Strength"}, CreatedBy: uuid.New()})
+	assert.True(t, isForeignKeyViolation(err), "got %v", err)
+}
+
+func TestOpenSQLite_MuscleLevels(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "shred.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	daos := NewDaos(db)
+	userUuid := pgtest.CreateUser(t, db, "Admin")
+	ctx := context.Background()
+
+	legs, quads := "LEGS", "QUADS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs},
+		{MuscleCode: "RECTUS", MuscleName: "Rectus femoris", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &quads},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus, CreatedBy: userUuid})
+		require.NoError(t, err)
+	}
+
+	// muscle sorts after group but is below it, and head before muscle
+	assert.NoError(t, daos.Muscles.UpdateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "QUADS", MuscleName: "Quads", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs}}))
+	assert.ErrorIs(t, daos.Muscles.UpdateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "QUADS", MuscleName: "Quads", MuscleLevel: model.MuscleLevelHead, ParentCode: &legs}}), ErrInvalidHierarchy)
+}
+
+func TestInDialect(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "shred.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	at := time.Date(2025, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	query, args := inDialect(db, "findExByNameDQL", findExByNameDQL,
+		[]any{&pq.StringArray{"squat", "bench"}, at, &at, (*time.Time)(nil), 5})
+	assert.Equal(t, sqliteStatements["findExByNameDQL"], query)
+	assert.Equal(t, []any{`["squat","bench"]`, "2025-03-01 08:30:00", "2025-03-01 08:30:00", nil, 5}, args)
+
+	query, args = inDialect(db, "createLicenseDML", createLicenseDML, []any{at})
+	assert.Equal(t, createLicenseDML, query, "statements SQLite understands are kept")
+	assert.Equal(t, []any{"2025-03-01 08:30:00"}, args)
+}
@@ -0,0 +1,277 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// TranslationDao provides access to exercise aliases and to the texts of
+// exercises, muscles, categories and apparatus in other languages.
+type TranslationDao struct {
+	db *sqlx.DB
+}
+
+type TranslationDaoInterface interface {
+	ListLocales(ctx context.Context) ([]string, error)
+	GetExerciseTranslations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseTranslation, error)
+	SaveExerciseTranslation(ctx context.Context, exUuid uuid.UUID, tr *model.ExerciseTranslation) error
+	DeleteExerciseTranslation(ctx context.Context, exUuid uuid.UUID, locale string) error
+	GetAliases(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseAlias, error)
+	AddAlias(ctx context.Context, exUuid uuid.UUID, alias *model.ExerciseAlias) error
+	DeleteAlias(ctx context.Context, exUuid uuid.UUID, alias string) error
+	GetReferenceTranslations(ctx context.Context, kind model.ReferenceKind, locale string) (map[string]model.ReferenceTranslation, error)
+	SaveReferenceTranslation(ctx context.Context, tr *model.ReferenceTranslation) error
+	SearchExercises(ctx context.Context, query, locale string, limit int) ([]model.ExerciseSearchResult, error)
+}
+
+// Ensure TranslationDao implements TranslationDaoInterface
+var _ TranslationDaoInterface = (*TranslationDao)(nil)
+
+// NewTranslationDao creates a new instance of TranslationDao.
+func NewTranslationDao(db *sqlx.DB) *TranslationDao {
+	return &TranslationDao{db: db}
+}
+
+// ListLocales returns every locale something has been translated into.
+const listLocalesDQL string = `
+	SELECT locale FROM exercise_translation
+	UNION
+	SELECT locale FROM reference_translation
+	ORDER BY locale`
+
+func (dao *TranslationDao) ListLocales(ctx context.Context) (_ []string, err error) {
+	defer observe(ctx, "TranslationDao", "ListLocales", time.Now(), &err)
+	locales := []string{}
+	if err := selectContext(ctx, dao.db, "listLocalesDQL", &locales, listLocalesDQL); err != nil {
+		return nil, err
+	}
+	return locales, nil
+}
+
+const getExTranslationsDQL string = `
+	SELECT locale, exercise_name, COALESCE(exercise_description, '') AS exercise_description,
+	       COALESCE(instructions, '') AS instructions, COALESCE(cues, '') AS cues
+	FROM   exercise_translation
+	WHERE  exercise_uuid = $1
+	ORDER BY locale`
+
+func (dao *TranslationDao) GetExerciseTranslations(ctx context.Context, exUuid uuid.UUID) (_ []model.ExerciseTranslation, err error) {
+	defer observe(ctx, "TranslationDao", "GetExerciseTranslations", time.Now(), &err)
+	translations := []model.ExerciseTranslation{}
+	if err := selectContext(ctx, dao.db, "getExTranslationsDQL", &translations, getExTranslationsDQL, exUuid); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+const saveExTranslationDML string = `
+	INSERT INTO exercise_translation (
+		exercise_uuid, locale, exercise_name, exercise_description, instructions, cues
+	) VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (exercise_uuid, locale) DO UPDATE SET
+		exercise_name = EXCLUDED.exercise_name,
+		exercise_description = EXCLUDED.exercise_description,
+		instructions = EXCLUDED.instructions,
+		cues = EXCLUDED.cues,
+		updated_at = CURRENT_TIMESTAMP`
+
+func (dao *TranslationDao) SaveExerciseTranslation(ctx context.Context, exUuid uuid.UUID, tr *model.ExerciseTranslation) (err error) {
+	defer observe(ctx, "TranslationDao", "SaveExerciseTranslation", time.Now(), &err)
+	_, err = execContext(ctx, dao.db, "saveExTranslationDML", saveExTranslationDML,
+		exUuid, tr.Locale, tr.ExerciseName, nullIfEmpty(tr.Description),
+		nullIfEmpty(tr.Instructions), nullIfEmpty(tr.Cues))
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("exercise with uuid %s %w", exUuid, ErrNotFound)
+	}
+	return err
+}
+
+const deleteExTranslationDML string = `
+	DELETE FROM exercise_translation WHERE exercise_uuid = $1 AND locale = $2`
+
+func (dao *TranslationDao) DeleteExerciseTranslation(ctx context.Context, exUuid uuid.UUID, locale string) (err error) {
+	defer observe(ctx, "TranslationDao", "DeleteExerciseTranslation", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteExTranslationDML", deleteExTranslationDML, exUuid, locale)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s translation of exercise %s %w", locale, exUuid, ErrNotFound)
+	}
+	return nil
+}
+
+const getAliasesDQL string = `
+	SELECT alias, COALESCE(locale, '') AS locale
+	FROM   exercise_alias
+	WHERE  exercise_uuid = $1
+	ORDER BY alias`
+
+func (dao *TranslationDao) GetAliases(ctx context.Context, exUuid uuid.UUID) (_ []model.ExerciseAlias, err error) {
+	defer observe(ctx, "TranslationDao", "GetAliases", time.Now(), &err)
+	aliases := []model.ExerciseAlias{}
+	if err := selectContext(ctx, dao.db, "getAliasesDQL", &aliases, getAliasesDQL, exUuid); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+const addAliasDML string = `
+	INSERT INTO exercise_alias (exercise_uuid, alias, locale)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (exercise_uuid, alias) DO NOTHING`
+
+func (dao *TranslationDao) AddAlias(ctx context.Context, exUuid uuid.UUID, alias *model.ExerciseAlias) (err error) {
+	defer observe(ctx, "TranslationDao", "AddAlias", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "addAliasDML", addAliasDML, exUuid, alias.Alias, nullIfEmpty(alias.Locale))
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("exercise with uuid %s %w", exUuid, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("alias '%s' of exercise %s %w", alias.Alias, exUuid, ErrConflict)
+	}
+	return nil
+}
+
+const deleteAliasDML string = `
+	DELETE FROM exercise_alias WHERE exercise_uuid = $1 AND alias = $2`
+
+func (dao *TranslationDao) DeleteAlias(ctx context.Context, exUuid uuid.UUID, alias string) (err error) {
+	defer observe(ctx, "TranslationDao", "DeleteAlias", time.Now(), &err)
+	result, err := execContext(ctx, dao.db, "deleteAliasDML", deleteAliasDML, exUuid, alias)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("alias '%s' of exercise %s %w", alias, exUuid, ErrNotFound)
+	}
+	return nil
+}
+
+// GetReferenceTranslations returns the translations of one kind of
+// reference into locale, keyed by code.
+const getRefTranslationsDQL string = `
+	SELECT reference_kind, reference_code, locale, reference_name,
+	       COALESCE(reference_description, '') AS reference_description
+	FROM   reference_translation
+	WHERE  reference_kind = $1 AND locale = $2`
+
+func (dao *TranslationDao) GetReferenceTranslations(ctx context.Context, kind model.ReferenceKind, locale string) (_ map[string]model.ReferenceTranslation, err error) {
+	defer observe(ctx, "TranslationDao", "GetReferenceTranslations", time.Now(), &err)
+	var rows []model.ReferenceTranslation
+	if err := selectContext(ctx, dao.db, "getRefTranslationsDQL", &rows, getRefTranslationsDQL, kind, locale); err != nil {
+		return nil, err
+	}
+
+	translations := make(map[string]model.ReferenceTranslation, len(rows))
+	for _, tr := range rows {
+		translations[tr.Code] = tr
+	}
+	return translations, nil
+}
+
+// referenceTables maps each kind of reference to its table and code column.
+var referenceTables = map[model.ReferenceKind][2]string{
+	model.ReferenceMuscle:    {"muscle_type", "muscle_code"},
+	model.ReferenceCategory:  {"category_type", "category_code"},
+	model.ReferenceApparatus: {"apparatus_type", "apparatus_code"},
+}
+
+const saveRefTranslationDML string = `
+	INSERT INTO reference_translation (
+		reference_kind, reference_code, locale, reference_name, reference_description
+	) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (reference_kind, reference_code, locale) DO UPDATE SET
+		reference_name = EXCLUDED.reference_name,
+		reference_description = EXCLUDED.reference_description,
+		updated_at = CURRENT_TIMESTAMP`
+
+// SaveReferenceTranslation stores the translation of an existing muscle,
+// category or apparatus. As the translations of all kinds share a table the
+// reference is looked up first instead of relying on a foreign key.
+func (dao *TranslationDao) SaveReferenceTranslation(ctx context.Context, tr *model.ReferenceTranslation) (err error) {
+	defer observe(ctx, "TranslationDao", "SaveReferenceTranslation", time.Now(), &err)
+	table, ok := referenceTables[tr.Kind]
+	if !ok {
+		return fmt.Errorf("unknown reference kind %q", tr.Kind)
+	}
+	tr.Code = strings.ToUpper(tr.Code)
+
+	var exists bool
+	existsDQL := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s = $1)", table[0], table[1])
+	if err := scanContext(ctx, dao.db, "existsDQL", existsDQL, []any{tr.Code}, &exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s with code %s %w", tr.Kind, tr.Code, ErrNotFound)
+	}
+
+	_, err = execContext(ctx, dao.db, "saveRefTranslationDML", saveRefTranslationDML,
+		tr.Kind, tr.Code, tr.Locale, tr.Name, nullIfEmpty(tr.Description))
+	return err
+}
+
+// SearchExercises finds exercises whose name, alias or translated name
+// contains $1, ignoring case. Exact matches come first, then matches on the
+// name before aliases and translations. Names are returned in locale $2 when
+// translated.
+const searchExercisesDQL string = `
+	SELECT exercise_uuid, exercise_name, category_code, matched_by, match
+	FROM (
+		SELECT DISTINCT ON (e.exercise_uuid)
+		       e.exercise_uuid,
+		       COALESCE(t.exercise_name, e.exercise_name) AS exercise_name,
+		       e.category_code, m.matched_by, m.match,
+		       lower(m.match) = lower($4) AS exact, m.rank
+		FROM (
+			SELECT exercise_uuid, 'name' AS matched_by, exercise_name AS match, 0 AS rank
+			FROM   exercise WHERE exercise_name ILIKE $1
+			UNION ALL
+			SELECT exercise_uuid, 'alias', alias, 1
+			FROM   exercise_alias WHERE alias ILIKE $1
+			UNION ALL
+			SELECT exercise_uuid, 'translation', exercise_name, 2
+			FROM   exercise_translation WHERE exercise_name ILIKE $1
+		) m
+		JOIN      exercise e ON e.exercise_uuid = m.exercise_uuid
+		LEFT JOIN exercise_translation t ON t.exercise_uuid = e.exercise_uuid AND t.locale = $2
+		ORDER BY e.exercise_uuid, lower(m.match) = lower($4) DESC, m.rank
+	) found
+	ORDER BY exact DESC, rank, exercise_name
+	LIMIT $3`
+
+func (dao *TranslationDao) SearchExercises(ctx context.Context, query, locale string, limit int) (_ []model.ExerciseSearchResult, err error) {
+	defer observe(ctx, "TranslationDao", "SearchExercises", time.Now(), &err)
+	query = strings.TrimSpace(query)
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+
+	results := []model.ExerciseSearchResult{}
+	if err := selectContext(ctx, dao.db, "searchExercisesDQL", &results, searchExercisesDQL, pattern, locale, limit, query); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
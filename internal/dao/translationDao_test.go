@@ -0,0 +1,195 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListLocales(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT locale FROM exercise_translation UNION SELECT locale FROM reference_translation").
+		WillReturnRows(sqlmock.NewRows([]string{"locale"}).AddRow("de").AddRow("pt-BR"))
+
+	locales, err := dao.ListLocales(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de", "pt-BR"}, locales)
+}
+
+func TestSaveExerciseTranslation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	tr := &model.ExerciseTranslation{Locale: "de", ExerciseName: "Rumänisches Kreuzheben", Cues: "Hüfte nach hinten"}
+	mock.ExpectExec("INSERT INTO exercise_translation .* ON CONFLICT \\(exercise_uuid, locale\\) DO UPDATE").
+		WithArgs(exUuid, "de", "Rumänisches Kreuzheben", nil, nil, "Hüfte nach hinten").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.SaveExerciseTranslation(context.Background(), exUuid, tr))
+
+	mock.ExpectExec("INSERT INTO exercise_translation").
+		WillReturnError(&pq.Error{Code: "23503"})
+	err = dao.SaveExerciseTranslation(context.Background(), exUuid, tr)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExerciseTranslations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("SELECT locale, exercise_name, .* FROM exercise_translation WHERE exercise_uuid = \\$1").
+		WithArgs(exUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"locale", "exercise_name", "exercise_description", "instructions", "cues"}).
+			AddRow("de", "Rumänisches Kreuzheben", "", "", ""))
+
+	translations, err := dao.GetExerciseTranslations(context.Background(), exUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseTranslation{{Locale: "de", ExerciseName: "Rumänisches Kreuzheben"}}, translations)
+}
+
+func TestDeleteExerciseTranslation_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectExec("DELETE FROM exercise_translation").
+		WithArgs(exUuid, "fr").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = dao.DeleteExerciseTranslation(context.Background(), exUuid, "fr")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAddAlias(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	alias := &model.ExerciseAlias{Alias: "RDL"}
+	mock.ExpectExec("INSERT INTO exercise_alias .* ON CONFLICT \\(exercise_uuid, alias\\) DO NOTHING").
+		WithArgs(exUuid, "RDL", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.AddAlias(context.Background(), exUuid, alias))
+
+	mock.ExpectExec("INSERT INTO exercise_alias").
+		WithArgs(exUuid, "RDL", nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, dao.AddAlias(context.Background(), exUuid, alias), ErrConflict)
+
+	mock.ExpectExec("INSERT INTO exercise_alias").
+		WillReturnError(&pq.Error{Code: "23503"})
+	assert.ErrorIs(t, dao.AddAlias(context.Background(), exUuid, alias), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAlias(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectExec("DELETE FROM exercise_alias").
+		WithArgs(exUuid, "RDL").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.DeleteAlias(context.Background(), exUuid, "RDL"))
+
+	mock.ExpectExec("DELETE FROM exercise_alias").
+		WithArgs(exUuid, "SLDL").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, dao.DeleteAlias(context.Background(), exUuid, "SLDL"), ErrNotFound)
+}
+
+func TestGetReferenceTranslations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT reference_kind, reference_code, .* FROM reference_translation").
+		WithArgs(model.ReferenceMuscle, "de").
+		WillReturnRows(sqlmock.NewRows([]string{"reference_kind", "reference_code", "locale", "reference_name", "reference_description"}).
+			AddRow("muscle", "GLUTES", "de", "Gesäßmuskel", ""))
+
+	translations, err := dao.GetReferenceTranslations(context.Background(), model.ReferenceMuscle, "de")
+	assert.NoError(t, err)
+	assert.Equal(t, "Gesäßmuskel", translations["GLUTES"].Name)
+}
+
+func TestSaveReferenceTranslation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	tr := &model.ReferenceTranslation{Kind: model.ReferenceCategory, Code: "strength", Locale: "de", Name: "Kraft"}
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM category_type WHERE category_code = \\$1\\)").
+		WithArgs("STRENGTH").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec("INSERT INTO reference_translation .* ON CONFLICT").
+		WithArgs(model.ReferenceCategory, "STRENGTH", "de", "Kraft", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, dao.SaveReferenceTranslation(context.Background(), tr))
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("STRENGTH").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	assert.ErrorIs(t, dao.SaveReferenceTranslation(context.Background(), tr), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchExercises(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewTranslationDao(sqlx.NewDb(db, "postgres"))
+
+	exUuid := uuid.New()
+	mock.ExpectQuery("FROM exercise_alias WHERE alias ILIKE \\$1").
+		WithArgs(`%100\%\_RDL%`, "de", 20, `100%_RDL`).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "category_code", "matched_by", "match"}))
+	results, err := dao.SearchExercises(context.Background(), `100%_RDL`, "de", 20)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	mock.ExpectQuery("FROM exercise_alias WHERE alias ILIKE \\$1").
+		WithArgs(`%rdl%`, "de", 20, "rdl").
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_uuid", "exercise_name", "category_code", "matched_by", "match"}).
+			AddRow(exUuid, "Rumänisches Kreuzheben", "STRENGTH", "alias", "RDL"))
+
+	results, err = dao.SearchExercises(context.Background(), " rdl ", "de", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExerciseSearchResult{{
+		ExerciseUuid: exUuid, ExerciseName: "Rumänisches Kreuzheben", CategoryCode: "STRENGTH",
+		MatchedBy: model.MatchedByAlias, Match: "RDL",
+	}}, results)
+}
@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// UnitOfWork runs several DAO calls in one transaction.
+type UnitOfWork struct {
+	db   *sqlx.DB
+	opts *sql.TxOptions
+}
+
+type UnitOfWorkInterface interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Ensure UnitOfWork implements UnitOfWorkInterface
+var _ UnitOfWorkInterface = (*UnitOfWork)(nil)
+
+// NewUnitOfWork creates a new instance of UnitOfWork running serializable
+// transactions.
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db, opts: &sql.TxOptions{Isolation: sql.LevelSerializable}}
+}
+
+const (
+	// MaxAttempts bounds how often a unit of work is tried when it keeps
+	// failing to serialize.
+	MaxAttempts = 3
+	// retryBackoff is the wait before the second attempt, growing with
+	// each attempt.
+	retryBackoff = 20 * time.Millisecond
+)
+
+/*
+ * Do runs fn in a transaction. DAO methods called with the context given to
+ * fn run in that transaction, which is committed when fn returns nil and
+ * rolled back when it returns an error or panics. A transaction that fails
+ * to serialize with concurrent ones, or deadlocks, is rolled back and fn is
+ * run again, up to MaxAttempts times, so fn must not have effects outside
+ * the database. Called within a unit of work, Do joins it.
+ */
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	for attempt := 1; ; attempt++ {
+		err := transaction(ctx, u.db, u.opts, func(ctx context.Context, _ *sqlx.Tx) error {
+			return fn(ctx)
+		})
+		if attempt == MaxAttempts || !isRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+	}
+}
+
+type txKey struct{}
+
+func txFromContext(ctx context.Context) *sqlx.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx
+}
+
+/*
+ * transaction runs fn in the transaction of ctx, or else in a new one
+ * started with opts and committed when fn succeeds. DAO methods writing
+ * several rows run in it, so they are atomic on their own and take part in
+ * a unit of work when they are called within one.
+ */
+func transaction(ctx context.Context, db *sqlx.DB, opts *sql.TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	if tx := txFromContext(ctx); tx != nil {
+		return fn(ctx, tx)
+	}
+	tx, err := db.BeginTxx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// inTransaction runs statements meant for the database in the transaction
+// of ctx, if there is one.
+func inTransaction(ctx context.Context, q queryer) queryer {
+	if _, ok := q.(*sqlx.DB); ok {
+		if tx := txFromContext(ctx); tx != nil {
+			return tx
+		}
+	}
+	return q
+}
@@ -0,0 +1,232 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDb := sqlx.NewDb(db, "postgres")
+	uow := NewUnitOfWork(sqlxDb)
+	exDao := NewExerciseDao(sqlxDb)
+	exUuid := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO exercise_muscle").
+		WithArgs(exUuid, "QUADS", model.MuscleRolePrimary).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO exercise_apparatus").
+		WithArgs(exUuid, "BARBELL").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "quads", MuscleRole: model.MuscleRolePrimary}}); err != nil {
+			return err
+		}
+		return exDao.AddApparatus(ctx, exUuid, []string{"barbell"})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDb := sqlx.NewDb(db, "postgres")
+	uow := NewUnitOfWork(sqlxDb)
+	exDao := NewExerciseDao(sqlxDb)
+	exUuid := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO exercise_muscle").
+		WithArgs(exUuid, "QUADS", model.MuscleRolePrimary).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO exercise_apparatus").
+		WithArgs(exUuid, "TRAPEZE").
+		WillReturnError(&pq.Error{Code: foreignKeyViolation})
+	mock.ExpectRollback()
+
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := exDao.AddMuscles(ctx, exUuid, []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}}); err != nil {
+			return err
+		}
+		return exDao.AddApparatus(ctx, exUuid, []string{"TRAPEZE"})
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Panic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.Panics(t, func() {
+		_ = uow.Do(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Retry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	attempts := 0
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return &pq.Error{Code: serializationFailure}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RetryCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&pq.Error{Code: deadlockDetected})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	attempts := 0
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_GiveUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	for range MaxAttempts {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	attempts := 0
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return &pq.Error{Code: serializationFailure}
+	})
+	var pqErr *pq.Error
+	assert.True(t, errors.As(err, &pqErr))
+	assert.Equal(t, MaxAttempts, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_NoRetry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	attempts := 0
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return ErrConflict
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Canceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	uow := NewUnitOfWork(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err = uow.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return &pq.Error{Code: serializationFailure}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_Nested(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDb := sqlx.NewDb(db, "postgres")
+	uow := NewUnitOfWork(sqlxDb)
+	workoutDao := NewWorkoutDao(sqlxDb)
+	userUuid, sessionUuid := uuid.New(), uuid.New()
+	startedAt := time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+	req := model.WorkoutSessionRequest{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt}}
+
+	// CreateSessions and the inner unit of work join the outer transaction.
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WithArgs(userUuid, "Legs", startedAt, nil, "", model.SourceShred).
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(sessionUuid, time.Now(), time.Now()))
+	mock.ExpectCommit()
+
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		return uow.Do(ctx, func(ctx context.Context) error {
+			_, err := workoutDao.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{req})
+			return err
+		})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// UserDao provides access to the settings of users.
+type UserDao struct {
+	db *sqlx.DB
+}
+
+type UserDaoInterface interface {
+	GetPreferences(ctx context.Context, userUuid uuid.UUID) (*model.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userUuid uuid.UUID, prefs *model.UserPreferences) (*model.UserPreferences, error)
+}
+
+// Ensure UserDao implements UserDaoInterface
+var _ UserDaoInterface = (*UserDao)(nil)
+
+// NewUserDao creates a new instance of UserDao.
+func NewUserDao(db *sqlx.DB) *UserDao {
+	return &UserDao{db: db}
+}
+
+const (
+	getPreferencesDQL string = `
+	SELECT unit_system, load_increment
+	FROM   shred_user
+	WHERE  user_uuid = $1`
+
+	updatePreferencesDML string = `
+	UPDATE shred_user
+	SET    unit_system = $1, load_increment = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE  user_uuid = $3
+	RETURNING unit_system, load_increment`
+)
+
+func (dao *UserDao) GetPreferences(ctx context.Context, userUuid uuid.UUID) (_ *model.UserPreferences, err error) {
+	defer observe(ctx, "UserDao", "GetPreferences", time.Now(), &err)
+	var prefs model.UserPreferences
+	if err := getContext(ctx, dao.db, "getPreferencesDQL", &prefs, getPreferencesDQL, userUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %s %w", userUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (dao *UserDao) UpdatePreferences(ctx context.Context, userUuid uuid.UUID, prefs *model.UserPreferences) (_ *model.UserPreferences, err error) {
+	defer observe(ctx, "UserDao", "UpdatePreferences", time.Now(), &err)
+	var updated model.UserPreferences
+	if err := getContext(ctx, dao.db, "updatePreferencesDML", &updated, updatePreferencesDML,
+		prefs.UnitSystem, prefs.LoadIncrement, userUuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %s %w", userUuid, ErrNotFound)
+		}
+		return nil, err
+	}
+	return &updated, nil
+}
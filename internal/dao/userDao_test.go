@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	mock.ExpectQuery("SELECT unit_system, load_increment FROM shred_user WHERE user_uuid = \\$1").
+		WithArgs(userUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"unit_system", "load_increment"}).AddRow("imperial", "2.50"))
+
+	prefs, err := dao.GetPreferences(context.Background(), userUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, units.Imperial, prefs.UnitSystem)
+	assert.Equal(t, 2.5, *prefs.LoadIncrement)
+
+	mock.ExpectQuery("SELECT unit_system").
+		WithArgs(userUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"unit_system", "load_increment"}))
+	_, err = dao.GetPreferences(context.Background(), userUuid)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewUserDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	prefs := &model.UserPreferences{UnitSystem: units.Metric}
+	mock.ExpectQuery("UPDATE shred_user SET unit_system = \\$1, load_increment = \\$2").
+		WithArgs(units.Metric, nil, userUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"unit_system", "load_increment"}).AddRow("metric", nil))
+
+	updated, err := dao.UpdatePreferences(context.Background(), userUuid, prefs)
+	assert.NoError(t, err)
+	assert.Equal(t, units.Metric, updated.UnitSystem)
+	assert.Nil(t, updated.LoadIncrement)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// WorkoutDao provides access to logged workout sessions and their sets.
+type WorkoutDao struct {
+	db *sqlx.DB
+}
+
+type WorkoutDaoInterface interface {
+	CreateSessions(ctx context.Context, userUuid uuid.UUID, sessions []model.WorkoutSessionRequest) ([]model.WorkoutSession, error)
+	ListSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.WorkoutSession, error)
+	ListSessionStarts(ctx context.Context, userUuid uuid.UUID, source string) ([]time.Time, error)
+}
+
+// Ensure WorkoutDao implements WorkoutDaoInterface
+var _ WorkoutDaoInterface = (*WorkoutDao)(nil)
+
+// NewWorkoutDao creates a new instance of WorkoutDao.
+func NewWorkoutDao(db *sqlx.DB) *WorkoutDao {
+	return &WorkoutDao{db: db}
+}
+
+const createSessionDML string = `
+	INSERT INTO workout_session (
+		user_uuid, session_name, started_at, duration_seconds, notes, source
+	) VALUES ($1, $2, $3, $4, $5, $6) RETURNING session_uuid, created_at, updated_at`
+
+const createSetDML string = `
+	INSERT INTO workout_set (
+		session_uuid, set_number, exercise_uuid, reps, weight_kg,
+		distance_m, duration_seconds, rpe, notes, entered_weight_unit, entered_distance_unit
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+// CreateSessions inserts the sessions with all their sets in one transaction,
+// or in the unit of work it is called in.
+// Sets without a set number are numbered in the order given.
+func (dao *WorkoutDao) CreateSessions(ctx context.Context, userUuid uuid.UUID, sessions []model.WorkoutSessionRequest) (_ []model.WorkoutSession, err error) {
+	defer observe(ctx, "WorkoutDao", "CreateSessions", time.Now(), &err)
+	created := make([]model.WorkoutSession, 0, len(sessions))
+	err = transaction(ctx, dao.db, nil, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, req := range sessions {
+			session, err := insertSession(ctx, tx, userUuid, req)
+			if err != nil {
+				return err
+			}
+			created = append(created, *session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range created {
+		countLogged(&created[i])
+	}
+	return created, nil
+}
+
+// countLogged counts a stored session and its sets in the domain metrics.
+func countLogged(session *model.WorkoutSession) {
+	metrics.WorkoutsLogged.WithLabelValues(session.Source).Inc()
+	metrics.SetsLogged.Add(float64(len(session.Sets)))
+}
+
+// insertSession inserts a session and its sets within tx.
+func insertSession(ctx context.Context, tx *sqlx.Tx, userUuid uuid.UUID, req model.WorkoutSessionRequest) (*model.WorkoutSession, error) {
+	session := &model.WorkoutSession{
+		UserUuid:             userUuid,
+		WorkoutSessionFields: req.WorkoutSessionFields,
+		Sets:                 make([]model.WorkoutSet, len(req.Sets)),
+	}
+	if session.Source == "" {
+		session.Source = model.SourceShred
+	}
+	session.StartedAt = session.StartedAt.UTC()
+
+	if err := scanContext(ctx, tx, "createSessionDML", createSessionDML, []any{
+		userUuid, session.SessionName, session.StartedAt, session.DurationSeconds, session.Notes, session.Source,
+	}, &session.SessionUuid, &session.CreatedAt, &session.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	for i, set := range req.Sets {
+		if set.SetNumber == 0 {
+			set.SetNumber = i + 1
+		}
+		if _, err := execContext(ctx, tx, "createSetDML", createSetDML,
+			session.SessionUuid, set.SetNumber, set.ExerciseUuid, set.Reps, set.WeightKg,
+			set.DistanceM, set.DurationSeconds, set.Rpe, set.Notes,
+			nullIfEmpty(string(set.EnteredWeightUnit)), nullIfEmpty(string(set.EnteredDistanceUnit))); err != nil {
+			return nil, err
+		}
+		session.Sets[i] = set
+	}
+	return session, nil
+}
+
+// ListSessions returns the sessions of a user started in [from, to) with
+// their sets, oldest first.
+const listSessionsDQL string = `
+	SELECT session_uuid, user_uuid, session_name, started_at, duration_seconds,
+	       COALESCE(notes, '') AS notes, source, created_at, updated_at
+	FROM   workout_session
+	WHERE  user_uuid = $1 AND started_at >= $2 AND started_at < $3
+	ORDER BY started_at, session_uuid`
+
+const listSessionSetsDQL string = `
+	SELECT ws.session_uuid, ws.set_number, ws.exercise_uuid, ws.reps, ws.weight_kg,
+	       ws.distance_m, ws.duration_seconds, ws.rpe, COALESCE(ws.notes, '') AS notes,
+	       COALESCE(ws.entered_weight_unit, '') AS entered_weight_unit,
+	       COALESCE(ws.entered_distance_unit, '') AS entered_distance_unit
+	FROM   workout_set ws
+	JOIN   workout_session s ON s.session_uuid = ws.session_uuid
+	WHERE  s.user_uuid = $1 AND s.started_at >= $2 AND s.started_at < $3
+	ORDER BY ws.session_uuid, ws.set_number`
+
+func (dao *WorkoutDao) ListSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) (_ []model.WorkoutSession, err error) {
+	defer observe(ctx, "WorkoutDao", "ListSessions", time.Now(), &err)
+	sessions := []model.WorkoutSession{}
+	if err := selectContext(ctx, dao.db, "listSessionsDQL", &sessions, listSessionsDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+
+	var sets []struct {
+		SessionUuid uuid.UUID `db:"session_uuid"`
+		model.WorkoutSet
+	}
+	if err := selectContext(ctx, dao.db, "listSessionSetsDQL", &sets, listSessionSetsDQL, userUuid, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+
+	bySession := make(map[uuid.UUID]*model.WorkoutSession, len(sessions))
+	for i := range sessions {
+		sessions[i].Sets = []model.WorkoutSet{}
+		bySession[sessions[i].SessionUuid] = &sessions[i]
+	}
+	for _, set := range sets {
+		if session, ok := bySession[set.SessionUuid]; ok {
+			session.Sets = append(session.Sets, set.WorkoutSet)
+		}
+	}
+
+	return sessions, nil
+}
+
+// ListSessionStarts returns the start times of the sessions a user imported
+// from source, used to skip sessions that were imported before.
+const listSessionStartsDQL string = `
+	SELECT started_at
+	FROM   workout_session
+	WHERE  user_uuid = $1 AND source = $2`
+
+func (dao *WorkoutDao) ListSessionStarts(ctx context.Context, userUuid uuid.UUID, source string) (_ []time.Time, err error) {
+	defer observe(ctx, "WorkoutDao", "ListSessionStarts", time.Now(), &err)
+	var starts []time.Time
+	if err := selectContext(ctx, dao.db, "listSessionStartsDQL", &starts, listSessionStartsDQL, userUuid, source); err != nil {
+		return nil, err
+	}
+	return starts, nil
+}
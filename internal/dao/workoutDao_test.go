@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(n int) *int           { return &n }
+func floatPtr(f float64) *float64 { return &f }
+
+func TestCreateSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, sessionUuid, exUuid := uuid.New(), uuid.New(), uuid.New()
+	startedAt := time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+	req := model.WorkoutSessionRequest{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt},
+		Sets: []model.WorkoutSet{
+			{ExerciseUuid: exUuid, Reps: intPtr(5), WeightKg: floatPtr(100)},
+			{ExerciseUuid: exUuid, Reps: intPtr(5), WeightKg: floatPtr(104.33), EnteredWeightUnit: units.Lb},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WithArgs(userUuid, "Legs", startedAt, nil, "", model.SourceShred).
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(sessionUuid, time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO workout_set").
+		WithArgs(sessionUuid, 1, exUuid, 5, 100.0, nil, nil, nil, "", nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO workout_set").
+		WithArgs(sessionUuid, 2, exUuid, 5, 104.33, nil, nil, nil, "", "lb", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	logged := testutil.ToFloat64(metrics.WorkoutsLogged.WithLabelValues(model.SourceShred))
+	sets := testutil.ToFloat64(metrics.SetsLogged)
+	sessions, err := dao.CreateSessions(context.Background(), userUuid, []model.WorkoutSessionRequest{req})
+	assert.NoError(t, err)
+	assert.Equal(t, logged+1, testutil.ToFloat64(metrics.WorkoutsLogged.WithLabelValues(model.SourceShred)))
+	assert.Equal(t, sets+2, testutil.ToFloat64(metrics.SetsLogged))
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, sessionUuid, sessions[0].SessionUuid)
+	assert.Equal(t, model.SourceShred, sessions[0].Source)
+	assert.Equal(t, 2, sessions[0].Sets[1].SetNumber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSessions_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workout_session").
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "created_at", "updated_at"}).AddRow(uuid.New(), time.Now(), time.Now()))
+	mock.ExpectExec("INSERT INTO workout_set").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+
+	req := model.WorkoutSessionRequest{Sets: []model.WorkoutSet{{ExerciseUuid: uuid.New()}}}
+	sessions, err := dao.CreateSessions(context.Background(), uuid.New(), []model.WorkoutSessionRequest{req})
+	assert.Error(t, err)
+	assert.Nil(t, sessions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid, legs, arms, exUuid := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectQuery("SELECT session_uuid, user_uuid, session_name, .* FROM workout_session WHERE user_uuid = \\$1 AND started_at >= \\$2 AND started_at < \\$3").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "user_uuid", "session_name", "started_at", "duration_seconds", "notes", "source", "created_at", "updated_at"}).
+			AddRow(legs, userUuid, "Legs", from, 3600, "", "strong", from, from).
+			AddRow(arms, userUuid, "Arms", from.AddDate(0, 0, 1), nil, "", "shred", from, from))
+	mock.ExpectQuery("SELECT ws.session_uuid, ws.set_number, .* FROM workout_set ws").
+		WithArgs(userUuid, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"session_uuid", "set_number", "exercise_uuid", "reps", "weight_kg", "distance_m", "duration_seconds", "rpe", "notes", "entered_weight_unit", "entered_distance_unit"}).
+			AddRow(legs, 1, exUuid, 5, "100.00", nil, nil, "8.5", "", "kg", "").
+			AddRow(legs, 2, exUuid, 5, "102.06", nil, nil, nil, "last set", "lb", ""))
+
+	sessions, err := dao.ListSessions(context.Background(), userUuid, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, 3600, *sessions[0].DurationSeconds)
+	assert.Len(t, sessions[0].Sets, 2)
+	assert.Equal(t, 102.06, *sessions[0].Sets[1].WeightKg)
+	assert.Equal(t, units.Lb, sessions[0].Sets[1].EnteredWeightUnit)
+	assert.Equal(t, 8.5, *sessions[0].Sets[0].Rpe)
+	assert.Equal(t, "last set", sessions[0].Sets[1].Notes)
+	assert.Empty(t, sessions[1].Sets)
+	assert.Nil(t, sessions[1].DurationSeconds)
+}
+
+func TestListSessions_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery("SELECT session_uuid").WillReturnError(sqlmock.ErrCancelled)
+
+	sessions, err := dao.ListSessions(context.Background(), uuid.New(), time.Now(), time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, sessions)
+}
+
+func TestListSessionStarts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dao := NewWorkoutDao(sqlx.NewDb(db, "postgres"))
+
+	userUuid := uuid.New()
+	start := time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT started_at FROM workout_session WHERE user_uuid = \\$1 AND source = \\$2").
+		WithArgs(userUuid, "strong").
+		WillReturnRows(sqlmock.NewRows([]string{"started_at"}).AddRow(start))
+
+	starts, err := dao.ListSessionStarts(context.Background(), userUuid, "strong")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{start}, starts)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/adherence"
+)
+
+type AdherenceHandler struct {
+	reporter adherence.ReporterInterface
+}
+
+func NewAdherenceHandler(reporter adherence.ReporterInterface) *AdherenceHandler {
+	return &AdherenceHandler{reporter: reporter}
+}
+
+/*
+ * GetAdherence reports how well the user in the path kept to their
+ * schedule over the number of weeks in the weeks query parameter, 12 by
+ * default, with weeks starting on Monday in the time zone tz.
+ */
+func (h AdherenceHandler) GetAdherence(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	weeks, now, ok := adherenceQuery(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := h.reporter.Report(ctx.Request.Context(), userUuid, weeks, now)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GetRosterAdherence reports the adherence of every client of the coach in
+// the path, taking the same query parameters as GetAdherence.
+func (h AdherenceHandler) GetRosterAdherence(ctx *gin.Context) {
+	coachUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	weeks, now, ok := adherenceQuery(ctx)
+	if !ok {
+		return
+	}
+
+	roster, err := h.reporter.Roster(ctx.Request.Context(), coachUuid, weeks, now)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, roster)
+}
+
+// adherenceQuery reads the weeks and tz query parameters, returning now in
+// the time zone.
+func adherenceQuery(ctx *gin.Context) (int, time.Time, bool) {
+	weeks := adherence.DefaultWeeks
+	if value := ctx.Query("weeks"); value != "" {
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 1 || w > adherence.MaxWeeks {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("weeks must be between 1 and %d", adherence.MaxWeeks)})
+			return 0, time.Time{}, false
+		}
+		weeks = w
+	}
+	loc, err := timeZone(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, time.Time{}, false
+	}
+	return weeks, time.Now().In(loc), true
+}
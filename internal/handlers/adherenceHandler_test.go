@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/adherence"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReporter is a mock implementation of the adherence.ReporterInterface
+type MockReporter struct {
+	mock.Mock
+}
+
+func (m *MockReporter) Report(ctx context.Context, userUuid uuid.UUID, weeks int, now time.Time) (*model.AdherenceReport, error) {
+	args := m.Called(userUuid, weeks, now.Location().String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AdherenceReport), args.Error(1)
+}
+
+func (m *MockReporter) Roster(ctx context.Context, coachUuid uuid.UUID, weeks int, now time.Time) (*model.RosterAdherence, error) {
+	args := m.Called(coachUuid, weeks, now.Location().String())
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RosterAdherence), args.Error(1)
+}
+
+func newAdherenceRouter(reporter *MockReporter) *gin.Engine {
+	handler := NewAdherenceHandler(reporter)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/adherence", handler.GetAdherence)
+	router.GET("/coaches/:uuid/adherence", handler.GetRosterAdherence)
+	return router
+}
+
+func TestGetAdherence(t *testing.T) {
+	reporter := new(MockReporter)
+	router := newAdherenceRouter(reporter)
+
+	userUuid := uuid.New()
+	reporter.On("Report", userUuid, adherence.DefaultWeeks, "UTC").
+		Return(&model.AdherenceReport{UserUuid: userUuid, CurrentStreak: 3, MissedDays: []string{"2025-03-05"}}, nil)
+	reporter.On("Report", userUuid, 4, "America/New_York").
+		Return(&model.AdherenceReport{UserUuid: userUuid}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/adherence", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"currentStreak":3`)
+	assert.Contains(t, w.Body.String(), `"missedDays":["2025-03-05"]`)
+
+	r, _ = http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/adherence?weeks=4&tz=America/New_York", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	reporter.AssertExpectations(t)
+}
+
+func TestGetAdherence_Invalid(t *testing.T) {
+	router := newAdherenceRouter(new(MockReporter))
+
+	for _, query := range []string{"weeks=0", "weeks=53", "weeks=many", "tz=Nowhere"} {
+		t.Run(query, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/adherence?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestGetRosterAdherence(t *testing.T) {
+	reporter := new(MockReporter)
+	router := newAdherenceRouter(reporter)
+
+	coachUuid, failingUuid := uuid.New(), uuid.New()
+	score := 87.5
+	reporter.On("Roster", coachUuid, adherence.DefaultWeeks, "UTC").Return(&model.RosterAdherence{
+		CoachUuid: coachUuid, OnStreak: 1,
+		Windows: []model.AdherenceWindow{{Days: 7, Due: 8, Completed: 7, Missed: 1, Score: &score}},
+		Clients: []model.ClientAdherence{{RosterClient: model.RosterClient{FirstName: "John"}}},
+	}, nil)
+	reporter.On("Roster", failingUuid, adherence.DefaultWeeks, "UTC").Return(nil, errors.New("connection refused"))
+
+	r, _ := http.NewRequest(http.MethodGet, "/coaches/"+coachUuid.String()+"/adherence", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"score":87.5`)
+	assert.Contains(t, w.Body.String(), `"firstName":"John"`)
+
+	r, _ = http.NewRequest(http.MethodGet, "/coaches/"+failingUuid.String()+"/adherence", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	reporter.AssertExpectations(t)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/analytics"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+)
+
+// defaultAnalyticsWindow is how far back time series go without a from date.
+const defaultAnalyticsWindow = 90 * 24 * time.Hour
+
+type AnalyticsHandler struct {
+	analyzer analytics.AnalyzerInterface
+	users    dao.UserDaoInterface
+}
+
+func NewAnalyticsHandler(analyzer analytics.AnalyzerInterface, users dao.UserDaoInterface) *AnalyticsHandler {
+	return &AnalyticsHandler{analyzer: analyzer, users: users}
+}
+
+// GetTimeSeries returns the training volume and body measurements of the
+// user in the path between the optional from and to query parameters, which
+// default to the last 90 days. metrics is a comma separated subset of
+// analytics.Metrics and window the trend window in days. Weights and lengths
+// are in the units the user prefers.
+func (h AnalyticsHandler) GetTimeSeries(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := analytics.Options{TrendWindowDays: analytics.DefaultTrendWindowDays}
+	if opts.From, opts.To, err = timeRange(ctx, time.Now(), defaultAnalyticsWindow); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if value := ctx.Query("window"); value != "" {
+		opts.TrendWindowDays, err = strconv.Atoi(value)
+		if err != nil || opts.TrendWindowDays < 1 || opts.TrendWindowDays > analytics.MaxTrendWindowDays {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be between 1 and %d days", analytics.MaxTrendWindowDays)})
+			return
+		}
+	}
+	if value := ctx.Query("metrics"); value != "" {
+		opts.Metrics = strings.Split(value, ",")
+	}
+
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, userUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	series, err := h.analyzer.TimeSeries(ctx.Request.Context(), userUuid, opts)
+	switch {
+	case errors.Is(err, analytics.ErrUnknownMetric):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		series.Convert(converter)
+		ctx.JSON(http.StatusOK, series)
+	}
+}
+
+// GetMuscleVolume returns the training volume of the user in the path
+// between the optional from and to query parameters, which default to the
+// last 90 days, rolled up to the muscles of the level query parameter:
+// region, the default, group, muscle or head. Volumes are in the weight unit
+// the user prefers.
+func (h AnalyticsHandler) GetMuscleVolume(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := timeRange(ctx, time.Now(), defaultAnalyticsWindow)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	level := model.MuscleLevel(ctx.DefaultQuery("level", string(model.MuscleLevelRegion)))
+
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, userUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	volumes, err := h.analyzer.MuscleVolume(ctx.Request.Context(), userUuid, level, from, to)
+	switch {
+	case errors.Is(err, analytics.ErrUnknownLevel):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		volumes.Convert(converter)
+		ctx.JSON(http.StatusOK, volumes)
+	}
+}
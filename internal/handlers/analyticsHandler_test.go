@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/analytics"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAnalyzer is a mock implementation of the analytics.AnalyzerInterface
+type MockAnalyzer struct {
+	mock.Mock
+}
+
+func (m *MockAnalyzer) TimeSeries(ctx context.Context, userUuid uuid.UUID, opts analytics.Options) (*model.TimeSeries, error) {
+	args := m.Called(userUuid, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TimeSeries), args.Error(1)
+}
+
+func (m *MockAnalyzer) MuscleVolume(ctx context.Context, userUuid uuid.UUID, level model.MuscleLevel, from, to time.Time) (*model.MuscleVolumes, error) {
+	args := m.Called(userUuid, level, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MuscleVolumes), args.Error(1)
+}
+
+func newAnalyticsRouter(analyzer *MockAnalyzer) *gin.Engine {
+	handler := NewAnalyticsHandler(analyzer, usersPreferring(units.Imperial))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/analytics/timeseries", handler.GetTimeSeries)
+	router.GET("/users/:uuid/analytics/muscle-volume", handler.GetMuscleVolume)
+	return router
+}
+
+func TestGetTimeSeries(t *testing.T) {
+	analyzer := new(MockAnalyzer)
+	router := newAnalyticsRouter(analyzer)
+
+	userUuid := uuid.New()
+	opts := analytics.Options{
+		From:            time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:              time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		TrendWindowDays: 14,
+		Metrics:         []string{"volume", "bodyweight"},
+	}
+	analyzer.On("TimeSeries", userUuid, opts).Return(&model.TimeSeries{UserUuid: userUuid, Series: []model.Series{
+		{Metric: "bodyweight", Unit: "kg", Points: []model.SeriesPoint{{Value: 82.4}}, Trend: []model.SeriesPoint{{Value: 82.1}}},
+	}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+
+		"/analytics/timeseries?from=2025-01-01T00:00:00Z&to=2025-04-01T00:00:00Z&window=14&metrics=volume,bodyweight", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"metric":"bodyweight","unit":"lb","points":[{"at":"0001-01-01T00:00:00Z","value":181.66}]`)
+	analyzer.AssertExpectations(t)
+}
+
+func TestGetTimeSeries_Errors(t *testing.T) {
+	analyzer := new(MockAnalyzer)
+	router := newAnalyticsRouter(analyzer)
+
+	analyzer.On("TimeSeries", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w %q", analytics.ErrUnknownMetric, "steps"))
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"window=0", http.StatusBadRequest},
+		{"window=week", http.StatusBadRequest},
+		{"from=yesterday", http.StatusBadRequest},
+		{"metrics=steps", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/analytics/timeseries?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tt.want, w.Code, tt.query)
+	}
+	analyzer.AssertNumberOfCalls(t, "TimeSeries", 1)
+}
+
+func TestGetMuscleVolume(t *testing.T) {
+	analyzer := new(MockAnalyzer)
+	router := newAnalyticsRouter(analyzer)
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	analyzer.On("MuscleVolume", userUuid, model.MuscleLevelGroup, from, to).Return(&model.MuscleVolumes{
+		UserUuid: userUuid, Level: model.MuscleLevelGroup, Unit: "kg", Muscles: []model.MuscleVolume{
+			{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, Sets: 12, Volume: 1000},
+		}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+
+		"/analytics/muscle-volume?from=2025-01-01T00:00:00Z&to=2025-04-01T00:00:00Z&level=group", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"unit":"lb","muscles":[{"muscleCode":"QUADS","muscleName":"Quadriceps","muscleLevel":"group","sets":12,"volume":2204.62}]`)
+	analyzer.AssertExpectations(t)
+}
+
+func TestGetMuscleVolume_Errors(t *testing.T) {
+	analyzer := new(MockAnalyzer)
+	router := newAnalyticsRouter(analyzer)
+
+	analyzer.On("MuscleVolume", mock.Anything, model.MuscleLevel("limb"), mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w %q", analytics.ErrUnknownLevel, "limb"))
+	analyzer.On("MuscleVolume", mock.Anything, model.MuscleLevelRegion, mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused"))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/users/nobody/analytics/muscle-volume", http.StatusBadRequest},
+		{"/users/" + uuid.NewString() + "/analytics/muscle-volume?from=yesterday", http.StatusBadRequest},
+		{"/users/" + uuid.NewString() + "/analytics/muscle-volume?level=limb", http.StatusBadRequest},
+		{"/users/" + uuid.NewString() + "/analytics/muscle-volume", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tt.want, w.Code, tt.path)
+	}
+	analyzer.AssertNumberOfCalls(t, "MuscleVolume", 2)
+}
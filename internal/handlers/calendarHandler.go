@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/calendar"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// defaultScheduleWindow is how far back and ahead scheduled workouts are
+// listed without from and to dates.
+const defaultScheduleWindow = 28 * 24 * time.Hour
+
+type CalendarHandler struct {
+	dao       dao.CalendarDaoInterface
+	scheduler calendar.SchedulerInterface
+}
+
+func NewCalendarHandler(dao dao.CalendarDaoInterface, scheduler calendar.SchedulerInterface) *CalendarHandler {
+	return &CalendarHandler{dao: dao, scheduler: scheduler}
+}
+
+// CreateScheduledWorkout schedules a workout for the user in the path.
+func (h CalendarHandler) CreateScheduledWorkout(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fields, ok := bindScheduledWorkout(ctx)
+	if !ok {
+		return
+	}
+
+	workout, err := h.dao.CreateScheduledWorkout(ctx.Request.Context(), userUuid, fields)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusCreated, workout)
+	}
+}
+
+// GetScheduledWorkouts lists the workouts a user scheduled between the
+// optional from and to query parameters, by default the four weeks before
+// and after now, with whether they were done.
+func (h CalendarHandler) GetScheduledWorkouts(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	from, to, err := timeRange(ctx, now.Add(defaultScheduleWindow), 2*defaultScheduleWindow)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workouts, err := h.scheduler.Schedule(ctx.Request.Context(), userUuid, from, to, now)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, workouts)
+}
+
+func (h CalendarHandler) GetScheduledWorkout(ctx *gin.Context) {
+	scheduleUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workout, err := h.dao.GetScheduledWorkout(ctx.Request.Context(), scheduleUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusOK, workout)
+	}
+}
+
+// UpdateScheduledWorkout moves or renames a scheduled workout, or links it
+// to the session that completed it.
+func (h CalendarHandler) UpdateScheduledWorkout(ctx *gin.Context) {
+	scheduleUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fields, ok := bindScheduledWorkout(ctx)
+	if !ok {
+		return
+	}
+
+	workout, err := h.dao.UpdateScheduledWorkout(ctx.Request.Context(), scheduleUuid, fields)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusOK, workout)
+	}
+}
+
+func (h CalendarHandler) DeleteScheduledWorkout(ctx *gin.Context) {
+	scheduleUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.dao.DeleteScheduledWorkout(ctx.Request.Context(), scheduleUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+/*
+ * GetCalendar lays out the scheduled workouts and logged sessions of a user
+ * by day. The view query parameter is month, the default, or week; date is
+ * a day in it, by default today, and tz the IANA time zone days are in, by
+ * default UTC.
+ */
+func (h CalendarHandler) GetCalendar(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	view := model.CalendarView(ctx.DefaultQuery("view", string(model.CalendarMonth)))
+	if !view.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("view must be %s or %s", model.CalendarMonth, model.CalendarWeek)})
+		return
+	}
+	loc, err := timeZone(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	date := now.In(loc)
+	if value := ctx.Query("date"); value != "" {
+		if date, err = time.ParseInLocation(time.DateOnly, value, loc); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid date: %v", err)})
+			return
+		}
+	}
+
+	cal, err := h.scheduler.Calendar(ctx.Request.Context(), userUuid, view, date, now)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, cal)
+}
+
+// GetFeed returns the secret path of the calendar feed of a user.
+func (h CalendarHandler) GetFeed(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.dao.GetFeed(ctx.Request.Context(), userUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		feed.Path = calendar.FeedPath(feed.Token)
+		ctx.JSON(http.StatusOK, feed)
+	}
+}
+
+// CreateFeed gives a user a calendar feed under a new secret path; the path
+// of an earlier feed stops working.
+func (h CalendarHandler) CreateFeed(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.scheduler.CreateFeed(ctx.Request.Context(), userUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusCreated, feed)
+	}
+}
+
+func (h CalendarHandler) DeleteFeed(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.dao.DeleteFeed(ctx.Request.Context(), userUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// ServeFeed serves the iCalendar feed at /calendar/<token>.ics. The token is
+// the only credential, so unknown tokens are not told apart from other
+// missing pages.
+func (h CalendarHandler) ServeFeed(ctx *gin.Context) {
+	token, ok := strings.CutSuffix(ctx.Param("feed"), ".ics")
+	if !ok || token == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	err := h.scheduler.WriteFeed(ctx.Request.Context(), token, time.Now().UTC(), &buf)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.Status(http.StatusNotFound)
+	case err != nil:
+		logging.FromContext(ctx.Request.Context()).ErrorContext(ctx.Request.Context(), "serving calendar feed failed", "error", err)
+		ctx.Status(http.StatusInternalServerError)
+	default:
+		ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+	}
+}
+
+// respond writes workout with its status.
+func (h CalendarHandler) respond(ctx *gin.Context, status int, workout *model.ScheduledWorkout) {
+	if err := h.scheduler.Resolve(ctx.Request.Context(), workout, time.Now().UTC()); err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(status, workout)
+}
+
+// timeZone reads the IANA time zone in the tz query parameter, UTC by
+// default.
+func timeZone(ctx *gin.Context) (*time.Location, error) {
+	loc, err := time.LoadLocation(ctx.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz: %w", err)
+	}
+	return loc, nil
+}
+
+func bindScheduledWorkout(ctx *gin.Context) (*model.ScheduledWorkoutFields, bool) {
+	var fields model.ScheduledWorkoutFields
+	if err := ctx.ShouldBindJSON(&fields); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err := fields.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &fields, true
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCalendarDao is a mock implementation of the CalendarDaoInterface
+type MockCalendarDao struct {
+	mock.Mock
+}
+
+func (m *MockCalendarDao) CreateScheduledWorkout(ctx context.Context, userUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error) {
+	args := m.Called(userUuid, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScheduledWorkout), args.Error(1)
+}
+
+func (m *MockCalendarDao) ListScheduledWorkouts(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.ScheduledWorkout, error) {
+	args := m.Called(userUuid, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ScheduledWorkout), args.Error(1)
+}
+
+func (m *MockCalendarDao) GetScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) (*model.ScheduledWorkout, error) {
+	args := m.Called(scheduleUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScheduledWorkout), args.Error(1)
+}
+
+func (m *MockCalendarDao) UpdateScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID, fields *model.ScheduledWorkoutFields) (*model.ScheduledWorkout, error) {
+	args := m.Called(scheduleUuid, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScheduledWorkout), args.Error(1)
+}
+
+func (m *MockCalendarDao) DeleteScheduledWorkout(ctx context.Context, scheduleUuid uuid.UUID) error {
+	args := m.Called(scheduleUuid)
+	return args.Error(0)
+}
+
+func (m *MockCalendarDao) ListCalendarSessions(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CalendarSession, error) {
+	args := m.Called(userUuid, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CalendarSession), args.Error(1)
+}
+
+func (m *MockCalendarDao) GetFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarDao) SaveFeed(ctx context.Context, userUuid uuid.UUID, token string) (*model.CalendarFeed, error) {
+	args := m.Called(userUuid, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarDao) DeleteFeed(ctx context.Context, userUuid uuid.UUID) error {
+	args := m.Called(userUuid)
+	return args.Error(0)
+}
+
+func (m *MockCalendarDao) GetFeedUser(ctx context.Context, token string) (uuid.UUID, error) {
+	args := m.Called(token)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+// MockScheduler is a mock implementation of the calendar.SchedulerInterface
+type MockScheduler struct {
+	mock.Mock
+}
+
+func (m *MockScheduler) Schedule(ctx context.Context, userUuid uuid.UUID, from, to, now time.Time) ([]model.ScheduledWorkout, error) {
+	args := m.Called(userUuid, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ScheduledWorkout), args.Error(1)
+}
+
+func (m *MockScheduler) Resolve(ctx context.Context, workout *model.ScheduledWorkout, now time.Time) error {
+	args := m.Called(workout.ScheduleUuid)
+	workout.Status = model.SchedulePlanned
+	return args.Error(0)
+}
+
+func (m *MockScheduler) Calendar(ctx context.Context, userUuid uuid.UUID, view model.CalendarView, date, now time.Time) (*model.Calendar, error) {
+	args := m.Called(userUuid, view, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Calendar), args.Error(1)
+}
+
+func (m *MockScheduler) CreateFeed(ctx context.Context, userUuid uuid.UUID) (*model.CalendarFeed, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CalendarFeed), args.Error(1)
+}
+
+func (m *MockScheduler) WriteFeed(ctx context.Context, token string, now time.Time, w io.Writer) error {
+	args := m.Called(token)
+	if args.Error(0) == nil {
+		io.WriteString(w, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+	}
+	return args.Error(0)
+}
+
+func newCalendarRouter(calendarDao *MockCalendarDao, scheduler *MockScheduler) *gin.Engine {
+	handler := NewCalendarHandler(calendarDao, scheduler)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/scheduled-workouts", handler.GetScheduledWorkouts)
+	router.POST("/users/:uuid/scheduled-workouts", handler.CreateScheduledWorkout)
+	router.GET("/scheduled-workouts/:uuid", handler.GetScheduledWorkout)
+	router.PUT("/scheduled-workouts/:uuid", handler.UpdateScheduledWorkout)
+	router.DELETE("/scheduled-workouts/:uuid", handler.DeleteScheduledWorkout)
+	router.GET("/users/:uuid/calendar", handler.GetCalendar)
+	router.GET("/users/:uuid/calendar-feed", handler.GetFeed)
+	router.POST("/users/:uuid/calendar-feed", handler.CreateFeed)
+	router.DELETE("/users/:uuid/calendar-feed", handler.DeleteFeed)
+	router.GET("/calendar/:feed", handler.ServeFeed)
+	return router
+}
+
+func TestCreateScheduledWorkout(t *testing.T) {
+	calendarDao, scheduler := new(MockCalendarDao), new(MockScheduler)
+	router := newCalendarRouter(calendarDao, scheduler)
+
+	userUuid, scheduleUuid := uuid.New(), uuid.New()
+	at := time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC)
+	fields := model.ScheduledWorkoutFields{SessionName: "Legs", ScheduledAt: at}
+	calendarDao.On("CreateScheduledWorkout", userUuid, &fields).
+		Return(&model.ScheduledWorkout{ScheduleUuid: scheduleUuid, UserUuid: userUuid, ScheduledWorkoutFields: fields}, nil)
+	scheduler.On("Resolve", scheduleUuid).Return(nil)
+
+	body := `{"sessionName":" Legs ","scheduledAt":"2025-03-03T18:00:00+01:00"}`
+	r, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/scheduled-workouts", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"scheduledAt":"2025-03-03T17:00:00Z"`)
+	assert.Contains(t, w.Body.String(), `"status":"planned"`)
+	calendarDao.AssertExpectations(t)
+	scheduler.AssertExpectations(t)
+}
+
+func TestCreateScheduledWorkout_Invalid(t *testing.T) {
+	router := newCalendarRouter(new(MockCalendarDao), new(MockScheduler))
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no name", `{"scheduledAt":"2025-03-03T17:00:00Z"}`},
+		{"no time", `{"sessionName":"Legs"}`},
+		{"negative duration", `{"sessionName":"Legs","scheduledAt":"2025-03-03T17:00:00Z","durationMinutes":-5}`},
+		{"two days", `{"sessionName":"Legs","scheduledAt":"2025-03-03T17:00:00Z","durationMinutes":2880}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/scheduled-workouts", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestGetScheduledWorkouts(t *testing.T) {
+	calendarDao, scheduler := new(MockCalendarDao), new(MockScheduler)
+	router := newCalendarRouter(calendarDao, scheduler)
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	scheduler.On("Schedule", userUuid, from, to).Return([]model.ScheduledWorkout{
+		{ScheduledWorkoutFields: model.ScheduledWorkoutFields{SessionName: "Legs"}, Status: model.ScheduleMissed},
+	}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/scheduled-workouts?from=2025-03-01&to=2025-04-01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"missed"`)
+	scheduler.AssertExpectations(t)
+}
+
+func TestDeleteScheduledWorkout_NotFound(t *testing.T) {
+	calendarDao := new(MockCalendarDao)
+	router := newCalendarRouter(calendarDao, new(MockScheduler))
+
+	scheduleUuid := uuid.New()
+	calendarDao.On("DeleteScheduledWorkout", scheduleUuid).Return(fmt.Errorf("scheduled workout %w", dao.ErrNotFound))
+
+	r, _ := http.NewRequest(http.MethodDelete, "/scheduled-workouts/"+scheduleUuid.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	calendarDao.AssertExpectations(t)
+}
+
+func TestGetCalendar(t *testing.T) {
+	scheduler := new(MockScheduler)
+	router := newCalendarRouter(new(MockCalendarDao), scheduler)
+
+	userUuid := uuid.New()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	date := time.Date(2025, 3, 5, 0, 0, 0, 0, berlin)
+	scheduler.On("Calendar", userUuid, model.CalendarWeek, date).
+		Return(&model.Calendar{UserUuid: userUuid, View: model.CalendarWeek, TimeZone: "Europe/Berlin"}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/calendar?view=week&date=2025-03-05&tz=Europe/Berlin", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"timeZone":"Europe/Berlin"`)
+	scheduler.AssertExpectations(t)
+}
+
+func TestGetCalendar_Invalid(t *testing.T) {
+	router := newCalendarRouter(new(MockCalendarDao), new(MockScheduler))
+
+	for _, query := range []string{"view=year", "tz=Mars/Olympus", "date=05.03.2025"} {
+		t.Run(query, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/calendar?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestCreateFeed(t *testing.T) {
+	scheduler := new(MockScheduler)
+	router := newCalendarRouter(new(MockCalendarDao), scheduler)
+
+	userUuid := uuid.New()
+	scheduler.On("CreateFeed", userUuid).
+		Return(&model.CalendarFeed{UserUuid: userUuid, Token: "secret", Path: "/calendar/secret.ics"}, nil)
+
+	r, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/calendar-feed", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"path":"/calendar/secret.ics"`)
+	scheduler.AssertExpectations(t)
+}
+
+func TestGetFeed(t *testing.T) {
+	calendarDao := new(MockCalendarDao)
+	router := newCalendarRouter(calendarDao, new(MockScheduler))
+
+	userUuid := uuid.New()
+	calendarDao.On("GetFeed", userUuid).Return(&model.CalendarFeed{UserUuid: userUuid, Token: "secret"}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/calendar-feed", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"path":"/calendar/secret.ics"`)
+	calendarDao.AssertExpectations(t)
+}
+
+func TestServeFeed(t *testing.T) {
+	scheduler := new(MockScheduler)
+	router := newCalendarRouter(new(MockCalendarDao), scheduler)
+
+	scheduler.On("WriteFeed", "secret").Return(nil)
+	scheduler.On("WriteFeed", "revoked").Return(fmt.Errorf("calendar feed %w", dao.ErrNotFound))
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/calendar/secret.ics", http.StatusOK, "text/calendar; charset=utf-8"},
+		{"/calendar/revoked.ics", http.StatusNotFound, ""},
+		{"/calendar/secret", http.StatusNotFound, ""},
+		{"/calendar/.ics", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				assert.True(t, strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR"))
+			}
+		})
+	}
+	scheduler.AssertExpectations(t)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/cardio"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/service"
+)
+
+// maxActivityUploadSize limits activity files, which are a few megabytes
+// even for long rides.
+const maxActivityUploadSize = 32 << 20
+
+type CardioHandler struct {
+	importer cardio.ImporterInterface
+	dao      dao.CardioDaoInterface
+	users    dao.UserDaoInterface
+}
+
+func NewCardioHandler(importer cardio.ImporterInterface, dao dao.CardioDaoInterface, users dao.UserDaoInterface) *CardioHandler {
+	return &CardioHandler{importer: importer, dao: dao, users: users}
+}
+
+// ImportActivity uploads a FIT, TCX or GPX file, named by the format in the
+// path, for the user in the path. The file is sent as the request body or as
+// the file field of a multipart form. The exerciseUuid query parameter names
+// the cardio exercise the activity is logged against and sessionName
+// optionally names the session.
+func (h CardioHandler) ImportActivity(ctx *gin.Context) {
+	opts := cardio.ImportOptions{SessionName: ctx.Query("sessionName")}
+	var err error
+	if opts.UserUuid, err = uuid.Parse(ctx.Param("uuid")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := cardio.ParseFormat(ctx.Param("format"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if opts.ExerciseUuid, err = uuid.Parse(ctx.Query("exerciseUuid")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "exerciseUuid is required"})
+		return
+	}
+
+	body, err := activityFile(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer body.Close()
+
+	activity, err := cardio.Parse(format, body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, opts.UserUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	created, err := h.importer.Import(ctx.Request.Context(), activity, opts)
+	switch {
+	case errors.Is(err, cardio.ErrDuplicate):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, cardio.ErrInvalidExercise), errors.Is(err, cardio.ErrEmpty):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		created.Convert(converter)
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetActivities lists the cardio activities of a user between the optional
+// from and to query parameters, which default to the last 30 days.
+func (h CardioHandler) GetActivities(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := timeRange(ctx, time.Now(), defaultWorkoutWindow)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, userUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	activities, err := h.dao.ListActivities(ctx.Request.Context(), userUuid, from, to)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	for i := range activities {
+		activities[i].Convert(converter)
+	}
+	ctx.JSON(http.StatusOK, activities)
+}
+
+// GetActivity returns a single activity with its heart rate samples.
+func (h CardioHandler) GetActivity(ctx *gin.Context) {
+	sessionUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	activity, err := h.dao.GetActivity(ctx.Request.Context(), sessionUuid)
+	if errors.Is(err, dao.ErrNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, activity.UserUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	activity.Convert(converter)
+	ctx.JSON(http.StatusOK, activity)
+}
+
+// activityFile returns the uploaded file, either the file field of a
+// multipart form or the whole body.
+func activityFile(ctx *gin.Context) (io.ReadCloser, error) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxActivityUploadSize)
+	if !strings.HasPrefix(ctx.ContentType(), "multipart/form-data") {
+		return ctx.Request.Body, nil
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return header.Open()
+}
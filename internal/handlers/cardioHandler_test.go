@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/cardio"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCardioImporter is a mock implementation of the cardio.ImporterInterface
+type MockCardioImporter struct {
+	mock.Mock
+}
+
+func (m *MockCardioImporter) Import(ctx context.Context, activity *cardio.Activity, opts cardio.ImportOptions) (*model.CardioActivity, error) {
+	args := m.Called(activity, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CardioActivity), args.Error(1)
+}
+
+// MockCardioDao is a mock implementation of the CardioDaoInterface
+type MockCardioDao struct {
+	mock.Mock
+}
+
+func (m *MockCardioDao) GetExerciseCategory(ctx context.Context, exUuid uuid.UUID) (string, error) {
+	args := m.Called(exUuid)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCardioDao) FindActivityByStart(ctx context.Context, userUuid uuid.UUID, startedAt time.Time) (*uuid.UUID, error) {
+	args := m.Called(userUuid, startedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*uuid.UUID), args.Error(1)
+}
+
+func (m *MockCardioDao) CreateActivity(ctx context.Context, activity *model.CardioActivity) error {
+	args := m.Called(activity)
+	return args.Error(0)
+}
+
+func (m *MockCardioDao) ListActivities(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.CardioActivity, error) {
+	args := m.Called(userUuid, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CardioActivity), args.Error(1)
+}
+
+func (m *MockCardioDao) GetActivity(ctx context.Context, sessionUuid uuid.UUID) (*model.CardioActivity, error) {
+	args := m.Called(sessionUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CardioActivity), args.Error(1)
+}
+
+const runGPX = `<gpx><trk><type>running</type><trkseg>
+<trkpt lat="52.0" lon="21.0"><time>2025-03-01T07:00:00Z</time></trkpt>
+<trkpt lat="52.009" lon="21.0"><time>2025-03-01T07:05:00Z</time></trkpt>
+</trkseg></trk></gpx>`
+
+func newCardioRouter(importer *MockCardioImporter, dao *MockCardioDao) *gin.Engine {
+	handler := NewCardioHandler(importer, dao, usersPreferring(units.Metric))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/cardio", handler.GetActivities)
+	router.POST("/users/:uuid/cardio/:format", handler.ImportActivity)
+	router.GET("/cardio/:uuid", handler.GetActivity)
+	return router
+}
+
+func TestImportActivity(t *testing.T) {
+	importer := new(MockCardioImporter)
+	router := newCardioRouter(importer, nil)
+
+	userUuid, exUuid := uuid.New(), uuid.New()
+	opts := cardio.ImportOptions{UserUuid: userUuid, ExerciseUuid: exUuid, SessionName: "Tempo"}
+	importer.On("Import", mock.MatchedBy(func(a *cardio.Activity) bool {
+		return a.Format == cardio.FormatGPX && a.Sport == "running" && len(a.Points) == 2
+	}), opts).Return(&model.CardioActivity{SessionUuid: uuid.New()}, nil)
+
+	url := fmt.Sprintf("/users/%s/cardio/gpx?exerciseUuid=%s&sessionName=Tempo", userUuid, exUuid)
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(runGPX))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	importer.AssertExpectations(t)
+}
+
+func TestImportActivity_Multipart(t *testing.T) {
+	importer := new(MockCardioImporter)
+	router := newCardioRouter(importer, nil)
+
+	importer.On("Import", mock.Anything, mock.Anything).Return(&model.CardioActivity{}, nil)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "run.gpx")
+	part.Write([]byte(runGPX))
+	form.Close()
+
+	url := fmt.Sprintf("/users/%s/cardio/gpx?exerciseUuid=%s", uuid.New(), uuid.New())
+	req, _ := http.NewRequest(http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	importer.AssertExpectations(t)
+}
+
+func TestImportActivity_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"duplicate", fmt.Errorf("%w as session x", cardio.ErrDuplicate), http.StatusConflict},
+		{"not cardio", fmt.Errorf("%w: strength", cardio.ErrInvalidExercise), http.StatusUnprocessableEntity},
+		{"empty", cardio.ErrEmpty, http.StatusUnprocessableEntity},
+		{"database", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer := new(MockCardioImporter)
+			router := newCardioRouter(importer, nil)
+			importer.On("Import", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			url := fmt.Sprintf("/users/%s/cardio/gpx?exerciseUuid=%s", uuid.New(), uuid.New())
+			req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(runGPX))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.JSONEq(t, `{"error":"`+tt.err.Error()+`"}`, w.Body.String())
+		})
+	}
+}
+
+func TestImportActivity_BadRequest(t *testing.T) {
+	importer := new(MockCardioImporter)
+	router := newCardioRouter(importer, nil)
+
+	userUuid, exUuid := uuid.New(), uuid.New()
+	tests := []struct {
+		url  string
+		body string
+		want string
+	}{
+		{fmt.Sprintf("/users/%s/cardio/kml?exerciseUuid=%s", userUuid, exUuid), runGPX, `unsupported format \"kml\", expected fit, tcx or gpx`},
+		{fmt.Sprintf("/users/%s/cardio/gpx", userUuid), runGPX, "exerciseUuid is required"},
+		{fmt.Sprintf("/users/%s/cardio/gpx?exerciseUuid=%s", userUuid, exUuid), "<gpx/>", "gpx file has no track"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	importer.AssertNotCalled(t, "Import")
+}
+
+func TestGetActivities(t *testing.T) {
+	cardioDao := new(MockCardioDao)
+	router := newCardioRouter(nil, cardioDao)
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+	cardioDao.On("ListActivities", userUuid, from, to).Return([]model.CardioActivity{{UserUuid: userUuid}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/cardio?from=2025-03-01&to=2025-03-08", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	cardioDao.AssertExpectations(t)
+}
+
+func TestGetActivity(t *testing.T) {
+	cardioDao := new(MockCardioDao)
+	router := newCardioRouter(nil, cardioDao)
+
+	found, missing := uuid.New(), uuid.New()
+	cardioDao.On("GetActivity", found).Return(&model.CardioActivity{SessionUuid: found}, nil)
+	cardioDao.On("GetActivity", missing).Return(nil, fmt.Errorf("activity with uuid %s %w", missing, dao.ErrNotFound))
+
+	req, _ := http.NewRequest(http.MethodGet, "/cardio/"+found.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/cardio/"+missing.String(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	cardioDao.AssertExpectations(t)
+}
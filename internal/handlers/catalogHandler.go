@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/catalog"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// maxBundleUploadSize bounds the size of an uploaded bundle, which has to be
+// held in memory to read the zip directory.
+const maxBundleUploadSize = 64 * 1024 * 1024
+
+type CatalogHandler struct {
+	importer catalog.ImporterInterface
+	exporter catalog.ExporterInterface
+}
+
+func NewCatalogHandler(importer catalog.ImporterInterface, exporter catalog.ExporterInterface) *CatalogHandler {
+	return &CatalogHandler{importer: importer, exporter: exporter}
+}
+
+// ExportExercises writes the catalog as csv, ndjson (the default) or bundle.
+func (h CatalogHandler) ExportExercises(ctx *gin.Context) {
+	format, err := catalog.ParseFormat(ctx.DefaultQuery("format", string(catalog.FormatNDJSON)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Buffer the export so a database error can still be reported as such.
+	var buf bytes.Buffer
+	if err := h.exporter.Export(ctx.Request.Context(), format, &buf); err != nil {
+		internalError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="shred-catalog.%s"`, format.Extension()))
+	ctx.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// ImportExercises loads a CSV, NDJSON or bundle file of exercises from the
+// request body. The query parameters select the format (otherwise taken from the
+// Content-Type), the mode (insert or upsert), dryRun and createdBy.
+func (h CatalogHandler) ImportExercises(ctx *gin.Context) {
+	format, err := importFormat(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := catalog.ImportOptions{Mode: model.ImportMode(ctx.DefaultQuery("mode", string(model.ImportModeInsert)))}
+	if opts.Mode != model.ImportModeInsert && opts.Mode != model.ImportModeUpsert {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "mode must be insert or upsert"})
+		return
+	}
+	if dryRun := ctx.Query("dryRun"); dryRun != "" {
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "dryRun must be a boolean"})
+			return
+		}
+	}
+	if createdBy := ctx.Query("createdBy"); createdBy != "" {
+		if opts.CreatedBy, err = uuid.Parse(createdBy); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if !opts.DryRun {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "createdBy is required"})
+		return
+	}
+
+	var report *model.ImportReport
+	if format == catalog.FormatBundle {
+		bundle, err := readBundle(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err = h.importer.ImportBundle(ctx.Request.Context(), bundle, opts)
+		if err != nil {
+			internalError(ctx, err)
+			return
+		}
+	} else {
+		rows, err := catalog.Parse(format, ctx.Request.Body)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err = h.importer.Import(ctx.Request.Context(), rows, opts)
+		if err != nil {
+			internalError(ctx, err)
+			return
+		}
+	}
+
+	switch {
+	case report.Invalid > 0:
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+	case report.Committed:
+		ctx.JSON(http.StatusCreated, report)
+	default:
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+func readBundle(ctx *gin.Context) (*catalog.Bundle, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBundleUploadSize))
+	if err != nil {
+		return nil, err
+	}
+	return catalog.ReadBundle(bytes.NewReader(data), int64(len(data)))
+}
+
+// importFormat prefers the format query parameter and falls back to the
+// media type of the request body.
+func importFormat(ctx *gin.Context) (catalog.Format, error) {
+	if name := ctx.Query("format"); name != "" {
+		return catalog.ParseFormat(name)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return catalog.FormatCSV, nil
+	case "application/x-ndjson", "application/jsonl":
+		return catalog.FormatNDJSON, nil
+	case "application/zip":
+		return catalog.FormatBundle, nil
+	}
+	return "", fmt.Errorf("unable to determine import format from content type %q", mediaType)
+}
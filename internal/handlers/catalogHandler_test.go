@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/catalog"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImporter is a mock implementation of the catalog.ImporterInterface
+type MockImporter struct {
+	mock.Mock
+}
+
+func (m *MockImporter) Import(ctx context.Context, rows []catalog.Row, opts catalog.ImportOptions) (*model.ImportReport, error) {
+	args := m.Called(rows, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportReport), args.Error(1)
+}
+
+func (m *MockImporter) ImportBundle(ctx context.Context, bundle *catalog.Bundle, opts catalog.ImportOptions) (*model.ImportReport, error) {
+	args := m.Called(bundle, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportReport), args.Error(1)
+}
+
+// MockExporter is a mock implementation of the catalog.ExporterInterface
+type MockExporter struct {
+	mock.Mock
+}
+
+func (m *MockExporter) Export(ctx context.Context, format catalog.Format, w io.Writer) error {
+	args := m.Called(format)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, args.String(1))
+	return err
+}
+
+const importCSV = "exerciseName,category,muscles\nSquat,STRENGTH,QUAD:primary\n"
+
+func newImportRouter(importer *MockImporter) *gin.Engine {
+	handler := NewCatalogHandler(importer, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/exercises/import", handler.ImportExercises)
+	return router
+}
+
+func TestImportExercises(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	createdBy := uuid.New()
+	opts := catalog.ImportOptions{Mode: model.ImportModeUpsert, CreatedBy: createdBy}
+	report := &model.ImportReport{Mode: model.ImportModeUpsert, Committed: true, Total: 1, Created: 1}
+	importer.On("Import", mock.MatchedBy(func(rows []catalog.Row) bool {
+		return len(rows) == 1 && rows[0].Exercise.ExerciseName == "Squat"
+	}), opts).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?mode=upsert&createdBy="+createdBy.String(), strings.NewReader(importCSV))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response model.ImportReport
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, *report, response)
+	importer.AssertExpectations(t)
+}
+
+func TestImportExercises_DryRun(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	opts := catalog.ImportOptions{Mode: model.ImportModeInsert, DryRun: true}
+	importer.On("Import", mock.Anything, opts).Return(&model.ImportReport{DryRun: true, Total: 1, Created: 1}, nil)
+
+	body := `{"exerciseName":"Squat","category":"STRENGTH"}`
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?format=ndjson&dryRun=true", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	importer.AssertExpectations(t)
+}
+
+func TestImportExercises_Invalid(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	importer.On("Import", mock.Anything, mock.Anything).Return(&model.ImportReport{Total: 1, Invalid: 1}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?format=csv&createdBy="+uuid.NewString(), strings.NewReader(importCSV))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestImportExercises_DbError(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	importer.On("Import", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?format=csv&createdBy="+uuid.NewString(), strings.NewReader(importCSV))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestImportExercises_BadRequest(t *testing.T) {
+	tests := []struct {
+		query       string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"?createdBy=" + uuid.NewString(), "application/json", importCSV, `{"error":"unable to determine import format from content type \"application/json\""}`},
+		{"?format=xml", "", importCSV, `{"error":"unsupported format \"xml\""}`},
+		{"?format=csv&mode=merge", "", importCSV, `{"error":"mode must be insert or upsert"}`},
+		{"?format=csv&dryRun=maybe", "", importCSV, `{"error":"dryRun must be a boolean"}`},
+		{"?format=csv", "", importCSV, `{"error":"createdBy is required"}`},
+		{"?format=csv&createdBy=bad", "", importCSV, `{"error":"invalid UUID length: 3"}`},
+		{"?format=csv&dryRun=true", "", "", `{"error":"csv input is empty"}`},
+	}
+
+	for _, tt := range tests {
+		importer := new(MockImporter)
+		router := newImportRouter(importer)
+
+		req, _ := http.NewRequest(http.MethodPost, "/exercises/import"+tt.query, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.query)
+		assert.Equal(t, tt.want, w.Body.String(), tt.query)
+		importer.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	}
+}
+
+func TestImportExercises_Bundle(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	var buf bytes.Buffer
+	exercises := []model.CatalogExercise{{ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}}}
+	assert.NoError(t, catalog.WriteBundle(&buf, model.CatalogReferences{}, exercises, time.Now()))
+
+	createdBy := uuid.New()
+	importer.On("ImportBundle", mock.MatchedBy(func(bundle *catalog.Bundle) bool {
+		return len(bundle.Rows) == 1 && bundle.Rows[0].Exercise.ExerciseName == "Squat"
+	}), catalog.ImportOptions{Mode: model.ImportModeInsert, CreatedBy: createdBy}).
+		Return(&model.ImportReport{Committed: true, Total: 1, Created: 1}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?createdBy="+createdBy.String(), &buf)
+	req.Header.Set("Content-Type", "application/zip")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	importer.AssertExpectations(t)
+}
+
+func TestImportExercises_BadBundle(t *testing.T) {
+	importer := new(MockImporter)
+	router := newImportRouter(importer)
+
+	req, _ := http.NewRequest(http.MethodPost, "/exercises/import?format=bundle&dryRun=true", strings.NewReader("not a zip"))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"error":"invalid bundle: zip: not a valid zip file"}`, w.Body.String())
+}
+
+func newExportRouter(exporter *MockExporter) *gin.Engine {
+	handler := NewCatalogHandler(nil, exporter)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/export", handler.ExportExercises)
+	return router
+}
+
+func TestExportExercises(t *testing.T) {
+	exporter := new(MockExporter)
+	router := newExportRouter(exporter)
+
+	exporter.On("Export", catalog.FormatCSV).Return(nil, importCSV)
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/export?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="shred-catalog.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, importCSV, w.Body.String())
+}
+
+func TestExportExercises_DefaultsToNDJSON(t *testing.T) {
+	exporter := new(MockExporter)
+	router := newExportRouter(exporter)
+
+	exporter.On("Export", catalog.FormatNDJSON).Return(nil, "{}\n")
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/export", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+}
+
+func TestExportExercises_Errors(t *testing.T) {
+	exporter := new(MockExporter)
+	router := newExportRouter(exporter)
+
+	exporter.On("Export", catalog.FormatBundle).Return(assert.AnError, "")
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/export?format=bundle", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+	req, _ = http.NewRequest(http.MethodGet, "/exercises/export?format=xml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
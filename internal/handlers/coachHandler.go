@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+)
+
+type CoachHandler struct {
+	dao dao.CoachDaoInterface
+}
+
+func NewCoachHandler(dao dao.CoachDaoInterface) *CoachHandler {
+	return &CoachHandler{dao: dao}
+}
+
+// GetClients lists the clients on the roster of the coach in the path.
+func (h CoachHandler) GetClients(ctx *gin.Context) {
+	coachUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clients, err := h.dao.ListClients(ctx.Request.Context(), coachUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, clients)
+}
+
+// AddClient puts a user on the roster of the coach in the path. Adding a
+// client twice is not an error.
+func (h CoachHandler) AddClient(ctx *gin.Context) {
+	coachUuid, clientUuid, ok := rosterParams(ctx)
+	if !ok {
+		return
+	}
+	if coachUuid == clientUuid {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "a coach cannot be their own client"})
+		return
+	}
+
+	err := h.dao.AddClient(ctx.Request.Context(), coachUuid, clientUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (h CoachHandler) RemoveClient(ctx *gin.Context) {
+	coachUuid, clientUuid, ok := rosterParams(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.dao.RemoveClient(ctx.Request.Context(), coachUuid, clientUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func rosterParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	coachUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	clientUuid, err := uuid.Parse(ctx.Param("clientUuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return coachUuid, clientUuid, true
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCoachDao is a mock implementation of the CoachDaoInterface
+type MockCoachDao struct {
+	mock.Mock
+}
+
+func (m *MockCoachDao) AddClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error {
+	args := m.Called(coachUuid, clientUuid)
+	return args.Error(0)
+}
+
+func (m *MockCoachDao) RemoveClient(ctx context.Context, coachUuid, clientUuid uuid.UUID) error {
+	args := m.Called(coachUuid, clientUuid)
+	return args.Error(0)
+}
+
+func (m *MockCoachDao) ListClients(ctx context.Context, coachUuid uuid.UUID) ([]model.RosterClient, error) {
+	args := m.Called(coachUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RosterClient), args.Error(1)
+}
+
+func newCoachRouter(coachDao *MockCoachDao) *gin.Engine {
+	handler := NewCoachHandler(coachDao)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/coaches/:uuid/clients", handler.GetClients)
+	router.PUT("/coaches/:uuid/clients/:clientUuid", handler.AddClient)
+	router.DELETE("/coaches/:uuid/clients/:clientUuid", handler.RemoveClient)
+	return router
+}
+
+func TestGetClients(t *testing.T) {
+	coachDao := new(MockCoachDao)
+	router := newCoachRouter(coachDao)
+
+	coachUuid := uuid.New()
+	coachDao.On("ListClients", coachUuid).Return([]model.RosterClient{{UserUuid: uuid.New(), FirstName: "John", LastName: "Doe"}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/coaches/"+coachUuid.String()+"/clients", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"lastName":"Doe"`)
+	coachDao.AssertExpectations(t)
+}
+
+func TestAddClient(t *testing.T) {
+	coachDao := new(MockCoachDao)
+	router := newCoachRouter(coachDao)
+
+	coachUuid, clientUuid, unknownUuid := uuid.New(), uuid.New(), uuid.New()
+	coachDao.On("AddClient", coachUuid, clientUuid).Return(nil)
+	coachDao.On("AddClient", coachUuid, unknownUuid).Return(fmt.Errorf("client %w", dao.ErrNotFound))
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"added", "/coaches/" + coachUuid.String() + "/clients/" + clientUuid.String(), http.StatusNoContent},
+		{"unknown client", "/coaches/" + coachUuid.String() + "/clients/" + unknownUuid.String(), http.StatusNotFound},
+		{"self", "/coaches/" + coachUuid.String() + "/clients/" + coachUuid.String(), http.StatusBadRequest},
+		{"invalid uuid", "/coaches/" + coachUuid.String() + "/clients/john", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPut, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	coachDao.AssertExpectations(t)
+}
+
+func TestRemoveClient_NotFound(t *testing.T) {
+	coachDao := new(MockCoachDao)
+	router := newCoachRouter(coachDao)
+
+	coachUuid, clientUuid := uuid.New(), uuid.New()
+	coachDao.On("RemoveClient", coachUuid, clientUuid).Return(fmt.Errorf("client %w", dao.ErrNotFound))
+
+	r, _ := http.NewRequest(http.MethodDelete, "/coaches/"+coachUuid.String()+"/clients/"+clientUuid.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	coachDao.AssertExpectations(t)
+}
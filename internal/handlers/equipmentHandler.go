@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+// EquipmentHandler manages the equipment profiles of users and gym locations
+// and lists what can be trained with them.
+type EquipmentHandler struct {
+	dao dao.EquipmentDaoInterface
+}
+
+func NewEquipmentHandler(dao dao.EquipmentDaoInterface) *EquipmentHandler {
+	return &EquipmentHandler{dao: dao}
+}
+
+// GetUserProfiles lists the profiles of the user in the path, the default
+// first.
+func (h EquipmentHandler) GetUserProfiles(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.listProfiles(ctx, &userUuid)
+}
+
+// GetGymProfiles lists the profiles of gym locations.
+func (h EquipmentHandler) GetGymProfiles(ctx *gin.Context) {
+	h.listProfiles(ctx, nil)
+}
+
+func (h EquipmentHandler) CreateUserProfile(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.createProfile(ctx, &userUuid)
+}
+
+func (h EquipmentHandler) CreateGymProfile(ctx *gin.Context) {
+	h.createProfile(ctx, nil)
+}
+
+func (h EquipmentHandler) GetProfile(ctx *gin.Context) {
+	profileUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.dao.GetProfile(ctx.Request.Context(), profileUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, profile)
+	}
+}
+
+// UpdateProfile renames the profile in the path and replaces its apparatus.
+func (h EquipmentHandler) UpdateProfile(ctx *gin.Context) {
+	profileUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req, ok := bindProfileRequest(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := h.dao.UpdateProfile(ctx.Request.Context(), profileUuid, req)
+	if respondProfileError(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
+
+func (h EquipmentHandler) DeleteProfile(ctx *gin.Context) {
+	profileUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.dao.DeleteProfile(ctx.Request.Context(), profileUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// GetProfileExercises lists the exercises the profile in the path has all
+// apparatus for, optionally of the category query parameter.
+func (h EquipmentHandler) GetProfileExercises(ctx *gin.Context) {
+	profileUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.findExercises(ctx, profileUuid)
+}
+
+// GetAvailableExercises lists the exercises the user in the path can do with
+// their default profile, or with the profile query parameter.
+func (h EquipmentHandler) GetAvailableExercises(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if param := ctx.Query("profile"); param != "" {
+		profileUuid, err := uuid.Parse(param)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.findExercises(ctx, profileUuid)
+		return
+	}
+
+	profile, err := h.dao.GetDefaultProfile(ctx.Request.Context(), userUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		internalError(ctx, err)
+		return
+	}
+	h.findExercises(ctx, profile.ProfileUuid)
+}
+
+func (h EquipmentHandler) listProfiles(ctx *gin.Context, userUuid *uuid.UUID) {
+	profiles, err := h.dao.ListProfiles(ctx.Request.Context(), userUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, profiles)
+}
+
+func (h EquipmentHandler) createProfile(ctx *gin.Context, userUuid *uuid.UUID) {
+	req, ok := bindProfileRequest(ctx)
+	if !ok {
+		return
+	}
+	if userUuid == nil && req.IsDefault {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "a gym location cannot be a default profile"})
+		return
+	}
+
+	profile, err := h.dao.CreateProfile(ctx.Request.Context(), userUuid, req)
+	if respondProfileError(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusCreated, profile)
+}
+
+func (h EquipmentHandler) findExercises(ctx *gin.Context, profileUuid uuid.UUID) {
+	exercises, err := h.dao.FindAvailableExercises(ctx.Request.Context(), profileUuid, ctx.Query("category"))
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, exercises)
+	}
+}
+
+func bindProfileRequest(ctx *gin.Context) (*model.EquipmentProfileRequest, bool) {
+	var req model.EquipmentProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	req.ProfileName = strings.TrimSpace(req.ProfileName)
+	if req.ProfileName == "" || req.CreatedBy == uuid.Nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "profileName and createdBy are required"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// respondProfileError writes the response for a failed create or update and
+// reports whether it did.
+func respondProfileError(ctx *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, dao.ErrConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		internalError(ctx, err)
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEquipmentDao is a mock implementation of the EquipmentDaoInterface
+type MockEquipmentDao struct {
+	mock.Mock
+}
+
+func (m *MockEquipmentDao) CreateProfile(ctx context.Context, userUuid *uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error) {
+	args := m.Called(userUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EquipmentProfile), args.Error(1)
+}
+
+func (m *MockEquipmentDao) ListProfiles(ctx context.Context, userUuid *uuid.UUID) ([]model.EquipmentProfile, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.EquipmentProfile), args.Error(1)
+}
+
+func (m *MockEquipmentDao) GetProfile(ctx context.Context, profileUuid uuid.UUID) (*model.EquipmentProfile, error) {
+	args := m.Called(profileUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EquipmentProfile), args.Error(1)
+}
+
+func (m *MockEquipmentDao) GetDefaultProfile(ctx context.Context, userUuid uuid.UUID) (*model.EquipmentProfile, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EquipmentProfile), args.Error(1)
+}
+
+func (m *MockEquipmentDao) UpdateProfile(ctx context.Context, profileUuid uuid.UUID, req *model.EquipmentProfileRequest) (*model.EquipmentProfile, error) {
+	args := m.Called(profileUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EquipmentProfile), args.Error(1)
+}
+
+func (m *MockEquipmentDao) DeleteProfile(ctx context.Context, profileUuid uuid.UUID) error {
+	args := m.Called(profileUuid)
+	return args.Error(0)
+}
+
+func (m *MockEquipmentDao) FindAvailableExercises(ctx context.Context, profileUuid uuid.UUID, category string) ([]model.AvailableExercise, error) {
+	args := m.Called(profileUuid, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AvailableExercise), args.Error(1)
+}
+
+func newEquipmentRouter(dao *MockEquipmentDao) *gin.Engine {
+	handler := NewEquipmentHandler(dao)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/equipment-profiles", handler.GetUserProfiles)
+	router.POST("/users/:uuid/equipment-profiles", handler.CreateUserProfile)
+	router.GET("/users/:uuid/available-exercises", handler.GetAvailableExercises)
+	router.GET("/equipment-profiles", handler.GetGymProfiles)
+	router.POST("/equipment-profiles", handler.CreateGymProfile)
+	router.GET("/equipment-profiles/:uuid", handler.GetProfile)
+	router.PUT("/equipment-profiles/:uuid", handler.UpdateProfile)
+	router.DELETE("/equipment-profiles/:uuid", handler.DeleteProfile)
+	router.GET("/equipment-profiles/:uuid/exercises", handler.GetProfileExercises)
+	return router
+}
+
+func TestCreateUserProfile(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	userUuid, createdBy := uuid.New(), uuid.New()
+	req := &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{ProfileName: "Home", IsDefault: true, Apparatus: []string{"BARBELL"}},
+		CreatedBy:              createdBy,
+	}
+	equipmentDao.On("CreateProfile", &userUuid, req).Return(&model.EquipmentProfile{UserUuid: &userUuid}, nil)
+
+	body := fmt.Sprintf(`{"profileName":" Home ","isDefault":true,"apparatus":["BARBELL"],"createdBy":"%s"}`, createdBy)
+	r, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/equipment-profiles", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	equipmentDao.AssertExpectations(t)
+}
+
+func TestCreateGymProfile_Invalid(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"profileName":" "}`, "profileName and createdBy are required"},
+		{fmt.Sprintf(`{"profileName":"Downtown","isDefault":true,"createdBy":"%s"}`, uuid.New()), "a gym location cannot be a default profile"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/equipment-profiles", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	equipmentDao.AssertNotCalled(t, "CreateProfile")
+}
+
+func TestUpdateProfile_Errors(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	equipmentDao.On("UpdateProfile", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("profile %w", dao.ErrConflict)).Once()
+	equipmentDao.On("UpdateProfile", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("apparatus %w", dao.ErrNotFound)).Once()
+
+	body := fmt.Sprintf(`{"profileName":"Home","createdBy":"%s"}`, uuid.New())
+	for _, want := range []int{http.StatusConflict, http.StatusNotFound} {
+		r, _ := http.NewRequest(http.MethodPut, "/equipment-profiles/"+uuid.NewString(), strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestGetGymProfiles(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	equipmentDao.On("ListProfiles", (*uuid.UUID)(nil)).Return([]model.EquipmentProfile{{}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/equipment-profiles", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	equipmentDao.AssertExpectations(t)
+}
+
+func TestDeleteProfile(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	equipmentDao.On("DeleteProfile", mock.Anything).Return(nil).Once()
+	equipmentDao.On("DeleteProfile", mock.Anything).Return(fmt.Errorf("profile %w", dao.ErrNotFound)).Once()
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		r, _ := http.NewRequest(http.MethodDelete, "/equipment-profiles/"+uuid.NewString(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestGetProfileExercises(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	profileUuid := uuid.New()
+	equipmentDao.On("FindAvailableExercises", profileUuid, "STRENGTH").
+		Return([]model.AvailableExercise{{ExerciseName: "Push-up", Apparatus: []string{}}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/equipment-profiles/"+profileUuid.String()+"/exercises?category=STRENGTH", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"exerciseName":"Push-up"`)
+	equipmentDao.AssertExpectations(t)
+}
+
+func TestGetAvailableExercises(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	userUuid, profileUuid, otherUuid := uuid.New(), uuid.New(), uuid.New()
+	equipmentDao.On("GetDefaultProfile", userUuid).Return(&model.EquipmentProfile{ProfileUuid: profileUuid}, nil)
+	equipmentDao.On("FindAvailableExercises", profileUuid, "").Return([]model.AvailableExercise{}, nil)
+	equipmentDao.On("FindAvailableExercises", otherUuid, "").Return([]model.AvailableExercise{}, nil)
+
+	for _, query := range []string{"", "?profile=" + otherUuid.String()} {
+		r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/available-exercises"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	equipmentDao.AssertExpectations(t)
+	equipmentDao.AssertNumberOfCalls(t, "GetDefaultProfile", 1)
+}
+
+func TestGetAvailableExercises_NoDefault(t *testing.T) {
+	equipmentDao := new(MockEquipmentDao)
+	router := newEquipmentRouter(equipmentDao)
+
+	equipmentDao.On("GetDefaultProfile", mock.Anything).Return(nil, fmt.Errorf("default profile %w", dao.ErrNotFound))
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/available-exercises", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	equipmentDao.AssertNotCalled(t, "FindAvailableExercises")
+}
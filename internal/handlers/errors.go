@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/logging"
+)
+
+/*
+ * internalError logs err with the request it failed, attaches it to the
+ * request for the tracing middleware and answers it with a server error,
+ * or with 504 when a query ran out of time.
+ */
+func internalError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	logging.FromContext(ctx.Request.Context()).ErrorContext(ctx.Request.Context(), "request failed",
+		"route", ctx.FullPath(), "error", err)
+	status := http.StatusInternalServerError
+	if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+	ctx.JSON(status, gin.H{"error": err.Error()})
+}
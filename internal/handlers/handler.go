@@ -1,35 +1,61 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pwydra/shred/internal/dao"
 	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
 )
 
 type Handler struct {
-	dao dao.ExerciseDaoInterface
+	exercises service.ExerciseServiceInterface
 }
 
-func NewHandler(dao dao.ExerciseDaoInterface) *Handler {
-	return &Handler{dao: dao}
+func NewHandler(exercises service.ExerciseServiceInterface) *Handler {
+	return &Handler{exercises: exercises}
 }
 
+// CreateExercise creates an exercise together with the muscles it works and
+// the apparatus it needs, all or nothing.
 func (h Handler) CreateExercise(ctx *gin.Context) {
 	var exReq model.ExerciseRequest
 	if err := ctx.ShouldBindJSON(&exReq); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if ex, ok := h.create(ctx, &exReq); ok {
+		ctx.JSON(http.StatusCreated, ex)
+	}
+}
 
-	ex, err := h.dao.Create(&exReq)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// CreateExerciseV2 is CreateExercise with the v2 spelling of the license
+// fields.
+func (h Handler) CreateExerciseV2(ctx *gin.Context) {
+	var exReq model.ExerciseRequestV2
+	if err := ctx.ShouldBindJSON(&exReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusCreated, ex)
+	if ex, ok := h.create(ctx, exReq.ExerciseRequest()); ok {
+		ctx.JSON(http.StatusCreated, model.NewExerciseV2(ex))
+	}
+}
+
+func (h Handler) create(ctx *gin.Context, exReq *model.ExerciseRequest) (*model.Exercise, bool) {
+	ex, err := h.exercises.Create(ctx.Request.Context(), exReq)
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		return ex, true
+	}
+	return nil, false
 }
 
 func (h Handler) UpdateExercise(ctx *gin.Context) {
@@ -38,22 +64,41 @@ func (h Handler) UpdateExercise(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	uuid, err := uuid.Parse(ctx.Param("uuid"))
-	if err != nil {
+	if h.update(ctx, &exReq) {
+		ctx.JSON(http.StatusOK, exReq)
+	}
+}
+
+// UpdateExerciseV2 is UpdateExercise with the v2 spelling of the license
+// fields.
+func (h Handler) UpdateExerciseV2(ctx *gin.Context) {
+	var exReq model.ExerciseV2
+	if err := ctx.ShouldBindJSON(&exReq); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if exReq.ExerciseUuid != uuid {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UUID in path does not match UUID in request body"})
-		return
+	if h.update(ctx, exReq.Exercise()) {
+		ctx.JSON(http.StatusOK, exReq)
 	}
+}
 
-	err = h.dao.Update(&exReq)
+func (h Handler) update(ctx *gin.Context, ex *model.Exercise) bool {
+	uuid, err := uuid.Parse(ctx.Param("uuid"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
 	}
-	ctx.JSON(http.StatusOK, exReq)
+
+	err = h.exercises.Update(ctx.Request.Context(), uuid, ex)
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		return true
+	}
+	return false
 }
 
 func (h Handler) DeleteExercise(ctx *gin.Context) {
@@ -62,23 +107,47 @@ func (h Handler) DeleteExercise(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.dao.Delete(uuid); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	err = h.exercises.Delete(ctx.Request.Context(), uuid)
+	switch {
+	case errors.Is(err, dao.ErrConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusNoContent, nil)
 	}
-	ctx.JSON(http.StatusNoContent, nil)
 }
 
+// GetExercise returns an exercise in the language that best matches the
+// Accept-Language header.
 func (h Handler) GetExercise(ctx *gin.Context) {
+	if ex, ok := h.get(ctx); ok {
+		ctx.JSON(http.StatusOK, ex)
+	}
+}
+
+// GetExerciseV2 is GetExercise with the v2 spelling of the license fields.
+func (h Handler) GetExerciseV2(ctx *gin.Context) {
+	if ex, ok := h.get(ctx); ok {
+		ctx.JSON(http.StatusOK, model.NewExerciseV2(ex))
+	}
+}
+
+func (h Handler) get(ctx *gin.Context) (*model.Exercise, bool) {
 	uuid, err := uuid.Parse(ctx.Param("uuid"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return nil, false
 	}
-	ex, err := h.dao.Read(uuid)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	ex, locale, err := h.exercises.Get(ctx.Request.Context(), uuid, ctx.GetHeader("Accept-Language"))
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	case err != nil:
+		internalError(ctx, err)
+		return nil, false
 	}
-	ctx.JSON(http.StatusOK, ex)
+	ctx.Header("Content-Language", locale)
+	return ex, true
 }
@@ -2,53 +2,103 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
 	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-// MockExerciseDao is a mock implementation of the ExerciseDao interface
-type MockExerciseDao struct {
-	mock.Mock
+/*
+ * newMemoryDaos returns the DAOs on an empty memory store holding the
+ * STRENGTH category, the CC-BY and CC-BY-NC licenses, the muscles
+ * LEGS > QUADS > RECTUS_FEMORIS, LEGS > GLUTES and LEGS > HAMSTRINGS and the
+ * BARBELL apparatus.
+ */
+func newMemoryDaos(t *testing.T) *dao.Daos {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "strength", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	for _, name := range []string{"CC-BY", "CC-BY-NC"} {
+		require.NoError(t, daos.Licenses.CreateLicense(ctx, &model.LicenseRequest{
+			LicenseFields: model.LicenseFields{LicenseShortName: name, LicenseFullName: name}}))
+	}
+	legs, quads := "LEGS", "QUADS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs},
+		{MuscleCode: "RECTUS_FEMORIS", MuscleName: "Rectus femoris", ParentCode: &quads},
+		{MuscleCode: "GLUTES", MuscleName: "Glutes", MuscleDesc: "Muscles of the buttocks", ParentCode: &legs},
+		{MuscleCode: "HAMSTRINGS", MuscleName: "Hamstrings", MuscleDesc: "Back of the thigh", ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		require.NoError(t, err)
+	}
+	require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+		ApparatusFields: model.ApparatusFields{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}}))
+	return daos
+}
+
+// createExercise stores a strength exercise working muscles as primary
+// muscles.
+func createExercise(t *testing.T, daos *dao.Daos, name string, muscles ...string) uuid.UUID {
+	ctx := context.Background()
+	ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: name, CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	for _, code := range muscles {
+		require.NoError(t, daos.Exercises.AddMuscles(ctx, ex.ExerciseUuid,
+			[]model.ExerciseMuscle{{MuscleCode: code, MuscleRole: model.MuscleRolePrimary}}))
+	}
+	return ex.ExerciseUuid
 }
 
-func (m *MockExerciseDao) Create(exerciseRequest *model.ExerciseRequest) (*model.Exercise, error) {
-	args := m.Called(exerciseRequest)
-	return args.Get(0).(*model.Exercise), args.Error(1)
+func newExerciseHandler(daos *dao.Daos) *Handler {
+	return NewHandler(service.NewExerciseService(daos.Exercises, daos.Translations, daos.UnitOfWork))
 }
 
-func (m *MockExerciseDao) Read(uuid uuid.UUID) (*model.Exercise, error) {
-	args := m.Called(uuid)
-	return args.Get(0).(*model.Exercise), args.Error(1)
+// failingExerciseDao fails every read and delete with err.
+type failingExerciseDao struct {
+	dao.ExerciseDaoInterface
+	err error
 }
 
-func (m *MockExerciseDao) Update(exercise *model.Exercise) error {
-	args := m.Called(exercise)
-	return args.Error(0)
+func (d failingExerciseDao) Read(ctx context.Context, exUuid uuid.UUID) (*model.Exercise, error) {
+	return nil, d.err
 }
 
-func (m *MockExerciseDao) Delete(uuid uuid.UUID) error {
-	args := m.Called(uuid)
-	return args.Error(0)
+func (d failingExerciseDao) Delete(ctx context.Context, exUuid uuid.UUID) error {
+	return d.err
 }
 
-func newUpdateExerciseRequest() model.Exercise {
+func newFailingExerciseHandler(daos *dao.Daos, err error) *Handler {
+	exercises := failingExerciseDao{ExerciseDaoInterface: daos.Exercises, err: err}
+	return NewHandler(service.NewExerciseService(exercises, daos.Translations, daos.UnitOfWork))
+}
+
+func newUpdateExerciseRequest(exUuid uuid.UUID) model.Exercise {
 	return model.Exercise{
-		ExerciseUuid: uuid.New(),
+		ExerciseUuid: exUuid,
 		ExerciseFields: model.ExerciseFields{
+			ExerciseName:     "Updated Name",
 			Description:      "Updated Description",
 			Instructions:     "Updated Instructions",
 			Cues:             "Updated Cues",
 			VideoUrl:         "http://example.com/updated.mp4",
-			CategoryCode:     "updated-category",
+			CategoryCode:     "STRENGTH",
 			LicenseShortName: "CC-BY-NC",
 			LicenseAuthor:    "Jane Doe",
 		},
@@ -56,8 +106,8 @@ func newUpdateExerciseRequest() model.Exercise {
 }
 
 func TestCreateExercise(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -70,20 +120,13 @@ func TestCreateExercise(t *testing.T) {
 			Instructions:     "Stand with feet shoulder-width apart",
 			Cues:             "Keep chest up and back flat",
 			VideoUrl:         "http://example.com/squat.mp4",
-			CategoryCode:     "strength",
+			CategoryCode:     "STRENGTH",
 			LicenseShortName: "CC-BY",
 			LicenseAuthor:    "John Doe",
 		},
 		CreatedBy: uuid.New(),
 	}
 
-	ex := model.Exercise{
-		ExerciseUuid:   uuid.New(),
-		ExerciseFields: exReq.ExerciseFields,
-	}
-
-	mockDao.On("Create", &exReq).Return(&ex, nil)
-
 	body, _ := json.Marshal(exReq)
 	req, _ := http.NewRequest(http.MethodPost, "/exercises", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -95,54 +138,92 @@ func TestCreateExercise(t *testing.T) {
 	var response model.Exercise
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, ex.CreatedBy, response.CreatedBy)
+	assert.Equal(t, exReq.CreatedBy, response.CreatedBy)
+
+	stored, err := daos.Exercises.Read(context.Background(), response.ExerciseUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, exReq.ExerciseFields, stored.ExerciseFields)
+}
+
+func TestCreateExercise_MusclesAndApparatus(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/exercises", handler.CreateExercise)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"created", `{"exerciseName":"Squat","category":"STRENGTH","muscles":[{"muscleCode":"QUADS","muscleRole":"primary"}],"apparatus":["BARBELL"]}`, http.StatusCreated},
+		{"unknown muscle", `{"exerciseName":"Curl","category":"STRENGTH","muscles":[{"muscleCode":"BICEPS","muscleRole":"primary"}]}`, http.StatusBadRequest},
+		{"unknown apparatus", `{"exerciseName":"Swing","category":"STRENGTH","apparatus":["KETTLEBELL"]}`, http.StatusBadRequest},
+		{"invalid role", `{"exerciseName":"Lunge","category":"STRENGTH","muscles":[{"muscleCode":"QUADS","muscleRole":"main"}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/exercises", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	// Exercises with unknown muscles or apparatus are rolled back.
+	exercises, err := daos.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 1) {
+		assert.Equal(t, "Squat", exercises[0].ExerciseName)
+	}
+	muscles, err := daos.Muscles.FindExercisesByMuscle(context.Background(), "QUADS", model.MuscleRolePrimary)
+	assert.NoError(t, err)
+	assert.Len(t, muscles, 1)
 }
 
 func TestGetExercise(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.GET("/exercises/:uuid", handler.GetExercise)
 
-	exUuid := uuid.New()
-	ex := model.Exercise{
-		ExerciseUuid: exUuid,
+	ex, err := daos.Exercises.Create(context.Background(), &model.ExerciseRequest{
 		ExerciseFields: model.ExerciseFields{
 			ExerciseName:     "Squat",
 			Description:      "Lower Body",
 			Instructions:     "Stand with feet shoulder-width apart",
 			Cues:             "Keep chest up and back flat",
 			VideoUrl:         "http://example.com/squat.mp4",
-			CategoryCode:     "strength",
+			CategoryCode:     "STRENGTH",
 			LicenseShortName: "CC-BY",
 			LicenseAuthor:    "John Doe",
 		},
-		AuditRecord: model.AuditRecord{
-			CreatedBy: uuid.New(),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-	}
-
-	mockDao.On("Read", exUuid).Return(&ex, nil)
+		CreatedBy: uuid.New(),
+	})
+	require.NoError(t, err)
 
-	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String(), nil)
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+ex.ExerciseUuid.String(), nil)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	var response model.Exercise
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, ex.ExerciseUuid, response.ExerciseUuid)
+	assert.Equal(t, ex.ExerciseFields, response.ExerciseFields)
+	assert.Equal(t, ex.CreatedBy, response.CreatedBy)
 }
 
 func TestGetExercise_BadUuid(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	handler := newExerciseHandler(newMemoryDaos(t))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -158,36 +239,63 @@ func TestGetExercise_BadUuid(t *testing.T) {
 	assert.Equal(t, w.Body.String(), `{"error":"invalid UUID length: 7"}`)
 }
 
-func TestGetExercise_DbError(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+func TestGetExercise_NotFound(t *testing.T) {
+	handler := newExerciseHandler(newMemoryDaos(t))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.GET("/exercises/:uuid", handler.GetExercise)
 
 	exUuid := uuid.New()
-	mockDao.On("Select", exUuid).Return(assert.AnError)
-
 	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String(), nil)
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"error":"exercise with uuid %s not found"}`, exUuid), w.Body.String())
+}
+
+func TestGetExercise_DbError(t *testing.T) {
+	handler := newFailingExerciseHandler(newMemoryDaos(t), assert.AnError)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/:uuid", handler.GetExercise)
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestGetExercise_Timeout(t *testing.T) {
+	handler := newFailingExerciseHandler(newMemoryDaos(t),
+		fmt.Errorf("%w: canceling statement", context.DeadlineExceeded))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/:uuid", handler.GetExercise)
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
 func TestUpdateExercise(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.PUT("/exercises/:uuid", handler.UpdateExercise)
 
-	ex := newUpdateExerciseRequest()
-
-	mockDao.On("Update", &ex).Return(nil)
+	ex := newUpdateExerciseRequest(createExercise(t, daos, "Squat"))
 
 	body, _ := json.Marshal(ex)
 	req, _ := http.NewRequest(http.MethodPut, "/exercises/"+ex.ExerciseUuid.String(), bytes.NewBuffer(body))
@@ -201,17 +309,20 @@ func TestUpdateExercise(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, ex.ExerciseUuid, response.ExerciseUuid)
+
+	stored, err := daos.Exercises.Read(context.Background(), ex.ExerciseUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, ex.ExerciseFields, stored.ExerciseFields)
 }
 
 func TestUpdateExercise_BadUuid(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	handler := newExerciseHandler(newMemoryDaos(t))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.PUT("/exercises/:uuid", handler.UpdateExercise)
 
-	ex := newUpdateExerciseRequest()
+	ex := newUpdateExerciseRequest(uuid.New())
 
 	body, _ := json.Marshal(ex)
 	req, _ := http.NewRequest(http.MethodPut, "/exercises/badUuid", bytes.NewBuffer(body))
@@ -225,17 +336,18 @@ func TestUpdateExercise_BadUuid(t *testing.T) {
 }
 
 func TestUpdateExercise_UnmatchedUuid(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.PUT("/exercises/:uuid", handler.UpdateExercise)
 
-	ex := newUpdateExerciseRequest()
+	squat := createExercise(t, daos, "Squat")
+	ex := newUpdateExerciseRequest(uuid.New())
 
 	body, _ := json.Marshal(ex)
-	req, _ := http.NewRequest(http.MethodPut, "/exercises/"+uuid.New().String(), bytes.NewBuffer(body))
+	req, _ := http.NewRequest(http.MethodPut, "/exercises/"+squat.String(), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
@@ -243,11 +355,13 @@ func TestUpdateExercise_UnmatchedUuid(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Equal(t, w.Body.String(), `{"error":"UUID in path does not match UUID in request body"}`)
+	stored, err := daos.Exercises.Read(context.Background(), squat)
+	assert.NoError(t, err)
+	assert.Equal(t, "Squat", stored.ExerciseName)
 }
 
 func TestUpdateExercise_BadRequestBody(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	handler := newExerciseHandler(newMemoryDaos(t))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -265,17 +379,104 @@ func TestUpdateExercise_BadRequestBody(t *testing.T) {
 	assert.Equal(t, w.Body.String(), `{"error":"invalid character 'b' looking for beginning of value"}`)
 }
 
+func TestCreateExerciseV2(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/exercises", handler.CreateExerciseV2)
+
+	createdBy := uuid.New()
+	body := fmt.Sprintf(`{"exerciseName":"Squat","category":"STRENGTH","licenseShortName":"CC-BY",
+		"licenseAuthor":"John Doe","createdBy":%q}`, createdBy)
+	req, _ := http.NewRequest(http.MethodPost, "/exercises", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"licenseShortName":"CC-BY","licenseAuthor":"John Doe"`)
+	assert.NotContains(t, w.Body.String(), "licence")
+
+	var response model.ExerciseV2
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	stored, err := daos.Exercises.Read(context.Background(), response.ExerciseUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "CC-BY", stored.LicenseShortName)
+	assert.Equal(t, createdBy, stored.CreatedBy)
+}
+
+func TestGetExerciseV2(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/:uuid", handler.GetExerciseV2)
+
+	created, err := daos.Exercises.Create(context.Background(), &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH",
+			LicenseShortName: "CC-BY", LicenseAuthor: "John Doe"},
+		CreatedBy: uuid.New(),
+	})
+	require.NoError(t, err)
+	ex, err := daos.Exercises.Read(context.Background(), created.ExerciseUuid)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/exercises/"+ex.ExerciseUuid.String(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response model.ExerciseV2
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, model.NewExerciseV2(ex).ExerciseFieldsV2, response.ExerciseFieldsV2)
+	assert.Equal(t, ex.ExerciseUuid, response.ExerciseUuid)
+
+	req, _ = http.NewRequest(http.MethodGet, "/exercises/not-a-uuid", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateExerciseV2(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PUT("/exercises/:uuid", handler.UpdateExerciseV2)
+
+	ex := newUpdateExerciseRequest(createExercise(t, daos, "Squat"))
+
+	body, _ := json.Marshal(model.NewExerciseV2(&ex))
+	req, _ := http.NewRequest(http.MethodPut, "/exercises/"+ex.ExerciseUuid.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"licenseShortName":"CC-BY-NC"`)
+	stored, err := daos.Exercises.Read(context.Background(), ex.ExerciseUuid)
+	assert.NoError(t, err)
+	assert.Equal(t, ex.ExerciseFields, stored.ExerciseFields)
+}
+
 func TestDeleteExercise(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.DELETE("/exercises/:uuid", handler.DeleteExercise)
 
-	exUuid := uuid.New()
-
-	mockDao.On("Delete", exUuid).Return(nil)
+	// The exercise goes with its muscles and apparatus.
+	exUuid := createExercise(t, daos, "Squat", "QUADS", "GLUTES")
+	require.NoError(t, daos.Exercises.AddApparatus(context.Background(), exUuid, []string{"BARBELL"}))
 
 	req, _ := http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String(), nil)
 	w := httptest.NewRecorder()
@@ -283,19 +484,45 @@ func TestDeleteExercise(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
+	_, err := daos.Exercises.Read(context.Background(), exUuid)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	exercises, err := daos.Muscles.FindExercisesByMuscle(context.Background(), "LEGS", "")
+	assert.NoError(t, err)
+	assert.Empty(t, exercises)
 }
 
-func TestDeleteExercise_DbError(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+func TestDeleteExercise_InUse(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.DELETE("/exercises/:uuid", handler.DeleteExercise)
 
-	exUuid := uuid.New()
+	exUuid := createExercise(t, daos, "Squat", "QUADS")
+	require.NoError(t, daos.Mappings.SaveMapping(context.Background(),
+		&model.ExerciseMapping{Source: "strong", ExternalName: "Squat (Barbell)", ExerciseUuid: exUuid}))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String(), nil)
+	w := httptest.NewRecorder()
 
-	mockDao.On("Delete", exUuid).Return(assert.AnError)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	exercises, err := daos.Muscles.FindExercisesByMuscle(context.Background(), "QUADS", "")
+	assert.NoError(t, err)
+	assert.Len(t, exercises, 1, "the muscles stay with the exercise")
+}
+
+func TestDeleteExercise_DbError(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newFailingExerciseHandler(daos, assert.AnError)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.DELETE("/exercises/:uuid", handler.DeleteExercise)
+
+	exUuid := createExercise(t, daos, "Squat", "QUADS")
 
 	req, _ := http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String(), nil)
 	w := httptest.NewRecorder()
@@ -303,20 +530,18 @@ func TestDeleteExercise_DbError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	exercises, err := daos.Muscles.FindExercisesByMuscle(context.Background(), "QUADS", "")
+	assert.NoError(t, err)
+	assert.Len(t, exercises, 1, "the muscles are rolled back")
 }
 
 func TestDeleteExercise_BadUuid(t *testing.T) {
-	mockDao := new(MockExerciseDao)
-	handler := NewHandler(mockDao)
+	handler := newExerciseHandler(newMemoryDaos(t))
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	router.DELETE("/exercises/:uuid", handler.DeleteExercise)
 
-	exUuid := uuid.New()
-
-	mockDao.On("Delete", exUuid).Return(nil)
-
 	req, _ := http.NewRequest(http.MethodDelete, "/exercises/badGuid", nil)
 	w := httptest.NewRecorder()
 
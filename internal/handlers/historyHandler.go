@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/history"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type HistoryHandler struct {
+	importer history.ImporterInterface
+	mappings dao.ExerciseMappingDaoInterface
+}
+
+func NewHistoryHandler(importer history.ImporterInterface, mappings dao.ExerciseMappingDaoInterface) *HistoryHandler {
+	return &HistoryHandler{importer: importer, mappings: mappings}
+}
+
+// ImportHistory imports a CSV export from Strong, Hevy or FitNotes for the
+// user in the path. The query parameters select the source (otherwise
+// detected from the header), the timezone and the weightUnit and
+// distanceUnit of Strong exports, dryRun and skipUnresolved.
+func (h HistoryHandler) ImportHistory(ctx *gin.Context) {
+	opts := history.ImportOptions{}
+	var err error
+	if opts.UserUuid, err = uuid.Parse(ctx.Param("uuid")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var source history.Source
+	if name := ctx.Query("source"); name != "" {
+		if source, err = history.ParseSource(name); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	parseOpts := history.ParseOptions{
+		WeightUnit:   history.WeightUnit(ctx.DefaultQuery("weightUnit", string(history.WeightUnitKg))),
+		DistanceUnit: history.DistanceUnit(ctx.DefaultQuery("distanceUnit", string(history.DistanceUnitKm))),
+	}
+	if parseOpts.WeightUnit != history.WeightUnitKg && parseOpts.WeightUnit != history.WeightUnitLb {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "weightUnit must be kg or lb"})
+		return
+	}
+	if parseOpts.DistanceUnit != history.DistanceUnitKm && parseOpts.DistanceUnit != history.DistanceUnitMi {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "distanceUnit must be km or mi"})
+		return
+	}
+	if parseOpts.Location, err = time.LoadLocation(ctx.DefaultQuery("timezone", "UTC")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flags := []struct {
+		name   string
+		target *bool
+	}{{"dryRun", &opts.DryRun}, {"skipUnresolved", &opts.SkipUnresolved}}
+	for _, flag := range flags {
+		if value := ctx.Query(flag.name); value != "" {
+			if *flag.target, err = strconv.ParseBool(value); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": flag.name + " must be a boolean"})
+				return
+			}
+		}
+	}
+
+	parsed, err := history.Parse(ctx.Request.Body, source, parseOpts)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.importer.Import(ctx.Request.Context(), parsed, opts)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+
+	switch {
+	case report.Committed:
+		ctx.JSON(http.StatusCreated, report)
+	case !opts.DryRun && (len(report.Errors) > 0 || report.Unresolved > 0):
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// GetMappings lists the manual exercise name mappings of a source.
+func (h HistoryHandler) GetMappings(ctx *gin.Context) {
+	source, err := history.ParseSource(ctx.Param("source"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mappings, err := h.mappings.GetMappings(ctx.Request.Context(), string(source))
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, mappings)
+}
+
+// SaveMapping maps an exercise name of a source to a catalog exercise.
+func (h HistoryHandler) SaveMapping(ctx *gin.Context) {
+	source, err := history.ParseSource(ctx.Param("source"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var mapping model.ExerciseMapping
+	if err := ctx.ShouldBindJSON(&mapping); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if mapping.ExternalName == "" || mapping.ExerciseUuid == uuid.Nil || mapping.CreatedBy == uuid.Nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "externalName, exerciseUuid and createdBy are required"})
+		return
+	}
+	mapping.Source = string(source)
+
+	if err := h.mappings.SaveMapping(ctx.Request.Context(), &mapping); err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, mapping)
+}
+
+// DeleteMapping removes the mapping of the externalName query parameter.
+func (h HistoryHandler) DeleteMapping(ctx *gin.Context) {
+	source, err := history.ParseSource(ctx.Param("source"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	externalName := ctx.Query("externalName")
+	if externalName == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "externalName is required"})
+		return
+	}
+
+	if err := h.mappings.DeleteMapping(ctx.Request.Context(), string(source), externalName); err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/history"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHistoryImporter is a mock implementation of the history.ImporterInterface
+type MockHistoryImporter struct {
+	mock.Mock
+}
+
+func (m *MockHistoryImporter) Import(ctx context.Context, parsed *history.ParseResult, opts history.ImportOptions) (*model.HistoryImportReport, error) {
+	args := m.Called(parsed, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.HistoryImportReport), args.Error(1)
+}
+
+// MockExerciseMappingDao is a mock implementation of the ExerciseMappingDaoInterface
+type MockExerciseMappingDao struct {
+	mock.Mock
+}
+
+func (m *MockExerciseMappingDao) GetMappings(ctx context.Context, source string) ([]model.ExerciseMapping, error) {
+	args := m.Called(source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExerciseMapping), args.Error(1)
+}
+
+func (m *MockExerciseMappingDao) SaveMapping(ctx context.Context, mapping *model.ExerciseMapping) error {
+	args := m.Called(mapping)
+	return args.Error(0)
+}
+
+func (m *MockExerciseMappingDao) DeleteMapping(ctx context.Context, source, externalName string) error {
+	args := m.Called(source, externalName)
+	return args.Error(0)
+}
+
+const strongCSV = "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n2025-03-01 08:30:00,Legs,Squat,1,100,5\n"
+
+func newHistoryRouter(importer *MockHistoryImporter, mappings *MockExerciseMappingDao) *gin.Engine {
+	handler := NewHistoryHandler(importer, mappings)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/users/:uuid/history/import", handler.ImportHistory)
+	router.GET("/history/mappings/:source", handler.GetMappings)
+	router.PUT("/history/mappings/:source", handler.SaveMapping)
+	router.DELETE("/history/mappings/:source", handler.DeleteMapping)
+	return router
+}
+
+func TestImportHistory(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		report model.HistoryImportReport
+		want   int
+	}{
+		{"committed", "", model.HistoryImportReport{Committed: true, Sessions: 1}, http.StatusCreated},
+		{"dry run", "?dryRun=true", model.HistoryImportReport{DryRun: true, Unresolved: 1}, http.StatusOK},
+		{"unresolved", "", model.HistoryImportReport{Unresolved: 1}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer := new(MockHistoryImporter)
+			router := newHistoryRouter(importer, nil)
+
+			userUuid := uuid.New()
+			report := tt.report
+			importer.On("Import", mock.MatchedBy(func(parsed *history.ParseResult) bool {
+				return parsed.Source == history.SourceStrong && len(parsed.Sessions) == 1
+			}), mock.MatchedBy(func(opts history.ImportOptions) bool {
+				return opts.UserUuid == userUuid
+			})).Return(&report, nil)
+
+			req, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/history/import"+tt.query, strings.NewReader(strongCSV))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			importer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportHistory_BadRequest(t *testing.T) {
+	importer := new(MockHistoryImporter)
+	router := newHistoryRouter(importer, nil)
+
+	tests := []struct {
+		query string
+		body  string
+		want  string
+	}{
+		{"?source=myfitnesspal", strongCSV, `unsupported source \"myfitnesspal\", expected strong, hevy or fitnotes`},
+		{"?weightUnit=stone", strongCSV, "weightUnit must be kg or lb"},
+		{"?timezone=Mars/Olympus", strongCSV, "unknown time zone Mars/Olympus"},
+		{"?dryRun=maybe", strongCSV, "dryRun must be a boolean"},
+		{"", "", "csv input is empty"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/history/import"+tt.query, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	importer.AssertNotCalled(t, "Import")
+}
+
+func TestGetMappings(t *testing.T) {
+	mappings := new(MockExerciseMappingDao)
+	router := newHistoryRouter(nil, mappings)
+
+	mappings.On("GetMappings", "hevy").Return([]model.ExerciseMapping{{Source: "hevy", ExternalName: "squat"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/history/mappings/Hevy", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mappings.AssertExpectations(t)
+}
+
+func TestSaveMapping(t *testing.T) {
+	mappings := new(MockExerciseMappingDao)
+	router := newHistoryRouter(nil, mappings)
+
+	exUuid, createdBy := uuid.New(), uuid.New()
+	mappings.On("SaveMapping", &model.ExerciseMapping{Source: "strong", ExternalName: "Squat (Barbell)", ExerciseUuid: exUuid, CreatedBy: createdBy}).Return(nil)
+
+	body := `{"externalName":"Squat (Barbell)","exerciseUuid":"` + exUuid.String() + `","createdBy":"` + createdBy.String() + `"}`
+	req, _ := http.NewRequest(http.MethodPut, "/history/mappings/strong", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mappings.AssertExpectations(t)
+
+	req, _ = http.NewRequest(http.MethodPut, "/history/mappings/strong", strings.NewReader(`{"externalName":"Squat"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteMapping(t *testing.T) {
+	mappings := new(MockExerciseMappingDao)
+	router := newHistoryRouter(nil, mappings)
+
+	mappings.On("DeleteMapping", "strong", "Squat").Return(nil)
+	mappings.On("DeleteMapping", "strong", "Plank").Return(errors.New("mapping for strong exercise 'Plank' not found"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/history/mappings/strong?externalName=Squat", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/history/mappings/strong?externalName=Plank", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mappings.AssertExpectations(t)
+}
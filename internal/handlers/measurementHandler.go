@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+)
+
+// defaultMeasurementWindow is how far back measurements are listed without a
+// from date.
+const defaultMeasurementWindow = 90 * 24 * time.Hour
+
+type MeasurementHandler struct {
+	dao   dao.MeasurementDaoInterface
+	users dao.UserDaoInterface
+}
+
+func NewMeasurementHandler(dao dao.MeasurementDaoInterface, users dao.UserDaoInterface) *MeasurementHandler {
+	return &MeasurementHandler{dao: dao, users: users}
+}
+
+// CreateMeasurement records a bodyweight, body fat or circumference
+// measurement for the user in the path. Values given in lb or in are stored
+// in kg and cm; responses give them in the units the user prefers.
+func (h MeasurementHandler) CreateMeasurement(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req, ok := bindMeasurementRequest(ctx)
+	if !ok {
+		return
+	}
+
+	measurement, err := h.dao.CreateMeasurement(ctx.Request.Context(), userUuid, req)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusCreated, measurement)
+	}
+}
+
+// GetMeasurements lists the measurements of a user between the optional from
+// and to query parameters, which default to the last 90 days, optionally of
+// one kind and site.
+func (h MeasurementHandler) GetMeasurements(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	kind := model.MeasurementKind(ctx.Query("kind"))
+	if kind != "" && !kind.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown measurement kind %q", kind)})
+		return
+	}
+	from, to, err := timeRange(ctx, time.Now(), defaultMeasurementWindow)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, userUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	measurements, err := h.dao.ListMeasurements(ctx.Request.Context(), userUuid, kind,
+		strings.ToUpper(ctx.Query("site")), from, to)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	for i := range measurements {
+		measurements[i].Convert(converter)
+	}
+	ctx.JSON(http.StatusOK, measurements)
+}
+
+func (h MeasurementHandler) GetMeasurement(ctx *gin.Context) {
+	measurementUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measurement, err := h.dao.GetMeasurement(ctx.Request.Context(), measurementUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusOK, measurement)
+	}
+}
+
+func (h MeasurementHandler) UpdateMeasurement(ctx *gin.Context) {
+	measurementUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req, ok := bindMeasurementRequest(ctx)
+	if !ok {
+		return
+	}
+
+	measurement, err := h.dao.UpdateMeasurement(ctx.Request.Context(), measurementUuid, req)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		h.respond(ctx, http.StatusOK, measurement)
+	}
+}
+
+func (h MeasurementHandler) DeleteMeasurement(ctx *gin.Context) {
+	measurementUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.dao.DeleteMeasurement(ctx.Request.Context(), measurementUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// respond writes measurement in the units its user prefers.
+func (h MeasurementHandler) respond(ctx *gin.Context, status int, measurement *model.Measurement) {
+	converter, err := service.PreferredUnits(ctx.Request.Context(), h.users, measurement.UserUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	measurement.Convert(converter)
+	ctx.JSON(status, measurement)
+}
+
+func bindMeasurementRequest(ctx *gin.Context) (*model.MeasurementRequest, bool) {
+	var req model.MeasurementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err := req.Normalize(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &req, true
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMeasurementDao is a mock implementation of the MeasurementDaoInterface
+type MockMeasurementDao struct {
+	mock.Mock
+}
+
+func (m *MockMeasurementDao) CreateMeasurement(ctx context.Context, userUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error) {
+	args := m.Called(userUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementDao) ListMeasurements(ctx context.Context, userUuid uuid.UUID, kind model.MeasurementKind, site string, from, to time.Time) ([]model.Measurement, error) {
+	args := m.Called(userUuid, kind, site, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementDao) GetMeasurement(ctx context.Context, measurementUuid uuid.UUID) (*model.Measurement, error) {
+	args := m.Called(measurementUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementDao) UpdateMeasurement(ctx context.Context, measurementUuid uuid.UUID, req *model.MeasurementRequest) (*model.Measurement, error) {
+	args := m.Called(measurementUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementDao) DeleteMeasurement(ctx context.Context, measurementUuid uuid.UUID) error {
+	args := m.Called(measurementUuid)
+	return args.Error(0)
+}
+
+func newMeasurementRouter(dao *MockMeasurementDao) *gin.Engine {
+	return newMeasurementRouterFor(dao, usersPreferring(units.Metric))
+}
+
+func newMeasurementRouterFor(dao *MockMeasurementDao, users *MockUserDao) *gin.Engine {
+	handler := NewMeasurementHandler(dao, users)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/measurements", handler.GetMeasurements)
+	router.POST("/users/:uuid/measurements", handler.CreateMeasurement)
+	router.GET("/measurements/:uuid", handler.GetMeasurement)
+	router.PUT("/measurements/:uuid", handler.UpdateMeasurement)
+	router.DELETE("/measurements/:uuid", handler.DeleteMeasurement)
+	return router
+}
+
+func TestCreateMeasurement(t *testing.T) {
+	measurementDao := new(MockMeasurementDao)
+	router := newMeasurementRouterFor(measurementDao, usersPreferring(units.Imperial))
+
+	userUuid := uuid.New()
+	measuredAt := time.Date(2025, 3, 1, 7, 0, 0, 0, time.UTC)
+	fields := model.MeasurementFields{Kind: model.MeasurementBodyweight, Value: 81.65, MeasuredAt: measuredAt, EnteredUnit: "lb"}
+	measurementDao.On("CreateMeasurement", userUuid, &model.MeasurementRequest{MeasurementFields: fields, Unit: "kg"}).
+		Return(&model.Measurement{UserUuid: userUuid, MeasurementFields: fields, Unit: "kg"}, nil)
+
+	body := `{"kind":"bodyweight","value":180,"unit":"lb","measuredAt":"2025-03-01T08:00:00+01:00"}`
+	r, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/measurements", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"value":180.01,`)
+	assert.Contains(t, w.Body.String(), `"unit":"lb"`)
+	measurementDao.AssertExpectations(t)
+}
+
+func TestCreateMeasurement_Invalid(t *testing.T) {
+	measurementDao := new(MockMeasurementDao)
+	router := newMeasurementRouter(measurementDao)
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"kind":"height","value":180,"measuredAt":"2025-03-01T07:00:00Z"}`, `unknown measurement kind \"height\"`},
+		{`{"kind":"circumference","value":80,"measuredAt":"2025-03-01T07:00:00Z"}`, "site must be one of NECK, CHEST, WAIST, HIPS, ARM, FOREARM, THIGH, CALF"},
+		{`{"kind":"bodyweight","site":"WAIST","value":80,"measuredAt":"2025-03-01T07:00:00Z"}`, "only circumferences have a site"},
+		{`{"kind":"bodyweight","value":80}`, "measuredAt is required"},
+		{`{"kind":"bodyweight","value":80,"unit":"in","measuredAt":"2025-03-01T07:00:00Z"}`, `unit \"in\" does not apply to bodyweight`},
+		{`{"kind":"body_fat","value":0,"measuredAt":"2025-03-01T07:00:00Z"}`, "value must be positive"},
+		{`{"kind":"body_fat","value":100,"measuredAt":"2025-03-01T07:00:00Z"}`, "body fat must be below 100%"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/measurements", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	measurementDao.AssertNotCalled(t, "CreateMeasurement")
+}
+
+func TestGetMeasurements(t *testing.T) {
+	measurementDao := new(MockMeasurementDao)
+	router := newMeasurementRouter(measurementDao)
+
+	userUuid := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	measurementDao.On("ListMeasurements", userUuid, model.MeasurementCircumference, "WAIST", from, to).
+		Return([]model.Measurement{{UserUuid: userUuid, Unit: "cm"}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+
+		"/measurements?kind=circumference&site=waist&from=2025-01-01T00:00:00Z&to=2025-04-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"unit":"cm"`)
+	measurementDao.AssertExpectations(t)
+
+	r, _ = http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/measurements?kind=height", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateMeasurement_NotFound(t *testing.T) {
+	measurementDao := new(MockMeasurementDao)
+	router := newMeasurementRouter(measurementDao)
+
+	measurementDao.On("UpdateMeasurement", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("measurement %w", dao.ErrNotFound))
+
+	body := `{"kind":"circumference","site":"waist","value":33,"unit":"in","measuredAt":"2025-03-01T07:00:00Z"}`
+	r, _ := http.NewRequest(http.MethodPut, "/measurements/"+uuid.NewString(), strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	req := measurementDao.Calls[0].Arguments.Get(1).(*model.MeasurementRequest)
+	assert.Equal(t, "WAIST", req.Site)
+	assert.Equal(t, 83.82, req.Value)
+}
+
+func TestDeleteMeasurement(t *testing.T) {
+	measurementDao := new(MockMeasurementDao)
+	router := newMeasurementRouter(measurementDao)
+
+	measurementDao.On("DeleteMeasurement", mock.Anything).Return(nil).Once()
+	measurementDao.On("DeleteMeasurement", mock.Anything).Return(fmt.Errorf("measurement %w", dao.ErrNotFound)).Once()
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		r, _ := http.NewRequest(http.MethodDelete, "/measurements/"+uuid.NewString(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/planner"
+)
+
+type PlannerHandler struct {
+	planner planner.PlannerInterface
+}
+
+func NewPlannerHandler(planner planner.PlannerInterface) *PlannerHandler {
+	return &PlannerHandler{planner: planner}
+}
+
+// CreateSessionPlan builds a session for the user in the path from the
+// constraints in the body. The plan is not stored; its session can be posted
+// to the workouts of the user.
+func (h PlannerHandler) CreateSessionPlan(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req model.SessionPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Experience == "" {
+		req.Experience = model.ExperienceBeginner
+	}
+	if req.TimeBudgetMinutes == 0 {
+		req.TimeBudgetMinutes = planner.DefaultTimeBudgetMinutes
+	}
+	switch {
+	case !req.Experience.Valid():
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown experience level %q", req.Experience)})
+		return
+	case req.TimeBudgetMinutes < planner.MinTimeBudgetMinutes || req.TimeBudgetMinutes > planner.MaxTimeBudgetMinutes:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("timeBudgetMinutes must be between %d and %d",
+			planner.MinTimeBudgetMinutes, planner.MaxTimeBudgetMinutes)})
+		return
+	}
+
+	plan, err := h.planner.Plan(ctx.Request.Context(), userUuid, &req, time.Now().UTC())
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, planner.ErrNoExercises):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, plan)
+	}
+}
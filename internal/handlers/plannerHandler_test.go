@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/planner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPlanner is a mock implementation of the planner.PlannerInterface
+type MockPlanner struct {
+	mock.Mock
+}
+
+func (m *MockPlanner) Plan(ctx context.Context, userUuid uuid.UUID, req *model.SessionPlanRequest, now time.Time) (*model.SessionPlan, error) {
+	args := m.Called(userUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SessionPlan), args.Error(1)
+}
+
+func newPlannerRouter(planner *MockPlanner) *gin.Engine {
+	handler := NewPlannerHandler(planner)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/users/:uuid/session-plans", handler.CreateSessionPlan)
+	return router
+}
+
+func TestCreateSessionPlan(t *testing.T) {
+	sessionPlanner := new(MockPlanner)
+	router := newPlannerRouter(sessionPlanner)
+
+	userUuid := uuid.New()
+	seed := uint64(42)
+	req := &model.SessionPlanRequest{
+		TargetMuscles:     []string{"LEGS"},
+		TimeBudgetMinutes: planner.DefaultTimeBudgetMinutes,
+		Experience:        model.ExperienceBeginner,
+		Seed:              &seed,
+	}
+	sessionPlanner.On("Plan", userUuid, req).Return(&model.SessionPlan{Seed: seed}, nil)
+
+	r, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/session-plans",
+		strings.NewReader(`{"targetMuscles":["LEGS"],"seed":42}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"seed":42`)
+	sessionPlanner.AssertExpectations(t)
+}
+
+func TestCreateSessionPlan_Invalid(t *testing.T) {
+	sessionPlanner := new(MockPlanner)
+	router := newPlannerRouter(sessionPlanner)
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"experience":"elite"}`, `unknown experience level \"elite\"`},
+		{`{"timeBudgetMinutes":5}`, "timeBudgetMinutes must be between 10 and 180"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/session-plans", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	sessionPlanner.AssertNotCalled(t, "Plan")
+}
+
+func TestCreateSessionPlan_Errors(t *testing.T) {
+	sessionPlanner := new(MockPlanner)
+	router := newPlannerRouter(sessionPlanner)
+
+	sessionPlanner.On("Plan", mock.Anything, mock.Anything).Return(nil, planner.ErrNoExercises).Once()
+	sessionPlanner.On("Plan", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("equipment profile %w", dao.ErrNotFound)).Once()
+
+	for _, want := range []int{http.StatusUnprocessableEntity, http.StatusNotFound} {
+		r, _ := http.NewRequest(http.MethodPost, "/users/"+uuid.NewString()+"/session-plans", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code)
+	}
+}
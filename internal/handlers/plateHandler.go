@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/plates"
+)
+
+// PlateHandler serves the plate and warm-up calculators, which need no
+// stored data.
+type PlateHandler struct{}
+
+func NewPlateHandler() *PlateHandler {
+	return &PlateHandler{}
+}
+
+// CalculatePlates returns the plates for each side of the bar that load it
+// closest to the target.
+func (h PlateHandler) CalculatePlates(ctx *gin.Context) {
+	var req model.PlateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	barbell, err := plates.NewBarbell(req.Unit, req.Bar, req.Plates)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loading, err := barbell.Load(req.Target)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, loading)
+}
+
+// CalculateWarmup returns the warm-up sets before a working set, each with
+// its plates.
+func (h PlateHandler) CalculateWarmup(ctx *gin.Context) {
+	var req model.WarmupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	barbell, err := plates.NewBarbell(req.Unit, req.Bar, req.Plates)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	warmup, err := barbell.Warmup(req.WorkingLoad, req.Reps)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, warmup)
+}
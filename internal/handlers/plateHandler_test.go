@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPlateRouter() *gin.Engine {
+	handler := NewPlateHandler()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/calculators/plates", handler.CalculatePlates)
+	router.POST("/calculators/warmup", handler.CalculateWarmup)
+	return router
+}
+
+func TestCalculatePlates(t *testing.T) {
+	router := newPlateRouter()
+
+	body := `{"target":102.5,"bar":15,"plates":[{"weight":20,"count":4},{"weight":2.5,"count":2},{"weight":1.25,"count":2}]}`
+	r, _ := http.NewRequest(http.MethodPost, "/calculators/plates", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"target":102.5,"bar":15,"total":102.5,"exact":true,"unit":"kg",
+		"perSide":[{"weight":20,"count":2},{"weight":2.5,"count":1},{"weight":1.25,"count":1}]}`, w.Body.String())
+}
+
+func TestCalculatePlates_Invalid(t *testing.T) {
+	router := newPlateRouter()
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"target":10}`, "load is below the bar: 10 kg is below the 20 kg bar"},
+		{`{"target":100,"unit":"stone"}`, `unknown weight unit \"stone\"`},
+		{`{"target":100,"plates":[{"weight":20,"count":1000}]}`, "at most 300 plates are supported"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/calculators/plates", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+}
+
+func TestCalculatePlates_LargeInventory(t *testing.T) {
+	router := newPlateRouter()
+
+	plates := make([]string, 0, 12)
+	for _, weight := range []string{"50", "45", "25", "20", "15", "10", "5", "2.5", "2", "1.25", "1", "0.5"} {
+		plates = append(plates, `{"weight":`+weight+`,"count":25}`)
+	}
+	body := `{"target":1000.5,"plates":[` + strings.Join(plates, ",") + `]}`
+	r, _ := http.NewRequest(http.MethodPost, "/calculators/plates", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	start := time.Now()
+	router.ServeHTTP(w, r)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"exact":true`)
+}
+
+func TestCalculateWarmup(t *testing.T) {
+	router := newPlateRouter()
+
+	r, _ := http.NewRequest(http.MethodPost, "/calculators/warmup", strings.NewReader(`{"workingLoad":315,"reps":5,"unit":"lb"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{"load":45,"reps":10,"percent":14,"perSide":[]}`)
+	assert.Contains(t, w.Body.String(), `{"load":185,"reps":3,"percent":59,"perSide":[{"weight":45,"count":1},{"weight":25,"count":1}]}`)
+
+	r, _ = http.NewRequest(http.MethodPost, "/calculators/warmup", strings.NewReader(`{"workingLoad":100}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"reps must be positive"}`, w.Body.String())
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/recovery"
+)
+
+type RecoveryHandler struct {
+	tracker recovery.TrackerInterface
+}
+
+func NewRecoveryHandler(tracker recovery.TrackerInterface) *RecoveryHandler {
+	return &RecoveryHandler{tracker: tracker}
+}
+
+// GetRecovery returns the fatigue and freshness of every muscle of the user
+// in the path, for a body heat map.
+func (h RecoveryHandler) GetRecovery(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.tracker.Report(ctx.Request.Context(), userUuid, time.Now().UTC())
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRecoveryTracker is a mock implementation of the recovery.TrackerInterface
+type MockRecoveryTracker struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryTracker) Report(ctx context.Context, userUuid uuid.UUID, now time.Time) (*model.RecoveryReport, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RecoveryReport), args.Error(1)
+}
+
+func newRecoveryRouter(tracker *MockRecoveryTracker) *gin.Engine {
+	handler := NewRecoveryHandler(tracker)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/recovery", handler.GetRecovery)
+	return router
+}
+
+func TestGetRecovery(t *testing.T) {
+	tracker := new(MockRecoveryTracker)
+	router := newRecoveryRouter(tracker)
+
+	userUuid := uuid.New()
+	tracker.On("Report", userUuid).Return(&model.RecoveryReport{UserUuid: userUuid, Muscles: []model.MuscleRecovery{
+		{MuscleCode: "QUAD", Fatigue: 6, Freshness: 0.5, Status: model.RecoveryRecovering},
+	}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/recovery", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"muscleCode":"QUAD"`)
+	assert.Contains(t, w.Body.String(), `"freshness":0.5`)
+	tracker.AssertExpectations(t)
+}
+
+func TestGetRecovery_Errors(t *testing.T) {
+	tracker := new(MockRecoveryTracker)
+	router := newRecoveryRouter(tracker)
+
+	tracker.On("Report", mock.Anything).Return(nil, errors.New("database is down"))
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid/recovery", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	r, _ = http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/recovery", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+)
+
+// ReferenceHandler lists the muscles, categories and apparatus exercises are
+// described with, in the language negotiated from the Accept-Language header.
+type ReferenceHandler struct {
+	references service.ReferenceServiceInterface
+}
+
+func NewReferenceHandler(references service.ReferenceServiceInterface) *ReferenceHandler {
+	return &ReferenceHandler{references: references}
+}
+
+func (h ReferenceHandler) GetMuscles(ctx *gin.Context) {
+	muscles, locale, err := h.references.Muscles(ctx.Request.Context(), ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Language", locale)
+	ctx.JSON(http.StatusOK, muscles)
+}
+
+// GetMuscleTree returns the muscles as a hierarchy of regions, groups,
+// muscles and heads.
+func (h ReferenceHandler) GetMuscleTree(ctx *gin.Context) {
+	tree, locale, err := h.references.MuscleTree(ctx.Request.Context(), ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Language", locale)
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// GetMuscleExercises lists the exercises that work the muscle in the path or
+// any muscle below it, optionally only in the role query parameter.
+func (h ReferenceHandler) GetMuscleExercises(ctx *gin.Context) {
+	exercises, err := h.references.MuscleExercises(ctx.Request.Context(), ctx.Param("code"),
+		model.MuscleRole(ctx.Query("role")))
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, exercises)
+	}
+}
+
+func (h ReferenceHandler) GetCategories(ctx *gin.Context) {
+	categories, locale, err := h.references.Categories(ctx.Request.Context(), ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Language", locale)
+	ctx.JSON(http.StatusOK, categories)
+}
+
+func (h ReferenceHandler) GetApparatus(ctx *gin.Context) {
+	apparatus, locale, err := h.references.Apparatus(ctx.Request.Context(), ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Language", locale)
+	ctx.JSON(http.StatusOK, apparatus)
+}
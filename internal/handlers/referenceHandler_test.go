@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReferenceHandler(daos *dao.Daos) *ReferenceHandler {
+	return NewReferenceHandler(service.NewReferenceService(daos.Muscles, daos.Categories, daos.Apparatus, daos.Translations))
+}
+
+func TestGetMuscles_Localized(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newReferenceHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/muscles", handler.GetMuscles)
+
+	require.NoError(t, daos.Translations.SaveReferenceTranslation(context.Background(), &model.ReferenceTranslation{
+		Kind: model.ReferenceMuscle, Code: "GLUTES", Locale: "de", Name: "Gesäßmuskel"}))
+
+	r, _ := http.NewRequest(http.MethodGet, "/muscles", nil)
+	r.Header.Set("Accept-Language", "de-DE")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "de", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Body.String(), `"muscleName":"Gesäßmuskel","muscleDesc":"Muscles of the buttocks"`)
+	assert.Contains(t, w.Body.String(), `"muscleName":"Hamstrings"`)
+}
+
+func TestGetMuscles_DefaultLanguage(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newReferenceHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/muscles", handler.GetMuscles)
+
+	require.NoError(t, daos.Translations.SaveReferenceTranslation(context.Background(), &model.ReferenceTranslation{
+		Kind: model.ReferenceMuscle, Code: "GLUTES", Locale: "de", Name: "Gesäßmuskel"}))
+
+	r, _ := http.NewRequest(http.MethodGet, "/muscles", nil)
+	r.Header.Set("Accept-Language", "en-GB, de;q=0.5")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Body.String(), `"muscleName":"Glutes"`)
+	assert.NotContains(t, w.Body.String(), "Gesäßmuskel")
+}
+
+func TestGetMuscleTree(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newReferenceHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/muscles/tree", handler.GetMuscleTree)
+
+	for code, name := range map[string]string{"LEGS": "Beine", "RECTUS_FEMORIS": "Gerader Oberschenkelmuskel"} {
+		require.NoError(t, daos.Translations.SaveReferenceTranslation(context.Background(), &model.ReferenceTranslation{
+			Kind: model.ReferenceMuscle, Code: code, Locale: "de", Name: name}))
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/muscles/tree", nil)
+	r.Header.Set("Accept-Language", "de")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tree []*model.MuscleNode
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+	require.Len(t, tree, 1)
+	assert.Equal(t, "Beine", tree[0].MuscleName)
+	var quads *model.MuscleNode
+	for _, node := range tree[0].Children {
+		if node.MuscleCode == "QUADS" {
+			quads = node
+		}
+	}
+	require.NotNil(t, quads)
+	assert.Equal(t, "Quadriceps", quads.MuscleName)
+	require.Len(t, quads.Children, 1)
+	assert.Equal(t, "Gerader Oberschenkelmuskel", quads.Children[0].MuscleName)
+	assert.Equal(t, "QUADS", *quads.Children[0].ParentCode)
+}
+
+func TestGetMuscleExercises(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newReferenceHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/muscles/:code/exercises", handler.GetMuscleExercises)
+
+	squat := createExercise(t, daos, "Squat", "RECTUS_FEMORIS")
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/muscles/legs/exercises?role=primary", http.StatusOK},
+		{"/muscles/WINGS/exercises", http.StatusNotFound},
+		{"/muscles/LEGS/exercises?role=main", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.status, w.Code, tt.path)
+		if w.Code == http.StatusOK {
+			var exercises []model.MuscleExercise
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &exercises))
+			require.Len(t, exercises, 1)
+			assert.Equal(t, squat, exercises[0].ExerciseUuid)
+			assert.Equal(t, "RECTUS_FEMORIS", exercises[0].MuscleCode)
+		}
+	}
+}
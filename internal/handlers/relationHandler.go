@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+const (
+	// defaultProgressionSteps is how far the progression graph is walked
+	// without a maxSteps query parameter.
+	defaultProgressionSteps = 3
+	maxProgressionSteps     = 10
+)
+
+type RelationHandler struct {
+	dao dao.RelationDaoInterface
+}
+
+func NewRelationHandler(dao dao.RelationDaoInterface) *RelationHandler {
+	return &RelationHandler{dao: dao}
+}
+
+// GetRelations lists the edges from and to the exercise in the path.
+func (h RelationHandler) GetRelations(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	relations, err := h.dao.GetRelations(ctx.Request.Context(), exUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, relations)
+}
+
+// CreateRelation adds an edge from the exercise in the path to the related
+// exercise in the body.
+func (h RelationHandler) CreateRelation(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req model.ExerciseRelationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch {
+	case req.RelatedUuid == uuid.Nil || req.CreatedBy == uuid.Nil:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "relatedUuid and createdBy are required"})
+		return
+	case !req.Relation.Valid():
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown relation %q", req.Relation)})
+		return
+	case req.RelatedUuid == exUuid:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "an exercise cannot be related to itself"})
+		return
+	}
+
+	relation, err := h.dao.CreateRelation(ctx.Request.Context(), exUuid, &req)
+	switch {
+	case errors.Is(err, dao.ErrConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusCreated, relation)
+	}
+}
+
+// DeleteRelation removes the edge of the relation query parameter between
+// the exercises in the path.
+func (h RelationHandler) DeleteRelation(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	relatedUuid, err := uuid.Parse(ctx.Param("relatedUuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	relation := model.RelationType(ctx.Query("relation"))
+	if !relation.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown relation %q", relation)})
+		return
+	}
+
+	err = h.dao.DeleteRelation(ctx.Request.Context(), exUuid, relatedUuid, relation)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// GetEasier suggests easier exercises along the regressions of the exercise
+// in the path.
+func (h RelationHandler) GetEasier(ctx *gin.Context) {
+	h.progressions(ctx, model.DirectionEasier)
+}
+
+// GetHarder suggests harder exercises along the progressions of the exercise
+// in the path.
+func (h RelationHandler) GetHarder(ctx *gin.Context) {
+	h.progressions(ctx, model.DirectionHarder)
+}
+
+// progressions walks the graph up to the maxSteps query parameter. Unless
+// sameMuscles is false only exercises that train the same primary muscles
+// are suggested, and the progressions of alternatives are included.
+func (h RelationHandler) progressions(ctx *gin.Context, direction model.Direction) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxSteps := defaultProgressionSteps
+	if value := ctx.Query("maxSteps"); value != "" {
+		if maxSteps, err = strconv.Atoi(value); err != nil || maxSteps < 1 || maxSteps > maxProgressionSteps {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("maxSteps must be between 1 and %d", maxProgressionSteps)})
+			return
+		}
+	}
+	sameMuscles := true
+	if value := ctx.Query("sameMuscles"); value != "" {
+		if sameMuscles, err = strconv.ParseBool(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "sameMuscles must be a boolean"})
+			return
+		}
+	}
+
+	related, err := h.dao.FindProgressions(ctx.Request.Context(), exUuid, direction, maxSteps, sameMuscles)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, related)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRelationDao is a mock implementation of the RelationDaoInterface
+type MockRelationDao struct {
+	mock.Mock
+}
+
+func (m *MockRelationDao) CreateRelation(ctx context.Context, exUuid uuid.UUID, req *model.ExerciseRelationRequest) (*model.ExerciseRelation, error) {
+	args := m.Called(exUuid, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ExerciseRelation), args.Error(1)
+}
+
+func (m *MockRelationDao) GetRelations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseRelation, error) {
+	args := m.Called(exUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExerciseRelation), args.Error(1)
+}
+
+func (m *MockRelationDao) DeleteRelation(ctx context.Context, exUuid, relatedUuid uuid.UUID, relation model.RelationType) error {
+	args := m.Called(exUuid, relatedUuid, relation)
+	return args.Error(0)
+}
+
+func (m *MockRelationDao) FindProgressions(ctx context.Context, exUuid uuid.UUID, direction model.Direction, maxSteps int, samePrimaryMuscles bool) ([]model.RelatedExercise, error) {
+	args := m.Called(exUuid, direction, maxSteps, samePrimaryMuscles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RelatedExercise), args.Error(1)
+}
+
+func newRelationRouter(dao *MockRelationDao) *gin.Engine {
+	handler := NewRelationHandler(dao)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/:uuid/relations", handler.GetRelations)
+	router.POST("/exercises/:uuid/relations", handler.CreateRelation)
+	router.DELETE("/exercises/:uuid/relations/:relatedUuid", handler.DeleteRelation)
+	router.GET("/exercises/:uuid/easier", handler.GetEasier)
+	router.GET("/exercises/:uuid/harder", handler.GetHarder)
+	return router
+}
+
+func TestCreateRelation(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	exUuid, relatedUuid, createdBy := uuid.New(), uuid.New(), uuid.New()
+	req := &model.ExerciseRelationRequest{RelatedUuid: relatedUuid, Relation: model.RelationProgressionOf, CreatedBy: createdBy}
+	relationDao.On("CreateRelation", exUuid, req).Return(&model.ExerciseRelation{ExerciseUuid: exUuid}, nil)
+
+	body := fmt.Sprintf(`{"relatedUuid":"%s","relation":"progression_of","createdBy":"%s"}`, relatedUuid, createdBy)
+	r, _ := http.NewRequest(http.MethodPost, "/exercises/"+exUuid.String()+"/relations", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	relationDao.AssertExpectations(t)
+}
+
+func TestCreateRelation_Invalid(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	exUuid, createdBy := uuid.New(), uuid.New()
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"relation":"progression_of"}`, "relatedUuid and createdBy are required"},
+		{fmt.Sprintf(`{"relatedUuid":"%s","relation":"harder_than","createdBy":"%s"}`, uuid.New(), createdBy), `unknown relation \"harder_than\"`},
+		{fmt.Sprintf(`{"relatedUuid":"%s","relation":"variation_of","createdBy":"%s"}`, exUuid, createdBy), "an exercise cannot be related to itself"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/exercises/"+exUuid.String()+"/relations", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	relationDao.AssertNotCalled(t, "CreateRelation")
+}
+
+func TestCreateRelation_Conflict(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	relationDao.On("CreateRelation", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("relation %w", dao.ErrConflict)).Once()
+	relationDao.On("CreateRelation", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("exercise %w", dao.ErrNotFound)).Once()
+
+	body := fmt.Sprintf(`{"relatedUuid":"%s","relation":"alternative_to","createdBy":"%s"}`, uuid.New(), uuid.New())
+	for _, want := range []int{http.StatusConflict, http.StatusNotFound} {
+		r, _ := http.NewRequest(http.MethodPost, "/exercises/"+uuid.NewString()+"/relations", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestGetRelations(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	exUuid := uuid.New()
+	relationDao.On("GetRelations", exUuid).Return([]model.ExerciseRelation{{ExerciseUuid: exUuid}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String()+"/relations", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	relationDao.AssertExpectations(t)
+}
+
+func TestDeleteRelation(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	exUuid, relatedUuid := uuid.New(), uuid.New()
+	relationDao.On("DeleteRelation", exUuid, relatedUuid, model.RelationRegressionOf).Return(nil)
+
+	url := fmt.Sprintf("/exercises/%s/relations/%s?relation=regression_of", exUuid, relatedUuid)
+	r, _ := http.NewRequest(http.MethodDelete, url, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	r, _ = http.NewRequest(http.MethodDelete, fmt.Sprintf("/exercises/%s/relations/%s", exUuid, relatedUuid), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	relationDao.AssertExpectations(t)
+}
+
+func TestGetEasierAndHarder(t *testing.T) {
+	relationDao := new(MockRelationDao)
+	router := newRelationRouter(relationDao)
+
+	exUuid := uuid.New()
+	relationDao.On("FindProgressions", exUuid, model.DirectionEasier, 3, true).Return([]model.RelatedExercise{{ExerciseName: "Knee Push-up", Steps: 1}}, nil)
+	relationDao.On("FindProgressions", exUuid, model.DirectionHarder, 5, false).Return([]model.RelatedExercise{}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String()+"/easier", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Knee Push-up")
+
+	r, _ = http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String()+"/harder?maxSteps=5&sameMuscles=false", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r, _ = http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String()+"/easier?maxSteps=50", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"maxSteps must be between 1 and 10"}`, w.Body.String())
+	relationDao.AssertExpectations(t)
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/i18n"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+)
+
+const (
+	// defaultSearchLimit is the number of exercises a search returns without
+	// a limit query parameter.
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type TranslationHandler struct {
+	dao dao.TranslationDaoInterface
+}
+
+func NewTranslationHandler(dao dao.TranslationDaoInterface) *TranslationHandler {
+	return &TranslationHandler{dao: dao}
+}
+
+// SearchExercises finds exercises whose name, alias or translation contains
+// the q query parameter. Names are returned in the language negotiated from
+// the Accept-Language header.
+func (h TranslationHandler) SearchExercises(ctx *gin.Context) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit := defaultSearchLimit
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxSearchLimit {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)})
+			return
+		}
+	}
+
+	locale, err := contentLocale(ctx, h.dao)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+
+	results, err := h.dao.SearchExercises(ctx.Request.Context(), query, locale, limit)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// GetLocales lists the languages there are translations for, the default
+// language first.
+func (h TranslationHandler) GetLocales(ctx *gin.Context) {
+	locales, err := h.dao.ListLocales(ctx.Request.Context())
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+
+	all := []string{i18n.DefaultLocale}
+	for _, locale := range locales {
+		if locale != i18n.DefaultLocale {
+			all = append(all, locale)
+		}
+	}
+	ctx.JSON(http.StatusOK, all)
+}
+
+// GetTranslations lists the translations of the exercise in the path.
+func (h TranslationHandler) GetTranslations(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translations, err := h.dao.GetExerciseTranslations(ctx.Request.Context(), exUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, translations)
+}
+
+// SaveTranslation creates or replaces the translation of the exercise in the
+// path into the locale in the path.
+func (h TranslationHandler) SaveTranslation(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	locale, ok := translationLocale(ctx)
+	if !ok {
+		return
+	}
+
+	var tr model.ExerciseTranslation
+	if err := ctx.ShouldBindJSON(&tr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(tr.ExerciseName) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "exerciseName is required"})
+		return
+	}
+	tr.Locale = locale
+
+	err = h.dao.SaveExerciseTranslation(ctx.Request.Context(), exUuid, &tr)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, tr)
+	}
+}
+
+// DeleteTranslation removes the translation of the exercise in the path into
+// the locale in the path.
+func (h TranslationHandler) DeleteTranslation(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	locale, ok := translationLocale(ctx)
+	if !ok {
+		return
+	}
+
+	err = h.dao.DeleteExerciseTranslation(ctx.Request.Context(), exUuid, locale)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// GetAliases lists the other names of the exercise in the path.
+func (h TranslationHandler) GetAliases(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	aliases, err := h.dao.GetAliases(ctx.Request.Context(), exUuid)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, aliases)
+}
+
+// AddAlias adds another name to the exercise in the path. The optional
+// locale says which language the alias belongs to.
+func (h TranslationHandler) AddAlias(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var alias model.ExerciseAlias
+	if err := ctx.ShouldBindJSON(&alias); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if alias.Alias = strings.TrimSpace(alias.Alias); alias.Alias == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "alias is required"})
+		return
+	}
+	if alias.Locale != "" {
+		if alias.Locale, err = i18n.NormalizeLocale(alias.Locale); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err = h.dao.AddAlias(ctx.Request.Context(), exUuid, &alias)
+	switch {
+	case errors.Is(err, dao.ErrConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusCreated, alias)
+	}
+}
+
+// DeleteAlias removes the alias query parameter from the exercise in the
+// path. Aliases are free text, so they are not part of the path.
+func (h TranslationHandler) DeleteAlias(ctx *gin.Context) {
+	exUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	alias := ctx.Query("alias")
+	if alias == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "alias is required"})
+		return
+	}
+
+	err = h.dao.DeleteAlias(ctx.Request.Context(), exUuid, alias)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// SaveReferenceTranslation creates or replaces the name of a muscle,
+// category or apparatus, e.g. PUT /translations/muscles/GLUTES/de.
+func (h TranslationHandler) SaveReferenceTranslation(ctx *gin.Context) {
+	kind, err := model.ParseReferenceKind(ctx.Param("kind"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	locale, ok := translationLocale(ctx)
+	if !ok {
+		return
+	}
+
+	var tr model.ReferenceTranslation
+	if err := ctx.ShouldBindJSON(&tr); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(tr.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	tr.Kind, tr.Code, tr.Locale = kind, ctx.Param("code"), locale
+
+	err = h.dao.SaveReferenceTranslation(ctx.Request.Context(), &tr)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, tr)
+	}
+}
+
+// translationLocale normalizes the locale in the path. The default language
+// is stored in the catalog itself and cannot be translated into.
+func translationLocale(ctx *gin.Context) (string, bool) {
+	locale, err := i18n.NormalizeLocale(ctx.Param("locale"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	if locale == i18n.DefaultLocale {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is the default language, update the catalog instead", locale)})
+		return "", false
+	}
+	return locale, true
+}
+
+// contentLocale negotiates the Accept-Language header against the languages
+// there are translations for and sets the Content-Language of the response.
+// Without the header the default language is used without a lookup.
+func contentLocale(ctx *gin.Context, translations dao.TranslationDaoInterface) (string, error) {
+	locale, err := service.ContentLocale(ctx.Request.Context(), translations, ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		return "", err
+	}
+	ctx.Header("Content-Language", locale)
+	return locale, nil
+}
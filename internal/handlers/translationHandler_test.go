@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTranslationDao is a mock implementation of the TranslationDaoInterface
+type MockTranslationDao struct {
+	mock.Mock
+}
+
+func (m *MockTranslationDao) ListLocales(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTranslationDao) GetExerciseTranslations(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseTranslation, error) {
+	args := m.Called(exUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExerciseTranslation), args.Error(1)
+}
+
+func (m *MockTranslationDao) SaveExerciseTranslation(ctx context.Context, exUuid uuid.UUID, tr *model.ExerciseTranslation) error {
+	args := m.Called(exUuid, tr)
+	return args.Error(0)
+}
+
+func (m *MockTranslationDao) DeleteExerciseTranslation(ctx context.Context, exUuid uuid.UUID, locale string) error {
+	args := m.Called(exUuid, locale)
+	return args.Error(0)
+}
+
+func (m *MockTranslationDao) GetAliases(ctx context.Context, exUuid uuid.UUID) ([]model.ExerciseAlias, error) {
+	args := m.Called(exUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExerciseAlias), args.Error(1)
+}
+
+func (m *MockTranslationDao) AddAlias(ctx context.Context, exUuid uuid.UUID, alias *model.ExerciseAlias) error {
+	args := m.Called(exUuid, alias)
+	return args.Error(0)
+}
+
+func (m *MockTranslationDao) DeleteAlias(ctx context.Context, exUuid uuid.UUID, alias string) error {
+	args := m.Called(exUuid, alias)
+	return args.Error(0)
+}
+
+func (m *MockTranslationDao) GetReferenceTranslations(ctx context.Context, kind model.ReferenceKind, locale string) (map[string]model.ReferenceTranslation, error) {
+	args := m.Called(kind, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]model.ReferenceTranslation), args.Error(1)
+}
+
+func (m *MockTranslationDao) SaveReferenceTranslation(ctx context.Context, tr *model.ReferenceTranslation) error {
+	args := m.Called(tr)
+	return args.Error(0)
+}
+
+func (m *MockTranslationDao) SearchExercises(ctx context.Context, query, locale string, limit int) ([]model.ExerciseSearchResult, error) {
+	args := m.Called(query, locale, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExerciseSearchResult), args.Error(1)
+}
+
+func newTranslationRouter(dao *MockTranslationDao) *gin.Engine {
+	handler := NewTranslationHandler(dao)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/search", handler.SearchExercises)
+	router.GET("/exercises/:uuid/translations", handler.GetTranslations)
+	router.PUT("/exercises/:uuid/translations/:locale", handler.SaveTranslation)
+	router.DELETE("/exercises/:uuid/translations/:locale", handler.DeleteTranslation)
+	router.GET("/exercises/:uuid/aliases", handler.GetAliases)
+	router.POST("/exercises/:uuid/aliases", handler.AddAlias)
+	router.DELETE("/exercises/:uuid/aliases", handler.DeleteAlias)
+	router.GET("/translations/locales", handler.GetLocales)
+	router.PUT("/translations/:kind/:code/:locale", handler.SaveReferenceTranslation)
+	return router
+}
+
+func TestSearchExercises(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	results := []model.ExerciseSearchResult{{
+		ExerciseUuid: uuid.New(), ExerciseName: "Rumänisches Kreuzheben", MatchedBy: model.MatchedByAlias, Match: "RDL",
+	}}
+	translationDao.On("ListLocales").Return([]string{"de", "fr"}, nil)
+	translationDao.On("SearchExercises", "RDL", "de", defaultSearchLimit).Return(results, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/exercises/search?q=RDL", nil)
+	r.Header.Set("Accept-Language", "de-AT,de;q=0.9,en;q=0.5")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "de", w.Header().Get("Content-Language"))
+	var found []model.ExerciseSearchResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	assert.Equal(t, results, found)
+	translationDao.AssertExpectations(t)
+}
+
+func TestSearchExercises_DefaultLanguage(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	translationDao.On("SearchExercises", "squat", "en", 5).Return([]model.ExerciseSearchResult{}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/exercises/search?q=squat&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	translationDao.AssertNotCalled(t, "ListLocales")
+}
+
+func TestSearchExercises_BadRequest(t *testing.T) {
+	router := newTranslationRouter(new(MockTranslationDao))
+
+	for _, query := range []string{"", "?q=%20", "?q=rdl&limit=0", "?q=rdl&limit=101", "?q=rdl&limit=ten"} {
+		r, _ := http.NewRequest(http.MethodGet, "/exercises/search"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetLocales(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	translationDao.On("ListLocales").Return([]string{"de", "en", "pt-BR"}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/translations/locales", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `["en","de","pt-BR"]`, w.Body.String())
+}
+
+func TestSaveTranslation(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	tr := &model.ExerciseTranslation{Locale: "pt-BR", ExerciseName: "Levantamento terra romeno"}
+	translationDao.On("SaveExerciseTranslation", exUuid, tr).Return(nil)
+
+	body := `{"exerciseName":"Levantamento terra romeno"}`
+	r, _ := http.NewRequest(http.MethodPut, "/exercises/"+exUuid.String()+"/translations/pt_br", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	translationDao.AssertExpectations(t)
+}
+
+func TestSaveTranslation_Errors(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	translationDao.On("SaveExerciseTranslation", exUuid, mock.Anything).
+		Return(fmt.Errorf("exercise with uuid %s %w", exUuid, dao.ErrNotFound))
+
+	tests := []struct {
+		locale string
+		body   string
+		status int
+	}{
+		{"de", `{"exerciseName":"Kniebeuge"}`, http.StatusNotFound},
+		{"de", `{"cues":"Brust raus"}`, http.StatusBadRequest},
+		{"en", `{"exerciseName":"Squat"}`, http.StatusBadRequest},
+		{"not a locale", `{"exerciseName":"Squat"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		path := "/exercises/" + exUuid.String() + "/translations/" + strings.ReplaceAll(tt.locale, " ", "%20")
+		r, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.status, w.Code, tt.locale+" "+tt.body)
+	}
+}
+
+func TestDeleteTranslation_NotFound(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	translationDao.On("DeleteExerciseTranslation", exUuid, "fr").Return(dao.ErrNotFound)
+
+	r, _ := http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String()+"/translations/FR", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAddAlias(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	translationDao.On("AddAlias", exUuid, &model.ExerciseAlias{Alias: "RDL"}).Return(nil)
+	translationDao.On("AddAlias", exUuid, &model.ExerciseAlias{Alias: "Rumänisches Kreuzheben", Locale: "de"}).
+		Return(dao.ErrConflict)
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"alias":" RDL "}`, http.StatusCreated},
+		{`{"alias":"Rumänisches Kreuzheben","locale":"DE"}`, http.StatusConflict},
+		{`{"alias":""}`, http.StatusBadRequest},
+		{`{"alias":"SLDL","locale":"!"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/exercises/"+exUuid.String()+"/aliases", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.status, w.Code, tt.body)
+	}
+	translationDao.AssertExpectations(t)
+}
+
+func TestGetAliases(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	translationDao.On("GetAliases", exUuid).Return([]model.ExerciseAlias{{Alias: "RDL"}}, nil)
+
+	r, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String()+"/aliases", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"alias":"RDL"}]`, w.Body.String())
+}
+
+func TestDeleteAlias(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	exUuid := uuid.New()
+	translationDao.On("DeleteAlias", exUuid, "Romanian DL").Return(nil)
+
+	r, _ := http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String()+"/aliases?alias=Romanian+DL", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	r, _ = http.NewRequest(http.MethodDelete, "/exercises/"+exUuid.String()+"/aliases", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSaveReferenceTranslation(t *testing.T) {
+	translationDao := new(MockTranslationDao)
+	router := newTranslationRouter(translationDao)
+
+	tr := &model.ReferenceTranslation{Kind: model.ReferenceMuscle, Code: "GLUTES", Locale: "de", Name: "Gesäßmuskel"}
+	translationDao.On("SaveReferenceTranslation", tr).Return(nil)
+	translationDao.On("SaveReferenceTranslation", mock.Anything).Return(dao.ErrNotFound)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/translations/muscles/GLUTES/de", http.StatusOK},
+		{"/translations/muscles/WINGS/de", http.StatusNotFound},
+		{"/translations/exercises/GLUTES/de", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPut, tt.path, strings.NewReader(`{"name":"Gesäßmuskel"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.status, w.Code, tt.path)
+	}
+}
+
+func TestGetExercise_Localized(t *testing.T) {
+	daos := newMemoryDaos(t)
+	handler := newExerciseHandler(daos)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/exercises/:uuid", handler.GetExercise)
+
+	ctx := context.Background()
+	ex, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{ExerciseFields: model.ExerciseFields{
+		ExerciseName: "Romanian Deadlift", Cues: "Push the hips back", CategoryCode: "STRENGTH",
+	}})
+	assert.NoError(t, err)
+	exUuid := ex.ExerciseUuid
+	assert.NoError(t, daos.Translations.SaveExerciseTranslation(ctx, exUuid,
+		&model.ExerciseTranslation{Locale: "de", ExerciseName: "Rumänisches Kreuzheben"}))
+	assert.NoError(t, daos.Translations.SaveExerciseTranslation(ctx, exUuid,
+		&model.ExerciseTranslation{Locale: "fr", ExerciseName: "Soulevé de terre roumain", Cues: "Hanches en arrière"}))
+
+	tests := []struct {
+		header string
+		locale string
+		name   string
+		cues   string
+	}{
+		{"de-CH", "de", "Rumänisches Kreuzheben", "Push the hips back"},
+		{"it, fr;q=0.8", "fr", "Soulevé de terre roumain", "Hanches en arrière"},
+		{"ja", "en", "Romanian Deadlift", "Push the hips back"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/exercises/"+exUuid.String(), nil)
+		r.Header.Set("Accept-Language", tt.header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tt.locale, w.Header().Get("Content-Language"), tt.header)
+		var ex model.Exercise
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ex))
+		assert.Equal(t, tt.name, ex.ExerciseName, tt.header)
+		assert.Equal(t, tt.cues, ex.Cues, tt.header)
+		assert.Equal(t, "STRENGTH", ex.CategoryCode)
+	}
+}
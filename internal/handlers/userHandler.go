@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// maxLoadIncrement bounds the load increment, which is a plate pair at most.
+const maxLoadIncrement = 50
+
+type UserHandler struct {
+	dao dao.UserDaoInterface
+}
+
+func NewUserHandler(dao dao.UserDaoInterface) *UserHandler {
+	return &UserHandler{dao: dao}
+}
+
+func (h UserHandler) GetPreferences(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs, err := h.dao.GetPreferences(ctx.Request.Context(), userUuid)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, prefs)
+	}
+}
+
+// UpdatePreferences sets the unit system of the user in the path and the
+// increment converted loads are rounded to.
+func (h UserHandler) UpdatePreferences(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var prefs model.UserPreferences
+	if err := ctx.ShouldBindJSON(&prefs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !prefs.UnitSystem.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unitSystem must be %s or %s", units.Metric, units.Imperial)})
+		return
+	}
+	if prefs.LoadIncrement != nil && (*prefs.LoadIncrement <= 0 || *prefs.LoadIncrement > maxLoadIncrement) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("loadIncrement must be above 0 and at most %d", maxLoadIncrement)})
+		return
+	}
+
+	updated, err := h.dao.UpdatePreferences(ctx.Request.Context(), userUuid, &prefs)
+	switch {
+	case errors.Is(err, dao.ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
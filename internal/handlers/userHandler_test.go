@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserDao is a mock implementation of the UserDaoInterface
+type MockUserDao struct {
+	mock.Mock
+}
+
+func (m *MockUserDao) GetPreferences(ctx context.Context, userUuid uuid.UUID) (*model.UserPreferences, error) {
+	args := m.Called(userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserPreferences), args.Error(1)
+}
+
+func (m *MockUserDao) UpdatePreferences(ctx context.Context, userUuid uuid.UUID, prefs *model.UserPreferences) (*model.UserPreferences, error) {
+	args := m.Called(userUuid, prefs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserPreferences), args.Error(1)
+}
+
+// usersPreferring returns users who all prefer system, for handlers that
+// convert their responses.
+func usersPreferring(system units.System) *MockUserDao {
+	users := new(MockUserDao)
+	users.On("GetPreferences", mock.Anything).Return(&model.UserPreferences{UnitSystem: system}, nil).Maybe()
+	return users
+}
+
+func newUserRouter(dao *MockUserDao) *gin.Engine {
+	handler := NewUserHandler(dao)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/preferences", handler.GetPreferences)
+	router.PUT("/users/:uuid/preferences", handler.UpdatePreferences)
+	return router
+}
+
+func TestGetPreferences(t *testing.T) {
+	userDao := new(MockUserDao)
+	router := newUserRouter(userDao)
+
+	userUuid := uuid.New()
+	userDao.On("GetPreferences", userUuid).Return(&model.UserPreferences{UnitSystem: units.Imperial}, nil).Once()
+	userDao.On("GetPreferences", mock.Anything).Return(nil, fmt.Errorf("user %w", dao.ErrNotFound))
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/preferences", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"unitSystem":"imperial"}`, w.Body.String())
+
+	r, _ = http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/preferences", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	userDao := new(MockUserDao)
+	router := newUserRouter(userDao)
+
+	userUuid, increment := uuid.New(), 2.5
+	prefs := &model.UserPreferences{UnitSystem: units.Imperial, LoadIncrement: &increment}
+	userDao.On("UpdatePreferences", userUuid, prefs).Return(prefs, nil)
+
+	r, _ := http.NewRequest(http.MethodPut, "/users/"+userUuid.String()+"/preferences",
+		strings.NewReader(`{"unitSystem":"imperial","loadIncrement":2.5}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"unitSystem":"imperial","loadIncrement":2.5}`, w.Body.String())
+	userDao.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_Invalid(t *testing.T) {
+	userDao := new(MockUserDao)
+	router := newUserRouter(userDao)
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"unitSystem":"nautical"}`, "unitSystem must be metric or imperial"},
+		{`{"unitSystem":"metric","loadIncrement":0}`, "loadIncrement must be above 0 and at most 50"},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPut, "/users/"+uuid.NewString()+"/preferences", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	userDao.AssertNotCalled(t, "UpdatePreferences")
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+)
+
+// defaultWorkoutWindow is how far back workouts are listed without a from date.
+const defaultWorkoutWindow = 30 * 24 * time.Hour
+
+type WorkoutHandler struct {
+	workouts service.WorkoutServiceInterface
+}
+
+func NewWorkoutHandler(workouts service.WorkoutServiceInterface) *WorkoutHandler {
+	return &WorkoutHandler{workouts: workouts}
+}
+
+// CreateWorkout logs a session with its sets for the user in the path.
+// Loads and distances are given in kg and metres or as a weight and
+// distance with their unit.
+func (h WorkoutHandler) CreateWorkout(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req model.WorkoutSessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.workouts.Create(ctx.Request.Context(), userUuid, &req)
+	switch {
+	case errors.Is(err, service.ErrInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		internalError(ctx, err)
+	default:
+		ctx.JSON(http.StatusCreated, session)
+	}
+}
+
+// GetWorkouts lists the sessions of a user between the optional from and to
+// query parameters, which default to the last 30 days, with loads and
+// distances in the units the user prefers.
+func (h WorkoutHandler) GetWorkouts(ctx *gin.Context) {
+	userUuid, err := uuid.Parse(ctx.Param("uuid"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, err := timeRange(ctx, time.Now(), defaultWorkoutWindow)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sessions, err := h.workouts.List(ctx.Request.Context(), userUuid, from, to)
+	if err != nil {
+		internalError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// timeRange reads the from and to query parameters, given as dates or
+// RFC 3339 timestamps. to defaults to now and from to window before to.
+func timeRange(ctx *gin.Context, now time.Time, window time.Duration) (time.Time, time.Time, error) {
+	to := now
+	if value := ctx.Query("to"); value != "" {
+		t, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = t
+	}
+
+	from := to.Add(-window)
+	if value := ctx.Query("from"); value != "" {
+		t, err := parseTime(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/service"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkoutRouter(daos *dao.Daos) *gin.Engine {
+	handler := NewWorkoutHandler(service.NewWorkoutService(daos.Workouts, daos.Users))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/users/:uuid/workouts", handler.GetWorkouts)
+	router.POST("/users/:uuid/workouts", handler.CreateWorkout)
+	return router
+}
+
+// listWorkouts returns the sessions a user logged in 2025.
+func listWorkouts(t *testing.T, daos *dao.Daos, userUuid uuid.UUID) []model.WorkoutSession {
+	sessions, err := daos.Workouts.ListSessions(context.Background(), userUuid,
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	return sessions
+}
+
+func TestCreateWorkout(t *testing.T) {
+	daos := newMemoryDaos(t)
+	router := newWorkoutRouter(daos)
+
+	userUuid, exUuid := uuid.New(), createExercise(t, daos, "Squat")
+
+	body := `{"sessionName":"Legs","startedAt":"2025-03-01T08:30:00Z","sets":[{"exerciseUuid":"` + exUuid.String() + `","reps":5,"weightKg":100}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/workouts", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got model.WorkoutSession
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+
+	sessions := listWorkouts(t, daos, userUuid)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, got.SessionUuid, sessions[0].SessionUuid)
+	assert.Equal(t, "Legs", sessions[0].SessionName)
+	require.Len(t, sessions[0].Sets, 1)
+	assert.Equal(t, exUuid, sessions[0].Sets[0].ExerciseUuid)
+	assert.Equal(t, 100.0, *sessions[0].Sets[0].WeightKg)
+}
+
+func TestCreateWorkout_Units(t *testing.T) {
+	daos := newMemoryDaos(t)
+	router := newWorkoutRouter(daos)
+
+	userUuid, exUuid := uuid.New(), createExercise(t, daos, "Squat")
+	_, err := daos.Users.UpdatePreferences(context.Background(), userUuid, &model.UserPreferences{UnitSystem: units.Imperial})
+	require.NoError(t, err)
+
+	body := `{"sessionName":"Run and lift","startedAt":"2025-03-01T08:30:00Z","sets":[` +
+		`{"exerciseUuid":"` + exUuid.String() + `","reps":5,"weight":225,"weightUnit":"lbs"},` +
+		`{"exerciseUuid":"` + exUuid.String() + `","reps":5,"weightKg":100},` +
+		`{"exerciseUuid":"` + exUuid.String() + `","distance":5,"distanceUnit":"km"}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/workouts", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	sessions := listWorkouts(t, daos, userUuid)
+	require.Len(t, sessions, 1)
+	sets := sessions[0].Sets
+	assert.Equal(t, 102.06, *sets[0].WeightKg)
+	assert.Equal(t, units.Lb, sets[0].EnteredWeightUnit)
+	assert.Equal(t, 5000.0, *sets[2].DistanceM)
+	assert.Equal(t, units.Km, sets[2].EnteredDistanceUnit)
+
+	var got model.WorkoutSession
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	// logged in lb, read as logged; logged in kg, rounded to 5 lb
+	assert.Equal(t, 225.0, *got.Sets[0].Weight)
+	assert.Equal(t, 220.0, *got.Sets[1].Weight)
+	assert.Equal(t, units.Lb, got.Sets[1].WeightUnit)
+	assert.Equal(t, 3.11, *got.Sets[2].Distance)
+	assert.Equal(t, units.Mi, got.Sets[2].DistanceUnit)
+}
+
+func TestCreateWorkout_Invalid(t *testing.T) {
+	daos := newMemoryDaos(t)
+	router := newWorkoutRouter(daos)
+
+	userUuid, exUuid := uuid.New(), createExercise(t, daos, "Squat")
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"startedAt":"2025-03-01T08:30:00Z","sets":[]}`, "sessionName is required"},
+		{`{"sessionName":"Legs","sets":[]}`, "startedAt is required"},
+		{`{"sessionName":"Legs","startedAt":"2025-03-01T08:30:00Z"}`, "at least one set is required"},
+		{`{"sessionName":"Legs","startedAt":"2025-03-01T08:30:00Z","sets":[{"reps":5}]}`, "set 1: exerciseUuid is required"},
+		{`{"sessionName":"Legs","startedAt":"2025-03-01T08:30:00Z","sets":[{"exerciseUuid":"` + exUuid.String() + `","weight":20,"weightUnit":"stone"}]}`,
+			`set 1: unknown weight unit \"stone\"`},
+		{`{"sessionName":"Legs","startedAt":"2025-03-01T08:30:00Z","sets":[{"exerciseUuid":"` + exUuid.String() + `","weight":225,"weightKg":102}]}`,
+			"set 1: give weight or weightKg, not both"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+userUuid.String()+"/workouts", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":"`+tt.want+`"}`, w.Body.String())
+	}
+	assert.Empty(t, listWorkouts(t, daos, userUuid))
+}
+
+func TestGetWorkouts(t *testing.T) {
+	daos := newMemoryDaos(t)
+	router := newWorkoutRouter(daos)
+
+	userUuid, exUuid := uuid.New(), createExercise(t, daos, "Squat")
+	weight := 100.0
+	var reqs []model.WorkoutSessionRequest
+	for _, started := range []time.Time{
+		time.Date(2025, 2, 28, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 15, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		reqs = append(reqs, model.WorkoutSessionRequest{
+			WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: started},
+			Sets:                 []model.WorkoutSet{{ExerciseUuid: exUuid, WeightKg: &weight}},
+		})
+	}
+	_, err := daos.Workouts.CreateSessions(context.Background(), userUuid, reqs)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/"+userUuid.String()+"/workouts?from=2025-03-01&to=2025-04-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []model.WorkoutSession
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Len(t, got, 1, "only the session started in March")
+	assert.Equal(t, time.Date(2025, 3, 15, 8, 0, 0, 0, time.UTC), got[0].StartedAt)
+}
+
+func TestGetWorkouts_InvalidRange(t *testing.T) {
+	router := newWorkoutRouter(newMemoryDaos(t))
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/workouts?from=2025-04-01&to=2025-03-01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"from must be before to"}`, w.Body.String())
+}
@@ -0,0 +1,141 @@
+package history
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type ImportOptions struct {
+	UserUuid uuid.UUID
+	DryRun   bool
+	// SkipUnresolved imports the history without the sets of exercises that
+	// could not be matched, instead of refusing to import.
+	SkipUnresolved bool
+}
+
+type ImporterInterface interface {
+	Import(ctx context.Context, parsed *ParseResult, opts ImportOptions) (*model.HistoryImportReport, error)
+}
+
+// Importer matches parsed workout history to the catalog and stores it as
+// workout sessions.
+type Importer struct {
+	workouts dao.WorkoutDaoInterface
+	catalog  dao.CatalogDaoInterface
+	mappings dao.ExerciseMappingDaoInterface
+}
+
+// Ensure Importer implements ImporterInterface
+var _ ImporterInterface = (*Importer)(nil)
+
+// NewImporter creates a new instance of Importer.
+func NewImporter(workouts dao.WorkoutDaoInterface, catalog dao.CatalogDaoInterface, mappings dao.ExerciseMappingDaoInterface) *Importer {
+	return &Importer{workouts: workouts, catalog: catalog, mappings: mappings}
+}
+
+// Import resolves the exercise names of parsed and, unless this is a dry
+// run, stores its sessions in one transaction. Sessions imported before are
+// skipped. Nothing is written while the file has row errors or, unless
+// SkipUnresolved is set, names that could not be matched.
+func (imp *Importer) Import(ctx context.Context, parsed *ParseResult, opts ImportOptions) (*model.HistoryImportReport, error) {
+	if opts.UserUuid == uuid.Nil {
+		return nil, errors.New("user is required")
+	}
+
+	exercises, err := imp.catalog.ListExerciseNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := imp.mappings.GetMappings(ctx, string(parsed.Source))
+	if err != nil {
+		return nil, err
+	}
+	starts, err := imp.workouts.ListSessionStarts(ctx, opts.UserUuid, string(parsed.Source))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.HistoryImportReport{
+		Source:    string(parsed.Source),
+		DryRun:    opts.DryRun,
+		Exercises: []model.NameResolution{},
+		Errors:    parsed.Errors,
+	}
+
+	matcher := NewMatcher(exercises, mappings)
+	resolutions := map[string]int{}
+	for _, session := range parsed.Sessions {
+		for _, set := range session.Sets {
+			idx, ok := resolutions[set.ExerciseName]
+			if !ok {
+				report.Exercises = append(report.Exercises, matcher.Resolve(set.ExerciseName))
+				idx = len(report.Exercises) - 1
+				resolutions[set.ExerciseName] = idx
+				if report.Exercises[idx].ExerciseUuid == nil {
+					report.Unresolved++
+				}
+			}
+			report.Exercises[idx].SetCount++
+		}
+	}
+
+	imported := make(map[int64]bool, len(starts))
+	for _, start := range starts {
+		imported[start.Unix()] = true
+	}
+
+	requests := make([]model.WorkoutSessionRequest, 0, len(parsed.Sessions))
+	for _, session := range parsed.Sessions {
+		if imported[session.StartedAt.Unix()] {
+			report.DuplicateSessions++
+			continue
+		}
+
+		req := model.WorkoutSessionRequest{WorkoutSessionFields: session.WorkoutSessionFields}
+		for _, set := range session.Sets {
+			resolution := report.Exercises[resolutions[set.ExerciseName]]
+			if resolution.ExerciseUuid == nil {
+				report.SkippedSets++
+				continue
+			}
+			workoutSet := set.WorkoutSet
+			workoutSet.ExerciseUuid = *resolution.ExerciseUuid
+			workoutSet.SetNumber = len(req.Sets) + 1
+			req.Sets = append(req.Sets, workoutSet)
+		}
+		if len(req.Sets) == 0 {
+			continue
+		}
+
+		requests = append(requests, req)
+		report.Sets += len(req.Sets)
+		startedAt := session.StartedAt.UTC()
+		if report.From == nil || startedAt.Before(*report.From) {
+			report.From = &startedAt
+		}
+		if report.To == nil || startedAt.After(*report.To) {
+			report.To = &startedAt
+		}
+	}
+	report.Sessions = len(requests)
+
+	blocked := len(report.Errors) > 0 || (report.Unresolved > 0 && !opts.SkipUnresolved)
+	if opts.DryRun || blocked || len(requests) == 0 {
+		return report, nil
+	}
+
+	sessions, err := imp.workouts.CreateSessions(ctx, opts.UserUuid, requests)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		report.SessionUuids = append(report.SessionUuids, session.SessionUuid)
+	}
+	report.Committed = true
+
+	return report, nil
+}
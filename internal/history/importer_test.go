@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newTestImporter returns an importer on a memory store holding a back
+ * squat, which the Strong name "Squat (Barbell)" is mapped to, with the
+ * DAOs and the squat's uuid.
+ */
+func newTestImporter(t *testing.T) (*Importer, *dao.Daos, uuid.UUID) {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	squat, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Back Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Mappings.SaveMapping(ctx, &model.ExerciseMapping{
+		Source: "strong", ExternalName: "squat (barbell)", ExerciseUuid: squat.ExerciseUuid, CreatedBy: uuid.New()}))
+
+	return NewImporter(daos.Workouts, daos.Catalog, daos.Mappings), daos, squat.ExerciseUuid
+}
+
+var (
+	monday   = time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+	saturday = time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+)
+
+// listSessions returns the sessions a user has logged in March 2025.
+func listSessions(t *testing.T, daos *dao.Daos, userUuid uuid.UUID) []model.WorkoutSession {
+	sessions, err := daos.Workouts.ListSessions(context.Background(), userUuid,
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	return sessions
+}
+
+func newParsed() *ParseResult {
+	reps := 5
+	return &ParseResult{
+		Source: SourceStrong,
+		Sessions: []Session{
+			{Line: 2, WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: saturday, Source: "strong"}, Sets: []Set{
+				{Line: 2, ExerciseName: "Squat (Barbell)", WorkoutSet: model.WorkoutSet{Reps: &reps}},
+				{Line: 3, ExerciseName: "Nordic Curl", WorkoutSet: model.WorkoutSet{Reps: &reps}},
+			}},
+			{Line: 4, WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: monday, Source: "strong"}, Sets: []Set{
+				{Line: 4, ExerciseName: "Squat (Barbell)", WorkoutSet: model.WorkoutSet{Reps: &reps}},
+			}},
+		},
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	importer, daos, _ := newTestImporter(t)
+
+	userUuid := uuid.New()
+	_, err := daos.Workouts.CreateSessions(context.Background(), userUuid, []model.WorkoutSessionRequest{
+		{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: saturday, Source: "strong"}}})
+	require.NoError(t, err)
+
+	report, err := importer.Import(context.Background(), newParsed(), ImportOptions{UserUuid: userUuid, DryRun: true})
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, 1, report.DuplicateSessions)
+	assert.Equal(t, 1, report.Sessions)
+	assert.Equal(t, 1, report.Sets)
+	assert.Equal(t, 1, report.Unresolved)
+	assert.Equal(t, monday, *report.From)
+	assert.Equal(t, monday, *report.To)
+	assert.Len(t, report.Exercises, 2)
+	assert.Equal(t, model.MatchMethodMapping, report.Exercises[0].Method)
+	assert.Equal(t, 2, report.Exercises[0].SetCount)
+	assert.Equal(t, model.MatchMethodUnresolved, report.Exercises[1].Method)
+	assert.Len(t, listSessions(t, daos, userUuid), 1, "a dry run stores nothing")
+}
+
+func TestImport_UnresolvedBlocksCommit(t *testing.T) {
+	importer, daos, _ := newTestImporter(t)
+
+	userUuid := uuid.New()
+	report, err := importer.Import(context.Background(), newParsed(), ImportOptions{UserUuid: userUuid})
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, 1, report.SkippedSets)
+	assert.Empty(t, listSessions(t, daos, userUuid))
+}
+
+func TestImport_SkipUnresolved(t *testing.T) {
+	importer, daos, squatUuid := newTestImporter(t)
+
+	userUuid := uuid.New()
+	report, err := importer.Import(context.Background(), newParsed(), ImportOptions{UserUuid: userUuid, SkipUnresolved: true})
+	assert.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, 2, report.Sessions)
+	assert.Equal(t, saturday, *report.From)
+	assert.Equal(t, monday, *report.To)
+
+	sessions := listSessions(t, daos, userUuid)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, report.SessionUuids, []uuid.UUID{sessions[0].SessionUuid, sessions[1].SessionUuid})
+		assert.Equal(t, "strong", sessions[0].Source)
+		if assert.Len(t, sessions[0].Sets, 1) {
+			assert.Equal(t, 1, sessions[0].Sets[0].SetNumber)
+			assert.Equal(t, squatUuid, sessions[0].Sets[0].ExerciseUuid)
+			assert.Equal(t, 5, *sessions[0].Sets[0].Reps)
+		}
+	}
+
+	again, err := importer.Import(context.Background(), newParsed(), ImportOptions{UserUuid: userUuid, SkipUnresolved: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, again.DuplicateSessions, "importing a file twice skips its sessions")
+	assert.False(t, again.Committed)
+}
+
+func TestImport_RowErrorsBlockCommit(t *testing.T) {
+	importer, daos, _ := newTestImporter(t)
+
+	userUuid := uuid.New()
+	parsed := newParsed()
+	parsed.Errors = []model.HistoryRowError{{Line: 9, Error: "invalid date"}}
+	report, err := importer.Import(context.Background(), parsed, ImportOptions{UserUuid: userUuid, SkipUnresolved: true})
+	assert.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Empty(t, listSessions(t, daos, userUuid))
+}
+
+func TestImport_UserRequired(t *testing.T) {
+	importer, _, _ := newTestImporter(t)
+
+	_, err := importer.Import(context.Background(), newParsed(), ImportOptions{})
+	assert.EqualError(t, err, "user is required")
+}
@@ -0,0 +1,166 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+)
+
+const (
+	// autoMatchScore is the similarity from which a name is matched without
+	// a manual mapping.
+	autoMatchScore = 0.8
+	// candidateScore is the lowest similarity suggested for an unresolved name.
+	candidateScore = 0.4
+	maxCandidates  = 3
+)
+
+type indexedName struct {
+	model.ExerciseName
+	key     string
+	bigrams map[string]int
+}
+
+// Matcher resolves exercise names from other applications to the catalog,
+// first through manual mappings, then by normalised name and finally by
+// similarity.
+type Matcher struct {
+	exercises []indexedName
+	byKey     map[string]model.ExerciseName
+	byUuid    map[uuid.UUID]model.ExerciseName
+	mappings  map[string]uuid.UUID
+}
+
+// NewMatcher indexes the catalog and the manual mappings of one source.
+func NewMatcher(exercises []model.ExerciseName, mappings []model.ExerciseMapping) *Matcher {
+	m := &Matcher{
+		exercises: make([]indexedName, 0, len(exercises)),
+		byKey:     make(map[string]model.ExerciseName, len(exercises)),
+		byUuid:    make(map[uuid.UUID]model.ExerciseName, len(exercises)),
+		mappings:  make(map[string]uuid.UUID, len(mappings)),
+	}
+
+	sorted := append([]model.ExerciseName{}, exercises...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ExerciseName != sorted[j].ExerciseName {
+			return sorted[i].ExerciseName < sorted[j].ExerciseName
+		}
+		return sorted[i].ExerciseUuid.String() < sorted[j].ExerciseUuid.String()
+	})
+	for _, ex := range sorted {
+		key := normalizeName(ex.ExerciseName)
+		m.exercises = append(m.exercises, indexedName{ExerciseName: ex, key: key, bigrams: bigrams(key)})
+		if _, ok := m.byKey[key]; !ok {
+			m.byKey[key] = ex
+		}
+		m.byUuid[ex.ExerciseUuid] = ex
+	}
+	for _, mapping := range mappings {
+		m.mappings[strings.ToLower(strings.TrimSpace(mapping.ExternalName))] = mapping.ExerciseUuid
+	}
+
+	return m
+}
+
+// Resolve matches a single external name.
+func (m *Matcher) Resolve(name string) model.NameResolution {
+	resolution := model.NameResolution{ExternalName: name, Method: model.MatchMethodUnresolved}
+	resolve := func(method model.MatchMethod, ex model.ExerciseName, score float64) model.NameResolution {
+		resolution.Method = method
+		resolution.ExerciseUuid = &ex.ExerciseUuid
+		resolution.ExerciseName = ex.ExerciseName
+		resolution.Score = score
+		return resolution
+	}
+
+	if exUuid, ok := m.mappings[strings.ToLower(strings.TrimSpace(name))]; ok {
+		ex, known := m.byUuid[exUuid]
+		if !known {
+			ex = model.ExerciseName{ExerciseUuid: exUuid}
+		}
+		return resolve(model.MatchMethodMapping, ex, 1)
+	}
+
+	key := normalizeName(name)
+	if ex, ok := m.byKey[key]; ok {
+		return resolve(model.MatchMethodExact, ex, 1)
+	}
+
+	candidates := m.candidates(key)
+	if len(candidates) > 0 && candidates[0].Score >= autoMatchScore {
+		best := candidates[0]
+		return resolve(model.MatchMethodFuzzy, model.ExerciseName{ExerciseUuid: best.ExerciseUuid, ExerciseName: best.ExerciseName}, best.Score)
+	}
+	resolution.Candidates = candidates
+	return resolution
+}
+
+// candidates returns the most similar exercises, best first.
+func (m *Matcher) candidates(key string) []model.MatchCandidate {
+	grams := bigrams(key)
+	var candidates []model.MatchCandidate
+	for _, ex := range m.exercises {
+		score := dice(grams, ex.bigrams)
+		if score >= candidateScore {
+			candidates = append(candidates, model.MatchCandidate{
+				ExerciseUuid: ex.ExerciseUuid,
+				ExerciseName: ex.ExerciseName.ExerciseName,
+				Score:        round(score),
+			})
+		}
+	}
+	// exercises are sorted by name, so a stable sort keeps ties deterministic
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	return candidates
+}
+
+// normalizeName lower-cases a name, drops punctuation and sorts its words so
+// that "Bench Press (Barbell)" and "Barbell Bench Press" compare equal.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+func bigrams(s string) map[string]int {
+	grams := map[string]int{}
+	runes := []rune(s)
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])]++
+	}
+	return grams
+}
+
+// dice is the Sørensen–Dice coefficient of two bigram multisets.
+func dice(a, b map[string]int) float64 {
+	total := 0
+	for _, n := range a {
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	shared := 0
+	for gram, n := range a {
+		shared += min(n, b[gram])
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+func round(score float64) float64 {
+	return float64(int(score*1000+0.5)) / 1000
+}
@@ -0,0 +1,55 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Resolve(t *testing.T) {
+	bench, squat, row := uuid.New(), uuid.New(), uuid.New()
+	matcher := NewMatcher([]model.ExerciseName{
+		{ExerciseUuid: bench, ExerciseName: "Barbell Bench Press"},
+		{ExerciseUuid: squat, ExerciseName: "Back Squat"},
+		{ExerciseUuid: row, ExerciseName: "Bent Over Row"},
+	}, []model.ExerciseMapping{
+		{Source: "strong", ExternalName: "squat (barbell)", ExerciseUuid: squat},
+	})
+
+	mapped := matcher.Resolve("Squat (Barbell)")
+	assert.Equal(t, model.MatchMethodMapping, mapped.Method)
+	assert.Equal(t, squat, *mapped.ExerciseUuid)
+	assert.Equal(t, "Back Squat", mapped.ExerciseName)
+
+	exact := matcher.Resolve("Bench Press (Barbell)")
+	assert.Equal(t, model.MatchMethodExact, exact.Method)
+	assert.Equal(t, bench, *exact.ExerciseUuid)
+	assert.Equal(t, 1.0, exact.Score)
+
+	fuzzy := matcher.Resolve("Bent-Over Rows")
+	assert.Equal(t, model.MatchMethodFuzzy, fuzzy.Method)
+	assert.Equal(t, row, *fuzzy.ExerciseUuid)
+	assert.GreaterOrEqual(t, fuzzy.Score, autoMatchScore)
+
+	unresolved := matcher.Resolve("Bench Press (Dumbbell)")
+	assert.Equal(t, model.MatchMethodUnresolved, unresolved.Method)
+	assert.Nil(t, unresolved.ExerciseUuid)
+	assert.NotEmpty(t, unresolved.Candidates)
+	assert.Equal(t, bench, unresolved.Candidates[0].ExerciseUuid)
+
+	none := matcher.Resolve("Zumba")
+	assert.Empty(t, none.Candidates)
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "barbell bench press", normalizeName("Bench Press (Barbell)"))
+	assert.Equal(t, normalizeName("Barbell Bench Press"), normalizeName("bench-press, barbell"))
+}
+
+func TestDice(t *testing.T) {
+	assert.Equal(t, 1.0, dice(bigrams("squat"), bigrams("squat")))
+	assert.Equal(t, 0.0, dice(bigrams("squat"), bigrams("plank")))
+	assert.Equal(t, 0.0, dice(bigrams(""), bigrams("")))
+}
@@ -0,0 +1,481 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+)
+
+const utf8BOM = "\ufeff"
+
+// WeightUnit is the unit loads are written in when the export does not say.
+type WeightUnit = units.WeightUnit
+
+const (
+	WeightUnitKg = units.Kg
+	WeightUnitLb = units.Lb
+)
+
+// DistanceUnit is the unit distances are written in when the export does
+// not say.
+type DistanceUnit = units.DistanceUnit
+
+const (
+	DistanceUnitKm = units.Km
+	DistanceUnitMi = units.Mi
+	DistanceUnitM  = units.M
+)
+
+// ParseOptions fill in what the exports leave out. Timestamps in the exports
+// are local times without a zone.
+type ParseOptions struct {
+	Location     *time.Location
+	WeightUnit   WeightUnit
+	DistanceUnit DistanceUnit
+}
+
+// Set is a parsed set still referring to its exercise by the name used in
+// the source application.
+type Set struct {
+	Line         int
+	ExerciseName string
+	model.WorkoutSet
+}
+
+type Session struct {
+	Line int
+	model.WorkoutSessionFields
+	Sets []Set
+}
+
+type ParseResult struct {
+	Source   Source
+	Sessions []Session
+	Errors   []model.HistoryRowError
+}
+
+// Parse reads a CSV export. When source is empty it is detected from the
+// header. Problems with single rows are collected in the result; an error is
+// only returned when the file cannot be read as an export at all.
+func Parse(r io.Reader, source Source, opts ParseOptions) (*ParseResult, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.WeightUnit == "" {
+		opts.WeightUnit = WeightUnitKg
+	}
+	if opts.DistanceUnit == "" {
+		opts.DistanceUnit = DistanceUnitKm
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.Comma = detectDelimiter(data)
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv input is empty")
+		}
+		return nil, err
+	}
+	if source == "" {
+		if source, err = DetectSource(header); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &parser{
+		result:   &ParseResult{Source: source},
+		columns:  columnIndex(header),
+		opts:     opts,
+		sessions: map[string]int{},
+	}
+	switch source {
+	case SourceStrong:
+		err = p.requireColumns("date", "workout name", "exercise name")
+	case SourceHevy:
+		err = p.requireColumns("title", "start_time", "exercise_title")
+	case SourceFitNotes:
+		err = p.requireColumns("date", "exercise")
+	default:
+		err = fmt.Errorf("unsupported source %q", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				p.fail(parseErr.StartLine, "%s", parseErr.Err)
+				continue
+			}
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		row := csvRecord{columns: p.columns, values: record}
+
+		switch source {
+		case SourceStrong:
+			p.strongRow(line, row)
+		case SourceHevy:
+			p.hevyRow(line, row)
+		case SourceFitNotes:
+			p.fitNotesRow(line, row)
+		}
+	}
+
+	return p.result, nil
+}
+
+// detectDelimiter picks between the comma and the semicolon that Strong uses
+// in locales with a decimal comma.
+func detectDelimiter(data []byte) rune {
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+type columns map[string]int
+
+func columnIndex(header []string) columns {
+	index := make(columns, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, utf8BOM)))] = i
+	}
+	return index
+}
+
+func (c columns) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+type csvRecord struct {
+	columns columns
+	values  []string
+}
+
+func (r csvRecord) get(name string) string {
+	idx, ok := r.columns[name]
+	if !ok || idx >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[idx])
+}
+
+type parser struct {
+	result  *ParseResult
+	columns columns
+	opts    ParseOptions
+	// sessions maps a session key to its index in result.Sessions
+	sessions map[string]int
+}
+
+func (p *parser) requireColumns(names ...string) error {
+	for _, name := range names {
+		if !p.columns.has(name) {
+			return fmt.Errorf("csv header is missing the %q column", name)
+		}
+	}
+	return nil
+}
+
+func (p *parser) fail(line int, format string, args ...any) {
+	p.result.Errors = append(p.result.Errors, model.HistoryRowError{Line: line, Error: fmt.Sprintf(format, args...)})
+}
+
+// session returns the session for key, creating it with fields on first use.
+func (p *parser) session(key string, line int, fields model.WorkoutSessionFields) *Session {
+	if idx, ok := p.sessions[key]; ok {
+		return &p.result.Sessions[idx]
+	}
+	fields.Source = string(p.result.Source)
+	p.result.Sessions = append(p.result.Sessions, Session{Line: line, WorkoutSessionFields: fields})
+	p.sessions[key] = len(p.result.Sessions) - 1
+	return &p.result.Sessions[len(p.result.Sessions)-1]
+}
+
+// strongRow reads a row of a Strong export:
+// Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE
+func (p *parser) strongRow(line int, row csvRecord) {
+	name := row.get("exercise name")
+	if name == "" || strings.EqualFold(row.get("set order"), "rest timer") {
+		return
+	}
+
+	startedAt, err := time.ParseInLocation("2006-01-02 15:04:05", row.get("date"), p.opts.Location)
+	if err != nil {
+		p.fail(line, "invalid date %q", row.get("date"))
+		return
+	}
+
+	set, ok := p.set(line, name, row, setColumns{
+		reps: "reps", weight: "weight", distance: "distance", seconds: "seconds", rpe: "rpe", notes: "notes",
+	}, p.opts.WeightUnit, p.opts.DistanceUnit)
+	if !ok {
+		return
+	}
+
+	session := p.session(row.get("date")+"\x00"+row.get("workout name"), line, model.WorkoutSessionFields{
+		SessionName:     row.get("workout name"),
+		StartedAt:       startedAt,
+		DurationSeconds: parseStrongDuration(row.get("duration")),
+		Notes:           row.get("workout notes"),
+	})
+	session.Sets = append(session.Sets, set)
+}
+
+// hevyRow reads a row of a Hevy export:
+// title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,
+// set_index,set_type,weight_kg|weight_lbs,reps,distance_km|distance_miles,duration_seconds,rpe
+func (p *parser) hevyRow(line int, row csvRecord) {
+	name := row.get("exercise_title")
+	if name == "" {
+		return
+	}
+
+	startedAt, err := parseHevyTime(row.get("start_time"), p.opts.Location)
+	if err != nil {
+		p.fail(line, "invalid start_time %q", row.get("start_time"))
+		return
+	}
+
+	cols := setColumns{reps: "reps", seconds: "duration_seconds", rpe: "rpe", notes: "exercise_notes"}
+	weightUnit, distanceUnit := WeightUnitKg, DistanceUnitKm
+	cols.weight = "weight_kg"
+	if p.columns.has("weight_lbs") {
+		cols.weight, weightUnit = "weight_lbs", WeightUnitLb
+	}
+	cols.distance = "distance_km"
+	if p.columns.has("distance_miles") {
+		cols.distance, distanceUnit = "distance_miles", DistanceUnitMi
+	}
+
+	set, ok := p.set(line, name, row, cols, weightUnit, distanceUnit)
+	if !ok {
+		return
+	}
+	if setType := row.get("set_type"); setType != "" && setType != "normal" {
+		set.Notes = strings.TrimSpace(setType + " " + set.Notes)
+	}
+
+	fields := model.WorkoutSessionFields{
+		SessionName: row.get("title"),
+		StartedAt:   startedAt,
+		Notes:       row.get("description"),
+	}
+	if endedAt, err := parseHevyTime(row.get("end_time"), p.opts.Location); err == nil && endedAt.After(startedAt) {
+		seconds := int(endedAt.Sub(startedAt).Seconds())
+		fields.DurationSeconds = &seconds
+	}
+
+	session := p.session(row.get("start_time")+"\x00"+row.get("title"), line, fields)
+	session.Sets = append(session.Sets, set)
+}
+
+// fitNotesRow reads a row of a FitNotes export, which has no sessions so the
+// sets of each day are grouped into one:
+// Date,Exercise,Category,Weight (kg)|Weight (lbs),Reps,Distance,Distance Unit,Time,Comment
+func (p *parser) fitNotesRow(line int, row csvRecord) {
+	name := row.get("exercise")
+	if name == "" {
+		return
+	}
+
+	startedAt, err := time.ParseInLocation("2006-01-02", row.get("date"), p.opts.Location)
+	if err != nil {
+		p.fail(line, "invalid date %q", row.get("date"))
+		return
+	}
+
+	cols := setColumns{reps: "reps", distance: "distance", notes: "comment"}
+	weightUnit := WeightUnitKg
+	cols.weight = "weight (kg)"
+	if p.columns.has("weight (lbs)") {
+		cols.weight, weightUnit = "weight (lbs)", WeightUnitLb
+	}
+
+	distanceUnit := DistanceUnitKm
+	switch unit := strings.ToLower(row.get("distance unit")); unit {
+	case "", "km":
+	case "mi", "miles":
+		distanceUnit = DistanceUnitMi
+	case "m", "metres", "meters":
+		distanceUnit = DistanceUnitM
+	default:
+		p.fail(line, "unsupported distance unit %q", unit)
+		return
+	}
+
+	set, ok := p.set(line, name, row, cols, weightUnit, distanceUnit)
+	if !ok {
+		return
+	}
+	if value := row.get("time"); value != "" {
+		seconds, err := parseClockDuration(value)
+		if err != nil {
+			p.fail(line, "invalid time %q", value)
+			return
+		}
+		if seconds > 0 {
+			set.DurationSeconds = &seconds
+		}
+	}
+
+	session := p.session(row.get("date"), line, model.WorkoutSessionFields{
+		SessionName: "Workout",
+		StartedAt:   startedAt,
+	})
+	session.Sets = append(session.Sets, set)
+}
+
+// setColumns names the columns a set is read from; empty names are skipped.
+type setColumns struct {
+	reps, weight, distance, seconds, rpe, notes string
+}
+
+// set reads the measurements common to all exports. Zero values are treated
+// as not recorded, as the applications write 0 for unused fields.
+func (p *parser) set(line int, name string, row csvRecord, cols setColumns, weightUnit WeightUnit, distanceUnit DistanceUnit) (Set, bool) {
+	set := Set{Line: line, ExerciseName: name}
+	ok := true
+	number := func(column string) *float64 {
+		if column == "" {
+			return nil
+		}
+		value, err := parseNumber(row.get(column))
+		if err != nil {
+			p.fail(line, "invalid %s %q", column, row.get(column))
+			ok = false
+			return nil
+		}
+		if value == nil || *value == 0 {
+			return nil
+		}
+		return value
+	}
+
+	if reps := number(cols.reps); reps != nil {
+		n := int(*reps)
+		set.Reps = &n
+	}
+	if weight := number(cols.weight); weight != nil {
+		*weight = units.ToKg(*weight, weightUnit)
+		set.WeightKg, set.EnteredWeightUnit = weight, weightUnit
+	}
+	if distance := number(cols.distance); distance != nil {
+		*distance = units.ToMetres(*distance, distanceUnit)
+		set.DistanceM, set.EnteredDistanceUnit = distance, distanceUnit
+	}
+	if seconds := number(cols.seconds); seconds != nil {
+		n := int(*seconds)
+		set.DurationSeconds = &n
+	}
+	set.Rpe = number(cols.rpe)
+	if cols.notes != "" {
+		set.Notes = row.get(cols.notes)
+	}
+
+	return set, ok
+}
+
+/*
+ * parseNumber reads a decimal that may use a decimal comma and group its
+ * thousands with the other separator, as in 1,234.5 or 1.234,5. When both
+ * appear the last one is the decimal separator; a separator that appears
+ * more than once groups thousands, and a single comma is a decimal comma.
+ * Empty values are returned as nil.
+ */
+func parseNumber(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	comma, dot := strings.LastIndex(value, ","), strings.LastIndex(value, ".")
+	switch {
+	case comma >= 0 && dot >= 0 && comma > dot:
+		value = strings.Replace(strings.ReplaceAll(value, ".", ""), ",", ".", 1)
+	case comma >= 0 && dot >= 0, strings.Count(value, ",") > 1:
+		value = strings.ReplaceAll(value, ",", "")
+	case strings.Count(value, ".") > 1:
+		value = strings.ReplaceAll(value, ".", "")
+	default:
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+var strongDurationPart = regexp.MustCompile(`(\d+)\s*([hms])`)
+
+// parseStrongDuration reads durations such as "1h 5m" or "45m".
+func parseStrongDuration(value string) *int {
+	parts := strongDurationPart.FindAllStringSubmatch(value, -1)
+	if len(parts) == 0 {
+		return nil
+	}
+
+	seconds := 0
+	for _, part := range parts {
+		n, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "h":
+			seconds += n * 3600
+		case "m":
+			seconds += n * 60
+		case "s":
+			seconds += n
+		}
+	}
+	return &seconds
+}
+
+// parseClockDuration reads h:mm:ss or mm:ss.
+func parseClockDuration(value string) (int, error) {
+	seconds := 0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, nil
+}
+
+var hevyTimeLayouts = []string{"2 Jan 2006, 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+func parseHevyTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range hevyTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
@@ -0,0 +1,154 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Strong(t *testing.T) {
+	input := utf8BOM + `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2025-03-01 08:30:00,Legs,1h 5m,Squat (Barbell),1,100,5,0,0,,Felt good,8
+2025-03-01 08:30:00,Legs,1h 5m,Squat (Barbell),Rest Timer,0,0,0,90,,,
+2025-03-01 08:30:00,Legs,1h 5m,Squat (Barbell),2,102.5,5,0,0,grind,Felt good,
+2025-03-03 18:00:00,Cardio,30m,Running,1,0,0,5,1800,,,
+`
+	result, err := Parse(strings.NewReader(input), "", ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceStrong, result.Source)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Sessions, 2)
+
+	legs := result.Sessions[0]
+	assert.Equal(t, "Legs", legs.SessionName)
+	assert.Equal(t, time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC), legs.StartedAt)
+	assert.Equal(t, 3900, *legs.DurationSeconds)
+	assert.Equal(t, "Felt good", legs.Notes)
+	assert.Len(t, legs.Sets, 2)
+	assert.Equal(t, "Squat (Barbell)", legs.Sets[0].ExerciseName)
+	assert.Equal(t, 5, *legs.Sets[0].Reps)
+	assert.Equal(t, 100.0, *legs.Sets[0].WeightKg)
+	assert.Equal(t, 8.0, *legs.Sets[0].Rpe)
+	assert.Nil(t, legs.Sets[0].DistanceM)
+	assert.Nil(t, legs.Sets[1].Rpe)
+	assert.Equal(t, "grind", legs.Sets[1].Notes)
+
+	run := result.Sessions[1].Sets[0]
+	assert.Equal(t, 5000.0, *run.DistanceM)
+	assert.Equal(t, 1800, *run.DurationSeconds)
+	assert.Nil(t, run.Reps)
+}
+
+func TestParse_StrongSemicolonAndPounds(t *testing.T) {
+	input := `Date;Workout Name;Exercise Name;Set Order;Weight;Reps
+2025-03-01 08:30:00;Legs;Squat;1;225,5;5
+`
+	loc := time.FixedZone("CET", 3600)
+	result, err := Parse(strings.NewReader(input), SourceStrong, ParseOptions{Location: loc, WeightUnit: WeightUnitLb})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.InDelta(t, 102.285, *result.Sessions[0].Sets[0].WeightKg, 0.001)
+	assert.Equal(t, WeightUnitLb, result.Sessions[0].Sets[0].EnteredWeightUnit)
+	assert.Equal(t, time.Date(2025, 3, 1, 7, 30, 0, 0, time.UTC), result.Sessions[0].StartedAt.UTC())
+}
+
+func TestParse_Hevy(t *testing.T) {
+	input := `title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_lbs,reps,distance_miles,duration_seconds,rpe
+Push,"1 Mar 2025, 08:30","1 Mar 2025, 09:15",,Bench Press (Barbell),,,0,warmup,100,10,,,
+Push,"1 Mar 2025, 08:30","1 Mar 2025, 09:15",,Bench Press (Barbell),,paused,1,normal,200,5,,,9
+Push,"1 Mar 2025, 08:30","1 Mar 2025, 09:15",,Treadmill,,,0,normal,,,2,900,
+`
+	result, err := Parse(strings.NewReader(input), "", ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceHevy, result.Source)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Sessions, 1)
+
+	push := result.Sessions[0]
+	assert.Equal(t, "Push", push.SessionName)
+	assert.Equal(t, 2700, *push.DurationSeconds)
+	assert.Len(t, push.Sets, 3)
+	assert.Equal(t, "warmup", push.Sets[0].Notes)
+	assert.InDelta(t, 45.359, *push.Sets[0].WeightKg, 0.001)
+	assert.Equal(t, "paused", push.Sets[1].Notes)
+	assert.Equal(t, 9.0, *push.Sets[1].Rpe)
+	assert.InDelta(t, 3218.688, *push.Sets[2].DistanceM, 0.001)
+	assert.Equal(t, DistanceUnitMi, push.Sets[2].EnteredDistanceUnit)
+	assert.Equal(t, 900, *push.Sets[2].DurationSeconds)
+}
+
+func TestParse_FitNotes(t *testing.T) {
+	input := `Date,Exercise,Category,Weight (kg),Reps,Distance,Distance Unit,Time,Comment
+2025-03-01,Deadlift,Back,140,3,,,,
+2025-03-01,Rowing Machine,Cardio,,,2000,m,0:08:30,
+2025-03-02,Deadlift,Back,145,3,,,,new pr
+2025-03-02,Rowing Machine,Cardio,,,"5,000.0",m,0:21:00,
+`
+	result, err := Parse(strings.NewReader(input), "", ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceFitNotes, result.Source)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Sessions, 2)
+
+	first := result.Sessions[0]
+	assert.Equal(t, "Workout", first.SessionName)
+	assert.Len(t, first.Sets, 2)
+	assert.Equal(t, 2000.0, *first.Sets[1].DistanceM)
+	assert.Equal(t, DistanceUnitM, first.Sets[1].EnteredDistanceUnit)
+	assert.Equal(t, 510, *first.Sets[1].DurationSeconds)
+	assert.Equal(t, "new pr", result.Sessions[1].Sets[0].Notes)
+	assert.Equal(t, 5000.0, *result.Sessions[1].Sets[1].DistanceM)
+}
+
+func TestParseNumber(t *testing.T) {
+	for value, want := range map[string]float64{
+		"102.5":        102.5,
+		"102,5":        102.5,
+		"1,234.5":      1234.5,
+		"1.234,5":      1234.5,
+		"1,234,567":    1234567,
+		"1.234.567":    1234567,
+		"1,234,567.25": 1234567.25,
+	} {
+		n, err := parseNumber(value)
+		if assert.NoError(t, err, value) {
+			assert.Equal(t, want, *n, value)
+		}
+	}
+
+	n, err := parseNumber("")
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+	_, err = parseNumber("1,2.3,4")
+	assert.Error(t, err)
+}
+
+func TestParse_RowErrors(t *testing.T) {
+	input := `Date,Workout Name,Exercise Name,Set Order,Weight,Reps
+yesterday,Legs,Squat,1,100,5
+2025-03-01 08:30:00,Legs,Squat,1,heavy,5
+2025-03-01 08:30:00,Legs,Squat,2,100,5
+`
+	result, err := Parse(strings.NewReader(input), SourceStrong, ParseOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.HistoryRowError{
+		{Line: 2, Error: `invalid date "yesterday"`},
+		{Line: 3, Error: `invalid weight "heavy"`},
+	}, result.Errors)
+	assert.Len(t, result.Sessions, 1)
+	assert.Len(t, result.Sessions[0].Sets, 1)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(""), "", ParseOptions{})
+	assert.EqualError(t, err, "csv input is empty")
+
+	_, err = Parse(strings.NewReader("a,b\n1,2\n"), "", ParseOptions{})
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader("Date,Exercise Name\n"), SourceStrong, ParseOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source is a tracking application whose CSV export can be imported.
+type Source string
+
+const (
+	SourceStrong   Source = "strong"
+	SourceHevy     Source = "hevy"
+	SourceFitNotes Source = "fitnotes"
+)
+
+// ParseSource converts a user supplied application name into a Source.
+func ParseSource(name string) (Source, error) {
+	switch source := Source(strings.ToLower(strings.TrimSpace(name))); source {
+	case SourceStrong, SourceHevy, SourceFitNotes:
+		return source, nil
+	}
+	return "", fmt.Errorf("unsupported source %q, expected strong, hevy or fitnotes", name)
+}
+
+// DetectSource guesses the exporting application from a CSV header.
+func DetectSource(header []string) (Source, error) {
+	columns := columnIndex(header)
+	switch {
+	case columns.has("workout name") && columns.has("exercise name"):
+		return SourceStrong, nil
+	case columns.has("exercise_title") && columns.has("start_time"):
+		return SourceHevy, nil
+	case columns.has("exercise") && columns.has("date") && (columns.has("weight (kg)") || columns.has("weight (lbs)")):
+		return SourceFitNotes, nil
+	}
+	return "", fmt.Errorf("unable to detect the source application from the csv header")
+}
@@ -0,0 +1,35 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource(" Hevy ")
+	assert.NoError(t, err)
+	assert.Equal(t, SourceHevy, source)
+
+	_, err = ParseSource("myfitnesspal")
+	assert.EqualError(t, err, `unsupported source "myfitnesspal", expected strong, hevy or fitnotes`)
+}
+
+func TestDetectSource(t *testing.T) {
+	tests := []struct {
+		header []string
+		want   Source
+	}{
+		{[]string{"Date", "Workout Name", "Duration", "Exercise Name", "Set Order", "Weight", "Reps"}, SourceStrong},
+		{[]string{"title", "start_time", "end_time", "exercise_title", "weight_kg", "reps"}, SourceHevy},
+		{[]string{"Date", "Exercise", "Category", "Weight (lbs)", "Reps"}, SourceFitNotes},
+	}
+	for _, tt := range tests {
+		source, err := DetectSource(tt.header)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, source)
+	}
+
+	_, err := DetectSource([]string{"date", "exercise"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+// Package i18n picks the language of responses from the Accept-Language
+// header of a request.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language the catalog itself is written in. It is
+// always available and used when nothing the client accepts is.
+const DefaultLocale = "en"
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// NormalizeLocale checks a BCP 47 language tag and writes it the canonical
+// way: lower case language, upper case region, e.g. "de-AT".
+func NormalizeLocale(tag string) (string, error) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if !localePattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid locale %q", tag)
+	}
+
+	parts := strings.Split(tag, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case len(parts[i]) == 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case len(parts[i]) == 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-"), nil
+}
+
+type preference struct {
+	tag     string
+	quality float64
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language header, most
+// preferred first. Invalid entries and entries with q=0 are dropped.
+func parseAcceptLanguage(header string) []preference {
+	var prefs []preference
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			value, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = value
+		}
+		if quality <= 0 {
+			continue
+		}
+		if tag = strings.TrimSpace(tag); tag == "*" {
+			prefs = append(prefs, preference{tag: tag, quality: quality})
+			continue
+		}
+		if normalized, err := NormalizeLocale(tag); err == nil {
+			prefs = append(prefs, preference{tag: normalized, quality: quality})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].quality > prefs[j].quality
+	})
+	return prefs
+}
+
+// Negotiate picks the available locale that best matches an Accept-Language
+// header. A preference matches a locale with the same tag, a more specific
+// one ("de" matches "de-AT") or a more general one ("de-AT" matches "de").
+// Without a match the default locale is returned.
+func Negotiate(header string, available []string) string {
+	locales := append([]string{DefaultLocale}, available...)
+	for _, pref := range parseAcceptLanguage(header) {
+		if pref.tag == "*" {
+			return DefaultLocale
+		}
+		if match := bestMatch(pref.tag, locales); match != "" {
+			return match
+		}
+	}
+	return DefaultLocale
+}
+
+func bestMatch(tag string, locales []string) string {
+	for _, locale := range locales {
+		if strings.EqualFold(locale, tag) {
+			return locale
+		}
+	}
+	// "de-AT" falls back to "de" and then to any other "de-…"
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range locales {
+		if strings.EqualFold(locale, language) {
+			return locale
+		}
+	}
+	for _, locale := range locales {
+		if base, _, _ := strings.Cut(locale, "-"); strings.EqualFold(base, language) {
+			return locale
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"de", "de"},
+		{"DE-at", "de-AT"},
+		{"pt_br", "pt-BR"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeLocale(tt.tag)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := NormalizeLocale("deutsch!")
+	assert.EqualError(t, err, `invalid locale "deutsch!"`)
+}
+
+func TestNegotiate(t *testing.T) {
+	available := []string{"de", "fr-CA", "pl"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT,de;q=0.9,en;q=0.8", "de"},
+		{"fr", "fr-CA"},
+		{"es, pl;q=0.5", "pl"},
+		{"pl;q=0.3, de;q=0.7", "de"},
+		{"en-GB", "en"},
+		{"es, *;q=0.1", "en"},
+		{"de;q=0, pl", "pl"},
+		{"ja", "en"},
+		{"garbage;;;", "en"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header, available), tt.header)
+	}
+}
@@ -0,0 +1,148 @@
+// Package logging builds the structured logger of the service and carries
+// it, with the ID of the request being served, through contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Format is how log records are written.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+)
+
+type Config struct {
+	Format Format
+	Level  slog.Level
+}
+
+// Redacted replaces secrets in log records.
+const Redacted = "[REDACTED]"
+
+/*
+ * ConfigFromEnv reads LOG_FORMAT, json or text, and LOG_LEVEL, one of
+ * debug, info, warn and error. Logs are text at info level by default.
+ */
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Format: FormatText, Level: slog.LevelInfo}
+	if value := os.Getenv("LOG_FORMAT"); value != "" {
+		cfg.Format = Format(strings.ToLower(value))
+		if cfg.Format != FormatJSON && cfg.Format != FormatText {
+			return Config{}, fmt.Errorf("LOG_FORMAT must be %s or %s, not %q", FormatJSON, FormatText, value)
+		}
+	}
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := cfg.Level.UnmarshalText([]byte(value)); err != nil {
+			return Config{}, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+/*
+ * New creates a logger writing to w. Records logged with a context get the
+ * ID of its request, and attributes named like a secret, e.g. password or
+ * token, are redacted.
+ */
+func New(w io.Writer, cfg Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Level, ReplaceAttr: redactAttr}
+	var h slog.Handler
+	if cfg.Format == FormatJSON {
+		h = slog.NewJSONHandler(w, opts)
+	} else {
+		h = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+// Discard returns a logger that drops every record, for tests and tools.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of its request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID and the trace ID of the context to
+// every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// secretKey matches the names of attributes that hold secrets.
+var secretKey = regexp.MustCompile(`(?i)(password|secret|token)`)
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if secretKey.MatchString(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+// dsnPassword matches the password of a key=value or URL connection string.
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S*)|(://[^:/@]*:)([^@]*)(@)`)
+
+// Redact hides the password in a database connection string, given as
+// key=value pairs or as a URL.
+func Redact(dsn string) string {
+	return dsnPassword.ReplaceAllStringFunc(dsn, func(match string) string {
+		m := dsnPassword.FindStringSubmatch(match)
+		if m[1] != "" {
+			return m[1] + Redacted
+		}
+		return m[3] + Redacted + m[5]
+	})
+}
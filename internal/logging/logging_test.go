@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name, format, level string
+		expected            Config
+		fails               bool
+	}{
+		{"defaults", "", "", Config{Format: FormatText, Level: slog.LevelInfo}, false},
+		{"json debug", "JSON", "debug", Config{Format: FormatJSON, Level: slog.LevelDebug}, false},
+		{"text warn", "text", "WARN", Config{Format: FormatText, Level: slog.LevelWarn}, false},
+		{"unknown format", "xml", "", Config{}, true},
+		{"unknown level", "", "loud", Config{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOG_FORMAT", tt.format)
+			t.Setenv("LOG_LEVEL", tt.level)
+
+			cfg, err := ConfigFromEnv()
+			if tt.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{Format: FormatJSON, Level: slog.LevelInfo})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "dao").InfoContext(ctx, "connected", "password", "hunter2", "feed_token", "abc", "rows", 3)
+	logger.DebugContext(ctx, "hidden below the level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "connected", record["msg"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "dao", record["component"])
+	assert.Equal(t, Redacted, record["password"])
+	assert.Equal(t, Redacted, record["feed_token"])
+	assert.Equal(t, 3.0, record["rows"])
+}
+
+func TestNew_TraceID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{Format: FormatText, Level: slog.LevelInfo})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	logger.InfoContext(ctx, "traced")
+
+	assert.Contains(t, buf.String(), "trace_id=4bf92f3577b34da6a3ce929d0e0e4736")
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{Format: FormatText, Level: slog.LevelInfo})
+
+	logger.Info("started")
+	assert.Contains(t, buf.String(), "level=INFO msg=started")
+	assert.NotContains(t, buf.String(), "request_id", "no request, no ID")
+}
+
+func TestFromContext(t *testing.T) {
+	logger := Discard()
+	assert.Same(t, logger, FromContext(NewContext(context.Background(), logger)))
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		dsn, expected string
+	}{
+		{"host=db port=5432 user=shred password=s3cr3t dbname=shred sslmode=disable",
+			"host=db port=5432 user=shred password=[REDACTED] dbname=shred sslmode=disable"},
+		{"host=db password='with space' dbname=shred", "host=db password=[REDACTED] dbname=shred"},
+		{"postgres://shred:s3cr3t@db:5432/shred?sslmode=disable", "postgres://shred:[REDACTED]@db:5432/shred?sslmode=disable"},
+		{"postgres://db:5432/shred", "postgres://db:5432/shred"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Redact(tt.dsn))
+		})
+	}
+}
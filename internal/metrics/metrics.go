@@ -0,0 +1,113 @@
+// Package metrics holds the Prometheus metrics of the service: requests
+// served, database queries run and what users log.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "shred"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dao_query_duration_seconds",
+		Help:      "Time taken by DAO methods, by DAO and method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"dao", "method"})
+
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dao_query_errors_total",
+		Help:      "DAO method calls that failed, by DAO and method.",
+	}, []string{"dao", "method"})
+
+	// WorkoutsLogged counts the workout sessions stored, by source: shred
+	// for sessions logged in the app, else the app they were imported from.
+	WorkoutsLogged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workouts_logged_total",
+		Help:      "Workout sessions logged, by source.",
+	}, []string{"source"})
+
+	// SetsLogged counts the sets of the workout sessions stored.
+	SetsLogged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sets_logged_total",
+		Help:      "Sets logged in workout sessions.",
+	})
+
+	// WorkoutsScheduled counts the workouts users planned.
+	WorkoutsScheduled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workouts_scheduled_total",
+		Help:      "Workouts scheduled.",
+	})
+
+	// MeasurementsRecorded counts the body measurements stored, by kind.
+	MeasurementsRecorded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "measurements_recorded_total",
+		Help:      "Body measurements recorded, by kind.",
+	}, []string{"kind"})
+)
+
+/*
+ * NewRegistry creates a registry with the metrics of the service, those of
+ * the Go runtime and the process, and the connection pool stats of db, such
+ * as open, in use and idle connections and waits for one, unless db is nil.
+ */
+func NewRegistry(db *sql.DB) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, queryDuration, queryErrors,
+		WorkoutsLogged, SetsLogged, WorkoutsScheduled, MeasurementsRecorded,
+	)
+	if db != nil {
+		reg.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+	return reg
+}
+
+// Handler serves the metrics of reg in the Prometheus text format.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
+
+// ObserveRequest records a request to route answered with status.
+func ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// ObserveQuery records a call of method of dao, counting it as an error
+// when failed.
+func ObserveQuery(dao, method string, elapsed time.Duration, failed bool) {
+	queryDuration.WithLabelValues(dao, method).Observe(elapsed.Seconds())
+	if failed {
+		queryErrors.WithLabelValues(dao, method).Inc()
+	}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestObserveRequest(t *testing.T) {
+	before := testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/cardio/:uuid", "404"))
+
+	ObserveRequest("GET", "/cardio/:uuid", http.StatusNotFound, 20*time.Millisecond)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/cardio/:uuid", "404")))
+}
+
+func TestObserveQuery(t *testing.T) {
+	before := testutil.ToFloat64(queryErrors.WithLabelValues("TestDao", "Get"))
+
+	ObserveQuery("TestDao", "Get", time.Millisecond, false)
+	assert.Equal(t, before, testutil.ToFloat64(queryErrors.WithLabelValues("TestDao", "Get")))
+
+	ObserveQuery("TestDao", "Get", time.Millisecond, true)
+	assert.Equal(t, before+1, testutil.ToFloat64(queryErrors.WithLabelValues("TestDao", "Get")))
+}
+
+func TestHandler(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ObserveRequest("GET", "/metrics", http.StatusOK, time.Millisecond)
+	ObserveQuery("TestDao", "Get", time.Millisecond, false)
+	WorkoutsLogged.WithLabelValues("strong").Inc()
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	Handler(NewRegistry(db)).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body, _ := io.ReadAll(w.Body)
+	for _, name := range []string{
+		`shred_http_requests_total{method="GET",route="/metrics",status="200"}`,
+		`shred_http_request_duration_seconds_bucket{method="GET",route="/metrics",status="200",le="0.005"}`,
+		`shred_dao_query_duration_seconds_count{dao="TestDao",method="Get"}`,
+		`shred_workouts_logged_total{source="strong"}`,
+		`go_sql_open_connections{db_name="shred"}`,
+		`go_sql_in_use_connections{db_name="shred"}`,
+		`go_sql_idle_connections{db_name="shred"}`,
+		`go_sql_wait_count_total{db_name="shred"}`,
+		"go_goroutines",
+	} {
+		assert.Contains(t, string(body), name)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+ * Deprecated marks the responses of an old API version, served under
+ * prefix: the Deprecation header (RFC 9745) says since when, the Sunset
+ * header (RFC 8594) when the version goes away and a successor-version
+ * link points to the same path under successor.
+ */
+func Deprecated(prefix, successor string, since, sunset time.Time) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+		ctx.Header("Sunset", sunsetDate)
+		if path, ok := strings.CutPrefix(ctx.Request.URL.Path, prefix); ok {
+			ctx.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, path))
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	since := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC)
+	v1 := router.Group("/v1", Deprecated("/v1", "/v2", since, sunset))
+	v1.GET("/exercises/:uuid", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	router.GET("/v2/exercises/:uuid", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/v1/exercises/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/exercises/42>; rel="successor-version"`, w.Header().Get("Link"))
+
+	r, _ = http.NewRequest(http.MethodGet, "/v2/exercises/42", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+}
@@ -0,0 +1,102 @@
+// Package middleware holds the gin middleware every request passes through.
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/logging"
+)
+
+// RequestIDHeader carries the ID that correlates the log lines of a
+// request, taken from the client or made up.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs taken from clients.
+const maxRequestIDLength = 128
+
+/*
+ * RequestID takes the X-Request-ID header of a request, or a new UUID when
+ * it is missing or unusable, puts it in the request context and echoes it
+ * in the response.
+ */
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
+/*
+ * Logger puts logger in the request context for handlers and DAOs and logs
+ * each request once it is served: at error level for server errors, warn
+ * for client errors and info otherwise. Requests are logged by route rather
+ * than by path, so secrets in paths, like calendar feed tokens, stay out of
+ * the log.
+ */
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Request = ctx.Request.WithContext(logging.NewContext(ctx.Request.Context(), logger))
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(ctx.Request.Context(), level, "request",
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", route(ctx)),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.String("client_ip", ctx.ClientIP()))
+	}
+}
+
+// Recovery answers a panicking request with 500 and logs the panic.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.ErrorContext(ctx.Request.Context(), "panic serving request", "error", err)
+				ctx.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		ctx.Next()
+	}
+}
+
+// route is the route template a request matched, like /cardio/:uuid.
+func route(ctx *gin.Context) string {
+	if route := ctx.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// validRequestID accepts printable ASCII IDs of reasonable length, so
+// clients cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
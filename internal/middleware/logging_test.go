@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoggedRouter(buf *bytes.Buffer) *gin.Engine {
+	logger := logging.New(buf, logging.Config{Format: logging.FormatJSON, Level: slog.LevelInfo})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Logger(logger), Recovery(logger))
+	router.GET("/calendar/:feed", func(ctx *gin.Context) {
+		logging.FromContext(ctx.Request.Context()).InfoContext(ctx.Request.Context(), "serving feed")
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+	return router
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		result = append(result, record)
+	}
+	return result
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name, header string
+		kept         bool
+	}{
+		{"from client", "abc-123", true},
+		{"missing", "", false},
+		{"forged line", "abc\nlevel=ERROR", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			router := newLoggedRouter(&buf)
+
+			r, _ := http.NewRequest(http.MethodGet, "/calendar/secret.ics", nil)
+			r.Header.Set(RequestIDHeader, tt.header)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, tt.kept, id == tt.header)
+			for _, record := range records(t, &buf) {
+				assert.Equal(t, id, record["request_id"], record["msg"])
+			}
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+
+	r, _ := http.NewRequest(http.MethodGet, "/calendar/secret.ics", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	r, _ = http.NewRequest(http.MethodGet, "/nowhere", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.NotContains(t, buf.String(), "secret", "paths are not logged")
+	logged := records(t, &buf)
+	assert.Len(t, logged, 3)
+	assert.Equal(t, "serving feed", logged[0]["msg"])
+	assert.Equal(t, "request", logged[1]["msg"])
+	assert.Equal(t, "INFO", logged[1]["level"])
+	assert.Equal(t, "/calendar/:feed", logged[1]["route"])
+	assert.Equal(t, 200.0, logged[1]["status"])
+	assert.Equal(t, "WARN", logged[2]["level"])
+	assert.Equal(t, "unmatched", logged[2]["route"])
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	router := newLoggedRouter(&buf)
+
+	r, _ := http.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	logged := records(t, &buf)
+	assert.Len(t, logged, 2)
+	assert.Equal(t, "panic serving request", logged[0]["msg"])
+	assert.Equal(t, "boom", logged[0]["error"])
+	assert.Equal(t, "ERROR", logged[1]["level"])
+}
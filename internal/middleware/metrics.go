@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/metrics"
+)
+
+// Metrics records each request in the HTTP metrics by route, so paths with
+// IDs in them do not each get their own series.
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		metrics.ObserveRequest(method(ctx.Request.Method), route(ctx), ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// method is the method of a request, or OTHER for one HTTP does not define,
+// so clients cannot add series by making methods up.
+func method(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "OTHER"
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/cardio/:uuid", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	reg := metrics.NewRegistry(nil)
+	router.GET("/metrics", gin.WrapH(metrics.Handler(reg)))
+
+	for _, path := range []string{"/cardio/1", "/cardio/2", "/nowhere"} {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	for _, m := range []string{"BREW", "get", "X-RANDOM-1"} {
+		r, _ := http.NewRequest(m, "/cardio/3", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `shred_http_requests_total{method="GET",route="/cardio/:uuid",status="204"} 2`)
+	assert.Contains(t, body, `shred_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.False(t, strings.Contains(body, "/cardio/1"), "paths are not labels")
+	assert.Contains(t, body, `shred_http_requests_total{method="OTHER",route="unmatched",status="404"} 3`)
+	assert.False(t, strings.Contains(body, "BREW"), "made up methods are not labels")
+}
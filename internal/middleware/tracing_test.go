@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pwydra/shred/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedRouter(t *testing.T) (*gin.Engine, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tracing.NewProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/cardio/:uuid", func(ctx *gin.Context) {
+		_, span := otel.Tracer("test").Start(ctx.Request.Context(), "getCardioDQL")
+		span.End()
+		ctx.Status(http.StatusOK)
+	})
+	router.GET("/failing", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("database is gone"))
+		ctx.Status(http.StatusInternalServerError)
+	})
+	return router, exporter
+}
+
+func TestTracing(t *testing.T) {
+	router, exporter := newTracedRouter(t)
+
+	r, _ := http.NewRequest(http.MethodGet, "/cardio/1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	query, request := spans[0], spans[1]
+	assert.Equal(t, "GET /cardio/:uuid", request.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", request.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", request.Parent.SpanID().String())
+	assert.Equal(t, request.SpanContext.SpanID(), query.Parent.SpanID())
+	assert.Contains(t, request.Attributes, attribute.String("http.route", "/cardio/:uuid"))
+	assert.Contains(t, request.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Equal(t, codes.Unset, request.Status.Code)
+}
+
+func TestTracing_ServerError(t *testing.T) {
+	router, exporter := newTracedRouter(t)
+
+	r, _ := http.NewRequest(http.MethodGet, "/failing", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.False(t, spans[0].Parent.IsValid(), "a new trace")
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}
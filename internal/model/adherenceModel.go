@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * AdherenceWeek is how a user kept to their schedule in a week starting on
+ * Monday. A week is kept when the user trained in it and missed none of the
+ * workouts they scheduled.
+ */
+type AdherenceWeek struct {
+	Week      string `json:"week"`
+	Scheduled int    `json:"scheduled"`
+	Completed int    `json:"completed"`
+	Missed    int    `json:"missed"`
+	// Unplanned counts the sessions that completed no scheduled workout.
+	Unplanned int  `json:"unplanned"`
+	Kept      bool `json:"kept"`
+}
+
+// AdherenceWindow is adherence over the Days up to now.
+type AdherenceWindow struct {
+	Days      int `json:"days"`
+	Due       int `json:"due"`
+	Completed int `json:"completed"`
+	Missed    int `json:"missed"`
+	Unplanned int `json:"unplanned"`
+	// Score is the percentage of the workouts due that were completed, nil
+	// when none were due.
+	Score *float64 `json:"score"`
+}
+
+type AdherenceReport struct {
+	UserUuid   uuid.UUID `json:"userUuid"`
+	ComputedAt time.Time `json:"computedAt"`
+	TimeZone   string    `json:"timeZone"`
+	Scheduled  int       `json:"scheduled"`
+	Completed  int       `json:"completed"`
+	Missed     int       `json:"missed"`
+	Upcoming   int       `json:"upcoming"`
+	Unplanned  int       `json:"unplanned"`
+	// CurrentStreak and LongestStreak are runs of kept weeks.
+	CurrentStreak int               `json:"currentStreak"`
+	LongestStreak int               `json:"longestStreak"`
+	MissedDays    []string          `json:"missedDays"`
+	Weeks         []AdherenceWeek   `json:"weeks"`
+	Windows       []AdherenceWindow `json:"windows"`
+}
+
+// RosterClient is a user on the roster of a coach.
+type RosterClient struct {
+	UserUuid  uuid.UUID `json:"userUuid" db:"user_uuid"`
+	FirstName string    `json:"firstName" db:"first_name"`
+	LastName  string    `json:"lastName" db:"last_name"`
+	AddedAt   time.Time `json:"addedAt" db:"created_at"`
+}
+
+type ClientAdherence struct {
+	RosterClient
+	Adherence *AdherenceReport `json:"adherence"`
+}
+
+/*
+ * RosterAdherence is the adherence of each client of a coach. Windows pool
+ * the workouts of all clients, so a client with a busy schedule weighs more
+ * than one who scheduled little.
+ */
+type RosterAdherence struct {
+	CoachUuid  uuid.UUID `json:"coachUuid"`
+	ComputedAt time.Time `json:"computedAt"`
+	// OnStreak counts the clients with a current streak.
+	OnStreak int               `json:"onStreak"`
+	Windows  []AdherenceWindow `json:"windows"`
+	Clients  []ClientAdherence `json:"clients"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// Analytics metrics besides the measurement kinds.
+const MetricVolume = "volume"
+
+type SeriesPoint struct {
+	At    time.Time `json:"at" db:"at"`
+	Value float64   `json:"value" db:"value"`
+}
+
+/*
+ * Series is one metric over time, e.g. bodyweight or the daily training
+ * volume in kg lifted. Trend smooths the points with a moving average.
+ */
+type Series struct {
+	Metric string        `json:"metric"`
+	Site   string        `json:"site,omitempty"`
+	Unit   string        `json:"unit"`
+	Points []SeriesPoint `json:"points"`
+	Trend  []SeriesPoint `json:"trend"`
+}
+
+type TimeSeries struct {
+	UserUuid        uuid.UUID `json:"userUuid"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	TrendWindowDays int       `json:"trendWindowDays"`
+	Series          []Series  `json:"series"`
+}
+
+// Convert gives the series in kg and cm in the units of c.
+func (ts *TimeSeries) Convert(c units.Converter) {
+	for i := range ts.Series {
+		var convert func(float64) float64
+		switch ts.Series[i].Unit {
+		case string(units.Kg):
+			convert, ts.Series[i].Unit = c.Weight, string(c.System().WeightUnit())
+		case "cm":
+			convert, ts.Series[i].Unit = c.Length, c.System().LengthUnit()
+		default:
+			continue
+		}
+		for _, points := range [][]SeriesPoint{ts.Series[i].Points, ts.Series[i].Trend} {
+			for j := range points {
+				points[j].Value = convert(points[j].Value)
+			}
+		}
+	}
+}
+
+// MuscleVolume is the training volume of the sets of exercises working a
+// muscle or any muscle below it, in kg lifted. A set working several of
+// these muscles counts once.
+type MuscleVolume struct {
+	MuscleCode  string      `json:"muscleCode" db:"muscle_code"`
+	MuscleName  string      `json:"muscleName" db:"muscle_name"`
+	MuscleLevel MuscleLevel `json:"muscleLevel" db:"muscle_level"`
+	Sets        int         `json:"sets" db:"sets"`
+	Volume      float64     `json:"volume" db:"volume"`
+}
+
+// MuscleVolumes rolls the training volume of a user up to the muscles of one
+// level of the hierarchy, e.g. the regions, largest volume first.
+type MuscleVolumes struct {
+	UserUuid uuid.UUID      `json:"userUuid"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Level    MuscleLevel    `json:"level"`
+	Unit     string         `json:"unit"`
+	Muscles  []MuscleVolume `json:"muscles"`
+}
+
+// Convert gives the volumes in kg in the weight unit of c.
+func (mv *MuscleVolumes) Convert(c units.Converter) {
+	for i := range mv.Muscles {
+		mv.Muscles[i].Volume = c.Weight(mv.Muscles[i].Volume)
+	}
+	mv.Unit = string(c.System().WeightUnit())
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleStatus is how a scheduled workout turned out.
+type ScheduleStatus string
+
+const (
+	SchedulePlanned   ScheduleStatus = "planned"
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleMissed    ScheduleStatus = "missed"
+)
+
+// maxScheduledMinutes bounds the planned duration of a workout to a day.
+const maxScheduledMinutes = 24 * 60
+
+type ScheduledWorkoutFields struct {
+	SessionName     string    `json:"sessionName" db:"session_name"`
+	ScheduledAt     time.Time `json:"scheduledAt" db:"scheduled_at"`
+	DurationMinutes *int      `json:"durationMinutes,omitempty" db:"duration_minutes"`
+	Notes           string    `json:"notes,omitempty" db:"notes"`
+	// SessionUuid is the logged session that completed the workout. Without
+	// it a session logged around the scheduled time completes the workout.
+	SessionUuid *uuid.UUID `json:"sessionUuid,omitempty" db:"session_uuid"`
+}
+
+// Normalize validates a scheduled workout from a request and moves its time
+// to UTC.
+func (f *ScheduledWorkoutFields) Normalize() error {
+	f.SessionName = strings.TrimSpace(f.SessionName)
+	switch {
+	case f.SessionName == "":
+		return errors.New("sessionName is required")
+	case f.ScheduledAt.IsZero():
+		return errors.New("scheduledAt is required")
+	case f.DurationMinutes != nil && (*f.DurationMinutes <= 0 || *f.DurationMinutes > maxScheduledMinutes):
+		return errors.New("durationMinutes must be above 0 and at most a day")
+	}
+	f.ScheduledAt = f.ScheduledAt.UTC()
+	return nil
+}
+
+type ScheduledWorkout struct {
+	ScheduleUuid uuid.UUID `json:"scheduleUuid" db:"schedule_uuid"`
+	UserUuid     uuid.UUID `json:"userUuid" db:"user_uuid"`
+	ScheduledWorkoutFields
+	Status    ScheduleStatus `json:"status" db:"-"`
+	CreatedAt time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// CalendarSession is a logged session as shown on a calendar, without its
+// sets.
+type CalendarSession struct {
+	SessionUuid     uuid.UUID `json:"sessionUuid" db:"session_uuid"`
+	SessionName     string    `json:"sessionName" db:"session_name"`
+	StartedAt       time.Time `json:"startedAt" db:"started_at"`
+	DurationSeconds *int      `json:"durationSeconds,omitempty" db:"duration_seconds"`
+	Source          string    `json:"source" db:"source"`
+}
+
+// CalendarView is the span of a calendar.
+type CalendarView string
+
+const (
+	CalendarMonth CalendarView = "month"
+	CalendarWeek  CalendarView = "week"
+)
+
+func (v CalendarView) Valid() bool {
+	return v == CalendarMonth || v == CalendarWeek
+}
+
+// CalendarDay holds the workouts scheduled and the sessions logged on a day.
+type CalendarDay struct {
+	Date      string             `json:"date"`
+	Scheduled []ScheduledWorkout `json:"scheduled"`
+	Sessions  []CalendarSession  `json:"sessions"`
+}
+
+type Calendar struct {
+	UserUuid uuid.UUID     `json:"userUuid"`
+	View     CalendarView  `json:"view"`
+	TimeZone string        `json:"timeZone"`
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Days     []CalendarDay `json:"days"`
+}
+
+// CalendarFeed is the secret token of the iCalendar feed of a user and the
+// path the feed is served at.
+type CalendarFeed struct {
+	UserUuid  uuid.UUID `json:"userUuid" db:"user_uuid"`
+	Token     string    `json:"token" db:"feed_token"`
+	Path      string    `json:"path" db:"-"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// CategoryCardio is the category an exercise needs for activities to be
+// logged against it.
+const CategoryCardio = "CARDIO"
+
+// HeartRateSample is a heart rate reading taken offset seconds after the
+// start of an activity.
+type HeartRateSample struct {
+	OffsetSeconds int `json:"offsetSeconds" db:"offset_seconds"`
+	HeartRate     int `json:"heartRate" db:"heart_rate"`
+}
+
+/*
+ * CardioSummary holds the figures derived from a recorded activity. Pace is
+ * in seconds per kilometre; fields the device did not record are nil.
+ */
+type CardioSummary struct {
+	Sport            string   `json:"sport" db:"sport"`
+	DistanceM        float64  `json:"distanceM" db:"distance_m"`
+	DurationSeconds  int      `json:"durationSeconds" db:"duration_seconds"`
+	PaceSecondsPerKm *float64 `json:"paceSecondsPerKm,omitempty" db:"pace_seconds_per_km"`
+	ElevationGainM   *float64 `json:"elevationGainM,omitempty" db:"elevation_gain_m"`
+	ElevationLossM   *float64 `json:"elevationLossM,omitempty" db:"elevation_loss_m"`
+	AvgHeartRate     *int     `json:"avgHeartRate,omitempty" db:"avg_heart_rate"`
+	MaxHeartRate     *int     `json:"maxHeartRate,omitempty" db:"max_heart_rate"`
+}
+
+/*
+ * CardioActivity is a run, ride or other activity recorded on a device and
+ * stored as a workout session with a single set of a cardio exercise.
+ */
+type CardioActivity struct {
+	SessionUuid  uuid.UUID `json:"sessionUuid" db:"session_uuid"`
+	UserUuid     uuid.UUID `json:"userUuid" db:"user_uuid"`
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	SessionName  string    `json:"sessionName" db:"session_name"`
+	StartedAt    time.Time `json:"startedAt" db:"started_at"`
+	Source       string    `json:"source" db:"source"`
+	CardioSummary
+	// Distance and PaceSeconds, per DistanceUnit, are in the units the user
+	// prefers.
+	Distance     float64            `json:"distance" db:"-"`
+	DistanceUnit units.DistanceUnit `json:"distanceUnit" db:"-"`
+	PaceSeconds  *float64           `json:"paceSeconds,omitempty" db:"-"`
+	HeartRate    []HeartRateSample  `json:"heartRate,omitempty" db:"-"`
+	CreatedAt    time.Time          `json:"createdAt" db:"created_at"`
+}
+
+func (a *CardioActivity) Convert(c units.Converter) {
+	a.Distance, a.DistanceUnit = c.Distance(a.DistanceM), c.System().DistanceUnit()
+	if a.PaceSecondsPerKm != nil {
+		pace := c.Pace(*a.PaceSecondsPerKm)
+		a.PaceSeconds = &pace
+	}
+}
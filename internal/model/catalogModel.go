@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * CatalogExercise is an exercise together with the muscles it works and the
+ * apparatus it needs. It is the unit of the bulk import and export formats.
+ */
+type CatalogExercise struct {
+	ExerciseFields
+	Muscles   []ExerciseMuscle `json:"muscles"`
+	Apparatus []string         `json:"apparatus"`
+}
+
+// ImportMode controls how imported exercises are matched to existing ones.
+type ImportMode string
+
+const (
+	// ImportModeInsert creates every row and rejects names that already exist.
+	ImportModeInsert ImportMode = "insert"
+	// ImportModeUpsert updates exercises with a matching name in place.
+	ImportModeUpsert ImportMode = "upsert"
+)
+
+// ImportAction is the outcome of a single imported row.
+type ImportAction string
+
+const (
+	ImportActionCreate  ImportAction = "create"
+	ImportActionUpdate  ImportAction = "update"
+	ImportActionInvalid ImportAction = "invalid"
+)
+
+type ImportRowResult struct {
+	Row          int          `json:"row"`
+	ExerciseName string       `json:"exerciseName"`
+	Action       ImportAction `json:"action"`
+	ExerciseUuid *uuid.UUID   `json:"exerciseUuid,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Mode      ImportMode        `json:"mode"`
+	Committed bool              `json:"committed"`
+	Total     int               `json:"total"`
+	Invalid   int               `json:"invalid"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+/*
+ * CatalogReferences holds the reference types shipped with a catalog bundle.
+ * Audit fields are left out as the creating users only exist in the source
+ * instance.
+ */
+type CatalogReferences struct {
+	Categories []CategoryFields  `json:"categories"`
+	Licenses   []LicenseFields   `json:"licenses"`
+	Muscles    []MuscleFields    `json:"muscles"`
+	Apparatus  []ApparatusFields `json:"apparatus"`
+}
+
+// CatalogManifest describes the contents of a catalog bundle.
+type CatalogManifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	ExportedAt    time.Time `json:"exportedAt"`
+	Categories    int       `json:"categories"`
+	Licenses      int       `json:"licenses"`
+	Muscles       int       `json:"muscles"`
+	Apparatus     int       `json:"apparatus"`
+	Exercises     int       `json:"exercises"`
+}
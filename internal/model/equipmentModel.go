@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type EquipmentProfileFields struct {
+	ProfileName string `json:"profileName" db:"profile_name"`
+	// IsDefault marks the profile used when a user does not pick one. Gym
+	// locations cannot be a default.
+	IsDefault bool `json:"isDefault" db:"is_default"`
+	// Apparatus holds the codes of the apparatus available.
+	Apparatus []string `json:"apparatus" db:"-"`
+}
+
+type EquipmentProfileRequest struct {
+	EquipmentProfileFields
+	CreatedBy uuid.UUID `json:"createdBy" db:"created_by"`
+}
+
+/*
+ * EquipmentProfile is the equipment available to a user at one place, e.g.
+ * at home. Profiles without a user describe a gym location anyone can train
+ * at.
+ */
+type EquipmentProfile struct {
+	ProfileUuid uuid.UUID  `json:"profileUuid" db:"profile_uuid"`
+	UserUuid    *uuid.UUID `json:"userUuid,omitempty" db:"user_uuid"`
+	EquipmentProfileFields
+	AuditRecord
+}
+
+// AvailableExercise is an exercise that needs no apparatus beyond those of an
+// equipment profile.
+type AvailableExercise struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string    `json:"exerciseName" db:"exercise_name"`
+	CategoryCode string    `json:"category" db:"category_code"`
+	// Apparatus lists what the exercise needs, empty for bodyweight ones.
+	Apparatus []string `json:"apparatus" db:"-"`
+}
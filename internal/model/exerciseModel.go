@@ -22,6 +22,9 @@ type ApparatusFields struct {
 	ApparatusGroup string `json:"apparatusGroup" db:"apparatus_group"`
 }
 
+// ApparatusGroupOther is the group of apparatus created without one.
+const ApparatusGroupOther = "Other"
+
 type ApparatusRequest struct {
 	ApparatusFields
 	CreatedBy uuid.UUID `json:"createdBy" db:"created_by"`
@@ -32,11 +35,50 @@ type Apparatus struct {
 	AuditRecord
 }
 
+/*
+ * MuscleLevel places a muscle in the anatomical hierarchy, from the region of
+ * the body down to the head of a muscle, e.g. Legs > Quadriceps > Rectus
+ * femoris. It mirrors the muscle_level enum in the database.
+ */
+type MuscleLevel string
+
+const (
+	MuscleLevelRegion MuscleLevel = "region"
+	MuscleLevelGroup  MuscleLevel = "group"
+	MuscleLevelMuscle MuscleLevel = "muscle"
+	MuscleLevelHead   MuscleLevel = "head"
+)
+
+// muscleLevels lists the levels from the top of the hierarchy down.
+var muscleLevels = []MuscleLevel{MuscleLevelRegion, MuscleLevelGroup, MuscleLevelMuscle, MuscleLevelHead}
+
+// Depth returns 0 for regions and grows towards heads, or -1 for unknown
+// levels.
+func (l MuscleLevel) Depth() int {
+	for i, level := range muscleLevels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// Valid reports whether the level is one of the known muscle levels.
+func (l MuscleLevel) Valid() bool {
+	return l.Depth() >= 0
+}
+
 type MuscleFields struct {
-	MuscleCode  string `json:"muscleCode" db:"muscle_code"`
-	MuscleName  string `json:"muscleName" db:"muscle_name"`
-	MuscleDesc  string `json:"muscleDesc" db:"muscle_description"`
-	MuscleGroup string `json:"muscleGroup" db:"muscle_group"`
+	MuscleCode  string      `json:"muscleCode" db:"muscle_code"`
+	MuscleName  string      `json:"muscleName" db:"muscle_name"`
+	MuscleDesc  string      `json:"muscleDesc" db:"muscle_description"`
+	MuscleGroup string      `json:"muscleGroup" db:"muscle_group"`
+	MuscleLevel MuscleLevel `json:"muscleLevel" db:"muscle_level"`
+	// ParentCode is the muscle one level up, nil for regions.
+	ParentCode *string `json:"parentCode,omitempty" db:"parent_code"`
+	// MovementPattern is push or pull for muscles that drive such a
+	// movement, nil for those that take it from a muscle above them.
+	MovementPattern *MovementPattern `json:"movementPattern,omitempty" db:"movement_pattern"`
 }
 
 type MuscleRequest struct {
@@ -49,6 +91,22 @@ type Muscle struct {
 	AuditRecord
 }
 
+// MuscleNode is a muscle with the muscles one level below it.
+type MuscleNode struct {
+	MuscleFields
+	Children []*MuscleNode `json:"children,omitempty"`
+}
+
+// MuscleExercise is an exercise that works a muscle or one of its
+// descendants, named by MuscleCode.
+type MuscleExercise struct {
+	ExerciseUuid uuid.UUID  `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string     `json:"exerciseName" db:"exercise_name"`
+	CategoryCode string     `json:"category" db:"category_code"`
+	MuscleCode   string     `json:"muscleCode" db:"muscle_code"`
+	MuscleRole   MuscleRole `json:"muscleRole" db:"muscle_role"`
+}
+
 type CategoryFields struct {
 	CategoryCode string `json:"categoryCode" db:"category_code"`
 	CategoryName string `json:"categoryName" db:"category_name"`
@@ -95,10 +153,91 @@ type ExerciseFields struct {
 type ExerciseRequest struct {
 	ExerciseFields
 	CreatedBy uuid.UUID `json:"createdBy" db:"created_by"`
+	// Muscles and Apparatus are linked to the exercise when it is created.
+	Muscles   []ExerciseMuscle `json:"muscles,omitempty"`
+	Apparatus []string         `json:"apparatus,omitempty"`
 }
 
 type Exercise struct {
 	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
 	ExerciseFields
 	AuditRecord
+	// Muscles and Apparatus are only filled in for a new exercise.
+	Muscles   []ExerciseMuscle `json:"muscles,omitempty" db:"-"`
+	Apparatus []string         `json:"apparatus,omitempty" db:"-"`
+}
+
+/*
+ * ExerciseFieldsV2 is ExerciseFields as API v2 sends it, with the license
+ * fields spelt licenseShortName and licenseAuthor. ExerciseFields keeps the
+ * v1 spelling, which catalog files use as well; the two convert into each
+ * other.
+ */
+type ExerciseFieldsV2 struct {
+	ExerciseName     string `json:"exerciseName" validate:"required"`
+	Description      string `json:"description"`
+	Instructions     string `json:"instructions"`
+	Cues             string `json:"cues"`
+	VideoUrl         string `json:"videoUrl"`
+	CategoryCode     string `json:"category"`
+	LicenseShortName string `json:"licenseShortName"`
+	LicenseAuthor    string `json:"licenseAuthor"`
+}
+
+// ExerciseRequestV2 is ExerciseRequest in API v2.
+type ExerciseRequestV2 struct {
+	ExerciseFieldsV2
+	CreatedBy uuid.UUID        `json:"createdBy"`
+	Muscles   []ExerciseMuscle `json:"muscles,omitempty"`
+	Apparatus []string         `json:"apparatus,omitempty"`
+}
+
+func (r ExerciseRequestV2) ExerciseRequest() *ExerciseRequest {
+	return &ExerciseRequest{ExerciseFields: ExerciseFields(r.ExerciseFieldsV2), CreatedBy: r.CreatedBy,
+		Muscles: r.Muscles, Apparatus: r.Apparatus}
+}
+
+// ExerciseV2 is Exercise in API v2.
+type ExerciseV2 struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid"`
+	ExerciseFieldsV2
+	AuditRecord
+	Muscles   []ExerciseMuscle `json:"muscles,omitempty"`
+	Apparatus []string         `json:"apparatus,omitempty"`
+}
+
+func NewExerciseV2(ex *Exercise) *ExerciseV2 {
+	return &ExerciseV2{ExerciseUuid: ex.ExerciseUuid, ExerciseFieldsV2: ExerciseFieldsV2(ex.ExerciseFields),
+		AuditRecord: ex.AuditRecord, Muscles: ex.Muscles, Apparatus: ex.Apparatus}
+}
+
+func (ex ExerciseV2) Exercise() *Exercise {
+	return &Exercise{ExerciseUuid: ex.ExerciseUuid, ExerciseFields: ExerciseFields(ex.ExerciseFieldsV2),
+		AuditRecord: ex.AuditRecord, Muscles: ex.Muscles, Apparatus: ex.Apparatus}
+}
+
+/*
+ * MuscleRole describes how a muscle is engaged by an exercise and mirrors the
+ * muscle_role enum in the database.
+ */
+type MuscleRole string
+
+const (
+	MuscleRolePrimary    MuscleRole = "primary"
+	MuscleRoleSecondary  MuscleRole = "secondary"
+	MuscleRoleStabilizer MuscleRole = "stabilizer"
+)
+
+// Valid reports whether the role is one of the known muscle roles.
+func (r MuscleRole) Valid() bool {
+	switch r {
+	case MuscleRolePrimary, MuscleRoleSecondary, MuscleRoleStabilizer:
+		return true
+	}
+	return false
+}
+
+type ExerciseMuscle struct {
+	MuscleCode string     `json:"muscleCode" db:"muscle_code"`
+	MuscleRole MuscleRole `json:"muscleRole" db:"muscle_role"`
 }
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchMethod records how an external exercise name was resolved.
+type MatchMethod string
+
+const (
+	MatchMethodMapping    MatchMethod = "mapping"
+	MatchMethodExact      MatchMethod = "exact"
+	MatchMethodFuzzy      MatchMethod = "fuzzy"
+	MatchMethodUnresolved MatchMethod = "unresolved"
+)
+
+type MatchCandidate struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid"`
+	ExerciseName string    `json:"exerciseName"`
+	Score        float64   `json:"score"`
+}
+
+type NameResolution struct {
+	ExternalName string           `json:"externalName"`
+	Method       MatchMethod      `json:"method"`
+	ExerciseUuid *uuid.UUID       `json:"exerciseUuid,omitempty"`
+	ExerciseName string           `json:"exerciseName,omitempty"`
+	Score        float64          `json:"score,omitempty"`
+	Candidates   []MatchCandidate `json:"candidates,omitempty"`
+	SetCount     int              `json:"setCount"`
+}
+
+type HistoryRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+/*
+ * HistoryImportReport previews or summarises an import of workout history
+ * from another tracking application.
+ */
+type HistoryImportReport struct {
+	Source            string            `json:"source"`
+	DryRun            bool              `json:"dryRun"`
+	Committed         bool              `json:"committed"`
+	Sessions          int               `json:"sessions"`
+	Sets              int               `json:"sets"`
+	DuplicateSessions int               `json:"duplicateSessions"`
+	SkippedSets       int               `json:"skippedSets"`
+	From              *time.Time        `json:"from,omitempty"`
+	To                *time.Time        `json:"to,omitempty"`
+	Exercises         []NameResolution  `json:"exercises"`
+	Unresolved        int               `json:"unresolved"`
+	Errors            []HistoryRowError `json:"errors,omitempty"`
+	SessionUuids      []uuid.UUID       `json:"sessionUuids,omitempty"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// MeasurementKind is what a body measurement measures.
+type MeasurementKind string
+
+const (
+	MeasurementBodyweight    MeasurementKind = "bodyweight"
+	MeasurementBodyFat       MeasurementKind = "body_fat"
+	MeasurementCircumference MeasurementKind = "circumference"
+)
+
+func (k MeasurementKind) Valid() bool {
+	switch k {
+	case MeasurementBodyweight, MeasurementBodyFat, MeasurementCircumference:
+		return true
+	}
+	return false
+}
+
+// Unit is the unit measurements of the kind are stored and returned in.
+func (k MeasurementKind) Unit() string {
+	switch k {
+	case MeasurementBodyweight:
+		return "kg"
+	case MeasurementBodyFat:
+		return "%"
+	case MeasurementCircumference:
+		return "cm"
+	}
+	return ""
+}
+
+// MeasurementSites are the places circumferences are measured at.
+var MeasurementSites = []string{"NECK", "CHEST", "WAIST", "HIPS", "ARM", "FOREARM", "THIGH", "CALF"}
+
+// measurementUnits converts the units a measurement may be entered in to the
+// unit of its kind.
+var measurementUnits = map[MeasurementKind]map[string]float64{
+	MeasurementBodyweight:    {"kg": 1, "lb": units.KgPerLb},
+	MeasurementBodyFat:       {"%": 1},
+	MeasurementCircumference: {"cm": 1, "in": units.CmPerIn},
+}
+
+type MeasurementFields struct {
+	Kind MeasurementKind `json:"kind" db:"measurement_kind"`
+	// Site is where a circumference is measured and empty for other kinds.
+	Site       string    `json:"site,omitempty" db:"site"`
+	Value      float64   `json:"value" db:"measured_value"`
+	MeasuredAt time.Time `json:"measuredAt" db:"measured_at"`
+	Notes      string    `json:"notes,omitempty" db:"notes"`
+	// EnteredUnit is the unit the value was given in.
+	EnteredUnit string `json:"enteredUnit,omitempty" db:"entered_unit"`
+}
+
+type MeasurementRequest struct {
+	MeasurementFields
+	// Unit is the unit Value is given in, by default the unit of the kind.
+	Unit string `json:"unit"`
+}
+
+/*
+ * Normalize validates the request and converts its value to the unit of its
+ * kind, e.g. pounds to kilograms.
+ */
+func (req *MeasurementRequest) Normalize() error {
+	if !req.Kind.Valid() {
+		return fmt.Errorf("unknown measurement kind %q", req.Kind)
+	}
+	req.Site = strings.ToUpper(strings.TrimSpace(req.Site))
+	switch {
+	case req.Kind == MeasurementCircumference && !slices.Contains(MeasurementSites, req.Site):
+		return fmt.Errorf("site must be one of %s", strings.Join(MeasurementSites, ", "))
+	case req.Kind != MeasurementCircumference && req.Site != "":
+		return fmt.Errorf("only circumferences have a site")
+	case req.MeasuredAt.IsZero():
+		return fmt.Errorf("measuredAt is required")
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(req.Unit))
+	if unit == "" {
+		unit = req.Kind.Unit()
+	}
+	factor, ok := measurementUnits[req.Kind][unit]
+	if !ok {
+		return fmt.Errorf("unit %q does not apply to %s", req.Unit, req.Kind)
+	}
+	// stored with two decimals
+	req.Value = math.Round(req.Value*factor*100) / 100
+	req.Unit, req.EnteredUnit = req.Kind.Unit(), unit
+	switch {
+	case req.Value <= 0:
+		return fmt.Errorf("value must be positive")
+	case req.Kind == MeasurementBodyFat && req.Value >= 100:
+		return fmt.Errorf("body fat must be below 100%%")
+	}
+	req.MeasuredAt = req.MeasuredAt.UTC()
+	return nil
+}
+
+type Measurement struct {
+	MeasurementUuid uuid.UUID `json:"measurementUuid" db:"measurement_uuid"`
+	UserUuid        uuid.UUID `json:"userUuid" db:"user_uuid"`
+	MeasurementFields
+	Unit      string    `json:"unit" db:"-"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Convert gives the value in the units of c.
+func (m *Measurement) Convert(c units.Converter) {
+	switch m.Kind {
+	case MeasurementBodyweight:
+		m.Value, m.Unit = c.Weight(m.Value), string(c.System().WeightUnit())
+	case MeasurementCircumference:
+		m.Value, m.Unit = c.Length(m.Value), c.System().LengthUnit()
+	}
+}
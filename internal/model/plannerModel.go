@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExperienceLevel sets the volume and rest of a generated session.
+type ExperienceLevel string
+
+const (
+	ExperienceBeginner     ExperienceLevel = "beginner"
+	ExperienceIntermediate ExperienceLevel = "intermediate"
+	ExperienceAdvanced     ExperienceLevel = "advanced"
+)
+
+func (l ExperienceLevel) Valid() bool {
+	switch l {
+	case ExperienceBeginner, ExperienceIntermediate, ExperienceAdvanced:
+		return true
+	}
+	return false
+}
+
+/*
+ * MovementPattern tells pushing from pulling exercises, so a session can
+ * balance them. Muscles that drive a push or a pull carry it, mirroring the
+ * movement_pattern enum in the database; an exercise takes the pattern most
+ * of its primary muscles have.
+ */
+type MovementPattern string
+
+const (
+	MovementPush  MovementPattern = "push"
+	MovementPull  MovementPattern = "pull"
+	MovementOther MovementPattern = "other"
+)
+
+/*
+ * SessionPlanRequest describes the session to build. Target muscles match
+ * muscles at any level of the hierarchy. The equipment is the apparatus
+ * listed, else the apparatus of the profile, else of the user's default
+ * profile; without any of them all exercises are considered.
+ */
+type SessionPlanRequest struct {
+	TargetMuscles     []string        `json:"targetMuscles"`
+	Category          string          `json:"category"`
+	ProfileUuid       *uuid.UUID      `json:"profileUuid,omitempty"`
+	Apparatus         []string        `json:"apparatus"`
+	TimeBudgetMinutes int             `json:"timeBudgetMinutes"`
+	Experience        ExperienceLevel `json:"experience"`
+	// Seed makes the plan reproducible, a random one is picked when nil.
+	Seed *uint64 `json:"seed,omitempty"`
+}
+
+// CandidateMuscle is a muscle an exercise works with the codes of the muscle
+// and all muscles above it.
+type CandidateMuscle struct {
+	MuscleCode string     `json:"muscleCode"`
+	MuscleRole MuscleRole `json:"muscleRole"`
+	Ancestors  []string   `json:"ancestors"`
+	// Pattern is that of the nearest muscle at or above this one that has
+	// one, MovementOther when none does.
+	Pattern MovementPattern `json:"pattern"`
+}
+
+// CandidateExercise is an exercise the planner can pick.
+type CandidateExercise struct {
+	ExerciseUuid uuid.UUID         `json:"exerciseUuid"`
+	ExerciseName string            `json:"exerciseName"`
+	CategoryCode string            `json:"category"`
+	Apparatus    []string          `json:"apparatus"`
+	Muscles      []CandidateMuscle `json:"muscles"`
+}
+
+type PlannedExercise struct {
+	ExerciseUuid   uuid.UUID       `json:"exerciseUuid"`
+	ExerciseName   string          `json:"exerciseName"`
+	Pattern        MovementPattern `json:"pattern"`
+	PrimaryMuscles []string        `json:"primaryMuscles"`
+	Sets           int             `json:"sets"`
+	Reps           int             `json:"reps"`
+	RestSeconds    int             `json:"restSeconds"`
+}
+
+/*
+ * SessionPlan is a generated session. Session can be posted to the workouts
+ * of the user as is once the weights are filled in.
+ */
+type SessionPlan struct {
+	Seed             uint64                `json:"seed"`
+	GeneratedAt      time.Time             `json:"generatedAt"`
+	EstimatedSeconds int                   `json:"estimatedSeconds"`
+	Exercises        []PlannedExercise     `json:"exercises"`
+	Session          WorkoutSessionRequest `json:"session"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "github.com/pwydra/shred/internal/units"
+
+// Plate is a plate weight and how many of them there are.
+type Plate struct {
+	Weight float64 `json:"weight"`
+	Count  int     `json:"count"`
+}
+
+/*
+ * PlateRequest asks how to load a barbell to Target. Loads are in Unit, kg
+ * by default; without Bar or Plates the standard bar and plates of the unit
+ * are used. Plates counts every plate, so a pair loads both sides.
+ */
+type PlateRequest struct {
+	Target float64          `json:"target"`
+	Bar    *float64         `json:"bar,omitempty"`
+	Unit   units.WeightUnit `json:"unit"`
+	Plates []Plate          `json:"plates,omitempty"`
+}
+
+/*
+ * PlateLoading is how to load a barbell: PerSide lists the plates on each
+ * side, heaviest first. Total is the load reached, which is below the
+ * target when the plates cannot make it exactly.
+ */
+type PlateLoading struct {
+	Target  float64          `json:"target"`
+	Bar     float64          `json:"bar"`
+	Total   float64          `json:"total"`
+	Exact   bool             `json:"exact"`
+	Unit    units.WeightUnit `json:"unit"`
+	PerSide []Plate          `json:"perSide"`
+}
+
+// WarmupRequest asks for the warm-up sets before a working set of Reps at
+// WorkingLoad, with the bar and plates as in PlateRequest.
+type WarmupRequest struct {
+	WorkingLoad float64          `json:"workingLoad"`
+	Reps        int              `json:"reps"`
+	Bar         *float64         `json:"bar,omitempty"`
+	Unit        units.WeightUnit `json:"unit"`
+	Plates      []Plate          `json:"plates,omitempty"`
+}
+
+// WarmupSet is one set of a warm-up ramp; Percent is its load as a
+// percentage of the working load.
+type WarmupSet struct {
+	Load    float64 `json:"load"`
+	Reps    int     `json:"reps"`
+	Percent int     `json:"percent"`
+	PerSide []Plate `json:"perSide"`
+}
+
+type Warmup struct {
+	WorkingLoad float64          `json:"workingLoad"`
+	Reps        int              `json:"reps"`
+	Unit        units.WeightUnit `json:"unit"`
+	Sets        []WarmupSet      `json:"sets"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MuscleLoad is the number of sets of one session that worked a muscle in
+// one role.
+type MuscleLoad struct {
+	StartedAt  time.Time  `json:"startedAt" db:"started_at"`
+	MuscleCode string     `json:"muscleCode" db:"muscle_code"`
+	MuscleRole MuscleRole `json:"muscleRole" db:"muscle_role"`
+	Sets       int        `json:"sets" db:"sets"`
+}
+
+type RecoveryStatus string
+
+const (
+	RecoveryFresh      RecoveryStatus = "fresh"
+	RecoveryRecovering RecoveryStatus = "recovering"
+	RecoveryFatigued   RecoveryStatus = "fatigued"
+)
+
+/*
+ * MuscleRecovery is how recovered a muscle is. Fatigue is in role-weighted
+ * sets still to recover from; freshness goes from 0 for exhausted to 1 for
+ * fully recovered.
+ */
+type MuscleRecovery struct {
+	MuscleCode    string         `json:"muscleCode"`
+	MuscleName    string         `json:"muscleName"`
+	MuscleLevel   MuscleLevel    `json:"muscleLevel"`
+	ParentCode    *string        `json:"parentCode,omitempty"`
+	Fatigue       float64        `json:"fatigue"`
+	Freshness     float64        `json:"freshness"`
+	Status        RecoveryStatus `json:"status"`
+	LastTrainedAt *time.Time     `json:"lastTrainedAt,omitempty"`
+}
+
+type RecoveryReport struct {
+	UserUuid   uuid.UUID        `json:"userUuid"`
+	ComputedAt time.Time        `json:"computedAt"`
+	Muscles    []MuscleRecovery `json:"muscles"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * RelationType is the type of an edge between two exercises and mirrors the
+ * exercise_relation enum in the database. An edge reads from the exercise to
+ * the related exercise: "knee push-up regression_of push-up".
+ */
+type RelationType string
+
+const (
+	RelationVariationOf   RelationType = "variation_of"
+	RelationProgressionOf RelationType = "progression_of"
+	RelationRegressionOf  RelationType = "regression_of"
+	RelationAlternativeTo RelationType = "alternative_to"
+)
+
+// Valid reports whether the relation is one of the known relation types.
+func (r RelationType) Valid() bool {
+	switch r {
+	case RelationVariationOf, RelationProgressionOf, RelationRegressionOf, RelationAlternativeTo:
+		return true
+	}
+	return false
+}
+
+// Direction is the way the progression graph is walked.
+type Direction string
+
+const (
+	DirectionEasier Direction = "easier"
+	DirectionHarder Direction = "harder"
+)
+
+type ExerciseRelationRequest struct {
+	RelatedUuid uuid.UUID    `json:"relatedUuid" db:"related_uuid"`
+	Relation    RelationType `json:"relation" db:"relation"`
+	CreatedBy   uuid.UUID    `json:"createdBy" db:"created_by"`
+}
+
+type ExerciseRelation struct {
+	ExerciseUuid uuid.UUID    `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string       `json:"exerciseName" db:"exercise_name"`
+	Relation     RelationType `json:"relation" db:"relation"`
+	RelatedUuid  uuid.UUID    `json:"relatedUuid" db:"related_uuid"`
+	RelatedName  string       `json:"relatedName" db:"related_name"`
+	CreatedBy    uuid.UUID    `json:"createdBy" db:"created_by"`
+	CreatedAt    time.Time    `json:"createdAt" db:"created_at"`
+}
+
+// RelatedExercise is an exercise reached by walking the progression graph,
+// Steps edges away from where the walk started.
+type RelatedExercise struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string    `json:"exerciseName" db:"exercise_name"`
+	CategoryCode string    `json:"category" db:"category_code"`
+	Steps        int       `json:"steps" db:"steps"`
+}
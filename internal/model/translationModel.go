@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * ExerciseTranslation holds the texts of an exercise in one language. Empty
+ * fields fall back to the default language.
+ */
+type ExerciseTranslation struct {
+	Locale       string `json:"locale" db:"locale"`
+	ExerciseName string `json:"exerciseName" db:"exercise_name"`
+	Description  string `json:"description" db:"exercise_description"`
+	Instructions string `json:"instructions" db:"instructions"`
+	Cues         string `json:"cues" db:"cues"`
+}
+
+// Apply overlays the translated texts that are set onto fields.
+func (t ExerciseTranslation) Apply(fields *ExerciseFields) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&fields.ExerciseName, t.ExerciseName},
+		{&fields.Description, t.Description},
+		{&fields.Instructions, t.Instructions},
+		{&fields.Cues, t.Cues},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+}
+
+type ExerciseAlias struct {
+	Alias  string `json:"alias" db:"alias"`
+	Locale string `json:"locale,omitempty" db:"locale"`
+}
+
+// ReferenceKind names the reference tables whose names can be translated.
+type ReferenceKind string
+
+const (
+	ReferenceMuscle    ReferenceKind = "muscle"
+	ReferenceCategory  ReferenceKind = "category"
+	ReferenceApparatus ReferenceKind = "apparatus"
+)
+
+// ParseReferenceKind accepts the singular or the plural used in routes.
+func ParseReferenceKind(name string) (ReferenceKind, error) {
+	switch name {
+	case "muscle", "muscles":
+		return ReferenceMuscle, nil
+	case "category", "categories":
+		return ReferenceCategory, nil
+	case "apparatus":
+		return ReferenceApparatus, nil
+	}
+	return "", fmt.Errorf("unknown reference %q, expected muscles, categories or apparatus", name)
+}
+
+type ReferenceTranslation struct {
+	Kind        ReferenceKind `json:"kind" db:"reference_kind"`
+	Code        string        `json:"code" db:"reference_code"`
+	Locale      string        `json:"locale" db:"locale"`
+	Name        string        `json:"name" db:"reference_name"`
+	Description string        `json:"description,omitempty" db:"reference_description"`
+}
+
+// MatchedBy tells which text of an exercise matched a search.
+type MatchedBy string
+
+const (
+	MatchedByName        MatchedBy = "name"
+	MatchedByAlias       MatchedBy = "alias"
+	MatchedByTranslation MatchedBy = "translation"
+)
+
+// ExerciseSearchResult is an exercise found by name, alias or translated
+// name. ExerciseName is in the requested language when translated.
+type ExerciseSearchResult struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string    `json:"exerciseName" db:"exercise_name"`
+	CategoryCode string    `json:"category" db:"category_code"`
+	MatchedBy    MatchedBy `json:"matchedBy" db:"matched_by"`
+	Match        string    `json:"match" db:"match"`
+}
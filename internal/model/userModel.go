@@ -0,0 +1,24 @@
+package model
+
+import "github.com/pwydra/shred/internal/units"
+
+/*
+ * UserPreferences are the settings responses are shaped by. Loads,
+ * distances and lengths are returned in the unit system of the user, with
+ * converted loads rounded to LoadIncrement.
+ */
+type UserPreferences struct {
+	UnitSystem units.System `json:"unitSystem" db:"unit_system"`
+	// LoadIncrement is the smallest step between loads in the unit system,
+	// by default that of the system.
+	LoadIncrement *float64 `json:"loadIncrement,omitempty" db:"load_increment"`
+}
+
+// Converter converts stored values into the units of the preferences.
+func (p *UserPreferences) Converter() units.Converter {
+	var increment float64
+	if p.LoadIncrement != nil {
+		increment = *p.LoadIncrement
+	}
+	return units.NewConverter(p.UnitSystem, increment)
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// SourceShred marks sessions logged directly in shred rather than imported.
+const SourceShred = "shred"
+
+/*
+ * WorkoutSet is a single set within a session. Loads and distances are
+ * stored in kilograms and metres; fields that do not apply to the exercise
+ * are left nil.
+ */
+type WorkoutSet struct {
+	SetNumber       int       `json:"setNumber" db:"set_number"`
+	ExerciseUuid    uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	Reps            *int      `json:"reps,omitempty" db:"reps"`
+	WeightKg        *float64  `json:"weightKg,omitempty" db:"weight_kg"`
+	DistanceM       *float64  `json:"distanceM,omitempty" db:"distance_m"`
+	DurationSeconds *int      `json:"durationSeconds,omitempty" db:"duration_seconds"`
+	Rpe             *float64  `json:"rpe,omitempty" db:"rpe"`
+	Notes           string    `json:"notes,omitempty" db:"notes"`
+	// Weight and Distance are the load and distance in WeightUnit and
+	// DistanceUnit. Requests may give them instead of WeightKg and
+	// DistanceM; responses give them in the units the user prefers.
+	Weight       *float64           `json:"weight,omitempty" db:"-"`
+	WeightUnit   units.WeightUnit   `json:"weightUnit,omitempty" db:"-"`
+	Distance     *float64           `json:"distance,omitempty" db:"-"`
+	DistanceUnit units.DistanceUnit `json:"distanceUnit,omitempty" db:"-"`
+	// EnteredWeightUnit and EnteredDistanceUnit are the units the set was
+	// logged in.
+	EnteredWeightUnit   units.WeightUnit   `json:"enteredWeightUnit,omitempty" db:"entered_weight_unit"`
+	EnteredDistanceUnit units.DistanceUnit `json:"enteredDistanceUnit,omitempty" db:"entered_distance_unit"`
+}
+
+/*
+ * Normalize converts a Weight or Distance given in another unit into
+ * WeightKg and DistanceM and records the unit it was entered in.
+ */
+func (set *WorkoutSet) Normalize() error {
+	if set.Weight != nil {
+		if set.WeightKg != nil {
+			return errors.New("give weight or weightKg, not both")
+		}
+		unit := units.Kg
+		if set.WeightUnit != "" {
+			var err error
+			if unit, err = units.ParseWeightUnit(string(set.WeightUnit)); err != nil {
+				return err
+			}
+		}
+		kg := units.Round(units.ToKg(*set.Weight, unit))
+		set.WeightKg, set.EnteredWeightUnit = &kg, unit
+	} else if set.WeightKg != nil {
+		set.EnteredWeightUnit = units.Kg
+	}
+	if set.Distance != nil {
+		if set.DistanceM != nil {
+			return errors.New("give distance or distanceM, not both")
+		}
+		unit := units.Km
+		if set.DistanceUnit != "" {
+			var err error
+			if unit, err = units.ParseDistanceUnit(string(set.DistanceUnit)); err != nil {
+				return err
+			}
+		}
+		m := units.Round(units.ToMetres(*set.Distance, unit))
+		set.DistanceM, set.EnteredDistanceUnit = &m, unit
+	} else if set.DistanceM != nil {
+		set.EnteredDistanceUnit = units.Km
+	}
+	set.Weight, set.WeightUnit, set.Distance, set.DistanceUnit = nil, "", nil, ""
+	return nil
+}
+
+// Convert fills in Weight and Distance in the units of c.
+func (set *WorkoutSet) Convert(c units.Converter) {
+	if set.WeightKg != nil {
+		weight := c.Load(*set.WeightKg, set.EnteredWeightUnit)
+		set.Weight, set.WeightUnit = &weight, c.System().WeightUnit()
+	}
+	if set.DistanceM != nil {
+		distance := c.Distance(*set.DistanceM)
+		set.Distance, set.DistanceUnit = &distance, c.System().DistanceUnit()
+	}
+}
+
+type WorkoutSessionFields struct {
+	SessionName     string    `json:"sessionName" db:"session_name"`
+	StartedAt       time.Time `json:"startedAt" db:"started_at"`
+	DurationSeconds *int      `json:"durationSeconds,omitempty" db:"duration_seconds"`
+	Notes           string    `json:"notes,omitempty" db:"notes"`
+	Source          string    `json:"source" db:"source"`
+}
+
+type WorkoutSessionRequest struct {
+	WorkoutSessionFields
+	Sets []WorkoutSet `json:"sets"`
+}
+
+type WorkoutSession struct {
+	SessionUuid uuid.UUID `json:"sessionUuid" db:"session_uuid"`
+	UserUuid    uuid.UUID `json:"userUuid" db:"user_uuid"`
+	WorkoutSessionFields
+	Sets      []WorkoutSet `json:"sets" db:"-"`
+	CreatedAt time.Time    `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time    `json:"updatedAt" db:"updated_at"`
+}
+
+func (s *WorkoutSession) Convert(c units.Converter) {
+	for i := range s.Sets {
+		s.Sets[i].Convert(c)
+	}
+}
+
+// ExerciseName identifies an exercise by name, used to resolve names from
+// other applications.
+type ExerciseName struct {
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	ExerciseName string    `json:"exerciseName" db:"exercise_name"`
+}
+
+/*
+ * ExerciseMapping manually ties an exercise name used by another tracking
+ * application to an exercise in the catalog.
+ */
+type ExerciseMapping struct {
+	Source       string    `json:"source" db:"source"`
+	ExternalName string    `json:"externalName" db:"external_name"`
+	ExerciseUuid uuid.UUID `json:"exerciseUuid" db:"exercise_uuid"`
+	CreatedBy    uuid.UUID `json:"createdBy" db:"created_by"`
+}
@@ -0,0 +1,257 @@
+// Package openapi describes the HTTP API of the service as an OpenAPI 3
+// document. The routes are listed by hand; the schemas of their bodies are
+// derived from the json tags of the model types, so they cannot drift from
+// what the handlers send.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const version = "3.0.3"
+
+// Document is an OpenAPI 3 document, trimmed to the parts the service uses.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of a path by lower case HTTP method.
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema as OpenAPI 3.0 understands it.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	spec     *Document
+	specOnce sync.Once
+)
+
+/*
+ * Version is a version of the API, served under Prefix. Changed lists the
+ * operations that differ from v1; a deprecated version is flagged as such
+ * in the document.
+ */
+type Version struct {
+	Prefix     string
+	Changed    []Operation
+	Deprecated bool
+}
+
+// Versions are the API versions the service serves, oldest first.
+var Versions = []Version{
+	{Prefix: "/v1", Deprecated: true},
+	{Prefix: "/v2", Changed: v2Operations},
+}
+
+// Spec returns the document of the API, built on first use.
+func Spec() *Document {
+	specOnce.Do(func() {
+		spec = build(rootOperations, operations, Versions)
+	})
+	return spec
+}
+
+// Operations returns the operations of the version, with the changed ones
+// in place of those of v1.
+func (v Version) Operations(base []Operation) []Operation {
+	ops := make([]Operation, len(base))
+	for i, op := range base {
+		ops[i] = op
+		for _, changed := range v.Changed {
+			if changed.Method == op.Method && changed.Path == op.Path {
+				ops[i] = changed
+			}
+		}
+		ops[i].Path = v.Prefix + ops[i].Path
+		ops[i].Deprecated = v.Deprecated
+	}
+	return ops
+}
+
+/*
+ * Path turns a gin route path into an OpenAPI path, so /exercises/:uuid
+ * becomes /exercises/{uuid}.
+ */
+func Path(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func build(root, base []Operation, versions []Version) *Document {
+	doc := &Document{
+		OpenAPI: version,
+		Info: Info{
+			Title:       "Shred",
+			Description: "Exercise catalog, workout logging and training analytics.",
+			Version:     "2.0.0",
+		},
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+	schemas := newSchemaSet(doc.Components.Schemas)
+	doc.Components.Schemas["Error"] = &Schema{Type: "object", Required: []string{"error"},
+		Properties: map[string]*Schema{"error": {Type: "string"}}}
+
+	ops := root
+	for _, v := range versions {
+		ops = append(ops[:len(ops):len(ops)], v.Operations(base)...)
+	}
+	tags := map[string]bool{}
+	for _, op := range ops {
+		path := Path(op.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(op.Method)] = op.object(schemas)
+		if !tags[op.Tag] {
+			tags[op.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: op.Tag})
+		}
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+func (op Operation) object(schemas *schemaSet) *OperationObject {
+	obj := &OperationObject{
+		OperationID: op.operationID(),
+		Summary:     op.Summary,
+		Tags:        []string{op.Tag},
+		Deprecated:  op.Deprecated,
+		Responses:   map[string]Response{"default": errorResponse},
+	}
+	for _, segment := range strings.Split(op.Path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			obj.Parameters = append(obj.Parameters, pathParameter(segment[1:]))
+		}
+	}
+	for _, param := range op.Query {
+		obj.Parameters = append(obj.Parameters, param.parameter("query"))
+	}
+	for _, header := range op.Headers {
+		obj.Parameters = append(obj.Parameters, header.parameter("header"))
+	}
+
+	switch {
+	case op.Request != nil:
+		obj.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: schemas.of(reflect.TypeOf(op.Request))}}}
+	case len(op.RequestMedia) > 0:
+		obj.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{}}
+		for _, media := range op.RequestMedia {
+			obj.RequestBody.Content[media] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := Response{Description: http.StatusText(status)}
+	switch {
+	case op.Response != nil:
+		response.Content = map[string]MediaType{"application/json": {Schema: schemas.of(reflect.TypeOf(op.Response))}}
+	case len(op.ResponseMedia) > 0:
+		response.Content = map[string]MediaType{}
+		for _, media := range op.ResponseMedia {
+			response.Content[media] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+	obj.Responses[strconv.Itoa(status)] = response
+	return obj
+}
+
+var errorResponse = Response{Description: "The request failed", Content: map[string]MediaType{
+	"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}}}
+
+// operationID names an operation after its method and path, so
+// GET /exercises/:uuid/relations is getExercisesUuidRelations.
+func (op Operation) operationID() string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(op.Method))
+	for _, segment := range strings.FieldsFunc(op.Path, func(r rune) bool { return r == '/' || r == '-' || r == '.' }) {
+		segment = strings.TrimLeft(segment, ":*")
+		id.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return id.String()
+}
+
+// pathParameter describes a path parameter, typed as a UUID when its name
+// says it is one.
+func pathParameter(name string) Parameter {
+	schema := &Schema{Type: "string"}
+	if strings.HasSuffix(strings.ToLower(name), "uuid") {
+		schema.Format = "uuid"
+	}
+	return Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUI is the version of Swagger UI the docs page loads.
+const swaggerUI = "5.17.14"
+
+// Handler serves the document as JSON.
+func Handler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, Spec())
+}
+
+// DocsHandler serves a Swagger UI page browsing the document at specPath.
+func DocsHandler(specPath string) gin.HandlerFunc {
+	page := fmt.Sprintf(docsPage, swaggerUI, swaggerUI, specPath)
+	return func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Shred API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%[1]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@%[2]s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: %[3]q, dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
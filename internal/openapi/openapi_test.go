@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/exercises", Path("/exercises"))
+	assert.Equal(t, "/exercises/{uuid}/relations/{relatedUuid}", Path("/exercises/:uuid/relations/:relatedUuid"))
+	assert.Equal(t, "/files/{path}", Path("/files/*path"))
+}
+
+type schemaInner struct {
+	Name string `json:"name"`
+}
+
+type schemaSample struct {
+	schemaInner
+	Uuid     uuid.UUID        `json:"uuid"`
+	At       *time.Time       `json:"at,omitempty"`
+	Role     model.MuscleRole `json:"role"`
+	Children []*schemaSample  `json:"children"`
+	Counts   map[string]int   `json:"counts,omitempty"`
+	Hidden   string           `json:"-"`
+	Plain    float64
+	private  bool
+}
+
+func TestSchemaSet(t *testing.T) {
+	components := map[string]*Schema{}
+	ref := newSchemaSet(components).of(reflect.TypeOf([]schemaSample{}))
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/schemaSample"}}, ref)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name":     {Type: "string"},
+			"uuid":     {Type: "string", Format: "uuid"},
+			"at":       {Type: "string", Format: "date-time", Nullable: true},
+			"role":     {Type: "string", Enum: []string{"primary", "secondary", "stabilizer"}},
+			"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/schemaSample"}},
+			"counts":   {Type: "object", AdditionalProperties: &Schema{Type: "integer", Format: "int32"}},
+			"Plain":    {Type: "number", Format: "double"},
+		},
+		Required: []string{"name", "uuid", "role", "children", "Plain"},
+	}, components["schemaSample"])
+}
+
+func TestSpec(t *testing.T) {
+	doc := Spec()
+
+	op := doc.Paths["/v1/exercises/{uuid}/relations/{relatedUuid}"]["delete"]
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "deleteV1ExercisesUuidRelationsRelatedUuid", op.OperationID)
+		assert.True(t, op.Deprecated)
+		assert.Equal(t, []string{"relations"}, op.Tags)
+		assert.Equal(t, []Parameter{
+			{Name: "uuid", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+			{Name: "relatedUuid", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+			{Name: "relation", In: "query", Required: true, Schema: &Schema{Type: "string",
+				Enum: []string{"variation_of", "progression_of", "regression_of", "alternative_to"}}},
+		}, op.Parameters)
+		assert.Contains(t, op.Responses, "204")
+		assert.Contains(t, op.Responses, "default")
+	}
+
+	op = doc.Paths["/v1/exercises"]["post"]
+	if assert.NotNil(t, op) {
+		assert.Equal(t, "#/components/schemas/ExerciseRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/Exercise", op.Responses["201"].Content["application/json"].Schema.Ref)
+	}
+	op = doc.Paths["/v2/exercises"]["post"]
+	if assert.NotNil(t, op) {
+		assert.False(t, op.Deprecated)
+		assert.Equal(t, "#/components/schemas/ExerciseRequestV2", op.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/ExerciseV2", op.Responses["201"].Content["application/json"].Schema.Ref)
+	}
+	assert.Contains(t, doc.Components.Schemas["ExerciseRequest"].Properties, "licenceShortName")
+	assert.Contains(t, doc.Components.Schemas["ExerciseRequestV2"].Properties, "licenseShortName")
+	assert.NotNil(t, doc.Paths["/v2/exercises/{uuid}/relations"]["get"], "unchanged operations are in every version")
+	assert.NotNil(t, doc.Paths["/calendar/{feed}"]["get"], "feeds are not versioned")
+	assert.Nil(t, doc.Paths["/exercises"])
+
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			assert.NotEmpty(t, op.Summary, "%s %s has no summary", method, path)
+		}
+	}
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+}
+
+func TestHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/openapi.json", Handler)
+	engine.GET("/docs", DocsHandler("/openapi.json"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc Document
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, len(Spec().Paths), len(doc.Paths))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/docs", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `SwaggerUIBundle({ url: "/openapi.json"`)
+}
@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+/*
+ * Operation is a route of the API. Request and Response are values of the
+ * JSON bodies, used only for their types; RequestMedia and ResponseMedia
+ * list the media types of bodies that are not JSON. Status is the status
+ * of a successful response, 200 when zero. Deprecated is set on the
+ * operations of deprecated versions.
+ */
+type Operation struct {
+	Method        string
+	Path          string
+	Tag           string
+	Summary       string
+	Query         []Param
+	Headers       []Param
+	Request       any
+	RequestMedia  []string
+	Status        int
+	Response      any
+	ResponseMedia []string
+	Deprecated    bool
+}
+
+// Param is a query parameter or request header of an operation.
+type Param struct {
+	Name        string
+	Type        string
+	Format      string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+func (p Param) parameter(in string) Parameter {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	return Parameter{Name: p.Name, In: in, Description: p.Description, Required: p.Required,
+		Schema: &Schema{Type: typ, Format: p.Format, Enum: p.Enum}}
+}
+
+var (
+	acceptLanguage = Param{Name: "Accept-Language", Description: "Locales to translate names into, by preference."}
+	from           = Param{Name: "from", Format: "date-time", Description: "Start of the range, as a date or RFC 3339 time."}
+	to             = Param{Name: "to", Format: "date-time", Description: "End of the range, as a date or RFC 3339 time."}
+	tz             = Param{Name: "tz", Description: "IANA time zone, UTC by default."}
+	profileUuid    = Param{Name: "profile", Format: "uuid", Description: "Equipment profile, the user's default when omitted."}
+	category       = Param{Name: "category", Description: "Only exercises of this category."}
+	catalogFormat  = Param{Name: "format", Enum: []string{"csv", "ndjson", "bundle"}}
+	catalogMedia   = []string{"text/csv", "application/x-ndjson", "application/zip"}
+	progressions   = []Param{
+		{Name: "maxSteps", Type: "integer", Description: "How many relations to follow."},
+		{Name: "sameMuscles", Type: "boolean", Description: "Only exercises with the same primary muscles, including the progressions of alternatives."},
+	}
+)
+
+// rootOperations lists the routes outside the API versions.
+var rootOperations = []Operation{
+	{Method: "GET", Path: "/metrics", Tag: "operations", Summary: "Prometheus metrics",
+		ResponseMedia: []string{"text/plain"}},
+	{Method: "GET", Path: "/openapi.json", Tag: "operations", Summary: "This OpenAPI document",
+		ResponseMedia: []string{"application/json"}},
+	{Method: "GET", Path: "/docs", Tag: "operations", Summary: "API documentation viewer",
+		ResponseMedia: []string{"text/html"}},
+	{Method: "GET", Path: "/calendar/:feed", Tag: "calendar", Summary: "Serve an iCalendar feed at <token>.ics",
+		ResponseMedia: []string{"text/calendar"}},
+}
+
+// operations lists the routes of an API version, as v1 serves them.
+var operations = []Operation{
+	{Method: "GET", Path: "/exercises/export", Tag: "catalog", Summary: "Export the exercise catalog",
+		Query: []Param{catalogFormat}, ResponseMedia: catalogMedia},
+	{Method: "POST", Path: "/exercises/import", Tag: "catalog", Summary: "Import exercises into the catalog",
+		Query: []Param{catalogFormat,
+			{Name: "mode", Enum: values(model.ImportModeInsert, model.ImportModeUpsert)},
+			{Name: "dryRun", Type: "boolean", Description: "Validate without storing anything."},
+			{Name: "createdBy", Format: "uuid", Description: "User recorded as creator, required unless dryRun."}},
+		RequestMedia: catalogMedia, Status: http.StatusCreated, Response: model.ImportReport{}},
+	{Method: "GET", Path: "/exercises/search", Tag: "exercises", Summary: "Search exercises by name, alias or translation",
+		Query: []Param{{Name: "q", Required: true}, {Name: "limit", Type: "integer"}}, Headers: []Param{acceptLanguage},
+		Response: []model.ExerciseSearchResult{}},
+	{Method: "POST", Path: "/exercises", Tag: "exercises", Summary: "Create an exercise",
+		Request: model.ExerciseRequest{}, Status: http.StatusCreated, Response: model.Exercise{}},
+	{Method: "GET", Path: "/exercises/:uuid", Tag: "exercises", Summary: "Get an exercise",
+		Headers: []Param{acceptLanguage}, Response: model.Exercise{}},
+	{Method: "PUT", Path: "/exercises/:uuid", Tag: "exercises", Summary: "Update an exercise",
+		Request: model.Exercise{}, Response: model.Exercise{}},
+	{Method: "DELETE", Path: "/exercises/:uuid", Tag: "exercises", Summary: "Delete an exercise",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/exercises/:uuid/relations", Tag: "relations", Summary: "List the relations of an exercise",
+		Response: []model.ExerciseRelation{}},
+	{Method: "POST", Path: "/exercises/:uuid/relations", Tag: "relations", Summary: "Relate an exercise to another",
+		Request: model.ExerciseRelationRequest{}, Status: http.StatusCreated, Response: model.ExerciseRelation{}},
+	{Method: "DELETE", Path: "/exercises/:uuid/relations/:relatedUuid", Tag: "relations", Summary: "Remove a relation",
+		Query:  []Param{{Name: "relation", Required: true, Enum: enums[typeOf[model.RelationType]()]}},
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/exercises/:uuid/easier", Tag: "relations", Summary: "Find easier progressions",
+		Query: progressions, Response: []model.RelatedExercise{}},
+	{Method: "GET", Path: "/exercises/:uuid/harder", Tag: "relations", Summary: "Find harder progressions",
+		Query: progressions, Response: []model.RelatedExercise{}},
+	{Method: "GET", Path: "/exercises/:uuid/translations", Tag: "translations", Summary: "List the translations of an exercise",
+		Response: []model.ExerciseTranslation{}},
+	{Method: "PUT", Path: "/exercises/:uuid/translations/:locale", Tag: "translations", Summary: "Translate an exercise",
+		Request: model.ExerciseTranslation{}, Response: model.ExerciseTranslation{}},
+	{Method: "DELETE", Path: "/exercises/:uuid/translations/:locale", Tag: "translations",
+		Summary: "Delete the translation of an exercise", Status: http.StatusNoContent},
+	{Method: "GET", Path: "/exercises/:uuid/aliases", Tag: "translations", Summary: "List the aliases of an exercise",
+		Response: []model.ExerciseAlias{}},
+	{Method: "POST", Path: "/exercises/:uuid/aliases", Tag: "translations", Summary: "Add an alias to an exercise",
+		Request: model.ExerciseAlias{}, Status: http.StatusCreated, Response: model.ExerciseAlias{}},
+	{Method: "DELETE", Path: "/exercises/:uuid/aliases", Tag: "translations", Summary: "Remove an alias of an exercise",
+		Query: []Param{{Name: "alias", Required: true}}, Status: http.StatusNoContent},
+
+	{Method: "GET", Path: "/muscles", Tag: "references", Summary: "List muscles",
+		Headers: []Param{acceptLanguage}, Response: []model.Muscle{}},
+	{Method: "GET", Path: "/muscles/tree", Tag: "references", Summary: "Get the muscle hierarchy",
+		Headers: []Param{acceptLanguage}, Response: []model.MuscleNode{}},
+	{Method: "GET", Path: "/muscles/:code/exercises", Tag: "references", Summary: "List the exercises working a muscle",
+		Query: []Param{{Name: "role", Enum: enums[typeOf[model.MuscleRole]()]}}, Response: []model.MuscleExercise{}},
+	{Method: "GET", Path: "/categories", Tag: "references", Summary: "List exercise categories",
+		Headers: []Param{acceptLanguage}, Response: []model.Category{}},
+	{Method: "GET", Path: "/apparatus", Tag: "references", Summary: "List apparatus",
+		Headers: []Param{acceptLanguage}, Response: []model.Apparatus{}},
+	{Method: "GET", Path: "/translations/locales", Tag: "translations", Summary: "List the locales with translations",
+		Response: []string{}},
+	{Method: "PUT", Path: "/translations/:kind/:code/:locale", Tag: "translations",
+		Summary: "Translate a muscle, category or apparatus", Request: model.ReferenceTranslation{},
+		Response: model.ReferenceTranslation{}},
+
+	{Method: "GET", Path: "/users/:uuid/preferences", Tag: "users", Summary: "Get a user's preferences",
+		Response: model.UserPreferences{}},
+	{Method: "PUT", Path: "/users/:uuid/preferences", Tag: "users", Summary: "Update a user's preferences",
+		Request: model.UserPreferences{}, Response: model.UserPreferences{}},
+	{Method: "GET", Path: "/users/:uuid/workouts", Tag: "workouts", Summary: "List a user's workout sessions",
+		Query: []Param{from, to}, Response: []model.WorkoutSession{}},
+	{Method: "POST", Path: "/users/:uuid/workouts", Tag: "workouts", Summary: "Log a workout session",
+		Request: model.WorkoutSessionRequest{}, Status: http.StatusCreated, Response: model.WorkoutSession{}},
+	{Method: "POST", Path: "/users/:uuid/history/import", Tag: "history", Summary: "Import workouts from another app",
+		Query: []Param{
+			{Name: "source", Enum: []string{"strong", "hevy", "fitnotes"}, Description: "Detected from the header when omitted."},
+			{Name: "weightUnit", Enum: []string{"kg", "lb"}},
+			{Name: "distanceUnit", Enum: []string{"km", "mi"}},
+			{Name: "timezone", Description: "IANA time zone of the export, UTC by default."},
+			{Name: "dryRun", Type: "boolean"},
+			{Name: "skipUnresolved", Type: "boolean", Description: "Skip sets of exercises that cannot be matched."}},
+		RequestMedia: []string{"text/csv"}, Status: http.StatusCreated, Response: model.HistoryImportReport{}},
+	{Method: "GET", Path: "/history/mappings/:source", Tag: "history", Summary: "List exercise name mappings",
+		Response: []model.ExerciseMapping{}},
+	{Method: "PUT", Path: "/history/mappings/:source", Tag: "history", Summary: "Map an exercise name to an exercise",
+		Request: model.ExerciseMapping{}, Response: model.ExerciseMapping{}},
+	{Method: "DELETE", Path: "/history/mappings/:source", Tag: "history", Summary: "Delete an exercise name mapping",
+		Query: []Param{{Name: "externalName", Required: true}}, Status: http.StatusNoContent},
+
+	{Method: "GET", Path: "/users/:uuid/cardio", Tag: "cardio", Summary: "List a user's cardio activities",
+		Query: []Param{from, to}, Response: []model.CardioActivity{}},
+	{Method: "POST", Path: "/users/:uuid/cardio/:format", Tag: "cardio", Summary: "Import a GPX, TCX or FIT activity",
+		Query:        []Param{{Name: "exerciseUuid", Format: "uuid", Required: true}, {Name: "sessionName"}},
+		RequestMedia: []string{"application/octet-stream", "multipart/form-data"}, Status: http.StatusCreated,
+		Response: model.CardioActivity{}},
+	{Method: "GET", Path: "/cardio/:uuid", Tag: "cardio", Summary: "Get a cardio activity",
+		Response: model.CardioActivity{}},
+
+	{Method: "GET", Path: "/users/:uuid/equipment-profiles", Tag: "equipment", Summary: "List a user's equipment profiles",
+		Response: []model.EquipmentProfile{}},
+	{Method: "POST", Path: "/users/:uuid/equipment-profiles", Tag: "equipment", Summary: "Create a user's equipment profile",
+		Request: model.EquipmentProfileRequest{}, Status: http.StatusCreated, Response: model.EquipmentProfile{}},
+	{Method: "GET", Path: "/users/:uuid/available-exercises", Tag: "equipment",
+		Summary: "List the exercises a user's equipment allows", Query: []Param{profileUuid, category},
+		Response: []model.AvailableExercise{}},
+	{Method: "GET", Path: "/equipment-profiles", Tag: "equipment", Summary: "List gym equipment profiles",
+		Response: []model.EquipmentProfile{}},
+	{Method: "POST", Path: "/equipment-profiles", Tag: "equipment", Summary: "Create a gym equipment profile",
+		Request: model.EquipmentProfileRequest{}, Status: http.StatusCreated, Response: model.EquipmentProfile{}},
+	{Method: "GET", Path: "/equipment-profiles/:uuid", Tag: "equipment", Summary: "Get an equipment profile",
+		Response: model.EquipmentProfile{}},
+	{Method: "PUT", Path: "/equipment-profiles/:uuid", Tag: "equipment", Summary: "Update an equipment profile",
+		Request: model.EquipmentProfileRequest{}, Response: model.EquipmentProfile{}},
+	{Method: "DELETE", Path: "/equipment-profiles/:uuid", Tag: "equipment", Summary: "Delete an equipment profile",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/equipment-profiles/:uuid/exercises", Tag: "equipment",
+		Summary: "List the exercises a profile allows", Query: []Param{category}, Response: []model.AvailableExercise{}},
+
+	{Method: "POST", Path: "/users/:uuid/session-plans", Tag: "training", Summary: "Plan a training session",
+		Request: model.SessionPlanRequest{}, Response: model.SessionPlan{}},
+	{Method: "GET", Path: "/users/:uuid/recovery", Tag: "training", Summary: "Get a user's muscle recovery",
+		Response: model.RecoveryReport{}},
+	{Method: "GET", Path: "/users/:uuid/measurements", Tag: "measurements", Summary: "List a user's body measurements",
+		Query: []Param{{Name: "kind", Enum: enums[typeOf[model.MeasurementKind]()]}, {Name: "site", Enum: model.MeasurementSites},
+			from, to}, Response: []model.Measurement{}},
+	{Method: "POST", Path: "/users/:uuid/measurements", Tag: "measurements", Summary: "Record a body measurement",
+		Request: model.MeasurementRequest{}, Status: http.StatusCreated, Response: model.Measurement{}},
+	{Method: "GET", Path: "/measurements/:uuid", Tag: "measurements", Summary: "Get a body measurement",
+		Response: model.Measurement{}},
+	{Method: "PUT", Path: "/measurements/:uuid", Tag: "measurements", Summary: "Update a body measurement",
+		Request: model.MeasurementRequest{}, Response: model.Measurement{}},
+	{Method: "DELETE", Path: "/measurements/:uuid", Tag: "measurements", Summary: "Delete a body measurement",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/users/:uuid/analytics/timeseries", Tag: "analytics", Summary: "Get a user's training trends",
+		Query: []Param{from, to, {Name: "window", Type: "integer", Description: "Days of the rolling average."},
+			{Name: "metrics", Description: "Comma separated metrics, all when omitted."}},
+		Response: model.TimeSeries{}},
+	{Method: "GET", Path: "/users/:uuid/analytics/muscle-volume", Tag: "analytics", Summary: "Get a user's training volume per muscle",
+		Query: []Param{from, to, {Name: "level", Enum: enums[typeOf[model.MuscleLevel]()],
+			Description: "Level of the muscles to roll the volume up to, region when omitted."}},
+		Response: model.MuscleVolumes{}},
+	{Method: "POST", Path: "/calculators/plates", Tag: "calculators", Summary: "Work out the plates to load",
+		Request: model.PlateRequest{}, Response: model.PlateLoading{}},
+	{Method: "POST", Path: "/calculators/warmup", Tag: "calculators", Summary: "Work out warm-up sets",
+		Request: model.WarmupRequest{}, Response: model.Warmup{}},
+
+	{Method: "GET", Path: "/users/:uuid/scheduled-workouts", Tag: "calendar", Summary: "List a user's scheduled workouts",
+		Query: []Param{from, to}, Response: []model.ScheduledWorkout{}},
+	{Method: "POST", Path: "/users/:uuid/scheduled-workouts", Tag: "calendar", Summary: "Schedule a workout",
+		Request: model.ScheduledWorkoutFields{}, Status: http.StatusCreated, Response: model.ScheduledWorkout{}},
+	{Method: "GET", Path: "/scheduled-workouts/:uuid", Tag: "calendar", Summary: "Get a scheduled workout",
+		Response: model.ScheduledWorkout{}},
+	{Method: "PUT", Path: "/scheduled-workouts/:uuid", Tag: "calendar", Summary: "Update a scheduled workout",
+		Request: model.ScheduledWorkoutFields{}, Response: model.ScheduledWorkout{}},
+	{Method: "DELETE", Path: "/scheduled-workouts/:uuid", Tag: "calendar", Summary: "Delete a scheduled workout",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/users/:uuid/calendar", Tag: "calendar", Summary: "Get a user's training calendar",
+		Query: []Param{{Name: "view", Enum: enums[typeOf[model.CalendarView]()]},
+			{Name: "date", Format: "date", Description: "A day in the week or month, today by default."}, tz},
+		Response: model.Calendar{}},
+	{Method: "GET", Path: "/users/:uuid/calendar-feed", Tag: "calendar", Summary: "Get a user's calendar feed",
+		Response: model.CalendarFeed{}},
+	{Method: "POST", Path: "/users/:uuid/calendar-feed", Tag: "calendar", Summary: "Create a user's calendar feed",
+		Status: http.StatusCreated, Response: model.CalendarFeed{}},
+	{Method: "DELETE", Path: "/users/:uuid/calendar-feed", Tag: "calendar", Summary: "Delete a user's calendar feed",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/users/:uuid/adherence", Tag: "coaching", Summary: "Get a user's adherence to the schedule",
+		Query: []Param{{Name: "weeks", Type: "integer"}, tz}, Response: model.AdherenceReport{}},
+	{Method: "GET", Path: "/coaches/:uuid/clients", Tag: "coaching", Summary: "List a coach's clients",
+		Response: []model.RosterClient{}},
+	{Method: "PUT", Path: "/coaches/:uuid/clients/:clientUuid", Tag: "coaching", Summary: "Add a client to a coach",
+		Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/coaches/:uuid/clients/:clientUuid", Tag: "coaching", Summary: "Remove a client from a coach",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/coaches/:uuid/adherence", Tag: "coaching", Summary: "Get the adherence of a coach's clients",
+		Query: []Param{{Name: "weeks", Type: "integer"}, tz}, Response: model.RosterAdherence{}},
+}
+
+// v2Operations lists the operations v2 changed, replacing those of v1 with
+// the same method and path.
+var v2Operations = []Operation{
+	{Method: "POST", Path: "/exercises", Tag: "exercises", Summary: "Create an exercise",
+		Request: model.ExerciseRequestV2{}, Status: http.StatusCreated, Response: model.ExerciseV2{}},
+	{Method: "GET", Path: "/exercises/:uuid", Tag: "exercises", Summary: "Get an exercise",
+		Headers: []Param{acceptLanguage}, Response: model.ExerciseV2{}},
+	{Method: "PUT", Path: "/exercises/:uuid", Tag: "exercises", Summary: "Update an exercise",
+		Request: model.ExerciseV2{}, Response: model.ExerciseV2{}},
+}
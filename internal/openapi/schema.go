@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// enums lists the values of the string types the API accepts only a few
+// values of.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(model.MuscleLevel("")): values(model.MuscleLevelRegion, model.MuscleLevelGroup,
+		model.MuscleLevelMuscle, model.MuscleLevelHead),
+	reflect.TypeOf(model.MuscleRole("")): values(model.MuscleRolePrimary, model.MuscleRoleSecondary,
+		model.MuscleRoleStabilizer),
+	reflect.TypeOf(model.RelationType("")): values(model.RelationVariationOf, model.RelationProgressionOf,
+		model.RelationRegressionOf, model.RelationAlternativeTo),
+	reflect.TypeOf(model.Direction("")):     values(model.DirectionEasier, model.DirectionHarder),
+	reflect.TypeOf(model.ReferenceKind("")): values(model.ReferenceMuscle, model.ReferenceCategory, model.ReferenceApparatus),
+	reflect.TypeOf(model.MatchedBy("")):     values(model.MatchedByName, model.MatchedByAlias, model.MatchedByTranslation),
+	reflect.TypeOf(model.ImportMode("")):    values(model.ImportModeInsert, model.ImportModeUpsert),
+	reflect.TypeOf(model.ImportAction("")): values(model.ImportActionCreate, model.ImportActionUpdate,
+		model.ImportActionInvalid),
+	reflect.TypeOf(model.MatchMethod("")): values(model.MatchMethodExact, model.MatchMethodMapping, model.MatchMethodFuzzy,
+		model.MatchMethodUnresolved),
+	reflect.TypeOf(model.MeasurementKind("")): values(model.MeasurementBodyweight, model.MeasurementBodyFat,
+		model.MeasurementCircumference),
+	reflect.TypeOf(model.ExperienceLevel("")): values(model.ExperienceBeginner, model.ExperienceIntermediate,
+		model.ExperienceAdvanced),
+	reflect.TypeOf(model.MovementPattern("")): values(model.MovementPush, model.MovementPull, model.MovementOther),
+	reflect.TypeOf(model.RecoveryStatus("")): values(model.RecoveryFresh, model.RecoveryRecovering,
+		model.RecoveryFatigued),
+	reflect.TypeOf(model.ScheduleStatus("")): values(model.SchedulePlanned, model.ScheduleCompleted, model.ScheduleMissed),
+	reflect.TypeOf(model.CalendarView("")):   values(model.CalendarWeek, model.CalendarMonth),
+	reflect.TypeOf(units.System("")):         values(units.Metric, units.Imperial),
+	reflect.TypeOf(units.WeightUnit("")):     values(units.Kg, units.Lb),
+}
+
+func values[T ~string](all ...T) []string {
+	names := make([]string, len(all))
+	for i, v := range all {
+		names[i] = string(v)
+	}
+	return names
+}
+
+// schemaSet derives schemas from Go types, adding one component per named
+// struct so types used by several operations are described once.
+type schemaSet struct {
+	components map[string]*Schema
+}
+
+func newSchemaSet(components map[string]*Schema) *schemaSet {
+	return &schemaSet{components: components}
+}
+
+func (s *schemaSet) of(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		schema := s.of(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string", Enum: enums[t]}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		return s.component(t)
+	}
+	return &Schema{}
+}
+
+// component returns a reference to the component of a named struct,
+// describing the struct the first time it is seen.
+func (s *schemaSet) component(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return s.object(t)
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := s.components[t.Name()]; !ok {
+		// Claim the name before describing the fields, for types that
+		// refer to themselves.
+		s.components[t.Name()] = &Schema{}
+		*s.components[t.Name()] = *s.object(t)
+	}
+	return ref
+}
+
+/*
+ * object describes the fields of a struct as encoding/json writes them:
+ * untagged embedded structs are flattened, fields tagged "-" and
+ * unexported fields are left out and fields without omitempty are
+ * required.
+ */
+func (s *schemaSet) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := s.object(field.Type)
+			for key, prop := range embedded.Properties {
+				schema.Properties[key] = prop
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.of(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// typeOf returns the type T, for looking up the enums of a type by name.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,276 @@
+package planner
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ErrNoExercises is returned when no exercise satisfies the constraints.
+var ErrNoExercises = errors.New("no exercise matches the constraints")
+
+const (
+	DefaultTimeBudgetMinutes = 45
+	MinTimeBudgetMinutes     = 10
+	MaxTimeBudgetMinutes     = 180
+	// maxExercises caps a session however long the time budget is.
+	maxExercises = 10
+	// setSeconds is the time under load of one set and changeoverSeconds
+	// the time to set up the next exercise.
+	setSeconds        = 40
+	changeoverSeconds = 60
+)
+
+// volume is the prescription of every exercise at an experience level.
+type volume struct {
+	sets, reps, restSeconds int
+}
+
+var volumes = map[model.ExperienceLevel]volume{
+	model.ExperienceBeginner:     {sets: 3, reps: 10, restSeconds: 60},
+	model.ExperienceIntermediate: {sets: 3, reps: 8, restSeconds: 90},
+	model.ExperienceAdvanced:     {sets: 4, reps: 6, restSeconds: 120},
+}
+
+func (v volume) seconds() int {
+	return v.sets*(setSeconds+v.restSeconds) + changeoverSeconds
+}
+
+// Score weights of the greedy selection.
+const (
+	newMuscleScore     = 4
+	repeatMuscleScore  = -3
+	primaryTargetScore = 6
+	otherTargetScore   = 2
+	balanceScore       = 3
+	unrecoveredScore   = -8
+)
+
+// Constraints are the resolved constraints of a request.
+type Constraints struct {
+	TargetMuscles []string
+	Category      string
+	// Apparatus is the equipment available, nil when it is not restricted.
+	Apparatus         []string
+	TimeBudgetMinutes int
+	Experience        model.ExperienceLevel
+	Seed              uint64
+	// Unrecovered holds the codes of the muscles that are not fresh yet.
+	Unrecovered map[string]bool
+	Now         time.Time
+}
+
+/*
+ * Build picks the exercises of a session from candidates. Candidates are
+ * filtered by category, equipment and target muscles, then picked greedily:
+ * each pick favours new primary muscles and the targets, evens out pushing
+ * and pulling and avoids muscles that have not recovered. Ties are broken by an
+ * order shuffled with the seed, so the same inputs and seed always give the
+ * same plan.
+ */
+func Build(candidates []model.CandidateExercise, c Constraints) (*model.SessionPlan, error) {
+	vol, ok := volumes[c.Experience]
+	if !ok {
+		vol = volumes[model.ExperienceBeginner]
+	}
+	budget := c.TimeBudgetMinutes
+	if budget == 0 {
+		budget = DefaultTimeBudgetMinutes
+	}
+	count := min(max(budget*60/vol.seconds(), 1), maxExercises)
+
+	pool := filter(candidates, c)
+	if len(pool) == 0 {
+		return nil, ErrNoExercises
+	}
+	slices.SortFunc(pool, func(a, b model.CandidateExercise) int {
+		return strings.Compare(a.ExerciseUuid.String(), b.ExerciseUuid.String())
+	})
+	rng := rand.New(rand.NewPCG(c.Seed, c.Seed))
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	targets := codeSet(c.TargetMuscles)
+	covered := map[string]bool{}
+	patterns := map[model.MovementPattern]int{}
+	plan := &model.SessionPlan{Seed: c.Seed, GeneratedAt: c.Now}
+	for len(plan.Exercises) < count && len(pool) > 0 {
+		best, bestScore := 0, 0
+		for i, candidate := range pool {
+			if score := scoreCandidate(candidate, targets, covered, patterns, c.Unrecovered); i == 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		picked := pool[best]
+		pool = slices.Delete(pool, best, best+1)
+
+		pattern := Pattern(picked)
+		patterns[pattern]++
+		primaries := primaryMuscles(picked)
+		for _, code := range primaries {
+			covered[code] = true
+		}
+		plan.Exercises = append(plan.Exercises, model.PlannedExercise{
+			ExerciseUuid:   picked.ExerciseUuid,
+			ExerciseName:   picked.ExerciseName,
+			Pattern:        pattern,
+			PrimaryMuscles: primaries,
+			Sets:           vol.sets,
+			Reps:           vol.reps,
+			RestSeconds:    vol.restSeconds,
+		})
+	}
+
+	plan.EstimatedSeconds = len(plan.Exercises) * vol.seconds()
+	plan.Session = session(plan, c)
+	return plan, nil
+}
+
+// filter keeps the candidates of the category that need no apparatus beyond
+// those available and work a target muscle.
+func filter(candidates []model.CandidateExercise, c Constraints) []model.CandidateExercise {
+	var available map[string]bool
+	if c.Apparatus != nil {
+		available = codeSet(c.Apparatus)
+	}
+	targets := codeSet(c.TargetMuscles)
+
+	var pool []model.CandidateExercise
+	for _, candidate := range candidates {
+		if c.Category != "" && !strings.EqualFold(candidate.CategoryCode, c.Category) {
+			continue
+		}
+		if available != nil && slices.ContainsFunc(candidate.Apparatus, func(code string) bool { return !available[code] }) {
+			continue
+		}
+		if len(targets) > 0 && !slices.ContainsFunc(candidate.Muscles, func(m model.CandidateMuscle) bool {
+			return hitsAny(m, targets)
+		}) {
+			continue
+		}
+		pool = append(pool, candidate)
+	}
+	return pool
+}
+
+func scoreCandidate(candidate model.CandidateExercise, targets, covered map[string]bool,
+	patterns map[model.MovementPattern]int, unrecovered map[string]bool) int {
+	score := 0
+	hitTarget := false
+	for _, m := range candidate.Muscles {
+		if m.MuscleRole != model.MuscleRolePrimary {
+			if hitsAny(m, targets) {
+				score += otherTargetScore
+			}
+			continue
+		}
+		if covered[m.MuscleCode] {
+			score += repeatMuscleScore
+		} else {
+			score += newMuscleScore
+		}
+		if hitsAny(m, targets) {
+			hitTarget = true
+		}
+		if unrecovered[m.MuscleCode] {
+			score += unrecoveredScore
+		}
+	}
+	if hitTarget {
+		score += primaryTargetScore
+	}
+
+	switch pattern, pushes, pulls := Pattern(candidate), patterns[model.MovementPush], patterns[model.MovementPull]; {
+	case pattern == model.MovementPush && pushes < pulls, pattern == model.MovementPull && pulls < pushes:
+		score += balanceScore
+	case pattern == model.MovementPush && pushes > pulls, pattern == model.MovementPull && pulls > pushes:
+		score -= balanceScore
+	}
+	return score
+}
+
+// Pattern classifies an exercise by the patterns of its primary muscles,
+// the majority deciding.
+func Pattern(candidate model.CandidateExercise) model.MovementPattern {
+	votes := 0
+	for _, m := range candidate.Muscles {
+		if m.MuscleRole != model.MuscleRolePrimary {
+			continue
+		}
+		switch m.Pattern {
+		case model.MovementPush:
+			votes++
+		case model.MovementPull:
+			votes--
+		}
+	}
+	switch {
+	case votes > 0:
+		return model.MovementPush
+	case votes < 0:
+		return model.MovementPull
+	}
+	return model.MovementOther
+}
+
+// session turns the plan into a session request with one set per planned
+// set, ready to be logged once weights are filled in.
+func session(plan *model.SessionPlan, c Constraints) model.WorkoutSessionRequest {
+	duration := plan.EstimatedSeconds
+	req := model.WorkoutSessionRequest{
+		WorkoutSessionFields: model.WorkoutSessionFields{
+			SessionName:     sessionName(c),
+			StartedAt:       c.Now,
+			DurationSeconds: &duration,
+			Source:          model.SourceShred,
+		},
+		Sets: []model.WorkoutSet{},
+	}
+	for _, ex := range plan.Exercises {
+		for range ex.Sets {
+			reps := ex.Reps
+			req.Sets = append(req.Sets, model.WorkoutSet{
+				SetNumber:    len(req.Sets) + 1,
+				ExerciseUuid: ex.ExerciseUuid,
+				Reps:         &reps,
+			})
+		}
+	}
+	return req
+}
+
+func sessionName(c Constraints) string {
+	switch {
+	case len(c.TargetMuscles) > 0:
+		return "Generated " + strings.Join(c.TargetMuscles, ", ") + " session"
+	case c.Category != "":
+		return "Generated " + c.Category + " session"
+	}
+	return "Generated session"
+}
+
+func primaryMuscles(candidate model.CandidateExercise) []string {
+	primaries := []string{}
+	for _, m := range candidate.Muscles {
+		if m.MuscleRole == model.MuscleRolePrimary {
+			primaries = append(primaries, m.MuscleCode)
+		}
+	}
+	slices.Sort(primaries)
+	return primaries
+}
+
+func hitsAny(m model.CandidateMuscle, codes map[string]bool) bool {
+	return slices.ContainsFunc(m.Ancestors, func(code string) bool { return codes[code] })
+}
+
+func codeSet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+	return set
+}
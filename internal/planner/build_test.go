@@ -0,0 +1,187 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 5, 18, 0, 0, 0, time.UTC)
+
+func candidate(name, category string, apparatus []string, muscles ...model.CandidateMuscle) model.CandidateExercise {
+	return model.CandidateExercise{
+		ExerciseUuid: uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)),
+		ExerciseName: name,
+		CategoryCode: category,
+		Apparatus:    apparatus,
+		Muscles:      muscles,
+	}
+}
+
+// patterns are the movement patterns of the muscles of the test catalog.
+var patterns = map[string]model.MovementPattern{
+	"CHEST": model.MovementPush, "SHOULDERS": model.MovementPush, "TRICEPS": model.MovementPush,
+	"QUAD": model.MovementPush, "BACK": model.MovementPull, "POSTERIOR_DELTOID": model.MovementPull,
+	"BICEPS": model.MovementPull, "HAMSTRINGS": model.MovementPull, "GLUTES": model.MovementPull,
+}
+
+// worked returns a muscle given by its code and those of the muscles above
+// it, with the pattern of the nearest that has one, like the planner DAO.
+func worked(role model.MuscleRole, ancestors ...string) model.CandidateMuscle {
+	m := model.CandidateMuscle{MuscleCode: ancestors[0], MuscleRole: role, Ancestors: ancestors, Pattern: model.MovementOther}
+	for _, code := range ancestors {
+		if pattern, ok := patterns[code]; ok {
+			m.Pattern = pattern
+			break
+		}
+	}
+	return m
+}
+
+func primary(ancestors ...string) model.CandidateMuscle {
+	return worked(model.MuscleRolePrimary, ancestors...)
+}
+
+func secondary(ancestors ...string) model.CandidateMuscle {
+	return worked(model.MuscleRoleSecondary, ancestors...)
+}
+
+var catalog = []model.CandidateExercise{
+	candidate("Back Squat", "STRENGTH", []string{"BARBELL"}, primary("QUAD", "LEGS"), secondary("GLUTES", "LEGS")),
+	candidate("Romanian Deadlift", "STRENGTH", []string{"BARBELL"}, primary("HAMSTRINGS", "LEGS")),
+	candidate("Bench Press", "STRENGTH", []string{"BARBELL", "BENCH"}, primary("CHEST", "UPPER_BODY"), secondary("TRICEPS", "ARMS")),
+	candidate("Push-up", "STRENGTH", nil, primary("CHEST", "UPPER_BODY")),
+	candidate("Pull-up", "STRENGTH", []string{"PULL_UP_BAR"}, primary("LATS", "BACK", "UPPER_BODY"), secondary("BICEPS", "ARMS")),
+	candidate("Inverted Row", "STRENGTH", nil, primary("RHOMBOIDS", "BACK", "UPPER_BODY")),
+	candidate("Overhead Press", "STRENGTH", []string{"BARBELL"}, primary("SHOULDERS", "UPPER_BODY")),
+	candidate("Plank", "STRENGTH", nil, primary("ABS", "CORE")),
+	candidate("Running", "CARDIO", nil),
+}
+
+func names(plan *model.SessionPlan) []string {
+	var names []string
+	for _, ex := range plan.Exercises {
+		names = append(names, ex.ExerciseName)
+	}
+	return names
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	c := Constraints{Category: "STRENGTH", TimeBudgetMinutes: 30, Experience: model.ExperienceIntermediate, Seed: 42, Now: now}
+
+	first, err := Build(catalog, c)
+	assert.NoError(t, err)
+	reversed := make([]model.CandidateExercise, len(catalog))
+	for i, ex := range catalog {
+		reversed[len(catalog)-1-i] = ex
+	}
+	second, err := Build(reversed, c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, uint64(42), first.Seed)
+}
+
+func TestBuild_VolumeAndSession(t *testing.T) {
+	tests := []struct {
+		experience model.ExperienceLevel
+		minutes    int
+		exercises  int
+		sets, reps int
+	}{
+		// 3 x (40 + 60) + 60 = 360 seconds an exercise
+		{model.ExperienceBeginner, 30, 5, 3, 10},
+		// 3 x (40 + 90) + 60 = 450 seconds an exercise
+		{model.ExperienceIntermediate, 30, 4, 3, 8},
+		// 4 x (40 + 120) + 60 = 700 seconds an exercise
+		{model.ExperienceAdvanced, 30, 2, 4, 6},
+		{model.ExperienceAdvanced, 10, 1, 4, 6},
+	}
+	for _, tt := range tests {
+		plan, err := Build(catalog, Constraints{Category: "STRENGTH", TimeBudgetMinutes: tt.minutes, Experience: tt.experience, Now: now})
+		assert.NoError(t, err)
+		assert.Len(t, plan.Exercises, tt.exercises, tt.experience)
+		assert.Len(t, plan.Session.Sets, tt.exercises*tt.sets)
+		assert.Equal(t, tt.reps, *plan.Session.Sets[0].Reps)
+		assert.Equal(t, len(plan.Session.Sets), plan.Session.Sets[len(plan.Session.Sets)-1].SetNumber)
+		assert.Equal(t, now, plan.Session.StartedAt)
+		assert.Equal(t, model.SourceShred, plan.Session.Source)
+	}
+}
+
+func TestBuild_Equipment(t *testing.T) {
+	plan, err := Build(catalog, Constraints{Category: "STRENGTH", Apparatus: []string{}, TimeBudgetMinutes: 60, Now: now})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Push-up", "Inverted Row", "Plank"}, names(plan))
+
+	plan, err = Build(catalog, Constraints{Category: "STRENGTH", Apparatus: []string{"barbell"}, TimeBudgetMinutes: 180, Now: now})
+	assert.NoError(t, err)
+	assert.NotContains(t, names(plan), "Bench Press")
+	assert.Contains(t, names(plan), "Back Squat")
+}
+
+func TestBuild_TargetMuscles(t *testing.T) {
+	plan, err := Build(catalog, Constraints{TargetMuscles: []string{"legs"}, TimeBudgetMinutes: 60, Now: now})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Back Squat", "Romanian Deadlift"}, names(plan))
+	assert.Equal(t, "Generated legs session", plan.Session.SessionName)
+
+	_, err = Build(catalog, Constraints{TargetMuscles: []string{"NECK"}, Now: now})
+	assert.ErrorIs(t, err, ErrNoExercises)
+}
+
+func TestBuild_BalancesPushAndPull(t *testing.T) {
+	upper := []model.CandidateExercise{
+		candidate("Bench Press", "STRENGTH", nil, primary("CHEST", "UPPER_BODY")),
+		candidate("Dip", "STRENGTH", nil, primary("TRICEPS", "ARMS")),
+		candidate("Overhead Press", "STRENGTH", nil, primary("SHOULDERS", "UPPER_BODY")),
+		candidate("Pull-up", "STRENGTH", nil, primary("LATS", "BACK")),
+		candidate("Curl", "STRENGTH", nil, primary("BICEPS", "ARMS")),
+	}
+	for seed := range uint64(20) {
+		plan, err := Build(upper, Constraints{TimeBudgetMinutes: 24, Seed: seed, Now: now})
+		assert.NoError(t, err)
+		assert.Len(t, plan.Exercises, 4)
+
+		patterns := map[model.MovementPattern]int{}
+		for _, ex := range plan.Exercises {
+			patterns[ex.Pattern]++
+		}
+		assert.Equal(t, 2, patterns[model.MovementPush], "seed %d", seed)
+		assert.Equal(t, 2, patterns[model.MovementPull], "seed %d", seed)
+	}
+}
+
+func TestBuild_AvoidsUnrecoveredMuscles(t *testing.T) {
+	unrecovered := map[string]bool{"QUAD": true, "LEGS": true}
+	for seed := range uint64(20) {
+		plan, err := Build(catalog, Constraints{Category: "STRENGTH", TimeBudgetMinutes: 30, Seed: seed, Unrecovered: unrecovered, Now: now})
+		assert.NoError(t, err)
+		assert.NotContains(t, names(plan), "Back Squat", "seed %d", seed)
+
+		// the hamstrings are fresh even though the legs as a whole are not
+		plan, err = Build(catalog, Constraints{TargetMuscles: []string{"LEGS"}, TimeBudgetMinutes: 10,
+			Experience: model.ExperienceAdvanced, Seed: seed, Unrecovered: unrecovered, Now: now})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Romanian Deadlift"}, names(plan), "seed %d", seed)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		exercise model.CandidateExercise
+		want     model.MovementPattern
+	}{
+		{candidate("Push-up", "", nil, primary("CHEST", "UPPER_BODY")), model.MovementPush},
+		{candidate("Face Pull", "", nil, primary("POSTERIOR_DELTOID", "SHOULDERS")), model.MovementPull},
+		{candidate("Plank", "", nil, primary("ABS", "CORE")), model.MovementOther},
+		{candidate("Thruster", "", nil, primary("QUAD", "LEGS"), primary("SHOULDERS"), primary("LATS")), model.MovementPush},
+		{candidate("Curl", "", nil, secondary("CHEST"), primary("BICEPS", "ARMS")), model.MovementPull},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Pattern(tt.exercise), tt.exercise.ExerciseName)
+	}
+}
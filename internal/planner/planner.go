@@ -0,0 +1,93 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/recovery"
+)
+
+type PlannerInterface interface {
+	Plan(ctx context.Context, userUuid uuid.UUID, req *model.SessionPlanRequest, now time.Time) (*model.SessionPlan, error)
+}
+
+// Planner builds sessions for a user from the catalog, their equipment and
+// their recent training.
+type Planner struct {
+	planner   dao.PlannerDaoInterface
+	equipment dao.EquipmentDaoInterface
+	tracker   recovery.TrackerInterface
+}
+
+// Ensure Planner implements PlannerInterface
+var _ PlannerInterface = (*Planner)(nil)
+
+// NewPlanner creates a new instance of Planner.
+func NewPlanner(planner dao.PlannerDaoInterface, equipment dao.EquipmentDaoInterface, tracker recovery.TrackerInterface) *Planner {
+	return &Planner{planner: planner, equipment: equipment, tracker: tracker}
+}
+
+// Plan resolves the equipment of the request, reads the candidates and the
+// recovery of the user's muscles at now and builds the plan. A random seed
+// is picked when the request has none.
+func (p *Planner) Plan(ctx context.Context, userUuid uuid.UUID, req *model.SessionPlanRequest, now time.Time) (*model.SessionPlan, error) {
+	c := Constraints{
+		TargetMuscles:     req.TargetMuscles,
+		Category:          req.Category,
+		Apparatus:         req.Apparatus,
+		TimeBudgetMinutes: req.TimeBudgetMinutes,
+		Experience:        req.Experience,
+		Now:               now,
+	}
+	if req.Seed != nil {
+		c.Seed = *req.Seed
+	} else {
+		c.Seed = uint64(now.UnixNano())
+	}
+
+	if c.Apparatus == nil {
+		apparatus, err := p.profileApparatus(ctx, userUuid, req.ProfileUuid)
+		if err != nil {
+			return nil, err
+		}
+		c.Apparatus = apparatus
+	}
+
+	candidates, err := p.planner.ListCandidates(ctx, req.Category)
+	if err != nil {
+		return nil, err
+	}
+	report, err := p.tracker.Report(ctx, userUuid, now)
+	if err != nil {
+		return nil, err
+	}
+	c.Unrecovered = recovery.Unrecovered(report)
+	return Build(candidates, c)
+}
+
+// profileApparatus returns the apparatus of the profile, or of the user's
+// default profile when profileUuid is nil. Users without a default profile
+// are not restricted, which is nil.
+func (p *Planner) profileApparatus(ctx context.Context, userUuid uuid.UUID, profileUuid *uuid.UUID) ([]string, error) {
+	var profile *model.EquipmentProfile
+	var err error
+	if profileUuid != nil {
+		profile, err = p.equipment.GetProfile(ctx, *profileUuid)
+	} else {
+		profile, err = p.equipment.GetDefaultProfile(ctx, userUuid)
+		if errors.Is(err, dao.ErrNotFound) {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if profile.Apparatus == nil {
+		return []string{}, nil
+	}
+	return profile.Apparatus, nil
+}
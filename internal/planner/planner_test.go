@@ -0,0 +1,134 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/recovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newTestPlanner returns a planner on a memory store holding a push-up for
+ * the chest and a back squat on a barbell for the quads, with the DAOs to
+ * add to it.
+ */
+func newTestPlanner(t *testing.T) (*Planner, *dao.Daos) {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	upperBody, legs := "UPPER_BODY", "LEGS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "UPPER_BODY", MuscleName: "Upper body", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "CHEST", MuscleName: "Chest", MuscleLevel: model.MuscleLevelGroup, ParentCode: &upperBody},
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUAD", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		require.NoError(t, err)
+	}
+	require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+		ApparatusFields: model.ApparatusFields{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}}))
+
+	for _, ex := range []struct {
+		name, muscle string
+		apparatus    []string
+	}{{"Push-up", "CHEST", nil}, {"Back Squat", "QUAD", []string{"BARBELL"}}} {
+		created, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+			ExerciseFields: model.ExerciseFields{ExerciseName: ex.name, CategoryCode: "STRENGTH"}})
+		require.NoError(t, err)
+		require.NoError(t, daos.Exercises.AddMuscles(ctx, created.ExerciseUuid,
+			[]model.ExerciseMuscle{{MuscleCode: ex.muscle, MuscleRole: model.MuscleRolePrimary}}))
+		require.NoError(t, daos.Exercises.AddApparatus(ctx, created.ExerciseUuid, ex.apparatus))
+	}
+
+	tracker := recovery.NewTracker(daos.Recovery, daos.Muscles)
+	return NewPlanner(daos.Planner, daos.Equipment, tracker), daos
+}
+
+func TestPlan_DefaultProfile(t *testing.T) {
+	planner, daos := newTestPlanner(t)
+
+	userUuid := uuid.New()
+	_, err := daos.Equipment.CreateProfile(context.Background(), &userUuid, &model.EquipmentProfileRequest{
+		EquipmentProfileFields: model.EquipmentProfileFields{ProfileName: "Travel", IsDefault: true}, CreatedBy: userUuid})
+	require.NoError(t, err)
+
+	seed := uint64(7)
+	plan, err := planner.Plan(context.Background(), userUuid, &model.SessionPlanRequest{Category: "strength", Seed: &seed}, now)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Exercises, 1)
+	assert.Equal(t, "Push-up", plan.Exercises[0].ExerciseName)
+	assert.Equal(t, seed, plan.Seed)
+}
+
+func TestPlan_NoDefaultProfile(t *testing.T) {
+	planner, _ := newTestPlanner(t)
+
+	plan, err := planner.Plan(context.Background(), uuid.New(), &model.SessionPlanRequest{Category: "STRENGTH"}, now)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Exercises, 2)
+	assert.Equal(t, uint64(now.UnixNano()), plan.Seed)
+}
+
+func TestPlan_ApparatusOverridesProfile(t *testing.T) {
+	planner, _ := newTestPlanner(t)
+
+	// the profile does not exist, so looking it up would fail
+	missing := uuid.New()
+	plan, err := planner.Plan(context.Background(), uuid.New(),
+		&model.SessionPlanRequest{Category: "STRENGTH", Apparatus: []string{"BARBELL"}, ProfileUuid: &missing}, now)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Exercises, 2)
+}
+
+func TestPlan_MissingProfile(t *testing.T) {
+	planner, _ := newTestPlanner(t)
+
+	missing := uuid.New()
+	_, err := planner.Plan(context.Background(), uuid.New(),
+		&model.SessionPlanRequest{Category: "STRENGTH", ProfileUuid: &missing}, now)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
+
+func TestPlan_SampleCatalog(t *testing.T) {
+	// the muscles and exercise of db/sample.sql
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength Training"}})
+	require.NoError(t, err)
+	legs, quad, push := "LEGS", "QUAD", model.MovementPush
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUAD", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs, MovementPattern: &push},
+		{MuscleCode: "RECTUS_FEMORIS", MuscleName: "Rectus femoris", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &quad},
+		{MuscleCode: "VASTUS_LATERALIS", MuscleName: "Vastus lateralis", MuscleLevel: model.MuscleLevelMuscle, ParentCode: &quad},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		require.NoError(t, err)
+	}
+	squat, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Exercises.AddMuscles(ctx, squat.ExerciseUuid, []model.ExerciseMuscle{
+		{MuscleCode: "RECTUS_FEMORIS", MuscleRole: model.MuscleRolePrimary},
+		{MuscleCode: "VASTUS_LATERALIS", MuscleRole: model.MuscleRolePrimary},
+	}))
+	planner := NewPlanner(daos.Planner, daos.Equipment, recovery.NewTracker(daos.Recovery, daos.Muscles))
+
+	plan, err := planner.Plan(ctx, uuid.New(), &model.SessionPlanRequest{TargetMuscles: []string{"legs"}}, now)
+	assert.NoError(t, err)
+	if assert.Len(t, plan.Exercises, 1) {
+		assert.Equal(t, "Squat", plan.Exercises[0].ExerciseName)
+		assert.Equal(t, model.MovementPush, plan.Exercises[0].Pattern, "the muscles take the pattern of the quadriceps")
+		assert.Equal(t, []string{"RECTUS_FEMORIS", "VASTUS_LATERALIS"}, plan.Exercises[0].PrimaryMuscles)
+	}
+}
@@ -0,0 +1,235 @@
+// Package plates works out how to load a barbell from the plates at hand
+// and ramps warm-up sets up to a working set.
+package plates
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// ErrBelowBar is returned for a load lighter than the empty bar.
+var ErrBelowBar = errors.New("load is below the bar")
+
+// Bounds on the plates of a barbell and the loads worked out for them, so
+// the search for the best loading stays cheap.
+const (
+	maxPlateSizes    = 12
+	maxPlatesPerSize = 100
+	maxPlates        = 300
+	// maxSideSteps bounds the loads per side searched, in steps of the
+	// greatest common divisor of the plates.
+	maxSideSteps = 1 << 16
+)
+
+// standard is the bar and the plates of a gym, in the unit they are sold in.
+var standard = map[units.WeightUnit]struct {
+	bar    float64
+	plates []model.Plate
+}{
+	units.Kg: {bar: 20, plates: []model.Plate{
+		{Weight: 25, Count: 8}, {Weight: 20, Count: 2}, {Weight: 15, Count: 2}, {Weight: 10, Count: 2},
+		{Weight: 5, Count: 2}, {Weight: 2.5, Count: 2}, {Weight: 1.25, Count: 2},
+	}},
+	units.Lb: {bar: 45, plates: []model.Plate{
+		{Weight: 45, Count: 8}, {Weight: 35, Count: 2}, {Weight: 25, Count: 2}, {Weight: 10, Count: 2},
+		{Weight: 5, Count: 2}, {Weight: 2.5, Count: 2},
+	}},
+}
+
+// Barbell is a bar with the plates that can go on it.
+type Barbell struct {
+	Unit   units.WeightUnit
+	Bar    float64
+	Plates []model.Plate
+}
+
+/*
+ * NewBarbell creates a barbell in unit, kg when empty, with the standard bar
+ * and plates of the unit unless bar and plates are given. Plates of the same
+ * weight are merged.
+ */
+func NewBarbell(unit units.WeightUnit, bar *float64, plates []model.Plate) (*Barbell, error) {
+	if unit == "" {
+		unit = units.Kg
+	}
+	defaults, ok := standard[unit]
+	if !ok {
+		return nil, fmt.Errorf("unknown weight unit %q", unit)
+	}
+
+	b := &Barbell{Unit: unit, Bar: defaults.bar}
+	if bar != nil {
+		if *bar < 0 {
+			return nil, errors.New("bar must not be negative")
+		}
+		b.Bar = *bar
+	}
+	if plates == nil {
+		plates = defaults.plates
+	}
+	counts := map[int]int{}
+	total := 0
+	for _, p := range plates {
+		if p.Weight <= 0 || p.Count < 0 {
+			return nil, errors.New("plates need a positive weight and a count")
+		}
+		counts[hundredths(p.Weight)] += p.Count
+		total += p.Count
+	}
+	if len(counts) > maxPlateSizes {
+		return nil, fmt.Errorf("at most %d plate sizes are supported", maxPlateSizes)
+	}
+	if total > maxPlates {
+		return nil, fmt.Errorf("at most %d plates are supported", maxPlates)
+	}
+	for weight, count := range counts {
+		if count > maxPlatesPerSize {
+			return nil, fmt.Errorf("at most %d plates of %g %s are supported", maxPlatesPerSize, float64(weight)/100, unit)
+		}
+	}
+	for weight, count := range counts {
+		b.Plates = append(b.Plates, model.Plate{Weight: float64(weight) / 100, Count: count})
+	}
+	slices.SortFunc(b.Plates, func(a, b model.Plate) int { return cmp.Compare(b.Weight, a.Weight) })
+	return b, nil
+}
+
+/*
+ * Load works out the plates for each side of the bar that come closest to
+ * target without going over it, using as few plates as possible. Plates are
+ * loaded in pairs, so an odd plate is left over. Among loadings with as few
+ * plates, the one with the most heavy plates wins.
+ */
+func (b *Barbell) Load(target float64) (*model.PlateLoading, error) {
+	if hundredths(target) < hundredths(b.Bar) {
+		return nil, b.belowBar(target)
+	}
+
+	// Loads per side are counted in steps of the greatest common divisor of
+	// the plates, as no loading falls between two steps.
+	step, capacity := 0, 0
+	weights := make([]int, len(b.Plates))
+	pairs := make([]int, len(b.Plates))
+	for i, p := range b.Plates {
+		weights[i], pairs[i] = hundredths(p.Weight), p.Count/2
+		if pairs[i] > 0 {
+			step = gcd(step, weights[i])
+			capacity += weights[i] * pairs[i]
+		}
+	}
+	perSide := make([]int, len(b.Plates))
+	side := 0
+	if step > 0 {
+		steps := min((hundredths(target)-hundredths(b.Bar))/2, capacity) / step
+		if steps > maxSideSteps {
+			return nil, fmt.Errorf("cannot work out %g %s in steps of %g %s", target, b.Unit, float64(2*step)/100, b.Unit)
+		}
+		for i := range weights {
+			if pairs[i] > 0 {
+				weights[i] /= step
+			}
+		}
+		side = step * fewestPlates(weights, pairs, steps, perSide)
+	}
+
+	loading := &model.PlateLoading{Target: target, Bar: b.Bar, Unit: b.Unit, PerSide: []model.Plate{}}
+	for i, n := range perSide {
+		if n > 0 {
+			loading.PerSide = append(loading.PerSide, model.Plate{Weight: b.Plates[i].Weight, Count: n})
+		}
+	}
+	loading.Total = float64(hundredths(b.Bar)+2*side) / 100
+	loading.Exact = hundredths(loading.Total) == hundredths(target)
+	return loading, nil
+}
+
+func (b *Barbell) belowBar(load float64) error {
+	return fmt.Errorf("%w: %g %s is below the %g %s bar", ErrBelowBar, load, b.Unit, b.Bar, b.Unit)
+}
+
+/*
+ * fewestPlates finds the heaviest side of at most limit that the pairs of
+ * plates of weights, heaviest first, can make, fills perSide with the pairs
+ * of each weight that make it with as few plates as possible and returns
+ * the side. Weights without pairs are left out. It is a dynamic program over
+ * the sides up to limit, adding one weight at a time from the lightest, so
+ * it takes time in proportion to the number of weights times limit.
+ */
+func fewestPlates(weights, pairs []int, limit int, perSide []int) int {
+	const unreachable = math.MaxInt32
+	// fewest[s] is the fewest pairs that make side s from the weights added
+	// so far.
+	fewest := make([]int32, limit+1)
+	for s := 1; s <= limit; s++ {
+		fewest[s] = unreachable
+	}
+	next := make([]int32, limit+1)
+	// chosen[i][s] is how many pairs of weight i the fewest pairs for side
+	// s take, with only weight i and lighter ones.
+	chosen := make([][]uint8, len(weights))
+	queue := make([]int, limit+1)
+	for i := len(weights) - 1; i >= 0; i-- {
+		w, p := weights[i], pairs[i]
+		if p == 0 {
+			continue
+		}
+		chosen[i] = make([]uint8, limit+1)
+		// Sides r, r+w, r+2w... differ by whole pairs of w, so the fewest
+		// pairs for the j-th of them is the minimum of before(k)+j over the
+		// p+1 sides k before it, kept in a monotonic queue. Ties keep the
+		// earliest side, which takes more pairs of w.
+		before := func(k, r int) int32 { return fewest[r+k*w] - int32(k) }
+		for r := 0; r < w && r <= limit; r++ {
+			head, tail := 0, 0
+			for j, s := 0, r; s <= limit; j, s = j+1, s+w {
+				if fewest[s] != unreachable {
+					for tail > head && before(queue[tail-1], r) > before(j, r) {
+						tail--
+					}
+					queue[tail] = j
+					tail++
+				}
+				if tail > head && queue[head] < j-p {
+					head++
+				}
+				if tail == head {
+					next[s] = unreachable
+					continue
+				}
+				next[s] = before(queue[head], r) + int32(j)
+				chosen[i][s] = uint8(j - queue[head])
+			}
+		}
+		fewest, next = next, fewest
+	}
+
+	side := limit
+	for fewest[side] == unreachable {
+		side--
+	}
+	for i, s := 0, side; i < len(weights); i++ {
+		if chosen[i] != nil {
+			perSide[i] = int(chosen[i][s])
+			s -= perSide[i] * weights[i]
+		}
+	}
+	return side
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// hundredths turns a load into an integer, so sums of plates are exact.
+func hundredths(load float64) int {
+	return int(math.Round(load * 100))
+}
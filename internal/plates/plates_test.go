@@ -0,0 +1,119 @@
+package plates
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		unit    units.WeightUnit
+		bar     *float64
+		plates  []model.Plate
+		target  float64
+		total   float64
+		exact   bool
+		perSide []model.Plate
+	}{
+		{name: "empty bar", target: 20, total: 20, exact: true, perSide: []model.Plate{}},
+		{name: "standard kg", target: 142.5, total: 142.5, exact: true,
+			perSide: []model.Plate{{Weight: 25, Count: 2}, {Weight: 10, Count: 1}, {Weight: 1.25, Count: 1}}},
+		{name: "heaviest plates first", target: 100, total: 100, exact: true,
+			perSide: []model.Plate{{Weight: 25, Count: 1}, {Weight: 15, Count: 1}}},
+		{name: "rounds down", target: 101, total: 100, perSide: []model.Plate{{Weight: 25, Count: 1}, {Weight: 15, Count: 1}}},
+		{name: "standard lb", unit: units.Lb, target: 315, total: 315, exact: true,
+			perSide: []model.Plate{{Weight: 45, Count: 3}}},
+		{name: "lb with change", unit: units.Lb, target: 230, total: 230, exact: true,
+			perSide: []model.Plate{{Weight: 45, Count: 2}, {Weight: 2.5, Count: 1}}},
+		{name: "odd plate left over", bar: ptr(15), plates: []model.Plate{{Weight: 20, Count: 3}, {Weight: 10, Count: 2}},
+			target: 75, total: 75, exact: true, perSide: []model.Plate{{Weight: 20, Count: 1}, {Weight: 10, Count: 1}}},
+		{name: "greedy would miss", bar: ptr(20), plates: []model.Plate{{Weight: 15, Count: 2}, {Weight: 10, Count: 4}},
+			target: 60, total: 60, exact: true, perSide: []model.Plate{{Weight: 10, Count: 2}}},
+		{name: "fewest plates", bar: ptr(20), plates: []model.Plate{{Weight: 5, Count: 8}, {Weight: 10, Count: 2}},
+			target: 40, total: 40, exact: true, perSide: []model.Plate{{Weight: 10, Count: 1}}},
+		{name: "not enough plates", bar: ptr(20), plates: []model.Plate{{Weight: 20, Count: 2}},
+			target: 100, total: 60, perSide: []model.Plate{{Weight: 20, Count: 1}}},
+		{name: "merged sizes", bar: ptr(10), plates: []model.Plate{{Weight: 5, Count: 1}, {Weight: 5, Count: 1}},
+			target: 20, total: 20, exact: true, perSide: []model.Plate{{Weight: 5, Count: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barbell, err := NewBarbell(tt.unit, tt.bar, tt.plates)
+			assert.NoError(t, err)
+			loading, err := barbell.Load(tt.target)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, loading.Total)
+			assert.Equal(t, tt.exact, loading.Exact)
+			assert.Equal(t, tt.perSide, loading.PerSide)
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	barbell, err := NewBarbell(units.Kg, nil, nil)
+	assert.NoError(t, err)
+	_, err = barbell.Load(15)
+	assert.True(t, errors.Is(err, ErrBelowBar))
+	assert.EqualError(t, err, "load is below the bar: 15 kg is below the 20 kg bar")
+
+	tests := []struct {
+		unit   units.WeightUnit
+		bar    *float64
+		plates []model.Plate
+		want   string
+	}{
+		{"stone", nil, nil, `unknown weight unit "stone"`},
+		{units.Kg, ptr(-1), nil, "bar must not be negative"},
+		{units.Kg, nil, []model.Plate{{Weight: 0, Count: 2}}, "plates need a positive weight and a count"},
+		{units.Kg, nil, []model.Plate{{Weight: 1, Count: 1}, {Weight: 2, Count: 1}, {Weight: 3, Count: 1}, {Weight: 4, Count: 1},
+			{Weight: 5, Count: 1}, {Weight: 6, Count: 1}, {Weight: 7, Count: 1}, {Weight: 8, Count: 1}, {Weight: 9, Count: 1},
+			{Weight: 10, Count: 1}, {Weight: 11, Count: 1}, {Weight: 12, Count: 1}, {Weight: 13, Count: 1}},
+			"at most 12 plate sizes are supported"},
+		{units.Kg, nil, []model.Plate{{Weight: 20, Count: 60}, {Weight: 20, Count: 41}}, "at most 100 plates of 20 kg are supported"},
+		{units.Kg, nil, []model.Plate{{Weight: 25, Count: 100}, {Weight: 20, Count: 100}, {Weight: 15, Count: 100},
+			{Weight: 10, Count: 1}}, "at most 300 plates are supported"},
+	}
+	for _, tt := range tests {
+		_, err := NewBarbell(tt.unit, tt.bar, tt.plates)
+		assert.EqualError(t, err, tt.want)
+	}
+}
+
+func TestLoad_TooFine(t *testing.T) {
+	barbell, err := NewBarbell(units.Kg, nil, []model.Plate{{Weight: 0.01, Count: 2}, {Weight: 1000, Count: 100}})
+	assert.NoError(t, err)
+	_, err = barbell.Load(2000)
+	assert.EqualError(t, err, "cannot work out 2000 kg in steps of 0.02 kg")
+}
+
+// TestLoad_LargeInventory checks that the most plates a barbell takes are
+// loaded in well under a second.
+func TestLoad_LargeInventory(t *testing.T) {
+	var inventory []model.Plate
+	for _, weight := range []float64{50, 45, 25, 20, 15, 10, 5, 2.5, 2, 1.25, 1, 0.5} {
+		inventory = append(inventory, model.Plate{Weight: weight, Count: 25})
+	}
+	barbell, err := NewBarbell(units.Kg, nil, inventory)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	loading, err := barbell.Load(1000.5)
+	assert.NoError(t, err)
+	assert.True(t, loading.Exact)
+	loading, err = barbell.Load(4274)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, loading.Exact)
+	assert.Len(t, loading.PerSide, 12)
+	for _, plate := range loading.PerSide {
+		assert.Equal(t, 12, plate.Count)
+	}
+}
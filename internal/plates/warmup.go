@@ -0,0 +1,61 @@
+package plates
+
+import (
+	"errors"
+	"math"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+// ramp is a warm-up step: a share of the working load and its reps.
+type ramp struct {
+	share float64
+	reps  int
+}
+
+// ramps climb from the empty bar to the working load with fewer reps as the
+// load gets closer. The last step only comes before heavy sets of at most
+// heavyReps reps.
+var ramps = []ramp{{0, 10}, {0.4, 5}, {0.6, 3}, {0.8, 2}, {0.9, 1}}
+
+const heavyReps = 3
+
+/*
+ * Warmup ramps up to a working set of reps at load. Each step is loaded as
+ * close to its share of the working load as the plates allow without going
+ * over; steps that would repeat a load or reach the working load are left
+ * out, so light working sets get fewer warm-up sets.
+ */
+func (b *Barbell) Warmup(load float64, reps int) (*model.Warmup, error) {
+	if reps <= 0 {
+		return nil, errors.New("reps must be positive")
+	}
+	if hundredths(load) < hundredths(b.Bar) {
+		return nil, b.belowBar(load)
+	}
+
+	warmup := &model.Warmup{WorkingLoad: load, Reps: reps, Unit: b.Unit, Sets: []model.WarmupSet{}}
+	previous := -1
+	for i, step := range ramps {
+		if i == len(ramps)-1 && reps > heavyReps {
+			break
+		}
+		target := math.Max(b.Bar, load*step.share)
+		loading, err := b.Load(target)
+		if err != nil {
+			return nil, err
+		}
+		total := hundredths(loading.Total)
+		if total <= previous || total >= hundredths(load) {
+			continue
+		}
+		previous = total
+		warmup.Sets = append(warmup.Sets, model.WarmupSet{
+			Load:    loading.Total,
+			Reps:    step.reps,
+			Percent: int(math.Round(loading.Total / load * 100)),
+			PerSide: loading.PerSide,
+		})
+	}
+	return warmup, nil
+}
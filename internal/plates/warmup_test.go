@@ -0,0 +1,56 @@
+package plates
+
+import (
+	"testing"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmup(t *testing.T) {
+	type step struct {
+		load    float64
+		reps    int
+		percent int
+	}
+	tests := []struct {
+		name string
+		unit units.WeightUnit
+		load float64
+		reps int
+		want []step
+	}{
+		{"squat", units.Kg, 140, 5, []step{{20, 10, 14}, {55, 5, 39}, {82.5, 3, 59}, {110, 2, 79}}},
+		{"heavy single", units.Kg, 200, 1, []step{{20, 10, 10}, {80, 5, 40}, {120, 3, 60}, {160, 2, 80}, {180, 1, 90}}},
+		{"light", units.Kg, 40, 8, []step{{20, 10, 50}, {22.5, 3, 56}, {30, 2, 75}}},
+		{"empty bar", units.Kg, 20, 10, []step{}},
+		{"pounds", units.Lb, 225, 5, []step{{45, 10, 20}, {80, 5, 36}, {135, 3, 60}, {180, 2, 80}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barbell, err := NewBarbell(tt.unit, nil, nil)
+			assert.NoError(t, err)
+			warmup, err := barbell.Warmup(tt.load, tt.reps)
+			assert.NoError(t, err)
+			got := []step{}
+			for _, s := range warmup.Sets {
+				got = append(got, step{s.Load, s.Reps, s.Percent})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWarmup_Plates(t *testing.T) {
+	barbell, err := NewBarbell(units.Kg, nil, nil)
+	assert.NoError(t, err)
+	warmup, err := barbell.Warmup(100, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Plate{{Weight: 10, Count: 1}}, warmup.Sets[1].PerSide)
+
+	_, err = barbell.Warmup(100, 0)
+	assert.EqualError(t, err, "reps must be positive")
+	_, err = barbell.Warmup(10, 5)
+	assert.ErrorIs(t, err, ErrBelowBar)
+}
@@ -0,0 +1,118 @@
+package recovery
+
+import (
+	"math"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+)
+
+const (
+	// HalfLife is the time in which the fatigue of a set halves.
+	HalfLife = 36 * time.Hour
+	// Window is how far back sets are read. After it less than 4% of their
+	// fatigue is left.
+	Window = 7 * 24 * time.Hour
+	// capacity is the fatigue, in role-weighted sets, of a muscle with no
+	// freshness left.
+	capacity = 12.0
+
+	// FreshThreshold and FatiguedThreshold bound the freshness of the
+	// recovering status.
+	FreshThreshold    = 0.8
+	FatiguedThreshold = 0.5
+)
+
+// roleWeights is the share of a set's fatigue each role of a muscle takes.
+var roleWeights = map[model.MuscleRole]float64{
+	model.MuscleRolePrimary:    1,
+	model.MuscleRoleSecondary:  0.5,
+	model.MuscleRoleStabilizer: 0.25,
+}
+
+/*
+ * Compute returns the recovery of every muscle at now. Each set adds its
+ * role weight in fatigue to its muscle, decaying exponentially with
+ * HalfLife. Sets on a muscle tire the muscles below it too, e.g. sets on the
+ * quadriceps tire the rectus femoris, and a muscle is as tired as its most
+ * tired muscle below, so regions and groups show their hardest worked part.
+ * Muscles are returned in the order given.
+ */
+func Compute(muscles []model.Muscle, loads []model.MuscleLoad, now time.Time) []model.MuscleRecovery {
+	direct := map[string]float64{}
+	lastTrained := map[string]time.Time{}
+	for _, load := range loads {
+		age := max(now.Sub(load.StartedAt), 0)
+		direct[load.MuscleCode] += float64(load.Sets) * roleWeights[load.MuscleRole] *
+			math.Exp2(-age.Hours()/HalfLife.Hours())
+		if load.StartedAt.After(lastTrained[load.MuscleCode]) {
+			lastTrained[load.MuscleCode] = load.StartedAt
+		}
+	}
+
+	known := make(map[string]bool, len(muscles))
+	for _, m := range muscles {
+		known[m.MuscleCode] = true
+	}
+	children := map[string][]string{}
+	var roots []string
+	for _, m := range muscles {
+		if m.ParentCode != nil && known[*m.ParentCode] {
+			children[*m.ParentCode] = append(children[*m.ParentCode], m.MuscleCode)
+		} else {
+			roots = append(roots, m.MuscleCode)
+		}
+	}
+
+	fatigue := map[string]float64{}
+	trained := map[string]time.Time{}
+	var walk func(code string, inherited float64)
+	walk = func(code string, inherited float64) {
+		own := inherited + direct[code]
+		fatigue[code], trained[code] = own, lastTrained[code]
+		for _, child := range children[code] {
+			walk(child, own)
+			fatigue[code] = max(fatigue[code], fatigue[child])
+			if trained[child].After(trained[code]) {
+				trained[code] = trained[child]
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+
+	recoveries := make([]model.MuscleRecovery, len(muscles))
+	for i, m := range muscles {
+		f := round(fatigue[m.MuscleCode])
+		freshness := round(1 - min(f/capacity, 1))
+		recoveries[i] = model.MuscleRecovery{
+			MuscleCode:  m.MuscleCode,
+			MuscleName:  m.MuscleName,
+			MuscleLevel: m.MuscleLevel,
+			ParentCode:  m.ParentCode,
+			Fatigue:     f,
+			Freshness:   freshness,
+			Status:      status(freshness),
+		}
+		if at, ok := trained[m.MuscleCode]; ok && !at.IsZero() {
+			recoveries[i].LastTrainedAt = &at
+		}
+	}
+	return recoveries
+}
+
+func status(freshness float64) model.RecoveryStatus {
+	switch {
+	case freshness >= FreshThreshold:
+		return model.RecoveryFresh
+	case freshness >= FatiguedThreshold:
+		return model.RecoveryRecovering
+	}
+	return model.RecoveryFatigued
+}
+
+// round keeps two decimals, enough for a heat map.
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,93 @@
+package recovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 5, 18, 0, 0, 0, time.UTC)
+
+func muscle(code string, level model.MuscleLevel, parent string) model.Muscle {
+	m := model.Muscle{MuscleFields: model.MuscleFields{MuscleCode: code, MuscleName: code, MuscleLevel: level}}
+	if parent != "" {
+		m.ParentCode = &parent
+	}
+	return m
+}
+
+var muscles = []model.Muscle{
+	muscle("LEGS", model.MuscleLevelRegion, ""),
+	muscle("QUAD", model.MuscleLevelGroup, "LEGS"),
+	muscle("RECTUS_FEMORIS", model.MuscleLevelMuscle, "QUAD"),
+	muscle("VASTUS_LATERALIS", model.MuscleLevelMuscle, "QUAD"),
+	muscle("HAMSTRINGS", model.MuscleLevelGroup, "LEGS"),
+	muscle("CHEST", model.MuscleLevelGroup, ""),
+}
+
+func byCode(recoveries []model.MuscleRecovery) map[string]model.MuscleRecovery {
+	m := map[string]model.MuscleRecovery{}
+	for _, r := range recoveries {
+		m[r.MuscleCode] = r
+	}
+	return m
+}
+
+func TestCompute_Decay(t *testing.T) {
+	tests := []struct {
+		age       time.Duration
+		sets      int
+		role      model.MuscleRole
+		fatigue   float64
+		freshness float64
+		status    model.RecoveryStatus
+	}{
+		{0, 12, model.MuscleRolePrimary, 12, 0, model.RecoveryFatigued},
+		{HalfLife, 12, model.MuscleRolePrimary, 6, 0.5, model.RecoveryRecovering},
+		{2 * HalfLife, 12, model.MuscleRolePrimary, 3, 0.75, model.RecoveryRecovering},
+		{HalfLife, 12, model.MuscleRoleSecondary, 3, 0.75, model.RecoveryRecovering},
+		{HalfLife, 12, model.MuscleRoleStabilizer, 1.5, 0.88, model.RecoveryFresh},
+		{0, 24, model.MuscleRolePrimary, 24, 0, model.RecoveryFatigued},
+	}
+	for _, tt := range tests {
+		loads := []model.MuscleLoad{{StartedAt: now.Add(-tt.age), MuscleCode: "CHEST", MuscleRole: tt.role, Sets: tt.sets}}
+		chest := byCode(Compute(muscles, loads, now))["CHEST"]
+
+		assert.Equal(t, tt.fatigue, chest.Fatigue)
+		assert.Equal(t, tt.freshness, chest.Freshness)
+		assert.Equal(t, tt.status, chest.Status)
+		assert.Equal(t, now.Add(-tt.age), *chest.LastTrainedAt)
+	}
+}
+
+func TestCompute_Hierarchy(t *testing.T) {
+	loads := []model.MuscleLoad{
+		{StartedAt: now, MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary, Sets: 3},
+		{StartedAt: now.Add(-HalfLife), MuscleCode: "RECTUS_FEMORIS", MuscleRole: model.MuscleRolePrimary, Sets: 6},
+	}
+	recoveries := Compute(muscles, loads, now)
+	assert.Len(t, recoveries, len(muscles))
+	assert.Equal(t, "LEGS", recoveries[0].MuscleCode)
+
+	got := byCode(recoveries)
+	// sets on the group tire its muscles, the group is as tired as its most tired muscle
+	assert.Equal(t, 6.0, got["RECTUS_FEMORIS"].Fatigue)
+	assert.Equal(t, 3.0, got["VASTUS_LATERALIS"].Fatigue)
+	assert.Equal(t, 6.0, got["QUAD"].Fatigue)
+	assert.Equal(t, 6.0, got["LEGS"].Fatigue)
+	assert.Equal(t, now, *got["LEGS"].LastTrainedAt)
+	// siblings are not tired by each other
+	assert.Equal(t, 0.0, got["HAMSTRINGS"].Fatigue)
+	assert.Equal(t, 1.0, got["HAMSTRINGS"].Freshness)
+	assert.Nil(t, got["HAMSTRINGS"].LastTrainedAt)
+}
+
+func TestUnrecovered(t *testing.T) {
+	report := &model.RecoveryReport{Muscles: Compute(muscles, []model.MuscleLoad{
+		{StartedAt: now.Add(-24 * time.Hour), MuscleCode: "VASTUS_LATERALIS", MuscleRole: model.MuscleRolePrimary, Sets: 4},
+	}, now)}
+
+	assert.Equal(t, map[string]bool{"LEGS": true, "QUAD": true, "VASTUS_LATERALIS": true}, Unrecovered(report))
+}
@@ -0,0 +1,58 @@
+package recovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type TrackerInterface interface {
+	Report(ctx context.Context, userUuid uuid.UUID, now time.Time) (*model.RecoveryReport, error)
+}
+
+// Tracker computes the recovery of users' muscles from their logged sets.
+type Tracker struct {
+	loads   dao.RecoveryDaoInterface
+	muscles dao.MuscleDaoInterface
+}
+
+// Ensure Tracker implements TrackerInterface
+var _ TrackerInterface = (*Tracker)(nil)
+
+// NewTracker creates a new instance of Tracker.
+func NewTracker(loads dao.RecoveryDaoInterface, muscles dao.MuscleDaoInterface) *Tracker {
+	return &Tracker{loads: loads, muscles: muscles}
+}
+
+// Report computes the recovery of every muscle of the user at now from the
+// sets logged within Window before it.
+func (t *Tracker) Report(ctx context.Context, userUuid uuid.UUID, now time.Time) (*model.RecoveryReport, error) {
+	muscles, err := t.muscles.GetAllMuscles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loads, err := t.loads.ListMuscleLoads(ctx, userUuid, now.Add(-Window))
+	if err != nil {
+		return nil, err
+	}
+	return &model.RecoveryReport{
+		UserUuid:   userUuid,
+		ComputedAt: now,
+		Muscles:    Compute(muscles, loads, now),
+	}, nil
+}
+
+// Unrecovered returns the codes of the muscles of the report that are not
+// fresh yet.
+func Unrecovered(report *model.RecoveryReport) map[string]bool {
+	codes := map[string]bool{}
+	for _, m := range report.Muscles {
+		if m.Status != model.RecoveryFresh {
+			codes[m.MuscleCode] = true
+		}
+	}
+	return codes
+}
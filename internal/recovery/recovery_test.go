@@ -0,0 +1,52 @@
+package recovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	daos := memory.NewDaos(memory.NewStore())
+	tracker := NewTracker(daos.Recovery, daos.Muscles)
+	ctx := context.Background()
+
+	legs := "LEGS"
+	for _, mus := range []model.MuscleFields{
+		{MuscleCode: "LEGS", MuscleName: "Legs", MuscleLevel: model.MuscleLevelRegion},
+		{MuscleCode: "QUAD", MuscleName: "Quadriceps", MuscleLevel: model.MuscleLevelGroup, ParentCode: &legs},
+	} {
+		_, err := daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: mus})
+		require.NoError(t, err)
+	}
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	squat, err := daos.Exercises.Create(ctx, &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Exercises.AddMuscles(ctx, squat.ExerciseUuid,
+		[]model.ExerciseMuscle{{MuscleCode: "QUAD", MuscleRole: model.MuscleRolePrimary}}))
+
+	userUuid := uuid.New()
+	session := model.WorkoutSessionRequest{WorkoutSessionFields: model.WorkoutSessionFields{
+		SessionName: "Legs", StartedAt: now.Add(-HalfLife)}}
+	for i := 1; i <= 8; i++ {
+		session.Sets = append(session.Sets, model.WorkoutSet{SetNumber: i, ExerciseUuid: squat.ExerciseUuid})
+	}
+	_, err = daos.Workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{session})
+	require.NoError(t, err)
+
+	report, err := tracker.Report(ctx, userUuid, now)
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid, report.UserUuid)
+	assert.Equal(t, now, report.ComputedAt)
+	assert.Len(t, report.Muscles, 2)
+	assert.Equal(t, 4.0, report.Muscles[1].Fatigue)
+	assert.Equal(t, model.RecoveryRecovering, report.Muscles[0].Status)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/i18n"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type ExerciseServiceInterface interface {
+	Create(ctx context.Context, exReq *model.ExerciseRequest) (*model.Exercise, error)
+	Get(ctx context.Context, exUuid uuid.UUID, acceptLanguage string) (*model.Exercise, string, error)
+	Update(ctx context.Context, exUuid uuid.UUID, ex *model.Exercise) error
+	Delete(ctx context.Context, exUuid uuid.UUID) error
+}
+
+// ExerciseService creates, localizes and changes exercises.
+type ExerciseService struct {
+	exercises    dao.ExerciseDaoInterface
+	translations dao.TranslationDaoInterface
+	uow          dao.UnitOfWorkInterface
+}
+
+// Ensure ExerciseService implements ExerciseServiceInterface
+var _ ExerciseServiceInterface = (*ExerciseService)(nil)
+
+// NewExerciseService creates a new instance of ExerciseService. Exercises
+// are not localized without translations.
+func NewExerciseService(exercises dao.ExerciseDaoInterface, translations dao.TranslationDaoInterface,
+	uow dao.UnitOfWorkInterface) *ExerciseService {
+	return &ExerciseService{exercises: exercises, translations: translations, uow: uow}
+}
+
+/*
+ * Create stores an exercise together with the muscles it works and the
+ * apparatus it needs, all or nothing. Invalid muscle roles and unknown
+ * muscles or apparatus wrap ErrInvalid.
+ */
+func (s *ExerciseService) Create(ctx context.Context, exReq *model.ExerciseRequest) (*model.Exercise, error) {
+	for _, mus := range exReq.Muscles {
+		if !mus.MuscleRole.Valid() {
+			return nil, invalid(fmt.Errorf("invalid role %q of muscle %s", mus.MuscleRole, mus.MuscleCode))
+		}
+	}
+
+	var ex *model.Exercise
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if ex, err = s.exercises.Create(ctx, exReq); err != nil {
+			return err
+		}
+		if err := s.exercises.AddMuscles(ctx, ex.ExerciseUuid, exReq.Muscles); err != nil {
+			return err
+		}
+		return s.exercises.AddApparatus(ctx, ex.ExerciseUuid, exReq.Apparatus)
+	})
+	if errors.Is(err, dao.ErrNotFound) {
+		return nil, invalid(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ex.Muscles, ex.Apparatus = exReq.Muscles, exReq.Apparatus
+	return ex, nil
+}
+
+/*
+ * Get returns an exercise with the translation that best matches the
+ * Accept-Language header overlaid, and the locale it is in. Texts that are
+ * not translated stay in the default language. Without the header the
+ * translations are not looked up.
+ */
+func (s *ExerciseService) Get(ctx context.Context, exUuid uuid.UUID, acceptLanguage string) (*model.Exercise, string, error) {
+	ex, err := s.exercises.Read(ctx, exUuid)
+	if err != nil {
+		return nil, "", err
+	}
+	if acceptLanguage == "" || s.translations == nil {
+		return ex, i18n.DefaultLocale, nil
+	}
+
+	translations, err := s.translations.GetExerciseTranslations(ctx, ex.ExerciseUuid)
+	if err != nil {
+		return nil, "", err
+	}
+	locales := make([]string, len(translations))
+	for i, tr := range translations {
+		locales[i] = tr.Locale
+	}
+
+	locale := i18n.Negotiate(acceptLanguage, locales)
+	for _, tr := range translations {
+		if tr.Locale == locale {
+			tr.Apply(&ex.ExerciseFields)
+		}
+	}
+	return ex, locale, nil
+}
+
+// Update replaces the exercise with the given UUID, which must be the UUID
+// of ex.
+func (s *ExerciseService) Update(ctx context.Context, exUuid uuid.UUID, ex *model.Exercise) error {
+	if ex.ExerciseUuid != exUuid {
+		return invalid(errors.New("UUID in path does not match UUID in request body"))
+	}
+	return s.exercises.Update(ctx, ex)
+}
+
+// Delete deletes an exercise together with the links to the muscles it
+// works and the apparatus it needs, all or nothing.
+func (s *ExerciseService) Delete(ctx context.Context, exUuid uuid.UUID) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.exercises.RemoveMuscles(ctx, exUuid); err != nil {
+			return err
+		}
+		if err := s.exercises.RemoveApparatus(ctx, exUuid); err != nil {
+			return err
+		}
+		return s.exercises.Delete(ctx, exUuid)
+	})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/dao/memory"
+	"github.com/pwydra/shred/internal/i18n"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+ * newTestDaos returns the DAOs of a memory store holding the STRENGTH
+ * category, the QUADS muscle and the BARBELL.
+ */
+func newTestDaos(t *testing.T) *dao.Daos {
+	daos := memory.NewDaos(memory.NewStore())
+	ctx := context.Background()
+	_, err := daos.Categories.CreateCategory(ctx, &model.CategoryRequest{
+		CategoryFields: model.CategoryFields{CategoryCode: "STRENGTH", CategoryName: "Strength"}})
+	require.NoError(t, err)
+	_, err = daos.Muscles.CreateMuscle(ctx, &model.MuscleRequest{MuscleFields: model.MuscleFields{
+		MuscleCode: "QUADS", MuscleName: "Quadriceps", MuscleDesc: "Front of the thigh", MuscleLevel: model.MuscleLevelGroup}})
+	require.NoError(t, err)
+	require.NoError(t, daos.Apparatus.CreateApparatus(ctx, &model.ApparatusRequest{
+		ApparatusFields: model.ApparatusFields{ApparatusCode: "BARBELL", ApparatusName: "Barbell"}}))
+	return daos
+}
+
+func newExerciseService(t *testing.T) (*ExerciseService, *dao.Daos) {
+	daos := newTestDaos(t)
+	return NewExerciseService(daos.Exercises, daos.Translations, daos.UnitOfWork), daos
+}
+
+func newFrontSquat() *model.ExerciseRequest {
+	return &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Front Squat", CategoryCode: "STRENGTH"},
+		Muscles:        []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: model.MuscleRolePrimary}},
+		Apparatus:      []string{"BARBELL"},
+	}
+}
+
+func TestCreateExercise(t *testing.T) {
+	svc, daos := newExerciseService(t)
+
+	exReq := newFrontSquat()
+	ex, err := svc.Create(context.Background(), exReq)
+	require.NoError(t, err)
+	assert.Equal(t, exReq.Muscles, ex.Muscles)
+	assert.Equal(t, exReq.Apparatus, ex.Apparatus)
+
+	exercises, err := daos.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 1) {
+		assert.Equal(t, exReq.Muscles, exercises[0].Muscles)
+		assert.Equal(t, exReq.Apparatus, exercises[0].Apparatus)
+	}
+}
+
+func TestCreateExercise_Invalid(t *testing.T) {
+	svc, daos := newExerciseService(t)
+
+	// An unknown muscle rolls the exercise back.
+	exReq := newFrontSquat()
+	exReq.Muscles[0].MuscleCode = "GLUTES"
+	_, err := svc.Create(context.Background(), exReq)
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+	exercises, err := daos.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, exercises)
+
+	// An invalid role is rejected before the database is asked.
+	_, err = svc.Create(context.Background(), &model.ExerciseRequest{
+		Muscles: []model.ExerciseMuscle{{MuscleCode: "QUADS", MuscleRole: "main"}},
+	})
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.EqualError(t, err, `invalid role "main" of muscle QUADS`)
+}
+
+func TestUpdateExercise_Mismatch(t *testing.T) {
+	svc, _ := newExerciseService(t)
+
+	err := svc.Update(context.Background(), uuid.New(), &model.Exercise{ExerciseUuid: uuid.New()})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestGetExercise_DefaultLanguage(t *testing.T) {
+	svc, _ := newExerciseService(t)
+
+	created, err := svc.Create(context.Background(), newFrontSquat())
+	require.NoError(t, err)
+
+	ex, locale, err := svc.Get(context.Background(), created.ExerciseUuid, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Front Squat", ex.ExerciseName)
+	assert.Equal(t, i18n.DefaultLocale, locale)
+}
+
+func TestDeleteExercise(t *testing.T) {
+	svc, daos := newExerciseService(t)
+
+	// The links to muscles and apparatus go with the exercise.
+	ex, err := svc.Create(context.Background(), newFrontSquat())
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.Delete(context.Background(), ex.ExerciseUuid))
+	_, err = daos.Exercises.Read(context.Background(), ex.ExerciseUuid)
+	assert.ErrorIs(t, err, dao.ErrNotFound)
+}
+
+func TestDeleteExercise_RollsBack(t *testing.T) {
+	svc, daos := newExerciseService(t)
+
+	// A set that still refers to the exercise keeps its links.
+	ex, err := svc.Create(context.Background(), newFrontSquat())
+	require.NoError(t, err)
+	_, err = daos.Workouts.CreateSessions(context.Background(), uuid.New(), []model.WorkoutSessionRequest{{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt},
+		Sets:                 []model.WorkoutSet{{SetNumber: 1, ExerciseUuid: ex.ExerciseUuid}},
+	}})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.Delete(context.Background(), ex.ExerciseUuid), dao.ErrConflict)
+	exercises, err := daos.Catalog.ListExercises(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 1) {
+		assert.Len(t, exercises[0].Muscles, 1)
+	}
+}
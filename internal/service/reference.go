@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/i18n"
+	"github.com/pwydra/shred/internal/model"
+)
+
+/*
+ * ReferenceServiceInterface lists the muscles, categories and apparatus
+ * exercises are described with, in the language negotiated from an
+ * Accept-Language header. Each list is returned with the locale it is in.
+ */
+type ReferenceServiceInterface interface {
+	Muscles(ctx context.Context, acceptLanguage string) ([]model.Muscle, string, error)
+	MuscleTree(ctx context.Context, acceptLanguage string) ([]*model.MuscleNode, string, error)
+	MuscleExercises(ctx context.Context, code string, role model.MuscleRole) ([]model.MuscleExercise, error)
+	Categories(ctx context.Context, acceptLanguage string) ([]model.Category, string, error)
+	Apparatus(ctx context.Context, acceptLanguage string) ([]model.Apparatus, string, error)
+}
+
+// ReferenceService lists and translates the reference types.
+type ReferenceService struct {
+	muscles      dao.MuscleDaoInterface
+	categories   dao.CategoryDaoInterface
+	apparatus    dao.ApparatusDaoInterface
+	translations dao.TranslationDaoInterface
+}
+
+// Ensure ReferenceService implements ReferenceServiceInterface
+var _ ReferenceServiceInterface = (*ReferenceService)(nil)
+
+// NewReferenceService creates a new instance of ReferenceService.
+func NewReferenceService(muscles dao.MuscleDaoInterface, categories dao.CategoryDaoInterface,
+	apparatus dao.ApparatusDaoInterface, translations dao.TranslationDaoInterface) *ReferenceService {
+	return &ReferenceService{muscles: muscles, categories: categories, apparatus: apparatus, translations: translations}
+}
+
+func (s *ReferenceService) Muscles(ctx context.Context, acceptLanguage string) ([]model.Muscle, string, error) {
+	muscles, err := s.muscles.GetAllMuscles(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	translations, locale, err := s.referenceTranslations(ctx, model.ReferenceMuscle, acceptLanguage)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range muscles {
+		m := &muscles[i].MuscleFields
+		translate(translations, m.MuscleCode, &m.MuscleName, &m.MuscleDesc)
+	}
+	return muscles, locale, nil
+}
+
+// MuscleTree returns the muscles as a hierarchy of regions, groups, muscles
+// and heads.
+func (s *ReferenceService) MuscleTree(ctx context.Context, acceptLanguage string) ([]*model.MuscleNode, string, error) {
+	tree, err := s.muscles.GetMuscleTree(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	translations, locale, err := s.referenceTranslations(ctx, model.ReferenceMuscle, acceptLanguage)
+	if err != nil {
+		return nil, "", err
+	}
+	translateTree(translations, tree)
+	return tree, locale, nil
+}
+
+// MuscleExercises lists the exercises that work the muscle with the given
+// code or any muscle below it, only in role unless it is empty. An unknown
+// role wraps ErrInvalid and an unknown muscle dao.ErrNotFound.
+func (s *ReferenceService) MuscleExercises(ctx context.Context, code string, role model.MuscleRole) ([]model.MuscleExercise, error) {
+	if role != "" && !role.Valid() {
+		return nil, invalid(fmt.Errorf("unknown muscle role %q", role))
+	}
+	return s.muscles.FindExercisesByMuscle(ctx, code, role)
+}
+
+func (s *ReferenceService) Categories(ctx context.Context, acceptLanguage string) ([]model.Category, string, error) {
+	categories, err := s.categories.GetAllCategories(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	translations, locale, err := s.referenceTranslations(ctx, model.ReferenceCategory, acceptLanguage)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range categories {
+		c := &categories[i].CategoryFields
+		translate(translations, c.CategoryCode, &c.CategoryName, &c.CategoryDesc)
+	}
+	return categories, locale, nil
+}
+
+func (s *ReferenceService) Apparatus(ctx context.Context, acceptLanguage string) ([]model.Apparatus, string, error) {
+	apparatus, err := s.apparatus.GetAllApparatuses(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	translations, locale, err := s.referenceTranslations(ctx, model.ReferenceApparatus, acceptLanguage)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range apparatus {
+		a := &apparatus[i].ApparatusFields
+		translate(translations, a.ApparatusCode, &a.ApparatusName, &a.ApparatusDesc)
+	}
+	return apparatus, locale, nil
+}
+
+// referenceTranslations returns the translations of one kind of reference
+// into the negotiated locale, or none for the default locale.
+func (s *ReferenceService) referenceTranslations(ctx context.Context, kind model.ReferenceKind,
+	acceptLanguage string) (map[string]model.ReferenceTranslation, string, error) {
+	locale, err := ContentLocale(ctx, s.translations, acceptLanguage)
+	if err != nil || locale == i18n.DefaultLocale {
+		return nil, locale, err
+	}
+	translations, err := s.translations.GetReferenceTranslations(ctx, kind, locale)
+	return translations, locale, err
+}
+
+// translate replaces the name and, when translated, the description of the
+// reference with the given code.
+func translate(translations map[string]model.ReferenceTranslation, code string, name, description *string) {
+	tr, ok := translations[code]
+	if !ok {
+		return
+	}
+	*name = tr.Name
+	if tr.Description != "" {
+		*description = tr.Description
+	}
+}
+
+func translateTree(translations map[string]model.ReferenceTranslation, nodes []*model.MuscleNode) {
+	for _, node := range nodes {
+		translate(translations, node.MuscleCode, &node.MuscleName, &node.MuscleDesc)
+		translateTree(translations, node.Children)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pwydra/shred/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReferenceService(t *testing.T) *ReferenceService {
+	daos := newTestDaos(t)
+	require.NoError(t, daos.Translations.SaveReferenceTranslation(context.Background(), &model.ReferenceTranslation{
+		Kind: model.ReferenceMuscle, Code: "QUADS", Locale: "de", Name: "Quadrizeps"}))
+	return NewReferenceService(daos.Muscles, daos.Categories, daos.Apparatus, daos.Translations)
+}
+
+func TestMuscles_Localized(t *testing.T) {
+	svc := newReferenceService(t)
+
+	muscles, locale, err := svc.Muscles(context.Background(), "de-DE, en;q=0.5")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "de", locale)
+	assert.Equal(t, "Quadrizeps", muscles[0].MuscleName)
+	assert.Equal(t, "Front of the thigh", muscles[0].MuscleDesc)
+}
+
+func TestMuscleExercises_InvalidRole(t *testing.T) {
+	svc := newReferenceService(t)
+
+	_, err := svc.MuscleExercises(context.Background(), "QUADS", "main")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
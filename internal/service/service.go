@@ -0,0 +1,59 @@
+// Package service holds the rules of the service between the HTTP handlers
+// and the DAOs: what a valid request is, which writes belong together and
+// how results are localized and converted for the user. The DAOs only store
+// and load rows.
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/i18n"
+	"github.com/pwydra/shred/internal/units"
+)
+
+// ErrInvalid is wrapped by the errors of requests that break a rule of the
+// service, so handlers can answer them with 400 using errors.Is.
+var ErrInvalid = errors.New("invalid request")
+
+type invalidError struct {
+	err error
+}
+
+func (e invalidError) Error() string        { return e.err.Error() }
+func (e invalidError) Unwrap() error        { return e.err }
+func (e invalidError) Is(target error) bool { return target == ErrInvalid }
+
+// invalid marks err as breaking a rule of the service, keeping its message.
+func invalid(err error) error {
+	return invalidError{err: err}
+}
+
+// PreferredUnits returns the converter into the units the user prefers, or
+// into metric units for users without preferences.
+func PreferredUnits(ctx context.Context, users dao.UserDaoInterface, userUuid uuid.UUID) (units.Converter, error) {
+	prefs, err := users.GetPreferences(ctx, userUuid)
+	if errors.Is(err, dao.ErrNotFound) {
+		return units.NewConverter(units.Metric, 0), nil
+	}
+	if err != nil {
+		return units.Converter{}, err
+	}
+	return prefs.Converter(), nil
+}
+
+// ContentLocale negotiates the locale of a response from an Accept-Language
+// header and the locales there are translations in. Without the header it
+// is the default locale and the locales are not looked up.
+func ContentLocale(ctx context.Context, translations dao.TranslationDaoInterface, acceptLanguage string) (string, error) {
+	if acceptLanguage == "" {
+		return i18n.DefaultLocale, nil
+	}
+	locales, err := translations.ListLocales(ctx)
+	if err != nil {
+		return "", err
+	}
+	return i18n.Negotiate(acceptLanguage, locales), nil
+}
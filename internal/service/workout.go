@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+)
+
+type WorkoutServiceInterface interface {
+	Create(ctx context.Context, userUuid uuid.UUID, req *model.WorkoutSessionRequest) (*model.WorkoutSession, error)
+	List(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.WorkoutSession, error)
+}
+
+// WorkoutService logs and lists the workout sessions of users in the units
+// they prefer.
+type WorkoutService struct {
+	workouts dao.WorkoutDaoInterface
+	users    dao.UserDaoInterface
+}
+
+// Ensure WorkoutService implements WorkoutServiceInterface
+var _ WorkoutServiceInterface = (*WorkoutService)(nil)
+
+// NewWorkoutService creates a new instance of WorkoutService.
+func NewWorkoutService(workouts dao.WorkoutDaoInterface, users dao.UserDaoInterface) *WorkoutService {
+	return &WorkoutService{workouts: workouts, users: users}
+}
+
+/*
+ * Create logs a session with its sets for the user. Loads and distances are
+ * given in kg and metres or as a weight and distance with their unit, and
+ * are returned in the units the user prefers. A session without a name,
+ * start or sets, or with an invalid set, wraps ErrInvalid.
+ */
+func (s *WorkoutService) Create(ctx context.Context, userUuid uuid.UUID, req *model.WorkoutSessionRequest) (*model.WorkoutSession, error) {
+	if err := validateWorkout(req); err != nil {
+		return nil, invalid(err)
+	}
+
+	converter, err := PreferredUnits(ctx, s.users, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.workouts.CreateSessions(ctx, userUuid, []model.WorkoutSessionRequest{*req})
+	if err != nil {
+		return nil, err
+	}
+	sessions[0].Convert(converter)
+	return &sessions[0], nil
+}
+
+// List returns the sessions the user started between from and to, with
+// loads and distances in the units the user prefers.
+func (s *WorkoutService) List(ctx context.Context, userUuid uuid.UUID, from, to time.Time) ([]model.WorkoutSession, error) {
+	converter, err := PreferredUnits(ctx, s.users, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.workouts.ListSessions(ctx, userUuid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Convert(converter)
+	}
+	return sessions, nil
+}
+
+func validateWorkout(req *model.WorkoutSessionRequest) error {
+	if req.SessionName == "" {
+		return errors.New("sessionName is required")
+	}
+	if req.StartedAt.IsZero() {
+		return errors.New("startedAt is required")
+	}
+	if len(req.Sets) == 0 {
+		return errors.New("at least one set is required")
+	}
+	for i := range req.Sets {
+		if req.Sets[i].ExerciseUuid == uuid.Nil {
+			return fmt.Errorf("set %d: exerciseUuid is required", i+1)
+		}
+		if err := req.Sets[i].Normalize(); err != nil {
+			return fmt.Errorf("set %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
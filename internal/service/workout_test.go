@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pwydra/shred/internal/dao"
+	"github.com/pwydra/shred/internal/model"
+	"github.com/pwydra/shred/internal/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var startedAt = time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC)
+
+func newWorkoutService(t *testing.T) (*WorkoutService, *dao.Daos) {
+	daos := newTestDaos(t)
+	return NewWorkoutService(daos.Workouts, daos.Users), daos
+}
+
+func TestCreateWorkout(t *testing.T) {
+	svc, daos := newWorkoutService(t)
+
+	userUuid := uuid.New()
+	increment := 5.0
+	_, err := daos.Users.UpdatePreferences(context.Background(), userUuid,
+		&model.UserPreferences{UnitSystem: units.Imperial, LoadIncrement: &increment})
+	require.NoError(t, err)
+	ex, err := daos.Exercises.Create(context.Background(), &model.ExerciseRequest{
+		ExerciseFields: model.ExerciseFields{ExerciseName: "Back Squat", CategoryCode: "STRENGTH"}})
+	require.NoError(t, err)
+
+	weight := 225.0
+	req := &model.WorkoutSessionRequest{
+		WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt},
+		Sets:                 []model.WorkoutSet{{ExerciseUuid: ex.ExerciseUuid, Weight: &weight, WeightUnit: units.Lb}},
+	}
+
+	session, err := svc.Create(context.Background(), userUuid, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 225.0, *session.Sets[0].Weight)
+	assert.Equal(t, units.Lb, session.Sets[0].WeightUnit)
+
+	sessions, err := daos.Workouts.ListSessions(context.Background(), userUuid, startedAt, startedAt.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, session.SessionUuid, sessions[0].SessionUuid)
+		assert.InDelta(t, 102.06, *sessions[0].Sets[0].WeightKg, 0.01)
+	}
+}
+
+func TestCreateWorkout_Invalid(t *testing.T) {
+	svc, _ := newWorkoutService(t)
+
+	tests := []struct {
+		name string
+		req  model.WorkoutSessionRequest
+		want string
+	}{
+		{"no name", model.WorkoutSessionRequest{}, "sessionName is required"},
+		{"no start", model.WorkoutSessionRequest{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs"}},
+			"startedAt is required"},
+		{"no sets", model.WorkoutSessionRequest{WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt}},
+			"at least one set is required"},
+		{"no exercise", model.WorkoutSessionRequest{
+			WorkoutSessionFields: model.WorkoutSessionFields{SessionName: "Legs", StartedAt: startedAt},
+			Sets:                 []model.WorkoutSet{{}},
+		}, "set 1: exerciseUuid is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), uuid.New(), &tt.req)
+			assert.ErrorIs(t, err, ErrInvalid)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestPreferredUnits_NoUser(t *testing.T) {
+	daos := newTestDaos(t)
+
+	converter, err := PreferredUnits(context.Background(), daos.Users, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, units.Metric, converter.System())
+}
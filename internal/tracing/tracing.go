@@ -0,0 +1,96 @@
+// Package tracing sets up the OpenTelemetry tracer provider the gin
+// middleware and the DAOs record their spans with.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ServiceName names the service in exported spans.
+const ServiceName = "shred"
+
+// Exporter is where spans are sent.
+type Exporter string
+
+const (
+	ExporterNone   Exporter = "none"
+	ExporterOTLP   Exporter = "otlp"
+	ExporterStdout Exporter = "stdout"
+)
+
+type Config struct {
+	Exporter Exporter
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g.
+	// http://collector:4318. The exporter defaults apply when empty.
+	Endpoint string
+}
+
+/*
+ * ConfigFromEnv reads OTEL_TRACES_EXPORTER, one of none, otlp and stdout,
+ * and OTEL_EXPORTER_OTLP_ENDPOINT. Tracing is off by default.
+ */
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Exporter: ExporterNone, Endpoint: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")}
+	if value := os.Getenv("OTEL_TRACES_EXPORTER"); value != "" {
+		cfg.Exporter = Exporter(strings.ToLower(value))
+		switch cfg.Exporter {
+		case ExporterNone, ExporterOTLP, ExporterStdout:
+		default:
+			return Config{}, fmt.Errorf("OTEL_TRACES_EXPORTER must be %s, %s or %s, not %q",
+				ExporterNone, ExporterOTLP, ExporterStdout, value)
+		}
+	}
+	return cfg, nil
+}
+
+/*
+ * Setup installs the global tracer provider and the W3C trace context
+ * propagator. Spans go to the exporter of cfg, stdout spans being written
+ * to w. The returned function flushes pending spans and must be called
+ * before the service exits.
+ */
+func Setup(ctx context.Context, cfg Config, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s exporter: %w", cfg.Exporter, err)
+	}
+
+	provider := NewProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider for the service, e.g. exporting to
+// an in-memory exporter in tests.
+func NewProvider(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(attribute.String("service.name", ServiceName))
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)...)
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name, exporter, endpoint string
+		expected                 Config
+		fails                    bool
+	}{
+		{"defaults", "", "", Config{Exporter: ExporterNone}, false},
+		{"otlp", "OTLP", "http://collector:4318", Config{Exporter: ExporterOTLP, Endpoint: "http://collector:4318"}, false},
+		{"stdout", "stdout", "", Config{Exporter: ExporterStdout}, false},
+		{"unknown", "zipkin", "", Config{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_EXPORTER", tt.exporter)
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+
+			cfg, err := ConfigFromEnv()
+			if tt.fails {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var buf bytes.Buffer
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout}, &buf)
+	assert.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "getPreferencesDQL")
+	span.End()
+	assert.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name":"getPreferencesDQL"`)
+	assert.Contains(t, buf.String(), `"Value":"shred"`, "the service is named")
+}
+
+func TestSetup_None(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, previous, otel.GetTracerProvider())
+}
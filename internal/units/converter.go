@@ -0,0 +1,66 @@
+package units
+
+import "math"
+
+// Converter converts stored metric values into the units of a system.
+type Converter struct {
+	system        System
+	loadIncrement float64
+}
+
+// NewConverter creates a converter into system whose loads are rounded to
+// loadIncrement, or to the default increment of system when it is zero.
+func NewConverter(system System, loadIncrement float64) Converter {
+	if !system.Valid() {
+		system = Metric
+	}
+	if loadIncrement <= 0 {
+		loadIncrement = system.DefaultLoadIncrement()
+	}
+	return Converter{system: system, loadIncrement: loadIncrement}
+}
+
+func (c Converter) System() System {
+	return c.system
+}
+
+/*
+ * Load converts a load logged in entered into the unit of the system.
+ * Converted loads are rounded to the load increment, so 100 kg reads as
+ * 220 lb rather than 220.46 lb; loads read in the unit they were logged in
+ * are returned as logged. Loads logged without a unit were logged in kg.
+ */
+func (c Converter) Load(kg float64, entered WeightUnit) float64 {
+	unit := c.system.WeightUnit()
+	if entered == "" {
+		entered = Kg
+	}
+	if entered == unit {
+		return Round(FromKg(kg, unit))
+	}
+	return RoundTo(FromKg(kg, unit), c.loadIncrement)
+}
+
+// Weight converts a bodyweight or training volume without rounding it to
+// the load increment.
+func (c Converter) Weight(kg float64) float64 {
+	return Round(FromKg(kg, c.system.WeightUnit()))
+}
+
+func (c Converter) Distance(m float64) float64 {
+	return Round(FromMetres(m, c.system.DistanceUnit()))
+}
+
+func (c Converter) Length(cm float64) float64 {
+	if c.system == Imperial {
+		return Round(cm / CmPerIn)
+	}
+	return Round(cm)
+}
+
+// Pace converts seconds per kilometre into seconds per distance unit of the
+// system, to a tenth of a second.
+func (c Converter) Pace(secondsPerKm float64) float64 {
+	pace := secondsPerKm * ToMetres(1, c.system.DistanceUnit()) / MPerKm
+	return math.Round(pace*10) / 10
+}
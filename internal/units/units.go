@@ -0,0 +1,151 @@
+// Package units converts loads, distances and lengths between the metric
+// units shred stores them in and the units users enter and read them in.
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Conversion factors into the units shred stores.
+const (
+	KgPerLb = 0.45359237
+	MPerKm  = 1000.0
+	MPerMi  = 1609.344
+	CmPerIn = 2.54
+)
+
+// System is the set of units a user reads loads, distances and lengths in.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+func (s System) Valid() bool {
+	return s == Metric || s == Imperial
+}
+
+func (s System) WeightUnit() WeightUnit {
+	if s == Imperial {
+		return Lb
+	}
+	return Kg
+}
+
+func (s System) DistanceUnit() DistanceUnit {
+	if s == Imperial {
+		return Mi
+	}
+	return Km
+}
+
+// LengthUnit is the unit body circumferences are given in.
+func (s System) LengthUnit() string {
+	if s == Imperial {
+		return "in"
+	}
+	return "cm"
+}
+
+/*
+ * DefaultLoadIncrement is the smallest step a barbell load can change by
+ * with common plates: a pair of 1.25 kg or of 2.5 lb plates.
+ */
+func (s System) DefaultLoadIncrement() float64 {
+	if s == Imperial {
+		return 5
+	}
+	return 2.5
+}
+
+// WeightUnit is the unit a load or bodyweight is given in.
+type WeightUnit string
+
+const (
+	Kg WeightUnit = "kg"
+	Lb WeightUnit = "lb"
+)
+
+// ParseWeightUnit reads kg or lb, also spelt kgs and lbs.
+func ParseWeightUnit(s string) (WeightUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "kg", "kgs":
+		return Kg, nil
+	case "lb", "lbs":
+		return Lb, nil
+	}
+	return "", fmt.Errorf("unknown weight unit %q", s)
+}
+
+// DistanceUnit is the unit a distance is given in.
+type DistanceUnit string
+
+const (
+	Km DistanceUnit = "km"
+	Mi DistanceUnit = "mi"
+	// M is only recorded for distances imported in metres; distances are
+	// read and entered in km or mi.
+	M DistanceUnit = "m"
+)
+
+// ParseDistanceUnit reads km or mi, also spelt miles.
+func ParseDistanceUnit(s string) (DistanceUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "km":
+		return Km, nil
+	case "mi", "miles":
+		return Mi, nil
+	}
+	return "", fmt.Errorf("unknown distance unit %q", s)
+}
+
+func ToKg(value float64, unit WeightUnit) float64 {
+	if unit == Lb {
+		return value * KgPerLb
+	}
+	return value
+}
+
+func FromKg(kg float64, unit WeightUnit) float64 {
+	if unit == Lb {
+		return kg / KgPerLb
+	}
+	return kg
+}
+
+func ToMetres(value float64, unit DistanceUnit) float64 {
+	switch unit {
+	case Mi:
+		return value * MPerMi
+	case M:
+		return value
+	}
+	return value * MPerKm
+}
+
+func FromMetres(m float64, unit DistanceUnit) float64 {
+	switch unit {
+	case Mi:
+		return m / MPerMi
+	case M:
+		return m
+	}
+	return m / MPerKm
+}
+
+// RoundTo rounds value to the nearest multiple of increment, or to two
+// decimals when increment is not positive.
+func RoundTo(value, increment float64) float64 {
+	if increment > 0 {
+		value = math.Round(value/increment) * increment
+	}
+	return Round(value)
+}
+
+// Round rounds to two decimals, the precision values are stored with.
+func Round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
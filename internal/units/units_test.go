@@ -0,0 +1,88 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnits(t *testing.T) {
+	for _, s := range []string{"kg", "KGS", " kg "} {
+		unit, err := ParseWeightUnit(s)
+		assert.NoError(t, err)
+		assert.Equal(t, Kg, unit)
+	}
+	unit, err := ParseWeightUnit("lbs")
+	assert.NoError(t, err)
+	assert.Equal(t, Lb, unit)
+	_, err = ParseWeightUnit("stone")
+	assert.EqualError(t, err, `unknown weight unit "stone"`)
+
+	distance, err := ParseDistanceUnit("Miles")
+	assert.NoError(t, err)
+	assert.Equal(t, Mi, distance)
+	_, err = ParseDistanceUnit("m")
+	assert.Error(t, err)
+}
+
+func TestConversions(t *testing.T) {
+	assert.Equal(t, 102.06, Round(ToKg(225, Lb)))
+	assert.Equal(t, 225.0, Round(FromKg(102.06, Lb)))
+	assert.Equal(t, 100.0, ToKg(100, Kg))
+	assert.Equal(t, 1609.344, ToMetres(1, Mi))
+	assert.Equal(t, 5000.0, ToMetres(5, Km))
+	assert.Equal(t, 3.11, Round(FromMetres(5000, Mi)))
+	assert.Equal(t, 2000.0, ToMetres(2000, M))
+	assert.Equal(t, 2000.0, FromMetres(2000, M))
+}
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		value, increment, want float64
+	}{
+		{220.46, 5, 220},
+		{222.5, 5, 225},
+		{101.2, 2.5, 100},
+		{101.3, 2.5, 102.5},
+		{62, 1.25, 62.5},
+		{101.234, 0, 101.23},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RoundTo(tt.value, tt.increment), "%v to %v", tt.value, tt.increment)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	imperial := NewConverter(Imperial, 0)
+	tests := []struct {
+		name    string
+		kg      float64
+		entered WeightUnit
+		want    float64
+	}{
+		{"logged in lb", 102.06, Lb, 225},
+		{"logged in kg", 100, Kg, 220},
+		{"logged without a unit", 60, "", 130},
+		{"microplates", 102.06, Kg, 225},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, imperial.Load(tt.kg, tt.entered))
+		})
+	}
+	assert.Equal(t, 181.66, imperial.Weight(82.4))
+	assert.Equal(t, 33.25, imperial.Length(84.46))
+	assert.Equal(t, 482.8, imperial.Pace(300))
+
+	metric := NewConverter(Metric, 0)
+	assert.Equal(t, 102.5, metric.Load(102.06, Lb))
+	assert.Equal(t, 101.0, metric.Load(101, Kg))
+	assert.Equal(t, 5.0, metric.Distance(5000))
+	assert.Equal(t, 300.0, metric.Pace(300))
+
+	fine := NewConverter(Imperial, 1)
+	assert.Equal(t, 220.0, fine.Load(100, Kg))
+	assert.Equal(t, 221.0, fine.Load(100.3, Kg))
+
+	assert.Equal(t, Metric, NewConverter("", 0).System())
+}